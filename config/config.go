@@ -13,6 +13,7 @@ type (
 		SvcVersion string `mapstructure:"SVC_VERSION"`
 		Port       string `mapstructure:"PORT"`
 		JWTSecret  string `mapstructure:"JWT_SECRET"`
+		AppURL     string `mapstructure:"APP_URL"`
 
 		// Postgres
 		PostgreHost         string `mapstructure:"POSTGRES_URL"`
@@ -25,6 +26,12 @@ type (
 		RedisUsername string   `mapstructure:"REDIS_USERNAME"`
 		RedisPassword string   `mapstructure:"REDIS_PASSWORD"`
 
+		// Rate limits, written as requests/window e.g. "10/1m"
+		RateLimitEnabled bool   `mapstructure:"RATE_LIMIT_ENABLED"`
+		RateLimitAPI     string `mapstructure:"RATE_LIMIT_API"`  // every route, per user, API key or IP address
+		RateLimitAuth    string `mapstructure:"RATE_LIMIT_AUTH"` // unauthenticated auth routes, per IP address
+		RateLimitOTP     string `mapstructure:"RATE_LIMIT_OTP"`  // one-time code routes, per phone number
+
 		// HTTP client
 		HttpClientTimeout             int  `mapstructure:"HTTP_CLIENT_TIMEOUT"`
 		HttpClientDisableKeepAlives   bool `mapstructure:"HTTP_CLIENT_DISABLE_KEEP_ALIVE"`
@@ -47,6 +54,18 @@ type (
 		// WhatsApp
 		WhatsAppPhoneNumberID string `mapstructure:"WHATSAPP_PHONE_NUMBER_ID"`
 		WhatsAppAccessToken   string `mapstructure:"WHATSAPP_ACCESS_TOKEN"`
+		WhatsAppOTPTemplate   string `mapstructure:"WHATSAPP_OTP_TEMPLATE"` // authentication template for one-time codes
+
+		// SMS gateway for one-time codes
+		SMSGatewayURL    string `mapstructure:"SMS_GATEWAY_URL"`
+		SMSGatewayAPIKey string `mapstructure:"SMS_GATEWAY_API_KEY"`
+
+		// SMTP for transactional email, e.g. email verification links
+		SMTPHost     string `mapstructure:"SMTP_HOST"`
+		SMTPPort     int    `mapstructure:"SMTP_PORT"`
+		SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+		SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+		EmailFrom    string `mapstructure:"EMAIL_FROM"`
 
 		// OpenAI
 		OpenAIApiKey string `mapstructure:"OPENAI_API_KEY"`
@@ -80,6 +99,11 @@ func NewConfig() (*Config, error) {
 	viper.SetDefault("MAX_MESSAGES_PER_RESPONSE", 5)
 	viper.SetDefault("MAX_MESSAGE_WORD_COUNT", 500)
 	viper.SetDefault("MESSAGE_CHUNK_LENGTH", 1000)
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("RATE_LIMIT_ENABLED", true)
+	viper.SetDefault("RATE_LIMIT_API", "300/1m")
+	viper.SetDefault("RATE_LIMIT_AUTH", "20/1m")
+	viper.SetDefault("RATE_LIMIT_OTP", "10/15m")
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -21,9 +21,11 @@ import (
 	"github.com/vasst-id/vasst-expense-api/internal/utils/httpclient"
 	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
 	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/redis"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/vasst-id/vasst-expense-api/config"
+	adminRouter "github.com/vasst-id/vasst-expense-api/internal/controller/http/v0"
 	httpRouter "github.com/vasst-id/vasst-expense-api/internal/controller/http/v1"
 	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 	"github.com/vasst-id/vasst-expense-api/internal/services"
@@ -67,20 +69,24 @@ func Run(config *config.Config) {
 		log.Fatalf("error init postgres %s", err.Error())
 	}
 
-	// redis
-	// redisOpts := &redis.ClientOptions{
-	// 	ServiceName:   ServiceNameRedis,
-	// 	Address:       config.RedisHost,
-	// 	Username:      config.RedisUsername,
-	// 	Password:      config.RedisPassword,
-	// 	DB:            config.RedisDB,
-	// 	DataDogTracer: true,
-	// }
-
-	// reds, err := redis.New(redisOpts)
-	// if err != nil {
-	// 	log.Fatalf("error init redis %s", err.Error())
-	// }
+	// redis is optional, rate limits are counted in memory without it
+	var reds redis.Cache
+	if len(config.RedisHost) > 0 {
+		redisOpts := &redis.ClientOptions{
+			ServiceName:   ServiceNameRedis,
+			Address:       config.RedisHost,
+			Username:      config.RedisUsername,
+			Password:      config.RedisPassword,
+			DB:            config.RedisDB,
+			DataDogTracer: true,
+		}
+
+		reds, err = redis.New(redisOpts)
+		if err != nil {
+			log.Printf("error init redis %s, rate limits are counted in memory", err.Error())
+			reds = nil
+		}
+	}
 
 	// logger
 	logger := logs.New(initLoggerOptions(config))
@@ -109,23 +115,50 @@ func Run(config *config.Config) {
 	// 	log.Fatalf("error init google cloud storage service %s", err.Error())
 	// }
 
+	httpClient := httpclient.New(httpClientConfig(config))
+
 	// services
 	authMiddleware := middleware.NewAuthMiddleware(config.JWTSecret)
-	userService := services.NewUserService(repositories.NewUserRepository(pg), authMiddleware)
-	workspaceService := services.NewWorkspaceService(repositories.NewWorkspaceRepository(pg))
+	rateLimiter := middleware.NewRateLimiter(reds, authMiddleware, config.RateLimitEnabled)
+	rateLimits, err := rateLimitsConfig(config)
+	if err != nil {
+		log.Fatalf("error init rate limits %s", err.Error())
+	}
+	workspaceAuthorizer := services.NewWorkspaceAuthorizer(repositories.NewWorkspaceRepository(pg), repositories.NewWorkspaceMemberRepository(pg))
+	activityService := services.NewActivityService(repositories.NewActivityRepository(pg), repositories.NewUserRepository(pg), repositories.NewWorkspaceRepository(pg), workspaceAuthorizer)
+	workspaceTemplateService := services.NewWorkspaceTemplateService(repositories.NewWorkspaceRepository(pg), repositories.NewWorkspaceTemplateRepository(pg), repositories.NewCategoryRepository(pg), repositories.NewUserTagsRepository(pg), workspaceAuthorizer)
+	workspaceService := services.NewWorkspaceService(repositories.NewWorkspaceRepository(pg), repositories.NewWorkspaceMemberRepository(pg), repositories.NewUserRepository(pg), repositories.NewAccountRepository(pg), workspaceAuthorizer, authMiddleware, activityService)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(pg))
+	workspaceInvitationService := services.NewWorkspaceInvitationService(repositories.NewWorkspaceInvitationRepository(pg), repositories.NewWorkspaceMemberRepository(pg), repositories.NewUserRepository(pg), workspaceAuthorizer, notificationService, authMiddleware, config.AppURL, activityService)
+	sessionService := services.NewSessionService(repositories.NewUserSessionRepository(pg), repositories.NewUserRepository(pg), authMiddleware)
+	verificationCodeService := services.NewVerificationCodeService(repositories.NewVerificationCodeRepository(pg), repositories.NewUserRepository(pg), workspaceInvitationService, services.NewCodeSender(httpClient, config))
+	userService := services.NewUserService(repositories.NewUserRepository(pg), authMiddleware, workspaceInvitationService, sessionService, verificationCodeService, services.NewEmailSender(config), config.AppURL)
 	accountService := services.NewAccountService(repositories.NewAccountRepository(pg))
 	bankService := services.NewBankService(repositories.NewBankRepository(pg))
 	currencyService := services.NewCurrencyService(repositories.NewCurrencyRepository(pg))
 	subscriptionPlanService := services.NewSubscriptionPlanService(repositories.NewSubscriptionPlanRepository(pg))
-	budgetService := services.NewBudgetService(repositories.NewBudgetRepository(pg))
-	categoryService := services.NewCategoryService(repositories.NewCategoryRepository(pg))
-	transactionService := services.NewTransactionService(repositories.NewTransactionRepository(pg), repositories.NewWorkspaceRepository(pg), repositories.NewAccountRepository(pg))
-	conversationService := services.NewConversationService(repositories.NewConversationRepository(pg), repositories.NewUserRepository(pg))
+	budgetService := services.NewBudgetService(repositories.NewBudgetRepository(pg), repositories.NewCategoryRepository(pg), repositories.NewUserTagsRepository(pg), repositories.NewAccountRepository(pg), workspaceAuthorizer, activityService)
+	categoryService := services.NewCategoryService(repositories.NewCategoryRepository(pg), activityService)
+	merchantService := services.NewMerchantService(repositories.NewMerchantRepository(pg))
+	exchangeRateService := services.NewExchangeRateService(repositories.NewExchangeRateRepository(pg), repositories.NewCurrencyRepository(pg))
+	adminService := services.NewAdminService(repositories.NewUserRepository(pg), repositories.NewAdminAuditRepository(pg), sessionService)
+	transactionApprovalService := services.NewTransactionApprovalService(repositories.NewTransactionRepository(pg), repositories.NewWorkspaceMemberRepository(pg), repositories.NewTransactionRollupRepository(pg), workspaceAuthorizer, notificationService, activityService)
+	transactionService := services.NewTransactionService(repositories.NewTransactionRepository(pg), workspaceAuthorizer, repositories.NewAccountRepository(pg), repositories.NewTransactionRollupRepository(pg), merchantService, activityService, transactionApprovalService)
+	conversationService := services.NewConversationService(repositories.NewConversationRepository(pg), repositories.NewUserRepository(pg), workspaceAuthorizer)
 	messageService := services.NewMessageService(repositories.NewMessageRepository(pg), repositories.NewConversationRepository(pg), repositories.NewUserRepository(pg))
 	taxonomyService := services.NewTaxonomyService(repositories.NewTaxonomyRepository(pg))
 	userTagsService := services.NewUserTagsService(repositories.NewUserTagsRepository(pg))
 	transactionTagsService := services.NewTransactionTagsService(repositories.NewTransactionTagsRepository(pg), repositories.NewUserTagsRepository(pg))
-	verificationCodeService := services.NewVerificationCodeService(repositories.NewVerificationCodeRepository(pg), repositories.NewUserRepository(pg))
+	envelopeService := services.NewEnvelopeService(repositories.NewEnvelopeRepository(pg), workspaceAuthorizer)
+	analyticsService := services.NewAnalyticsService(repositories.NewAnalyticsRepository(pg), workspaceAuthorizer)
+	reportService := services.NewReportService(repositories.NewReportRepository(pg), workspaceAuthorizer, repositories.NewCurrencyRepository(pg))
+	netWorthService := services.NewNetWorthService(repositories.NewAccountBalanceSnapshotRepository(pg), repositories.NewUserRepository(pg), repositories.NewCurrencyRepository(pg))
+	dashboardService := services.NewDashboardService(repositories.NewDashboardRepository(pg), repositories.NewUserRepository(pg), repositories.NewCurrencyRepository(pg))
+	spendingAnomalyService := services.NewSpendingAnomalyService(repositories.NewSpendingAnomalyRepository(pg), repositories.NewWorkspaceRepository(pg), notificationService)
+	recurringChargeService := services.NewRecurringChargeService(repositories.NewRecurringChargeRepository(pg), workspaceAuthorizer)
+	settlementService := services.NewSettlementService(repositories.NewSettlementRepository(pg), repositories.NewTransactionRepository(pg), workspaceAuthorizer, activityService)
+	reimbursementService := services.NewReimbursementService(repositories.NewReimbursementRepository(pg), repositories.NewTransactionRepository(pg), repositories.NewTransactionRollupRepository(pg), repositories.NewAccountRepository(pg), repositories.NewWorkspaceMemberRepository(pg), repositories.NewCurrencyRepository(pg), workspaceAuthorizer, notificationService, activityService)
+	transactionCommentService := services.NewTransactionCommentService(repositories.NewTransactionCommentRepository(pg), repositories.NewTransactionRepository(pg), repositories.NewWorkspaceMemberRepository(pg), repositories.NewMessageRepository(pg), repositories.NewConversationRepository(pg), workspaceAuthorizer, notificationService)
 	// openAIService, err := services.NewOpenAIService(config, messageService)
 	// if err != nil {
 	// 	log.Fatalf("error init openai service %s", err.Error())
@@ -143,8 +176,6 @@ func Run(config *config.Config) {
 	// publisherAdapter := pubsub.NewPublisherAdapter(pubsubClient)
 	// webhookEventHandler := handlers.NewWebhookEventHandler(publisherAdapter)
 
-	httpClient := httpclient.New(httpClientConfig(config))
-
 	// gin
 	gin.SetMode(gin.ReleaseMode)
 	handler := gin.New()
@@ -154,11 +185,12 @@ func Run(config *config.Config) {
 	handler.Use(gin.Logger())
 	handler.Use(gzip.Gzip(gzip.DefaultCompression))
 	handler.Use(gin.Recovery())
+	handler.Use(middleware.SourceChannel())
 	handler.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000", "http://localhost:3001"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", middleware.SourceChannelHeader, middleware.APIKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Authorization", middleware.RateLimitLimitHeader, middleware.RateLimitRemainingHeader, middleware.RateLimitResetHeader, middleware.RateLimitPolicyHeader, middleware.RetryAfterHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -195,23 +227,58 @@ func Run(config *config.Config) {
 	handler.GET("/health-check", gin.WrapF(healthCheck.HandlerFunc))
 
 	httpRouter.NewRouter(handler, httpRouter.Services{
+		Cfg:                        config,
+		UserService:                userService,
+		WorkspaceService:           workspaceService,
+		WorkspaceTemplateService:   workspaceTemplateService,
+		AccountService:             accountService,
+		BankService:                bankService,
+		CurrencyService:            currencyService,
+		SubscriptionPlanService:    subscriptionPlanService,
+		BudgetService:              budgetService,
+		AuthMiddleware:             authMiddleware,
+		CategoryService:            categoryService,
+		TransactionService:         transactionService,
+		ConversationService:        conversationService,
+		MessageService:             messageService,
+		TaxonomyService:            taxonomyService,
+		UserTagsService:            userTagsService,
+		TransactionTagsService:     transactionTagsService,
+		VerificationCodeService:    verificationCodeService,
+		EnvelopeService:            envelopeService,
+		AnalyticsService:           analyticsService,
+		ReportService:              reportService,
+		NetWorthService:            netWorthService,
+		DashboardService:           dashboardService,
+		MerchantService:            merchantService,
+		NotificationService:        notificationService,
+		SpendingAnomalyService:     spendingAnomalyService,
+		RecurringChargeService:     recurringChargeService,
+		WorkspaceInvitationService: workspaceInvitationService,
+		SettlementService:          settlementService,
+		ActivityService:            activityService,
+		TransactionCommentService:  transactionCommentService,
+		TransactionApprovalService: transactionApprovalService,
+		ReimbursementService:       reimbursementService,
+		SessionService:             sessionService,
+		RateLimiter:                rateLimiter,
+		RateLimits:                 rateLimits,
+	})
+
+	// Administration API, restricted to users holding a system role
+	adminRouter.NewRouter(handler, adminRouter.Services{
 		Cfg:                     config,
 		UserService:             userService,
-		WorkspaceService:        workspaceService,
-		AccountService:          accountService,
 		BankService:             bankService,
 		CurrencyService:         currencyService,
 		SubscriptionPlanService: subscriptionPlanService,
-		BudgetService:           budgetService,
-		AuthMiddleware:          authMiddleware,
-		CategoryService:         categoryService,
-		TransactionService:      transactionService,
-		ConversationService:     conversationService,
-		MessageService:          messageService,
 		TaxonomyService:         taxonomyService,
-		UserTagsService:         userTagsService,
-		TransactionTagsService:  transactionTagsService,
-		VerificationCodeService: verificationCodeService,
+		ExchangeRateService:     exchangeRateService,
+		MerchantService:         merchantService,
+		AdminService:            adminService,
+		AuthMiddleware:          authMiddleware,
+		RateLimiter:             rateLimiter,
+		RateLimits:              rateLimits,
 	})
 
 	fmt.Printf("Starting server on port %s\n", config.Port)
@@ -233,6 +300,23 @@ func initLoggerOptions(cfg *config.Config) logs.Options {
 	}
 }
 
+func rateLimitsConfig(config *config.Config) (middleware.RateLimits, error) {
+	var limits middleware.RateLimits
+	var err error
+
+	if limits.API, err = middleware.ParseRateLimit(config.RateLimitAPI); err != nil {
+		return limits, err
+	}
+	if limits.Auth, err = middleware.ParseRateLimit(config.RateLimitAuth); err != nil {
+		return limits, err
+	}
+	if limits.OTP, err = middleware.ParseRateLimit(config.RateLimitOTP); err != nil {
+		return limits, err
+	}
+
+	return limits, nil
+}
+
 func httpClientConfig(config *config.Config) *httpclient.Config {
 	httpClientCfg := &httpclient.Config{
 		Timeout:     config.HttpClientTimeout,
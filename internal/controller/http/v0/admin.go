@@ -0,0 +1,371 @@
+package v0
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
+)
+
+type adminRoutes struct {
+	adminService services.AdminService
+}
+
+func newAdminRoutes(handler *gin.RouterGroup, adminService services.AdminService, auth *middleware.AuthMiddleware) {
+	r := &adminRoutes{adminService: adminService}
+
+	// Admin management and the audit log are for superadmins only
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
+	admins := handler.Group("/admins", superAdmin)
+	{
+		admins.GET("", r.ListAdmins)
+		admins.PUT("/:id", r.UpdateSystemRole)
+		admins.DELETE("/:id", r.RemoveSystemRole)
+	}
+
+	handler.GET("/audit-logs", superAdmin, r.GetAuditLogs)
+}
+
+// adminErrorStatus maps admin service errors to HTTP status codes
+func adminErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid system role", "you cannot change your own system role":
+		return http.StatusBadRequest
+	case "user not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary List admins
+// @Description List the users holding a system role
+// @Tags admins
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /admins [get]
+func (r *adminRoutes) ListAdmins(c *gin.Context) {
+	admins, err := r.adminService.ListAdmins(c.Request.Context())
+	if err != nil {
+		c.JSON(adminErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    admins,
+	})
+}
+
+// @Summary Grant a system role
+// @Description Grant or change the system role of a user. Demoting an admin ends their sessions.
+// @Tags admins
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param input body entities.UpdateSystemRoleRequest true "System role"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /admins/{id} [put]
+func (r *adminRoutes) UpdateSystemRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid user ID format",
+		})
+		return
+	}
+
+	var input entities.UpdateSystemRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := r.adminService.SetSystemRole(c.Request.Context(), adminUserID(c), userID, input.SystemRole)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    user,
+		Message: "System role updated successfully",
+	})
+}
+
+// @Summary Remove a system role
+// @Description Take the system role away from a user and end their sessions
+// @Tags admins
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /admins/{id} [delete]
+func (r *adminRoutes) RemoveSystemRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid user ID format",
+		})
+		return
+	}
+
+	if _, err := r.adminService.SetSystemRole(c.Request.Context(), adminUserID(c), userID, entities.SystemRoleNone); err != nil {
+		c.JSON(adminErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "System role removed successfully",
+	})
+}
+
+// @Summary Get the admin audit log
+// @Description List the requests admins made to change data through the administration API, newest first
+// @Tags admins
+// @Produce json
+// @Security BearerAuth
+// @Param admin_id query string false "Filter by admin"
+// @Param resource_type query string false "Filter by resource type, e.g. user, bank"
+// @Param resource_id query string false "Filter by resource ID"
+// @Param action query string false "Filter by action: create, update, delete"
+// @Param start_date query string false "Start date filter (YYYY-MM-DD)"
+// @Param end_date query string false "End date filter, inclusive (YYYY-MM-DD)"
+// @Param limit query int false "Limit for pagination (default: 20)"
+// @Param offset query int false "Offset for pagination (default: 0)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /audit-logs [get]
+func (r *adminRoutes) GetAuditLogs(c *gin.Context) {
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	params := parseAdminAuditListParams(c)
+
+	auditLogs, totalCount, err := r.adminService.GetAuditLogs(c.Request.Context(), params, limit, offset)
+	if err != nil {
+		c.JSON(adminErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"audit_logs": auditLogs,
+			"total":      totalCount,
+			"limit":      limit,
+			"offset":     offset,
+		},
+	})
+}
+
+// parseAdminAuditListParams reads the audit log filters from the query, invalid values are ignored
+func parseAdminAuditListParams(c *gin.Context) *entities.AdminAuditListParams {
+	params := &entities.AdminAuditListParams{
+		ResourceType: c.Query("resource_type"),
+		Action:       c.Query("action"),
+	}
+
+	if adminID, err := uuid.Parse(c.Query("admin_id")); err == nil {
+		params.AdminID = &adminID
+	}
+	if resourceID, err := uuid.Parse(c.Query("resource_id")); err == nil {
+		params.ResourceID = &resourceID
+	}
+	if startDate, err := time.Parse("2006-01-02", c.Query("start_date")); err == nil {
+		params.StartDate = &startDate
+	}
+	if endDate, err := time.Parse("2006-01-02", c.Query("end_date")); err == nil {
+		// Inclusive of the whole end day
+		endDate = endDate.AddDate(0, 0, 1)
+		params.EndDate = &endDate
+	}
+
+	return params
+}
+
+// adminUserID returns the admin of the request, AuthRequired has already validated the token
+func adminUserID(c *gin.Context) uuid.UUID {
+	userID, _ := c.Get("user_id")
+	id, _ := userID.(uuid.UUID)
+	return id
+}
+
+// adminAuditBodyLimit caps the request bodies kept in the audit log
+const adminAuditBodyLimit = 64 * 1024
+
+// adminAudit records every request changing data in the audit log once it is handled, including the ones
+// refused for the admin's role. It runs after AdminRequired, reads are not recorded.
+func adminAudit(adminService services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		var body map[string]interface{}
+		if c.Request.Body != nil {
+			data, err := io.ReadAll(c.Request.Body)
+			if err == nil {
+				// The handler binds the body again
+				c.Request.Body = io.NopCloser(bytes.NewReader(data))
+				if len(data) <= adminAuditBodyLimit && json.Unmarshal(data, &body) == nil {
+					redactSecrets(body)
+				}
+			}
+		}
+
+		c.Next()
+
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		auditLog := &entities.AdminAuditLog{
+			AuditLogID:   uuid.New(),
+			Action:       adminAuditAction(c.Request.Method),
+			ResourceType: adminAuditResourceType(c.FullPath()),
+			ResourceID:   adminAuditResourceID(c.FullPath(), c.Params),
+			Request: &entities.AdminAuditRequest{
+				Method:     c.Request.Method,
+				Path:       c.FullPath(),
+				Params:     params,
+				Body:       body,
+				StatusCode: c.Writer.Status(),
+				SystemRole: c.GetInt("system_role"),
+			},
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		if adminID := adminUserID(c); adminID != uuid.Nil {
+			auditLog.AdminID = &adminID
+		}
+
+		if err := adminService.RecordAction(c.Request.Context(), auditLog); err != nil {
+			utils.Log().Error().Err(err).Str("path", c.FullPath()).Msg("failed to record admin action")
+		}
+	}
+}
+
+// adminAuditAction names the action of a request method
+func adminAuditAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return entities.ActivityActionCreated
+	case http.MethodDelete:
+		return entities.ActivityActionDeleted
+	default:
+		return entities.ActivityActionUpdated
+	}
+}
+
+// adminAuditResourceTypes names the resource of each v0 route group
+var adminAuditResourceTypes = map[string]string{
+	"users":              "user",
+	"banks":              "bank",
+	"currencies":         "currency",
+	"subscription-plans": "subscription_plan",
+	"taxonomies":         "taxonomy",
+	"exchange-rates":     "exchange_rate",
+	"merchants":          "merchant",
+	"admins":             "system_role",
+}
+
+// adminAuditResourceType names the resource of a route, e.g. "bank" for /v0/banks/:id
+func adminAuditResourceType(fullPath string) string {
+	segments := strings.Split(strings.TrimPrefix(fullPath, "/v0/"), "/")
+	if segments[0] == "merchants" && strings.Contains(fullPath, "/aliases") {
+		return "merchant_alias"
+	}
+	if resourceType, ok := adminAuditResourceTypes[segments[0]]; ok {
+		return resourceType
+	}
+	return segments[0]
+}
+
+// adminAuditResourceID returns the ID of the resource of a route when it is a UUID, other IDs are kept in the params.
+// The :id of the alias routes is their merchant.
+func adminAuditResourceID(fullPath string, params gin.Params) *uuid.UUID {
+	key := "id"
+	if adminAuditResourceType(fullPath) == "merchant_alias" {
+		key = "alias_id"
+	}
+	if id, err := uuid.Parse(params.ByName(key)); err == nil {
+		return &id
+	}
+	return nil
+}
+
+// redactSecrets hides passwords and tokens of a request body before it is kept
+func redactSecrets(body map[string]interface{}) {
+	for key, value := range body {
+		lower := strings.ToLower(key)
+		if strings.Contains(lower, "password") || strings.Contains(lower, "token") || strings.Contains(lower, "secret") {
+			body[key] = "[REDACTED]"
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			redactSecrets(nested)
+		}
+	}
+}
@@ -21,21 +21,23 @@ func newBankAdminRoutes(handler *gin.RouterGroup, bankService services.BankServi
 		auth:        auth,
 	}
 
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
 	// Bank endpoints
 	banks := handler.Group("/banks")
 	{
-		// Protected endpoints - authentication required
-		banks.GET("", auth.AuthRequired(), r.GetAllBanks)
-		banks.POST("", auth.AuthRequired(), r.CreateBank)
-		banks.GET("/:id", auth.AuthRequired(), r.GetBankByID)
-		banks.PUT("/:id", auth.AuthRequired(), r.UpdateBank)
-		banks.DELETE("/:id", auth.AuthRequired(), r.DeleteBank)
-		banks.GET("/code/:code", auth.AuthRequired(), r.GetBankByCode)
+		banks.GET("", r.GetAllBanks)
+		banks.POST("", superAdmin, r.CreateBank)
+		banks.GET("/:id", r.GetBankByID)
+		banks.PUT("/:id", superAdmin, r.UpdateBank)
+		banks.DELETE("/:id", superAdmin, r.DeleteBank)
+		banks.GET("/code/:code", r.GetBankByCode)
 	}
 }
 
 // @Summary Get all banks
-// @Description Get a list of all active banks (public endpoint)
+// @Description Get a list of all active banks
 // @Tags banks
 // @Accept json
 // @Produce json
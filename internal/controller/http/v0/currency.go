@@ -21,19 +21,22 @@ func newCurrencyAdminRoutes(handler *gin.RouterGroup, currencyService services.C
 		auth:            auth,
 	}
 
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
 	// Currency endpoints
 	currencies := handler.Group("/currencies")
 	{
-		currencies.GET("", auth.AuthRequired(), r.GetAllCurrencies)
-		currencies.POST("", auth.AuthRequired(), r.CreateCurrency)
-		currencies.GET("/:id", auth.AuthRequired(), r.GetCurrencyByID)
-		currencies.PUT("/:id", auth.AuthRequired(), r.UpdateCurrency)
-		currencies.DELETE("/:id", auth.AuthRequired(), r.DeleteCurrency)
+		currencies.GET("", r.GetAllCurrencies)
+		currencies.POST("", superAdmin, r.CreateCurrency)
+		currencies.GET("/:id", r.GetCurrencyByID)
+		currencies.PUT("/:id", superAdmin, r.UpdateCurrency)
+		currencies.DELETE("/:id", superAdmin, r.DeleteCurrency)
 	}
 }
 
 // @Summary Get all currencies
-// @Description Get a list of all active currencies (public endpoint)
+// @Description Get a list of all active currencies
 // @Tags currencies
 // @Accept json
 // @Produce json
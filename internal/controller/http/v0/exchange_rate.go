@@ -0,0 +1,166 @@
+package v0
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type exchangeRateAdminRoutes struct {
+	exchangeRateService services.ExchangeRateService
+	auth                *middleware.AuthMiddleware
+}
+
+func newExchangeRateAdminRoutes(handler *gin.RouterGroup, exchangeRateService services.ExchangeRateService, auth *middleware.AuthMiddleware) {
+	r := &exchangeRateAdminRoutes{
+		exchangeRateService: exchangeRateService,
+		auth:                auth,
+	}
+
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
+	// Exchange rate endpoints
+	exchangeRates := handler.Group("/exchange-rates")
+	{
+		exchangeRates.GET("", r.GetAllExchangeRates)
+		exchangeRates.PUT("", superAdmin, r.UpsertExchangeRate)
+		exchangeRates.DELETE("/:id", superAdmin, r.DeleteExchangeRate)
+	}
+}
+
+// @Summary Get all exchange rates
+// @Description Get exchange rates, newest effective date first
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /exchange-rates [get]
+func (r *exchangeRateAdminRoutes) GetAllExchangeRates(c *gin.Context) {
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	exchangeRates, err := r.exchangeRateService.GetAllExchangeRates(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    exchangeRates,
+	})
+}
+
+// @Summary Set an exchange rate
+// @Description Create or replace the rate of a currency pair for an effective date
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.UpsertExchangeRateInput true "Exchange rate details"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /exchange-rates [put]
+func (r *exchangeRateAdminRoutes) UpsertExchangeRate(c *gin.Context) {
+	var input entities.UpsertExchangeRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	exchangeRate, err := r.exchangeRateService.UpsertExchangeRate(c.Request.Context(), &input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "currency not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "from and to currency must be different" ||
+			err.Error() == "rate must be greater than 0" ||
+			err.Error() == "invalid effective date format, expected YYYY-MM-DD" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    exchangeRate,
+	})
+}
+
+// @Summary Delete an exchange rate
+// @Description Delete an exchange rate by its ID
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exchange Rate ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /exchange-rates/{id} [delete]
+func (r *exchangeRateAdminRoutes) DeleteExchangeRate(c *gin.Context) {
+	exchangeRateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid exchange rate ID format",
+		})
+		return
+	}
+
+	err = r.exchangeRateService.DeleteExchangeRate(c.Request.Context(), exchangeRateID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "exchange rate not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Exchange rate deleted successfully",
+	})
+}
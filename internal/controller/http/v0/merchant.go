@@ -0,0 +1,347 @@
+package v0
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type merchantAdminRoutes struct {
+	merchantService services.MerchantService
+	auth            *middleware.AuthMiddleware
+}
+
+func newMerchantAdminRoutes(handler *gin.RouterGroup, merchantService services.MerchantService, auth *middleware.AuthMiddleware) {
+	r := &merchantAdminRoutes{
+		merchantService: merchantService,
+		auth:            auth,
+	}
+
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
+	// Merchant endpoints
+	merchants := handler.Group("/merchants")
+	{
+		merchants.GET("", r.GetAllMerchants)
+		merchants.POST("", superAdmin, r.CreateMerchant)
+		merchants.PUT("/:id", superAdmin, r.UpdateMerchant)
+		merchants.DELETE("/:id", superAdmin, r.DeleteMerchant)
+		merchants.GET("/:id/aliases", r.GetMerchantAliases)
+		merchants.POST("/:id/aliases", superAdmin, r.CreateMerchantAlias)
+		merchants.DELETE("/aliases/:alias_id", superAdmin, r.DeleteMerchantAlias)
+	}
+}
+
+// merchantErrorStatus maps merchant service errors to HTTP status codes
+func merchantErrorStatus(err error) int {
+	switch err.Error() {
+	case "merchant not found", "merchant alias not found":
+		return http.StatusNotFound
+	case "merchant with this name already exists", "merchant alias already exists":
+		return http.StatusConflict
+	case "merchant name is required", "pattern is required", "invalid match type":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get all merchants
+// @Description Get all merchants of the merchant directory, including inactive ones
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Filter by name"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants [get]
+func (r *merchantAdminRoutes) GetAllMerchants(c *gin.Context) {
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	merchants, err := r.merchantService.GetAllMerchants(c.Request.Context(), c.Query("search"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    merchants,
+	})
+}
+
+// @Summary Create a merchant
+// @Description Create a global merchant. Its normalized name is added as a prefix alias.
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.CreateMerchantRequest true "Merchant details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants [post]
+func (r *merchantAdminRoutes) CreateMerchant(c *gin.Context) {
+	var input entities.CreateMerchantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	merchant, err := r.merchantService.CreateMerchant(c.Request.Context(), &input)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    merchant,
+	})
+}
+
+// @Summary Update a merchant
+// @Description Update a global merchant
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Merchant ID"
+// @Param input body entities.UpdateMerchantRequest true "Merchant details"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/{id} [put]
+func (r *merchantAdminRoutes) UpdateMerchant(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant ID format",
+		})
+		return
+	}
+
+	var input entities.UpdateMerchantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	merchant, err := r.merchantService.UpdateMerchant(c.Request.Context(), merchantID, &input)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    merchant,
+	})
+}
+
+// @Summary Delete a merchant
+// @Description Delete a global merchant and its aliases. Transactions keep their merchant name.
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Merchant ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/{id} [delete]
+func (r *merchantAdminRoutes) DeleteMerchant(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant ID format",
+		})
+		return
+	}
+
+	err = r.merchantService.DeleteMerchant(c.Request.Context(), merchantID)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Merchant deleted successfully",
+	})
+}
+
+// @Summary Get merchant aliases
+// @Description Get the global aliases of a merchant
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Merchant ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/{id}/aliases [get]
+func (r *merchantAdminRoutes) GetMerchantAliases(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant ID format",
+		})
+		return
+	}
+
+	aliases, err := r.merchantService.GetGlobalAliases(c.Request.Context(), merchantID)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    aliases,
+	})
+}
+
+// @Summary Create a merchant alias
+// @Description Add a global matching rule to a merchant. match_type is 1 (exact), 2 (prefix, default) or 3 (contains).
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Merchant ID"
+// @Param input body entities.CreateMerchantAliasRequest true "Alias details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/{id}/aliases [post]
+func (r *merchantAdminRoutes) CreateMerchantAlias(c *gin.Context) {
+	merchantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant ID format",
+		})
+		return
+	}
+
+	var input entities.CreateMerchantAliasRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	alias, err := r.merchantService.CreateGlobalAlias(c.Request.Context(), merchantID, &input)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    alias,
+	})
+}
+
+// @Summary Delete a merchant alias
+// @Description Delete a global merchant alias
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param alias_id path string true "Merchant Alias ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/aliases/{alias_id} [delete]
+func (r *merchantAdminRoutes) DeleteMerchantAlias(c *gin.Context) {
+	merchantAliasID, err := uuid.Parse(c.Param("alias_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant alias ID format",
+		})
+		return
+	}
+
+	err = r.merchantService.DeleteGlobalAlias(c.Request.Context(), merchantAliasID)
+	if err != nil {
+		c.JSON(merchantErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Merchant alias deleted successfully",
+	})
+}
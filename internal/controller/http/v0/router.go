@@ -17,6 +17,9 @@ type Services struct {
 	CurrencyService         services.CurrencyService
 	SubscriptionPlanService services.SubscriptionPlanService
 	TaxonomyService         services.TaxonomyService
+	ExchangeRateService     services.ExchangeRateService
+	MerchantService         services.MerchantService
+	AdminService            services.AdminService
 
 	// ConversationService services.ConversationService
 	// MessageService      services.MessageService
@@ -25,6 +28,8 @@ type Services struct {
 	// WhatsAppService     services.WhatsAppService
 
 	AuthMiddleware *middleware.AuthMiddleware
+	RateLimiter    *middleware.RateLimiter
+	RateLimits     middleware.RateLimits
 }
 
 func (s Services) Initialized() error {
@@ -39,12 +44,22 @@ func NewRouter(handler *gin.Engine, s Services) {
 	}
 
 	// API Routers
-	h := handler.Group("v0")
+	h := handler.Group("v0",
+		s.RateLimiter.Limit("api", s.RateLimits.API, s.RateLimiter.KeyByClient),
+		s.RateLimiter.Limit("auth", s.RateLimits.Auth, middleware.KeyByIP, "/v0/auth/login"),
+	)
+	newAdminAuthRoutes(h, s.UserService) // Admin login, the only route open without a token
+
+	// Every other route needs a system role, changes are audited
+	admin := h.Group("", s.AuthMiddleware.AuthRequired(), s.AuthMiddleware.AdminRequired(), adminAudit(s.AdminService))
 	{
-		newUserAdminRoutes(h, s.UserService, s.AuthMiddleware)                         // User management routes
-		newBankAdminRoutes(h, s.BankService, s.AuthMiddleware)                         // Bank management routes
-		newCurrencyAdminRoutes(h, s.CurrencyService, s.AuthMiddleware)                 // Currency management routes
-		newSubscriptionPlanAdminRoutes(h, s.SubscriptionPlanService, s.AuthMiddleware) // Subscription plan management routes
-		newTaxonomyAdminRoutes(h, s.TaxonomyService, s.AuthMiddleware)                 // Taxonomy management routes
+		newUserAdminRoutes(admin, s.UserService, s.AuthMiddleware)                         // User management routes
+		newBankAdminRoutes(admin, s.BankService, s.AuthMiddleware)                         // Bank management routes
+		newCurrencyAdminRoutes(admin, s.CurrencyService, s.AuthMiddleware)                 // Currency management routes
+		newSubscriptionPlanAdminRoutes(admin, s.SubscriptionPlanService, s.AuthMiddleware) // Subscription plan management routes
+		newTaxonomyAdminRoutes(admin, s.TaxonomyService, s.AuthMiddleware)                 // Taxonomy management routes
+		newExchangeRateAdminRoutes(admin, s.ExchangeRateService, s.AuthMiddleware)         // Exchange rate management routes
+		newMerchantAdminRoutes(admin, s.MerchantService, s.AuthMiddleware)                 // Merchant management routes
+		newAdminRoutes(admin, s.AdminService, s.AuthMiddleware)                            // Admin management and audit log routes
 	}
 }
@@ -21,19 +21,22 @@ func newSubscriptionPlanAdminRoutes(handler *gin.RouterGroup, subscriptionPlanSe
 		auth:                    auth,
 	}
 
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
 	// Plan endpoints
 	subscriptionPlans := handler.Group("/subscription-plans")
 	{
-		subscriptionPlans.GET("", auth.AuthRequired(), r.GetAllSubscriptionPlans)
-		subscriptionPlans.POST("", auth.AuthRequired(), r.CreateSubscriptionPlan)
-		subscriptionPlans.GET("/:id", auth.AuthRequired(), r.GetSubscriptionPlanByID)
-		subscriptionPlans.PUT("/:id", auth.AuthRequired(), r.UpdateSubscriptionPlan)
-		subscriptionPlans.DELETE("/:id", auth.AuthRequired(), r.DeleteSubscriptionPlan)
+		subscriptionPlans.GET("", r.GetAllSubscriptionPlans)
+		subscriptionPlans.POST("", superAdmin, r.CreateSubscriptionPlan)
+		subscriptionPlans.GET("/:id", r.GetSubscriptionPlanByID)
+		subscriptionPlans.PUT("/:id", superAdmin, r.UpdateSubscriptionPlan)
+		subscriptionPlans.DELETE("/:id", superAdmin, r.DeleteSubscriptionPlan)
 	}
 }
 
 // @Summary Get all subscription plans
-// @Description Get a list of all active subscription plans (public endpoint)
+// @Description Get a list of all active subscription plans
 // @Tags subscription-plans
 // @Accept json
 // @Produce json
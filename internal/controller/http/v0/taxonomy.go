@@ -21,18 +21,20 @@ func newTaxonomyAdminRoutes(handler *gin.RouterGroup, taxonomyService services.T
 		auth:            auth,
 	}
 
-	// Taxonomy endpoints - all require authentication
+	// Any admin reads, only superadmins change the data
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
+	// Taxonomy endpoints
 	taxonomies := handler.Group("/taxonomies")
-	taxonomies.Use(auth.AuthRequired())
 	{
 		taxonomies.GET("", r.GetAllTaxonomies)
-		taxonomies.POST("", r.CreateTaxonomy)
+		taxonomies.POST("", superAdmin, r.CreateTaxonomy)
 		taxonomies.GET("/active", r.GetActiveTaxonomies)
 		taxonomies.GET("/type/:type", r.GetTaxonomiesByType)
 		taxonomies.GET("/type/:type/value/:value", r.GetTaxonomyByTypeAndValue)
 		taxonomies.GET("/:id", r.GetTaxonomyByID)
-		taxonomies.PUT("/:id", r.UpdateTaxonomy)
-		taxonomies.DELETE("/:id", r.DeleteTaxonomy)
+		taxonomies.PUT("/:id", superAdmin, r.UpdateTaxonomy)
+		taxonomies.DELETE("/:id", superAdmin, r.DeleteTaxonomy)
 	}
 }
 
@@ -22,25 +22,31 @@ func newUserAdminRoutes(handler *gin.RouterGroup, userService services.UserServi
 		auth:        auth,
 	}
 
+	// Any admin reads, support also creates and updates users, only superadmins delete them
+	userManager := auth.AdminRequired(entities.SystemRoleSuperAdmin, entities.SystemRoleSupport)
+	superAdmin := auth.AdminRequired(entities.SystemRoleSuperAdmin)
+
 	// User management endpoints
 	users := handler.Group("/users")
 	{
 		users.GET("", r.ListAllUsers)
-		users.POST("", r.CreateUser)
+		users.POST("", userManager, r.CreateUser)
 		users.GET("/:id", r.GetUserByID)
-		users.PUT("/:id", r.UpdateUser)
-		users.DELETE("/:id", r.DeleteUser)
+		users.PUT("/:id", userManager, r.UpdateUser)
+		users.DELETE("/:id", superAdmin, r.DeleteUser)
 		users.GET("/email/:email", r.GetUserByEmail)
 		users.GET("/phone/:phone", r.GetUserByPhoneNumber)
 	}
+}
+
+// newAdminAuthRoutes registers the login of the administration API, the only v0 route open without a token.
+// Password resets go through v1, the account is the same.
+func newAdminAuthRoutes(handler *gin.RouterGroup, userService services.UserService) {
+	r := &userAdminRoutes{userService: userService}
 
-	// Authentication endpoints
 	authRoutes := handler.Group("/auth")
 	{
 		authRoutes.POST("/login", r.Login)
-		authRoutes.POST("/forgot-password", r.ForgotPassword)
-		authRoutes.POST("/reset-password", r.ResetPassword)
-		authRoutes.POST("/change-password", r.ChangePassword)
 	}
 }
 
@@ -211,6 +217,8 @@ func (r *userAdminRoutes) UpdateUser(c *gin.Context) {
 		} else if err.Error() == "phone number already in use" ||
 			err.Error() == "email already in use" {
 			status = http.StatusConflict
+		} else if err.Error() == "phone number changes must be verified" {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -345,8 +353,8 @@ func (r *userAdminRoutes) GetUserByPhoneNumber(c *gin.Context) {
 	})
 }
 
-// @Summary Login
-// @Description Authenticate a user with email/phone and password
+// @Summary Admin login
+// @Description Authenticate a user holding a system role with phone number and PIN
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -356,6 +364,7 @@ func (r *userAdminRoutes) GetUserByPhoneNumber(c *gin.Context) {
 // @Failure 401 {object} entities.ApiResponse
 // @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
 // @Router /auth/login [post]
 func (r *userAdminRoutes) Login(c *gin.Context) {
 	var input entities.LoginRequest
@@ -367,25 +376,26 @@ func (r *userAdminRoutes) Login(c *gin.Context) {
 		return
 	}
 
-	if (input.Email == "" && input.Phone == "") || input.Password == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "email or phone and password are required",
-		})
-		return
-	}
+	ctx := middleware.WithSessionClient(c.Request.Context(), entities.SessionClient{
+		DeviceName: input.DeviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	})
 
-	loginResponse, err := r.userService.Login(c.Request.Context(), &input)
+	loginResponse, err := r.userService.AdminLogin(ctx, &input)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
+		switch err.Error() {
+		case "user not found":
 			status = http.StatusNotFound
-		} else if err.Error() == "password is incorrect" {
+		case "password is incorrect":
 			status = http.StatusUnauthorized
-		} else if err.Error() == "user account is inactive" {
+		case "user account is inactive", "admin access required":
 			status = http.StatusForbidden
-		} else if err.Error() == "email or phone is required" {
+		case "phone number is required":
 			status = http.StatusBadRequest
+		case "too many incorrect attempts, try again later":
+			status = http.StatusTooManyRequests
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -400,116 +410,23 @@ func (r *userAdminRoutes) Login(c *gin.Context) {
 	})
 }
 
-// @Summary Forgot Password
-// @Description Initiate password reset process
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param input body entities.ForgotPasswordRequest true "Forgot password request"
-// @Success 200 {object} entities.ApiResponse
-// @Failure 400 {object} entities.ApiResponse
-// @Failure 404 {object} entities.ApiResponse
-// @Router /auth/forgot-password [post]
-func (r *userAdminRoutes) ForgotPassword(c *gin.Context) {
-	var input entities.ForgotPasswordRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
+// verificationErrorStatus maps phone and email verification errors to HTTP status codes
+func verificationErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid verification code", "verification code has expired", "maximum verification attempts exceeded",
+		"invalid or expired verification link", "email is required":
+		return http.StatusBadRequest
+	case "user not found", "no active verification code found":
+		return http.StatusNotFound
+	case "phone number is already verified", "email is already verified":
+		return http.StatusConflict
+	case "please wait before requesting another code", "too many codes requested, try again later":
+		return http.StatusTooManyRequests
+	case "failed to deliver verification code":
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
 	}
-
-	err := r.userService.ForgotPassword(c.Request.Context(), &input)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, &entities.ApiResponse{
-		Success: true,
-		Message: "Password reset instructions sent to your email",
-	})
-}
-
-// @Summary Reset Password
-// @Description Reset password using token
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param input body entities.ResetPasswordRequest true "Reset password request"
-// @Success 200 {object} entities.ApiResponse
-// @Failure 400 {object} entities.ApiResponse
-// @Router /auth/reset-password [post]
-func (r *userAdminRoutes) ResetPassword(c *gin.Context) {
-	var input entities.ResetPasswordRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	err := r.userService.ResetPassword(c.Request.Context(), &input)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, &entities.ApiResponse{
-		Success: true,
-		Message: "Password reset successfully",
-	})
-}
-
-// @Summary Change Password
-// @Description Change user's password
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param input body entities.ChangePasswordRequest true "Change password request"
-// @Success 200 {object} entities.ApiResponse
-// @Failure 400 {object} entities.ApiResponse
-// @Failure 501 {object} entities.ApiResponse
-// @Router /auth/change-password [post]
-func (r *userAdminRoutes) ChangePassword(c *gin.Context) {
-	var input entities.ChangePasswordRequest
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	err := r.userService.ChangePassword(c.Request.Context(), &input)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "change password not implemented" {
-			status = http.StatusNotImplemented
-		}
-		c.JSON(status, &entities.ApiResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, &entities.ApiResponse{
-		Success: true,
-		Message: "Password changed successfully",
-	})
 }
 
 // @Summary Verify Phone
@@ -534,11 +451,7 @@ func (r *userAdminRoutes) VerifyPhone(c *gin.Context) {
 
 	err := r.userService.VerifyPhone(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -573,11 +486,7 @@ func (r *userAdminRoutes) ResendVerificationCode(c *gin.Context) {
 
 	err := r.userService.ResendVerificationCode(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -612,11 +521,7 @@ func (r *userAdminRoutes) VerifyEmail(c *gin.Context) {
 
 	err := r.userService.VerifyEmail(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -651,11 +556,7 @@ func (r *userAdminRoutes) ResendVerificationEmail(c *gin.Context) {
 
 	err := r.userService.ResendVerificationEmail(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type activityRoutes struct {
+	activityService services.ActivityService
+	auth            *middleware.AuthMiddleware
+}
+
+func newActivityRoutes(handler *gin.RouterGroup, activityService services.ActivityService, auth *middleware.AuthMiddleware) {
+	r := &activityRoutes{
+		activityService: activityService,
+		auth:            auth,
+	}
+
+	// Activity feed endpoints
+	activities := handler.Group("/activities")
+	activities.Use(r.auth.AuthRequired())
+	{
+		activities.GET("", r.GetActivities)
+	}
+}
+
+// activityErrorStatus maps activity service errors to HTTP status codes
+func activityErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found":
+		return http.StatusNotFound
+	case "access denied to workspace":
+		return http.StatusForbidden
+	case "workspace is archived":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get the workspace activity feed
+// @Description Get the transaction, budget, category and member changes of a workspace, newest first, with who made them, from which channel and a summary in Indonesian and English
+// @Tags activities
+// @Accept json
+// @Produce json
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param actor_id query string false "Filter by the user who made the change"
+// @Param resource_type query string false "Filter by resource type (transaction, budget, category, member)"
+// @Param action query string false "Filter by action (create, update, delete, join)"
+// @Param channel query string false "Filter by source channel (web, whatsapp, api)"
+// @Param ai_generated query boolean false "Filter by AI generated entries"
+// @Param start_date query string false "Start date filter (YYYY-MM-DD)"
+// @Param end_date query string false "End date filter, inclusive (YYYY-MM-DD)"
+// @Param limit query int false "Limit for pagination (default: 20)"
+// @Param offset query int false "Offset for pagination (default: 0)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /activities [get]
+func (r *activityRoutes) GetActivities(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	// Parse pagination parameters
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	// Parse filter parameters
+	params := parseActivityListParams(c)
+	params.WorkspaceID = workspaceID
+
+	activities, totalCount, err := r.activityService.GetActivities(c.Request.Context(), userID, params, limit, offset)
+	if err != nil {
+		c.JSON(activityErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"activities": activities,
+			"total":      totalCount,
+			"limit":      limit,
+			"offset":     offset,
+		},
+	})
+}
+
+// parseActivityListParams reads the activity feed filters from the query, invalid values are ignored
+func parseActivityListParams(c *gin.Context) *entities.ActivityListParams {
+	params := &entities.ActivityListParams{
+		ResourceType: c.Query("resource_type"),
+		Action:       c.Query("action"),
+		Channel:      c.Query("channel"),
+	}
+
+	if actorIDStr := c.Query("actor_id"); actorIDStr != "" {
+		if actorID, err := uuid.Parse(actorIDStr); err == nil {
+			params.ActorID = &actorID
+		}
+	}
+
+	if aiGeneratedStr := c.Query("ai_generated"); aiGeneratedStr != "" {
+		if aiGenerated, err := strconv.ParseBool(aiGeneratedStr); err == nil {
+			params.AIGenerated = &aiGenerated
+		}
+	}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			params.StartDate = &startDate
+		}
+	}
+
+	// The end date is inclusive, the feed is filtered up to the start of the next day
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = endDate.AddDate(0, 0, 1)
+			params.EndDate = &endDate
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type analyticsRoutes struct {
+	analyticsService services.AnalyticsService
+	auth             *middleware.AuthMiddleware
+}
+
+func newAnalyticsRoutes(handler *gin.RouterGroup, analyticsService services.AnalyticsService, auth *middleware.AuthMiddleware) {
+	r := &analyticsRoutes{
+		analyticsService: analyticsService,
+		auth:             auth,
+	}
+
+	// All analytics endpoints require authentication
+	analytics := handler.Group("/analytics").Use(auth.AuthRequired())
+	{
+		analytics.GET("/breakdown", r.GetBreakdown)
+		analytics.GET("/timeseries", r.GetTimeSeries)
+	}
+}
+
+// analyticsErrorStatus maps analytics service errors to HTTP status codes
+func analyticsErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found":
+		return http.StatusNotFound
+	case "access denied to workspace":
+		return http.StatusForbidden
+	case "invalid group by",
+		"invalid interval",
+		"invalid transaction type",
+		"start date must be before end date":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTransactionTypeQuery parses the optional transaction_type query parameter, 0 when absent or invalid
+func parseTransactionTypeQuery(c *gin.Context) int {
+	if transactionTypeStr := c.Query("transaction_type"); transactionTypeStr != "" {
+		if transactionType, err := strconv.Atoi(transactionTypeStr); err == nil {
+			return transactionType
+		}
+	}
+	return 0
+}
+
+// @Summary Get spending breakdown
+// @Description Get the totals of a workspace grouped by category (rolled up the parent hierarchy), merchant, account, tag or payment method, converted to the workspace currency
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param group_by query string false "category, merchant, account, tag or payment_method (default: category)"
+// @Param transaction_type query int false "1 - income, 2 - expense (default: 2)"
+// @Param account_id query string false "Filter by account ID"
+// @Param category_id query string false "Filter by category ID"
+// @Param start_date query string false "Start date filter (YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (YYYY-MM-DD)"
+// @Param payment_method query int false "Filter by payment method"
+// @Param description query string false "Filter by description (partial match)"
+// @Param merchant_name query string false "Filter by merchant name (partial match)"
+// @Param amount query number false "Filter by exact amount"
+// @Param is_recurring query boolean false "Filter by recurring status"
+// @Param credit_status query int false "Filter by credit status"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /analytics/breakdown [get]
+func (r *analyticsRoutes) GetBreakdown(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	groupBy := c.DefaultQuery("group_by", entities.AnalyticsGroupByCategory)
+
+	breakdown, err := r.analyticsService.GetBreakdown(c.Request.Context(), userID, workspaceID, groupBy, parseTransactionTypeQuery(c), parseTransactionListParams(c))
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    breakdown,
+	})
+}
+
+// @Summary Get spending time series
+// @Description Get the totals of a workspace per day, week or month compared with the previous period, converted to the workspace currency
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param interval query string false "day, week or month (default: day)"
+// @Param transaction_type query int false "1 - income, 2 - expense (default: 2)"
+// @Param account_id query string false "Filter by account ID"
+// @Param category_id query string false "Filter by category ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to 30 days, 12 weeks or 12 months before the end date"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param payment_method query int false "Filter by payment method"
+// @Param description query string false "Filter by description (partial match)"
+// @Param merchant_name query string false "Filter by merchant name (partial match)"
+// @Param amount query number false "Filter by exact amount"
+// @Param is_recurring query boolean false "Filter by recurring status"
+// @Param credit_status query int false "Filter by credit status"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /analytics/timeseries [get]
+func (r *analyticsRoutes) GetTimeSeries(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	interval := c.DefaultQuery("interval", entities.AnalyticsIntervalDay)
+
+	series, err := r.analyticsService.GetTimeSeries(c.Request.Context(), userID, workspaceID, interval, parseTransactionTypeQuery(c), parseTransactionListParams(c))
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    series,
+	})
+}
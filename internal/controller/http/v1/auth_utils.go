@@ -1,11 +1,13 @@
 package v1
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 )
 
 // GetAuthenticatedUserID extracts and validates the authenticated user ID from the Gin context
@@ -73,3 +75,71 @@ func GetOptionalAuthenticatedUserID(c *gin.Context) (uuid.UUID, bool) {
 
 	return userID, true
 }
+
+// GetActiveWorkspaceID returns the active workspace of the authenticated user from the token
+func GetActiveWorkspaceID(c *gin.Context) (uuid.UUID, bool) {
+	workspaceIDInterface, ok := c.Get("active_workspace_id")
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	workspaceID, ok := workspaceIDInterface.(uuid.UUID)
+	return workspaceID, ok
+}
+
+// defaultToActiveWorkspace sets a request body workspace ID to the active workspace when it is not given
+func defaultToActiveWorkspace(c *gin.Context, workspaceID *uuid.UUID) {
+	if *workspaceID != uuid.Nil {
+		return
+	}
+	if activeWorkspaceID, ok := GetActiveWorkspaceID(c); ok {
+		*workspaceID = activeWorkspaceID
+	}
+}
+
+// parseWorkspaceIDQuery parses the workspace_id query parameter, falling back to the active workspace
+// If parsing fails, it automatically sends an error response and returns false
+func parseWorkspaceIDQuery(c *gin.Context) (uuid.UUID, bool) {
+	workspaceIDStr := c.Query("workspace_id")
+	if workspaceIDStr == "" {
+		if workspaceID, ok := GetActiveWorkspaceID(c); ok {
+			return workspaceID, true
+		}
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "workspace_id is required",
+		})
+		return uuid.Nil, false
+	}
+
+	workspaceID, err := uuid.Parse(workspaceIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace_id format",
+		})
+		return uuid.Nil, false
+	}
+
+	return workspaceID, true
+}
+
+// GetSessionID returns the session of the access token of the request
+func GetSessionID(c *gin.Context) (uuid.UUID, bool) {
+	sessionIDInterface, ok := c.Get("session_id")
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	sessionID, ok := sessionIDInterface.(uuid.UUID)
+	return sessionID, ok
+}
+
+// withSessionClient returns the request context carrying the device a session is opened or refreshed from
+func withSessionClient(c *gin.Context, deviceName string) context.Context {
+	return middleware.WithSessionClient(c.Request.Context(), entities.SessionClient{
+		DeviceName: deviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	})
+}
@@ -62,14 +62,21 @@ func bindBudgetRequest(c *gin.Context, isUpdate bool) (interface{}, error) {
 		}
 
 		// Parse other fields
-		if userCategoryIDStr, ok := rawData["user_category_id"].(string); ok {
+		// user_category_id is optional, a budget can be scoped through the scope object instead
+		if userCategoryIDStr, ok := rawData["user_category_id"].(string); ok && userCategoryIDStr != "" {
 			if id, err := uuid.Parse(userCategoryIDStr); err == nil {
 				input.UserCategoryID = id
 			} else {
 				return nil, fmt.Errorf("invalid user_category_id: %v", err)
 			}
-		} else {
-			return nil, fmt.Errorf("user_category_id is required")
+		}
+
+		if rawScope, ok := rawData["scope"]; ok && rawScope != nil {
+			scope, err := parseBudgetScope(rawScope)
+			if err != nil {
+				return nil, err
+			}
+			input.Scope = scope
 		}
 
 		if name, ok := rawData["name"].(string); ok {
@@ -90,12 +97,6 @@ func bindBudgetRequest(c *gin.Context, isUpdate bool) (interface{}, error) {
 			return nil, fmt.Errorf("period_type is required")
 		}
 
-		if spentAmount, ok := rawData["spent_amount"].(float64); ok {
-			input.SpentAmount = spentAmount
-		} else {
-			return nil, fmt.Errorf("spent_amount is required")
-		}
-
 		if isActive, ok := rawData["is_active"].(bool); ok {
 			input.IsActive = isActive
 		} else {
@@ -118,6 +119,7 @@ func bindBudgetRequest(c *gin.Context, isUpdate bool) (interface{}, error) {
 			}
 		}
 		input.WorkspaceID = workspaceID
+		defaultToActiveWorkspace(c, &input.WorkspaceID)
 
 		// Parse dates manually
 		if periodStartStr, ok := rawData["period_start"].(string); ok {
@@ -141,14 +143,21 @@ func bindBudgetRequest(c *gin.Context, isUpdate bool) (interface{}, error) {
 		}
 
 		// Parse other fields
-		if userCategoryIDStr, ok := rawData["user_category_id"].(string); ok {
+		// user_category_id is optional, a budget can be scoped through the scope object instead
+		if userCategoryIDStr, ok := rawData["user_category_id"].(string); ok && userCategoryIDStr != "" {
 			if id, err := uuid.Parse(userCategoryIDStr); err == nil {
 				input.UserCategoryID = id
 			} else {
 				return nil, fmt.Errorf("invalid user_category_id: %v", err)
 			}
-		} else {
-			return nil, fmt.Errorf("user_category_id is required")
+		}
+
+		if rawScope, ok := rawData["scope"]; ok && rawScope != nil {
+			scope, err := parseBudgetScope(rawScope)
+			if err != nil {
+				return nil, err
+			}
+			input.Scope = scope
 		}
 
 		if name, ok := rawData["name"].(string); ok {
@@ -173,6 +182,21 @@ func bindBudgetRequest(c *gin.Context, isUpdate bool) (interface{}, error) {
 	}
 }
 
+// parseBudgetScope converts the raw scope object of a budget request into a BudgetScope
+func parseBudgetScope(rawScope interface{}) (entities.BudgetScope, error) {
+	var scope entities.BudgetScope
+
+	scopeJSON, err := json.Marshal(rawScope)
+	if err != nil {
+		return scope, fmt.Errorf("invalid scope: %v", err)
+	}
+	if err := json.Unmarshal(scopeJSON, &scope); err != nil {
+		return scope, fmt.Errorf("invalid scope: %v", err)
+	}
+
+	return scope, nil
+}
+
 type budgetRoutes struct {
 	budgetService services.BudgetService
 	auth          *middleware.AuthMiddleware
@@ -190,6 +214,7 @@ func newBudgetRoutes(handler *gin.RouterGroup, budgetService services.BudgetServ
 		budgets.GET("", r.GetAllBudgets)
 		budgets.POST("", r.CreateBudget)
 		budgets.GET("/:id", r.GetBudgetByID)
+		budgets.GET("/:id/transactions", r.GetBudgetTransactions)
 		budgets.PUT("/:id", r.UpdateBudget)
 		budgets.DELETE("/:id", r.DeleteBudget)
 	}
@@ -201,12 +226,13 @@ func newBudgetRoutes(handler *gin.RouterGroup, budgetService services.BudgetServ
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Param limit query int false "Limit for pagination"
 // @Param offset query int false "Offset for pagination"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /budgets [get]
 func (r *budgetRoutes) GetAllBudgets(c *gin.Context) {
@@ -215,24 +241,9 @@ func (r *budgetRoutes) GetAllBudgets(c *gin.Context) {
 	if !ok {
 		return
 	}
-	_ = userID // We have the userID for future workspace validation
-
-	// Get workspace ID from query parameter
-	workspaceIDStr := c.Query("workspace_id")
-	if workspaceIDStr == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "workspace_id is required",
-		})
-		return
-	}
 
-	workspaceID, err := uuid.Parse(workspaceIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "invalid workspace_id format",
-		})
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
 		return
 	}
 
@@ -251,9 +262,15 @@ func (r *budgetRoutes) GetAllBudgets(c *gin.Context) {
 		}
 	}
 
-	budgets, err := r.budgetService.GetAllBudgets(c.Request.Context(), workspaceID, limit, offset)
+	budgets, err := r.budgetService.GetAllBudgets(c.Request.Context(), workspaceID, userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+		status := http.StatusInternalServerError
+		if err.Error() == "workspace not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -273,10 +290,11 @@ func (r *budgetRoutes) GetAllBudgets(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Budget ID"
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /budgets/{id} [get]
@@ -286,7 +304,6 @@ func (r *budgetRoutes) GetBudgetByID(c *gin.Context) {
 	if !ok {
 		return
 	}
-	_ = userID // We have the userID for future workspace validation
 
 	budgetID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -297,30 +314,92 @@ func (r *budgetRoutes) GetBudgetByID(c *gin.Context) {
 		return
 	}
 
-	// Get workspace ID from query parameter
-	workspaceIDStr := c.Query("workspace_id")
-	if workspaceIDStr == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	budget, err := r.budgetService.GetBudgetByID(c.Request.Context(), budgetID, workspaceID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "budget not found" || err.Error() == "workspace not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, &entities.ApiResponse{
 			Success: false,
-			Error:   "workspace_id is required",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	workspaceID, err := uuid.Parse(workspaceIDStr)
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    budget,
+	})
+}
+
+// @Summary Get budget transactions
+// @Description Get the transactions that contributed to a budget's spent amount
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Budget ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /budgets/{id}/transactions [get]
+func (r *budgetRoutes) GetBudgetTransactions(c *gin.Context) {
+	// Get authenticated user ID
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	budgetID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
 			Success: false,
-			Error:   "invalid workspace_id format",
+			Error:   "invalid budget ID format",
 		})
 		return
 	}
 
-	budget, err := r.budgetService.GetBudgetByID(c.Request.Context(), budgetID, workspaceID)
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	transactions, err := r.budgetService.GetBudgetTransactions(c.Request.Context(), budgetID, workspaceID, userID, limit, offset)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "budget not found" {
+		if err.Error() == "budget not found" || err.Error() == "workspace not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -331,7 +410,7 @@ func (r *budgetRoutes) GetBudgetByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
-		Data:    budget,
+		Data:    transactions,
 	})
 }
 
@@ -341,11 +420,12 @@ func (r *budgetRoutes) GetBudgetByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Param input body entities.CreateBudgetRequest true "Budget details"
 // @Success 201 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /budgets [post]
 func (r *budgetRoutes) CreateBudget(c *gin.Context) {
@@ -368,13 +448,23 @@ func (r *budgetRoutes) CreateBudget(c *gin.Context) {
 	budget, err := r.budgetService.CreateBudget(c.Request.Context(), createInput.WorkspaceID, userID, createInput)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "budget name is required" ||
+		if err.Error() == "workspace not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
+		} else if err.Error() == "budget name is required" ||
 			err.Error() == "budgeted amount must be greater than 0" ||
 			err.Error() == "invalid period type" ||
 			err.Error() == "period start is required" ||
 			err.Error() == "period end is required" ||
 			err.Error() == "period end must be after period start" ||
-			err.Error() == "user category ID is required" {
+			err.Error() == "budget scope is required" ||
+			err.Error() == "budget scope category not found" ||
+			err.Error() == "budget scope tag not found" ||
+			err.Error() == "budget scope account not found" ||
+			err.Error() == "total spend budget cannot have scope filters" {
 			status = http.StatusBadRequest
 		}
 		c.JSON(status, &entities.ApiResponse{
@@ -397,11 +487,12 @@ func (r *budgetRoutes) CreateBudget(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Budget ID"
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Param input body entities.UpdateBudgetRequest true "Updated budget details"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /budgets/{id} [put]
@@ -411,7 +502,6 @@ func (r *budgetRoutes) UpdateBudget(c *gin.Context) {
 	if !ok {
 		return
 	}
-	_ = userID // We have the userID for future workspace validation
 
 	budgetID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -422,22 +512,8 @@ func (r *budgetRoutes) UpdateBudget(c *gin.Context) {
 		return
 	}
 
-	// Get workspace ID from query parameter
-	workspaceIDStr := c.Query("workspace_id")
-	if workspaceIDStr == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "workspace_id is required",
-		})
-		return
-	}
-
-	workspaceID, err := uuid.Parse(workspaceIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "invalid workspace_id format",
-		})
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
 		return
 	}
 
@@ -451,18 +527,26 @@ func (r *budgetRoutes) UpdateBudget(c *gin.Context) {
 	}
 
 	updateInput := input.(*entities.UpdateBudgetRequest)
-	budget, err := r.budgetService.UpdateBudget(c.Request.Context(), budgetID, workspaceID, updateInput)
+	budget, err := r.budgetService.UpdateBudget(c.Request.Context(), budgetID, workspaceID, userID, updateInput)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "budget not found" {
+		if err.Error() == "budget not found" || err.Error() == "workspace not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
 		} else if err.Error() == "budget name is required" ||
 			err.Error() == "budgeted amount must be greater than 0" ||
 			err.Error() == "invalid period type" ||
 			err.Error() == "period start is required" ||
 			err.Error() == "period end is required" ||
 			err.Error() == "period end must be after period start" ||
-			err.Error() == "user category ID is required" {
+			err.Error() == "budget scope is required" ||
+			err.Error() == "budget scope category not found" ||
+			err.Error() == "budget scope tag not found" ||
+			err.Error() == "budget scope account not found" ||
+			err.Error() == "total spend budget cannot have scope filters" {
 			status = http.StatusBadRequest
 		}
 		c.JSON(status, &entities.ApiResponse{
@@ -485,10 +569,11 @@ func (r *budgetRoutes) UpdateBudget(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Budget ID"
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /budgets/{id} [delete]
@@ -498,7 +583,6 @@ func (r *budgetRoutes) DeleteBudget(c *gin.Context) {
 	if !ok {
 		return
 	}
-	_ = userID // We have the userID for future workspace validation
 
 	budgetID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -509,30 +593,20 @@ func (r *budgetRoutes) DeleteBudget(c *gin.Context) {
 		return
 	}
 
-	// Get workspace ID from query parameter
-	workspaceIDStr := c.Query("workspace_id")
-	if workspaceIDStr == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "workspace_id is required",
-		})
-		return
-	}
-
-	workspaceID, err := uuid.Parse(workspaceIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "invalid workspace_id format",
-		})
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
 		return
 	}
 
-	err = r.budgetService.DeleteBudget(c.Request.Context(), budgetID, workspaceID)
+	err = r.budgetService.DeleteBudget(c.Request.Context(), budgetID, workspaceID, userID)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "budget not found" {
+		if err.Error() == "budget not found" || err.Error() == "workspace not found" {
 			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
 	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 	"github.com/vasst-id/vasst-expense-api/internal/services"
@@ -25,6 +26,22 @@ func newConversationRoutes(handler *gin.RouterGroup, conversationService service
 	conversations.Use(auth.AuthRequired())
 	{
 		conversations.GET("/active", r.GetActiveConversationsByUserID)
+		conversations.GET("/:id/workspace", r.GetActiveWorkspace)
+		conversations.PUT("/:id/workspace", r.SetActiveWorkspace)
+	}
+}
+
+// conversationErrorStatus maps conversation service errors to HTTP status codes
+func conversationErrorStatus(err error) int {
+	switch err.Error() {
+	case "conversation not found", "user not found", "workspace not found":
+		return http.StatusNotFound
+	case "access denied", "access denied to workspace":
+		return http.StatusForbidden
+	case "invalid conversation metadata":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
@@ -63,3 +80,100 @@ func (r *conversationRoutes) GetActiveConversationsByUserID(c *gin.Context) {
 		Data:    conversations,
 	})
 }
+
+// @Summary Get the active workspace of a conversation
+// @Description Get the workspace a conversation works in, falling back to the user's active and default workspace
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /conversations/{id}/workspace [get]
+func (r *conversationRoutes) GetActiveWorkspace(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid conversation ID format",
+		})
+		return
+	}
+
+	workspaceID, err := r.conversationService.GetActiveWorkspaceID(c.Request.Context(), userID, conversationID)
+	if err != nil {
+		c.JSON(conversationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    gin.H{"workspace_id": workspaceID},
+	})
+}
+
+// @Summary Switch the active workspace of a conversation
+// @Description Set the workspace a conversation works in, kept in the conversation metadata
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param input body entities.SetConversationWorkspaceRequest true "Workspace"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /conversations/{id}/workspace [put]
+func (r *conversationRoutes) SetActiveWorkspace(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid conversation ID format",
+		})
+		return
+	}
+
+	var input entities.SetConversationWorkspaceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	conversation, err := r.conversationService.SetActiveWorkspace(c.Request.Context(), userID, conversationID, input.WorkspaceID)
+	if err != nil {
+		c.JSON(conversationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    conversation,
+		Message: "Conversation workspace switched successfully",
+	})
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type dashboardRoutes struct {
+	dashboardService services.DashboardService
+	auth             *middleware.AuthMiddleware
+}
+
+func newDashboardRoutes(handler *gin.RouterGroup, dashboardService services.DashboardService, auth *middleware.AuthMiddleware) {
+	r := &dashboardRoutes{
+		dashboardService: dashboardService,
+		auth:             auth,
+	}
+
+	// All dashboard endpoints require authentication
+	dashboard := handler.Group("/dashboard").Use(auth.AuthRequired())
+	{
+		dashboard.GET("/consolidated", r.GetConsolidatedDashboard)
+	}
+}
+
+// @Summary Get consolidated dashboard
+// @Description Get income, expenses, budget status and account balances across all workspaces of the authenticated user, converted into the user currency, with a breakdown per workspace
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the first day of the end date's month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param exclude_workspace_ids query string false "Comma separated workspace IDs to leave out"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /dashboard/consolidated [get]
+func (r *dashboardRoutes) GetConsolidatedDashboard(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	startDate, endDate, ok := parseReportPeriod(c)
+	if !ok {
+		return
+	}
+
+	var excludedWorkspaceIDs []uuid.UUID
+	if excludeStr := c.Query("exclude_workspace_ids"); excludeStr != "" {
+		for _, idStr := range strings.Split(excludeStr, ",") {
+			workspaceID, err := uuid.Parse(strings.TrimSpace(idStr))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+					Success: false,
+					Error:   "invalid exclude_workspace_ids",
+				})
+				return
+			}
+			excludedWorkspaceIDs = append(excludedWorkspaceIDs, workspaceID)
+		}
+	}
+
+	dashboard, err := r.dashboardService.GetConsolidatedDashboard(c.Request.Context(), userID, excludedWorkspaceIDs, startDate, endDate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "start date must be before end date" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    dashboard,
+	})
+}
@@ -0,0 +1,302 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type envelopeRoutes struct {
+	envelopeService services.EnvelopeService
+	auth            *middleware.AuthMiddleware
+}
+
+func newEnvelopeRoutes(handler *gin.RouterGroup, envelopeService services.EnvelopeService, auth *middleware.AuthMiddleware) {
+	r := &envelopeRoutes{
+		envelopeService: envelopeService,
+		auth:            auth,
+	}
+
+	// All envelope endpoints require authentication
+	envelopes := handler.Group("/envelopes").Use(auth.AuthRequired())
+	{
+		envelopes.GET("", r.GetMonthSummary)
+		envelopes.PUT("/allocations", r.AllocateEnvelope)
+		envelopes.GET("/transfers", r.GetTransferHistory)
+		envelopes.POST("/transfers", r.TransferBetweenEnvelopes)
+		envelopes.POST("/cover", r.CoverOverspend)
+	}
+}
+
+// envelopeErrorStatus maps envelope service errors to HTTP status codes
+func envelopeErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found":
+		return http.StatusNotFound
+	case "access denied to workspace":
+		return http.StatusForbidden
+	case "workspace is archived":
+		return http.StatusConflict
+	case "envelope budgeting is not enabled for this workspace",
+		"invalid month format, expected YYYY-MM",
+		"user category ID is required",
+		"amount cannot be negative",
+		"amount must be greater than 0",
+		"amount exceeds available to assign",
+		"source and destination envelopes are required",
+		"source and destination envelopes must be different",
+		"envelope is not overspent",
+		"amount exceeds overspent amount",
+		"insufficient envelope balance":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get envelope month summary
+// @Description Get the available to assign pool and every envelope of a workspace for a month
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param month query string false "Month in YYYY-MM format, defaults to the current month"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /envelopes [get]
+func (r *envelopeRoutes) GetMonthSummary(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	summary, err := r.envelopeService.GetMonthSummary(c.Request.Context(), userID, workspaceID, c.Query("month"))
+	if err != nil {
+		c.JSON(envelopeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// @Summary Allocate to an envelope
+// @Description Set the amount assigned to an envelope for a month from the available to assign pool
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param input body entities.AllocateEnvelopeRequest true "Allocation details"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /envelopes/allocations [put]
+func (r *envelopeRoutes) AllocateEnvelope(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var input entities.AllocateEnvelopeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	allocation, err := r.envelopeService.AllocateEnvelope(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(envelopeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    allocation,
+	})
+}
+
+// @Summary Get envelope transfer history
+// @Description Get the history of money moved between envelopes
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /envelopes/transfers [get]
+func (r *envelopeRoutes) GetTransferHistory(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	transfers, err := r.envelopeService.GetTransferHistory(c.Request.Context(), userID, workspaceID, limit, offset)
+	if err != nil {
+		c.JSON(envelopeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transfers,
+	})
+}
+
+// @Summary Transfer between envelopes
+// @Description Move available money from one envelope to another
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param input body entities.EnvelopeTransferRequest true "Transfer details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /envelopes/transfers [post]
+func (r *envelopeRoutes) TransferBetweenEnvelopes(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var input entities.EnvelopeTransferRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transfer, err := r.envelopeService.TransferBetweenEnvelopes(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(envelopeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    transfer,
+	})
+}
+
+// @Summary Cover an overspent envelope
+// @Description Move money from another envelope into an overspent envelope. Without an amount the whole overspent amount is covered.
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param input body entities.EnvelopeTransferRequest true "Cover details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /envelopes/cover [post]
+func (r *envelopeRoutes) CoverOverspend(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	var input entities.EnvelopeTransferRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transfer, err := r.envelopeService.CoverOverspend(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(envelopeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    transfer,
+	})
+}
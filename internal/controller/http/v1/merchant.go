@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type merchantRoutes struct {
+	merchantService services.MerchantService
+	auth            *middleware.AuthMiddleware
+}
+
+func newMerchantRoutes(handler *gin.RouterGroup, merchantService services.MerchantService, auth *middleware.AuthMiddleware) {
+	r := &merchantRoutes{
+		merchantService: merchantService,
+		auth:            auth,
+	}
+
+	// All merchant endpoints require authentication
+	merchants := handler.Group("/merchants").Use(auth.AuthRequired())
+	{
+		merchants.GET("", r.GetMerchantDirectory)
+		merchants.GET("/match", r.MatchMerchant)
+		merchants.GET("/aliases", r.GetPersonalAliases)
+		merchants.POST("/aliases", r.CreatePersonalAlias)
+		merchants.DELETE("/aliases/:id", r.DeletePersonalAlias)
+	}
+}
+
+// @Summary Get merchant directory
+// @Description Get the active merchants with their canonical names, default category and logo
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Filter by name"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants [get]
+func (r *merchantRoutes) GetMerchantDirectory(c *gin.Context) {
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	merchants, err := r.merchantService.GetMerchantDirectory(c.Request.Context(), c.Query("search"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    merchants,
+	})
+}
+
+// @Summary Match a merchant
+// @Description Normalize free text merchant names the same way transactions are, e.g. for imports or AI extraction. Data is null when nothing matches.
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name query string true "Merchant name as written on the receipt or statement"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/match [get]
+func (r *merchantRoutes) MatchMerchant(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	name := c.Query("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "name is required",
+		})
+		return
+	}
+
+	match, err := r.merchantService.NormalizeMerchant(c.Request.Context(), userID, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    match,
+	})
+}
+
+// @Summary Get personal merchant aliases
+// @Description Get the merchant aliases of the authenticated user
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/aliases [get]
+func (r *merchantRoutes) GetPersonalAliases(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	aliases, err := r.merchantService.GetPersonalAliases(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    aliases,
+	})
+}
+
+// @Summary Create a personal merchant alias
+// @Description Add a matching rule that only applies to the authenticated user's transactions and wins over global aliases. match_type is 1 (exact), 2 (prefix, default) or 3 (contains).
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.CreateMerchantAliasRequest true "Alias details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/aliases [post]
+func (r *merchantRoutes) CreatePersonalAlias(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.CreateMerchantAliasRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	alias, err := r.merchantService.CreatePersonalAlias(c.Request.Context(), userID, &input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "merchant not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "merchant alias already exists" {
+			status = http.StatusConflict
+		} else if err.Error() == "merchant ID is required" || err.Error() == "pattern is required" || err.Error() == "invalid match type" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    alias,
+	})
+}
+
+// @Summary Delete a personal merchant alias
+// @Description Delete a merchant alias of the authenticated user
+// @Tags merchants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Merchant Alias ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /merchants/aliases/{id} [delete]
+func (r *merchantRoutes) DeletePersonalAlias(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	merchantAliasID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid merchant alias ID format",
+		})
+		return
+	}
+
+	err = r.merchantService.DeletePersonalAlias(c.Request.Context(), userID, merchantAliasID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "merchant alias not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to merchant alias" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Merchant alias deleted successfully",
+	})
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type netWorthRoutes struct {
+	netWorthService services.NetWorthService
+	auth            *middleware.AuthMiddleware
+}
+
+func newNetWorthRoutes(handler *gin.RouterGroup, netWorthService services.NetWorthService, auth *middleware.AuthMiddleware) {
+	r := &netWorthRoutes{
+		netWorthService: netWorthService,
+		auth:            auth,
+	}
+
+	// All net worth endpoints require authentication
+	netWorth := handler.Group("/net-worth").Use(auth.AuthRequired())
+	{
+		netWorth.GET("", r.GetNetWorth)
+	}
+}
+
+// @Summary Get net worth
+// @Description Get the net worth time series from daily account balance snapshots and the current assets and liabilities per account, in the user currency. Credit cards and loans are liabilities.
+// @Tags net-worth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param interval query string false "day, week or month (default: day)"
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to 30 days, 12 weeks or 12 months before the end date"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /net-worth [get]
+func (r *netWorthRoutes) GetNetWorth(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	startDate, endDate, ok := parseReportPeriod(c)
+	if !ok {
+		return
+	}
+
+	netWorth, err := r.netWorthService.GetNetWorth(c.Request.Context(), userID, c.Query("interval"), startDate, endDate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "user not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "invalid interval" || err.Error() == "start date must be before end date" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    netWorth,
+	})
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type notificationRoutes struct {
+	notificationService services.NotificationService
+	auth                *middleware.AuthMiddleware
+}
+
+func newNotificationRoutes(handler *gin.RouterGroup, notificationService services.NotificationService, auth *middleware.AuthMiddleware) {
+	r := &notificationRoutes{
+		notificationService: notificationService,
+		auth:                auth,
+	}
+
+	// All notification endpoints require authentication
+	notifications := handler.Group("/notifications").Use(auth.AuthRequired())
+	{
+		notifications.GET("", r.GetNotifications)
+		notifications.PUT("/read", r.MarkAllAsRead)
+		notifications.PUT("/:id/read", r.MarkAsRead)
+	}
+}
+
+// @Summary Get notifications
+// @Description Get the notifications of the authenticated user, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /notifications [get]
+func (r *notificationRoutes) GetNotifications(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, err := r.notificationService.GetNotifications(c.Request.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    notifications,
+	})
+}
+
+// @Summary Mark a notification as read
+// @Description Mark a notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /notifications/{id}/read [put]
+func (r *notificationRoutes) MarkAsRead(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid notification ID format",
+		})
+		return
+	}
+
+	err = r.notificationService.MarkAsRead(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "notification not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to notification" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Notification marked as read",
+	})
+}
+
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /notifications/read [put]
+func (r *notificationRoutes) MarkAllAsRead(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	count, err := r.notificationService.MarkAllAsRead(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    gin.H{"marked_count": count},
+		Message: "Notifications marked as read",
+	})
+}
@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type recurringChargeRoutes struct {
+	recurringChargeService services.RecurringChargeService
+	auth                   *middleware.AuthMiddleware
+}
+
+func newRecurringChargeRoutes(handler *gin.RouterGroup, recurringChargeService services.RecurringChargeService, auth *middleware.AuthMiddleware) {
+	r := &recurringChargeRoutes{
+		recurringChargeService: recurringChargeService,
+		auth:                   auth,
+	}
+
+	// All recurring charge endpoints require authentication
+	recurringCharges := handler.Group("/recurring-charges").Use(auth.AuthRequired())
+	{
+		recurringCharges.GET("", r.GetRecurringCharges)
+		recurringCharges.POST("/track", r.TrackRecurringCharge)
+	}
+}
+
+// recurringChargeErrorStatus maps recurring charge service errors to HTTP status codes
+func recurringChargeErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "recurring charge not found":
+		return http.StatusNotFound
+	case "access denied to workspace":
+		return http.StatusForbidden
+	case "workspace is archived":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get recurring charges
+// @Description Detect subscriptions and bills from the expense history of a workspace: the same merchant charging a similar amount every week, month or year. Includes the total monthly cost, price increases and late or missed charges.
+// @Tags recurring-charges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /recurring-charges [get]
+func (r *recurringChargeRoutes) GetRecurringCharges(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	summary, err := r.recurringChargeService.GetRecurringCharges(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(recurringChargeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// @Summary Track a recurring charge
+// @Description Record a detected charge as a recurring transaction: its latest charge is flagged as recurring with the detected interval
+// @Tags recurring-charges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.TrackRecurringChargeRequest true "Workspace and merchant key of the detected charge"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /recurring-charges/track [post]
+func (r *recurringChargeRoutes) TrackRecurringCharge(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.TrackRecurringChargeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defaultToActiveWorkspace(c, &input.WorkspaceID)
+
+	charge, err := r.recurringChargeService.TrackRecurringCharge(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(recurringChargeErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    charge,
+		Message: "Recurring charge tracked successfully",
+	})
+}
@@ -0,0 +1,603 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type reimbursementRoutes struct {
+	reimbursementService services.ReimbursementService
+}
+
+func newReimbursementRoutes(handler *gin.RouterGroup, reimbursementService services.ReimbursementService, auth *middleware.AuthMiddleware) {
+	r := &reimbursementRoutes{reimbursementService: reimbursementService}
+
+	// All reimbursement endpoints require authentication
+	reimbursements := handler.Group("/reimbursements").Use(auth.AuthRequired())
+	{
+		reimbursements.GET("", r.GetClaims)
+		reimbursements.POST("", r.CreateClaim)
+		reimbursements.GET("/balances", r.GetBalances)
+		reimbursements.GET("/:id", r.GetClaimByID)
+		reimbursements.PUT("/:id", r.UpdateClaim)
+		reimbursements.DELETE("/:id", r.DeleteClaim)
+		reimbursements.GET("/:id/pdf", r.ExportClaimPDF)
+		reimbursements.POST("/:id/submit", r.SubmitClaim)
+		reimbursements.POST("/:id/approve", auth.VerifiedRequired(), r.ApproveClaim)
+		reimbursements.POST("/:id/reject", r.RejectClaim)
+		reimbursements.POST("/:id/pay", auth.VerifiedRequired(), r.PayClaim)
+	}
+}
+
+// reimbursementErrorStatus maps reimbursement service errors to HTTP status codes
+func reimbursementErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "claim not found", "transaction not found", "account not found":
+		return http.StatusNotFound
+	case "access denied to workspace",
+		"access denied to claim",
+		"access denied to account",
+		"only the claimant can change a claim",
+		"cannot review your own claim":
+		return http.StatusForbidden
+	case "workspace is archived",
+		"claim can no longer be changed",
+		"claim is not waiting for review",
+		"claim is not approved",
+		"claim status has changed",
+		"expense is already claimed":
+		return http.StatusConflict
+	case "reimbursement claims are only available in business workspaces",
+		"title is required",
+		"payout account must be your own account",
+		"payment account must be different from the payout account",
+		"too many expenses in claim",
+		"duplicate expense in claim",
+		"only expenses can be claimed",
+		"only your own expenses can be claimed",
+		"only approved expenses can be claimed",
+		"claim has no expenses",
+		"no exchange rate recorded for the currency of an expense",
+		"rejection note is required":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseClaimID reads the claim ID from the path
+func parseClaimID(c *gin.Context) (uuid.UUID, bool) {
+	claimID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid claim ID format",
+		})
+		return uuid.Nil, false
+	}
+	return claimID, true
+}
+
+// @Summary List reimbursement claims
+// @Description List the reimbursement claims of a workspace, newest first. Approvers see every claim, other members only their own.
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param claimant_id query string false "Filter by claimant"
+// @Param status query int false "Filter by status (1=draft, 2=submitted, 3=approved, 4=rejected, 5=paid)"
+// @Param limit query int false "Limit for pagination (default: 20)"
+// @Param offset query int false "Offset for pagination (default: 0)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements [get]
+func (r *reimbursementRoutes) GetClaims(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	params := &entities.ReimbursementClaimListParams{WorkspaceID: workspaceID}
+	if claimantIDStr := c.Query("claimant_id"); claimantIDStr != "" {
+		claimantID, err := uuid.Parse(claimantIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid claimant_id format",
+			})
+			return
+		}
+		params.ClaimantID = &claimantID
+	}
+	if statusStr := c.Query("status"); statusStr != "" {
+		if val, err := strconv.Atoi(statusStr); err == nil {
+			params.Status = &val
+		}
+	}
+
+	// Parse pagination parameters
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	claims, totalCount, err := r.reimbursementService.GetClaims(c.Request.Context(), userID, params, limit, offset)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"claims": claims,
+			"total":  totalCount,
+			"limit":  limit,
+			"offset": offset,
+		},
+	})
+}
+
+// @Summary Create a reimbursement claim
+// @Description Draft a claim of the user's own expenses in a business workspace, or submit it right away with submit
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.CreateReimbursementClaimRequest true "Claim details and expenses"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements [post]
+func (r *reimbursementRoutes) CreateClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.CreateReimbursementClaimRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defaultToActiveWorkspace(c, &input.WorkspaceID)
+
+	claim, err := r.reimbursementService.CreateClaim(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim created successfully",
+	})
+}
+
+// @Summary Get outstanding reimbursements
+// @Description Get what the workspace owes every claimant for submitted and approved claims. Members who are not approvers only get their own balance.
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/balances [get]
+func (r *reimbursementRoutes) GetBalances(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	balances, err := r.reimbursementService.GetBalances(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    balances,
+	})
+}
+
+// @Summary Get a reimbursement claim
+// @Description Get a claim with its expenses, for its claimant or an approver
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id} [get]
+func (r *reimbursementRoutes) GetClaimByID(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	claim, err := r.reimbursementService.GetClaimByID(c.Request.Context(), userID, claimID)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+	})
+}
+
+// @Summary Update a reimbursement claim
+// @Description Replace the details and expenses of a draft or rejected claim, a rejected claim goes back to draft
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param input body entities.UpdateReimbursementClaimRequest true "Claim details and expenses"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id} [put]
+func (r *reimbursementRoutes) UpdateClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.UpdateReimbursementClaimRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	claim, err := r.reimbursementService.UpdateClaim(c.Request.Context(), userID, claimID, &input)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim updated successfully",
+	})
+}
+
+// @Summary Delete a reimbursement claim
+// @Description Delete a draft or rejected claim, its expenses can then be claimed again
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id} [delete]
+func (r *reimbursementRoutes) DeleteClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.reimbursementService.DeleteClaim(c.Request.Context(), userID, claimID); err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Claim deleted successfully",
+	})
+}
+
+// @Summary Export a reimbursement claim
+// @Description Download the summary of a claim with its expenses and receipts as a PDF for finance
+// @Tags reimbursements
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {file} file
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id}/pdf [get]
+func (r *reimbursementRoutes) ExportClaimPDF(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	document, err := r.reimbursementService.ExportClaimPDF(c.Request.Context(), userID, claimID)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("reimbursement-%s.pdf", claimID.String()[:8])
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// @Summary Submit a reimbursement claim
+// @Description Submit a draft or rejected claim for review, the approvers of the workspace are notified
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id}/submit [post]
+func (r *reimbursementRoutes) SubmitClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	claim, err := r.reimbursementService.SubmitClaim(c.Request.Context(), userID, claimID)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim submitted for review",
+	})
+}
+
+// @Summary Approve a reimbursement claim
+// @Description Approve a submitted claim, it then waits for payment. Approvers cannot review their own claims unless they own the workspace.
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id}/approve [post]
+func (r *reimbursementRoutes) ApproveClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	claim, err := r.reimbursementService.ApproveClaim(c.Request.Context(), userID, claimID)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim approved",
+	})
+}
+
+// @Summary Reject a reimbursement claim
+// @Description Reject a submitted claim with a note, its claimant may edit and submit it again
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param input body entities.RejectReimbursementClaimRequest true "Rejection note"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id}/reject [post]
+func (r *reimbursementRoutes) RejectClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.RejectReimbursementClaimRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	claim, err := r.reimbursementService.RejectClaim(c.Request.Context(), userID, claimID, &input)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim rejected",
+	})
+}
+
+// @Summary Pay a reimbursement claim
+// @Description Pay an approved claim by a transfer from a company account of the approver to the claimant's payout account. Approved expenses of the claim are marked as reimbursed.
+// @Tags reimbursements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Claim ID"
+// @Param input body entities.PayReimbursementClaimRequest true "Company account and payment date"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reimbursements/{id}/pay [post]
+func (r *reimbursementRoutes) PayClaim(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	claimID, ok := parseClaimID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.PayReimbursementClaimRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	claim, err := r.reimbursementService.PayClaim(c.Request.Context(), userID, claimID, &input)
+	if err != nil {
+		c.JSON(reimbursementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Claim paid",
+	})
+}
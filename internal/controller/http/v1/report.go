@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type reportRoutes struct {
+	reportService services.ReportService
+	auth          *middleware.AuthMiddleware
+}
+
+func newReportRoutes(handler *gin.RouterGroup, reportService services.ReportService, auth *middleware.AuthMiddleware) {
+	r := &reportRoutes{
+		reportService: reportService,
+		auth:          auth,
+	}
+
+	// All report endpoints require authentication
+	reports := handler.Group("/reports").Use(auth.AuthRequired())
+	{
+		reports.GET("/cashflow", r.GetCashFlowStatement)
+	}
+}
+
+// parseReportPeriod parses the optional start_date and end_date query parameters
+// If parsing fails, it automatically sends an error response and returns false
+func parseReportPeriod(c *gin.Context) (*time.Time, *time.Time, bool) {
+	var startDate, endDate *time.Time
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid start_date format, expected YYYY-MM-DD",
+			})
+			return nil, nil, false
+		}
+		startDate = &parsed
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		parsed, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid end_date format, expected YYYY-MM-DD",
+			})
+			return nil, nil, false
+		}
+		endDate = &parsed
+	}
+
+	return startDate, endDate, true
+}
+
+// @Summary Get cash flow statement
+// @Description Get income by category, expenses by category, net cash flow, savings rate and account balances of a workspace over a period. Transfers between own accounts are excluded.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param start_date query string false "Start date (YYYY-MM-DD), defaults to the first day of the end date's month"
+// @Param end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param format query string false "json, pdf or text (default: json)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /reports/cashflow [get]
+func (r *reportRoutes) GetCashFlowStatement(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	startDate, endDate, ok := parseReportPeriod(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", entities.ReportFormatJSON)
+	if format != entities.ReportFormatJSON && format != entities.ReportFormatPDF && format != entities.ReportFormatText {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid format",
+		})
+		return
+	}
+
+	statement, err := r.reportService.GetCashFlowStatement(c.Request.Context(), userID, workspaceID, startDate, endDate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "workspace not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to workspace" {
+			status = http.StatusForbidden
+		} else if err.Error() == "start date must be before end date" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	switch format {
+	case entities.ReportFormatPDF:
+		filename := fmt.Sprintf("cashflow-%s-%s.pdf", statement.StartDate.Format("20060102"), statement.EndDate.Format("20060102"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "application/pdf", services.RenderCashFlowPDF(statement))
+	case entities.ReportFormatText:
+		c.JSON(http.StatusOK, &entities.ApiResponse{
+			Success: true,
+			Data: map[string]interface{}{
+				"text": services.RenderCashFlowText(statement),
+			},
+		})
+	default:
+		c.JSON(http.StatusOK, &entities.ApiResponse{
+			Success: true,
+			Data:    statement,
+		})
+	}
+}
@@ -12,21 +12,38 @@ import (
 type Services struct {
 	Cfg *config.Config
 
-	UserService             services.UserService
-	WorkspaceService        services.WorkspaceService
-	AccountService          services.AccountService
-	CategoryService         services.CategoryService
-	BankService             services.BankService
-	CurrencyService         services.CurrencyService
-	SubscriptionPlanService services.SubscriptionPlanService
-	BudgetService           services.BudgetService
-	TransactionService      services.TransactionService
-	ConversationService     services.ConversationService
-	MessageService          services.MessageService
-	TaxonomyService         services.TaxonomyService
-	UserTagsService         services.UserTagsService
-	TransactionTagsService  services.TransactionTagsService
-	VerificationCodeService services.VerificationCodeService
+	UserService                services.UserService
+	WorkspaceService           services.WorkspaceService
+	WorkspaceTemplateService   services.WorkspaceTemplateService
+	AccountService             services.AccountService
+	CategoryService            services.CategoryService
+	BankService                services.BankService
+	CurrencyService            services.CurrencyService
+	SubscriptionPlanService    services.SubscriptionPlanService
+	BudgetService              services.BudgetService
+	TransactionService         services.TransactionService
+	ConversationService        services.ConversationService
+	MessageService             services.MessageService
+	TaxonomyService            services.TaxonomyService
+	UserTagsService            services.UserTagsService
+	TransactionTagsService     services.TransactionTagsService
+	VerificationCodeService    services.VerificationCodeService
+	EnvelopeService            services.EnvelopeService
+	AnalyticsService           services.AnalyticsService
+	ReportService              services.ReportService
+	NetWorthService            services.NetWorthService
+	DashboardService           services.DashboardService
+	MerchantService            services.MerchantService
+	NotificationService        services.NotificationService
+	SpendingAnomalyService     services.SpendingAnomalyService
+	RecurringChargeService     services.RecurringChargeService
+	WorkspaceInvitationService services.WorkspaceInvitationService
+	SettlementService          services.SettlementService
+	ActivityService            services.ActivityService
+	TransactionCommentService  services.TransactionCommentService
+	TransactionApprovalService services.TransactionApprovalService
+	ReimbursementService       services.ReimbursementService
+	SessionService             services.SessionService
 	// ConversationService services.ConversationService
 	// MessageService      services.MessageService
 	// OpenAIService       services.OpenAIService
@@ -34,6 +51,45 @@ type Services struct {
 	// WhatsAppService     services.WhatsAppService
 
 	AuthMiddleware *middleware.AuthMiddleware
+	RateLimiter    *middleware.RateLimiter
+	RateLimits     middleware.RateLimits
+}
+
+// authRateLimitedPaths guess or spend credentials and are limited per IP address
+var authRateLimitedPaths = []string{
+	"/v1/auth/login",
+	"/v1/auth/register",
+	"/v1/auth/refresh",
+	"/v1/auth/forgot-password",
+	"/v1/auth/verify-reset-code",
+	"/v1/auth/reset-password",
+	"/v1/auth/change-password",
+	"/v1/auth/verify-phone",
+	"/v1/auth/resend-verification-code",
+	"/v1/auth/verify-email",
+	"/v1/auth/resend-verification-email",
+	"/v1/verification-codes/create",
+	"/v1/verification-codes/verify",
+	"/v1/verification-codes/resend",
+}
+
+// otpRateLimitedPaths send or check one-time codes and are limited per phone number
+var otpRateLimitedPaths = []string{
+	"/v1/auth/forgot-password",
+	"/v1/auth/verify-reset-code",
+	"/v1/auth/verify-phone",
+	"/v1/auth/resend-verification-code",
+	"/v1/verification-codes/create",
+	"/v1/verification-codes/verify",
+	"/v1/verification-codes/resend",
+	"/v1/users/phone",
+	"/v1/users/phone/verify",
+}
+
+// passwordRateLimitedPaths check the password of a signed in user and are also limited per user,
+// so a stolen access token cannot guess it from many IP addresses
+var passwordRateLimitedPaths = []string{
+	"/v1/auth/change-password",
 }
 
 func (s Services) Initialized() error {
@@ -48,22 +104,44 @@ func NewRouter(handler *gin.Engine, s Services) {
 	}
 
 	// API Routers
-	h := handler.Group("v1")
+	h := handler.Group("v1",
+		s.RateLimiter.Limit("api", s.RateLimits.API, s.RateLimiter.KeyByClient),
+		s.RateLimiter.Limit("auth", s.RateLimits.Auth, middleware.KeyByIP, authRateLimitedPaths...),
+		s.RateLimiter.Limit("otp", s.RateLimits.OTP, middleware.KeyByPhoneNumber, otpRateLimitedPaths...),
+		s.RateLimiter.Limit("password", s.RateLimits.Auth, s.RateLimiter.KeyByClient, passwordRateLimitedPaths...),
+	)
 	{
-		newUserRoutes(h, s.UserService, s.AuthMiddleware)                         // User management routes
-		newWorkspaceRoutes(h, s.WorkspaceService, s.AuthMiddleware)               // Workspace management routes
-		newAccountRoutes(h, s.AccountService, s.AuthMiddleware)                   // Account management routes
-		newCategoryRoutes(h, s.CategoryService, s.AuthMiddleware)                 // Category management routes
-		newBankRoutes(h, s.BankService, s.AuthMiddleware)                         // Bank management routes
-		newCurrencyRoutes(h, s.CurrencyService, s.AuthMiddleware)                 // Currency management routes
-		newSubscriptionPlanRoutes(h, s.SubscriptionPlanService, s.AuthMiddleware) // Subscription plan management routes
-		newBudgetRoutes(h, s.BudgetService, s.AuthMiddleware)                     // Budget management routes
-		newTransactionRoutes(h, s.TransactionService, s.AuthMiddleware)           // Transaction management routes
-		newConversationRoutes(h, s.ConversationService, s.AuthMiddleware)         // Conversation management routes
-		newMessageRoutes(h, s.MessageService, s.AuthMiddleware)                   // Message management routes
-		newTaxonomyRoutes(h, s.TaxonomyService, s.AuthMiddleware)                 // Taxonomy management routes
-		newUserTagsRoutes(h, s.UserTagsService, s.AuthMiddleware)                 // User tags management routes
-		newTransactionTagsRoutes(h, s.TransactionTagsService, s.AuthMiddleware)   // Transaction tags management routes
-		newVerificationCodeRoutes(h, s.VerificationCodeService, s.AuthMiddleware) // Verification code management routes
+		newUserRoutes(h, s.UserService, s.AuthMiddleware)                               // User management routes
+		newWorkspaceRoutes(h, s.WorkspaceService, s.AuthMiddleware)                     // Workspace management routes
+		newWorkspaceTemplateRoutes(h, s.WorkspaceTemplateService, s.AuthMiddleware)     // Workspace clone and template routes
+		newAccountRoutes(h, s.AccountService, s.AuthMiddleware)                         // Account management routes
+		newCategoryRoutes(h, s.CategoryService, s.AuthMiddleware)                       // Category management routes
+		newBankRoutes(h, s.BankService, s.AuthMiddleware)                               // Bank management routes
+		newCurrencyRoutes(h, s.CurrencyService, s.AuthMiddleware)                       // Currency management routes
+		newSubscriptionPlanRoutes(h, s.SubscriptionPlanService, s.AuthMiddleware)       // Subscription plan management routes
+		newBudgetRoutes(h, s.BudgetService, s.AuthMiddleware)                           // Budget management routes
+		newTransactionRoutes(h, s.TransactionService, s.AuthMiddleware)                 // Transaction management routes
+		newConversationRoutes(h, s.ConversationService, s.AuthMiddleware)               // Conversation management routes
+		newMessageRoutes(h, s.MessageService, s.AuthMiddleware)                         // Message management routes
+		newTaxonomyRoutes(h, s.TaxonomyService, s.AuthMiddleware)                       // Taxonomy management routes
+		newUserTagsRoutes(h, s.UserTagsService, s.AuthMiddleware)                       // User tags management routes
+		newTransactionTagsRoutes(h, s.TransactionTagsService, s.AuthMiddleware)         // Transaction tags management routes
+		newVerificationCodeRoutes(h, s.VerificationCodeService, s.AuthMiddleware)       // Verification code management routes
+		newEnvelopeRoutes(h, s.EnvelopeService, s.AuthMiddleware)                       // Envelope budgeting routes
+		newAnalyticsRoutes(h, s.AnalyticsService, s.AuthMiddleware)                     // Spending analytics routes
+		newReportRoutes(h, s.ReportService, s.AuthMiddleware)                           // Report routes
+		newNetWorthRoutes(h, s.NetWorthService, s.AuthMiddleware)                       // Net worth routes
+		newDashboardRoutes(h, s.DashboardService, s.AuthMiddleware)                     // Consolidated dashboard routes
+		newMerchantRoutes(h, s.MerchantService, s.AuthMiddleware)                       // Merchant directory routes
+		newNotificationRoutes(h, s.NotificationService, s.AuthMiddleware)               // Notification routes
+		newSpendingAnomalyRoutes(h, s.SpendingAnomalyService, s.AuthMiddleware)         // Spending anomaly routes
+		newRecurringChargeRoutes(h, s.RecurringChargeService, s.AuthMiddleware)         // Recurring charge routes
+		newWorkspaceInvitationRoutes(h, s.WorkspaceInvitationService, s.AuthMiddleware) // Workspace invitation routes
+		newSettlementRoutes(h, s.SettlementService, s.AuthMiddleware)                   // Settlement and split routes
+		newActivityRoutes(h, s.ActivityService, s.AuthMiddleware)                       // Workspace activity feed routes
+		newTransactionCommentRoutes(h, s.TransactionCommentService, s.AuthMiddleware)   // Transaction comment routes
+		newTransactionApprovalRoutes(h, s.TransactionApprovalService, s.AuthMiddleware) // Expense approval routes
+		newReimbursementRoutes(h, s.ReimbursementService, s.AuthMiddleware)             // Reimbursement claim routes
+		newSessionRoutes(h, s.SessionService, s.AuthMiddleware)                         // Refresh token and session routes
 	}
 }
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type sessionRoutes struct {
+	sessionService services.SessionService
+}
+
+func newSessionRoutes(handler *gin.RouterGroup, sessionService services.SessionService, auth *middleware.AuthMiddleware) {
+	r := &sessionRoutes{sessionService: sessionService}
+
+	// The refresh token authenticates the refresh, the other endpoints need an access token
+	authRoutes := handler.Group("/auth")
+	{
+		authRoutes.POST("/refresh", r.Refresh)
+		authRoutes.POST("/logout", auth.AuthRequired(), r.Logout)
+		authRoutes.POST("/logout-all", auth.AuthRequired(), r.LogoutAll)
+		authRoutes.GET("/sessions", auth.AuthRequired(), r.GetSessions)
+		authRoutes.DELETE("/sessions/:id", auth.AuthRequired(), r.RevokeSession)
+	}
+}
+
+// sessionErrorStatus maps session service errors to HTTP status codes
+func sessionErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid refresh token", "refresh token has expired", "refresh token reuse detected", "session has been revoked":
+		return http.StatusUnauthorized
+	case "user account is inactive":
+		return http.StatusForbidden
+	case "session not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and the next refresh token. Each refresh token works once, reusing one revokes its session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body entities.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/refresh [post]
+func (r *sessionRoutes) Refresh(c *gin.Context) {
+	var input entities.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tokens, err := r.sessionService.Refresh(withSessionClient(c, ""), &input)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// @Summary Logout
+// @Description End the session of the access token, its refresh token stops working. The access token itself stays valid until it expires.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/logout [post]
+func (r *sessionRoutes) Logout(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	// AuthRequired only lets access tokens of a session through
+	sessionID, _ := GetSessionID(c)
+	if err := r.sessionService.Logout(c.Request.Context(), userID, sessionID); err != nil {
+		c.JSON(sessionErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Logged out successfully",
+	})
+}
+
+// @Summary Logout everywhere
+// @Description End every session of the user, including the current one
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/logout-all [post]
+func (r *sessionRoutes) LogoutAll(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := r.sessionService.LogoutAll(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    map[string]interface{}{"revoked_sessions": revoked},
+		Message: "Logged out of all sessions",
+	})
+}
+
+// @Summary List sessions
+// @Description List the open sessions of the user with their device, IP address and last use, the session of the access token is marked current
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/sessions [get]
+func (r *sessionRoutes) GetSessions(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	currentSessionID, _ := GetSessionID(c)
+	sessions, err := r.sessionService.GetSessions(c.Request.Context(), userID, currentSessionID)
+	if err != nil {
+		c.JSON(sessionErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    sessions,
+	})
+}
+
+// @Summary Revoke a session
+// @Description End one of the user's sessions, e.g. on a lost device
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/sessions/{id} [delete]
+func (r *sessionRoutes) RevokeSession(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid session ID format",
+		})
+		return
+	}
+
+	if err := r.sessionService.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		c.JSON(sessionErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Session revoked successfully",
+	})
+}
@@ -0,0 +1,481 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type settlementRoutes struct {
+	settlementService services.SettlementService
+	auth              *middleware.AuthMiddleware
+}
+
+func newSettlementRoutes(handler *gin.RouterGroup, settlementService services.SettlementService, auth *middleware.AuthMiddleware) {
+	r := &settlementRoutes{
+		settlementService: settlementService,
+		auth:              auth,
+	}
+
+	// All settlement endpoints require authentication
+	settlements := handler.Group("/settlements").Use(auth.AuthRequired())
+	{
+		settlements.GET("", r.ListSettlements)
+		settlements.POST("", auth.VerifiedRequired(), r.CreateSettlement)
+		settlements.GET("/balances", r.GetSettlementSummary)
+		settlements.GET("/:id", r.GetSettlementByID)
+		settlements.POST("/:id/confirm", auth.VerifiedRequired(), r.ConfirmSettlement)
+		settlements.POST("/:id/cancel", r.CancelSettlement)
+	}
+
+	// Splits of an expense between members
+	transactions := handler.Group("/transactions").Use(auth.AuthRequired())
+	{
+		transactions.GET("/:id/splits", r.GetTransactionSplits)
+		transactions.PUT("/:id/splits", r.SetTransactionSplits)
+	}
+}
+
+// settlementErrorStatus maps settlement service errors to HTTP status codes
+func settlementErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "transaction not found", "settlement not found":
+		return http.StatusNotFound
+	case "access denied to workspace",
+		"only the payer or the payee can record a settlement",
+		"only the payer or the payee can change a settlement":
+		return http.StatusForbidden
+	case "settlement is no longer pending", "workspace is archived":
+		return http.StatusConflict
+	case "only expenses can be split",
+		"the payer of the transaction is unknown",
+		"transaction does not belong to a workspace",
+		"split amounts must be greater than zero",
+		"each member can only appear once in a split",
+		"split amounts must add up to the transaction amount",
+		"split members must belong to the workspace",
+		"grace amount cannot be negative",
+		"amount must be greater than zero",
+		"payer and payee must be different members",
+		"payer and payee must belong to the workspace":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get who owes whom
+// @Description Get the split balances of the workspace members and the fewest transfers that settle them. Balances and transfers under the grace amount are ignored.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param grace_amount query number false "Balances under this amount are considered settled (default 10000)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements/balances [get]
+func (r *settlementRoutes) GetSettlementSummary(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	graceAmount := float64(entities.SettlementGraceAmount)
+	if graceAmountStr := c.Query("grace_amount"); graceAmountStr != "" {
+		val, err := strconv.ParseFloat(graceAmountStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid grace_amount",
+			})
+			return
+		}
+		graceAmount = val
+	}
+
+	summary, err := r.settlementService.GetSettlementSummary(c.Request.Context(), userID, workspaceID, graceAmount)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// @Summary Get settlement history
+// @Description Get the settlements of a workspace, newest first. Cancelled settlements are kept for the history.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
+// @Param user_id query string false "Only settlements paid or received by this member"
+// @Param status query int false "Status: 1 pending, 2 completed, 3 cancelled"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements [get]
+func (r *settlementRoutes) ListSettlements(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	params := &entities.SettlementListParams{WorkspaceID: workspaceID}
+	if memberIDStr := c.Query("user_id"); memberIDStr != "" {
+		memberID, err := uuid.Parse(memberIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid user_id format",
+			})
+			return
+		}
+		params.UserID = &memberID
+	}
+	if statusStr := c.Query("status"); statusStr != "" {
+		if val, err := strconv.Atoi(statusStr); err == nil {
+			params.Status = &val
+		}
+	}
+
+	limit := 10
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	settlements, err := r.settlementService.ListSettlements(c.Request.Context(), userID, params, limit, offset)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    settlements,
+	})
+}
+
+// @Summary Record a settlement
+// @Description Record a payment from one member to another, optionally with a photo of the transfer receipt. The recording member must be the payer or the payee and their side is confirmed right away.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.CreateSettlementRequest true "Settlement details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements [post]
+func (r *settlementRoutes) CreateSettlement(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.CreateSettlementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	defaultToActiveWorkspace(c, &input.WorkspaceID)
+
+	settlement, err := r.settlementService.CreateSettlement(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    settlement,
+	})
+}
+
+// @Summary Get settlement by ID
+// @Description Get a settlement with the confirmations of both sides
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements/{id} [get]
+func (r *settlementRoutes) GetSettlementByID(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := r.settlementService.GetSettlementByID(c.Request.Context(), userID, settlementID)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    settlement,
+	})
+}
+
+// @Summary Confirm a settlement
+// @Description Confirm a pending settlement as its payer or payee. It completes and counts towards the balances once both sides confirmed.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements/{id}/confirm [post]
+func (r *settlementRoutes) ConfirmSettlement(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := r.settlementService.ConfirmSettlement(c.Request.Context(), userID, settlementID)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    settlement,
+		Message: "Settlement confirmed successfully",
+	})
+}
+
+// @Summary Cancel a settlement
+// @Description Cancel a pending settlement as its payer or payee. Completed settlements cannot be cancelled, record the opposite payment instead.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /settlements/{id}/cancel [post]
+func (r *settlementRoutes) CancelSettlement(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	settlementID, ok := parseSettlementID(c)
+	if !ok {
+		return
+	}
+
+	settlement, err := r.settlementService.CancelSettlement(c.Request.Context(), userID, settlementID)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    settlement,
+		Message: "Settlement cancelled successfully",
+	})
+}
+
+// @Summary Get transaction splits
+// @Description Get the shares of the members in a split expense
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/splits [get]
+func (r *settlementRoutes) GetTransactionSplits(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	splits, err := r.settlementService.GetTransactionSplits(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    splits,
+	})
+}
+
+// @Summary Split a transaction
+// @Description Replace the shares of the members in an expense. The member who recorded the expense paid it and the others owe their share to them. The shares must add up to the transaction amount, an empty list removes the split.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param input body entities.SetTransactionSplitsRequest true "Shares of the members"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/splits [put]
+func (r *settlementRoutes) SetTransactionSplits(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	var input entities.SetTransactionSplitsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	splits, err := r.settlementService.SetTransactionSplits(c.Request.Context(), userID, transactionID, &input)
+	if err != nil {
+		c.JSON(settlementErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    splits,
+	})
+}
+
+// parseSettlementID parses the settlement ID path parameter, sending a 400 response when it is invalid
+func parseSettlementID(c *gin.Context) (uuid.UUID, bool) {
+	settlementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid settlement ID format",
+		})
+		return uuid.Nil, false
+	}
+	return settlementID, true
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type spendingAnomalyRoutes struct {
+	anomalyService services.SpendingAnomalyService
+	auth           *middleware.AuthMiddleware
+}
+
+func newSpendingAnomalyRoutes(handler *gin.RouterGroup, anomalyService services.SpendingAnomalyService, auth *middleware.AuthMiddleware) {
+	r := &spendingAnomalyRoutes{
+		anomalyService: anomalyService,
+		auth:           auth,
+	}
+
+	// All anomaly endpoints require authentication
+	anomalies := handler.Group("/anomalies").Use(auth.AuthRequired())
+	{
+		anomalies.GET("", r.GetAnomalies)
+		anomalies.PUT("/:id/dismiss", r.DismissAnomaly)
+	}
+}
+
+// @Summary Get spending anomalies
+// @Description Get the transactions flagged as unusual for the authenticated user, newest first. anomaly_type is 1 (category outlier), 2 (large amount at a new merchant), 3 (unusual hour) or 4 (category spike).
+// @Tags anomalies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query int false "1 (open) or 2 (dismissed), all when omitted"
+// @Param limit query int false "Limit for pagination"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /anomalies [get]
+func (r *spendingAnomalyRoutes) GetAnomalies(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	offset := 0
+	status := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil {
+			offset = val
+		}
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		val, err := strconv.Atoi(statusStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+				Success: false,
+				Error:   "invalid status",
+			})
+			return
+		}
+		status = val
+	}
+
+	anomalies, err := r.anomalyService.GetAnomalies(c.Request.Context(), userID, status, limit, offset)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err.Error() == "invalid status" {
+			statusCode = http.StatusBadRequest
+		}
+		c.JSON(statusCode, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    anomalies,
+	})
+}
+
+// @Summary Dismiss a spending anomaly
+// @Description Mark an anomaly as expected. The transaction then counts as normal spending in the baselines of later checks.
+// @Tags anomalies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Spending Anomaly ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /anomalies/{id}/dismiss [put]
+func (r *spendingAnomalyRoutes) DismissAnomaly(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	anomalyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid spending anomaly ID format",
+		})
+		return
+	}
+
+	anomaly, err := r.anomalyService.DismissAnomaly(c.Request.Context(), userID, anomalyID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "spending anomaly not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "access denied to spending anomaly" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    anomaly,
+		Message: "Spending anomaly dismissed successfully",
+	})
+}
@@ -65,11 +65,10 @@ func bindTransactionRequest(c *gin.Context, isUpdate bool) (interface{}, error)
 			return nil, fmt.Errorf("transaction_type is required")
 		}
 
-		// if paymentMethod, ok := rawData["payment_method"].(float64); ok {
-		// 	input.PaymentMethod = int(paymentMethod)
-		// } else {
-		// 	return nil, fmt.Errorf("payment_method is required")
-		// }
+		// Payment method is optional, the workspace default applies when it is left out
+		if paymentMethod, ok := rawData["payment_method"].(float64); ok {
+			input.PaymentMethod = int(paymentMethod)
+		}
 
 		// Optional fields
 		if accountIDStr, ok := rawData["account_id"].(string); ok {
@@ -117,6 +116,14 @@ func bindTransactionRequest(c *gin.Context, isUpdate bool) (interface{}, error)
 			}
 		}
 
+		if transferAccountIDStr, ok := rawData["transfer_account_id"].(string); ok {
+			if id, err := uuid.Parse(transferAccountIDStr); err == nil {
+				input.TransferAccountID = &id
+			} else {
+				return nil, fmt.Errorf("invalid transfer_account_id: %v", err)
+			}
+		}
+
 		return &input, nil
 	} else {
 		var input entities.CreateTransactionRequest
@@ -151,11 +158,10 @@ func bindTransactionRequest(c *gin.Context, isUpdate bool) (interface{}, error)
 			return nil, fmt.Errorf("transaction_type is required")
 		}
 
-		// if paymentMethod, ok := rawData["payment_method"].(float64); ok {
-		// 	input.PaymentMethod = int(paymentMethod)
-		// } else {
-		// 	return nil, fmt.Errorf("payment_method is required")
-		// }
+		// Payment method is optional, the workspace default applies when it is left out
+		if paymentMethod, ok := rawData["payment_method"].(float64); ok {
+			input.PaymentMethod = int(paymentMethod)
+		}
 
 		// Parse workspace_id
 		if workspaceIDStr, ok := rawData["workspace_id"].(string); ok {
@@ -164,19 +170,19 @@ func bindTransactionRequest(c *gin.Context, isUpdate bool) (interface{}, error)
 			} else {
 				return nil, fmt.Errorf("invalid workspace_id: %v", err)
 			}
+		} else if activeWorkspaceID, ok := GetActiveWorkspaceID(c); ok {
+			input.WorkspaceID = activeWorkspaceID
 		} else {
 			return nil, fmt.Errorf("workspace_id is required")
 		}
 
-		// Parse account_id
+		// Parse account_id, the workspace default account applies when it is left out
 		if accountIDStr, ok := rawData["account_id"].(string); ok {
 			if id, err := uuid.Parse(accountIDStr); err == nil {
 				input.AccountID = id
 			} else {
 				return nil, fmt.Errorf("invalid account_id: %v", err)
 			}
-		} else {
-			return nil, fmt.Errorf("account_id is required")
 		}
 
 		// Optional fields
@@ -216,6 +222,22 @@ func bindTransactionRequest(c *gin.Context, isUpdate bool) (interface{}, error)
 			}
 		}
 
+		if transferAccountIDStr, ok := rawData["transfer_account_id"].(string); ok {
+			if id, err := uuid.Parse(transferAccountIDStr); err == nil {
+				input.TransferAccountID = &id
+			} else {
+				return nil, fmt.Errorf("invalid transfer_account_id: %v", err)
+			}
+		}
+
+		if aiConfidenceScore, ok := rawData["ai_confidence_score"].(float64); ok {
+			input.AIConfidenceScore = &aiConfidenceScore
+		}
+
+		if saveAsDraft, ok := rawData["save_as_draft"].(bool); ok {
+			input.SaveAsDraft = saveAsDraft
+		}
+
 		return &input, nil
 	}
 }
@@ -249,7 +271,7 @@ func newTransactionRoutes(handler *gin.RouterGroup, transactionService services.
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param workspace_id query string true "Workspace ID"
+// @Param workspace_id query string false "Workspace ID, defaults to the active workspace"
 // @Param account_id query string false "Filter by account ID"
 // @Param category_id query string false "Filter by category ID"
 // @Param start_date query string false "Start date filter (YYYY-MM-DD)"
@@ -260,6 +282,7 @@ func newTransactionRoutes(handler *gin.RouterGroup, transactionService services.
 // @Param amount query number false "Filter by exact amount"
 // @Param is_recurring query boolean false "Filter by recurring status"
 // @Param credit_status query int false "Filter by credit status"
+// @Param approval_status query int false "Filter by approval status (0=not required, 1=draft, 2=submitted, 3=approved, 4=rejected, 5=reimbursed)"
 // @Param limit query int false "Limit for pagination (default: 10)"
 // @Param offset query int false "Offset for pagination (default: 0)"
 // @Success 200 {object} entities.ApiResponse
@@ -274,21 +297,8 @@ func (r *transactionRoutes) GetTransactionsByWorkspace(c *gin.Context) {
 		return
 	}
 
-	workspaceIDStr := c.Query("workspace_id")
-	if workspaceIDStr == "" {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "workspace_id is required",
-		})
-		return
-	}
-
-	workspaceID, err := uuid.Parse(workspaceIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "invalid workspace_id format",
-		})
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
 		return
 	}
 
@@ -309,70 +319,14 @@ func (r *transactionRoutes) GetTransactionsByWorkspace(c *gin.Context) {
 	}
 
 	// Parse filter parameters
-	params := &entities.TransactionListParams{}
-
-	if accountIDStr := c.Query("account_id"); accountIDStr != "" {
-		if accountID, err := uuid.Parse(accountIDStr); err == nil {
-			params.AccountID = &accountID
-		}
-	}
-
-	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
-		if categoryID, err := uuid.Parse(categoryIDStr); err == nil {
-			params.CategoryID = &categoryID
-		}
-	}
-
-	if startDateStr := c.Query("start_date"); startDateStr != "" {
-		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
-			params.StartDate = &startDate
-		}
-	}
-
-	if endDateStr := c.Query("end_date"); endDateStr != "" {
-		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
-			params.EndDate = &endDate
-		}
-	}
-
-	if paymentMethodStr := c.Query("payment_method"); paymentMethodStr != "" {
-		if paymentMethod, err := strconv.Atoi(paymentMethodStr); err == nil {
-			params.PaymentMethod = &paymentMethod
-		}
-	}
-
-	if description := c.Query("description"); description != "" {
-		params.Description = &description
-	}
-
-	if merchantName := c.Query("merchant_name"); merchantName != "" {
-		params.MerchantName = &merchantName
-	}
-
-	if amountStr := c.Query("amount"); amountStr != "" {
-		if amount, err := strconv.ParseFloat(amountStr, 64); err == nil {
-			params.Amount = &amount
-		}
-	}
-
-	if isRecurringStr := c.Query("is_recurring"); isRecurringStr != "" {
-		if isRecurring, err := strconv.ParseBool(isRecurringStr); err == nil {
-			params.IsRecurring = &isRecurring
-		}
-	}
-
-	if creditStatusStr := c.Query("credit_status"); creditStatusStr != "" {
-		if creditStatus, err := strconv.Atoi(creditStatusStr); err == nil {
-			params.CreditStatus = &creditStatus
-		}
-	}
+	params := parseTransactionListParams(c)
 
 	transactions, totalCount, err := r.transactionService.GetTransactionsByWorkspace(c.Request.Context(), userID, workspaceID, params, limit, offset)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "workspace not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "access denied" {
+		} else if err.Error() == "access denied" || err.Error() == "access denied to workspace" {
 			status = http.StatusForbidden
 		}
 		c.JSON(status, &entities.ApiResponse{
@@ -430,14 +384,21 @@ func (r *transactionRoutes) CreateTransaction(c *gin.Context) {
 			err.Error() == "amount is required" ||
 			err.Error() == "transaction type is required" ||
 			err.Error() == "payment method is required" ||
-			err.Error() == "transaction date is required" {
+			err.Error() == "transaction date is required" ||
+			err.Error() == "account is required" ||
+			err.Error() == "invalid payment method" ||
+			err.Error() == "account is required for a transfer" ||
+			err.Error() == "transfer account must be different from the account" {
 			status = http.StatusBadRequest
 		} else if err.Error() == "workspace not found" ||
-			err.Error() == "account not found" {
+			err.Error() == "account not found" ||
+			err.Error() == "transfer account not found" {
 			status = http.StatusNotFound
 		} else if err.Error() == "access denied to workspace" ||
 			err.Error() == "access denied to account" {
 			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -486,7 +447,7 @@ func (r *transactionRoutes) GetTransactionByID(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err.Error() == "transaction not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "access denied" {
+		} else if err.Error() == "access denied" || err.Error() == "access denied to workspace" {
 			status = http.StatusForbidden
 		}
 		c.JSON(status, &entities.ApiResponse{
@@ -549,14 +510,21 @@ func (r *transactionRoutes) UpdateTransaction(c *gin.Context) {
 			err.Error() == "amount is required" ||
 			err.Error() == "transaction type is required" ||
 			err.Error() == "payment method is required" ||
-			err.Error() == "transaction date is required" {
+			err.Error() == "transaction date is required" ||
+			err.Error() == "invalid payment method" ||
+			err.Error() == "account is required for a transfer" ||
+			err.Error() == "transfer account must be different from the account" {
 			status = http.StatusBadRequest
 		} else if err.Error() == "transaction not found" ||
-			err.Error() == "account not found" {
+			err.Error() == "account not found" ||
+			err.Error() == "transfer account not found" {
 			status = http.StatusNotFound
 		} else if err.Error() == "access denied" ||
+			err.Error() == "access denied to workspace" ||
 			err.Error() == "access denied to account" {
 			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -605,8 +573,10 @@ func (r *transactionRoutes) DeleteTransaction(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err.Error() == "transaction not found" {
 			status = http.StatusNotFound
-		} else if err.Error() == "access denied" {
+		} else if err.Error() == "access denied" || err.Error() == "access denied to workspace" {
 			status = http.StatusForbidden
+		} else if err.Error() == "workspace is archived" {
+			status = http.StatusConflict
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -620,3 +590,73 @@ func (r *transactionRoutes) DeleteTransaction(c *gin.Context) {
 		Message: "Transaction deleted successfully",
 	})
 }
+
+// parseTransactionListParams parses the transaction list filters from the query string.
+// Invalid values are ignored.
+func parseTransactionListParams(c *gin.Context) *entities.TransactionListParams {
+	params := &entities.TransactionListParams{}
+
+	if accountIDStr := c.Query("account_id"); accountIDStr != "" {
+		if accountID, err := uuid.Parse(accountIDStr); err == nil {
+			params.AccountID = &accountID
+		}
+	}
+
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		if categoryID, err := uuid.Parse(categoryIDStr); err == nil {
+			params.CategoryID = &categoryID
+		}
+	}
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			params.StartDate = &startDate
+		}
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		if endDate, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			params.EndDate = &endDate
+		}
+	}
+
+	if paymentMethodStr := c.Query("payment_method"); paymentMethodStr != "" {
+		if paymentMethod, err := strconv.Atoi(paymentMethodStr); err == nil {
+			params.PaymentMethod = &paymentMethod
+		}
+	}
+
+	if description := c.Query("description"); description != "" {
+		params.Description = &description
+	}
+
+	if merchantName := c.Query("merchant_name"); merchantName != "" {
+		params.MerchantName = &merchantName
+	}
+
+	if amountStr := c.Query("amount"); amountStr != "" {
+		if amount, err := strconv.ParseFloat(amountStr, 64); err == nil {
+			params.Amount = &amount
+		}
+	}
+
+	if isRecurringStr := c.Query("is_recurring"); isRecurringStr != "" {
+		if isRecurring, err := strconv.ParseBool(isRecurringStr); err == nil {
+			params.IsRecurring = &isRecurring
+		}
+	}
+
+	if creditStatusStr := c.Query("credit_status"); creditStatusStr != "" {
+		if creditStatus, err := strconv.Atoi(creditStatusStr); err == nil {
+			params.CreditStatus = &creditStatus
+		}
+	}
+
+	if approvalStatusStr := c.Query("approval_status"); approvalStatusStr != "" {
+		if approvalStatus, err := strconv.Atoi(approvalStatusStr); err == nil {
+			params.ApprovalStatus = &approvalStatus
+		}
+	}
+
+	return params
+}
@@ -0,0 +1,350 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type transactionApprovalRoutes struct {
+	transactionApprovalService services.TransactionApprovalService
+}
+
+func newTransactionApprovalRoutes(handler *gin.RouterGroup, transactionApprovalService services.TransactionApprovalService, auth *middleware.AuthMiddleware) {
+	r := &transactionApprovalRoutes{transactionApprovalService: transactionApprovalService}
+
+	transactions := handler.Group("/transactions").Use(auth.AuthRequired())
+	{
+		transactions.POST("/:id/submit", r.SubmitTransaction)
+		transactions.POST("/:id/approve", auth.VerifiedRequired(), r.ApproveTransaction)
+		transactions.POST("/:id/reject", auth.VerifiedRequired(), r.RejectTransaction)
+		transactions.POST("/:id/reimburse", auth.VerifiedRequired(), r.ReimburseTransaction)
+	}
+
+	approvals := handler.Group("/approvals").Use(auth.AuthRequired())
+	{
+		approvals.GET("", r.GetPendingApprovals)
+		approvals.POST("/quick-reply", auth.VerifiedRequired(), r.HandleQuickReply)
+	}
+}
+
+// transactionApprovalErrorStatus maps transaction approval service errors to HTTP status codes
+func transactionApprovalErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "transaction not found":
+		return http.StatusNotFound
+	case "access denied to workspace", "cannot decide on your own expense":
+		return http.StatusForbidden
+	case "workspace is archived",
+		"transaction cannot be submitted",
+		"transaction is not waiting for approval",
+		"transaction is not approved",
+		"transaction approval status has changed":
+		return http.StatusConflict
+	case "rejection note is required", "invalid quick reply payload":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Get pending approvals
+// @Description Get the expenses of a business workspace waiting for approval, for owners and admins
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id query string false "Workspace ID (defaults to the active workspace)"
+// @Param limit query int false "Limit for pagination (default: 20)"
+// @Param offset query int false "Offset for pagination (default: 0)"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /approvals [get]
+func (r *transactionApprovalRoutes) GetPendingApprovals(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	workspaceID, ok := parseWorkspaceIDQuery(c)
+	if !ok {
+		return
+	}
+
+	// Parse pagination parameters
+	limit := 20
+	offset := 0
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if val, err := strconv.Atoi(offsetStr); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	transactions, totalCount, err := r.transactionApprovalService.GetPendingApprovals(c.Request.Context(), userID, workspaceID, limit, offset)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"transactions": transactions,
+			"total":        totalCount,
+			"limit":        limit,
+			"offset":       offset,
+		},
+	})
+}
+
+// @Summary Submit an expense for approval
+// @Description Submit a draft or rejected expense, the approvers of the workspace are notified
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/submit [post]
+func (r *transactionApprovalRoutes) SubmitTransaction(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	transaction, err := r.transactionApprovalService.SubmitTransaction(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transaction,
+		Message: "Transaction submitted for approval",
+	})
+}
+
+// @Summary Approve an expense
+// @Description Approve a submitted expense, it then counts toward budgets and reports. Approvers cannot approve their own expenses unless they own the workspace.
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/approve [post]
+func (r *transactionApprovalRoutes) ApproveTransaction(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	transaction, err := r.transactionApprovalService.ApproveTransaction(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transaction,
+		Message: "Transaction approved",
+	})
+}
+
+// @Summary Reject an expense
+// @Description Reject a submitted expense with a note, its author may edit and submit it again
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param input body entities.RejectTransactionRequest true "Rejection note"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/reject [post]
+func (r *transactionApprovalRoutes) RejectTransaction(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	var input entities.RejectTransactionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transaction, err := r.transactionApprovalService.RejectTransaction(c.Request.Context(), userID, transactionID, &input)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transaction,
+		Message: "Transaction rejected",
+	})
+}
+
+// @Summary Reimburse an expense
+// @Description Mark an approved expense as paid back to its author
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/reimburse [post]
+func (r *transactionApprovalRoutes) ReimburseTransaction(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	transaction, err := r.transactionApprovalService.ReimburseTransaction(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transaction,
+		Message: "Transaction reimbursed",
+	})
+}
+
+// @Summary Answer an approval request from WhatsApp
+// @Description Approve or reject an expense from a WhatsApp quick reply, the payload is "approve:<transaction_id>" or "reject:<transaction_id>"
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.ApprovalQuickReplyRequest true "Quick reply payload and optional rejection note"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /approvals/quick-reply [post]
+func (r *transactionApprovalRoutes) HandleQuickReply(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.ApprovalQuickReplyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	transaction, err := r.transactionApprovalService.HandleQuickReply(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(transactionApprovalErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    transaction,
+	})
+}
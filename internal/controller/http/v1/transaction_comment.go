@@ -0,0 +1,322 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type transactionCommentRoutes struct {
+	transactionCommentService services.TransactionCommentService
+}
+
+func newTransactionCommentRoutes(handler *gin.RouterGroup, transactionCommentService services.TransactionCommentService, auth *middleware.AuthMiddleware) {
+	r := &transactionCommentRoutes{transactionCommentService: transactionCommentService}
+
+	transactions := handler.Group("/transactions").Use(auth.AuthRequired())
+	{
+		transactions.GET("/:id/comments", r.GetComments)
+		transactions.POST("/:id/comments", r.CreateComment)
+		transactions.PUT("/:id/comments/:comment_id", r.UpdateComment)
+		transactions.DELETE("/:id/comments/:comment_id", r.DeleteComment)
+	}
+
+	messages := handler.Group("/messages").Use(auth.AuthRequired())
+	{
+		messages.POST("/:id/comments", r.ReplyToMessage)
+	}
+}
+
+// transactionCommentErrorStatus maps transaction comment service errors to HTTP status codes
+func transactionCommentErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "transaction not found", "comment not found", "message not found":
+		return http.StatusNotFound
+	case "access denied to workspace", "access denied to message", "only the author can edit a comment":
+		return http.StatusForbidden
+	case "workspace is archived":
+		return http.StatusConflict
+	case "transaction does not belong to a workspace",
+		"message is not linked to a transaction",
+		"comment content or attachment is required",
+		"comment is too long",
+		"too many attachments",
+		"attachment url is required",
+		"mentioned user is not a member of the workspace":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseCommentIDs reads the transaction and comment IDs from the path
+func parseCommentIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid comment ID format",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return transactionID, commentID, true
+}
+
+// @Summary Get transaction comments
+// @Description Get the discussion thread of a workspace transaction, oldest first
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/comments [get]
+func (r *transactionCommentRoutes) GetComments(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	comments, err := r.transactionCommentService.GetComments(c.Request.Context(), userID, transactionID)
+	if err != nil {
+		c.JSON(transactionCommentErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    comments,
+	})
+}
+
+// @Summary Comment on a transaction
+// @Description Post a comment on a workspace transaction. Members are mentioned by user ID or with @name in the content and get a notification.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param input body entities.CreateTransactionCommentRequest true "Content, mentions and attachments"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/comments [post]
+func (r *transactionCommentRoutes) CreateComment(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid transaction ID format",
+		})
+		return
+	}
+
+	var input entities.CreateTransactionCommentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	comment, err := r.transactionCommentService.CreateComment(c.Request.Context(), userID, transactionID, &input)
+	if err != nil {
+		c.JSON(transactionCommentErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    comment,
+		Message: "Comment posted successfully",
+	})
+}
+
+// @Summary Edit a transaction comment
+// @Description Replace the content, mentions and attachments of a comment. Only the author can edit it, newly mentioned members get a notification.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param comment_id path string true "Comment ID"
+// @Param input body entities.UpdateTransactionCommentRequest true "Content, mentions and attachments"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/comments/{comment_id} [put]
+func (r *transactionCommentRoutes) UpdateComment(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, commentID, ok := parseCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	var input entities.UpdateTransactionCommentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	comment, err := r.transactionCommentService.UpdateComment(c.Request.Context(), userID, transactionID, commentID, &input)
+	if err != nil {
+		c.JSON(transactionCommentErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    comment,
+		Message: "Comment updated successfully",
+	})
+}
+
+// @Summary Delete a transaction comment
+// @Description Delete a comment. Members may delete their own comments, admins and the owner any comment.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /transactions/{id}/comments/{comment_id} [delete]
+func (r *transactionCommentRoutes) DeleteComment(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	transactionID, commentID, ok := parseCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := r.transactionCommentService.DeleteComment(c.Request.Context(), userID, transactionID, commentID); err != nil {
+		c.JSON(transactionCommentErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Comment deleted successfully",
+	})
+}
+
+// @Summary Comment by replying to a message
+// @Description Post a comment on the transaction a bot message is linked to, used when the user replies to the WhatsApp confirmation of a transaction. The comment is recorded as coming from WhatsApp.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Message ID the user replied to"
+// @Param input body entities.ReplyTransactionCommentRequest true "Content and attachments of the reply"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /messages/{id}/comments [post]
+func (r *transactionCommentRoutes) ReplyToMessage(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid message ID format",
+		})
+		return
+	}
+
+	var input entities.ReplyTransactionCommentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	comment, err := r.transactionCommentService.ReplyToMessage(c.Request.Context(), userID, messageID, &input)
+	if err != nil {
+		c.JSON(transactionCommentErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    comment,
+		Message: "Comment posted successfully",
+	})
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
 	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 	"github.com/vasst-id/vasst-expense-api/internal/services"
@@ -26,6 +25,8 @@ func newUserRoutes(handler *gin.RouterGroup, userService services.UserService, a
 	{
 		users.GET("/", r.auth.AuthRequired(), r.GetUserByID)
 		users.PUT("/", r.auth.AuthRequired(), r.UpdateUser)
+		users.POST("/phone", r.auth.AuthRequired(), r.RequestPhoneChange)
+		users.POST("/phone/verify", r.auth.AuthRequired(), r.ConfirmPhoneChange)
 	}
 
 	// Authentication endpoints
@@ -34,8 +35,9 @@ func newUserRoutes(handler *gin.RouterGroup, userService services.UserService, a
 		authRoutes.POST("/login", r.Login)
 		authRoutes.POST("/register", r.Register)
 		authRoutes.POST("/forgot-password", r.ForgotPassword)
+		authRoutes.POST("/verify-reset-code", r.VerifyPasswordResetCode)
 		authRoutes.POST("/reset-password", r.ResetPassword)
-		authRoutes.POST("/change-password", r.ChangePassword)
+		authRoutes.POST("/change-password", r.auth.AuthRequired(), r.ChangePassword)
 		authRoutes.POST("/verify-phone", r.VerifyPhone)
 		authRoutes.POST("/resend-verification-code", r.ResendVerificationCode)
 		authRoutes.POST("/verify-email", r.VerifyEmail)
@@ -43,6 +45,45 @@ func newUserRoutes(handler *gin.RouterGroup, userService services.UserService, a
 	}
 }
 
+// passwordErrorStatus maps password reset and change errors to HTTP status codes
+func passwordErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid verification code", "verification code has expired", "maximum verification attempts exceeded",
+		"invalid or expired reset token", "reset token has already been used",
+		"current password is incorrect", "new password must be different from the current one", "new password must be exactly 6 digits":
+		return http.StatusBadRequest
+	case "user account is inactive":
+		return http.StatusForbidden
+	case "user not found", "no active verification code found":
+		return http.StatusNotFound
+	case "please wait before requesting another code", "too many codes requested, try again later", "too many incorrect attempts, try again later":
+		return http.StatusTooManyRequests
+	case "failed to deliver verification code":
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// verificationErrorStatus maps phone and email verification errors to HTTP status codes
+func verificationErrorStatus(err error) int {
+	switch err.Error() {
+	case "invalid verification code", "verification code has expired", "maximum verification attempts exceeded",
+		"invalid or expired verification link", "new phone number is the current one", "email is required":
+		return http.StatusBadRequest
+	case "user not found", "no active verification code found":
+		return http.StatusNotFound
+	case "phone number is already verified", "email is already verified", "phone number already in use":
+		return http.StatusConflict
+	case "please wait before requesting another code", "too many codes requested, try again later":
+		return http.StatusTooManyRequests
+	case "failed to deliver verification code":
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // @Summary Get user by ID
 // @Description Get a user by their ID
 // @Tags users
@@ -80,7 +121,7 @@ func (r *userRoutes) GetUserByID(c *gin.Context) {
 }
 
 // @Summary Create a new user
-// @Description Create a new user with the provided details
+// @Description Create a new user with the provided details. With an invitation token the user joins the invited workspace once the phone number is verified.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -99,13 +140,20 @@ func (r *userRoutes) Register(c *gin.Context) {
 		return
 	}
 
-	loginResponse, err := r.userService.CreateUser(c.Request.Context(), &input)
+	loginResponse, err := r.userService.CreateUser(withSessionClient(c, input.DeviceName), &input)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user with this email already exists" ||
-			err.Error() == "user with this phone number already exists" {
+			err.Error() == "user with this phone number already exists" ||
+			err.Error() == "invitation is no longer pending" {
 			status = http.StatusConflict
-		} else if err.Error() == "password must be exactly 6 digits" ||
+		} else if err.Error() == "invitation not found" {
+			status = http.StatusNotFound
+		} else if err.Error() == "invitation was sent to another user" {
+			status = http.StatusForbidden
+		} else if err.Error() == "invalid invitation token" ||
+			err.Error() == "invitation has expired" ||
+			err.Error() == "password must be exactly 6 digits" ||
 			err.Error() == "email is required" ||
 			err.Error() == "phone number is required" ||
 			err.Error() == "first name is required" ||
@@ -128,24 +176,21 @@ func (r *userRoutes) Register(c *gin.Context) {
 }
 
 // @Summary Update a user
-// @Description Update an existing user's details
+// @Description Update the authenticated user's details. A new email must be verified again, the phone number is changed with /users/phone.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param id path string true "User ID"
+// @Security BearerAuth
 // @Param input body entities.UpdateUserInput true "Updated user details"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
-// @Router /users/{id} [put]
+// @Router /users [put]
 func (r *userRoutes) UpdateUser(c *gin.Context) {
-	userID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
-			Success: false,
-			Error:   "invalid user ID format",
-		})
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
 		return
 	}
 
@@ -166,6 +211,8 @@ func (r *userRoutes) UpdateUser(c *gin.Context) {
 		} else if err.Error() == "phone number already in use" ||
 			err.Error() == "email already in use" {
 			status = http.StatusConflict
+		} else if err.Error() == "phone number changes must be verified" {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -191,6 +238,7 @@ func (r *userRoutes) UpdateUser(c *gin.Context) {
 // @Failure 401 {object} entities.ApiResponse
 // @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
 // @Router /auth/login [post]
 func (r *userRoutes) Login(c *gin.Context) {
 	var input entities.LoginRequest
@@ -210,13 +258,15 @@ func (r *userRoutes) Login(c *gin.Context) {
 		return
 	}
 
-	loginResponse, err := r.userService.Login(c.Request.Context(), &input)
+	loginResponse, err := r.userService.Login(withSessionClient(c, input.DeviceName), &input)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "user not found" {
 			status = http.StatusNotFound
 		} else if err.Error() == "password is incorrect" {
 			status = http.StatusUnauthorized
+		} else if err.Error() == "too many incorrect attempts, try again later" {
+			status = http.StatusTooManyRequests
 		} else if err.Error() == "user account is inactive" {
 			status = http.StatusForbidden
 		} else if err.Error() == "email or phone is required" {
@@ -236,14 +286,15 @@ func (r *userRoutes) Login(c *gin.Context) {
 }
 
 // @Summary Forgot Password
-// @Description Initiate password reset process
+// @Description Send a password reset code to the user's phone over WhatsApp (default) or SMS. The response is the same whether or not the phone number has an account.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body entities.ForgotPasswordRequest true "Forgot password request"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
-// @Failure 404 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
 // @Router /auth/forgot-password [post]
 func (r *userRoutes) ForgotPassword(c *gin.Context) {
 	var input entities.ForgotPasswordRequest
@@ -257,11 +308,43 @@ func (r *userRoutes) ForgotPassword(c *gin.Context) {
 
 	err := r.userService.ForgotPassword(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(passwordErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "If the phone number has an account, a password reset code has been sent",
+	})
+}
+
+// @Summary Verify Password Reset Code
+// @Description Exchange the password reset code for a reset token, valid for 10 minutes and a single reset
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body entities.VerifyPasswordResetCodeRequest true "Password reset code"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /auth/verify-reset-code [post]
+func (r *userRoutes) VerifyPasswordResetCode(c *gin.Context) {
+	var input entities.VerifyPasswordResetCodeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	resetToken, err := r.userService.VerifyPasswordResetCode(c.Request.Context(), &input)
+	if err != nil {
+		c.JSON(passwordErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -270,18 +353,20 @@ func (r *userRoutes) ForgotPassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
-		Message: "Password reset instructions sent to your email",
+		Data:    resetToken,
 	})
 }
 
 // @Summary Reset Password
-// @Description Reset password using token
+// @Description Set a new password with the reset token, every session of the user is logged out
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body entities.ResetPasswordRequest true "Reset password request"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
 // @Router /auth/reset-password [post]
 func (r *userRoutes) ResetPassword(c *gin.Context) {
 	var input entities.ResetPasswordRequest
@@ -295,7 +380,7 @@ func (r *userRoutes) ResetPassword(c *gin.Context) {
 
 	err := r.userService.ResetPassword(c.Request.Context(), &input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+		c.JSON(passwordErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -304,21 +389,30 @@ func (r *userRoutes) ResetPassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
-		Message: "Password reset successfully",
+		Message: "Password reset successfully, please log in again",
 	})
 }
 
 // @Summary Change Password
-// @Description Change user's password
+// @Description Change the user's password with the current one. Every session is logged out and this device gets new tokens.
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param input body entities.ChangePasswordRequest true "Change password request"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
-// @Failure 501 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
 // @Router /auth/change-password [post]
 func (r *userRoutes) ChangePassword(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
 	var input entities.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
@@ -328,13 +422,9 @@ func (r *userRoutes) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	err := r.userService.ChangePassword(c.Request.Context(), &input)
+	loginResponse, err := r.userService.ChangePassword(withSessionClient(c, ""), userID, &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "change password not implemented" {
-			status = http.StatusNotImplemented
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(passwordErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -343,12 +433,13 @@ func (r *userRoutes) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
+		Data:    loginResponse,
 		Message: "Password changed successfully",
 	})
 }
 
 // @Summary Verify Phone
-// @Description Verify phone number with code
+// @Description Verify phone number with the code sent at registration. Refresh the access token afterwards to use routes that need a verified phone.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -369,11 +460,7 @@ func (r *userRoutes) VerifyPhone(c *gin.Context) {
 
 	err := r.userService.VerifyPhone(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -387,7 +474,7 @@ func (r *userRoutes) VerifyPhone(c *gin.Context) {
 }
 
 // @Summary Resend Verification Code
-// @Description Resend SMS verification code
+// @Description Send a new phone verification code over WhatsApp (default) or SMS
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -395,6 +482,9 @@ func (r *userRoutes) VerifyPhone(c *gin.Context) {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
+// @Failure 502 {object} entities.ApiResponse
 // @Router /auth/resend-verification-code [post]
 func (r *userRoutes) ResendVerificationCode(c *gin.Context) {
 	var input entities.ResendVerificationCodeRequest
@@ -408,11 +498,7 @@ func (r *userRoutes) ResendVerificationCode(c *gin.Context) {
 
 	err := r.userService.ResendVerificationCode(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -426,14 +512,13 @@ func (r *userRoutes) ResendVerificationCode(c *gin.Context) {
 }
 
 // @Summary Verify Email
-// @Description Verify email address with token
+// @Description Verify email address with the token of the emailed verification link
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param input body entities.VerifyEmailRequest true "Email verification request"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
-// @Failure 404 {object} entities.ApiResponse
 // @Router /auth/verify-email [post]
 func (r *userRoutes) VerifyEmail(c *gin.Context) {
 	var input entities.VerifyEmailRequest
@@ -447,11 +532,7 @@ func (r *userRoutes) VerifyEmail(c *gin.Context) {
 
 	err := r.userService.VerifyEmail(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -473,6 +554,7 @@ func (r *userRoutes) VerifyEmail(c *gin.Context) {
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
 // @Router /auth/resend-verification-email [post]
 func (r *userRoutes) ResendVerificationEmail(c *gin.Context) {
 	var input entities.ResendVerificationEmailRequest
@@ -486,11 +568,7 @@ func (r *userRoutes) ResendVerificationEmail(c *gin.Context) {
 
 	err := r.userService.ResendVerificationEmail(c.Request.Context(), &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -502,3 +580,90 @@ func (r *userRoutes) ResendVerificationEmail(c *gin.Context) {
 		Message: "Verification email sent successfully",
 	})
 }
+
+// @Summary Request Phone Change
+// @Description Send a code to the new phone number over WhatsApp (default) or SMS, the number changes once the code is verified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.ChangePhoneRequest true "New phone number"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 429 {object} entities.ApiResponse
+// @Failure 502 {object} entities.ApiResponse
+// @Router /users/phone [post]
+func (r *userRoutes) RequestPhoneChange(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.ChangePhoneRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := r.userService.RequestPhoneChange(c.Request.Context(), userID, &input); err != nil {
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Verification code sent to the new phone number",
+	})
+}
+
+// @Summary Confirm Phone Change
+// @Description Move to the new phone number with the code sent to it, the new number is verified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body entities.ConfirmPhoneChangeRequest true "New phone number and code"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Router /users/phone/verify [post]
+func (r *userRoutes) ConfirmPhoneChange(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var input entities.ConfirmPhoneChangeRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user, err := r.userService.ConfirmPhoneChange(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(verificationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    user,
+		Message: "Phone number changed successfully",
+	})
+}
@@ -56,8 +56,11 @@ func (r *verificationCodeRoutes) CreateVerificationCode(c *gin.Context) {
 		if err.Error() == "phone number is required" ||
 			err.Error() == "code type is required" {
 			status = http.StatusBadRequest
-		} else if err.Error() == "please wait before requesting another code" {
+		} else if err.Error() == "please wait before requesting another code" ||
+			err.Error() == "too many codes requested, try again later" {
 			status = http.StatusTooManyRequests
+		} else if err.Error() == "failed to deliver verification code" {
+			status = http.StatusBadGateway
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -155,8 +158,11 @@ func (r *verificationCodeRoutes) ResendVerificationCode(c *gin.Context) {
 	err := r.verificationCodeService.ResendVerificationCode(c.Request.Context(), phoneNumber, codeType)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "please wait before requesting another code" {
+		if err.Error() == "please wait before requesting another code" ||
+			err.Error() == "too many codes requested, try again later" {
 			status = http.StatusTooManyRequests
+		} else if err.Error() == "failed to deliver verification code" {
+			status = http.StatusBadGateway
 		}
 		c.JSON(status, &entities.ApiResponse{
 			Success: false,
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -22,7 +24,7 @@ func newWorkspaceRoutes(handler *gin.RouterGroup, workspaceService services.Work
 		auth:             auth,
 	}
 
-	// Workspace management endpoints
+	// Workspace management endpoints, deleting a workspace and managing members need a verified phone
 	workspaces := handler.Group("/workspaces")
 	workspaces.Use(r.auth.AuthRequired())
 	{
@@ -30,12 +32,67 @@ func newWorkspaceRoutes(handler *gin.RouterGroup, workspaceService services.Work
 		workspaces.POST("", r.CreateWorkspace)
 		workspaces.GET("/:id", r.GetWorkspaceByID)
 		workspaces.PUT("/:id", r.UpdateWorkspace)
-		workspaces.DELETE("/:id", r.DeleteWorkspace)
+		workspaces.DELETE("/:id", r.auth.VerifiedRequired(), r.DeleteWorkspace)
+		workspaces.POST("/:id/archive", r.ArchiveWorkspace)
+		workspaces.POST("/:id/unarchive", r.UnarchiveWorkspace)
+		workspaces.POST("/:id/switch", r.SwitchWorkspace)
+		workspaces.GET("/:id/settings", r.GetSettings)
+		workspaces.PATCH("/:id/settings", r.UpdateSettings)
+		workspaces.GET("/:id/members", r.GetMembers)
+		workspaces.POST("/:id/members", r.auth.VerifiedRequired(), r.AddMember)
+		workspaces.PUT("/:id/members/:user_id", r.auth.VerifiedRequired(), r.UpdateMemberRole)
+		workspaces.DELETE("/:id/members/:user_id", r.auth.VerifiedRequired(), r.RemoveMember)
+	}
+}
+
+// workspaceErrorStatus maps workspace service errors to HTTP status codes
+func workspaceErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "workspace member not found", "user not found":
+		return http.StatusNotFound
+	case "access denied to workspace", "access denied to workspace member", "the workspace owner cannot be changed":
+		return http.StatusForbidden
+	case "workspace with this name already exists",
+		"workspace name already in use",
+		"user is already a member of this workspace",
+		"workspace is archived",
+		"workspace is already archived",
+		"workspace is not archived":
+		return http.StatusConflict
+	case "workspace name is required",
+		"workspace type is required",
+		"currency ID is required",
+		"invalid budgeting mode",
+		"email or phone number is required",
+		"invalid role",
+		"the workspace owner cannot leave the workspace",
+		"invalid settings",
+		"week start day must be between 0 and 6",
+		"fiscal month start day must be between 1 and 28",
+		"invalid default payment method",
+		"auto categorize confidence must be between 0 and 1",
+		"approval amount threshold cannot be negative",
+		"approvals are only available for business workspaces",
+		"default account not found",
+		"default account must belong to a workspace member",
+		"approval users must be workspace members",
+		"unsupported template version",
+		"invalid template category",
+		"template category not found",
+		"budget name is required",
+		"budgeted amount must be greater than 0",
+		"invalid period type",
+		"period end must be after period start",
+		"budget scope is required",
+		"total spend budget cannot have scope filters":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
 	}
 }
 
 // @Summary Get all workspaces
-// @Description Get a list of workspaces with optional filtering
+// @Description Get the workspaces the authenticated user owns or is a member of, with the user's role
 // @Tags workspaces
 // @Accept json
 // @Produce json
@@ -90,6 +147,7 @@ func (r *workspaceRoutes) ListAllWorkspaces(c *gin.Context) {
 // @Param id path string true "Workspace ID"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /workspaces/{id} [get]
@@ -108,31 +166,167 @@ func (r *workspaceRoutes) GetWorkspaceByID(c *gin.Context) {
 		return
 	}
 
-	workspace, err := r.workspaceService.GetWorkspaceByID(c.Request.Context(), workspaceID)
+	workspace, err := r.workspaceService.GetWorkspaceByID(c.Request.Context(), userID, workspaceID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    workspace,
+	})
+}
+
+// @Summary Switch the active workspace
+// @Description Make a workspace the active workspace of the authenticated user, used when a request gives no workspace_id. Set make_default to also select it after login. Returns a new access token holding the workspace.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param input body entities.SwitchWorkspaceRequest false "Switch options"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/switch [post]
+func (r *workspaceRoutes) SwitchWorkspace(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	// The body is optional
+	var input entities.SwitchWorkspaceRequest
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response, err := r.workspaceService.SwitchWorkspace(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	// Check if the user owns this workspace
-	if workspace.CreatedBy != userID {
-		c.JSON(http.StatusForbidden, &entities.ApiResponse{
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    response,
+		Message: "Workspace switched successfully",
+	})
+}
+
+// @Summary Get workspace settings
+// @Description Get the typed settings of a workspace
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/settings [get]
+func (r *workspaceRoutes) GetSettings(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	settings, err := r.workspaceService.GetSettings(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
-			Error:   "access denied: you can only access your own workspaces",
+			Error:   err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
-		Data:    workspace,
+		Data:    settings,
+	})
+}
+
+// @Summary Update workspace settings
+// @Description Update some of the settings of a workspace, fields left out keep their value
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param input body entities.WorkspaceSettings true "Settings to change"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/settings [patch]
+func (r *workspaceRoutes) UpdateSettings(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	settings, err := r.workspaceService.UpdateSettings(c.Request.Context(), userID, workspaceID, patch)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    settings,
+		Message: "Workspace settings updated successfully",
 	})
 }
 
@@ -163,15 +357,7 @@ func (r *workspaceRoutes) CreateWorkspace(c *gin.Context) {
 
 	workspace, err := r.workspaceService.CreateWorkspace(c.Request.Context(), userID, &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace with this name already exists" {
-			status = http.StatusConflict
-		} else if err.Error() == "workspace name is required" ||
-			err.Error() == "workspace type is required" ||
-			err.Error() == "currency ID is required" {
-			status = http.StatusBadRequest
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -193,6 +379,7 @@ func (r *workspaceRoutes) CreateWorkspace(c *gin.Context) {
 // @Param input body entities.UpdateWorkspaceInput true "Updated workspace details"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
 // @Router /workspaces/{id} [put]
@@ -211,46 +398,131 @@ func (r *workspaceRoutes) UpdateWorkspace(c *gin.Context) {
 		return
 	}
 
-	// Check if workspace exists and user owns it
-	existingWorkspace, err := r.workspaceService.GetWorkspaceByID(c.Request.Context(), workspaceID)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+	var input entities.UpdateWorkspaceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	if existingWorkspace.CreatedBy != userID {
-		c.JSON(http.StatusForbidden, &entities.ApiResponse{
+	workspace, err := r.workspaceService.UpdateWorkspace(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
-			Error:   "access denied: you can only update your own workspaces",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	var input entities.UpdateWorkspaceInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    workspace,
+	})
+}
+
+// @Summary Delete a workspace
+// @Description Delete a workspace by its ID
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id} [delete]
+func (r *workspaceRoutes) DeleteWorkspace(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	err = r.workspaceService.DeleteWorkspace(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	workspace, err := r.workspaceService.UpdateWorkspace(c.Request.Context(), workspaceID, &input)
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Workspace deleted successfully",
+	})
+}
+
+// @Summary Archive a workspace
+// @Description Archive a workspace, archived workspaces are read-only until they are unarchived
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/archive [post]
+func (r *workspaceRoutes) ArchiveWorkspace(c *gin.Context) {
+	r.setArchived(c, true)
+}
+
+// @Summary Unarchive a workspace
+// @Description Unarchive a workspace so its members can change it again
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/unarchive [post]
+func (r *workspaceRoutes) UnarchiveWorkspace(c *gin.Context) {
+	r.setArchived(c, false)
+}
+
+func (r *workspaceRoutes) setArchived(c *gin.Context, archived bool) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	var workspace *entities.Workspace
+	message := "Workspace archived successfully"
+	if archived {
+		workspace, err = r.workspaceService.ArchiveWorkspace(c.Request.Context(), userID, workspaceID)
+	} else {
+		workspace, err = r.workspaceService.UnarchiveWorkspace(c.Request.Context(), userID, workspaceID)
+		message = "Workspace unarchived successfully"
+	}
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			status = http.StatusNotFound
-		} else if err.Error() == "workspace name already in use" {
-			status = http.StatusConflict
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -260,21 +532,23 @@ func (r *workspaceRoutes) UpdateWorkspace(c *gin.Context) {
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
 		Data:    workspace,
+		Message: message,
 	})
 }
 
-// @Summary Delete a workspace
-// @Description Delete a workspace by its ID
+// @Summary Get workspace members
+// @Description Get the active members of a workspace with their roles
 // @Tags workspaces
 // @Accept json
 // @Produce json
 // @Param id path string true "Workspace ID"
 // @Success 200 {object} entities.ApiResponse
 // @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
 // @Failure 404 {object} entities.ApiResponse
 // @Failure 500 {object} entities.ApiResponse
-// @Router /workspaces/{id} [delete]
-func (r *workspaceRoutes) DeleteWorkspace(c *gin.Context) {
+// @Router /workspaces/{id}/members [get]
+func (r *workspaceRoutes) GetMembers(c *gin.Context) {
 	userID, ok := GetAuthenticatedUserID(c)
 	if !ok {
 		return // Error response already sent by GetAuthenticatedUserID
@@ -289,35 +563,124 @@ func (r *workspaceRoutes) DeleteWorkspace(c *gin.Context) {
 		return
 	}
 
-	// Check if workspace exists and user owns it
-	existingWorkspace, err := r.workspaceService.GetWorkspaceByID(c.Request.Context(), workspaceID)
+	members, err := r.workspaceService.GetMembers(c.Request.Context(), userID, workspaceID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	if existingWorkspace.CreatedBy != userID {
-		c.JSON(http.StatusForbidden, &entities.ApiResponse{
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    members,
+	})
+}
+
+// @Summary Add a workspace member
+// @Description Add a registered user to a workspace by email or phone number. Owners and admins only, admins can add members and viewers.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param input body entities.AddWorkspaceMemberRequest true "Member details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/members [post]
+func (r *workspaceRoutes) AddMember(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	var input entities.AddWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
 			Success: false,
-			Error:   "access denied: you can only delete your own workspaces",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	err = r.workspaceService.DeleteWorkspace(c.Request.Context(), workspaceID)
+	member, err := r.workspaceService.AddMember(c.Request.Context(), userID, workspaceID, &input)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, &entities.ApiResponse{
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    member,
+	})
+}
+
+// @Summary Update a workspace member role
+// @Description Change the role of a member. The owner's role cannot be changed and admins only manage members and viewers.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param user_id path string true "User ID of the member"
+// @Param input body entities.UpdateWorkspaceMemberRequest true "New role"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/members/{user_id} [put]
+func (r *workspaceRoutes) UpdateMemberRole(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	memberUserID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid user ID format",
+		})
+		return
+	}
+
+	var input entities.UpdateWorkspaceMemberRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	member, err := r.workspaceService.UpdateMemberRole(c.Request.Context(), userID, workspaceID, memberUserID, &input)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
@@ -326,6 +689,57 @@ func (r *workspaceRoutes) DeleteWorkspace(c *gin.Context) {
 
 	c.JSON(http.StatusOK, &entities.ApiResponse{
 		Success: true,
-		Message: "Workspace deleted successfully",
+		Data:    member,
+	})
+}
+
+// @Summary Remove a workspace member
+// @Description Remove a member from a workspace. Any member except the owner can remove themselves to leave the workspace.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param user_id path string true "User ID of the member"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/members/{user_id} [delete]
+func (r *workspaceRoutes) RemoveMember(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	memberUserID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid user ID format",
+		})
+		return
+	}
+
+	if err := r.workspaceService.RemoveMember(c.Request.Context(), userID, workspaceID, memberUserID); err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Workspace member removed successfully",
 	})
 }
@@ -0,0 +1,323 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type workspaceInvitationRoutes struct {
+	invitationService services.WorkspaceInvitationService
+	auth              *middleware.AuthMiddleware
+}
+
+func newWorkspaceInvitationRoutes(handler *gin.RouterGroup, invitationService services.WorkspaceInvitationService, auth *middleware.AuthMiddleware) {
+	r := &workspaceInvitationRoutes{
+		invitationService: invitationService,
+		auth:              auth,
+	}
+
+	// Invitations sent from a workspace
+	workspaces := handler.Group("/workspaces")
+	workspaces.Use(r.auth.AuthRequired())
+	{
+		workspaces.POST("/:id/invitations", r.auth.VerifiedRequired(), r.CreateInvitation)
+		workspaces.GET("/:id/invitations", r.GetWorkspaceInvitations)
+		workspaces.DELETE("/:id/invitations/:invitation_id", r.RevokeInvitation)
+	}
+
+	// Invitations received by the authenticated user
+	invitations := handler.Group("/invitations")
+	invitations.Use(r.auth.AuthRequired())
+	{
+		invitations.GET("", r.GetMyInvitations)
+		invitations.POST("/accept", r.AcceptInvitation)
+		invitations.POST("/decline", r.DeclineInvitation)
+	}
+}
+
+// workspaceInvitationErrorStatus maps workspace invitation service errors to HTTP status codes
+func workspaceInvitationErrorStatus(err error) int {
+	switch err.Error() {
+	case "workspace not found", "invitation not found", "user not found":
+		return http.StatusNotFound
+	case "access denied to workspace", "access denied to workspace member", "invitation was sent to another user":
+		return http.StatusForbidden
+	case "user is already a member of this workspace",
+		"an invitation is already pending for this contact",
+		"invitation is no longer pending",
+		"workspace is archived":
+		return http.StatusConflict
+	case "invalid role", "invalid invitation token", "invitation has expired":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// @Summary Invite to a workspace
+// @Description Invite a user by email or WhatsApp phone number, or create a shareable invitation link when both are empty. The response includes the signed invitation token and link.
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param input body entities.CreateWorkspaceInvitationRequest true "Invitation details"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/invitations [post]
+func (r *workspaceInvitationRoutes) CreateInvitation(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	var input entities.CreateWorkspaceInvitationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	invitation, err := r.invitationService.CreateInvitation(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    invitation,
+	})
+}
+
+// @Summary Get pending workspace invitations
+// @Description Get the pending, unexpired invitations of a workspace
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/invitations [get]
+func (r *workspaceInvitationRoutes) GetWorkspaceInvitations(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	invitations, err := r.invitationService.GetWorkspaceInvitations(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    invitations,
+	})
+}
+
+// @Summary Revoke a workspace invitation
+// @Description Revoke a pending invitation, its token can no longer be used
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/invitations/{invitation_id} [delete]
+func (r *workspaceInvitationRoutes) RevokeInvitation(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid invitation ID format",
+		})
+		return
+	}
+
+	if err := r.invitationService.RevokeInvitation(c.Request.Context(), userID, workspaceID, invitationID); err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Invitation revoked successfully",
+	})
+}
+
+// @Summary Get my invitations
+// @Description Get the pending invitations sent to the authenticated user, by account, email or phone number
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.ApiResponse
+// @Failure 401 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /invitations [get]
+func (r *workspaceInvitationRoutes) GetMyInvitations(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	invitations, err := r.invitationService.GetMyInvitations(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    invitations,
+	})
+}
+
+// @Summary Accept an invitation
+// @Description Accept a workspace invitation with its token and join the workspace with the invited role
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Param input body entities.RespondWorkspaceInvitationRequest true "Invitation token"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /invitations/accept [post]
+func (r *workspaceInvitationRoutes) AcceptInvitation(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	var input entities.RespondWorkspaceInvitationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	member, err := r.invitationService.AcceptInvitation(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    member,
+		Message: "Invitation accepted successfully",
+	})
+}
+
+// @Summary Decline an invitation
+// @Description Decline a workspace invitation with its token
+// @Tags workspace-invitations
+// @Accept json
+// @Produce json
+// @Param input body entities.RespondWorkspaceInvitationRequest true "Invitation token"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /invitations/decline [post]
+func (r *workspaceInvitationRoutes) DeclineInvitation(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	var input entities.RespondWorkspaceInvitationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := r.invitationService.DeclineInvitation(c.Request.Context(), userID, &input); err != nil {
+		c.JSON(workspaceInvitationErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Message: "Invitation declined successfully",
+	})
+}
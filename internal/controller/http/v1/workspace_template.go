@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+)
+
+type workspaceTemplateRoutes struct {
+	workspaceTemplateService services.WorkspaceTemplateService
+	auth                     *middleware.AuthMiddleware
+}
+
+func newWorkspaceTemplateRoutes(handler *gin.RouterGroup, workspaceTemplateService services.WorkspaceTemplateService, auth *middleware.AuthMiddleware) {
+	r := &workspaceTemplateRoutes{
+		workspaceTemplateService: workspaceTemplateService,
+		auth:                     auth,
+	}
+
+	// Workspace template endpoints
+	workspaces := handler.Group("/workspaces")
+	workspaces.Use(r.auth.AuthRequired())
+	{
+		workspaces.POST("/import", r.ImportTemplate)
+		workspaces.GET("/:id/template", r.ExportTemplate)
+		workspaces.POST("/:id/clone", r.CloneWorkspace)
+	}
+}
+
+// @Summary Export a workspace template
+// @Description Export the settings, categories, tags and budgets of a workspace as a template document, without transactions. Budget periods are relative to the earliest budget.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/template [get]
+func (r *workspaceTemplateRoutes) ExportTemplate(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	template, err := r.workspaceTemplateService.ExportTemplate(c.Request.Context(), userID, workspaceID)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &entities.ApiResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// @Summary Import a workspace template
+// @Description Create a workspace owned by the authenticated user from a template. Categories and tags are matched by name, missing ones are created. Budgets are re-dated from the start date.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param input body entities.ImportWorkspaceTemplateRequest true "Template, optional name and start date"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/import [post]
+func (r *workspaceTemplateRoutes) ImportTemplate(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	var input entities.ImportWorkspaceTemplateRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	workspace, err := r.workspaceTemplateService.ImportTemplate(c.Request.Context(), userID, &input)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    workspace,
+		Message: "Workspace imported successfully",
+	})
+}
+
+// @Summary Clone a workspace
+// @Description Create a workspace owned by the authenticated user with the settings, categories, tags and budgets of a workspace, without transactions. Archived workspaces can be cloned. Budgets are re-dated from the start date.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param input body entities.CloneWorkspaceRequest true "Name, description and start date of the clone"
+// @Success 201 {object} entities.ApiResponse
+// @Failure 400 {object} entities.ApiResponse
+// @Failure 403 {object} entities.ApiResponse
+// @Failure 404 {object} entities.ApiResponse
+// @Failure 409 {object} entities.ApiResponse
+// @Failure 500 {object} entities.ApiResponse
+// @Router /workspaces/{id}/clone [post]
+func (r *workspaceTemplateRoutes) CloneWorkspace(c *gin.Context) {
+	userID, ok := GetAuthenticatedUserID(c)
+	if !ok {
+		return // Error response already sent by GetAuthenticatedUserID
+	}
+
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   "invalid workspace ID format",
+		})
+		return
+	}
+
+	var input entities.CloneWorkspaceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	workspace, err := r.workspaceTemplateService.CloneWorkspace(c.Request.Context(), userID, workspaceID, &input)
+	if err != nil {
+		c.JSON(workspaceErrorStatus(err), &entities.ApiResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &entities.ApiResponse{
+		Success: true,
+		Data:    workspace,
+		Message: "Workspace cloned successfully",
+	})
+}
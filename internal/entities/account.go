@@ -55,8 +55,13 @@ const (
 	AccountTypeSavings = 3
 	AccountTypeCash    = 4
 	AccountTypeShared  = 5
+	AccountTypeLoan    = 6
 )
 
+// LiabilityAccountTypes are the account types whose balance is money owed.
+// Their current balance is the outstanding amount, so spending from them increases it.
+var LiabilityAccountTypes = []int64{AccountTypeCredit, AccountTypeLoan}
+
 type AccountSimple struct {
 	AccountID        uuid.UUID `json:"account_id" db:"account_id"`
 	AccountName      string    `json:"account_name" db:"account_name"`
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEvent is a change made in a workspace. Every event is written to the audit log and the activity feed.
+type DomainEvent struct {
+	WorkspaceID  uuid.UUID  // uuid.Nil for changes to the actor's own data, e.g. categories, shown in all of the actor's workspaces
+	ActorID      *uuid.UUID // nil for changes made by the system
+	Action       string
+	ResourceType string
+	ResourceID   *uuid.UUID
+	ResourceName string   // description or name of the resource, shown in the summary
+	Amount       *float64 // amount of a transaction or budget, shown in the summary
+	Detail       int      // transaction type of a transaction
+	OldValues    interface{}
+	NewValues    interface{}
+	AIGenerated  bool // the resource was created or categorized by the AI
+}
+
+// AuditLog is the audit trail of a change, with the values before and after it
+type AuditLog struct {
+	AuditLogID   uuid.UUID   `json:"audit_log_id" db:"audit_log_id"`
+	WorkspaceID  *uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	UserID       *uuid.UUID  `json:"user_id" db:"user_id"`
+	Action       string      `json:"action" db:"action"`
+	ResourceType string      `json:"resource_type" db:"resource_type"`
+	ResourceID   *uuid.UUID  `json:"resource_id" db:"resource_id"`
+	OldValues    interface{} `json:"old_values" db:"old_values"`
+	NewValues    interface{} `json:"new_values" db:"new_values"`
+	SourceType   string      `json:"source_type" db:"source_type"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+}
+
+// WorkspaceActivity is an entry of the activity feed of a workspace
+type WorkspaceActivity struct {
+	ActivityID   uuid.UUID  `json:"activity_id" db:"activity_id"`
+	WorkspaceID  uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	ActorID      *uuid.UUID `json:"actor_id" db:"actor_id"`
+	ActorName    string     `json:"actor_name" db:"actor_name"`
+	Action       string     `json:"action" db:"action"`
+	ResourceType string     `json:"resource_type" db:"resource_type"`
+	ResourceID   *uuid.UUID `json:"resource_id" db:"resource_id"`
+	Channel      string     `json:"channel" db:"channel"`
+	AIGenerated  bool       `json:"ai_generated" db:"ai_generated"`
+	SummaryID    string     `json:"summary_id" db:"summary_id"` // Indonesian
+	SummaryEN    string     `json:"summary_en" db:"summary_en"` // English
+	AuditLogID   *uuid.UUID `json:"audit_log_id" db:"audit_log_id"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ActivityListParams filters the activity feed of a workspace
+type ActivityListParams struct {
+	WorkspaceID  uuid.UUID  `json:"workspace_id"`
+	ActorID      *uuid.UUID `json:"actor_id"`
+	ResourceType string     `json:"resource_type"`
+	Action       string     `json:"action"`
+	Channel      string     `json:"channel"`
+	AIGenerated  *bool      `json:"ai_generated"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+}
+
+// Constants for activity actions
+const (
+	ActivityActionCreated = "create"
+	ActivityActionUpdated = "update"
+	ActivityActionDeleted = "delete"
+	ActivityActionJoined  = "join"
+
+	// Expense approval actions
+	ActivityActionSubmitted  = "submit"
+	ActivityActionApproved   = "approve"
+	ActivityActionRejected   = "reject"
+	ActivityActionReimbursed = "reimburse"
+	ActivityActionPaid       = "pay"
+)
+
+// Constants for activity resource types
+const (
+	ActivityResourceTransaction   = "transaction"
+	ActivityResourceBudget        = "budget"
+	ActivityResourceCategory      = "category"
+	ActivityResourceMember        = "member"
+	ActivityResourceReimbursement = "reimbursement"
+	ActivityResourceSplit         = "transaction_split"
+)
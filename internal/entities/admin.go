@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Constants for system roles, which grant access to the administration API (v0). They are unrelated to workspace roles.
+const (
+	SystemRoleNone       = 0 // regular user, no access to the administration API
+	SystemRoleSuperAdmin = 1 // full control, including admin management and the audit log
+	SystemRoleSupport    = 2 // reads everything, creates and updates users
+	SystemRoleReadOnly   = 3 // reads everything
+)
+
+// AuditSourceAdmin is the source type of the audit logs of the administration API
+const AuditSourceAdmin = "admin"
+
+// AdminAuditLog is a request an admin made to the administration API, successful or not
+type AdminAuditLog struct {
+	AuditLogID   uuid.UUID          `json:"audit_log_id" db:"audit_log_id"`
+	AdminID      *uuid.UUID         `json:"admin_id" db:"user_id"` // nil once the admin's account is deleted
+	AdminName    string             `json:"admin_name" db:"admin_name"`
+	Action       string             `json:"action" db:"action"`
+	ResourceType string             `json:"resource_type" db:"resource_type"`
+	ResourceID   *uuid.UUID         `json:"resource_id" db:"resource_id"`
+	Request      *AdminAuditRequest `json:"request" db:"new_values"`
+	IPAddress    string             `json:"ip_address" db:"ip_address"`
+	UserAgent    string             `json:"user_agent" db:"user_agent"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+}
+
+// AdminAuditRequest is what an admin asked for and how it ended, kept in the new_values of the audit log
+type AdminAuditRequest struct {
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	Params     map[string]string      `json:"params,omitempty"`
+	Body       map[string]interface{} `json:"body,omitempty"` // secrets are redacted
+	StatusCode int                    `json:"status_code"`
+	SystemRole int                    `json:"system_role"`
+}
+
+// AdminAuditListParams filters the audit log of the administration API
+type AdminAuditListParams struct {
+	AdminID      *uuid.UUID `json:"admin_id"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   *uuid.UUID `json:"resource_id"`
+	Action       string     `json:"action"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+}
+
+// UpdateSystemRoleRequest grants a system role to a user
+type UpdateSystemRoleRequest struct {
+	SystemRole int `json:"system_role" binding:"required,min=1,max=3"`
+}
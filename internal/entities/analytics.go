@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Analytics breakdown dimensions
+const (
+	AnalyticsGroupByCategory      = "category"
+	AnalyticsGroupByMerchant      = "merchant"
+	AnalyticsGroupByAccount       = "account"
+	AnalyticsGroupByTag           = "tag"
+	AnalyticsGroupByPaymentMethod = "payment_method"
+)
+
+// Analytics time series intervals
+const (
+	AnalyticsIntervalDay   = "day"
+	AnalyticsIntervalWeek  = "week"
+	AnalyticsIntervalMonth = "month"
+)
+
+// AnalyticsQuery represents the scope of an analytics aggregate.
+// Amounts are converted into CurrencyID, Filters follow the transaction list filters.
+type AnalyticsQuery struct {
+	WorkspaceID     uuid.UUID
+	TransactionType int
+	CurrencyID      int
+	WeekStartDay    int // 0 (Sunday) to 6 (Saturday)
+	MonthStartDay   int // first day of the fiscal month
+	Filters         *TransactionListParams
+}
+
+// AnalyticsBreakdownRow represents one aggregated group as returned by the repository.
+// For the category breakdown the path holds the system category hierarchy from the root down.
+type AnalyticsBreakdownRow struct {
+	Key               string
+	Label             string
+	CategoryPathIDs   []string
+	CategoryPathNames []string
+	Amount            float64
+	TransactionCount  int
+	UnconvertedCount  int
+}
+
+// AnalyticsTotals represents the total amount and transaction count of an analytics query.
+// UnconvertedCount transactions are left out of the amount because no exchange rate was recorded for their currency.
+type AnalyticsTotals struct {
+	Amount           float64 `json:"amount"`
+	TransactionCount int     `json:"transaction_count"`
+	UnconvertedCount int     `json:"unconverted_count"`
+}
+
+// AnalyticsBreakdownItem represents one group of a breakdown, with its sub groups for the category hierarchy
+type AnalyticsBreakdownItem struct {
+	Key              string                    `json:"key"`
+	Label            string                    `json:"label"`
+	Amount           float64                   `json:"amount"`
+	TransactionCount int                       `json:"transaction_count"`
+	UnconvertedCount int                       `json:"unconverted_count"`
+	Percentage       float64                   `json:"percentage"`
+	Children         []*AnalyticsBreakdownItem `json:"children,omitempty"`
+}
+
+// AnalyticsBreakdown represents totals grouped by one dimension
+type AnalyticsBreakdown struct {
+	WorkspaceID      uuid.UUID                 `json:"workspace_id"`
+	GroupBy          string                    `json:"group_by"`
+	TransactionType  int                       `json:"transaction_type"`
+	CurrencyID       int                       `json:"currency_id"`
+	StartDate        *time.Time                `json:"start_date,omitempty"`
+	EndDate          *time.Time                `json:"end_date,omitempty"`
+	TotalAmount      float64                   `json:"total_amount"`
+	TransactionCount int                       `json:"transaction_count"`
+	UnconvertedCount int                       `json:"unconverted_count"` // transactions left out of the amounts
+	Items            []*AnalyticsBreakdownItem `json:"items"`
+}
+
+// AnalyticsTimeSeriesPoint represents the total of one period of a time series
+type AnalyticsTimeSeriesPoint struct {
+	PeriodStart              time.Time `json:"period_start"`
+	Amount                   float64   `json:"amount"`
+	TransactionCount         int       `json:"transaction_count"`
+	UnconvertedCount         int       `json:"unconverted_count"`
+	PreviousPeriodStart      time.Time `json:"previous_period_start"`
+	PreviousAmount           float64   `json:"previous_amount"`
+	PreviousTransactionCount int       `json:"previous_transaction_count"`
+	PreviousUnconvertedCount int       `json:"previous_unconverted_count"`
+	Change                   float64   `json:"change"`
+	ChangePercentage         *float64  `json:"change_percentage"`
+}
+
+// AnalyticsTimeSeries represents totals per period compared with the previous period of the same length
+type AnalyticsTimeSeries struct {
+	WorkspaceID              uuid.UUID                   `json:"workspace_id"`
+	Interval                 string                      `json:"interval"`
+	TransactionType          int                         `json:"transaction_type"`
+	CurrencyID               int                         `json:"currency_id"`
+	StartDate                time.Time                   `json:"start_date"`
+	EndDate                  time.Time                   `json:"end_date"`
+	PreviousStartDate        time.Time                   `json:"previous_start_date"`
+	PreviousEndDate          time.Time                   `json:"previous_end_date"`
+	TotalAmount              float64                     `json:"total_amount"`
+	PreviousTotalAmount      float64                     `json:"previous_total_amount"`
+	UnconvertedCount         int                         `json:"unconverted_count"` // transactions left out of the amounts
+	PreviousUnconvertedCount int                         `json:"previous_unconverted_count"`
+	ChangePercentage         *float64                    `json:"change_percentage"`
+	Points                   []*AnalyticsTimeSeriesPoint `json:"points"`
+}
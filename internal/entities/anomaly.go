@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SpendingAnomaly represents a transaction flagged as unusual for its user
+type SpendingAnomaly struct {
+	SpendingAnomalyID uuid.UUID  `json:"spending_anomaly_id" db:"spending_anomaly_id"`
+	TransactionID     uuid.UUID  `json:"transaction_id" db:"transaction_id"`
+	WorkspaceID       uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	AnomalyType       int        `json:"anomaly_type" db:"anomaly_type"`
+	Score             float64    `json:"score" db:"score"`
+	ExpectedValue     float64    `json:"expected_value" db:"expected_value"`
+	ActualValue       float64    `json:"actual_value" db:"actual_value"`
+	Status            int        `json:"status" db:"status"`
+	DismissedAt       *time.Time `json:"dismissed_at" db:"dismissed_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Transaction details, filled when listing anomalies
+	Description     string     `json:"description,omitempty"`
+	MerchantName    *string    `json:"merchant_name,omitempty"`
+	CategoryID      *uuid.UUID `json:"category_id,omitempty"`
+	Amount          float64    `json:"amount,omitempty"`
+	TransactionDate *time.Time `json:"transaction_date,omitempty"`
+}
+
+// AnomalyCandidate is an expense waiting to be checked, with its amount converted into the currency of the user
+// and the hour it was recorded at in the user's timezone
+type AnomalyCandidate struct {
+	TransactionID     uuid.UUID
+	WorkspaceID       uuid.UUID
+	UserID            uuid.UUID
+	TransactionType   int
+	TransferAccountID *uuid.UUID
+	CategoryID        *uuid.UUID
+	MerchantID        *uuid.UUID
+	MerchantName      *string
+	Description       string
+	Amount            float64
+	Unconverted       bool // no exchange rate into the user currency is recorded, the amount cannot be compared
+	TransactionDate   time.Time
+	Hour              int
+}
+
+// AnomalyBaseline holds the recent spending history of a user a candidate is compared against.
+// Transactions with an open anomaly are left out, dismissed ones are included again.
+type AnomalyBaseline struct {
+	CategoryAmounts        []float64 // past expense amounts in the candidate's category
+	ExpenseAmounts         []float64 // past expense amounts in any category
+	MerchantExpenseCount   int       // earlier expenses at the candidate's merchant
+	HourCounts             [24]int   // expenses per hour of the day they were recorded at
+	CategoryWeeklyTotals   []float64 // category spending of the weeks before the candidate's week, most recent first
+	CategoryWeekSpentSoFar float64   // category spending in the candidate's week, without the candidate
+}
+
+// Anomaly types
+const (
+	AnomalyTypeCategoryOutlier = 1
+	AnomalyTypeNewMerchant     = 2 // first expense at a merchant with a large amount
+	AnomalyTypeUnusualHour     = 3
+	AnomalyTypeCategorySpike   = 4
+)
+
+// Anomaly statuses
+const (
+	AnomalyStatusOpen      = 1
+	AnomalyStatusDismissed = 2
+)
@@ -1,6 +1,9 @@
 package entities
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,57 +11,111 @@ import (
 
 // Budget represents a spending budget
 type Budget struct {
-	BudgetID       uuid.UUID `json:"budget_id" db:"budget_id"`
-	WorkspaceID    uuid.UUID `json:"workspace_id" db:"workspace_id"`
-	UserCategoryID uuid.UUID `json:"user_category_id" db:"user_category_id"`
-	Name           string    `json:"name" db:"name"`
-	BudgetedAmount float64   `json:"budgeted_amount" db:"budgeted_amount"`
-	PeriodType     int       `json:"period_type" db:"period_type"`
-	PeriodStart    time.Time `json:"period_start" db:"period_start"`
-	PeriodEnd      time.Time `json:"period_end" db:"period_end"`
-	SpentAmount    float64   `json:"spent_amount" db:"spent_amount"`
-	IsActive       bool      `json:"is_active" db:"is_active"`
-	CreatedBy      uuid.UUID `json:"created_by" db:"created_by"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	BudgetID       uuid.UUID   `json:"budget_id" db:"budget_id"`
+	WorkspaceID    uuid.UUID   `json:"workspace_id" db:"workspace_id"`
+	UserCategoryID uuid.UUID   `json:"user_category_id" db:"user_category_id"`
+	Name           string      `json:"name" db:"name"`
+	BudgetedAmount float64     `json:"budgeted_amount" db:"budgeted_amount"`
+	PeriodType     int         `json:"period_type" db:"period_type"`
+	PeriodStart    time.Time   `json:"period_start" db:"period_start"`
+	PeriodEnd      time.Time   `json:"period_end" db:"period_end"`
+	SpentAmount    float64     `json:"spent_amount" db:"spent_amount"`
+	Scope          BudgetScope `json:"scope" db:"scope"`
+	IsActive       bool        `json:"is_active" db:"is_active"`
+	CreatedBy      uuid.UUID   `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// BudgetScope defines which expenses count toward a budget.
+// Values within one dimension are alternatives (any category in CategoryIDs),
+// while every non-empty dimension must match (category AND tag AND account AND merchant).
+// TotalSpend counts every expense in the workspace and cannot be combined with filters.
+type BudgetScope struct {
+	CategoryIDs []uuid.UUID `json:"category_ids,omitempty"`
+	TagIDs      []uuid.UUID `json:"tag_ids,omitempty"`
+	AccountIDs  []uuid.UUID `json:"account_ids,omitempty"`
+	Merchants   []string    `json:"merchants,omitempty"`
+	TotalSpend  bool        `json:"total_spend"`
+}
+
+// HasFilters reports whether the scope narrows down the counted transactions
+func (s BudgetScope) HasFilters() bool {
+	return len(s.CategoryIDs) > 0 || len(s.TagIDs) > 0 || len(s.AccountIDs) > 0 || len(s.Merchants) > 0
+}
+
+// Value implements driver.Valuer so the scope is stored as JSONB
+func (s BudgetScope) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner for the JSONB scope column
+func (s *BudgetScope) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s = BudgetScope{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return errors.New("unsupported type for budget scope")
+	}
 }
 
 type BudgetSimple struct {
-	BudgetID         uuid.UUID `json:"budget_id" db:"budget_id"`
-	UserCategoryName string    `json:"user_category_name" db:"user_category_name"`
-	Name             string    `json:"name" db:"name"`
-	BudgetedAmount   float64   `json:"budgeted_amount" db:"budgeted_amount"`
-	PeriodTypeLabel  string    `json:"period_type_label" db:"period_type_label"`
-	PeriodStart      time.Time `json:"period_start" db:"period_start"`
-	PeriodEnd        time.Time `json:"period_end" db:"period_end"`
-	SpentAmount      float64   `json:"spent_amount" db:"spent_amount"`
-	RemainingAmount  float64   `json:"remaining_amount" db:"remaining_amount"`
-	PercentageUsed   float64   `json:"percentage_used" db:"percentage_used"`
-	DaysRemaining    int       `json:"days_remaining" db:"days_remaining"`
-	IsOverspent      bool      `json:"is_overspent" db:"is_overspent"`
+	BudgetID         uuid.UUID   `json:"budget_id" db:"budget_id"`
+	UserCategoryName string      `json:"user_category_name" db:"user_category_name"`
+	Name             string      `json:"name" db:"name"`
+	BudgetedAmount   float64     `json:"budgeted_amount" db:"budgeted_amount"`
+	PeriodTypeLabel  string      `json:"period_type_label" db:"period_type_label"`
+	PeriodStart      time.Time   `json:"period_start" db:"period_start"`
+	PeriodEnd        time.Time   `json:"period_end" db:"period_end"`
+	Scope            BudgetScope `json:"scope" db:"scope"`
+	SpentAmount      float64     `json:"spent_amount" db:"spent_amount"`
+	PendingAmount    float64     `json:"pending_amount" db:"pending_amount"` // expenses in scope waiting for approval, not in SpentAmount
+	TransactionCount int         `json:"transaction_count" db:"transaction_count"`
+	RemainingAmount  float64     `json:"remaining_amount" db:"remaining_amount"`
+	PercentageUsed   float64     `json:"percentage_used" db:"percentage_used"`
+	DaysRemaining    int         `json:"days_remaining" db:"days_remaining"`
+	IsOverspent      bool        `json:"is_overspent" db:"is_overspent"`
+	AlertLevel       string      `json:"alert_level" db:"alert_level"`
+}
+
+// BudgetTransaction is a transaction that contributed to a budget's spent amount
+type BudgetTransaction struct {
+	TransactionID   uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	Description     string    `json:"description" db:"description"`
+	Amount          float64   `json:"amount" db:"amount"`
+	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
+	MerchantName    *string   `json:"merchant_name" db:"merchant_name"`
+	CategoryName    *string   `json:"category_name" db:"category_name"`
+	AccountName     *string   `json:"account_name" db:"account_name"`
 }
 
 // CreateBudgetRequest represents the create budget request
 type CreateBudgetRequest struct {
-	WorkspaceID    uuid.UUID `json:"workspace_id"`
-	UserCategoryID uuid.UUID `json:"user_category_id"`
-	Name           string    `json:"name" binding:"required"`
-	BudgetedAmount float64   `json:"budgeted_amount" binding:"required"`
-	PeriodType     int       `json:"period_type" binding:"required"`
-	PeriodStart    time.Time `json:"period_start" binding:"required" time:"2006-01-02"`
-	PeriodEnd      time.Time `json:"period_end" binding:"required" time:"2006-01-02"`
-	CreatedBy      uuid.UUID `json:"created_by" db:"created_by"`
+	WorkspaceID    uuid.UUID   `json:"workspace_id"`
+	UserCategoryID uuid.UUID   `json:"user_category_id"`
+	Scope          BudgetScope `json:"scope"`
+	Name           string      `json:"name" binding:"required"`
+	BudgetedAmount float64     `json:"budgeted_amount" binding:"required"`
+	PeriodType     int         `json:"period_type" binding:"required"`
+	PeriodStart    time.Time   `json:"period_start" binding:"required" time:"2006-01-02"`
+	PeriodEnd      time.Time   `json:"period_end" binding:"required" time:"2006-01-02"`
+	CreatedBy      uuid.UUID   `json:"created_by" db:"created_by"`
 }
 
 type UpdateBudgetRequest struct {
-	UserCategoryID uuid.UUID `json:"user_category_id"`
-	Name           string    `json:"name" binding:"required"`
-	BudgetedAmount float64   `json:"budgeted_amount" binding:"required"`
-	PeriodType     int       `json:"period_type" binding:"required"`
-	PeriodStart    time.Time `json:"period_start" binding:"required" time:"2006-01-02"`
-	PeriodEnd      time.Time `json:"period_end" binding:"required" time:"2006-01-02"`
-	SpentAmount    float64   `json:"spent_amount" binding:"required"`
-	IsActive       bool      `json:"is_active" binding:"required"`
+	UserCategoryID uuid.UUID   `json:"user_category_id"`
+	Scope          BudgetScope `json:"scope"`
+	Name           string      `json:"name" binding:"required"`
+	BudgetedAmount float64     `json:"budgeted_amount" binding:"required"`
+	PeriodType     int         `json:"period_type" binding:"required"`
+	PeriodStart    time.Time   `json:"period_start" binding:"required" time:"2006-01-02"`
+	PeriodEnd      time.Time   `json:"period_end" binding:"required" time:"2006-01-02"`
+	IsActive       bool        `json:"is_active" binding:"required"`
 }
 
 // Constants for period types
@@ -68,3 +125,10 @@ const (
 	PeriodTypeYearly  = 3
 	PeriodTypeOneTime = 4
 )
+
+// Constants for budget alert levels
+const (
+	BudgetAlertLevelOK       = "ok"
+	BudgetAlertLevelWarning  = "warning"  // 80% or more of the budget used
+	BudgetAlertLevelExceeded = "exceeded" // spent more than budgeted
+)
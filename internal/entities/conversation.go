@@ -34,6 +34,11 @@ type UpdateConversationRequest struct {
 	IsActive bool    `json:"is_active"`
 }
 
+// SetConversationWorkspaceRequest switches the workspace a conversation works in
+type SetConversationWorkspaceRequest struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" binding:"required"`
+}
+
 // ConversationSimple represents a simplified conversation for listing
 type ConversationSimple struct {
 	ConversationID uuid.UUID `json:"conversation_id" db:"conversation_id"`
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsolidatedBudgetStatus represents the combined status of the active budgets in a period.
+// UnconvertedCount budgets are left out of the amounts because no exchange rate was recorded for their currency.
+type ConsolidatedBudgetStatus struct {
+	BudgetCount      int     `json:"budget_count"`
+	OverspentCount   int     `json:"overspent_count"`
+	BudgetedAmount   float64 `json:"budgeted_amount"`
+	SpentAmount      float64 `json:"spent_amount"`
+	RemainingAmount  float64 `json:"remaining_amount"`
+	PercentageUsed   float64 `json:"percentage_used"`
+	UnconvertedCount int     `json:"unconverted_count"`
+}
+
+// ConsolidatedWorkspaceSummary represents the totals of one workspace in the consolidated dashboard,
+// converted into the user currency. UnconvertedCount transactions are left out of the totals because no exchange rate
+// was recorded for their currency.
+type ConsolidatedWorkspaceSummary struct {
+	WorkspaceID      uuid.UUID                `json:"workspace_id"`
+	WorkspaceName    string                   `json:"workspace_name"`
+	WorkspaceType    int                      `json:"workspace_type"`
+	CurrencyID       int                      `json:"currency_id"`
+	TotalIncome      float64                  `json:"total_income"`
+	TotalExpense     float64                  `json:"total_expense"`
+	NetCashFlow      float64                  `json:"net_cash_flow"`
+	TransactionCount int                      `json:"transaction_count"`
+	UnconvertedCount int                      `json:"unconverted_count"`
+	Budget           ConsolidatedBudgetStatus `json:"budget"`
+}
+
+// ConsolidatedDashboard represents the financial summary of a user across workspaces, in the user currency
+type ConsolidatedDashboard struct {
+	CurrencyID           int                             `json:"currency_id"`
+	CurrencyCode         string                          `json:"currency_code"`
+	CurrencySymbol       string                          `json:"currency_symbol"`
+	StartDate            time.Time                       `json:"start_date"`
+	EndDate              time.Time                       `json:"end_date"`
+	ExcludedWorkspaceIDs []uuid.UUID                     `json:"excluded_workspace_ids"`
+	TotalIncome          float64                         `json:"total_income"`
+	TotalExpense         float64                         `json:"total_expense"`
+	NetCashFlow          float64                         `json:"net_cash_flow"`
+	SavingsRate          *float64                        `json:"savings_rate"`
+	UnconvertedCount     int                             `json:"unconverted_count"` // transactions left out of the totals
+	Budget               ConsolidatedBudgetStatus        `json:"budget"`
+	Accounts             *NetWorthBreakdown              `json:"accounts"`
+	Workspaces           []*ConsolidatedWorkspaceSummary `json:"workspaces"`
+}
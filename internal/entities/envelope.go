@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnvelopeAllocation represents money assigned to an envelope (user category) for a month
+type EnvelopeAllocation struct {
+	AllocationID    uuid.UUID `json:"allocation_id" db:"allocation_id"`
+	WorkspaceID     uuid.UUID `json:"workspace_id" db:"workspace_id"`
+	UserCategoryID  uuid.UUID `json:"user_category_id" db:"user_category_id"`
+	AllocationMonth time.Time `json:"allocation_month" db:"allocation_month"`
+	Amount          float64   `json:"amount" db:"amount"`
+	CreatedBy       uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// EnvelopeTransfer represents money moved from one envelope to another
+type EnvelopeTransfer struct {
+	TransferID         uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	WorkspaceID        uuid.UUID `json:"workspace_id" db:"workspace_id"`
+	FromUserCategoryID uuid.UUID `json:"from_user_category_id" db:"from_user_category_id"`
+	ToUserCategoryID   uuid.UUID `json:"to_user_category_id" db:"to_user_category_id"`
+	TransferMonth      time.Time `json:"transfer_month" db:"transfer_month"`
+	Amount             float64   `json:"amount" db:"amount"`
+	TransferType       int       `json:"transfer_type" db:"transfer_type"`
+	Note               *string   `json:"note" db:"note"`
+	CreatedBy          uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+// EnvelopeTransferSimple is an envelope transfer with the envelope names for history listing
+type EnvelopeTransferSimple struct {
+	TransferID       uuid.UUID `json:"transfer_id" db:"transfer_id"`
+	FromEnvelopeName string    `json:"from_envelope_name" db:"from_envelope_name"`
+	ToEnvelopeName   string    `json:"to_envelope_name" db:"to_envelope_name"`
+	TransferMonth    time.Time `json:"transfer_month" db:"transfer_month"`
+	Amount           float64   `json:"amount" db:"amount"`
+	TransferType     int       `json:"transfer_type" db:"transfer_type"`
+	Note             *string   `json:"note" db:"note"`
+	CreatedBy        uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// EnvelopeMonthlyActivity is the assigned, transferred and spent amount of an envelope in one month
+type EnvelopeMonthlyActivity struct {
+	UserCategoryID uuid.UUID `json:"user_category_id" db:"user_category_id"`
+	EnvelopeName   string    `json:"envelope_name" db:"envelope_name"`
+	Month          time.Time `json:"month" db:"month"`
+	Assigned       float64   `json:"assigned" db:"assigned"`
+	TransferredIn  float64   `json:"transferred_in" db:"transferred_in"`
+	TransferredOut float64   `json:"transferred_out" db:"transferred_out"`
+	Spent          float64   `json:"spent" db:"spent"`
+}
+
+// EnvelopeMonthlyIncome is the income received by a workspace in one month
+type EnvelopeMonthlyIncome struct {
+	Month  time.Time `json:"month" db:"month"`
+	Amount float64   `json:"amount" db:"amount"`
+}
+
+// EnvelopeBalance is the state of one envelope in a month
+type EnvelopeBalance struct {
+	UserCategoryID uuid.UUID `json:"user_category_id"`
+	EnvelopeName   string    `json:"envelope_name"`
+	CarriedOver    float64   `json:"carried_over"`
+	Assigned       float64   `json:"assigned"`
+	TransferredIn  float64   `json:"transferred_in"`
+	TransferredOut float64   `json:"transferred_out"`
+	Spent          float64   `json:"spent"`
+	Available      float64   `json:"available"`
+	IsOverspent    bool      `json:"is_overspent"`
+}
+
+// EnvelopeMonthSummary is the envelope budget of a workspace for one month
+type EnvelopeMonthSummary struct {
+	WorkspaceID       uuid.UUID          `json:"workspace_id"`
+	Month             time.Time          `json:"month"`
+	Income            float64            `json:"income"`
+	AvailableToAssign float64            `json:"available_to_assign"`
+	Assigned          float64            `json:"assigned"`
+	Spent             float64            `json:"spent"`
+	OverspentAmount   float64            `json:"overspent_amount"`   // overspending in this month that still has to be covered
+	UncoveredPrevious float64            `json:"uncovered_previous"` // overspending left uncovered in earlier months, taken from the pool
+	Envelopes         []*EnvelopeBalance `json:"envelopes"`
+}
+
+// AllocateEnvelopeRequest sets the amount assigned to an envelope for a month
+type AllocateEnvelopeRequest struct {
+	UserCategoryID uuid.UUID `json:"user_category_id" binding:"required"`
+	Month          string    `json:"month" binding:"required"` // YYYY-MM
+	Amount         float64   `json:"amount"`
+}
+
+// EnvelopeTransferRequest moves money between envelopes
+type EnvelopeTransferRequest struct {
+	FromUserCategoryID uuid.UUID `json:"from_user_category_id" binding:"required"`
+	ToUserCategoryID   uuid.UUID `json:"to_user_category_id" binding:"required"`
+	Month              string    `json:"month" binding:"required"` // YYYY-MM
+	Amount             float64   `json:"amount"`
+	Note               *string   `json:"note"`
+}
+
+// Constants for envelope transfer types
+const (
+	EnvelopeTransferTypeMove  = 1
+	EnvelopeTransferTypeCover = 2 // covers the overspending of the receiving envelope
+)
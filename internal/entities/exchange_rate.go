@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExchangeRate represents the rate to convert one currency into another from an effective date
+type ExchangeRate struct {
+	ExchangeRateID uuid.UUID `json:"exchange_rate_id" db:"exchange_rate_id"`
+	FromCurrencyID int       `json:"from_currency_id" db:"from_currency_id"`
+	ToCurrencyID   int       `json:"to_currency_id" db:"to_currency_id"`
+	Rate           float64   `json:"rate" db:"rate"`
+	EffectiveDate  time.Time `json:"effective_date" db:"effective_date"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpsertExchangeRateInput represents the input to set an exchange rate for a date.
+// EffectiveDate is in YYYY-MM-DD format and defaults to today.
+type UpsertExchangeRateInput struct {
+	FromCurrencyID int     `json:"from_currency_id" binding:"required"`
+	ToCurrencyID   int     `json:"to_currency_id" binding:"required"`
+	Rate           float64 `json:"rate" binding:"required"`
+	EffectiveDate  string  `json:"effective_date"`
+}
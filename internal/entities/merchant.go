@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Merchant represents a canonical merchant in the merchant directory
+type Merchant struct {
+	MerchantID        uuid.UUID  `json:"merchant_id" db:"merchant_id"`
+	Name              string     `json:"name" db:"name"`
+	DefaultCategoryID *uuid.UUID `json:"default_category_id" db:"default_category_id"`
+	LogoURL           *string    `json:"logo_url" db:"logo_url"`
+	IsActive          bool       `json:"is_active" db:"is_active"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MerchantAlias represents a rule matching normalized merchant text to a merchant.
+// Aliases without a user are global, the others only apply to their user.
+type MerchantAlias struct {
+	MerchantAliasID uuid.UUID  `json:"merchant_alias_id" db:"merchant_alias_id"`
+	MerchantID      uuid.UUID  `json:"merchant_id" db:"merchant_id"`
+	UserID          *uuid.UUID `json:"user_id" db:"user_id"`
+	Pattern         string     `json:"pattern" db:"pattern"`
+	MatchType       int        `json:"match_type" db:"match_type"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MerchantMatch is the outcome of normalizing merchant text
+type MerchantMatch struct {
+	Merchant       *Merchant      `json:"merchant"`
+	Alias          *MerchantAlias `json:"alias"`
+	UserCategoryID *uuid.UUID     `json:"user_category_id"`
+}
+
+// CreateMerchantRequest represents the create merchant request
+type CreateMerchantRequest struct {
+	Name              string     `json:"name" binding:"required"`
+	DefaultCategoryID *uuid.UUID `json:"default_category_id"`
+	LogoURL           *string    `json:"logo_url"`
+}
+
+// UpdateMerchantRequest represents the update merchant request
+type UpdateMerchantRequest struct {
+	Name              string     `json:"name" binding:"required"`
+	DefaultCategoryID *uuid.UUID `json:"default_category_id"`
+	LogoURL           *string    `json:"logo_url"`
+	IsActive          bool       `json:"is_active"`
+}
+
+// CreateMerchantAliasRequest represents the create merchant alias request
+type CreateMerchantAliasRequest struct {
+	MerchantID uuid.UUID `json:"merchant_id"`
+	Pattern    string    `json:"pattern" binding:"required"`
+	MatchType  int       `json:"match_type"`
+}
+
+// Constants for merchant alias match types
+const (
+	MerchantMatchExact    = 1
+	MerchantMatchPrefix   = 2
+	MerchantMatchContains = 3
+)
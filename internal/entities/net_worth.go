@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountBalanceSnapshot represents the balance of an account at the end of a day,
+// in the account currency and in the currency of the account owner. UserBalance is nil when no exchange rate
+// was recorded on the snapshot date.
+type AccountBalanceSnapshot struct {
+	AccountBalanceSnapshotID uuid.UUID `json:"account_balance_snapshot_id" db:"account_balance_snapshot_id"`
+	AccountID                uuid.UUID `json:"account_id" db:"account_id"`
+	UserID                   uuid.UUID `json:"user_id" db:"user_id"`
+	SnapshotDate             time.Time `json:"snapshot_date" db:"snapshot_date"`
+	AccountType              int       `json:"account_type" db:"account_type"`
+	Balance                  float64   `json:"balance" db:"balance"`
+	CurrencyID               int       `json:"currency_id" db:"currency_id"`
+	UserBalance              *float64  `json:"user_balance" db:"user_balance"`
+	UserCurrencyID           int       `json:"user_currency_id" db:"user_currency_id"`
+	CreatedAt                time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NetWorthPoint represents the assets, liabilities and net worth at the end of a day.
+// UnconvertedCount accounts are left out because no exchange rate was recorded for their currency.
+type NetWorthPoint struct {
+	Date             time.Time `json:"date"`
+	Assets           float64   `json:"assets"`
+	Liabilities      float64   `json:"liabilities"`
+	NetWorth         float64   `json:"net_worth"`
+	UnconvertedCount int       `json:"unconverted_count"`
+}
+
+// NetWorthAccount represents the current balance of one account in the net worth breakdown.
+// ConvertedBalance is nil when no exchange rate is recorded for the account currency.
+type NetWorthAccount struct {
+	AccountID        uuid.UUID `json:"account_id"`
+	AccountName      string    `json:"account_name"`
+	AccountType      int       `json:"account_type"`
+	Balance          float64   `json:"balance"`
+	CurrencyID       int       `json:"currency_id"`
+	ConvertedBalance *float64  `json:"converted_balance"`
+}
+
+// NetWorthBreakdown represents the current assets and liabilities per account.
+// Accounts without a converted balance are listed but left out of the totals, UnconvertedCount counts them.
+type NetWorthBreakdown struct {
+	Assets           []*NetWorthAccount `json:"assets"`
+	Liabilities      []*NetWorthAccount `json:"liabilities"`
+	TotalAssets      float64            `json:"total_assets"`
+	TotalLiabilities float64            `json:"total_liabilities"`
+	NetWorth         float64            `json:"net_worth"`
+	UnconvertedCount int                `json:"unconverted_count"`
+}
+
+// NetWorth represents the net worth of a user over time and right now, in the user currency
+type NetWorth struct {
+	CurrencyID       int                `json:"currency_id"`
+	CurrencyCode     string             `json:"currency_code"`
+	CurrencySymbol   string             `json:"currency_symbol"`
+	Interval         string             `json:"interval"`
+	StartDate        time.Time          `json:"start_date"`
+	EndDate          time.Time          `json:"end_date"`
+	Series           []*NetWorthPoint   `json:"series"`
+	Change           float64            `json:"change"`
+	ChangePercentage *float64           `json:"change_percentage"`
+	Current          *NetWorthBreakdown `json:"current"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification represents an in-app notification of a user
+type Notification struct {
+	NotificationID   uuid.UUID              `json:"notification_id" db:"notification_id"`
+	UserID           uuid.UUID              `json:"user_id" db:"user_id"`
+	NotificationType string                 `json:"notification_type" db:"notification_type"`
+	Title            string                 `json:"title" db:"title"`
+	Body             string                 `json:"body" db:"body"`
+	Data             map[string]interface{} `json:"data" db:"data"`
+	ReadAt           *time.Time             `json:"read_at" db:"read_at"`
+	CreatedAt        time.Time              `json:"created_at" db:"created_at"`
+}
+
+// Notification types
+const (
+	NotificationTypeSpendingAnomaly     = "spending_anomaly"
+	NotificationTypeWorkspaceInvitation = "workspace_invitation"
+	NotificationTypeTransactionMention  = "transaction_mention"
+	NotificationTypeApprovalRequest     = "approval_request"
+	NotificationTypeApprovalDecision    = "approval_decision"
+	NotificationTypeReimbursementClaim  = "reimbursement_claim"
+)
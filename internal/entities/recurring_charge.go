@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringChargeTransaction is an expense of the history recurring charges are detected from,
+// with its amount converted into the workspace currency
+type RecurringChargeTransaction struct {
+	TransactionID   uuid.UUID
+	MerchantID      *uuid.UUID
+	MerchantName    *string
+	Description     string
+	CategoryID      *uuid.UUID
+	AccountID       *uuid.UUID
+	Amount          float64
+	TransactionDate time.Time
+	IsRecurring     bool
+}
+
+// RecurringCharge represents a subscription or bill detected from the transaction history:
+// the same merchant charging a similar amount at a regular interval
+type RecurringCharge struct {
+	MerchantKey         string     `json:"merchant_key"`
+	MerchantID          *uuid.UUID `json:"merchant_id"`
+	MerchantName        string     `json:"merchant_name"`
+	CategoryID          *uuid.UUID `json:"category_id"`
+	AccountID           *uuid.UUID `json:"account_id"`
+	RecurrenceInterval  int        `json:"recurrence_interval"`
+	Occurrences         int        `json:"occurrences"`
+	AverageAmount       float64    `json:"average_amount"`
+	LatestAmount        float64    `json:"latest_amount"`
+	PreviousAmount      float64    `json:"previous_amount"`
+	PriceChange         *float64   `json:"price_change_percentage"` // set when the latest charge is higher than the one before
+	MonthlyCost         float64    `json:"monthly_cost"`
+	FirstChargeDate     time.Time  `json:"first_charge_date"`
+	LastChargeDate      time.Time  `json:"last_charge_date"`
+	NextExpectedDate    time.Time  `json:"next_expected_date"`
+	DaysOverdue         int        `json:"days_overdue"`
+	Status              int        `json:"status"`
+	IsTracked           bool       `json:"is_tracked"` // already recorded as a recurring transaction
+	LatestTransactionID uuid.UUID  `json:"latest_transaction_id"`
+}
+
+// RecurringChargeSummary represents the recurring charges of a workspace
+type RecurringChargeSummary struct {
+	WorkspaceID        uuid.UUID          `json:"workspace_id"`
+	CurrencyID         int                `json:"currency_id"`
+	TotalMonthlyCost   float64            `json:"total_monthly_cost"`
+	ActiveCount        int                `json:"active_count"`
+	LateCount          int                `json:"late_count"`
+	MissedCount        int                `json:"missed_count"`
+	PriceIncreaseCount int                `json:"price_increase_count"`
+	UntrackedCount     int                `json:"untracked_count"`
+	Charges            []*RecurringCharge `json:"charges"`
+}
+
+// TrackRecurringChargeRequest represents the request to record a detected charge as a recurring transaction
+type TrackRecurringChargeRequest struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	MerchantKey string    `json:"merchant_key" binding:"required"`
+}
+
+// Recurring charge statuses
+const (
+	RecurringChargeStatusActive = 1
+	RecurringChargeStatusLate   = 2 // the expected charge is a few days overdue
+	RecurringChargeStatusMissed = 3 // a whole interval passed without a charge, likely cancelled
+)
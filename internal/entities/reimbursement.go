@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReimbursementClaim bundles the expenses a member of a business workspace paid out of pocket and claims back.
+// Approved claims are paid by a transfer from a company account to the claimant's payout account.
+type ReimbursementClaim struct {
+	ClaimID              uuid.UUID  `json:"claim_id" db:"claim_id"`
+	WorkspaceID          uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	ClaimantID           uuid.UUID  `json:"claimant_id" db:"claimant_id"`
+	Title                string     `json:"title" db:"title"`
+	Notes                *string    `json:"notes" db:"notes"`
+	PayoutAccountID      uuid.UUID  `json:"payout_account_id" db:"payout_account_id"`
+	TotalAmount          float64    `json:"total_amount" db:"total_amount"`
+	Status               int        `json:"status" db:"status"`
+	SubmittedAt          *time.Time `json:"submitted_at" db:"submitted_at"`
+	ReviewedBy           *uuid.UUID `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt           *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNote           *string    `json:"review_note" db:"review_note"`
+	PaidBy               *uuid.UUID `json:"paid_by" db:"paid_by"`
+	PaidAt               *time.Time `json:"paid_at" db:"paid_at"`
+	PaymentTransactionID *uuid.UUID `json:"payment_transaction_id" db:"payment_transaction_id"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined from users and accounts
+	ClaimantName      string `json:"claimant_name" db:"claimant_name"`
+	PayoutAccountName string `json:"payout_account_name" db:"payout_account_name"`
+
+	Items []*ReimbursementClaimItem `json:"items,omitempty"`
+}
+
+// ReimbursementClaimItem is an expense bundled in a claim with its receipt
+type ReimbursementClaimItem struct {
+	ClaimItemID   uuid.UUID `json:"claim_item_id" db:"claim_item_id"`
+	ClaimID       uuid.UUID `json:"claim_id" db:"claim_id"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	ReceiptURL    *string   `json:"receipt_url" db:"receipt_url"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+
+	// Joined from transactions
+	Description     string    `json:"description" db:"description"`
+	Amount          float64   `json:"amount" db:"amount"`
+	TransactionDate time.Time `json:"transaction_date" db:"transaction_date"`
+	MerchantName    *string   `json:"merchant_name" db:"merchant_name"`
+	CategoryName    *string   `json:"category_name" db:"category_name"`
+}
+
+// ReimbursementClaimItemInput is an expense to claim, the receipt defaults to the receipt of the transaction
+type ReimbursementClaimItemInput struct {
+	TransactionID uuid.UUID `json:"transaction_id" binding:"required"`
+	ReceiptURL    string    `json:"receipt_url"`
+}
+
+// CreateReimbursementClaimRequest drafts a claim, or submits it right away with Submit
+type CreateReimbursementClaimRequest struct {
+	WorkspaceID     uuid.UUID                     `json:"workspace_id"`
+	Title           string                        `json:"title" binding:"required"`
+	Notes           string                        `json:"notes"`
+	PayoutAccountID uuid.UUID                     `json:"payout_account_id" binding:"required"`
+	Items           []ReimbursementClaimItemInput `json:"items" binding:"dive"`
+	Submit          bool                          `json:"submit"`
+}
+
+// UpdateReimbursementClaimRequest replaces the details and expenses of a draft or rejected claim
+type UpdateReimbursementClaimRequest struct {
+	Title           string                        `json:"title" binding:"required"`
+	Notes           string                        `json:"notes"`
+	PayoutAccountID uuid.UUID                     `json:"payout_account_id" binding:"required"`
+	Items           []ReimbursementClaimItemInput `json:"items" binding:"dive"`
+}
+
+// RejectReimbursementClaimRequest gives the reason a claim is rejected
+type RejectReimbursementClaimRequest struct {
+	Note string `json:"note"`
+}
+
+// PayReimbursementClaimRequest pays an approved claim from a company account owned by the paying approver
+type PayReimbursementClaimRequest struct {
+	AccountID   uuid.UUID  `json:"account_id" binding:"required"`
+	PaymentDate *time.Time `json:"payment_date"` // defaults to today
+}
+
+// ReimbursementClaimListParams filters the claims of a workspace
+type ReimbursementClaimListParams struct {
+	WorkspaceID uuid.UUID
+	ClaimantID  *uuid.UUID
+	Status      *int
+}
+
+// ReimbursementBalance is what a workspace owes a claimant, in the workspace currency
+type ReimbursementBalance struct {
+	ClaimantID        uuid.UUID `json:"claimant_id"`
+	ClaimantName      string    `json:"claimant_name"`
+	SubmittedAmount   float64   `json:"submitted_amount"`   // claims waiting for review
+	ApprovedAmount    float64   `json:"approved_amount"`    // approved claims waiting for payment
+	OutstandingAmount float64   `json:"outstanding_amount"` // submitted and approved
+	ClaimCount        int       `json:"claim_count"`
+}
+
+// Constants for reimbursement claim statuses
+const (
+	ReimbursementStatusDraft     = 1
+	ReimbursementStatusSubmitted = 2
+	ReimbursementStatusApproved  = 3
+	ReimbursementStatusRejected  = 4
+	ReimbursementStatusPaid      = 5
+)
+
+// ReimbursementClaimMaxItems caps the expenses bundled in one claim
+const ReimbursementClaimMaxItems = 100
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report output formats
+const (
+	ReportFormatJSON = "json"
+	ReportFormatPDF  = "pdf"
+	ReportFormatText = "text"
+)
+
+// CashFlowCategoryTotal represents the income or expenses of one category in a cash flow statement.
+// UnconvertedCount transactions are left out of the amount because no exchange rate was recorded for their currency.
+type CashFlowCategoryTotal struct {
+	UserCategoryID   *uuid.UUID `json:"user_category_id"`
+	CategoryName     string     `json:"category_name"`
+	Amount           float64    `json:"amount"`
+	TransactionCount int        `json:"transaction_count"`
+	UnconvertedCount int        `json:"unconverted_count"`
+	Percentage       float64    `json:"percentage"`
+}
+
+// CashFlowAccountMovement represents the money moved in and out of an account as returned by the repository.
+// MovementAfterPeriod is the net movement after the period, used to derive the closing balance from the current balance.
+type CashFlowAccountMovement struct {
+	AccountID           uuid.UUID
+	AccountName         string
+	CurrencyID          int
+	CurrentBalance      float64
+	Inflow              float64
+	Outflow             float64
+	MovementAfterPeriod float64
+}
+
+// CashFlowAccountBalance represents the opening and closing balance of an account over a period, in the account currency
+type CashFlowAccountBalance struct {
+	AccountID      uuid.UUID `json:"account_id"`
+	AccountName    string    `json:"account_name"`
+	CurrencyID     int       `json:"currency_id"`
+	OpeningBalance float64   `json:"opening_balance"`
+	Inflow         float64   `json:"inflow"`
+	Outflow        float64   `json:"outflow"`
+	ClosingBalance float64   `json:"closing_balance"`
+}
+
+// CashFlowStatement represents the income and expenses of a workspace over a period.
+// Totals are in the workspace currency, transfers between own accounts are excluded.
+type CashFlowStatement struct {
+	WorkspaceID    uuid.UUID                 `json:"workspace_id"`
+	WorkspaceName  string                    `json:"workspace_name"`
+	CurrencyID     int                       `json:"currency_id"`
+	CurrencyCode   string                    `json:"currency_code"`
+	CurrencySymbol string                    `json:"currency_symbol"`
+	StartDate      time.Time                 `json:"start_date"`
+	EndDate        time.Time                 `json:"end_date"`
+	TotalIncome    float64                   `json:"total_income"`
+	TotalExpense   float64                   `json:"total_expense"`
+	PendingExpense float64                   `json:"pending_expense"` // expenses waiting for approval, not in TotalExpense
+	NetCashFlow    float64                   `json:"net_cash_flow"`
+	SavingsRate    *float64                  `json:"savings_rate"`
+	Income         []*CashFlowCategoryTotal  `json:"income"`
+	Expenses       []*CashFlowCategoryTotal  `json:"expenses"`
+	Accounts       []*CashFlowAccountBalance `json:"accounts"`
+
+	// UnconvertedCount transactions, pending ones included, are left out of the totals because no exchange rate
+	// was recorded for their currency
+	UnconvertedCount int `json:"unconverted_count"`
+}
@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionSplit is the share of an expense a member owes to the member who paid it (the transaction's created_by)
+type TransactionSplit struct {
+	TransactionSplitID uuid.UUID `json:"transaction_split_id" db:"transaction_split_id"`
+	TransactionID      uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	UserID             uuid.UUID `json:"user_id" db:"user_id"`
+	Amount             float64   `json:"amount" db:"amount"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined from users
+	Name string `json:"name" db:"name"`
+}
+
+// TransactionSplitInput is the share of one member in a split
+type TransactionSplitInput struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Amount float64   `json:"amount" binding:"required"`
+}
+
+// SetTransactionSplitsRequest replaces the splits of an expense, the shares must add up to its amount.
+// An empty list removes the split.
+type SetTransactionSplitsRequest struct {
+	Splits []TransactionSplitInput `json:"splits" binding:"dive"`
+}
+
+// Settlement represents a payment between two members to settle their split balance, in the workspace currency.
+// It completes once both the payer and the payee confirmed it.
+type Settlement struct {
+	SettlementID     uuid.UUID  `json:"settlement_id" db:"settlement_id"`
+	WorkspaceID      uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	FromUserID       uuid.UUID  `json:"from_user_id" db:"from_user_id"`
+	ToUserID         uuid.UUID  `json:"to_user_id" db:"to_user_id"`
+	Amount           float64    `json:"amount" db:"amount"`
+	Notes            *string    `json:"notes" db:"notes"`
+	EvidenceURL      *string    `json:"evidence_url" db:"evidence_url"`
+	Status           int        `json:"status" db:"status"`
+	CreatedBy        uuid.UUID  `json:"created_by" db:"created_by"`
+	PayerConfirmedAt *time.Time `json:"payer_confirmed_at" db:"payer_confirmed_at"`
+	PayeeConfirmedAt *time.Time `json:"payee_confirmed_at" db:"payee_confirmed_at"`
+	SettledAt        *time.Time `json:"settled_at" db:"settled_at"`
+	CancelledBy      *uuid.UUID `json:"cancelled_by" db:"cancelled_by"`
+	CancelledAt      *time.Time `json:"cancelled_at" db:"cancelled_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined from users
+	FromUserName string `json:"from_user_name" db:"from_user_name"`
+	ToUserName   string `json:"to_user_name" db:"to_user_name"`
+}
+
+// CreateSettlementRequest records a payment from one member to another, the recording member must be one of them
+type CreateSettlementRequest struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	FromUserID  uuid.UUID `json:"from_user_id" binding:"required"`
+	ToUserID    uuid.UUID `json:"to_user_id" binding:"required"`
+	Amount      float64   `json:"amount" binding:"required"`
+	Notes       string    `json:"notes"`
+	EvidenceURL string    `json:"evidence_url"`
+}
+
+// SettlementListParams filters the settlement history of a workspace
+type SettlementListParams struct {
+	WorkspaceID uuid.UUID
+	UserID      *uuid.UUID // settlements paid or received by the user
+	Status      *int
+}
+
+// MemberBalance is the split balance of a member in the workspace currency.
+// A positive net balance is owed to the member, a negative one is owed by the member.
+type MemberBalance struct {
+	UserID           uuid.UUID `json:"user_id"`
+	Name             string    `json:"name"`
+	Lent             float64   `json:"lent"`             // shares of others in expenses the member paid
+	Borrowed         float64   `json:"borrowed"`         // the member's shares in expenses others paid
+	SettledPaid      float64   `json:"settled_paid"`     // completed settlements paid by the member
+	SettledReceived  float64   `json:"settled_received"` // completed settlements received by the member
+	NetBalance       float64   `json:"net_balance"`
+	UnconvertedCount int       `json:"unconverted_count"` // splits left out because no exchange rate was recorded for their currency
+}
+
+// SettlementTransfer is a suggested payment that settles balances
+type SettlementTransfer struct {
+	FromUserID   uuid.UUID `json:"from_user_id"`
+	FromUserName string    `json:"from_user_name"`
+	ToUserID     uuid.UUID `json:"to_user_id"`
+	ToUserName   string    `json:"to_user_name"`
+	Amount       float64   `json:"amount"`
+}
+
+// SettlementSummary tells who owes whom in a workspace
+type SettlementSummary struct {
+	WorkspaceID uuid.UUID             `json:"workspace_id"`
+	CurrencyID  int                   `json:"currency_id"`
+	GraceAmount float64               `json:"grace_amount"`
+	IsSettled   bool                  `json:"is_settled"` // no transfer is needed
+	Balances    []*MemberBalance      `json:"balances"`
+	Transfers   []*SettlementTransfer `json:"transfers"`
+}
+
+// Constants for settlement statuses
+const (
+	SettlementStatusPending   = 1
+	SettlementStatusCompleted = 2
+	SettlementStatusCancelled = 3
+)
+
+// SettlementGraceAmount is the default balance below which members are considered settled
+const SettlementGraceAmount = 10000
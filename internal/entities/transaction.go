@@ -28,23 +28,30 @@ type Transaction struct {
 	AIConfidenceScore   *float64   `json:"ai_confidence_score" db:"ai_confidence_score"`
 	AICategorized       bool       `json:"ai_categorized" db:"ai_categorized"`
 	CreditStatus        *int       `json:"credit_status" db:"credit_status"`
+	TransferAccountID   *uuid.UUID `json:"transfer_account_id" db:"transfer_account_id"`
+	MerchantID          *uuid.UUID `json:"merchant_id" db:"merchant_id"`
 	CreatedBy           *uuid.UUID `json:"created_by" db:"created_by"`
+	ApprovalStatus      int        `json:"approval_status" db:"approval_status"`
+	ApprovalNote        *string    `json:"approval_note" db:"approval_note"`
+	ApprovalDecidedBy   *uuid.UUID `json:"approval_decided_by" db:"approval_decided_by"`
+	ApprovalDecidedAt   *time.Time `json:"approval_decided_at" db:"approval_decided_at"`
 	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type TransactionListParams struct {
-	WorkspaceID   *uuid.UUID `json:"workspace_id"`
-	AccountID     *uuid.UUID `json:"account_id"`
-	CategoryID    *uuid.UUID `json:"category_id"`
-	StartDate     *time.Time `json:"start_date"`
-	EndDate       *time.Time `json:"end_date"`
-	PaymentMethod *int       `json:"payment_method"`
-	Description   *string    `json:"description"`
-	MerchantName  *string    `json:"merchant_name"`
-	Amount        *float64   `json:"amount"`
-	IsRecurring   *bool      `json:"is_recurring"`
-	CreditStatus  *int       `json:"credit_status"`
+	WorkspaceID    *uuid.UUID `json:"workspace_id"`
+	AccountID      *uuid.UUID `json:"account_id"`
+	CategoryID     *uuid.UUID `json:"category_id"`
+	StartDate      *time.Time `json:"start_date"`
+	EndDate        *time.Time `json:"end_date"`
+	PaymentMethod  *int       `json:"payment_method"`
+	Description    *string    `json:"description"`
+	MerchantName   *string    `json:"merchant_name"`
+	Amount         *float64   `json:"amount"`
+	IsRecurring    *bool      `json:"is_recurring"`
+	CreditStatus   *int       `json:"credit_status"`
+	ApprovalStatus *int       `json:"approval_status"`
 }
 
 // CreateTransactionRequest represents the create transaction request
@@ -63,6 +70,9 @@ type CreateTransactionRequest struct {
 	IsRecurring        *bool      `json:"is_recurring"`
 	RecurrenceInterval int        `json:"recurrence_interval"`
 	RecurrenceEndDate  *time.Time `json:"recurrence_end_date"`
+	TransferAccountID  *uuid.UUID `json:"transfer_account_id"`
+	AIConfidenceScore  *float64   `json:"ai_confidence_score"` // set when the category was suggested by the AI
+	SaveAsDraft        bool       `json:"save_as_draft"`       // keep an expense that needs approval as a draft instead of submitting it
 }
 
 // UpdateTransactionRequest represents the update transaction request
@@ -80,6 +90,7 @@ type UpdateTransactionRequest struct {
 	IsRecurring        *bool      `json:"is_recurring"`
 	RecurrenceInterval *int       `json:"recurrence_interval"`
 	RecurrenceEndDate  *time.Time `json:"recurrence_end_date"`
+	TransferAccountID  *uuid.UUID `json:"transfer_account_id"`
 }
 
 type TransationSimple struct {
@@ -115,6 +126,28 @@ const (
 	RecurrenceIntervalYearly  = 4
 )
 
+// Constants for approval statuses. Expenses that need approval count toward totals once approved.
+const (
+	ApprovalStatusNotRequired = 0
+	ApprovalStatusDraft       = 1
+	ApprovalStatusSubmitted   = 2
+	ApprovalStatusApproved    = 3
+	ApprovalStatusRejected    = 4
+	ApprovalStatusReimbursed  = 5
+)
+
+// RejectTransactionRequest gives the reason an expense is rejected
+type RejectTransactionRequest struct {
+	Note string `json:"note"`
+}
+
+// ApprovalQuickReplyRequest is a WhatsApp quick reply to an approval request, its payload is "approve:<transaction_id>"
+// or "reject:<transaction_id>"
+type ApprovalQuickReplyRequest struct {
+	Payload string `json:"payload" binding:"required"`
+	Note    string `json:"note"`
+}
+
 // Constants for credit status
 const (
 	CreditStatusPaid   = 1
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionComment is a comment in the discussion thread of a workspace transaction
+type TransactionComment struct {
+	CommentID       uuid.UUID          `json:"comment_id" db:"comment_id"`
+	TransactionID   uuid.UUID          `json:"transaction_id" db:"transaction_id"`
+	WorkspaceID     uuid.UUID          `json:"workspace_id" db:"workspace_id"`
+	UserID          uuid.UUID          `json:"user_id" db:"user_id"`
+	Content         string             `json:"content" db:"content"`
+	Mentions        CommentMentions    `json:"mentions" db:"mentions"`
+	Attachments     CommentAttachments `json:"attachments" db:"attachments"`
+	Channel         string             `json:"channel" db:"channel"`
+	SourceMessageID *uuid.UUID         `json:"source_message_id" db:"source_message_id"` // WhatsApp message the comment replied to
+	EditedAt        *time.Time         `json:"edited_at" db:"edited_at"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Joined from users
+	AuthorName string `json:"author_name,omitempty" db:"author_name"`
+}
+
+// CommentAttachment is a file attached to a comment, uploaded beforehand
+type CommentAttachment struct {
+	URL      string `json:"url"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+}
+
+// CommentMentions are the user IDs of the members mentioned in a comment
+type CommentMentions []uuid.UUID
+
+// Value implements driver.Valuer so the mentions are stored as JSONB
+func (m CommentMentions) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]uuid.UUID(m))
+}
+
+// Scan implements sql.Scanner for the JSONB mentions column
+func (m *CommentMentions) Scan(src interface{}) error {
+	*m = CommentMentions{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return errors.New("unsupported type for comment mentions")
+	}
+}
+
+// CommentAttachments are the files attached to a comment
+type CommentAttachments []CommentAttachment
+
+// Value implements driver.Valuer so the attachments are stored as JSONB
+func (a CommentAttachments) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]CommentAttachment(a))
+}
+
+// Scan implements sql.Scanner for the JSONB attachments column
+func (a *CommentAttachments) Scan(src interface{}) error {
+	*a = CommentAttachments{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return errors.New("unsupported type for comment attachments")
+	}
+}
+
+// CreateTransactionCommentRequest posts a comment. Members are mentioned by user ID or with @name in the content.
+type CreateTransactionCommentRequest struct {
+	Content     string              `json:"content"`
+	Mentions    []uuid.UUID         `json:"mentions"`
+	Attachments []CommentAttachment `json:"attachments"`
+}
+
+// UpdateTransactionCommentRequest replaces the content, mentions and attachments of a comment
+type UpdateTransactionCommentRequest struct {
+	Content     string              `json:"content"`
+	Mentions    []uuid.UUID         `json:"mentions"`
+	Attachments []CommentAttachment `json:"attachments"`
+}
+
+// ReplyTransactionCommentRequest posts a comment from a WhatsApp reply to a bot message linked to a transaction
+type ReplyTransactionCommentRequest struct {
+	Content     string              `json:"content"`
+	Attachments []CommentAttachment `json:"attachments"`
+}
+
+// Limits of a comment
+const (
+	TransactionCommentMaxLength      = 2000
+	TransactionCommentMaxAttachments = 10
+)
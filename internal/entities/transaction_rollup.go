@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionDailyRollup represents the daily total of the transactions of a workspace
+// with the same category, account and type, in the account currency
+type TransactionDailyRollup struct {
+	TransactionDailyRollupID uuid.UUID  `json:"transaction_daily_rollup_id" db:"transaction_daily_rollup_id"`
+	WorkspaceID              uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	TransactionDate          time.Time  `json:"transaction_date" db:"transaction_date"`
+	CategoryID               *uuid.UUID `json:"category_id" db:"category_id"`
+	AccountID                *uuid.UUID `json:"account_id" db:"account_id"`
+	TransactionType          int        `json:"transaction_type" db:"transaction_type"`
+	CurrencyID               int        `json:"currency_id" db:"currency_id"`
+	Amount                   float64    `json:"amount" db:"amount"`
+	TransactionCount         int64      `json:"transaction_count" db:"transaction_count"`
+	UpdatedAt                time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RollupDay identifies the rollups of one workspace on one day
+type RollupDay struct {
+	WorkspaceID     uuid.UUID
+	TransactionDate time.Time
+}
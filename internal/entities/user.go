@@ -20,6 +20,9 @@ type User struct {
 	EmailVerifiedAt    *time.Time `json:"email_verified_at" db:"email_verified_at"`
 	PhoneVerifiedAt    *time.Time `json:"phone_verified_at" db:"phone_verified_at"`
 	Status             int        `json:"status" db:"status"`
+	DefaultWorkspaceID *uuid.UUID `json:"default_workspace_id" db:"default_workspace_id"` // workspace selected after login
+	ActiveWorkspaceID  *uuid.UUID `json:"active_workspace_id" db:"active_workspace_id"`   // workspace last switched to
+	SystemRole         int        `json:"system_role" db:"system_role"`                   // role in the administration API, see SystemRoleNone
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 
@@ -36,39 +39,73 @@ type CreateUserInput struct {
 	Timezone           string `json:"timezone,omitempty"`
 	CurrencyID         int    `json:"currency_id,omitempty"`
 	SubscriptionPlanID int    `json:"subscription_plan_id,omitempty"`
+	InvitationToken    string `json:"invitation_token,omitempty"`
+	DeviceName         string `json:"device_name,omitempty" binding:"max=100"`
 }
 
 type UpdateUserInput struct {
-	Email              string     `json:"email" binding:"email"`
-	PhoneNumber        string     `json:"phone_number" binding:"required"`
-	FirstName          string     `json:"first_name" binding:"required"`
-	LastName           string     `json:"last_name" binding:"required"`
-	Timezone           string     `json:"timezone,omitempty"`
-	CurrencyID         int        `json:"currency_id" binding:"required"`
-	SubscriptionPlanID int        `json:"subscription_plan_id" binding:"required"`
-	Status             int        `json:"status" binding:"required"`
-	EmailVerifiedAt    *time.Time `json:"email_verified_at" db:"email_verified_at"`
-	PhoneVerifiedAt    *time.Time `json:"phone_verified_at" db:"phone_verified_at"`
+	Email              string `json:"email" binding:"email"`
+	PhoneNumber        string `json:"phone_number" binding:"required"`
+	FirstName          string `json:"first_name" binding:"required"`
+	LastName           string `json:"last_name" binding:"required"`
+	Timezone           string `json:"timezone,omitempty"`
+	CurrencyID         int    `json:"currency_id" binding:"required"`
+	SubscriptionPlanID int    `json:"subscription_plan_id" binding:"required"`
+	Status             int    `json:"status" binding:"required"`
 }
 
-// RefreshToken represents a refresh token for JWT authentication
+// RefreshToken is a hashed, single-use refresh token of a session.
+// Exchanging it rotates it, exchanging a rotated token again revokes the session.
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Token     string    `json:"token" db:"token"`
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-	IsRevoked bool      `json:"is_revoked" db:"is_revoked"`
-	IPAddress string    `json:"ip_address" db:"ip_address"`
-	UserAgent string    `json:"user_agent" db:"user_agent"`
-}
+	TokenID   uuid.UUID  `json:"token_id" db:"token_id"`
+	SessionID uuid.UUID  `json:"session_id" db:"session_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RotatedAt *time.Time `json:"rotated_at" db:"rotated_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// UserSession is a login of a user on one device, kept alive by rotating its refresh token
+type UserSession struct {
+	SessionID     uuid.UUID  `json:"session_id" db:"session_id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	DeviceName    string     `json:"device_name" db:"device_name"`
+	UserAgent     string     `json:"user_agent" db:"user_agent"`
+	IPAddress     string     `json:"ip_address" db:"ip_address"`
+	LastUsedAt    time.Time  `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt     *time.Time `json:"-" db:"revoked_at"`
+	RevokedReason *string    `json:"-" db:"revoked_reason"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+
+	// Current marks the session of the access token making the request
+	Current bool `json:"current" db:"-"`
+}
+
+// SessionClient describes the device a session is opened or refreshed from
+type SessionClient struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// Constants for session revocation reasons
+const (
+	SessionRevokedLogout    = "logout"
+	SessionRevokedLogoutAll = "logout_all"
+	SessionRevokedByUser    = "revoked"
+	SessionRevokedReuse     = "reuse"
+	SessionRevokedPassword  = "password_change"
+	SessionRevokedRole      = "role_change"
+)
 
 // JWT Claims for expense system
 type JWTClaims struct {
-	UserID             uuid.UUID `json:"user_id"`
-	DefaultWorkspaceID uuid.UUID `json:"default_workspace_id"`
-	Email              string    `json:"email"`
+	UserID             uuid.UUID  `json:"user_id"`
+	DefaultWorkspaceID *uuid.UUID `json:"default_workspace_id,omitempty"`
+	ActiveWorkspaceID  *uuid.UUID `json:"active_workspace_id,omitempty"`
+	Email              string     `json:"email"`
 	StandardClaims
 }
 
@@ -87,6 +124,7 @@ type StandardClaims struct {
 type LoginRequest struct {
 	PhoneNumber string `json:"phone_number,omitempty" binding:"required"`
 	Password    string `json:"password" binding:"required,len=6,numeric"`
+	DeviceName  string `json:"device_name,omitempty" binding:"max=100"`
 }
 
 // LoginResponse represents the login response
@@ -112,32 +150,58 @@ type RefreshTokenResponse struct {
 	ExpiresIn    int    `json:"expires_in"`
 }
 
-// ForgotPasswordRequest represents the forgot password request
+// ForgotPasswordRequest requests a password reset code on the user's phone
 type ForgotPasswordRequest struct {
-	Email string `json:"email" binding:"required,email"`
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Channel     string `json:"channel" binding:"omitempty,oneof=whatsapp sms"` // defaults to whatsapp
 }
 
-// ResetPasswordRequest represents the reset password request
+// VerifyPasswordResetCodeRequest exchanges a password reset code for a reset token
+type VerifyPasswordResetCodeRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// PasswordResetTokenResponse carries the short-lived token that sets a new password
+type PasswordResetTokenResponse struct {
+	ResetToken string `json:"reset_token"`
+	ExpiresIn  int    `json:"expires_in"`
+}
+
+// ResetPasswordRequest sets a new password with a reset token
 type ResetPasswordRequest struct {
 	Token       string `json:"token" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=8"`
+	NewPassword string `json:"new_password" binding:"required,len=6,numeric"`
 }
 
 // ChangePasswordRequest represents the change password request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
-	NewPassword     string `json:"new_password" binding:"required,min=8"`
+	NewPassword     string `json:"new_password" binding:"required,len=6,numeric"`
 }
 
 // VerifyPhoneRequest represents the phone verification request
 type VerifyPhoneRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
-	Code        string `json:"code" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
 }
 
 // ResendVerificationCodeRequest represents the resend verification code request
 type ResendVerificationCodeRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
+	Channel     string `json:"channel" binding:"omitempty,oneof=whatsapp sms"` // defaults to whatsapp
+}
+
+// ChangePhoneRequest sends a code to the new phone number of a user
+type ChangePhoneRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Channel     string `json:"channel" binding:"omitempty,oneof=whatsapp sms"` // defaults to whatsapp
+}
+
+// ConfirmPhoneChangeRequest moves a user to the new phone number with the code sent to it
+type ConfirmPhoneChangeRequest struct {
+	PhoneNumber string `json:"phone_number" binding:"required"`
+	Code        string `json:"code" binding:"required,len=6,numeric"`
 }
 
 // VerifyEmailRequest represents the email verification request
@@ -9,7 +9,7 @@ import (
 type VerificationCode struct {
 	VerificationCodeID uuid.UUID `json:"verification_code_id" db:"verification_code_id"`
 	PhoneNumber        string    `json:"phone_number" db:"phone_number"`
-	Code               string    `json:"code" db:"code"`
+	Code               string    `json:"-" db:"code"` // only ever delivered to the phone
 	CodeType           string    `json:"code_type" db:"code_type"`
 	ExpiresAt          time.Time `json:"expires_at" db:"expires_at"`
 	IsUsed             bool      `json:"is_used" db:"is_used"`
@@ -22,9 +22,23 @@ type VerificationCode struct {
 type CreateVerificationCodeRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
 	CodeType    string `json:"code_type" binding:"required"`
+	Channel     string `json:"channel" binding:"omitempty,oneof=whatsapp sms"` // defaults to whatsapp
 }
 
 type VerifyVerificationCodeRequest struct {
 	PhoneNumber string `json:"phone_number" binding:"required"`
 	Code        string `json:"code" binding:"required"`
 }
+
+// Constants for verification code types
+const (
+	VerificationCodeTypePhone         = "phone_verification"
+	VerificationCodeTypePasswordReset = "password_reset"
+	VerificationCodeTypePhoneChange   = "phone_change"
+)
+
+// Constants for the channels a verification code is delivered over
+const (
+	CodeDeliveryWhatsApp = "whatsapp"
+	CodeDeliverySMS      = "sms"
+)
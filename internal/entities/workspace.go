@@ -8,19 +8,22 @@ import (
 
 // Workspace represents a workspace in the expense tracking system
 type Workspace struct {
-	WorkspaceID   uuid.UUID `json:"workspace_id" db:"workspace_id"`
-	Name          string    `json:"name" db:"name"`
-	Description   string    `json:"description" db:"description"`
-	WorkspaceType int       `json:"workspace_type" db:"workspace_type"`
-	Icon          string    `json:"icon" db:"icon"`
-	ColorCode     string    `json:"color_code" db:"color_code"`
-	CurrencyID    int       `json:"currency_id" db:"currency_id"`
-	Timezone      string    `json:"timezone" db:"timezone"`
-	Settings      string    `json:"settings" db:"settings"` // JSON string
-	IsActive      bool      `json:"is_active" db:"is_active"`
-	CreatedBy     uuid.UUID `json:"created_by" db:"created_by"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	WorkspaceID   uuid.UUID         `json:"workspace_id" db:"workspace_id"`
+	Name          string            `json:"name" db:"name"`
+	Description   string            `json:"description" db:"description"`
+	WorkspaceType int               `json:"workspace_type" db:"workspace_type"`
+	Icon          string            `json:"icon" db:"icon"`
+	ColorCode     string            `json:"color_code" db:"color_code"`
+	CurrencyID    int               `json:"currency_id" db:"currency_id"`
+	Timezone      string            `json:"timezone" db:"timezone"`
+	Settings      WorkspaceSettings `json:"settings" db:"settings"`
+	BudgetingMode int               `json:"budgeting_mode" db:"budgeting_mode"`
+	IsActive      bool              `json:"is_active" db:"is_active"`
+	ArchivedAt    *time.Time        `json:"archived_at" db:"archived_at"` // archived workspaces are read-only
+	CreatedBy     uuid.UUID         `json:"created_by" db:"created_by"`
+	Role          int               `json:"role,omitempty" db:"role"` // role of the requesting user, set on listings
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 type CreateWorkspaceInput struct {
@@ -31,6 +34,7 @@ type CreateWorkspaceInput struct {
 	ColorCode     string `json:"color_code"`
 	CurrencyID    int    `json:"currency_id" binding:"required"`
 	Timezone      string `json:"timezone"`
+	BudgetingMode int    `json:"budgeting_mode"`
 }
 
 type UpdateWorkspaceInput struct {
@@ -41,9 +45,24 @@ type UpdateWorkspaceInput struct {
 	ColorCode     string `json:"color_code"`
 	CurrencyID    int    `json:"currency_id"`
 	Timezone      string `json:"timezone"`
+	BudgetingMode int    `json:"budgeting_mode"`
 	IsActive      bool   `json:"is_active"`
 }
 
+// SwitchWorkspaceRequest switches the active workspace, and the default workspace too when make_default is set
+type SwitchWorkspaceRequest struct {
+	MakeDefault bool `json:"make_default"`
+}
+
+// SwitchWorkspaceResponse carries a new access token holding the switched workspace
+type SwitchWorkspaceResponse struct {
+	AccessToken string     `json:"access_token"`
+	TokenType   string     `json:"token_type"`
+	ExpiresIn   int        `json:"expires_in"`
+	User        *User      `json:"user"`
+	Workspace   *Workspace `json:"workspace"`
+}
+
 // Constants for workspace types
 const (
 	WorkspaceTypePersonal = 1
@@ -53,3 +72,9 @@ const (
 	WorkspaceTypeProject  = 5
 	WorkspaceTypeShared   = 6
 )
+
+// Constants for workspace budgeting modes
+const (
+	BudgetingModeStandard = 1 // budgets per category, tag or account
+	BudgetingModeEnvelope = 2 // zero-based "amplop" budgeting, every income is assigned to envelopes
+)
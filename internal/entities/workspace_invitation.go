@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceInvitation represents an invitation to join a workspace with a role.
+// An invitation without email and phone number is a shareable link.
+type WorkspaceInvitation struct {
+	InvitationID  uuid.UUID  `json:"invitation_id" db:"invitation_id"`
+	WorkspaceID   uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	InvitedBy     *uuid.UUID `json:"invited_by" db:"invited_by"`
+	Email         *string    `json:"email,omitempty" db:"email"`
+	PhoneNumber   *string    `json:"phone_number,omitempty" db:"phone_number"`
+	InviteeUserID *uuid.UUID `json:"invitee_user_id,omitempty" db:"invitee_user_id"`
+	Role          int        `json:"role" db:"role"`
+	Status        int        `json:"status" db:"status"`
+	ExpiresAt     time.Time  `json:"expires_at" db:"expires_at"`
+	RespondedAt   *time.Time `json:"responded_at" db:"responded_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined from workspaces and users
+	WorkspaceName string `json:"workspace_name" db:"workspace_name"`
+	InviterName   string `json:"inviter_name" db:"inviter_name"`
+
+	// Set for the inviter and the invitee only, never stored
+	Token         string `json:"token,omitempty" db:"-"`
+	InvitationURL string `json:"invitation_url,omitempty" db:"-"`
+}
+
+// CreateWorkspaceInvitationRequest invites by email or WhatsApp phone number, or creates a link when both are empty
+type CreateWorkspaceInvitationRequest struct {
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Role        int    `json:"role" binding:"required"`
+}
+
+// RespondWorkspaceInvitationRequest accepts or declines an invitation with its token
+type RespondWorkspaceInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Constants for workspace invitation statuses, an expired invitation stays pending past its expires_at
+const (
+	WorkspaceInvitationStatusPending  = 1
+	WorkspaceInvitationStatusAccepted = 2
+	WorkspaceInvitationStatusDeclined = 3
+	WorkspaceInvitationStatusRevoked  = 4
+)
+
+// WorkspaceInvitationTTL is how long an invitation can be accepted
+const WorkspaceInvitationTTL = 7 * 24 * time.Hour
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceMember represents the membership of a user in a workspace
+type WorkspaceMember struct {
+	WorkspaceMemberID uuid.UUID  `json:"workspace_member_id" db:"workspace_member_id"`
+	WorkspaceID       uuid.UUID  `json:"workspace_id" db:"workspace_id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	Role              int        `json:"role" db:"role"`
+	InvitedBy         *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	JoinedAt          time.Time  `json:"joined_at" db:"joined_at"`
+	IsActive          bool       `json:"is_active" db:"is_active"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined from users
+	Name        string `json:"name,omitempty" db:"name"`
+	Email       string `json:"email,omitempty" db:"email"`
+	PhoneNumber string `json:"phone_number,omitempty" db:"phone_number"`
+}
+
+// AddWorkspaceMemberRequest adds a registered user to a workspace, by email or phone number
+type AddWorkspaceMemberRequest struct {
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Role        int    `json:"role" binding:"required"`
+}
+
+// UpdateWorkspaceMemberRequest changes the role of a member
+type UpdateWorkspaceMemberRequest struct {
+	Role int `json:"role" binding:"required"`
+}
+
+// Constants for workspace member roles
+const (
+	WorkspaceRoleOwner  = 1 // full control, member management, workspace deletion
+	WorkspaceRoleAdmin  = 2 // manages data, settings and members except the owner
+	WorkspaceRoleMember = 3 // records transactions, views all data, limited budget editing
+	WorkspaceRoleViewer = 4 // read only
+)
+
+// WorkspacePermission is an action a member role may be granted on a workspace
+type WorkspacePermission string
+
+// Workspace permissions, granted per role by the permission matrix in the services package
+const (
+	WorkspacePermissionView              WorkspacePermission = "view"
+	WorkspacePermissionCreateTransaction WorkspacePermission = "create_transaction"
+	WorkspacePermissionManageOwnData     WorkspacePermission = "manage_own_data"   // edit and delete what the member created
+	WorkspacePermissionManageAllData     WorkspacePermission = "manage_all_data"   // edit and delete what anyone created
+	WorkspacePermissionEditBudget        WorkspacePermission = "edit_budget"       // update existing budgets and envelope allocations
+	WorkspacePermissionManageBudget      WorkspacePermission = "manage_budget"     // create and delete budgets
+	WorkspacePermissionManageWorkspace   WorkspacePermission = "manage_workspace"  // update workspace details
+	WorkspacePermissionManageMembers     WorkspacePermission = "manage_members"    // add, update and remove members
+	WorkspacePermissionArchiveWorkspace  WorkspacePermission = "archive_workspace" // archive and unarchive, allowed while archived
+	WorkspacePermissionDeleteWorkspace   WorkspacePermission = "delete_workspace"
+	WorkspacePermissionApproveExpense    WorkspacePermission = "approve_expense" // approve, reject and reimburse expenses
+)
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceSettingsVersion is the current version of the workspace settings schema.
+// Bump it together with a new step in workspaceSettingsMigrations.
+const WorkspaceSettingsVersion = 1
+
+// Calendar defaults of the workspace settings
+const (
+	DefaultWeekStartDay        = 1 // Monday
+	DefaultFiscalMonthStartDay = 1
+)
+
+// WorkspaceSettings are the typed, versioned settings of a workspace, stored as JSONB
+type WorkspaceSettings struct {
+	Version                  int                           `json:"version"`
+	WeekStartDay             int                           `json:"week_start_day"`         // 0 (Sunday) to 6 (Saturday)
+	FiscalMonthStartDay      int                           `json:"fiscal_month_start_day"` // 1 to 28, monthly periods start on this day
+	DefaultAccountID         *uuid.UUID                    `json:"default_account_id"`     // used for transactions recorded without an account by its owner
+	DefaultPaymentMethod     int                           `json:"default_payment_method"` // 0 for none
+	AutoCategorizeConfidence float64                       `json:"auto_categorize_confidence"`
+	Approvals                WorkspaceApprovalSettings     `json:"approvals"`
+	Notifications            WorkspaceNotificationSettings `json:"notifications"`
+}
+
+// WorkspaceApprovalSettings decide which expenses need approval, business workspaces only
+type WorkspaceApprovalSettings struct {
+	Enabled            bool        `json:"enabled"`
+	AmountThreshold    float64     `json:"amount_threshold"`      // expenses above it need approval, 0 for every expense
+	RequiredForUserIDs []uuid.UUID `json:"required_for_user_ids"` // members whose expenses always need approval
+}
+
+// WorkspaceNotificationSettings are the notifications members of a workspace get by default
+type WorkspaceNotificationSettings struct {
+	SpendingAnomalies bool `json:"spending_anomalies"`
+	ApprovalRequests  bool `json:"approval_requests"`
+}
+
+// DefaultWorkspaceSettings returns the settings a new workspace of a type starts with
+func DefaultWorkspaceSettings(workspaceType int) WorkspaceSettings {
+	settings := WorkspaceSettings{
+		Version:                  WorkspaceSettingsVersion,
+		WeekStartDay:             DefaultWeekStartDay,
+		FiscalMonthStartDay:      DefaultFiscalMonthStartDay,
+		AutoCategorizeConfidence: 0.8,
+		Approvals: WorkspaceApprovalSettings{
+			RequiredForUserIDs: []uuid.UUID{},
+		},
+		Notifications: WorkspaceNotificationSettings{
+			SpendingAnomalies: true,
+			ApprovalRequests:  true,
+		},
+	}
+
+	switch workspaceType {
+	case WorkspaceTypeBusiness:
+		// Wrongly categorized business expenses end up in reports, ask before trusting the AI
+		settings.AutoCategorizeConfidence = 0.9
+	case WorkspaceTypeEvent, WorkspaceTypeTravel:
+		// Spending on a trip or an event is unusual by nature
+		settings.Notifications.SpendingAnomalies = false
+	}
+
+	return settings
+}
+
+// workspaceSettingsMigrations upgrade raw settings one version at a time, the step at index n upgrades version n
+var workspaceSettingsMigrations = []func(raw map[string]interface{}){
+	// Version 0 settings were free-form and never read, the keys matching the schema are kept
+	func(raw map[string]interface{}) {},
+}
+
+// MigrateWorkspaceSettings upgrades stored settings to the current version.
+// Fields missing from older versions take the defaults of the workspace type.
+func MigrateWorkspaceSettings(data []byte, workspaceType int) (WorkspaceSettings, error) {
+	settings := DefaultWorkspaceSettings(workspaceType)
+	if len(data) == 0 {
+		return settings, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return settings, fmt.Errorf("invalid workspace settings: %w", err)
+	}
+	if raw == nil {
+		return settings, nil
+	}
+
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > WorkspaceSettingsVersion {
+		return settings, fmt.Errorf("unsupported workspace settings version %d", version)
+	}
+	for ; version < WorkspaceSettingsVersion; version++ {
+		workspaceSettingsMigrations[version](raw)
+	}
+	raw["version"] = WorkspaceSettingsVersion
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(migrated, &settings); err != nil {
+		return DefaultWorkspaceSettings(workspaceType), fmt.Errorf("invalid workspace settings: %w", err)
+	}
+	if settings.Approvals.RequiredForUserIDs == nil {
+		settings.Approvals.RequiredForUserIDs = []uuid.UUID{}
+	}
+
+	return settings, nil
+}
+
+// Value implements driver.Valuer so the settings are stored as JSONB. They are read back as raw JSON and
+// migrated with the type of their workspace, see MigrateWorkspaceSettings.
+func (s WorkspaceSettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
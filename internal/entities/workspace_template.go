@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceTemplateVersion is the current version of the workspace template document
+const WorkspaceTemplateVersion = 1
+
+// WorkspaceTemplate is a portable copy of the setup of a workspace, without its transactions.
+// Categories and tags are referenced by name so any user can import the template.
+type WorkspaceTemplate struct {
+	Version       int                         `json:"version"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	WorkspaceType int                         `json:"workspace_type"`
+	Icon          string                      `json:"icon"`
+	ColorCode     string                      `json:"color_code"`
+	CurrencyID    int                         `json:"currency_id"`
+	Timezone      string                      `json:"timezone"`
+	BudgetingMode int                         `json:"budgeting_mode"`
+	Settings      WorkspaceSettings           `json:"settings"`
+	Categories    []WorkspaceTemplateCategory `json:"categories"`
+	Tags          []string                    `json:"tags"`
+	Budgets       []WorkspaceTemplateBudget   `json:"budgets"`
+	ExportedAt    time.Time                   `json:"exported_at"`
+}
+
+// WorkspaceTemplateCategory is a user category used by the workspace
+type WorkspaceTemplateCategory struct {
+	Name        string    `json:"name"`
+	CategoryID  uuid.UUID `json:"category_id"` // system category the user category belongs to
+	Description *string   `json:"description,omitempty"`
+	Icon        *string   `json:"icon,omitempty"`
+}
+
+// WorkspaceTemplateBudget is a budget whose period is relative to the start of the template.
+// The period starts at the start date plus the start offset and lasts for the length.
+type WorkspaceTemplateBudget struct {
+	Name              string                       `json:"name"`
+	CategoryName      string                       `json:"category_name,omitempty"`
+	BudgetedAmount    float64                      `json:"budgeted_amount"`
+	PeriodType        int                          `json:"period_type"`
+	StartOffsetMonths int                          `json:"start_offset_months"`
+	StartOffsetDays   int                          `json:"start_offset_days"`
+	LengthMonths      int                          `json:"length_months"`
+	LengthDays        int                          `json:"length_days"`
+	Scope             WorkspaceTemplateBudgetScope `json:"scope"`
+}
+
+// WorkspaceTemplateBudgetScope is a budget scope by name. Account filters are left out,
+// accounts hold balances and are not part of a template.
+type WorkspaceTemplateBudgetScope struct {
+	CategoryNames []string `json:"category_names,omitempty"`
+	TagNames      []string `json:"tag_names,omitempty"`
+	Merchants     []string `json:"merchants,omitempty"`
+	TotalSpend    bool     `json:"total_spend"`
+}
+
+// CloneWorkspaceRequest clones a workspace without its transactions, budgets are re-dated from the start date
+type CloneWorkspaceRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Description *string    `json:"description"`
+	StartDate   *time.Time `json:"start_date"` // defaults to today
+}
+
+// ImportWorkspaceTemplateRequest creates a workspace from an exported template
+type ImportWorkspaceTemplateRequest struct {
+	Template  WorkspaceTemplate `json:"template"`
+	Name      string            `json:"name"`       // defaults to the template name
+	StartDate *time.Time        `json:"start_date"` // defaults to today
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
+)
+
+// NetWorthSnapshotInterval is how often account balances are snapshotted. Snapshots are per day
+// and overwritten within the day, so the last run before midnight in the user's timezone is kept.
+const NetWorthSnapshotInterval = time.Hour
+
+type NetWorthSnapshotJob struct {
+	netWorthService services.NetWorthService
+	logger          *logs.Logger
+	interval        time.Duration
+	ctx             context.Context
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+func NewNetWorthSnapshotJob(netWorthService services.NetWorthService, logger *logs.Logger) *NetWorthSnapshotJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &NetWorthSnapshotJob{
+		netWorthService: netWorthService,
+		logger:          logger,
+		interval:        NetWorthSnapshotInterval,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+func (j *NetWorthSnapshotJob) Start() error {
+	j.logger.Info().Msg("Starting Net Worth Snapshot Job")
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.run()
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.run()
+			}
+		}
+	}()
+
+	j.logger.Info().Msg("Net Worth Snapshot Job started successfully")
+	return nil
+}
+
+func (j *NetWorthSnapshotJob) Stop() {
+	j.logger.Info().Msg("Stopping Net Worth Snapshot Job")
+	j.cancel()
+	j.wg.Wait()
+	j.logger.Info().Msg("Net Worth Snapshot Job stopped")
+}
+
+func (j *NetWorthSnapshotJob) run() {
+	count, err := j.netWorthService.TakeSnapshots(j.ctx)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to take account balance snapshots")
+		return
+	}
+	j.logger.Info().Int64("snapshots", count).Msg("Account balance snapshots taken")
+}
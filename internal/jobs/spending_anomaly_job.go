@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
+)
+
+const (
+	// SpendingAnomalyInterval is how often new transactions are checked for anomalies
+	SpendingAnomalyInterval = 5 * time.Minute
+
+	// SpendingAnomalyBatchSize is how many transactions a run checks at most.
+	SpendingAnomalyBatchSize = 500
+)
+
+type SpendingAnomalyJob struct {
+	anomalyService services.SpendingAnomalyService
+	logger         *logs.Logger
+	interval       time.Duration
+	ctx            context.Context
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
+}
+
+func NewSpendingAnomalyJob(anomalyService services.SpendingAnomalyService, logger *logs.Logger) *SpendingAnomalyJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SpendingAnomalyJob{
+		anomalyService: anomalyService,
+		logger:         logger,
+		interval:       SpendingAnomalyInterval,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+func (j *SpendingAnomalyJob) Start() error {
+	j.logger.Info().Msg("Starting Spending Anomaly Job")
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.run()
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.run()
+			}
+		}
+	}()
+
+	j.logger.Info().Msg("Spending Anomaly Job started successfully")
+	return nil
+}
+
+func (j *SpendingAnomalyJob) Stop() {
+	j.logger.Info().Msg("Stopping Spending Anomaly Job")
+	j.cancel()
+	j.wg.Wait()
+	j.logger.Info().Msg("Spending Anomaly Job stopped")
+}
+
+func (j *SpendingAnomalyJob) run() {
+	count, err := j.anomalyService.DetectAnomalies(j.ctx, SpendingAnomalyBatchSize)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to detect spending anomalies")
+		return
+	}
+	if count > 0 {
+		j.logger.Info().Int64("anomalies", count).Msg("Spending anomalies detected")
+	}
+}
@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vasst-id/vasst-expense-api/internal/services"
+	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
+)
+
+const (
+	// TransactionRollupInterval is how often the daily rollups are checked against the transactions
+	TransactionRollupInterval = time.Hour
+
+	// TransactionRollupRecentDays is how far back the hourly check looks
+	TransactionRollupRecentDays = 35
+
+	// TransactionRollupFullCheckInterval is how often the whole history is checked
+	TransactionRollupFullCheckInterval = 24 * time.Hour
+)
+
+type TransactionRollupJob struct {
+	rollupService services.TransactionRollupService
+	logger        *logs.Logger
+	interval      time.Duration
+	lastFullCheck time.Time
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+func NewTransactionRollupJob(rollupService services.TransactionRollupService, logger *logs.Logger) *TransactionRollupJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TransactionRollupJob{
+		rollupService: rollupService,
+		logger:        logger,
+		interval:      TransactionRollupInterval,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func (j *TransactionRollupJob) Start() error {
+	j.logger.Info().Msg("Starting Transaction Rollup Job")
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		j.run()
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.run()
+			}
+		}
+	}()
+
+	j.logger.Info().Msg("Transaction Rollup Job started successfully")
+	return nil
+}
+
+func (j *TransactionRollupJob) Stop() {
+	j.logger.Info().Msg("Stopping Transaction Rollup Job")
+	j.cancel()
+	j.wg.Wait()
+	j.logger.Info().Msg("Transaction Rollup Job stopped")
+}
+
+func (j *TransactionRollupJob) run() {
+	rebuilt, err := j.rollupService.RebuildMissing(j.ctx)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to rebuild missing transaction rollups")
+		return
+	}
+	if rebuilt > 0 {
+		j.logger.Info().Int("workspaces", rebuilt).Msg("Transaction rollups rebuilt")
+	}
+
+	var since *time.Time
+	fullCheck := time.Since(j.lastFullCheck) >= TransactionRollupFullCheckInterval
+	if !fullCheck {
+		recent := time.Now().UTC().AddDate(0, 0, -TransactionRollupRecentDays)
+		since = &recent
+	}
+
+	repaired, err := j.rollupService.CheckConsistency(j.ctx, since)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to check transaction rollup consistency")
+		return
+	}
+	if fullCheck {
+		j.lastFullCheck = time.Now()
+	}
+	if repaired > 0 {
+		j.logger.Warn().Int("days", repaired).Bool("full_check", fullCheck).Msg("Transaction rollup drift repaired")
+	}
+}
@@ -17,11 +17,23 @@ const (
 	BearerPrefix        = "Bearer "
 )
 
+const (
+	// AccessTokenTTL is short since an access token stays valid until it expires, even after logout
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a session lasts without being refreshed
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
 type JWTClaims struct {
-	UserID      uuid.UUID `json:"user_id"`
-	PhoneNumber string    `json:"phone_number"`
-	Email       string    `json:"email"`
-	Status      int64     `json:"status"`
+	UserID             uuid.UUID  `json:"user_id"`
+	PhoneNumber        string     `json:"phone_number"`
+	Email              string     `json:"email"`
+	Status             int64      `json:"status"`
+	DefaultWorkspaceID *uuid.UUID `json:"default_workspace_id,omitempty"`
+	ActiveWorkspaceID  *uuid.UUID `json:"active_workspace_id,omitempty"`
+	SessionID          *uuid.UUID `json:"session_id,omitempty"`
+	PhoneVerified      bool       `json:"phone_verified"`
+	SystemRole         int        `json:"system_role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -35,17 +47,29 @@ func NewAuthMiddleware(secretKey string) *AuthMiddleware {
 	}
 }
 
-func (m *AuthMiddleware) GenerateToken(user *entities.User) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
-		UserID:      user.UserID,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Status:      int64(user.Status),
+// GenerateToken issues a short-lived access token of a user for a session, see AccessTokenTTL
+func (m *AuthMiddleware) GenerateToken(user *entities.User, sessionID uuid.UUID) (string, error) {
+	// AuthRequired refuses access tokens without a session, they could not be revoked
+	if sessionID == uuid.Nil {
+		return "", errors.New("access token requires a session")
+	}
+
+	claims := JWTClaims{
+		UserID:             user.UserID,
+		PhoneNumber:        user.PhoneNumber,
+		Email:              user.Email,
+		Status:             int64(user.Status),
+		DefaultWorkspaceID: user.DefaultWorkspaceID,
+		ActiveWorkspaceID:  user.ActiveWorkspaceID,
+		PhoneVerified:      user.PhoneVerifiedAt != nil,
+		SessionID:          &sessionID,
+		SystemRole:         user.SystemRole,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * 7 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
-	})
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString(m.secretKey)
 	if err != nil {
@@ -97,10 +121,213 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		// Tokens issued before sessions existed last 7 days and logout or revocation cannot end them,
+		// their users log in again
+		if claims.SessionID == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("phone_number", claims.PhoneNumber)
 		c.Set("email", claims.Email)
 		c.Set("status", claims.Status)
+		c.Set("phone_verified", claims.PhoneVerified)
+		c.Set("system_role", claims.SystemRole)
+		c.Set("session_id", *claims.SessionID)
+		c.Request = c.Request.WithContext(WithSessionID(c.Request.Context(), *claims.SessionID))
+
+		// Routes fall back to the active workspace, then the default one, when no workspace is given
+		if claims.ActiveWorkspaceID != nil {
+			c.Set("active_workspace_id", *claims.ActiveWorkspaceID)
+		} else if claims.DefaultWorkspaceID != nil {
+			c.Set("active_workspace_id", *claims.DefaultWorkspaceID)
+		}
+		c.Next()
+	}
+}
+
+// VerifiedRequired restricts a route to users who verified their phone number, it runs after AuthRequired.
+// The claim is refreshed with the access token, so a user who just verified refreshes before retrying.
+func (m *AuthMiddleware) VerifiedRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("phone_verified") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "phone number is not verified"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// AdminRequired restricts a route to users holding one of the system roles, or any system role when none is given.
+// It runs after AuthRequired. The claim is refreshed with the access token, changing a role revokes the user's sessions.
+func (m *AuthMiddleware) AdminRequired(roles ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetInt("system_role")
+		if role == entities.SystemRoleNone {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			c.Abort()
+			return
+		}
+
+		if len(roles) > 0 && !hasSystemRole(role, roles) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin role does not allow this action"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasSystemRole(role int, roles []int) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// invitationTokenPurpose separates the invitation signing key from the access token key,
+// so an invitation token can never be used as a bearer token
+const invitationTokenPurpose = ":workspace_invitation"
+
+type InvitationClaims struct {
+	InvitationID uuid.UUID `json:"invitation_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateInvitationToken signs a workspace invitation ID until its expiry.
+// The token is deterministic, so it can be shown again to the inviter and the invitee.
+func (m *AuthMiddleware) GenerateInvitationToken(invitationID uuid.UUID, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, InvitationClaims{
+		InvitationID: invitationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	return token.SignedString(m.invitationKey())
+}
+
+// ValidateInvitationToken returns the invitation ID of a valid, unexpired invitation token
+func (m *AuthMiddleware) ValidateInvitationToken(tokenString string) (uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InvitationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return m.invitationKey(), nil
+	})
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if claims, ok := token.Claims.(*InvitationClaims); ok && token.Valid && claims.InvitationID != uuid.Nil {
+		return claims.InvitationID, nil
+	}
+
+	return uuid.Nil, errors.New("invalid token")
+}
+
+func (m *AuthMiddleware) invitationKey() []byte {
+	return append(append([]byte{}, m.secretKey...), invitationTokenPurpose...)
+}
+
+// passwordResetTokenPurpose separates the password reset signing key from the other token keys
+const passwordResetTokenPurpose = ":password_reset"
+
+type PasswordResetClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	// PasswordFingerprint ties the token to the password it replaces, so it stops working once used
+	PasswordFingerprint string `json:"pwf"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePasswordResetToken signs the permission to set a new password, given once a reset code is verified
+func (m *AuthMiddleware) GeneratePasswordResetToken(userID uuid.UUID, passwordFingerprint string, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, PasswordResetClaims{
+		UserID:              userID,
+		PasswordFingerprint: passwordFingerprint,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+
+	return token.SignedString(m.passwordResetKey())
+}
+
+// ValidatePasswordResetToken returns the claims of a valid, unexpired password reset token
+func (m *AuthMiddleware) ValidatePasswordResetToken(tokenString string) (*PasswordResetClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PasswordResetClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return m.passwordResetKey(), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*PasswordResetClaims); ok && token.Valid && claims.UserID != uuid.Nil {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+func (m *AuthMiddleware) passwordResetKey() []byte {
+	return append(append([]byte{}, m.secretKey...), passwordResetTokenPurpose...)
+}
+
+// emailVerificationTokenPurpose separates the email verification signing key from the other token keys
+const emailVerificationTokenPurpose = ":email_verification"
+
+type EmailVerificationClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	// Email is the address the link was sent to, the link stops working once the user changes it
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateEmailVerificationToken signs the link that verifies an email address of a user
+func (m *AuthMiddleware) GenerateEmailVerificationToken(userID uuid.UUID, email string, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	})
+
+	return token.SignedString(m.emailVerificationKey())
+}
+
+// ValidateEmailVerificationToken returns the claims of a valid, unexpired email verification token
+func (m *AuthMiddleware) ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return m.emailVerificationKey(), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*EmailVerificationClaims); ok && token.Valid && claims.UserID != uuid.Nil && claims.Email != "" {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+func (m *AuthMiddleware) emailVerificationKey() []byte {
+	return append(append([]byte{}, m.secretKey...), emailVerificationTokenPurpose...)
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestAuthRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := NewAuthMiddleware("secret")
+
+	request := func(token string) *httptest.ResponseRecorder {
+		engine := gin.New()
+		engine.GET("/v1/users", auth.AuthRequired(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		req.Header.Set(AuthorizationHeader, BearerPrefix+token)
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("given an access token of a session, when calling a route, then access is granted", func(t *testing.T) {
+		token, err := auth.GenerateToken(&entities.User{UserID: uuid.New()}, uuid.New())
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, request(token).Code)
+	})
+
+	t.Run("given an access token issued before sessions existed, when calling a route, then it is refused", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaims{
+			UserID: uuid.New(),
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		}).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token).Code)
+	})
+
+	t.Run("given no session, when generating an access token, then it fails", func(t *testing.T) {
+		_, err := auth.GenerateToken(&entities.User{UserID: uuid.New()}, uuid.Nil)
+		assert.EqualError(t, err, "access token requires a session")
+	})
+}
+
+func TestAdminRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := NewAuthMiddleware("secret")
+
+	request := func(systemRole int, roles ...int) *httptest.ResponseRecorder {
+		token, err := auth.GenerateToken(&entities.User{UserID: uuid.New(), SystemRole: systemRole}, uuid.New())
+		assert.NoError(t, err)
+
+		engine := gin.New()
+		engine.POST("/v0/banks", auth.AuthRequired(), auth.AdminRequired(roles...), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v0/banks", nil)
+		req.Header.Set(AuthorizationHeader, BearerPrefix+token)
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("given a regular user, when calling an admin route, then access is denied", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(entities.SystemRoleNone).Code)
+	})
+
+	t.Run("given any system role, when calling a route open to every admin, then access is granted", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(entities.SystemRoleReadOnly).Code)
+	})
+
+	t.Run("given a system role outside the allowed ones, when calling the route, then access is denied", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(entities.SystemRoleReadOnly, entities.SystemRoleSuperAdmin, entities.SystemRoleSupport).Code)
+		assert.Equal(t, http.StatusOK, request(entities.SystemRoleSupport, entities.SystemRoleSuperAdmin, entities.SystemRoleSupport).Code)
+	})
+}
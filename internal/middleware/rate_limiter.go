@@ -1,13 +1,358 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/redis"
+)
+
+// APIKeyHeader identifies integrations calling the API without a user token
+const APIKeyHeader = "X-API-Key"
+
+// Standard rate limit headers, see the IETF RateLimit header fields draft
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
 )
 
-// RateLimiterMiddleware provides rate limiting functionality
-func RateLimiterMiddleware() gin.HandlerFunc {
+type (
+	// RateLimit is the number of requests a caller may make per window
+	RateLimit struct {
+		Requests int
+		Window   time.Duration
+	}
+
+	// RateLimits are the limits of the route groups
+	RateLimits struct {
+		API  RateLimit // every route, per user, API key or IP address
+		Auth RateLimit // login, registration and the other unauthenticated auth routes, per IP address
+		OTP  RateLimit // routes sending or checking one-time codes, per phone number
+	}
+
+	// RateLimitKeyFunc identifies the caller a request is counted against
+	RateLimitKeyFunc func(c *gin.Context) string
+
+	// rateLimitStore counts requests in fixed windows
+	rateLimitStore interface {
+		// hit counts a request in the window of now and returns the counts of that window and the one before
+		hit(ctx context.Context, key string, window time.Duration, now time.Time) (current int64, previous int64, err error)
+	}
+
+	// RateLimiter limits requests with a sliding window counter kept in Redis,
+	// or in memory when Redis is not configured or unavailable
+	RateLimiter struct {
+		enabled bool
+		auth    *AuthMiddleware
+		redis   rateLimitStore
+		memory  *memoryRateLimitStore
+	}
+)
+
+// ParseRateLimit parses a limit written as requests per window, e.g. "10/1m"
+func ParseRateLimit(s string) (RateLimit, error) {
+	requests, window, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, expected requests/window e.g. 10/1m", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, requests must be a positive number", s)
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d < time.Second {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, window must be a duration of at least 1s", s)
+	}
+
+	return RateLimit{Requests: n, Window: d}, nil
+}
+
+// String writes the limit as requests per window
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// NewRateLimiter creates a rate limiter, cache may be nil to keep the counters in memory.
+// The auth middleware identifies the user of a request before AuthRequired runs.
+func NewRateLimiter(cache redis.Cache, auth *AuthMiddleware, enabled bool) *RateLimiter {
+	l := &RateLimiter{
+		enabled: enabled,
+		auth:    auth,
+		memory:  newMemoryRateLimitStore(),
+	}
+	if cache != nil {
+		l.redis = &redisRateLimitStore{cache: cache}
+	}
+	return l
+}
+
+// Limit counts the requests of the routes at paths, or of every route when no path is given, against the key
+// of the caller. Requests over the limit are refused with 429 Too Many Requests and a Retry-After header.
+// The name separates the counters of limits sharing a key.
+func (l *RateLimiter) Limit(name string, limit RateLimit, key RateLimitKeyFunc, paths ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// TODO: Implement rate limiting logic
+		if !l.enabled || !matchesPath(c.FullPath(), paths) {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		current, previous, err := l.hit(c.Request.Context(), "ratelimit:"+name+":"+key(c), limit.Window, now)
+		if err != nil {
+			// Counting failed everywhere, refusing every request would be worse than not limiting
+			utils.Log().Error().Err(err).Str("limit", name).Msg("rate limit check failed")
+			c.Next()
+			return
+		}
+
+		count, reset := slidingWindowCount(current, previous, limit.Window, now)
+		remaining := limit.Requests - int(math.Ceil(count))
+		if remaining < 0 {
+			remaining = 0
+		}
+		resetSeconds := int(math.Ceil(reset.Seconds()))
+
+		setRateLimitHeaders(c, limit, remaining, resetSeconds)
+
+		if count > float64(limit.Requests) {
+			retry := retryAfter(current, previous, limit, now)
+			c.Header(RetryAfterHeader, strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, try again later"})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
+
+// hit counts a request in Redis, falling back to memory when Redis fails
+func (l *RateLimiter) hit(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	if l.redis != nil {
+		current, previous, err := l.redis.hit(ctx, key, window, now)
+		if err == nil {
+			return current, previous, nil
+		}
+		utils.Log().Warn().Err(err).Msg("redis rate limit unavailable, counting in memory")
+	}
+	return l.memory.hit(ctx, key, window, now)
+}
+
+// KeyByClient identifies the caller by the user of a valid access token, else the API key, else the IP address
+func (l *RateLimiter) KeyByClient(c *gin.Context) string {
+	if token, ok := strings.CutPrefix(c.GetHeader(AuthorizationHeader), BearerPrefix); ok {
+		if claims, err := l.auth.ValidateToken(token); err == nil {
+			return "user:" + claims.UserID.String()
+		}
+	}
+
+	if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+		// Only a digest of the key ends up in Redis
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+
+	return KeyByIP(c)
+}
+
+// KeyByIP identifies the caller by IP address
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByPhoneNumber identifies the caller by the phone_number of the query or JSON body, else the IP address.
+// It spreads one-time code requests for a phone number over every IP address they come from.
+func KeyByPhoneNumber(c *gin.Context) string {
+	phoneNumber := c.Query("phone_number")
+
+	if phoneNumber == "" && c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err == nil {
+			// The handler binds the body again
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			var input struct {
+				PhoneNumber string `json:"phone_number"`
+			}
+			if json.Unmarshal(body, &input) == nil {
+				phoneNumber = input.PhoneNumber
+			}
+		}
+	}
+
+	phoneNumber = strings.TrimSpace(phoneNumber)
+	if phoneNumber == "" {
+		return KeyByIP(c)
+	}
+	return "phone:" + phoneNumber
+}
+
+// slidingWindowCount estimates the requests of the last window from the counts of the current fixed window
+// and the one before, weighting the previous count by how much of it still overlaps the last window.
+// It also returns how long until the current fixed window ends.
+func slidingWindowCount(current, previous int64, window time.Duration, now time.Time) (float64, time.Duration) {
+	elapsed := time.Duration(now.UnixNano() % int64(window))
+	overlap := 1 - float64(elapsed)/float64(window)
+	return float64(previous)*overlap + float64(current), window - elapsed
+}
+
+// retryAfter estimates how long until a refused caller may make a request again.
+// Within the current window the previous count fades out, after it the current count does.
+func retryAfter(current, previous int64, limit RateLimit, now time.Time) time.Duration {
+	window := float64(limit.Window)
+	elapsed := float64(now.UnixNano() % int64(limit.Window))
+	room := float64(limit.Requests - 1) // the retry itself counts
+
+	if previous > 0 && float64(current) <= room {
+		if wait := window*(1-(room-float64(current))/float64(previous)) - elapsed; wait < window-elapsed {
+			return time.Duration(math.Max(wait, float64(time.Second)))
+		}
+	}
+
+	fade := 0.0
+	if current > 0 {
+		fade = math.Max(0, 1-room/float64(current))
+	}
+	return time.Duration(window - elapsed + window*fade)
+}
+
+// setRateLimitHeaders reports the limit of the request, the strictest one when several limits apply
+func setRateLimitHeaders(c *gin.Context, limit RateLimit, remaining int, resetSeconds int) {
+	if existing := c.Writer.Header().Get(RateLimitRemainingHeader); existing != "" {
+		if n, err := strconv.Atoi(existing); err == nil && n <= remaining {
+			return
+		}
+	}
+
+	c.Header(RateLimitLimitHeader, strconv.Itoa(limit.Requests))
+	c.Header(RateLimitRemainingHeader, strconv.Itoa(remaining))
+	c.Header(RateLimitResetHeader, strconv.Itoa(resetSeconds))
+	c.Header(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window.Seconds())))
+}
+
+// matchesPath reports whether a route is one of paths, every route matches when there are none
+func matchesPath(fullPath string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, path := range paths {
+		if fullPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// windowIndex returns the index of the fixed window of now
+func windowIndex(window time.Duration, now time.Time) int64 {
+	return now.UnixNano() / int64(window)
+}
+
+// redisRateLimitStore keeps one counter per key and fixed window, kept for two windows
+type redisRateLimitStore struct {
+	cache redis.Cache
+}
+
+func (s *redisRateLimitStore) hit(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	index := windowIndex(window, now)
+
+	var incr *goredis.IntCmd
+	var prev *goredis.StringCmd
+	err := s.cache.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		incr = pipe.Incr(ctx, fmt.Sprintf("%s:%d", key, index))
+		pipe.PExpire(ctx, fmt.Sprintf("%s:%d", key, index), 2*window)
+		prev = pipe.Get(ctx, fmt.Sprintf("%s:%d", key, index-1))
+		return nil
+	})
+	// A missing previous window is not an error
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return 0, 0, err
+	}
+
+	previous, err := prev.Int64()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return 0, 0, err
+	}
+	return incr.Val(), previous, nil
+}
+
+// memoryRateLimitStore keeps the counters of this instance only
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryRateLimitCounter
+	lastSweep time.Time
+}
+
+type memoryRateLimitCounter struct {
+	window   time.Duration
+	index    int64
+	current  int64
+	previous int64
+}
+
+// memoryRateLimitSweepInterval is how often counters of past windows are dropped
+const memoryRateLimitSweepInterval = time.Minute
+
+func newMemoryRateLimitStore() *memoryRateLimitStore {
+	return &memoryRateLimitStore{
+		counters:  make(map[string]*memoryRateLimitCounter),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryRateLimitStore) hit(_ context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > memoryRateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	index := windowIndex(window, now)
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = &memoryRateLimitCounter{window: window, index: index}
+		s.counters[key] = counter
+	}
+
+	switch {
+	case counter.index == index-1:
+		counter.previous, counter.current = counter.current, 0
+	case counter.index < index-1:
+		counter.previous, counter.current = 0, 0
+	}
+	counter.index = index
+	counter.current++
+
+	return counter.current, counter.previous, nil
+}
+
+// sweep drops counters no longer counting towards any window
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	for key, counter := range s.counters {
+		if counter.index < windowIndex(counter.window, now)-1 {
+			delete(s.counters, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	t.Run("given a count and window, when parsing, then the rate limit is returned", func(t *testing.T) {
+		limit, err := ParseRateLimit("10/15m")
+		assert.NoError(t, err)
+		assert.Equal(t, RateLimit{Requests: 10, Window: 15 * time.Minute}, limit)
+		assert.Equal(t, "10/15m0s", limit.String())
+	})
+
+	t.Run("given a malformed limit, when parsing, then an error is returned", func(t *testing.T) {
+		for _, s := range []string{"", "10", "0/1m", "-1/1m", "ten/1m", "10/1ms", "10/soon"} {
+			_, err := ParseRateLimit(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestSlidingWindowCount(t *testing.T) {
+	window := time.Minute
+	start := time.Unix(0, 0).Add(1000 * window)
+
+	t.Run("given the start of a window, when counting, then the whole previous window counts", func(t *testing.T) {
+		count, reset := slidingWindowCount(1, 10, window, start)
+		assert.Equal(t, 11.0, count)
+		assert.Equal(t, window, reset)
+	})
+
+	t.Run("given the middle of a window, when counting, then half of the previous window counts", func(t *testing.T) {
+		count, reset := slidingWindowCount(1, 10, window, start.Add(30*time.Second))
+		assert.Equal(t, 6.0, count)
+		assert.Equal(t, 30*time.Second, reset)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	limit := RateLimit{Requests: 10, Window: time.Minute}
+	start := time.Unix(0, 0).Add(1000 * limit.Window)
+
+	t.Run("given a full previous window, when refused, then the wait ends once enough of it fades out", func(t *testing.T) {
+		retry := retryAfter(5, 10, limit, start)
+		assert.Equal(t, 36*time.Second, retry)
+	})
+
+	t.Run("given a full current window, when refused, then the wait runs into the next window", func(t *testing.T) {
+		retry := retryAfter(11, 0, limit, start.Add(30*time.Second))
+		assert.Greater(t, retry, 30*time.Second)
+		assert.LessOrEqual(t, retry, 30*time.Second+limit.Window)
+	})
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	window := time.Minute
+	start := time.Unix(0, 0).Add(1000 * window)
+	ctx := context.Background()
+
+	t.Run("given requests over several windows, when counting, then the current count moves to the previous one", func(t *testing.T) {
+		store := newMemoryRateLimitStore()
+
+		store.hit(ctx, "key", window, start)
+		current, previous, _ := store.hit(ctx, "key", window, start.Add(time.Second))
+		assert.Equal(t, int64(2), current)
+		assert.Equal(t, int64(0), previous)
+
+		current, previous, _ = store.hit(ctx, "key", window, start.Add(window))
+		assert.Equal(t, int64(1), current)
+		assert.Equal(t, int64(2), previous)
+
+		current, previous, _ = store.hit(ctx, "key", window, start.Add(3*window))
+		assert.Equal(t, int64(1), current)
+		assert.Equal(t, int64(0), previous)
+	})
+}
+
+func TestKeyByPhoneNumber(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("given a JSON body with a phone number, when keying, then the phone number is the key and the body is kept", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		body := `{"phone_number":"+6281234567890"}`
+		c.Request = httptest.NewRequest(http.MethodPost, "/v1/auth/forgot-password", strings.NewReader(body))
+
+		assert.Equal(t, "phone:+6281234567890", KeyByPhoneNumber(c))
+		rest, _ := io.ReadAll(c.Request.Body)
+		assert.Equal(t, body, string(rest))
+	})
+
+	t.Run("given no phone number, when keying, then the IP address is the key", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/v1/verification-codes/verify", strings.NewReader(`{"code":"123456"}`))
+		c.Request.RemoteAddr = "10.0.0.1:1234"
+
+		assert.Equal(t, "ip:10.0.0.1", KeyByPhoneNumber(c))
+	})
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(enabled bool) *gin.Engine {
+		limiter := NewRateLimiter(nil, NewAuthMiddleware("secret"), enabled)
+		engine := gin.New()
+		group := engine.Group("v1", limiter.Limit("auth", RateLimit{Requests: 2, Window: time.Hour}, KeyByIP, "/v1/auth/login"))
+		group.POST("/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+		group.GET("/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return engine
+	}
+
+	request := func(engine *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("given a limited route, when the limit is exceeded, then 429 is returned with Retry-After", func(t *testing.T) {
+		engine := newEngine(true)
+
+		w := request(engine, http.MethodPost, "/v1/auth/login")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "2;w=3600", w.Header().Get(RateLimitPolicyHeader))
+
+		request(engine, http.MethodPost, "/v1/auth/login")
+		w = request(engine, http.MethodPost, "/v1/auth/login")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		assert.NotEmpty(t, w.Header().Get(RetryAfterHeader))
+	})
+
+	t.Run("given a route outside the limit, when requested, then it is not counted", func(t *testing.T) {
+		engine := newEngine(true)
+
+		for i := 0; i < 3; i++ {
+			w := request(engine, http.MethodGet, "/v1/users")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+		}
+	})
+
+	t.Run("given rate limiting is disabled, when requested repeatedly, then nothing is refused", func(t *testing.T) {
+		engine := newEngine(false)
+
+		for i := 0; i < 3; i++ {
+			w := request(engine, http.MethodPost, "/v1/auth/login")
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+type sessionIDKey struct{}
+
+type sessionClientKey struct{}
+
+// WithSessionID marks the requests made with the context as coming from a session
+func WithSessionID(ctx context.Context, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the session of the access token of the request, uuid.Nil when it has none
+func SessionIDFromContext(ctx context.Context) uuid.UUID {
+	if sessionID, ok := ctx.Value(sessionIDKey{}).(uuid.UUID); ok {
+		return sessionID
+	}
+	return uuid.Nil
+}
+
+// WithSessionClient stores the device a session is opened or refreshed from
+func WithSessionClient(ctx context.Context, client entities.SessionClient) context.Context {
+	return context.WithValue(ctx, sessionClientKey{}, client)
+}
+
+// SessionClientFromContext returns the device a session is opened or refreshed from, empty when unknown
+func SessionClientFromContext(ctx context.Context) entities.SessionClient {
+	if client, ok := ctx.Value(sessionClientKey{}).(entities.SessionClient); ok {
+		return client
+	}
+	return entities.SessionClient{}
+}
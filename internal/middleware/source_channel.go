@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+// SourceChannelHeader tells which channel a request comes from, e.g. the WhatsApp bot calling the API
+const SourceChannelHeader = "X-Source-Channel"
+
+type sourceChannelKey struct{}
+
+// WithSourceChannel marks the changes made with the context as coming from a channel
+func WithSourceChannel(ctx context.Context, channel string) context.Context {
+	return context.WithValue(ctx, sourceChannelKey{}, channel)
+}
+
+// SourceChannelFromContext returns the channel the changes made with the context come from, web by default
+func SourceChannelFromContext(ctx context.Context) string {
+	if channel, ok := ctx.Value(sourceChannelKey{}).(string); ok {
+		return channel
+	}
+	return entities.ChannelWeb
+}
+
+// SourceChannel stores the channel of the request in its context, unknown channels count as web
+func SourceChannel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch channel := c.GetHeader(SourceChannelHeader); channel {
+		case entities.ChannelWhatsApp, entities.ChannelAPI:
+			c.Request = c.Request.WithContext(WithSourceChannel(c.Request.Context(), channel))
+		}
+		c.Next()
+	}
+}
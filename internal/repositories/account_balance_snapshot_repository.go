@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	accountBalanceSnapshotRepository struct {
+		*postgres.Postgres
+	}
+
+	AccountBalanceSnapshotRepository interface {
+		SnapshotActiveAccounts(ctx context.Context) (int64, error)
+		BackfillMissingHistory(ctx context.Context) (int64, error)
+		FindNetWorthSeries(ctx context.Context, userID uuid.UUID, currencyID int, interval string, startDate, endDate time.Time) ([]*entities.NetWorthPoint, error)
+		FindCurrentBalances(ctx context.Context, userID uuid.UUID, currencyID int) ([]*entities.NetWorthAccount, error)
+	}
+)
+
+// NewAccountBalanceSnapshotRepository creates a new AccountBalanceSnapshotRepository
+func NewAccountBalanceSnapshotRepository(pg *postgres.Postgres) AccountBalanceSnapshotRepository {
+	return &accountBalanceSnapshotRepository{pg}
+}
+
+// SnapshotActiveAccounts records the current balance of every active account for today in the owner's timezone.
+// The balance in the owner's currency is NULL when no exchange rate is recorded for the day.
+// Running it again on the same day overwrites the snapshot, so the last run of a day holds the end of day balance.
+func (r *accountBalanceSnapshotRepository) SnapshotActiveAccounts(ctx context.Context) (int64, error) {
+	query := `
+		INSERT INTO "vasst_expense".account_balance_snapshots (
+			account_id, user_id, snapshot_date, account_type, balance, currency_id, user_balance, user_currency_id
+		)
+		SELECT a.account_id, a.user_id, s.snapshot_date, a.account_type, a.current_balance, a.currency_id,
+		       ` + convertAmountSQL("a.current_balance", "a.currency_id", "u.currency_id", "s.snapshot_date") + `,
+		       u.currency_id
+		FROM "vasst_expense".accounts a
+		INNER JOIN "vasst_expense".users u ON a.user_id = u.user_id
+		CROSS JOIN LATERAL (SELECT (CURRENT_TIMESTAMP AT TIME ZONE u.timezone)::date as snapshot_date) s
+		WHERE a.is_active = true
+		ON CONFLICT (account_id, snapshot_date) DO UPDATE SET
+			account_type = EXCLUDED.account_type,
+			balance = EXCLUDED.balance,
+			currency_id = EXCLUDED.currency_id,
+			user_balance = EXCLUDED.user_balance,
+			user_currency_id = EXCLUDED.user_currency_id,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	result, err := r.DB.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// BackfillMissingHistory derives the daily balances of every active account with transactions dated before its
+// first snapshot, up to yesterday. It covers accounts snapshotted before their past transactions were recorded,
+// e.g. a new account whose statement is imported later. Existing snapshots are kept.
+func (r *accountBalanceSnapshotRepository) BackfillMissingHistory(ctx context.Context) (int64, error) {
+	return r.backfill(ctx, `a.is_active = true AND EXISTS (
+		SELECT 1 FROM "vasst_expense".transactions t
+		WHERE (t.account_id = a.account_id OR t.transfer_account_id = a.account_id)
+		AND t.transaction_date < COALESCE(
+			(SELECT MIN(s.snapshot_date) FROM "vasst_expense".account_balance_snapshots s WHERE s.account_id = a.account_id),
+			'infinity'::date
+		)
+	)`)
+}
+
+// backfill walks back from the current balance: the balance at the end of a day is the current balance
+// minus everything that moved after that day. Liability balances are amounts owed, so their movements are reversed.
+func (r *accountBalanceSnapshotRepository) backfill(ctx context.Context, condition string, args ...interface{}) (int64, error) {
+	query := `
+		WITH backfill_accounts AS (
+			SELECT a.account_id, a.user_id, a.account_type, a.current_balance, a.currency_id,
+			       u.currency_id as user_currency_id,
+			       (CURRENT_TIMESTAMP AT TIME ZONE u.timezone)::date as today,
+			       CASE WHEN a.account_type = ANY($1) THEN -1 ELSE 1 END as direction
+			FROM "vasst_expense".accounts a
+			INNER JOIN "vasst_expense".users u ON a.user_id = u.user_id
+			WHERE ` + condition + `
+		),
+		movements AS (
+			SELECT t.account_id, t.transaction_date,
+			       CASE WHEN t.transaction_type = 1 THEN t.amount ELSE -t.amount END as delta
+			FROM "vasst_expense".transactions t
+			WHERE t.account_id IN (SELECT account_id FROM backfill_accounts)
+			UNION ALL
+			SELECT t.transfer_account_id, t.transaction_date,
+			       CASE WHEN t.transaction_type = 1 THEN -t.amount ELSE t.amount END
+			FROM "vasst_expense".transactions t
+			WHERE t.transfer_account_id IN (SELECT account_id FROM backfill_accounts)
+		),
+		days AS (
+			SELECT ba.*, d::date as snapshot_date
+			FROM backfill_accounts ba
+			CROSS JOIN LATERAL generate_series(
+				(SELECT MIN(m.transaction_date) FROM movements m WHERE m.account_id = ba.account_id)::timestamp,
+				(ba.today - 1)::timestamp,
+				interval '1 day'
+			) d
+		),
+		balances AS (
+			SELECT d.account_id, d.user_id, d.snapshot_date, d.account_type, d.currency_id, d.user_currency_id,
+			       d.current_balance - d.direction * COALESCE((
+			           SELECT SUM(m.delta) FROM movements m
+			           WHERE m.account_id = d.account_id AND m.transaction_date > d.snapshot_date
+			       ), 0) as balance
+			FROM days d
+		)
+		INSERT INTO "vasst_expense".account_balance_snapshots (
+			account_id, user_id, snapshot_date, account_type, balance, currency_id, user_balance, user_currency_id
+		)
+		SELECT b.account_id, b.user_id, b.snapshot_date, b.account_type, b.balance, b.currency_id,
+		       ` + convertAmountSQL("b.balance", "b.currency_id", "b.user_currency_id", "b.snapshot_date") + `,
+		       b.user_currency_id
+		FROM balances b
+		ON CONFLICT (account_id, snapshot_date) DO NOTHING
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, append([]interface{}{pq.Int64Array(entities.LiabilityAccountTypes)}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// FindNetWorthSeries returns the assets and liabilities of a user per day, or at the last snapshot of every week or month,
+// converted into currencyID. Snapshots taken in another user currency are converted at the rate of their day,
+// balances without a rate are left out and counted.
+func (r *accountBalanceSnapshotRepository) FindNetWorthSeries(ctx context.Context, userID uuid.UUID, currencyID int, interval string, startDate, endDate time.Time) ([]*entities.NetWorthPoint, error) {
+	query := `
+		WITH daily AS (
+			SELECT s.snapshot_date,
+			       COALESCE(SUM(c.amount) FILTER (WHERE NOT s.account_type = ANY($5)), 0) as assets,
+			       COALESCE(SUM(c.amount) FILTER (WHERE s.account_type = ANY($5)), 0) as liabilities,
+			       COUNT(*) FILTER (WHERE c.amount IS NULL) as unconverted_count
+			FROM "vasst_expense".account_balance_snapshots s
+			CROSS JOIN LATERAL (
+				SELECT CASE WHEN s.user_currency_id = $2 AND s.user_balance IS NOT NULL THEN s.user_balance
+				       ELSE ` + convertAmountSQL("s.balance", "s.currency_id", "$2::int", "s.snapshot_date") + ` END as amount
+			) c
+			WHERE s.user_id = $1 AND s.snapshot_date BETWEEN $3 AND $4
+			GROUP BY s.snapshot_date
+		)
+		SELECT DISTINCT ON (date_trunc($6, snapshot_date)) snapshot_date, assets, liabilities, unconverted_count
+		FROM daily
+		ORDER BY date_trunc($6, snapshot_date), snapshot_date DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, currencyID, startDate, endDate, pq.Int64Array(entities.LiabilityAccountTypes), interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []*entities.NetWorthPoint
+	for rows.Next() {
+		var point entities.NetWorthPoint
+		err := rows.Scan(
+			&point.Date,
+			&point.Assets,
+			&point.Liabilities,
+			&point.UnconvertedCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, &point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// FindCurrentBalances returns the current balance of every active account of a user, converted into currencyID.
+// The converted balance is NULL when no exchange rate is recorded for the account currency.
+func (r *accountBalanceSnapshotRepository) FindCurrentBalances(ctx context.Context, userID uuid.UUID, currencyID int) ([]*entities.NetWorthAccount, error) {
+	query := `
+		SELECT a.account_id, a.account_name, a.account_type, a.current_balance, a.currency_id,
+		       ` + convertAmountSQL("a.current_balance", "a.currency_id", "$2::int", "CURRENT_DATE") + ` as converted_balance
+		FROM "vasst_expense".accounts a
+		WHERE a.user_id = $1 AND a.is_active = true
+		ORDER BY converted_balance DESC NULLS LAST, a.account_name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, currencyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entities.NetWorthAccount
+	for rows.Next() {
+		var account entities.NetWorthAccount
+		err := rows.Scan(
+			&account.AccountID,
+			&account.AccountName,
+			&account.AccountType,
+			&account.Balance,
+			&account.CurrencyID,
+			&account.ConvertedBalance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, &account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	activityRepository struct {
+		*postgres.Postgres
+	}
+
+	// ActivityRepository defines methods for interacting with the audit log and the workspace activity feed in the database
+	ActivityRepository interface {
+		Record(ctx context.Context, auditLog *entities.AuditLog, activities []*entities.WorkspaceActivity) error
+		FindByWorkspace(ctx context.Context, params *entities.ActivityListParams, limit, offset int) ([]*entities.WorkspaceActivity, error)
+		CountByWorkspace(ctx context.Context, params *entities.ActivityListParams) (int64, error)
+	}
+)
+
+// NewActivityRepository creates a new ActivityRepository
+func NewActivityRepository(pg *postgres.Postgres) ActivityRepository {
+	return &activityRepository{pg}
+}
+
+// Record writes the audit log of a change and its activity feed entries in a single transaction
+func (r *activityRepository) Record(ctx context.Context, auditLog *entities.AuditLog, activities []*entities.WorkspaceActivity) error {
+	oldValues, err := nullableJSON(auditLog.OldValues)
+	if err != nil {
+		return err
+	}
+	newValues, err := nullableJSON(auditLog.NewValues)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "vasst_expense".audit_logs (
+			audit_log_id, workspace_id, user_id, action, resource_type, resource_id,
+			old_values, new_values, source_type, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP)
+	`,
+		auditLog.AuditLogID,
+		auditLog.WorkspaceID,
+		auditLog.UserID,
+		auditLog.Action,
+		auditLog.ResourceType,
+		auditLog.ResourceID,
+		oldValues,
+		newValues,
+		auditLog.SourceType,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "vasst_expense".workspace_activities (
+			activity_id, workspace_id, actor_id, action, resource_type, resource_id,
+			channel, ai_generated, summary_id, summary_en, audit_log_id, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, CURRENT_TIMESTAMP)
+	`
+	for _, activity := range activities {
+		_, err = tx.ExecContext(ctx, query,
+			activity.ActivityID,
+			activity.WorkspaceID,
+			activity.ActorID,
+			activity.Action,
+			activity.ResourceType,
+			activity.ResourceID,
+			activity.Channel,
+			activity.AIGenerated,
+			activity.SummaryID,
+			activity.SummaryEN,
+			activity.AuditLogID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByWorkspace returns the activity feed of a workspace, newest first
+func (r *activityRepository) FindByWorkspace(ctx context.Context, params *entities.ActivityListParams, limit, offset int) ([]*entities.WorkspaceActivity, error) {
+	where, args := activityFilter(params)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT a.activity_id, a.workspace_id, a.actor_id, COALESCE(TRIM(u.first_name || ' ' || u.last_name), ''),
+		       a.action, a.resource_type, a.resource_id, a.channel, a.ai_generated,
+		       a.summary_id, a.summary_en, a.audit_log_id, a.created_at
+		FROM "vasst_expense".workspace_activities a
+		LEFT JOIN "vasst_expense".users u ON a.actor_id = u.user_id
+		WHERE %s
+		ORDER BY a.created_at DESC, a.activity_id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []*entities.WorkspaceActivity
+	for rows.Next() {
+		var activity entities.WorkspaceActivity
+		err := rows.Scan(
+			&activity.ActivityID,
+			&activity.WorkspaceID,
+			&activity.ActorID,
+			&activity.ActorName,
+			&activity.Action,
+			&activity.ResourceType,
+			&activity.ResourceID,
+			&activity.Channel,
+			&activity.AIGenerated,
+			&activity.SummaryID,
+			&activity.SummaryEN,
+			&activity.AuditLogID,
+			&activity.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, &activity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// CountByWorkspace counts the activity feed entries of a workspace matching the filters
+func (r *activityRepository) CountByWorkspace(ctx context.Context, params *entities.ActivityListParams) (int64, error) {
+	where, args := activityFilter(params)
+	query := `SELECT COUNT(*) FROM "vasst_expense".workspace_activities a WHERE ` + where
+
+	var count int64
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// activityFilter builds the WHERE clause (alias a) of the activity feed filters
+func activityFilter(params *entities.ActivityListParams) (string, []interface{}) {
+	where := "a.workspace_id = $1"
+	args := []interface{}{params.WorkspaceID}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if params.ActorID != nil {
+		add("a.actor_id = $%d", *params.ActorID)
+	}
+	if params.ResourceType != "" {
+		add("a.resource_type = $%d", params.ResourceType)
+	}
+	if params.Action != "" {
+		add("a.action = $%d", params.Action)
+	}
+	if params.Channel != "" {
+		add("a.channel = $%d", params.Channel)
+	}
+	if params.AIGenerated != nil {
+		add("a.ai_generated = $%d", *params.AIGenerated)
+	}
+	if params.StartDate != nil {
+		add("a.created_at >= $%d", *params.StartDate)
+	}
+	if params.EndDate != nil {
+		add("a.created_at < $%d", *params.EndDate)
+	}
+
+	return where, args
+}
+
+// nullableJSON marshals a value for a JSONB column, nil stays NULL
+func nullableJSON(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
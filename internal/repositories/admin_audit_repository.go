@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	adminAuditRepository struct {
+		*postgres.Postgres
+	}
+
+	// AdminAuditRepository defines methods for interacting with the audit log of the administration API in the database
+	AdminAuditRepository interface {
+		Create(ctx context.Context, auditLog *entities.AdminAuditLog) error
+		FindAll(ctx context.Context, params *entities.AdminAuditListParams, limit, offset int) ([]*entities.AdminAuditLog, error)
+		Count(ctx context.Context, params *entities.AdminAuditListParams) (int64, error)
+	}
+)
+
+// NewAdminAuditRepository creates a new AdminAuditRepository
+func NewAdminAuditRepository(pg *postgres.Postgres) AdminAuditRepository {
+	return &adminAuditRepository{pg}
+}
+
+// Create writes an admin request to audit_logs, outside of any workspace
+func (r *adminAuditRepository) Create(ctx context.Context, auditLog *entities.AdminAuditLog) error {
+	request, err := nullableJSON(auditLog.Request)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "vasst_expense".audit_logs (
+			audit_log_id, user_id, action, resource_type, resource_id,
+			new_values, ip_address, user_agent, source_type, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::inet, $8, $9, CURRENT_TIMESTAMP)
+	`
+
+	_, err = r.DB.ExecContext(ctx, query,
+		auditLog.AuditLogID,
+		auditLog.AdminID,
+		auditLog.Action,
+		auditLog.ResourceType,
+		auditLog.ResourceID,
+		request,
+		auditLog.IPAddress,
+		auditLog.UserAgent,
+		entities.AuditSourceAdmin,
+	)
+	return err
+}
+
+// FindAll returns the audit log of the administration API, newest first
+func (r *adminAuditRepository) FindAll(ctx context.Context, params *entities.AdminAuditListParams, limit, offset int) ([]*entities.AdminAuditLog, error) {
+	where, args := adminAuditFilter(params)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT a.audit_log_id, a.user_id, COALESCE(TRIM(u.first_name || ' ' || u.last_name), ''),
+		       a.action, a.resource_type, a.resource_id, a.new_values,
+		       COALESCE(host(a.ip_address), ''), COALESCE(a.user_agent, ''), a.created_at
+		FROM "vasst_expense".audit_logs a
+		LEFT JOIN "vasst_expense".users u ON a.user_id = u.user_id
+		WHERE %s
+		ORDER BY a.created_at DESC, a.audit_log_id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []*entities.AdminAuditLog
+	for rows.Next() {
+		var auditLog entities.AdminAuditLog
+		var request []byte
+		err := rows.Scan(
+			&auditLog.AuditLogID,
+			&auditLog.AdminID,
+			&auditLog.AdminName,
+			&auditLog.Action,
+			&auditLog.ResourceType,
+			&auditLog.ResourceID,
+			&request,
+			&auditLog.IPAddress,
+			&auditLog.UserAgent,
+			&auditLog.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(request) > 0 {
+			if err := json.Unmarshal(request, &auditLog.Request); err != nil {
+				return nil, err
+			}
+		}
+		auditLogs = append(auditLogs, &auditLog)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+// Count counts the audit logs of the administration API matching the filters
+func (r *adminAuditRepository) Count(ctx context.Context, params *entities.AdminAuditListParams) (int64, error) {
+	where, args := adminAuditFilter(params)
+	query := `SELECT COUNT(*) FROM "vasst_expense".audit_logs a WHERE ` + where
+
+	var count int64
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// adminAuditFilter builds the WHERE clause (alias a) of the admin audit log filters
+func adminAuditFilter(params *entities.AdminAuditListParams) (string, []interface{}) {
+	where := "a.source_type = $1"
+	args := []interface{}{entities.AuditSourceAdmin}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		where += fmt.Sprintf(" AND "+condition, len(args))
+	}
+	if params.AdminID != nil {
+		add("a.user_id = $%d", *params.AdminID)
+	}
+	if params.ResourceType != "" {
+		add("a.resource_type = $%d", params.ResourceType)
+	}
+	if params.ResourceID != nil {
+		add("a.resource_id = $%d", *params.ResourceID)
+	}
+	if params.Action != "" {
+		add("a.action = $%d", params.Action)
+	}
+	if params.StartDate != nil {
+		add("a.created_at >= $%d", *params.StartDate)
+	}
+	if params.EndDate != nil {
+		add("a.created_at < $%d", *params.EndDate)
+	}
+
+	return where, args
+}
@@ -0,0 +1,356 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	analyticsRepository struct {
+		*postgres.Postgres
+	}
+
+	AnalyticsRepository interface {
+		FindTotals(ctx context.Context, query *entities.AnalyticsQuery) (*entities.AnalyticsTotals, error)
+		FindBreakdown(ctx context.Context, query *entities.AnalyticsQuery, groupBy string) ([]*entities.AnalyticsBreakdownRow, error)
+		FindCategoryBreakdown(ctx context.Context, query *entities.AnalyticsQuery) ([]*entities.AnalyticsBreakdownRow, error)
+		FindTimeSeries(ctx context.Context, query *entities.AnalyticsQuery, interval string) ([]*entities.AnalyticsTimeSeriesPoint, error)
+	}
+)
+
+// NewAnalyticsRepository creates a new AnalyticsRepository
+func NewAnalyticsRepository(pg *postgres.Postgres) AnalyticsRepository {
+	return &analyticsRepository{pg}
+}
+
+// analyticsFrom is the FROM clause shared by the analytics aggregates. The account currency is the
+// currency of a transaction, transactions without an account are in the workspace currency.
+const analyticsFrom = `
+		FROM "vasst_expense".transactions t
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+`
+
+// analyticsAmountSQL converts the transaction amount into the requested currency ($3)
+var analyticsAmountSQL = convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "$3::int", "t.transaction_date")
+
+// analyticsRollupFrom reads the daily rollups instead of the transactions. Rollups share the column names
+// of transactions, so the same filters and grouping apply.
+const analyticsRollupFrom = `
+		FROM "vasst_expense".transaction_daily_rollups t
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+`
+
+// analyticsRollupAmountSQL converts the rollup amount into the requested currency ($3) at the rate of its day,
+// the same rate the transactions of that day are converted at
+var analyticsRollupAmountSQL = convertAmountSQL("t.amount", "t.currency_id", "$3::int", "t.transaction_date")
+
+// analyticsSource holds the FROM clause and aggregate expressions of an analytics query. unconverted counts the
+// transactions left out of the amount because no exchange rate is recorded for their currency.
+type analyticsSource struct {
+	from        string
+	amount      string
+	count       string
+	unconverted string
+}
+
+var (
+	transactionAnalyticsSource = analyticsSource{
+		from:        analyticsFrom,
+		amount:      analyticsAmountSQL,
+		count:       "COUNT(*)",
+		unconverted: "COUNT(*) FILTER (WHERE " + analyticsAmountSQL + " IS NULL)",
+	}
+	rollupAnalyticsSource = analyticsSource{
+		from:        analyticsRollupFrom,
+		amount:      analyticsRollupAmountSQL,
+		count:       "COALESCE(SUM(t.transaction_count), 0)",
+		unconverted: "COALESCE(SUM(t.transaction_count) FILTER (WHERE " + analyticsRollupAmountSQL + " IS NULL), 0)",
+	}
+)
+
+// analyticsFromRollups tells whether a query can be answered from the daily rollups: its filters
+// only use the date, category and account a rollup is keyed by
+func analyticsFromRollups(query *entities.AnalyticsQuery) bool {
+	params := query.Filters
+	if params == nil {
+		return true
+	}
+	return params.PaymentMethod == nil && params.Description == nil && params.MerchantName == nil &&
+		params.Amount == nil && params.IsRecurring == nil && params.CreditStatus == nil
+}
+
+// analyticsSourceFor picks the source of a query and builds its WHERE clause
+func analyticsSourceFor(query *entities.AnalyticsQuery) (analyticsSource, string, []interface{}) {
+	fromRollups := analyticsFromRollups(query)
+	where, args := analyticsWhere(query, fromRollups)
+	if fromRollups {
+		return rollupAnalyticsSource, where, args
+	}
+	return transactionAnalyticsSource, where, args
+}
+
+// analyticsWhere builds the WHERE clause of an analytics query. The first three arguments are always
+// the workspace, the transaction type and the target currency. Transfers between own accounts and expenses
+// waiting for approval are excluded, rollups never contain them.
+func analyticsWhere(query *entities.AnalyticsQuery, fromRollups bool) (string, []interface{}) {
+	where := " WHERE t.workspace_id = $1 AND t.transaction_type = $2"
+	if !fromRollups {
+		where += " AND t.transfer_account_id IS NULL AND t.approval_status IN " + countedApprovalStatuses
+	}
+	args := []interface{}{query.WorkspaceID, query.TransactionType, query.CurrencyID}
+	argIndex := 4
+
+	params := query.Filters
+	if params != nil {
+		if params.AccountID != nil {
+			where += fmt.Sprintf(" AND t.account_id = $%d", argIndex)
+			args = append(args, *params.AccountID)
+			argIndex++
+		}
+		if params.CategoryID != nil {
+			where += fmt.Sprintf(" AND t.category_id = $%d", argIndex)
+			args = append(args, *params.CategoryID)
+			argIndex++
+		}
+		if params.StartDate != nil {
+			where += fmt.Sprintf(" AND t.transaction_date >= $%d", argIndex)
+			args = append(args, *params.StartDate)
+			argIndex++
+		}
+		if params.EndDate != nil {
+			where += fmt.Sprintf(" AND t.transaction_date <= $%d", argIndex)
+			args = append(args, *params.EndDate)
+			argIndex++
+		}
+		if params.PaymentMethod != nil {
+			where += fmt.Sprintf(" AND t.payment_method = $%d", argIndex)
+			args = append(args, *params.PaymentMethod)
+			argIndex++
+		}
+		if params.Description != nil {
+			where += fmt.Sprintf(" AND t.description ILIKE $%d", argIndex)
+			args = append(args, "%"+*params.Description+"%")
+			argIndex++
+		}
+		if params.MerchantName != nil {
+			where += fmt.Sprintf(" AND t.merchant_name ILIKE $%d", argIndex)
+			args = append(args, "%"+*params.MerchantName+"%")
+			argIndex++
+		}
+		if params.Amount != nil {
+			where += fmt.Sprintf(" AND t.amount = $%d", argIndex)
+			args = append(args, *params.Amount)
+			argIndex++
+		}
+		if params.IsRecurring != nil {
+			where += fmt.Sprintf(" AND t.is_recurring = $%d", argIndex)
+			args = append(args, *params.IsRecurring)
+			argIndex++
+		}
+		if params.CreditStatus != nil {
+			where += fmt.Sprintf(" AND t.credit_status = $%d", argIndex)
+			args = append(args, *params.CreditStatus)
+		}
+	}
+
+	return where, args
+}
+
+// FindTotals returns the total converted amount and the transaction count of a query
+func (r *analyticsRepository) FindTotals(ctx context.Context, query *entities.AnalyticsQuery) (*entities.AnalyticsTotals, error) {
+	source, where, args := analyticsSourceFor(query)
+	sqlQuery := `SELECT COALESCE(SUM(` + source.amount + `), 0), ` + source.count + `, ` + source.unconverted + source.from + where
+
+	var totals entities.AnalyticsTotals
+	if err := r.DB.QueryRowContext(ctx, sqlQuery, args...).Scan(&totals.Amount, &totals.TransactionCount, &totals.UnconvertedCount); err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// FindBreakdown returns the converted totals grouped by merchant, account, tag or payment method, largest first.
+// A transaction with several tags counts towards each of its tags.
+func (r *analyticsRepository) FindBreakdown(ctx context.Context, query *entities.AnalyticsQuery, groupBy string) ([]*entities.AnalyticsBreakdownRow, error) {
+	var keyExpr, labelExpr, joins string
+	switch groupBy {
+	case entities.AnalyticsGroupByMerchant:
+		keyExpr = "COALESCE(LOWER(TRIM(t.merchant_name)), '')"
+		labelExpr = "COALESCE(MIN(TRIM(t.merchant_name)), '')"
+	case entities.AnalyticsGroupByAccount:
+		keyExpr = "COALESCE(t.account_id::text, '')"
+		labelExpr = "COALESCE(MIN(a.account_name), '')"
+	case entities.AnalyticsGroupByTag:
+		joins = `
+		LEFT JOIN "vasst_expense".transaction_tags tt ON t.transaction_id = tt.transaction_id
+		LEFT JOIN "vasst_expense".user_tags ut ON tt.user_tag_id = ut.user_tag_id
+`
+		keyExpr = "COALESCE(ut.user_tag_id::text, '')"
+		labelExpr = "COALESCE(MIN(ut.name), '')"
+	case entities.AnalyticsGroupByPaymentMethod:
+		keyExpr = "COALESCE(t.payment_method::text, '')"
+		labelExpr = "''"
+	default:
+		return nil, fmt.Errorf("unsupported analytics group by: %s", groupBy)
+	}
+
+	// Only the account breakdown can be read from the rollups, the other groups are not part of their key
+	fromRollups := groupBy == entities.AnalyticsGroupByAccount && analyticsFromRollups(query)
+	where, args := analyticsWhere(query, fromRollups)
+	source := transactionAnalyticsSource
+	countExpr := "COUNT(DISTINCT t.transaction_id)"
+	unconvertedExpr := "COUNT(DISTINCT t.transaction_id) FILTER (WHERE " + analyticsAmountSQL + " IS NULL)"
+	if fromRollups {
+		source, countExpr, unconvertedExpr = rollupAnalyticsSource, rollupAnalyticsSource.count, rollupAnalyticsSource.unconverted
+	}
+
+	sqlQuery := `
+		SELECT ` + keyExpr + ` as group_key, ` + labelExpr + ` as group_label,
+		       COALESCE(SUM(` + source.amount + `), 0) as amount,
+		       ` + countExpr + ` as transaction_count,
+		       ` + unconvertedExpr + ` as unconverted_count` +
+		source.from + joins + where + `
+		GROUP BY group_key
+		ORDER BY amount DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []*entities.AnalyticsBreakdownRow
+	for rows.Next() {
+		var row entities.AnalyticsBreakdownRow
+		if err := rows.Scan(&row.Key, &row.Label, &row.Amount, &row.TransactionCount, &row.UnconvertedCount); err != nil {
+			return nil, err
+		}
+
+		breakdown = append(breakdown, &row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// FindCategoryBreakdown returns the converted totals per user category together with the
+// path of its system category from the top level parent down
+func (r *analyticsRepository) FindCategoryBreakdown(ctx context.Context, query *entities.AnalyticsQuery) ([]*entities.AnalyticsBreakdownRow, error) {
+	source, where, args := analyticsSourceFor(query)
+	sqlQuery := `
+		WITH RECURSIVE category_path AS (
+			SELECT category_id, ARRAY[category_id::text] as path_ids, ARRAY[name::text] as path_names
+			FROM "vasst_expense".categories
+			WHERE parent_category_id IS NULL
+			UNION ALL
+			SELECT c.category_id, cp.path_ids || c.category_id::text, cp.path_names || c.name::text
+			FROM "vasst_expense".categories c
+			INNER JOIN category_path cp ON c.parent_category_id = cp.category_id
+		)
+		SELECT COALESCE(t.category_id::text, '') as group_key,
+		       COALESCE(MIN(uc.name), '') as group_label,
+		       COALESCE(MIN(cp.path_ids), ARRAY[]::text[]) as path_ids,
+		       COALESCE(MIN(cp.path_names), ARRAY[]::text[]) as path_names,
+		       COALESCE(SUM(` + source.amount + `), 0) as amount,
+		       ` + source.count + ` as transaction_count,
+		       ` + source.unconverted + ` as unconverted_count` +
+		source.from + `
+		LEFT JOIN "vasst_expense".user_categories uc ON t.category_id = uc.user_category_id
+		LEFT JOIN category_path cp ON uc.category_id = cp.category_id
+` + where + `
+		GROUP BY group_key
+		ORDER BY amount DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []*entities.AnalyticsBreakdownRow
+	for rows.Next() {
+		var row entities.AnalyticsBreakdownRow
+		var pathIDs, pathNames pq.StringArray
+		if err := rows.Scan(&row.Key, &row.Label, &pathIDs, &pathNames, &row.Amount, &row.TransactionCount, &row.UnconvertedCount); err != nil {
+			return nil, err
+		}
+
+		row.CategoryPathIDs = pathIDs
+		row.CategoryPathNames = pathNames
+		breakdown = append(breakdown, &row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// FindTimeSeries returns the converted totals per day, week or month, oldest first. Weeks and months start
+// on the week start day and fiscal month start day of the query. Periods without transactions are not returned.
+func (r *analyticsRepository) FindTimeSeries(ctx context.Context, query *entities.AnalyticsQuery, interval string) ([]*entities.AnalyticsTimeSeriesPoint, error) {
+	switch interval {
+	case entities.AnalyticsIntervalDay, entities.AnalyticsIntervalWeek, entities.AnalyticsIntervalMonth:
+	default:
+		return nil, fmt.Errorf("unsupported analytics interval: %s", interval)
+	}
+
+	// date_trunc weeks start on Monday and months on the 1st, shift the dates by the configured start
+	var offsetDays int
+	switch interval {
+	case entities.AnalyticsIntervalWeek:
+		offsetDays = (query.WeekStartDay + 6) % 7
+	case entities.AnalyticsIntervalMonth:
+		if query.MonthStartDay > 1 {
+			offsetDays = query.MonthStartDay - 1
+		}
+	}
+	offset := fmt.Sprintf("interval '%d days'", offsetDays)
+
+	source, where, args := analyticsSourceFor(query)
+	sqlQuery := `
+		SELECT (date_trunc('` + interval + `', t.transaction_date - ` + offset + `) + ` + offset + `)::date as period_start,
+		       COALESCE(SUM(` + source.amount + `), 0) as amount,
+		       ` + source.count + ` as transaction_count,
+		       ` + source.unconverted + ` as unconverted_count` +
+		source.from + where + `
+		GROUP BY period_start
+		ORDER BY period_start ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []*entities.AnalyticsTimeSeriesPoint
+	for rows.Next() {
+		var point entities.AnalyticsTimeSeriesPoint
+		var periodStart time.Time
+		if err := rows.Scan(&periodStart, &point.Amount, &point.TransactionCount, &point.UnconvertedCount); err != nil {
+			return nil, err
+		}
+
+		point.PeriodStart = time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, time.UTC)
+		points = append(points, &point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
@@ -21,9 +21,127 @@ type (
 		FindByID(ctx context.Context, budgetID uuid.UUID) (*entities.Budget, error)
 		FindByIDWithWorkspace(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) (*entities.BudgetSimple, error)
 		FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error)
+		RefreshSpentAmount(ctx context.Context, budgetID uuid.UUID) (float64, error)
+		FindContributingTransactions(ctx context.Context, budgetID uuid.UUID, limit, offset int) ([]*entities.BudgetTransaction, error)
 	}
 )
 
+// budgetScopeFilter matches transactions (alias t) against the scope of a budget (alias b).
+// Only expenses inside the budget period are counted. An empty dimension matches everything.
+// Merchant names match anywhere in the merchant of a transaction, with % and _ taken literally.
+const budgetScopeFilter = `
+	t.workspace_id = b.workspace_id
+	AND t.transaction_type = 2
+	AND t.transfer_account_id IS NULL
+	AND t.transaction_date BETWEEN b.period_start AND b.period_end
+	AND (
+		COALESCE((b.scope->>'total_spend')::boolean, false)
+		OR (
+			(jsonb_array_length(COALESCE(b.scope->'category_ids', '[]'::jsonb)) = 0
+				OR t.category_id = ANY(ARRAY(SELECT jsonb_array_elements_text(b.scope->'category_ids'))::uuid[]))
+			AND (jsonb_array_length(COALESCE(b.scope->'account_ids', '[]'::jsonb)) = 0
+				OR t.account_id = ANY(ARRAY(SELECT jsonb_array_elements_text(b.scope->'account_ids'))::uuid[]))
+			AND (jsonb_array_length(COALESCE(b.scope->'tag_ids', '[]'::jsonb)) = 0
+				OR EXISTS (
+					SELECT 1 FROM "vasst_expense".transaction_tags tt
+					WHERE tt.transaction_id = t.transaction_id
+					AND tt.user_tag_id = ANY(ARRAY(SELECT jsonb_array_elements_text(b.scope->'tag_ids'))::uuid[])
+				))
+			AND (jsonb_array_length(COALESCE(b.scope->'merchants', '[]'::jsonb)) = 0
+				OR EXISTS (
+					SELECT 1 FROM jsonb_array_elements_text(b.scope->'merchants') m(name)
+					WHERE t.merchant_name ILIKE '%' || replace(replace(replace(m.name, '\', '\\'), '%', '\%'), '_', '\_') || '%'
+				))
+		)
+	)
+`
+
+// budgetNeedsTransactions tells whether the scope of a budget (alias b) filters by tag or merchant,
+// which the daily rollups are not keyed by
+const budgetNeedsTransactions = `
+	NOT COALESCE((b.scope->>'total_spend')::boolean, false)
+	AND (jsonb_array_length(COALESCE(b.scope->'tag_ids', '[]'::jsonb)) > 0
+		OR jsonb_array_length(COALESCE(b.scope->'merchants', '[]'::jsonb)) > 0)
+`
+
+// budgetRollupScopeFilter matches daily rollups (alias t) against a budget scope without tags or merchants
+const budgetRollupScopeFilter = `
+	t.workspace_id = b.workspace_id
+	AND t.transaction_type = 2
+	AND t.transaction_date BETWEEN b.period_start AND b.period_end
+	AND (
+		COALESCE((b.scope->>'total_spend')::boolean, false)
+		OR (
+			(jsonb_array_length(COALESCE(b.scope->'category_ids', '[]'::jsonb)) = 0
+				OR t.category_id = ANY(ARRAY(SELECT jsonb_array_elements_text(b.scope->'category_ids'))::uuid[]))
+			AND (jsonb_array_length(COALESCE(b.scope->'account_ids', '[]'::jsonb)) = 0
+				OR t.account_id = ANY(ARRAY(SELECT jsonb_array_elements_text(b.scope->'account_ids'))::uuid[]))
+		)
+	)
+`
+
+// budgetSpentSelect computes the spent amount and transaction count of a budget (alias b). Scopes by category
+// or account are read from the daily rollups, scopes by tag or merchant from the transactions.
+const budgetSpentSelect = `
+	SELECT COALESCE(SUM(x.amount), 0) as spent_amount, COALESCE(SUM(x.transaction_count), 0) as transaction_count
+	FROM (
+		SELECT t.amount, t.transaction_count
+		FROM "vasst_expense".transaction_daily_rollups t
+		WHERE NOT (` + budgetNeedsTransactions + `) AND ` + budgetRollupScopeFilter + `
+		UNION ALL
+		SELECT t.amount, 1
+		FROM "vasst_expense".transactions t
+		WHERE (` + budgetNeedsTransactions + `) AND ` + budgetScopeFilter + `
+		AND t.approval_status IN ` + countedApprovalStatuses + `
+	) x
+`
+
+// budgetPendingSelect computes the amount of expenses in the scope of a budget (alias b) still waiting for approval.
+// They are never in the daily rollups, so every scope reads them from the transactions.
+const budgetPendingSelect = `
+	SELECT COALESCE(SUM(t.amount), 0) as pending_amount
+	FROM "vasst_expense".transactions t
+	WHERE ` + budgetScopeFilter + ` AND t.approval_status = 2
+`
+
+// budgetSimpleSelect selects a BudgetSimple with the spent and pending amounts computed live from the scope
+const budgetSimpleSelect = `
+	SELECT 
+		b.budget_id,
+		COALESCE(uc.name, 'Uncategorized') as user_category_name,
+		b.name,
+		b.budgeted_amount,
+		CASE 
+			WHEN b.period_type = 1 THEN 'Weekly'
+			WHEN b.period_type = 2 THEN 'Monthly'
+			WHEN b.period_type = 3 THEN 'Yearly'
+			WHEN b.period_type = 4 THEN 'Event'
+			ELSE 'Unknown'
+		END as period_type_label,
+		b.period_start,
+		b.period_end,
+		b.scope,
+		s.spent_amount,
+		p.pending_amount,
+		s.transaction_count,
+		(b.budgeted_amount - s.spent_amount) as remaining_amount,
+		CASE 
+			WHEN b.budgeted_amount > 0 THEN (s.spent_amount / b.budgeted_amount * 100)
+			ELSE 0
+		END as percentage_used,
+		GREATEST(0, (b.period_end - CURRENT_DATE)) as days_remaining,
+		(s.spent_amount > b.budgeted_amount) as is_overspent,
+		CASE
+			WHEN s.spent_amount > b.budgeted_amount THEN 'exceeded'
+			WHEN s.spent_amount >= b.budgeted_amount * 0.8 THEN 'warning'
+			ELSE 'ok'
+		END as alert_level
+	FROM "vasst_expense".budgets b
+	LEFT JOIN "vasst_expense".user_categories uc ON b.user_category_id = uc.user_category_id
+	LEFT JOIN LATERAL (` + budgetSpentSelect + `) s ON true
+	LEFT JOIN LATERAL (` + budgetPendingSelect + `) p ON true
+`
+
 // NewBudgetRepository creates a new BudgetRepository
 func NewBudgetRepository(pg *postgres.Postgres) BudgetRepository {
 	return &budgetRepository{pg}
@@ -34,12 +152,12 @@ func (r *budgetRepository) Create(ctx context.Context, budget *entities.Budget)
 	query := `
 		INSERT INTO "vasst_expense".budgets (
 			budget_id, workspace_id, user_category_id, name, budgeted_amount, 
-			period_type, period_start, period_end, spent_amount, is_active, 
+			period_type, period_start, period_end, spent_amount, scope, is_active, 
 			created_by, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING budget_id, workspace_id, user_category_id, name, budgeted_amount, 
-		          period_type, period_start, period_end, spent_amount, is_active, 
+		          period_type, period_start, period_end, spent_amount, scope, is_active, 
 		          created_by, created_at, updated_at
 	`
 
@@ -48,13 +166,14 @@ func (r *budgetRepository) Create(ctx context.Context, budget *entities.Budget)
 	err := r.DB.QueryRowContext(ctx, query,
 		budget.BudgetID,
 		budget.WorkspaceID,
-		budget.UserCategoryID,
+		nullableUUID(budget.UserCategoryID),
 		budget.Name,
 		budget.BudgetedAmount,
 		budget.PeriodType,
 		budget.PeriodStart,
 		budget.PeriodEnd,
 		budget.SpentAmount,
+		budget.Scope,
 		budget.IsActive,
 		budget.CreatedBy,
 	).Scan(
@@ -67,6 +186,7 @@ func (r *budgetRepository) Create(ctx context.Context, budget *entities.Budget)
 		&createdBudget.PeriodStart,
 		&createdBudget.PeriodEnd,
 		&createdBudget.SpentAmount,
+		&createdBudget.Scope,
 		&createdBudget.IsActive,
 		&createdBudget.CreatedBy,
 		&createdBudget.CreatedAt,
@@ -87,24 +207,26 @@ func (r *budgetRepository) Update(ctx context.Context, budget *entities.Budget)
 			period_start = $6,
 			period_end = $7,
 			spent_amount = $8,
-			is_active = $9,
+			scope = $9,
+			is_active = $10,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE budget_id = $1
 		RETURNING budget_id, workspace_id, user_category_id, name, budgeted_amount, 
-		          period_type, period_start, period_end, spent_amount, is_active, 
+		          period_type, period_start, period_end, spent_amount, scope, is_active, 
 		          created_by, created_at, updated_at
 	`
 
 	var updatedBudget entities.Budget
 	err := r.DB.QueryRowContext(ctx, query,
 		budget.BudgetID,
-		budget.UserCategoryID,
+		nullableUUID(budget.UserCategoryID),
 		budget.Name,
 		budget.BudgetedAmount,
 		budget.PeriodType,
 		budget.PeriodStart,
 		budget.PeriodEnd,
 		budget.SpentAmount,
+		budget.Scope,
 		budget.IsActive,
 	).Scan(
 		&updatedBudget.BudgetID,
@@ -116,6 +238,7 @@ func (r *budgetRepository) Update(ctx context.Context, budget *entities.Budget)
 		&updatedBudget.PeriodStart,
 		&updatedBudget.PeriodEnd,
 		&updatedBudget.SpentAmount,
+		&updatedBudget.Scope,
 		&updatedBudget.IsActive,
 		&updatedBudget.CreatedBy,
 		&updatedBudget.CreatedAt,
@@ -160,7 +283,7 @@ func (r *budgetRepository) Delete(ctx context.Context, budgetID uuid.UUID) error
 func (r *budgetRepository) FindByID(ctx context.Context, budgetID uuid.UUID) (*entities.Budget, error) {
 	query := `
 		SELECT budget_id, workspace_id, user_category_id, name, budgeted_amount, 
-			   period_type, period_start, period_end, spent_amount, is_active, 
+			   period_type, period_start, period_end, spent_amount, scope, is_active, 
 			   created_by, created_at, updated_at
 		FROM "vasst_expense".budgets
 		WHERE budget_id = $1
@@ -177,6 +300,7 @@ func (r *budgetRepository) FindByID(ctx context.Context, budgetID uuid.UUID) (*e
 		&budget.PeriodStart,
 		&budget.PeriodEnd,
 		&budget.SpentAmount,
+		&budget.Scope,
 		&budget.IsActive,
 		&budget.CreatedBy,
 		&budget.CreatedAt,
@@ -195,31 +319,7 @@ func (r *budgetRepository) FindByID(ctx context.Context, budgetID uuid.UUID) (*e
 
 // FindByIDWithWorkspace finds a budget by ID within a specific workspace (returns BudgetSimple)
 func (r *budgetRepository) FindByIDWithWorkspace(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) (*entities.BudgetSimple, error) {
-	query := `
-		SELECT 
-			b.budget_id,
-			COALESCE(uc.name, 'Uncategorized') as user_category_name,
-			b.name,
-			b.budgeted_amount,
-			CASE 
-				WHEN b.period_type = 1 THEN 'Weekly'
-				WHEN b.period_type = 2 THEN 'Monthly'
-				WHEN b.period_type = 3 THEN 'Yearly'
-				WHEN b.period_type = 4 THEN 'Event'
-				ELSE 'Unknown'
-			END as period_type_label,
-			b.period_start,
-			b.period_end,
-			b.spent_amount,
-			(b.budgeted_amount - b.spent_amount) as remaining_amount,
-			CASE 
-				WHEN b.budgeted_amount > 0 THEN (b.spent_amount / b.budgeted_amount * 100)
-				ELSE 0
-			END as percentage_used,
-			GREATEST(0, (b.period_end - CURRENT_DATE)) as days_remaining,
-			(b.spent_amount > b.budgeted_amount) as is_overspent
-		FROM "vasst_expense".budgets b
-		LEFT JOIN "vasst_expense".user_categories uc ON b.user_category_id = uc.user_category_id
+	query := budgetSimpleSelect + `
 		WHERE b.budget_id = $1 AND b.workspace_id = $2 AND b.is_active = true
 	`
 
@@ -232,11 +332,15 @@ func (r *budgetRepository) FindByIDWithWorkspace(ctx context.Context, budgetID u
 		&budget.PeriodTypeLabel,
 		&budget.PeriodStart,
 		&budget.PeriodEnd,
+		&budget.Scope,
 		&budget.SpentAmount,
+		&budget.PendingAmount,
+		&budget.TransactionCount,
 		&budget.RemainingAmount,
 		&budget.PercentageUsed,
 		&budget.DaysRemaining,
 		&budget.IsOverspent,
+		&budget.AlertLevel,
 	)
 
 	if err != nil {
@@ -251,31 +355,7 @@ func (r *budgetRepository) FindByIDWithWorkspace(ctx context.Context, budgetID u
 
 // FindByWorkspace finds all budgets by workspace with pagination (returns BudgetSimple)
 func (r *budgetRepository) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error) {
-	query := `
-		SELECT 
-			b.budget_id,
-			COALESCE(uc.name, 'Uncategorized') as user_category_name,
-			b.name,
-			b.budgeted_amount,
-			CASE 
-				WHEN b.period_type = 1 THEN 'Weekly'
-				WHEN b.period_type = 2 THEN 'Monthly'
-				WHEN b.period_type = 3 THEN 'Yearly'
-				WHEN b.period_type = 4 THEN 'Event'
-				ELSE 'Unknown'
-			END as period_type_label,
-			b.period_start,
-			b.period_end,
-			b.spent_amount,
-			(b.budgeted_amount - b.spent_amount) as remaining_amount,
-			CASE 
-				WHEN b.budgeted_amount > 0 THEN (b.spent_amount / b.budgeted_amount * 100)
-				ELSE 0
-			END as percentage_used,
-			GREATEST(0, (b.period_end - CURRENT_DATE)) as days_remaining,
-			(b.spent_amount > b.budgeted_amount) as is_overspent
-		FROM "vasst_expense".budgets b
-		LEFT JOIN "vasst_expense".user_categories uc ON b.user_category_id = uc.user_category_id
+	query := budgetSimpleSelect + `
 		WHERE b.workspace_id = $1 AND b.is_active = true
 		ORDER BY b.created_at DESC
 		LIMIT $2 OFFSET $3
@@ -298,11 +378,15 @@ func (r *budgetRepository) FindByWorkspace(ctx context.Context, workspaceID uuid
 			&budget.PeriodTypeLabel,
 			&budget.PeriodStart,
 			&budget.PeriodEnd,
+			&budget.Scope,
 			&budget.SpentAmount,
+			&budget.PendingAmount,
+			&budget.TransactionCount,
 			&budget.RemainingAmount,
 			&budget.PercentageUsed,
 			&budget.DaysRemaining,
 			&budget.IsOverspent,
+			&budget.AlertLevel,
 		)
 		if err != nil {
 			return nil, err
@@ -317,3 +401,76 @@ func (r *budgetRepository) FindByWorkspace(ctx context.Context, workspaceID uuid
 
 	return budgets, nil
 }
+
+// RefreshSpentAmount recalculates and stores the spent amount of a budget from its scope
+func (r *budgetRepository) RefreshSpentAmount(ctx context.Context, budgetID uuid.UUID) (float64, error) {
+	query := `
+		UPDATE "vasst_expense".budgets b
+		SET spent_amount = (SELECT s.spent_amount FROM (` + budgetSpentSelect + `) s)
+		WHERE b.budget_id = $1
+		RETURNING b.spent_amount
+	`
+
+	var spentAmount float64
+	err := r.DB.QueryRowContext(ctx, query, budgetID).Scan(&spentAmount)
+	if err != nil {
+		return 0, err
+	}
+
+	return spentAmount, nil
+}
+
+// FindContributingTransactions finds the transactions counted toward a budget, newest first
+func (r *budgetRepository) FindContributingTransactions(ctx context.Context, budgetID uuid.UUID, limit, offset int) ([]*entities.BudgetTransaction, error) {
+	query := `
+		SELECT t.transaction_id, t.description, t.amount, t.transaction_date, t.merchant_name,
+		       uc.name as category_name, a.account_name
+		FROM "vasst_expense".budgets b
+		JOIN "vasst_expense".transactions t ON ` + budgetScopeFilter + `
+			AND t.approval_status IN ` + countedApprovalStatuses + `
+		LEFT JOIN "vasst_expense".user_categories uc ON t.category_id = uc.user_category_id
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+		WHERE b.budget_id = $1
+		ORDER BY t.transaction_date DESC, t.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, budgetID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entities.BudgetTransaction
+	for rows.Next() {
+		var transaction entities.BudgetTransaction
+		err := rows.Scan(
+			&transaction.TransactionID,
+			&transaction.Description,
+			&transaction.Amount,
+			&transaction.TransactionDate,
+			&transaction.MerchantName,
+			&transaction.CategoryName,
+			&transaction.AccountName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, &transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// nullableUUID converts uuid.Nil into NULL so optional foreign keys stay valid
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	dashboardRepository struct {
+		*postgres.Postgres
+	}
+
+	DashboardRepository interface {
+		FindWorkspaceSummaries(ctx context.Context, userID uuid.UUID, currencyID int, excludedWorkspaceIDs []uuid.UUID, startDate, endDate time.Time) ([]*entities.ConsolidatedWorkspaceSummary, error)
+		FindAccountBalances(ctx context.Context, userID uuid.UUID, currencyID int, excludedWorkspaceIDs []uuid.UUID) ([]*entities.NetWorthAccount, error)
+	}
+)
+
+// NewDashboardRepository creates a new DashboardRepository
+func NewDashboardRepository(pg *postgres.Postgres) DashboardRepository {
+	return &dashboardRepository{pg}
+}
+
+// excludedWorkspaceArray binds workspace IDs as a uuid array. A nil slice would bind as NULL and exclude everything.
+func excludedWorkspaceArray(workspaceIDs []uuid.UUID) interface{} {
+	if workspaceIDs == nil {
+		workspaceIDs = []uuid.UUID{}
+	}
+	return pq.Array(workspaceIDs)
+}
+
+// FindWorkspaceSummaries returns the income, expenses and budget status of every active workspace of a user over a period,
+// converted into currencyID. Transfers between own accounts are excluded, budgets count when they overlap the period.
+// Transactions and budgets without an exchange rate are left out of the amounts and counted.
+func (r *dashboardRepository) FindWorkspaceSummaries(ctx context.Context, userID uuid.UUID, currencyID int, excludedWorkspaceIDs []uuid.UUID, startDate, endDate time.Time) ([]*entities.ConsolidatedWorkspaceSummary, error) {
+	query := `
+		SELECT w.workspace_id, w.name, w.workspace_type, w.currency_id,
+		       COALESCE(tx.total_income, 0), COALESCE(tx.total_expense, 0), tx.transaction_count, tx.unconverted_count,
+		       bg.budget_count, bg.overspent_count, COALESCE(bg.budgeted_amount, 0), COALESCE(bg.spent_amount, 0),
+		       bg.unconverted_count
+		FROM "vasst_expense".workspaces w
+		LEFT JOIN LATERAL (
+			SELECT SUM(c.amount) FILTER (WHERE t.transaction_type = 1) as total_income,
+			       SUM(c.amount) FILTER (WHERE t.transaction_type = 2) as total_expense,
+			       COUNT(*) as transaction_count,
+			       COUNT(*) FILTER (WHERE c.amount IS NULL) as unconverted_count
+			FROM "vasst_expense".transactions t
+			LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+			CROSS JOIN LATERAL (
+				SELECT ` + convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "$2::int", "t.transaction_date") + ` as amount
+			) c
+			WHERE t.workspace_id = w.workspace_id AND t.transfer_account_id IS NULL
+			AND t.approval_status IN ` + countedApprovalStatuses + `
+			AND t.transaction_date BETWEEN $3 AND $4
+		) tx ON true
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) as budget_count,
+			       COUNT(*) FILTER (WHERE s.spent_amount > b.budgeted_amount) as overspent_count,
+			       SUM(c.budgeted_amount) as budgeted_amount,
+			       SUM(c.spent_amount) as spent_amount,
+			       COUNT(*) FILTER (WHERE c.budgeted_amount IS NULL) as unconverted_count
+			FROM "vasst_expense".budgets b
+			LEFT JOIN LATERAL (` + budgetSpentSelect + `) s ON true
+			CROSS JOIN LATERAL (
+				SELECT ` + convertAmountSQL("b.budgeted_amount", "w.currency_id", "$2::int", "$4::date") + ` as budgeted_amount,
+				       ` + convertAmountSQL("s.spent_amount", "w.currency_id", "$2::int", "$4::date") + ` as spent_amount
+			) c
+			WHERE b.workspace_id = w.workspace_id AND b.is_active = true
+			AND b.period_start <= $4 AND b.period_end >= $3
+		) bg ON true
+		WHERE w.created_by = $1 AND w.is_active = true AND NOT (w.workspace_id = ANY($5))
+		ORDER BY w.created_at ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, currencyID, startDate, endDate, excludedWorkspaceArray(excludedWorkspaceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*entities.ConsolidatedWorkspaceSummary
+	for rows.Next() {
+		var summary entities.ConsolidatedWorkspaceSummary
+		err := rows.Scan(
+			&summary.WorkspaceID,
+			&summary.WorkspaceName,
+			&summary.WorkspaceType,
+			&summary.CurrencyID,
+			&summary.TotalIncome,
+			&summary.TotalExpense,
+			&summary.TransactionCount,
+			&summary.UnconvertedCount,
+			&summary.Budget.BudgetCount,
+			&summary.Budget.OverspentCount,
+			&summary.Budget.BudgetedAmount,
+			&summary.Budget.SpentAmount,
+			&summary.Budget.UnconvertedCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, &summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// FindAccountBalances returns the current balance of every active account of a user, converted into currencyID,
+// or NULL when no exchange rate is recorded for the account currency.
+// Accounts only used by excluded workspaces are left out, accounts shared with an included workspace are kept.
+func (r *dashboardRepository) FindAccountBalances(ctx context.Context, userID uuid.UUID, currencyID int, excludedWorkspaceIDs []uuid.UUID) ([]*entities.NetWorthAccount, error) {
+	query := `
+		WITH account_workspaces AS (
+			SELECT account_id, workspace_id FROM "vasst_expense".transactions WHERE account_id IS NOT NULL
+			UNION
+			SELECT transfer_account_id, workspace_id FROM "vasst_expense".transactions WHERE transfer_account_id IS NOT NULL
+		)
+		SELECT a.account_id, a.account_name, a.account_type, a.current_balance, a.currency_id,
+		       ` + convertAmountSQL("a.current_balance", "a.currency_id", "$2::int", "CURRENT_DATE") + ` as converted_balance
+		FROM "vasst_expense".accounts a
+		WHERE a.user_id = $1 AND a.is_active = true
+		AND NOT (
+			EXISTS (SELECT 1 FROM account_workspaces aw WHERE aw.account_id = a.account_id AND aw.workspace_id = ANY($3))
+			AND NOT EXISTS (SELECT 1 FROM account_workspaces aw WHERE aw.account_id = a.account_id AND NOT (aw.workspace_id = ANY($3)))
+		)
+		ORDER BY converted_balance DESC NULLS LAST, a.account_name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, currencyID, excludedWorkspaceArray(excludedWorkspaceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entities.NetWorthAccount
+	for rows.Next() {
+		var account entities.NetWorthAccount
+		err := rows.Scan(
+			&account.AccountID,
+			&account.AccountName,
+			&account.AccountType,
+			&account.Balance,
+			&account.CurrencyID,
+			&account.ConvertedBalance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, &account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
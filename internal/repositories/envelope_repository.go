@@ -0,0 +1,257 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	envelopeRepository struct {
+		*postgres.Postgres
+	}
+
+	EnvelopeRepository interface {
+		UpsertAllocation(ctx context.Context, allocation *entities.EnvelopeAllocation) (entities.EnvelopeAllocation, error)
+		CreateTransfer(ctx context.Context, transfer *entities.EnvelopeTransfer) (entities.EnvelopeTransfer, error)
+		FindTransfersByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.EnvelopeTransferSimple, error)
+		FindMonthlyActivity(ctx context.Context, workspaceID uuid.UUID, untilMonth time.Time) ([]*entities.EnvelopeMonthlyActivity, error)
+		FindMonthlyIncome(ctx context.Context, workspaceID uuid.UUID, untilMonth time.Time) ([]*entities.EnvelopeMonthlyIncome, error)
+	}
+)
+
+// NewEnvelopeRepository creates a new EnvelopeRepository
+func NewEnvelopeRepository(pg *postgres.Postgres) EnvelopeRepository {
+	return &envelopeRepository{pg}
+}
+
+// UpsertAllocation sets the amount assigned to an envelope for a month
+func (r *envelopeRepository) UpsertAllocation(ctx context.Context, allocation *entities.EnvelopeAllocation) (entities.EnvelopeAllocation, error) {
+	query := `
+		INSERT INTO "vasst_expense".envelope_allocations (
+			allocation_id, workspace_id, user_category_id, allocation_month, amount,
+			created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (workspace_id, user_category_id, allocation_month)
+		DO UPDATE SET amount = EXCLUDED.amount, updated_at = CURRENT_TIMESTAMP
+		RETURNING allocation_id, workspace_id, user_category_id, allocation_month, amount,
+		          created_by, created_at, updated_at
+	`
+
+	var upsertedAllocation entities.EnvelopeAllocation
+	err := r.DB.QueryRowContext(ctx, query,
+		allocation.AllocationID,
+		allocation.WorkspaceID,
+		allocation.UserCategoryID,
+		allocation.AllocationMonth,
+		allocation.Amount,
+		allocation.CreatedBy,
+	).Scan(
+		&upsertedAllocation.AllocationID,
+		&upsertedAllocation.WorkspaceID,
+		&upsertedAllocation.UserCategoryID,
+		&upsertedAllocation.AllocationMonth,
+		&upsertedAllocation.Amount,
+		&upsertedAllocation.CreatedBy,
+		&upsertedAllocation.CreatedAt,
+		&upsertedAllocation.UpdatedAt,
+	)
+
+	return upsertedAllocation, err
+}
+
+// CreateTransfer records money moved between envelopes
+func (r *envelopeRepository) CreateTransfer(ctx context.Context, transfer *entities.EnvelopeTransfer) (entities.EnvelopeTransfer, error) {
+	query := `
+		INSERT INTO "vasst_expense".envelope_transfers (
+			transfer_id, workspace_id, from_user_category_id, to_user_category_id, transfer_month,
+			amount, transfer_type, note, created_by, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP)
+		RETURNING transfer_id, workspace_id, from_user_category_id, to_user_category_id, transfer_month,
+		          amount, transfer_type, note, created_by, created_at
+	`
+
+	var createdTransfer entities.EnvelopeTransfer
+	err := r.DB.QueryRowContext(ctx, query,
+		transfer.TransferID,
+		transfer.WorkspaceID,
+		transfer.FromUserCategoryID,
+		transfer.ToUserCategoryID,
+		transfer.TransferMonth,
+		transfer.Amount,
+		transfer.TransferType,
+		transfer.Note,
+		transfer.CreatedBy,
+	).Scan(
+		&createdTransfer.TransferID,
+		&createdTransfer.WorkspaceID,
+		&createdTransfer.FromUserCategoryID,
+		&createdTransfer.ToUserCategoryID,
+		&createdTransfer.TransferMonth,
+		&createdTransfer.Amount,
+		&createdTransfer.TransferType,
+		&createdTransfer.Note,
+		&createdTransfer.CreatedBy,
+		&createdTransfer.CreatedAt,
+	)
+
+	return createdTransfer, err
+}
+
+// FindTransfersByWorkspace returns the envelope transfer history of a workspace, newest first
+func (r *envelopeRepository) FindTransfersByWorkspace(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.EnvelopeTransferSimple, error) {
+	query := `
+		SELECT et.transfer_id,
+		       COALESCE(fuc.name, 'Uncategorized') as from_envelope_name,
+		       COALESCE(tuc.name, 'Uncategorized') as to_envelope_name,
+		       et.transfer_month, et.amount, et.transfer_type, et.note, et.created_by, et.created_at
+		FROM "vasst_expense".envelope_transfers et
+		LEFT JOIN "vasst_expense".user_categories fuc ON et.from_user_category_id = fuc.user_category_id
+		LEFT JOIN "vasst_expense".user_categories tuc ON et.to_user_category_id = tuc.user_category_id
+		WHERE et.workspace_id = $1
+		ORDER BY et.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []*entities.EnvelopeTransferSimple
+	for rows.Next() {
+		var transfer entities.EnvelopeTransferSimple
+		err := rows.Scan(
+			&transfer.TransferID,
+			&transfer.FromEnvelopeName,
+			&transfer.ToEnvelopeName,
+			&transfer.TransferMonth,
+			&transfer.Amount,
+			&transfer.TransferType,
+			&transfer.Note,
+			&transfer.CreatedBy,
+			&transfer.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, &transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+// FindMonthlyActivity returns assigned, transferred and spent amounts per envelope per month,
+// from the first activity of the workspace up to and including untilMonth, ordered by month
+func (r *envelopeRepository) FindMonthlyActivity(ctx context.Context, workspaceID uuid.UUID, untilMonth time.Time) ([]*entities.EnvelopeMonthlyActivity, error) {
+	query := `
+		WITH activity AS (
+			SELECT user_category_id, allocation_month as month,
+			       amount as assigned, 0 as transferred_in, 0 as transferred_out, 0 as spent
+			FROM "vasst_expense".envelope_allocations
+			WHERE workspace_id = $1 AND allocation_month <= $2
+			UNION ALL
+			SELECT to_user_category_id, transfer_month, 0, amount, 0, 0
+			FROM "vasst_expense".envelope_transfers
+			WHERE workspace_id = $1 AND transfer_month <= $2
+			UNION ALL
+			SELECT from_user_category_id, transfer_month, 0, 0, amount, 0
+			FROM "vasst_expense".envelope_transfers
+			WHERE workspace_id = $1 AND transfer_month <= $2
+			UNION ALL
+			SELECT category_id, date_trunc('month', transaction_date)::date, 0, 0, 0, amount
+			FROM "vasst_expense".transactions
+			WHERE workspace_id = $1 AND transaction_type = 2 AND category_id IS NOT NULL AND transfer_account_id IS NULL
+			AND approval_status IN ` + countedApprovalStatuses + `
+			AND transaction_date < ($2::date + INTERVAL '1 month')
+		)
+		SELECT a.user_category_id,
+		       COALESCE(uc.name, 'Uncategorized') as envelope_name,
+		       a.month,
+		       SUM(a.assigned) as assigned,
+		       SUM(a.transferred_in) as transferred_in,
+		       SUM(a.transferred_out) as transferred_out,
+		       SUM(a.spent) as spent
+		FROM activity a
+		LEFT JOIN "vasst_expense".user_categories uc ON a.user_category_id = uc.user_category_id
+		GROUP BY a.user_category_id, uc.name, a.month
+		ORDER BY a.month ASC, envelope_name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, untilMonth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []*entities.EnvelopeMonthlyActivity
+	for rows.Next() {
+		var activity entities.EnvelopeMonthlyActivity
+		err := rows.Scan(
+			&activity.UserCategoryID,
+			&activity.EnvelopeName,
+			&activity.Month,
+			&activity.Assigned,
+			&activity.TransferredIn,
+			&activity.TransferredOut,
+			&activity.Spent,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		activities = append(activities, &activity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// FindMonthlyIncome returns the income of a workspace per month up to and including untilMonth
+func (r *envelopeRepository) FindMonthlyIncome(ctx context.Context, workspaceID uuid.UUID, untilMonth time.Time) ([]*entities.EnvelopeMonthlyIncome, error) {
+	query := `
+		SELECT date_trunc('month', transaction_date)::date as month, SUM(amount) as amount
+		FROM "vasst_expense".transactions
+		WHERE workspace_id = $1 AND transaction_type = 1 AND transfer_account_id IS NULL
+		AND approval_status IN ` + countedApprovalStatuses + `
+		AND transaction_date < ($2::date + INTERVAL '1 month')
+		GROUP BY month
+		ORDER BY month ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, untilMonth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incomes []*entities.EnvelopeMonthlyIncome
+	for rows.Next() {
+		var income entities.EnvelopeMonthlyIncome
+		if err := rows.Scan(&income.Month, &income.Amount); err != nil {
+			return nil, err
+		}
+
+		incomes = append(incomes, &income)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return incomes, nil
+}
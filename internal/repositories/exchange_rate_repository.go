@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	exchangeRateRepository struct {
+		*postgres.Postgres
+	}
+
+	ExchangeRateRepository interface {
+		Upsert(ctx context.Context, rate *entities.ExchangeRate) (entities.ExchangeRate, error)
+		Delete(ctx context.Context, exchangeRateID uuid.UUID) error
+		FindAll(ctx context.Context, limit, offset int) ([]*entities.ExchangeRate, error)
+		FindLatest(ctx context.Context, fromCurrencyID, toCurrencyID int, date time.Time) (*entities.ExchangeRate, error)
+	}
+)
+
+// ErrNoExchangeRate is returned when an amount has to be converted but no exchange rate is recorded for its currency
+var ErrNoExchangeRate = errors.New("no exchange rate recorded for the currency of an expense")
+
+// NewExchangeRateRepository creates a new ExchangeRateRepository
+func NewExchangeRateRepository(pg *postgres.Postgres) ExchangeRateRepository {
+	return &exchangeRateRepository{pg}
+}
+
+// convertAmountSQL returns an SQL expression converting amountExpr from fromCurrencyExpr into toCurrencyExpr
+// with the latest exchange rate effective on or before dateExpr. When only the opposite pair is recorded its
+// inverse is used. When no rate exists at all the expression is NULL, so SUM leaves
+// the amount out. Callers count those amounts and return them as unconverted, the total is incomplete then.
+func convertAmountSQL(amountExpr, fromCurrencyExpr, toCurrencyExpr, dateExpr string) string {
+	return fmt.Sprintf(`CASE WHEN %[2]s IS NULL OR %[2]s = %[3]s THEN %[1]s ELSE %[1]s * COALESCE(
+			(SELECT er.rate FROM "vasst_expense".exchange_rates er
+			 WHERE er.from_currency_id = %[2]s AND er.to_currency_id = %[3]s AND er.effective_date <= %[4]s
+			 ORDER BY er.effective_date DESC LIMIT 1),
+			(SELECT 1 / NULLIF(er.rate, 0) FROM "vasst_expense".exchange_rates er
+			 WHERE er.from_currency_id = %[3]s AND er.to_currency_id = %[2]s AND er.effective_date <= %[4]s
+			 ORDER BY er.effective_date DESC LIMIT 1)
+		) END`, amountExpr, fromCurrencyExpr, toCurrencyExpr, dateExpr)
+}
+
+// Upsert sets the rate of a currency pair for an effective date
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rate *entities.ExchangeRate) (entities.ExchangeRate, error) {
+	query := `
+		INSERT INTO "vasst_expense".exchange_rates (
+			exchange_rate_id, from_currency_id, to_currency_id, rate, effective_date, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (from_currency_id, to_currency_id, effective_date)
+		DO UPDATE SET rate = EXCLUDED.rate, updated_at = CURRENT_TIMESTAMP
+		RETURNING exchange_rate_id, from_currency_id, to_currency_id, rate, effective_date, created_at, updated_at
+	`
+
+	var upsertedRate entities.ExchangeRate
+	err := r.DB.QueryRowContext(ctx, query,
+		rate.ExchangeRateID,
+		rate.FromCurrencyID,
+		rate.ToCurrencyID,
+		rate.Rate,
+		rate.EffectiveDate,
+	).Scan(
+		&upsertedRate.ExchangeRateID,
+		&upsertedRate.FromCurrencyID,
+		&upsertedRate.ToCurrencyID,
+		&upsertedRate.Rate,
+		&upsertedRate.EffectiveDate,
+		&upsertedRate.CreatedAt,
+		&upsertedRate.UpdatedAt,
+	)
+
+	return upsertedRate, err
+}
+
+// Delete deletes an exchange rate
+func (r *exchangeRateRepository) Delete(ctx context.Context, exchangeRateID uuid.UUID) error {
+	query := `
+		DELETE FROM "vasst_expense".exchange_rates
+		WHERE exchange_rate_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, exchangeRateID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindAll returns exchange rates, newest effective date first
+func (r *exchangeRateRepository) FindAll(ctx context.Context, limit, offset int) ([]*entities.ExchangeRate, error) {
+	query := `
+		SELECT exchange_rate_id, from_currency_id, to_currency_id, rate, effective_date, created_at, updated_at
+		FROM "vasst_expense".exchange_rates
+		ORDER BY effective_date DESC, from_currency_id, to_currency_id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []*entities.ExchangeRate
+	for rows.Next() {
+		var rate entities.ExchangeRate
+		err := rows.Scan(
+			&rate.ExchangeRateID,
+			&rate.FromCurrencyID,
+			&rate.ToCurrencyID,
+			&rate.Rate,
+			&rate.EffectiveDate,
+			&rate.CreatedAt,
+			&rate.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rates = append(rates, &rate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// FindLatest finds the latest rate of a currency pair effective on or before a date
+func (r *exchangeRateRepository) FindLatest(ctx context.Context, fromCurrencyID, toCurrencyID int, date time.Time) (*entities.ExchangeRate, error) {
+	query := `
+		SELECT exchange_rate_id, from_currency_id, to_currency_id, rate, effective_date, created_at, updated_at
+		FROM "vasst_expense".exchange_rates
+		WHERE from_currency_id = $1 AND to_currency_id = $2 AND effective_date <= $3
+		ORDER BY effective_date DESC
+		LIMIT 1
+	`
+
+	var rate entities.ExchangeRate
+	err := r.DB.QueryRowContext(ctx, query, fromCurrencyID, toCurrencyID, date).Scan(
+		&rate.ExchangeRateID,
+		&rate.FromCurrencyID,
+		&rate.ToCurrencyID,
+		&rate.Rate,
+		&rate.EffectiveDate,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &rate, nil
+}
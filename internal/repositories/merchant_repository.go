@@ -0,0 +1,386 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	merchantRepository struct {
+		*postgres.Postgres
+	}
+
+	MerchantRepository interface {
+		Create(ctx context.Context, merchant *entities.Merchant) (entities.Merchant, error)
+		Update(ctx context.Context, merchant *entities.Merchant) (entities.Merchant, error)
+		Delete(ctx context.Context, merchantID uuid.UUID) error
+		FindByID(ctx context.Context, merchantID uuid.UUID) (*entities.Merchant, error)
+		FindByName(ctx context.Context, name string) (*entities.Merchant, error)
+		FindAll(ctx context.Context, search string, activeOnly bool, limit, offset int) ([]*entities.Merchant, error)
+		CreateAlias(ctx context.Context, alias *entities.MerchantAlias) (entities.MerchantAlias, error)
+		DeleteAlias(ctx context.Context, merchantAliasID uuid.UUID) error
+		FindAliasByID(ctx context.Context, merchantAliasID uuid.UUID) (*entities.MerchantAlias, error)
+		FindGlobalAliasesByMerchant(ctx context.Context, merchantID uuid.UUID) ([]*entities.MerchantAlias, error)
+		FindAliasesByUser(ctx context.Context, userID uuid.UUID) ([]*entities.MerchantAlias, error)
+		FindAliasCandidates(ctx context.Context, userID uuid.UUID, text string) ([]*entities.MerchantAlias, error)
+		FindUserCategoryID(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) (*uuid.UUID, error)
+	}
+)
+
+// NewMerchantRepository creates a new MerchantRepository
+func NewMerchantRepository(pg *postgres.Postgres) MerchantRepository {
+	return &merchantRepository{pg}
+}
+
+// Create creates a new merchant
+func (r *merchantRepository) Create(ctx context.Context, merchant *entities.Merchant) (entities.Merchant, error) {
+	query := `
+		INSERT INTO "vasst_expense".merchants (
+			merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+	`
+
+	var createdMerchant entities.Merchant
+	err := r.DB.QueryRowContext(ctx, query,
+		merchant.MerchantID,
+		merchant.Name,
+		merchant.DefaultCategoryID,
+		merchant.LogoURL,
+		merchant.IsActive,
+	).Scan(
+		&createdMerchant.MerchantID,
+		&createdMerchant.Name,
+		&createdMerchant.DefaultCategoryID,
+		&createdMerchant.LogoURL,
+		&createdMerchant.IsActive,
+		&createdMerchant.CreatedAt,
+		&createdMerchant.UpdatedAt,
+	)
+
+	return createdMerchant, err
+}
+
+// Update updates a merchant
+func (r *merchantRepository) Update(ctx context.Context, merchant *entities.Merchant) (entities.Merchant, error) {
+	query := `
+		UPDATE "vasst_expense".merchants
+		SET name = $2, default_category_id = $3, logo_url = $4, is_active = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE merchant_id = $1
+		RETURNING merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+	`
+
+	var updatedMerchant entities.Merchant
+	err := r.DB.QueryRowContext(ctx, query,
+		merchant.MerchantID,
+		merchant.Name,
+		merchant.DefaultCategoryID,
+		merchant.LogoURL,
+		merchant.IsActive,
+	).Scan(
+		&updatedMerchant.MerchantID,
+		&updatedMerchant.Name,
+		&updatedMerchant.DefaultCategoryID,
+		&updatedMerchant.LogoURL,
+		&updatedMerchant.IsActive,
+		&updatedMerchant.CreatedAt,
+		&updatedMerchant.UpdatedAt,
+	)
+
+	return updatedMerchant, err
+}
+
+// Delete deletes a merchant together with its aliases. Transactions keep their merchant name.
+func (r *merchantRepository) Delete(ctx context.Context, merchantID uuid.UUID) error {
+	query := `DELETE FROM "vasst_expense".merchants WHERE merchant_id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query, merchantID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindByID returns a merchant by ID
+func (r *merchantRepository) FindByID(ctx context.Context, merchantID uuid.UUID) (*entities.Merchant, error) {
+	query := `
+		SELECT merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+		FROM "vasst_expense".merchants
+		WHERE merchant_id = $1
+	`
+
+	var merchant entities.Merchant
+	err := r.DB.QueryRowContext(ctx, query, merchantID).Scan(
+		&merchant.MerchantID,
+		&merchant.Name,
+		&merchant.DefaultCategoryID,
+		&merchant.LogoURL,
+		&merchant.IsActive,
+		&merchant.CreatedAt,
+		&merchant.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &merchant, nil
+}
+
+// FindByName returns a merchant by name, ignoring case
+func (r *merchantRepository) FindByName(ctx context.Context, name string) (*entities.Merchant, error) {
+	query := `
+		SELECT merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+		FROM "vasst_expense".merchants
+		WHERE LOWER(name) = LOWER($1)
+	`
+
+	var merchant entities.Merchant
+	err := r.DB.QueryRowContext(ctx, query, name).Scan(
+		&merchant.MerchantID,
+		&merchant.Name,
+		&merchant.DefaultCategoryID,
+		&merchant.LogoURL,
+		&merchant.IsActive,
+		&merchant.CreatedAt,
+		&merchant.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &merchant, nil
+}
+
+// FindAll returns merchants ordered by name, optionally filtered by name and limited to active merchants
+func (r *merchantRepository) FindAll(ctx context.Context, search string, activeOnly bool, limit, offset int) ([]*entities.Merchant, error) {
+	query := `
+		SELECT merchant_id, name, default_category_id, logo_url, is_active, created_at, updated_at
+		FROM "vasst_expense".merchants
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%') AND (NOT $2 OR is_active = true)
+		ORDER BY name ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, search, activeOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merchants []*entities.Merchant
+	for rows.Next() {
+		var merchant entities.Merchant
+		err := rows.Scan(
+			&merchant.MerchantID,
+			&merchant.Name,
+			&merchant.DefaultCategoryID,
+			&merchant.LogoURL,
+			&merchant.IsActive,
+			&merchant.CreatedAt,
+			&merchant.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		merchants = append(merchants, &merchant)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return merchants, nil
+}
+
+// CreateAlias creates a new merchant alias
+func (r *merchantRepository) CreateAlias(ctx context.Context, alias *entities.MerchantAlias) (entities.MerchantAlias, error) {
+	query := `
+		INSERT INTO "vasst_expense".merchant_aliases (
+			merchant_alias_id, merchant_id, user_id, pattern, match_type, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING merchant_alias_id, merchant_id, user_id, pattern, match_type, created_at, updated_at
+	`
+
+	var createdAlias entities.MerchantAlias
+	err := r.DB.QueryRowContext(ctx, query,
+		alias.MerchantAliasID,
+		alias.MerchantID,
+		alias.UserID,
+		alias.Pattern,
+		alias.MatchType,
+	).Scan(
+		&createdAlias.MerchantAliasID,
+		&createdAlias.MerchantID,
+		&createdAlias.UserID,
+		&createdAlias.Pattern,
+		&createdAlias.MatchType,
+		&createdAlias.CreatedAt,
+		&createdAlias.UpdatedAt,
+	)
+
+	return createdAlias, err
+}
+
+// DeleteAlias deletes a merchant alias
+func (r *merchantRepository) DeleteAlias(ctx context.Context, merchantAliasID uuid.UUID) error {
+	query := `DELETE FROM "vasst_expense".merchant_aliases WHERE merchant_alias_id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query, merchantAliasID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindAliasByID returns a merchant alias by ID
+func (r *merchantRepository) FindAliasByID(ctx context.Context, merchantAliasID uuid.UUID) (*entities.MerchantAlias, error) {
+	query := `
+		SELECT merchant_alias_id, merchant_id, user_id, pattern, match_type, created_at, updated_at
+		FROM "vasst_expense".merchant_aliases
+		WHERE merchant_alias_id = $1
+	`
+
+	var alias entities.MerchantAlias
+	err := r.DB.QueryRowContext(ctx, query, merchantAliasID).Scan(
+		&alias.MerchantAliasID,
+		&alias.MerchantID,
+		&alias.UserID,
+		&alias.Pattern,
+		&alias.MatchType,
+		&alias.CreatedAt,
+		&alias.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &alias, nil
+}
+
+// FindGlobalAliasesByMerchant returns the global aliases of a merchant
+func (r *merchantRepository) FindGlobalAliasesByMerchant(ctx context.Context, merchantID uuid.UUID) ([]*entities.MerchantAlias, error) {
+	query := `
+		SELECT merchant_alias_id, merchant_id, user_id, pattern, match_type, created_at, updated_at
+		FROM "vasst_expense".merchant_aliases
+		WHERE merchant_id = $1 AND user_id IS NULL
+		ORDER BY pattern ASC
+	`
+
+	return r.findAliases(ctx, query, merchantID)
+}
+
+// FindAliasesByUser returns the personal aliases of a user
+func (r *merchantRepository) FindAliasesByUser(ctx context.Context, userID uuid.UUID) ([]*entities.MerchantAlias, error) {
+	query := `
+		SELECT merchant_alias_id, merchant_id, user_id, pattern, match_type, created_at, updated_at
+		FROM "vasst_expense".merchant_aliases
+		WHERE user_id = $1
+		ORDER BY pattern ASC
+	`
+
+	return r.findAliases(ctx, query, userID)
+}
+
+// FindAliasCandidates returns the global aliases and the personal aliases of a user whose pattern occurs in
+// normalized merchant text. Only aliases of active merchants are returned; the caller picks the best match.
+func (r *merchantRepository) FindAliasCandidates(ctx context.Context, userID uuid.UUID, text string) ([]*entities.MerchantAlias, error) {
+	query := `
+		SELECT ma.merchant_alias_id, ma.merchant_id, ma.user_id, ma.pattern, ma.match_type, ma.created_at, ma.updated_at
+		FROM "vasst_expense".merchant_aliases ma
+		INNER JOIN "vasst_expense".merchants m ON ma.merchant_id = m.merchant_id
+		WHERE (ma.user_id IS NULL OR ma.user_id = $1) AND m.is_active = true
+		AND strpos($2, ma.pattern) > 0
+	`
+
+	return r.findAliases(ctx, query, userID, text)
+}
+
+func (r *merchantRepository) findAliases(ctx context.Context, query string, args ...interface{}) ([]*entities.MerchantAlias, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []*entities.MerchantAlias
+	for rows.Next() {
+		var alias entities.MerchantAlias
+		err := rows.Scan(
+			&alias.MerchantAliasID,
+			&alias.MerchantID,
+			&alias.UserID,
+			&alias.Pattern,
+			&alias.MatchType,
+			&alias.CreatedAt,
+			&alias.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		aliases = append(aliases, &alias)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// FindUserCategoryID returns the active user category of a user that is based on a system category,
+// preferring the predefined one over custom ones
+func (r *merchantRepository) FindUserCategoryID(ctx context.Context, userID uuid.UUID, categoryID uuid.UUID) (*uuid.UUID, error) {
+	query := `
+		SELECT user_category_id
+		FROM "vasst_expense".user_categories
+		WHERE user_id = $1 AND category_id = $2 AND is_active = true
+		ORDER BY is_custom ASC, created_at ASC
+		LIMIT 1
+	`
+
+	var userCategoryID uuid.UUID
+	err := r.DB.QueryRowContext(ctx, query, userID, categoryID).Scan(&userCategoryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &userCategoryID, nil
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	notificationRepository struct {
+		*postgres.Postgres
+	}
+
+	NotificationRepository interface {
+		Create(ctx context.Context, notification *entities.Notification) (entities.Notification, error)
+		FindByID(ctx context.Context, notificationID uuid.UUID) (*entities.Notification, error)
+		FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*entities.Notification, error)
+		MarkAsRead(ctx context.Context, notificationID uuid.UUID) error
+		MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	}
+)
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(pg *postgres.Postgres) NotificationRepository {
+	return &notificationRepository{pg}
+}
+
+// scanNotification scans a notification row and decodes its data
+func scanNotification(scan func(dest ...interface{}) error) (*entities.Notification, error) {
+	var notification entities.Notification
+	var data []byte
+	err := scan(
+		&notification.NotificationID,
+		&notification.UserID,
+		&notification.NotificationType,
+		&notification.Title,
+		&notification.Body,
+		&data,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &notification.Data); err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(ctx context.Context, notification *entities.Notification) (entities.Notification, error) {
+	data := notification.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return entities.Notification{}, err
+	}
+
+	query := `
+		INSERT INTO "vasst_expense".notifications (
+			notification_id, user_id, notification_type, title, body, data, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		RETURNING notification_id, user_id, notification_type, title, body, data, read_at, created_at
+	`
+
+	row := r.DB.QueryRowContext(ctx, query,
+		notification.NotificationID,
+		notification.UserID,
+		notification.NotificationType,
+		notification.Title,
+		notification.Body,
+		encoded,
+	)
+
+	createdNotification, err := scanNotification(row.Scan)
+	if err != nil {
+		return entities.Notification{}, err
+	}
+
+	return *createdNotification, nil
+}
+
+// FindByID returns a notification by ID
+func (r *notificationRepository) FindByID(ctx context.Context, notificationID uuid.UUID) (*entities.Notification, error) {
+	query := `
+		SELECT notification_id, user_id, notification_type, title, body, data, read_at, created_at
+		FROM "vasst_expense".notifications
+		WHERE notification_id = $1
+	`
+
+	notification, err := scanNotification(r.DB.QueryRowContext(ctx, query, notificationID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// FindByUser returns the notifications of a user, newest first
+func (r *notificationRepository) FindByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*entities.Notification, error) {
+	query := `
+		SELECT notification_id, user_id, notification_type, title, body, data, read_at, created_at
+		FROM "vasst_expense".notifications
+		WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*entities.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// MarkAsRead marks a notification as read, keeping the first read time
+func (r *notificationRepository) MarkAsRead(ctx context.Context, notificationID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".notifications
+		SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE notification_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, notificationID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MarkAllAsRead marks every unread notification of a user as read
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE "vasst_expense".notifications
+		SET read_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
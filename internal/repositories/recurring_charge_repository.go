@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	recurringChargeRepository struct {
+		*postgres.Postgres
+	}
+
+	RecurringChargeRepository interface {
+		FindExpenseHistory(ctx context.Context, workspaceID uuid.UUID, startDate time.Time) ([]*entities.RecurringChargeTransaction, error)
+		MarkRecurring(ctx context.Context, transactionID uuid.UUID, recurrenceInterval int) error
+	}
+)
+
+// NewRecurringChargeRepository creates a new RecurringChargeRepository
+func NewRecurringChargeRepository(pg *postgres.Postgres) RecurringChargeRepository {
+	return &recurringChargeRepository{pg}
+}
+
+// FindExpenseHistory returns the expenses of a workspace since startDate, oldest first, converted into the workspace currency.
+// Transfers between own accounts are left out, and so are expenses without an exchange rate as their amount cannot be compared.
+func (r *recurringChargeRepository) FindExpenseHistory(ctx context.Context, workspaceID uuid.UUID, startDate time.Time) ([]*entities.RecurringChargeTransaction, error) {
+	query := `
+		SELECT t.transaction_id, t.merchant_id, t.merchant_name, t.description, t.category_id, t.account_id,
+		       c.amount, t.transaction_date, t.is_recurring
+		FROM "vasst_expense".transactions t
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+		CROSS JOIN LATERAL (
+			SELECT ` + convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "w.currency_id", "t.transaction_date") + ` as amount
+		) c
+		WHERE t.workspace_id = $1 AND t.transaction_type = $2 AND t.transfer_account_id IS NULL
+		AND c.amount IS NOT NULL
+		AND t.transaction_date >= $3
+		ORDER BY t.transaction_date ASC, t.created_at ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, entities.TransactionTypeExpense, startDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*entities.RecurringChargeTransaction
+	for rows.Next() {
+		var transaction entities.RecurringChargeTransaction
+		err := rows.Scan(
+			&transaction.TransactionID,
+			&transaction.MerchantID,
+			&transaction.MerchantName,
+			&transaction.Description,
+			&transaction.CategoryID,
+			&transaction.AccountID,
+			&transaction.Amount,
+			&transaction.TransactionDate,
+			&transaction.IsRecurring,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, &transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// MarkRecurring flags a transaction as recurring with the given interval
+func (r *recurringChargeRepository) MarkRecurring(ctx context.Context, transactionID uuid.UUID, recurrenceInterval int) error {
+	query := `
+		UPDATE "vasst_expense".transactions
+		SET is_recurring = true, recurrence_interval = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, transactionID, recurrenceInterval)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,578 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	reimbursementRepository struct {
+		*postgres.Postgres
+	}
+
+	// ReimbursementRepository defines methods for interacting with reimbursement claims in the database
+	ReimbursementRepository interface {
+		Create(ctx context.Context, claim *entities.ReimbursementClaim, items []*entities.ReimbursementClaimItem) (entities.ReimbursementClaim, error)
+		Update(ctx context.Context, claim *entities.ReimbursementClaim, items []*entities.ReimbursementClaimItem) (entities.ReimbursementClaim, error)
+		Delete(ctx context.Context, claimID uuid.UUID) error
+		FindByID(ctx context.Context, claimID uuid.UUID) (*entities.ReimbursementClaim, error)
+		FindItems(ctx context.Context, claimID uuid.UUID) ([]*entities.ReimbursementClaimItem, error)
+		FindByWorkspace(ctx context.Context, params *entities.ReimbursementClaimListParams, limit, offset int) ([]*entities.ReimbursementClaim, error)
+		CountByWorkspace(ctx context.Context, params *entities.ReimbursementClaimListParams) (int64, error)
+		FindClaimedTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID, excludedClaimID uuid.UUID) ([]uuid.UUID, error)
+		UpdateStatus(ctx context.Context, claim *entities.ReimbursementClaim, fromStatus int) error
+		MarkPaid(ctx context.Context, claim *entities.ReimbursementClaim, transfer *entities.Transaction) (entities.Transaction, error)
+		FindBalances(ctx context.Context, workspaceID uuid.UUID) ([]*entities.ReimbursementBalance, error)
+	}
+)
+
+// NewReimbursementRepository creates a new ReimbursementRepository
+func NewReimbursementRepository(pg *postgres.Postgres) ReimbursementRepository {
+	return &reimbursementRepository{pg}
+}
+
+const reimbursementClaimSelect = `
+	SELECT c.claim_id, c.workspace_id, c.claimant_id, c.title, c.notes, c.payout_account_id, c.total_amount,
+	       c.status, c.submitted_at, c.reviewed_by, c.reviewed_at, c.review_note, c.paid_by, c.paid_at,
+	       c.payment_transaction_id, c.created_at, c.updated_at,
+	       TRIM(u.first_name || ' ' || u.last_name), COALESCE(a.account_name, '')
+	FROM "vasst_expense".reimbursement_claims c
+	INNER JOIN "vasst_expense".users u ON c.claimant_id = u.user_id
+	LEFT JOIN "vasst_expense".accounts a ON c.payout_account_id = a.account_id
+`
+
+// scanReimbursementClaim scans a claim row joined with its claimant and payout account
+func scanReimbursementClaim(scan func(dest ...interface{}) error) (*entities.ReimbursementClaim, error) {
+	var claim entities.ReimbursementClaim
+	err := scan(
+		&claim.ClaimID,
+		&claim.WorkspaceID,
+		&claim.ClaimantID,
+		&claim.Title,
+		&claim.Notes,
+		&claim.PayoutAccountID,
+		&claim.TotalAmount,
+		&claim.Status,
+		&claim.SubmittedAt,
+		&claim.ReviewedBy,
+		&claim.ReviewedAt,
+		&claim.ReviewNote,
+		&claim.PaidBy,
+		&claim.PaidAt,
+		&claim.PaymentTransactionID,
+		&claim.CreatedAt,
+		&claim.UpdatedAt,
+		&claim.ClaimantName,
+		&claim.PayoutAccountName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+// Create records a new claim with its expenses
+func (r *reimbursementRepository) Create(ctx context.Context, claim *entities.ReimbursementClaim, items []*entities.ReimbursementClaimItem) (entities.ReimbursementClaim, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO "vasst_expense".reimbursement_claims (
+			claim_id, workspace_id, claimant_id, title, notes, payout_account_id, status, submitted_at,
+			created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		claim.ClaimID,
+		claim.WorkspaceID,
+		claim.ClaimantID,
+		claim.Title,
+		claim.Notes,
+		claim.PayoutAccountID,
+		claim.Status,
+		claim.SubmittedAt,
+	)
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	if err := replaceClaimItems(ctx, tx, claim.ClaimID, items); err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	return r.findExisting(ctx, claim.ClaimID)
+}
+
+// Update replaces the details and expenses of a draft or rejected claim, its status and submission are updated too
+// so a rejected claim can be submitted again in the same write
+func (r *reimbursementRepository) Update(ctx context.Context, claim *entities.ReimbursementClaim, items []*entities.ReimbursementClaimItem) (entities.ReimbursementClaim, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE "vasst_expense".reimbursement_claims
+		SET title = $2, notes = $3, payout_account_id = $4, status = $5, submitted_at = $6,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE claim_id = $1 AND status IN ($7, $8)
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		claim.ClaimID,
+		claim.Title,
+		claim.Notes,
+		claim.PayoutAccountID,
+		claim.Status,
+		claim.SubmittedAt,
+		entities.ReimbursementStatusDraft,
+		entities.ReimbursementStatusRejected,
+	)
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+	if rowsAffected == 0 {
+		return entities.ReimbursementClaim{}, sql.ErrNoRows
+	}
+
+	if err := replaceClaimItems(ctx, tx, claim.ClaimID, items); err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+
+	return r.findExisting(ctx, claim.ClaimID)
+}
+
+// replaceClaimItems replaces the expenses of a claim and recomputes its total in the workspace currency.
+// It returns ErrNoExchangeRate when an expense cannot be converted.
+func replaceClaimItems(ctx context.Context, tx *sql.Tx, claimID uuid.UUID, items []*entities.ReimbursementClaimItem) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "vasst_expense".reimbursement_claim_items WHERE claim_id = $1`, claimID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "vasst_expense".reimbursement_claim_items (claim_item_id, claim_id, transaction_id, receipt_url, created_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+	`
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, query, item.ClaimItemID, claimID, item.TransactionID, item.ReceiptURL); err != nil {
+			return err
+		}
+	}
+
+	// A claim is paid out in full, so its total cannot leave out an expense without an exchange rate
+	unconvertedQuery := `
+		SELECT COUNT(*)
+		FROM "vasst_expense".reimbursement_claim_items i
+		INNER JOIN "vasst_expense".transactions t ON i.transaction_id = t.transaction_id
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+		WHERE i.claim_id = $1
+		AND ` + convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "w.currency_id", "t.transaction_date") + ` IS NULL
+	`
+	var unconverted int
+	if err := tx.QueryRowContext(ctx, unconvertedQuery, claimID).Scan(&unconverted); err != nil {
+		return err
+	}
+	if unconverted > 0 {
+		return ErrNoExchangeRate
+	}
+
+	totalQuery := `
+		UPDATE "vasst_expense".reimbursement_claims c
+		SET total_amount = COALESCE((
+			SELECT ROUND(SUM(` + convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "w.currency_id", "t.transaction_date") + `), 2)
+			FROM "vasst_expense".reimbursement_claim_items i
+			INNER JOIN "vasst_expense".transactions t ON i.transaction_id = t.transaction_id
+			INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+			LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+			WHERE i.claim_id = c.claim_id
+		), 0)
+		WHERE c.claim_id = $1
+	`
+	_, err := tx.ExecContext(ctx, totalQuery, claimID)
+	return err
+}
+
+// findExisting returns a claim that must exist, right after writing it
+func (r *reimbursementRepository) findExisting(ctx context.Context, claimID uuid.UUID) (entities.ReimbursementClaim, error) {
+	claim, err := r.FindByID(ctx, claimID)
+	if err != nil {
+		return entities.ReimbursementClaim{}, err
+	}
+	if claim == nil {
+		return entities.ReimbursementClaim{}, sql.ErrNoRows
+	}
+
+	return *claim, nil
+}
+
+// Delete deletes a draft or rejected claim, its expenses can then be claimed again
+func (r *reimbursementRepository) Delete(ctx context.Context, claimID uuid.UUID) error {
+	query := `
+		DELETE FROM "vasst_expense".reimbursement_claims
+		WHERE claim_id = $1 AND status IN ($2, $3)
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, claimID, entities.ReimbursementStatusDraft, entities.ReimbursementStatusRejected)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindByID returns a claim by ID without its expenses
+func (r *reimbursementRepository) FindByID(ctx context.Context, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	query := reimbursementClaimSelect + `
+		WHERE c.claim_id = $1
+	`
+
+	claim, err := scanReimbursementClaim(r.DB.QueryRowContext(ctx, query, claimID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// FindItems returns the expenses of a claim, oldest first
+func (r *reimbursementRepository) FindItems(ctx context.Context, claimID uuid.UUID) ([]*entities.ReimbursementClaimItem, error) {
+	query := `
+		SELECT i.claim_item_id, i.claim_id, i.transaction_id, i.receipt_url, i.created_at,
+		       t.description, t.amount, t.transaction_date, t.merchant_name, uc.name
+		FROM "vasst_expense".reimbursement_claim_items i
+		INNER JOIN "vasst_expense".transactions t ON i.transaction_id = t.transaction_id
+		LEFT JOIN "vasst_expense".user_categories uc ON t.category_id = uc.user_category_id
+		WHERE i.claim_id = $1
+		ORDER BY t.transaction_date ASC, t.created_at ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, claimID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*entities.ReimbursementClaimItem
+	for rows.Next() {
+		var item entities.ReimbursementClaimItem
+		err := rows.Scan(
+			&item.ClaimItemID,
+			&item.ClaimID,
+			&item.TransactionID,
+			&item.ReceiptURL,
+			&item.CreatedAt,
+			&item.Description,
+			&item.Amount,
+			&item.TransactionDate,
+			&item.MerchantName,
+			&item.CategoryName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// reimbursementClaimWhere builds the WHERE clause of the claim list filters
+func reimbursementClaimWhere(params *entities.ReimbursementClaimListParams) (string, []interface{}) {
+	where := " WHERE c.workspace_id = $1"
+	args := []interface{}{params.WorkspaceID}
+
+	if params.ClaimantID != nil {
+		args = append(args, *params.ClaimantID)
+		where += fmt.Sprintf(" AND c.claimant_id = $%d", len(args))
+	}
+	if params.Status != nil {
+		args = append(args, *params.Status)
+		where += fmt.Sprintf(" AND c.status = $%d", len(args))
+	}
+
+	return where, args
+}
+
+// FindByWorkspace returns the claims of a workspace matching the filters, newest first
+func (r *reimbursementRepository) FindByWorkspace(ctx context.Context, params *entities.ReimbursementClaimListParams, limit, offset int) ([]*entities.ReimbursementClaim, error) {
+	where, args := reimbursementClaimWhere(params)
+	args = append(args, limit, offset)
+	query := reimbursementClaimSelect + where +
+		fmt.Sprintf(" ORDER BY c.created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claims []*entities.ReimbursementClaim
+	for rows.Next() {
+		claim, err := scanReimbursementClaim(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// CountByWorkspace counts the claims of a workspace matching the filters
+func (r *reimbursementRepository) CountByWorkspace(ctx context.Context, params *entities.ReimbursementClaimListParams) (int64, error) {
+	where, args := reimbursementClaimWhere(params)
+	query := `SELECT COUNT(*) FROM "vasst_expense".reimbursement_claims c` + where
+
+	var count int64
+	if err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindClaimedTransactionIDs returns which of the transactions already belong to a claim other than excludedClaimID
+func (r *reimbursementRepository) FindClaimedTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID, excludedClaimID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]string, 0, len(transactionIDs))
+	for _, id := range transactionIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `
+		SELECT transaction_id
+		FROM "vasst_expense".reimbursement_claim_items
+		WHERE transaction_id = ANY($1::uuid[]) AND claim_id <> $2
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(ids), excludedClaimID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// UpdateStatus moves a claim from one status to another with its review and payment details.
+// It returns sql.ErrNoRows when the claim is no longer in fromStatus, so concurrent reviews cannot both win.
+func (r *reimbursementRepository) UpdateStatus(ctx context.Context, claim *entities.ReimbursementClaim, fromStatus int) error {
+	query := `
+		UPDATE "vasst_expense".reimbursement_claims
+		SET status = $3, submitted_at = $4, reviewed_by = $5, reviewed_at = $6, review_note = $7,
+		    paid_by = $8, paid_at = $9, payment_transaction_id = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE claim_id = $1 AND status = $2
+	`
+
+	result, err := r.DB.ExecContext(ctx, query,
+		claim.ClaimID,
+		fromStatus,
+		claim.Status,
+		claim.SubmittedAt,
+		claim.ReviewedBy,
+		claim.ReviewedAt,
+		claim.ReviewNote,
+		claim.PaidBy,
+		claim.PaidAt,
+		claim.PaymentTransactionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MarkPaid records the payment transfer of an approved claim, moves the claim to paid and marks its expenses as reimbursed
+// in one database transaction. The claim is locked first, so a claim paid twice at once gets a single transfer.
+// It returns sql.ErrNoRows when the claim is no longer approved or an expense is neither approved nor exempt from approval.
+func (r *reimbursementRepository) MarkPaid(ctx context.Context, claim *entities.ReimbursementClaim, transfer *entities.Transaction) (entities.Transaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+	defer tx.Rollback()
+
+	var status int
+	lockQuery := `SELECT status FROM "vasst_expense".reimbursement_claims WHERE claim_id = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, claim.ClaimID).Scan(&status); err != nil {
+		return entities.Transaction{}, err
+	}
+	if status != entities.ReimbursementStatusApproved {
+		return entities.Transaction{}, sql.ErrNoRows
+	}
+
+	createdTransfer, err := insertTransaction(ctx, tx, transfer)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+	claim.PaymentTransactionID = &createdTransfer.TransactionID
+
+	claimQuery := `
+		UPDATE "vasst_expense".reimbursement_claims
+		SET status = $2, paid_by = $3, paid_at = $4, payment_transaction_id = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE claim_id = $1
+	`
+	_, err = tx.ExecContext(ctx, claimQuery,
+		claim.ClaimID,
+		entities.ReimbursementStatusPaid,
+		claim.PaidBy,
+		claim.PaidAt,
+		claim.PaymentTransactionID,
+	)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+
+	// Claimed expenses are either approved or never needed approval
+	itemsQuery := `
+		UPDATE "vasst_expense".transactions t
+		SET approval_status = $2, approval_note = NULL, approval_decided_by = $3, approval_decided_at = CURRENT_TIMESTAMP,
+		    updated_at = CURRENT_TIMESTAMP
+		FROM "vasst_expense".reimbursement_claim_items i
+		WHERE i.claim_id = $1 AND t.transaction_id = i.transaction_id
+		AND t.approval_status IN ($4, $5)
+	`
+	result, err := tx.ExecContext(ctx, itemsQuery,
+		claim.ClaimID,
+		entities.ApprovalStatusReimbursed,
+		claim.PaidBy,
+		entities.ApprovalStatusApproved,
+		entities.ApprovalStatusNotRequired,
+	)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+	reimbursed, err := result.RowsAffected()
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+
+	var itemCount int64
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM "vasst_expense".reimbursement_claim_items WHERE claim_id = $1`, claim.ClaimID).Scan(&itemCount); err != nil {
+		return entities.Transaction{}, err
+	}
+	if reimbursed != itemCount {
+		return entities.Transaction{}, sql.ErrNoRows
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entities.Transaction{}, err
+	}
+
+	return createdTransfer, nil
+}
+
+// FindBalances returns what a workspace owes every claimant with submitted or approved claims, largest first
+func (r *reimbursementRepository) FindBalances(ctx context.Context, workspaceID uuid.UUID) ([]*entities.ReimbursementBalance, error) {
+	query := `
+		SELECT c.claimant_id, COALESCE(TRIM(u.first_name || ' ' || u.last_name), ''),
+		       COALESCE(SUM(c.total_amount) FILTER (WHERE c.status = $2), 0),
+		       COALESCE(SUM(c.total_amount) FILTER (WHERE c.status = $3), 0),
+		       COALESCE(SUM(c.total_amount), 0),
+		       COUNT(*)
+		FROM "vasst_expense".reimbursement_claims c
+		LEFT JOIN "vasst_expense".users u ON c.claimant_id = u.user_id
+		WHERE c.workspace_id = $1 AND c.status IN ($2, $3)
+		GROUP BY c.claimant_id, u.first_name, u.last_name
+		ORDER BY 5 DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, entities.ReimbursementStatusSubmitted, entities.ReimbursementStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*entities.ReimbursementBalance
+	for rows.Next() {
+		var balance entities.ReimbursementBalance
+		err := rows.Scan(
+			&balance.ClaimantID,
+			&balance.ClaimantName,
+			&balance.SubmittedAmount,
+			&balance.ApprovedAmount,
+			&balance.OutstandingAmount,
+			&balance.ClaimCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	reportRepository struct {
+		*postgres.Postgres
+	}
+
+	ReportRepository interface {
+		FindCashFlowCategoryTotals(ctx context.Context, workspaceID uuid.UUID, transactionType int, currencyID int, startDate, endDate time.Time) ([]*entities.CashFlowCategoryTotal, error)
+		FindCashFlowAccountMovements(ctx context.Context, workspaceID uuid.UUID, startDate, endDate time.Time) ([]*entities.CashFlowAccountMovement, error)
+		FindPendingExpenseTotal(ctx context.Context, workspaceID uuid.UUID, currencyID int, startDate, endDate time.Time) (float64, int, error)
+	}
+)
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository(pg *postgres.Postgres) ReportRepository {
+	return &reportRepository{pg}
+}
+
+// FindCashFlowCategoryTotals returns the income or expenses of a workspace per category over a period,
+// converted into currencyID and largest first. They are read from the daily rollups, which exclude transfers between own accounts.
+// Transactions without an exchange rate are left out of the amount and counted.
+func (r *reportRepository) FindCashFlowCategoryTotals(ctx context.Context, workspaceID uuid.UUID, transactionType int, currencyID int, startDate, endDate time.Time) ([]*entities.CashFlowCategoryTotal, error) {
+	query := `
+		SELECT t.category_id,
+		       COALESCE(MIN(uc.name), 'Uncategorized') as category_name,
+		       COALESCE(SUM(c.amount), 0) as amount,
+		       COALESCE(SUM(t.transaction_count), 0) as transaction_count,
+		       COALESCE(SUM(t.transaction_count) FILTER (WHERE c.amount IS NULL), 0) as unconverted_count
+		FROM "vasst_expense".transaction_daily_rollups t
+		LEFT JOIN "vasst_expense".user_categories uc ON t.category_id = uc.user_category_id
+		CROSS JOIN LATERAL (
+			SELECT ` + convertAmountSQL("t.amount", "t.currency_id", "$3::int", "t.transaction_date") + ` as amount
+		) c
+		WHERE t.workspace_id = $1 AND t.transaction_type = $2
+		AND t.transaction_date BETWEEN $4 AND $5
+		GROUP BY t.category_id
+		ORDER BY amount DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, transactionType, currencyID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*entities.CashFlowCategoryTotal
+	for rows.Next() {
+		var total entities.CashFlowCategoryTotal
+		err := rows.Scan(
+			&total.UserCategoryID,
+			&total.CategoryName,
+			&total.Amount,
+			&total.TransactionCount,
+			&total.UnconvertedCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, &total)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// FindPendingExpenseTotal returns the expenses of a workspace over a period still waiting for approval, converted into
+// currencyID, and the number of them left out because no exchange rate is recorded for their currency.
+// They are read from the transactions, the daily rollups only contain what counts.
+func (r *reportRepository) FindPendingExpenseTotal(ctx context.Context, workspaceID uuid.UUID, currencyID int, startDate, endDate time.Time) (float64, int, error) {
+	query := `
+		SELECT COALESCE(SUM(c.amount), 0), COUNT(*) FILTER (WHERE c.amount IS NULL)
+		FROM "vasst_expense".transactions t
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+		CROSS JOIN LATERAL (
+			SELECT ` + convertAmountSQL("t.amount", "COALESCE(a.currency_id, w.currency_id)", "$2::int", "t.transaction_date") + ` as amount
+		) c
+		WHERE t.workspace_id = $1 AND t.transaction_type = 2 AND t.transfer_account_id IS NULL
+		AND t.approval_status = 2
+		AND t.transaction_date BETWEEN $3 AND $4
+	`
+
+	var total float64
+	var unconvertedCount int
+	err := r.DB.QueryRowContext(ctx, query, workspaceID, currencyID, startDate, endDate).Scan(&total, &unconvertedCount)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return total, unconvertedCount, nil
+}
+
+// FindCashFlowAccountMovements returns the money moved in and out of every account used by a workspace during a period,
+// and the net movement after the period. Movements cover all transactions of an account, including transfers.
+func (r *reportRepository) FindCashFlowAccountMovements(ctx context.Context, workspaceID uuid.UUID, startDate, endDate time.Time) ([]*entities.CashFlowAccountMovement, error) {
+	query := `
+		WITH workspace_accounts AS (
+			SELECT account_id FROM "vasst_expense".transactions
+			WHERE workspace_id = $1 AND account_id IS NOT NULL
+			UNION
+			SELECT transfer_account_id FROM "vasst_expense".transactions
+			WHERE workspace_id = $1 AND transfer_account_id IS NOT NULL
+		),
+		movements AS (
+			SELECT t.account_id, t.transaction_date,
+			       CASE WHEN t.transaction_type = 1 THEN t.amount ELSE -t.amount END as delta
+			FROM "vasst_expense".transactions t
+			WHERE t.account_id IN (SELECT account_id FROM workspace_accounts)
+			UNION ALL
+			SELECT t.transfer_account_id, t.transaction_date,
+			       CASE WHEN t.transaction_type = 1 THEN -t.amount ELSE t.amount END
+			FROM "vasst_expense".transactions t
+			WHERE t.transfer_account_id IN (SELECT account_id FROM workspace_accounts)
+		)
+		SELECT a.account_id, a.account_name, a.currency_id, a.current_balance,
+		       COALESCE(SUM(m.delta) FILTER (WHERE m.transaction_date BETWEEN $2 AND $3 AND m.delta > 0), 0) as inflow,
+		       COALESCE(-SUM(m.delta) FILTER (WHERE m.transaction_date BETWEEN $2 AND $3 AND m.delta < 0), 0) as outflow,
+		       COALESCE(SUM(m.delta) FILTER (WHERE m.transaction_date > $3), 0) as movement_after_period
+		FROM "vasst_expense".accounts a
+		INNER JOIN workspace_accounts wa ON a.account_id = wa.account_id
+		LEFT JOIN movements m ON a.account_id = m.account_id
+		GROUP BY a.account_id, a.account_name, a.currency_id, a.current_balance
+		ORDER BY a.account_name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []*entities.CashFlowAccountMovement
+	for rows.Next() {
+		var movement entities.CashFlowAccountMovement
+		err := rows.Scan(
+			&movement.AccountID,
+			&movement.AccountName,
+			&movement.CurrencyID,
+			&movement.CurrentBalance,
+			&movement.Inflow,
+			&movement.Outflow,
+			&movement.MovementAfterPeriod,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, &movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
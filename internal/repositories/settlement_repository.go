@@ -0,0 +1,373 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	settlementRepository struct {
+		*postgres.Postgres
+	}
+
+	// SettlementRepository defines methods for interacting with transaction splits and settlements in the database
+	SettlementRepository interface {
+		FindSplitsByTransaction(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionSplit, error)
+		ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []*entities.TransactionSplit) ([]*entities.TransactionSplit, error)
+		FindBalances(ctx context.Context, workspaceID uuid.UUID) ([]*entities.MemberBalance, error)
+
+		Create(ctx context.Context, settlement *entities.Settlement) (entities.Settlement, error)
+		FindByID(ctx context.Context, settlementID uuid.UUID) (*entities.Settlement, error)
+		List(ctx context.Context, params *entities.SettlementListParams, limit, offset int) ([]*entities.Settlement, error)
+		Confirm(ctx context.Context, settlementID uuid.UUID, asPayer, asPayee bool) error
+		Cancel(ctx context.Context, settlementID uuid.UUID, userID uuid.UUID) error
+	}
+)
+
+// NewSettlementRepository creates a new SettlementRepository
+func NewSettlementRepository(pg *postgres.Postgres) SettlementRepository {
+	return &settlementRepository{pg}
+}
+
+// FindSplitsByTransaction returns the splits of a transaction, largest share first
+func (r *settlementRepository) FindSplitsByTransaction(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionSplit, error) {
+	query := `
+		SELECT s.transaction_split_id, s.transaction_id, s.user_id, s.amount, s.created_at, s.updated_at,
+		       TRIM(u.first_name || ' ' || u.last_name)
+		FROM "vasst_expense".transaction_splits s
+		INNER JOIN "vasst_expense".users u ON s.user_id = u.user_id
+		WHERE s.transaction_id = $1
+		ORDER BY s.amount DESC, u.first_name
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var splits []*entities.TransactionSplit
+	for rows.Next() {
+		var split entities.TransactionSplit
+		err := rows.Scan(
+			&split.TransactionSplitID,
+			&split.TransactionID,
+			&split.UserID,
+			&split.Amount,
+			&split.CreatedAt,
+			&split.UpdatedAt,
+			&split.Name,
+		)
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, &split)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
+
+// ReplaceSplits replaces the splits of a transaction in a single transaction, the service keeps the previous
+// splits in the audit log
+func (r *settlementRepository) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []*entities.TransactionSplit) ([]*entities.TransactionSplit, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM "vasst_expense".transaction_splits WHERE transaction_id = $1`, transactionID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO "vasst_expense".transaction_splits (
+			transaction_split_id, transaction_id, user_id, amount, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+	for _, split := range splits {
+		if _, err := tx.ExecContext(ctx, query, split.TransactionSplitID, transactionID, split.UserID, split.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.FindSplitsByTransaction(ctx, transactionID)
+}
+
+// FindBalances returns the split balance of every member with split expenses or settlements in a workspace,
+// converted into the workspace currency. Only completed settlements count, splits without an exchange rate are left out and counted.
+func (r *settlementRepository) FindBalances(ctx context.Context, workspaceID uuid.UUID) ([]*entities.MemberBalance, error) {
+	query := `
+		WITH split_debts AS (
+			SELECT s.user_id AS debtor_id, t.created_by AS creditor_id,
+			       ` + convertAmountSQL("s.amount", "COALESCE(a.currency_id, w.currency_id)", "w.currency_id", "t.transaction_date") + ` AS amount
+			FROM "vasst_expense".transaction_splits s
+			INNER JOIN "vasst_expense".transactions t ON s.transaction_id = t.transaction_id
+			INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+			LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+			WHERE t.workspace_id = $1 AND t.transaction_type = $2
+			AND t.created_by IS NOT NULL AND s.user_id <> t.created_by
+		),
+		settled AS (
+			SELECT from_user_id, to_user_id, amount
+			FROM "vasst_expense".settlements
+			WHERE workspace_id = $1 AND status = $3
+		),
+		entries AS (
+			SELECT creditor_id AS user_id, COALESCE(amount, 0) AS lent, 0 AS borrowed, 0 AS paid, 0 AS received,
+			       (amount IS NULL)::int AS unconverted
+			FROM split_debts
+			UNION ALL
+			SELECT debtor_id, 0, COALESCE(amount, 0), 0, 0, (amount IS NULL)::int FROM split_debts
+			UNION ALL
+			SELECT from_user_id, 0, 0, amount, 0, 0 FROM settled
+			UNION ALL
+			SELECT to_user_id, 0, 0, 0, amount, 0 FROM settled
+		)
+		SELECT e.user_id, COALESCE(TRIM(u.first_name || ' ' || u.last_name), ''),
+		       ROUND(SUM(e.lent), 2), ROUND(SUM(e.borrowed), 2), ROUND(SUM(e.paid), 2), ROUND(SUM(e.received), 2),
+		       ROUND(SUM(e.lent - e.borrowed + e.paid - e.received), 2), SUM(e.unconverted)
+		FROM entries e
+		LEFT JOIN "vasst_expense".users u ON e.user_id = u.user_id
+		GROUP BY e.user_id, u.first_name, u.last_name
+		ORDER BY 7 DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID, entities.TransactionTypeExpense, entities.SettlementStatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []*entities.MemberBalance
+	for rows.Next() {
+		var balance entities.MemberBalance
+		err := rows.Scan(
+			&balance.UserID,
+			&balance.Name,
+			&balance.Lent,
+			&balance.Borrowed,
+			&balance.SettledPaid,
+			&balance.SettledReceived,
+			&balance.NetBalance,
+			&balance.UnconvertedCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, &balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+const settlementSelect = `
+	SELECT s.settlement_id, s.workspace_id, s.from_user_id, s.to_user_id, s.amount, s.notes, s.evidence_url,
+		   s.status, s.created_by, s.payer_confirmed_at, s.payee_confirmed_at, s.settled_at,
+		   s.cancelled_by, s.cancelled_at, s.created_at, s.updated_at,
+		   TRIM(fu.first_name || ' ' || fu.last_name), TRIM(tu.first_name || ' ' || tu.last_name)
+	FROM "vasst_expense".settlements s
+	INNER JOIN "vasst_expense".users fu ON s.from_user_id = fu.user_id
+	INNER JOIN "vasst_expense".users tu ON s.to_user_id = tu.user_id
+`
+
+// scanSettlement scans a settlement row joined with its payer and payee
+func scanSettlement(scan func(dest ...interface{}) error) (*entities.Settlement, error) {
+	var settlement entities.Settlement
+	err := scan(
+		&settlement.SettlementID,
+		&settlement.WorkspaceID,
+		&settlement.FromUserID,
+		&settlement.ToUserID,
+		&settlement.Amount,
+		&settlement.Notes,
+		&settlement.EvidenceURL,
+		&settlement.Status,
+		&settlement.CreatedBy,
+		&settlement.PayerConfirmedAt,
+		&settlement.PayeeConfirmedAt,
+		&settlement.SettledAt,
+		&settlement.CancelledBy,
+		&settlement.CancelledAt,
+		&settlement.CreatedAt,
+		&settlement.UpdatedAt,
+		&settlement.FromUserName,
+		&settlement.ToUserName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// Create records a new settlement
+func (r *settlementRepository) Create(ctx context.Context, settlement *entities.Settlement) (entities.Settlement, error) {
+	query := `
+		INSERT INTO "vasst_expense".settlements (
+			settlement_id, workspace_id, from_user_id, to_user_id, amount, notes, evidence_url,
+			status, created_by, payer_confirmed_at, payee_confirmed_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		settlement.SettlementID,
+		settlement.WorkspaceID,
+		settlement.FromUserID,
+		settlement.ToUserID,
+		settlement.Amount,
+		settlement.Notes,
+		settlement.EvidenceURL,
+		settlement.Status,
+		settlement.CreatedBy,
+		settlement.PayerConfirmedAt,
+		settlement.PayeeConfirmedAt,
+	)
+	if err != nil {
+		return entities.Settlement{}, err
+	}
+
+	createdSettlement, err := r.FindByID(ctx, settlement.SettlementID)
+	if err != nil {
+		return entities.Settlement{}, err
+	}
+	if createdSettlement == nil {
+		return entities.Settlement{}, sql.ErrNoRows
+	}
+
+	return *createdSettlement, nil
+}
+
+// FindByID returns a settlement by ID
+func (r *settlementRepository) FindByID(ctx context.Context, settlementID uuid.UUID) (*entities.Settlement, error) {
+	query := settlementSelect + `
+		WHERE s.settlement_id = $1
+	`
+
+	settlement, err := scanSettlement(r.DB.QueryRowContext(ctx, query, settlementID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// List returns the settlement history of a workspace, newest first, cancelled settlements included
+func (r *settlementRepository) List(ctx context.Context, params *entities.SettlementListParams, limit, offset int) ([]*entities.Settlement, error) {
+	query := settlementSelect + `
+		WHERE s.workspace_id = $1
+	`
+	args := []interface{}{params.WorkspaceID}
+
+	if params.UserID != nil {
+		args = append(args, *params.UserID)
+		query += fmt.Sprintf(" AND (s.from_user_id = $%d OR s.to_user_id = $%d)", len(args), len(args))
+	}
+	if params.Status != nil {
+		args = append(args, *params.Status)
+		query += fmt.Sprintf(" AND s.status = $%d", len(args))
+	}
+
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY s.created_at DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settlements []*entities.Settlement
+	for rows.Next() {
+		settlement, err := scanSettlement(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settlements, nil
+}
+
+// Confirm records the confirmation of the payer or the payee of a pending settlement.
+// The settlement completes when both sides confirmed.
+func (r *settlementRepository) Confirm(ctx context.Context, settlementID uuid.UUID, asPayer, asPayee bool) error {
+	query := `
+		UPDATE "vasst_expense".settlements
+		SET payer_confirmed_at = CASE WHEN $2 THEN COALESCE(payer_confirmed_at, CURRENT_TIMESTAMP) ELSE payer_confirmed_at END,
+			payee_confirmed_at = CASE WHEN $3 THEN COALESCE(payee_confirmed_at, CURRENT_TIMESTAMP) ELSE payee_confirmed_at END,
+			status = CASE WHEN ($2 OR payer_confirmed_at IS NOT NULL) AND ($3 OR payee_confirmed_at IS NOT NULL) THEN $5 ELSE status END,
+			settled_at = CASE WHEN ($2 OR payer_confirmed_at IS NOT NULL) AND ($3 OR payee_confirmed_at IS NOT NULL) THEN CURRENT_TIMESTAMP ELSE settled_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE settlement_id = $1 AND status = $4
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, settlementID, asPayer, asPayee,
+		entities.SettlementStatusPending, entities.SettlementStatusCompleted)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Cancel cancels a pending settlement, the row is kept for the history
+func (r *settlementRepository) Cancel(ctx context.Context, settlementID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".settlements
+		SET status = $3, cancelled_by = $2, cancelled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE settlement_id = $1 AND status = $4
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, settlementID, userID,
+		entities.SettlementStatusCancelled, entities.SettlementStatusPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	spendingAnomalyRepository struct {
+		*postgres.Postgres
+	}
+
+	SpendingAnomalyRepository interface {
+		FindUncheckedTransactions(ctx context.Context, limit int) ([]*entities.AnomalyCandidate, error)
+		FindBaseline(ctx context.Context, candidate *entities.AnomalyCandidate, startDate time.Time) (*entities.AnomalyBaseline, error)
+		MarkChecked(ctx context.Context, transactionIDs []uuid.UUID) error
+		Create(ctx context.Context, anomaly *entities.SpendingAnomaly) (*entities.SpendingAnomaly, error)
+		FindByID(ctx context.Context, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error)
+		FindByUser(ctx context.Context, userID uuid.UUID, status int, limit, offset int) ([]*entities.SpendingAnomaly, error)
+		Dismiss(ctx context.Context, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error)
+	}
+)
+
+// NewSpendingAnomalyRepository creates a new SpendingAnomalyRepository
+func NewSpendingAnomalyRepository(pg *postgres.Postgres) SpendingAnomalyRepository {
+	return &spendingAnomalyRepository{pg}
+}
+
+// FindUncheckedTransactions returns the oldest transactions not checked for anomalies yet, with their amount
+// converted into the currency of the workspace owner and the hour they were recorded at in the owner's timezone.
+// Transactions without an exchange rate are returned as unconverted.
+func (r *spendingAnomalyRepository) FindUncheckedTransactions(ctx context.Context, limit int) ([]*entities.AnomalyCandidate, error) {
+	query := `
+		SELECT t.transaction_id, t.workspace_id, w.created_by, t.transaction_type, t.transfer_account_id,
+		       t.category_id, t.merchant_id, t.merchant_name, t.description,
+		       ` + convertAmountSQL("t.amount", "w.currency_id", "u.currency_id", "t.transaction_date") + `,
+		       t.transaction_date, EXTRACT(HOUR FROM t.created_at AT TIME ZONE u.timezone)::int
+		FROM "vasst_expense".transactions t
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		INNER JOIN "vasst_expense".users u ON w.created_by = u.user_id
+		WHERE t.anomaly_checked_at IS NULL
+		ORDER BY t.created_at ASC
+		LIMIT $1
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*entities.AnomalyCandidate
+	for rows.Next() {
+		var candidate entities.AnomalyCandidate
+		var amount sql.NullFloat64
+		err := rows.Scan(
+			&candidate.TransactionID,
+			&candidate.WorkspaceID,
+			&candidate.UserID,
+			&candidate.TransactionType,
+			&candidate.TransferAccountID,
+			&candidate.CategoryID,
+			&candidate.MerchantID,
+			&candidate.MerchantName,
+			&candidate.Description,
+			&amount,
+			&candidate.TransactionDate,
+			&candidate.Hour,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		candidate.Amount = amount.Float64
+		candidate.Unconverted = !amount.Valid
+
+		candidates = append(candidates, &candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// FindBaseline collects the expenses of the candidate's user from startDate up to the candidate's date, across all their
+// workspaces and converted into their currency. Expenses with an open anomaly are left out of the baselines but still
+// count towards the spending of the current week, so a spike is only flagged once.
+func (r *spendingAnomalyRepository) FindBaseline(ctx context.Context, candidate *entities.AnomalyCandidate, startDate time.Time) (*entities.AnomalyBaseline, error) {
+	query := `
+		SELECT ` + convertAmountSQL("t.amount", "w.currency_id", "u.currency_id", "t.transaction_date") + `,
+		       COALESCE(t.category_id = $5::uuid, false),
+		       EXTRACT(HOUR FROM t.created_at AT TIME ZONE u.timezone)::int,
+		       ($4::date - t.transaction_date) / 7,
+		       EXISTS (
+		           SELECT 1 FROM "vasst_expense".spending_anomalies sa
+		           WHERE sa.transaction_id = t.transaction_id AND sa.status = $6
+		       )
+		FROM "vasst_expense".transactions t
+		INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+		INNER JOIN "vasst_expense".users u ON w.created_by = u.user_id
+		WHERE w.created_by = $1 AND t.transaction_id <> $2
+		AND t.transaction_type = $7 AND t.transfer_account_id IS NULL
+		AND t.transaction_date BETWEEN $3 AND $4
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query,
+		candidate.UserID,
+		candidate.TransactionID,
+		startDate,
+		candidate.TransactionDate,
+		candidate.CategoryID,
+		entities.AnomalyStatusOpen,
+		entities.TransactionTypeExpense,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	baseline := &entities.AnomalyBaseline{}
+	for rows.Next() {
+		var converted sql.NullFloat64
+		var inCategory, openAnomaly bool
+		var hour, week int
+		err := rows.Scan(&converted, &inCategory, &hour, &week, &openAnomaly)
+		if err != nil {
+			return nil, err
+		}
+		// Expenses without an exchange rate into the user currency cannot be compared
+		if !converted.Valid {
+			continue
+		}
+		amount := converted.Float64
+
+		if inCategory && week == 0 {
+			baseline.CategoryWeekSpentSoFar += amount
+		}
+		if openAnomaly {
+			continue
+		}
+
+		baseline.ExpenseAmounts = append(baseline.ExpenseAmounts, amount)
+		if hour >= 0 && hour < 24 {
+			baseline.HourCounts[hour]++
+		}
+		if inCategory {
+			baseline.CategoryAmounts = append(baseline.CategoryAmounts, amount)
+			if week > 0 {
+				for len(baseline.CategoryWeeklyTotals) < week {
+					baseline.CategoryWeeklyTotals = append(baseline.CategoryWeeklyTotals, 0)
+				}
+				baseline.CategoryWeeklyTotals[week-1] += amount
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if candidate.MerchantID != nil || candidate.MerchantName != nil {
+		merchantQuery := `
+			SELECT COUNT(*)
+			FROM "vasst_expense".transactions t
+			INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+			WHERE w.created_by = $1 AND t.transaction_id <> $2 AND t.transaction_type = $5
+			AND t.created_at < (SELECT created_at FROM "vasst_expense".transactions WHERE transaction_id = $2)
+			AND (t.merchant_id = $3::uuid OR LOWER(t.merchant_name) = LOWER($4::text))
+		`
+
+		err = r.DB.QueryRowContext(ctx, merchantQuery,
+			candidate.UserID,
+			candidate.TransactionID,
+			candidate.MerchantID,
+			candidate.MerchantName,
+			entities.TransactionTypeExpense,
+		).Scan(&baseline.MerchantExpenseCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return baseline, nil
+}
+
+// MarkChecked marks transactions as checked for anomalies
+func (r *spendingAnomalyRepository) MarkChecked(ctx context.Context, transactionIDs []uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".transactions
+		SET anomaly_checked_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = ANY($1)
+	`
+
+	_, err := r.DB.ExecContext(ctx, query, pq.Array(transactionIDs))
+	return err
+}
+
+// Create stores an anomaly. It returns nil when the transaction was already flagged for the same anomaly type.
+func (r *spendingAnomalyRepository) Create(ctx context.Context, anomaly *entities.SpendingAnomaly) (*entities.SpendingAnomaly, error) {
+	query := `
+		INSERT INTO "vasst_expense".spending_anomalies (
+			spending_anomaly_id, transaction_id, workspace_id, user_id, anomaly_type,
+			score, expected_value, actual_value, status, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (transaction_id, anomaly_type) DO NOTHING
+		RETURNING spending_anomaly_id, transaction_id, workspace_id, user_id, anomaly_type,
+		          score, expected_value, actual_value, status, dismissed_at, created_at, updated_at
+	`
+
+	var createdAnomaly entities.SpendingAnomaly
+	err := r.DB.QueryRowContext(ctx, query,
+		anomaly.SpendingAnomalyID,
+		anomaly.TransactionID,
+		anomaly.WorkspaceID,
+		anomaly.UserID,
+		anomaly.AnomalyType,
+		anomaly.Score,
+		anomaly.ExpectedValue,
+		anomaly.ActualValue,
+		anomaly.Status,
+	).Scan(
+		&createdAnomaly.SpendingAnomalyID,
+		&createdAnomaly.TransactionID,
+		&createdAnomaly.WorkspaceID,
+		&createdAnomaly.UserID,
+		&createdAnomaly.AnomalyType,
+		&createdAnomaly.Score,
+		&createdAnomaly.ExpectedValue,
+		&createdAnomaly.ActualValue,
+		&createdAnomaly.Status,
+		&createdAnomaly.DismissedAt,
+		&createdAnomaly.CreatedAt,
+		&createdAnomaly.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &createdAnomaly, nil
+}
+
+const spendingAnomalySelect = `
+	SELECT sa.spending_anomaly_id, sa.transaction_id, sa.workspace_id, sa.user_id, sa.anomaly_type,
+	       sa.score, sa.expected_value, sa.actual_value, sa.status, sa.dismissed_at, sa.created_at, sa.updated_at,
+	       t.description, t.merchant_name, t.category_id, t.amount, t.transaction_date
+	FROM "vasst_expense".spending_anomalies sa
+	INNER JOIN "vasst_expense".transactions t ON sa.transaction_id = t.transaction_id
+`
+
+// scanSpendingAnomaly scans a row selected with spendingAnomalySelect
+func scanSpendingAnomaly(scan func(dest ...interface{}) error) (*entities.SpendingAnomaly, error) {
+	var anomaly entities.SpendingAnomaly
+	var transactionDate time.Time
+	err := scan(
+		&anomaly.SpendingAnomalyID,
+		&anomaly.TransactionID,
+		&anomaly.WorkspaceID,
+		&anomaly.UserID,
+		&anomaly.AnomalyType,
+		&anomaly.Score,
+		&anomaly.ExpectedValue,
+		&anomaly.ActualValue,
+		&anomaly.Status,
+		&anomaly.DismissedAt,
+		&anomaly.CreatedAt,
+		&anomaly.UpdatedAt,
+		&anomaly.Description,
+		&anomaly.MerchantName,
+		&anomaly.CategoryID,
+		&anomaly.Amount,
+		&transactionDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	anomaly.TransactionDate = &transactionDate
+
+	return &anomaly, nil
+}
+
+// FindByID returns an anomaly by ID
+func (r *spendingAnomalyRepository) FindByID(ctx context.Context, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error) {
+	query := spendingAnomalySelect + `WHERE sa.spending_anomaly_id = $1`
+
+	anomaly, err := scanSpendingAnomaly(r.DB.QueryRowContext(ctx, query, anomalyID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return anomaly, nil
+}
+
+// FindByUser returns the anomalies of a user, newest first. A status of 0 returns every status.
+func (r *spendingAnomalyRepository) FindByUser(ctx context.Context, userID uuid.UUID, status int, limit, offset int) ([]*entities.SpendingAnomaly, error) {
+	query := spendingAnomalySelect + `
+		WHERE sa.user_id = $1 AND ($2 = 0 OR sa.status = $2)
+		ORDER BY sa.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*entities.SpendingAnomaly
+	for rows.Next() {
+		anomaly, err := scanSpendingAnomaly(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+
+		anomalies = append(anomalies, anomaly)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
+
+// Dismiss marks an anomaly as dismissed, which puts its transaction back into the baselines
+func (r *spendingAnomalyRepository) Dismiss(ctx context.Context, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error) {
+	query := `
+		UPDATE "vasst_expense".spending_anomalies
+		SET status = $2, dismissed_at = COALESCE(dismissed_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE spending_anomaly_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, anomalyID, entities.AnomalyStatusDismissed)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return r.FindByID(ctx, anomalyID)
+}
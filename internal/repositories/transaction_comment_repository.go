@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	transactionCommentRepository struct {
+		*postgres.Postgres
+	}
+
+	// TransactionCommentRepository defines methods for interacting with transaction comments in the database
+	TransactionCommentRepository interface {
+		Create(ctx context.Context, comment *entities.TransactionComment) (*entities.TransactionComment, error)
+		Update(ctx context.Context, comment *entities.TransactionComment) (*entities.TransactionComment, error)
+		Delete(ctx context.Context, commentID uuid.UUID) error
+		FindByID(ctx context.Context, commentID uuid.UUID) (*entities.TransactionComment, error)
+		FindByTransaction(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionComment, error)
+	}
+)
+
+// NewTransactionCommentRepository creates a new TransactionCommentRepository
+func NewTransactionCommentRepository(pg *postgres.Postgres) TransactionCommentRepository {
+	return &transactionCommentRepository{pg}
+}
+
+const transactionCommentSelect = `
+	SELECT c.comment_id, c.transaction_id, c.workspace_id, c.user_id, c.content, c.mentions, c.attachments,
+		   c.channel, c.source_message_id, c.edited_at, c.created_at, c.updated_at,
+		   TRIM(u.first_name || ' ' || u.last_name)
+	FROM "vasst_expense".transaction_comments c
+	INNER JOIN "vasst_expense".users u ON c.user_id = u.user_id
+`
+
+func scanTransactionComment(scan func(dest ...interface{}) error) (*entities.TransactionComment, error) {
+	var comment entities.TransactionComment
+	err := scan(
+		&comment.CommentID,
+		&comment.TransactionID,
+		&comment.WorkspaceID,
+		&comment.UserID,
+		&comment.Content,
+		&comment.Mentions,
+		&comment.Attachments,
+		&comment.Channel,
+		&comment.SourceMessageID,
+		&comment.EditedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.AuthorName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Create inserts a comment and returns it with the author name
+func (r *transactionCommentRepository) Create(ctx context.Context, comment *entities.TransactionComment) (*entities.TransactionComment, error) {
+	query := `
+		INSERT INTO "vasst_expense".transaction_comments (
+			comment_id, transaction_id, workspace_id, user_id, content, mentions, attachments,
+			channel, source_message_id, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		comment.CommentID,
+		comment.TransactionID,
+		comment.WorkspaceID,
+		comment.UserID,
+		comment.Content,
+		comment.Mentions,
+		comment.Attachments,
+		comment.Channel,
+		comment.SourceMessageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, comment.CommentID)
+}
+
+// Update replaces the content, mentions and attachments of a comment and marks it as edited
+func (r *transactionCommentRepository) Update(ctx context.Context, comment *entities.TransactionComment) (*entities.TransactionComment, error) {
+	query := `
+		UPDATE "vasst_expense".transaction_comments
+		SET content = $2, mentions = $3, attachments = $4, edited_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE comment_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, comment.CommentID, comment.Content, comment.Mentions, comment.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return r.FindByID(ctx, comment.CommentID)
+}
+
+// Delete removes a comment
+func (r *transactionCommentRepository) Delete(ctx context.Context, commentID uuid.UUID) error {
+	query := `DELETE FROM "vasst_expense".transaction_comments WHERE comment_id = $1`
+
+	result, err := r.DB.ExecContext(ctx, query, commentID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindByID returns a comment by ID
+func (r *transactionCommentRepository) FindByID(ctx context.Context, commentID uuid.UUID) (*entities.TransactionComment, error) {
+	query := transactionCommentSelect + `WHERE c.comment_id = $1`
+
+	comment, err := scanTransactionComment(r.DB.QueryRowContext(ctx, query, commentID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// FindByTransaction returns the thread of a transaction, oldest first
+func (r *transactionCommentRepository) FindByTransaction(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionComment, error) {
+	query := transactionCommentSelect + `
+		WHERE c.transaction_id = $1
+		ORDER BY c.created_at, c.comment_id
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*entities.TransactionComment{}
+	for rows.Next() {
+		comment, err := scanTransactionComment(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
@@ -24,9 +24,14 @@ type (
 		FindByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*entities.Transaction, error)
 		FindByCategoryID(ctx context.Context, categoryID uuid.UUID, limit, offset int) ([]*entities.Transaction, error)
 		CountByWorkspace(ctx context.Context, workspaceID uuid.UUID, params *entities.TransactionListParams) (int64, error)
+		UpdateApproval(ctx context.Context, transactionID uuid.UUID, fromStatus, toStatus int, note *string, decidedBy *uuid.UUID) error
 	}
 )
 
+// countedApprovalStatuses are the approval statuses of the transactions that count toward totals:
+// those that need no approval, approved and reimbursed expenses
+const countedApprovalStatuses = `(0, 3, 5)`
+
 // NewTransactionRepository creates a new TransactionRepository
 func NewTransactionRepository(pg *postgres.Postgres) TransactionRepository {
 	return &transactionRepository{pg}
@@ -34,36 +39,48 @@ func NewTransactionRepository(pg *postgres.Postgres) TransactionRepository {
 
 // Create creates a new transaction
 func (r *transactionRepository) Create(ctx context.Context, transaction *entities.Transaction) (entities.Transaction, error) {
+	return insertTransaction(ctx, r.DB, transaction)
+}
+
+// rowQuerier runs a query returning one row, on the database or inside a database transaction
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertTransaction inserts a transaction, also used by repositories writing one together with other records
+func insertTransaction(ctx context.Context, db rowQuerier, transaction *entities.Transaction) (entities.Transaction, error) {
 	query := `
 		INSERT INTO "vasst_expense".transactions 
 		(transaction_id, workspace_id, account_id, category_id, description, amount, 
 		 transaction_type, transaction_date, merchant_name, location, 
 		 notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date, 
 		 parent_transaction_id, ai_confidence_score, ai_categorized, credit_status, 
-		 created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		 transfer_account_id, merchant_id, created_by, payment_method, approval_status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, NULLIF($23, 0), $24, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING transaction_id, workspace_id, account_id, category_id, description, amount,
 		          transaction_type, transaction_date, merchant_name, location,
 		          notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		          parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		          created_by, created_at, updated_at
+		          transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 	`
 
 	var createdTransaction entities.Transaction
-	err := r.DB.QueryRowContext(ctx, query,
+	err := db.QueryRowContext(ctx, query,
 		transaction.TransactionID, transaction.WorkspaceID, transaction.AccountID, transaction.CategoryID,
 		transaction.Description, transaction.Amount, transaction.TransactionType,
 		transaction.TransactionDate, transaction.MerchantName, transaction.Location, transaction.Notes,
 		transaction.ReceiptURL, transaction.IsRecurring, transaction.RecurrenceInterval, transaction.RecurrenceEndDate,
 		transaction.ParentTransactionID, transaction.AIConfidenceScore, transaction.AICategorized, transaction.CreditStatus,
-		transaction.CreatedBy,
+		transaction.TransferAccountID, transaction.MerchantID, transaction.CreatedBy, transaction.PaymentMethod, transaction.ApprovalStatus,
 	).Scan(
 		&createdTransaction.TransactionID, &createdTransaction.WorkspaceID, &createdTransaction.AccountID, &createdTransaction.CategoryID,
 		&createdTransaction.Description, &createdTransaction.Amount, &createdTransaction.TransactionType,
 		&createdTransaction.TransactionDate, &createdTransaction.MerchantName, &createdTransaction.Location, &createdTransaction.Notes,
 		&createdTransaction.ReceiptURL, &createdTransaction.IsRecurring, &createdTransaction.RecurrenceInterval, &createdTransaction.RecurrenceEndDate,
 		&createdTransaction.ParentTransactionID, &createdTransaction.AIConfidenceScore, &createdTransaction.AICategorized, &createdTransaction.CreditStatus,
-		&createdTransaction.CreatedBy, &createdTransaction.CreatedAt, &createdTransaction.UpdatedAt,
+		&createdTransaction.TransferAccountID, &createdTransaction.MerchantID, &createdTransaction.CreatedBy, &createdTransaction.PaymentMethod,
+		&createdTransaction.ApprovalStatus, &createdTransaction.ApprovalNote, &createdTransaction.ApprovalDecidedBy, &createdTransaction.ApprovalDecidedAt, &createdTransaction.CreatedAt, &createdTransaction.UpdatedAt,
 	)
 
 	return createdTransaction, err
@@ -74,17 +91,19 @@ func (r *transactionRepository) Update(ctx context.Context, transaction *entitie
 	query := `
 		UPDATE "vasst_expense".transactions 
 		SET account_id = $2, category_id = $3, description = $4, amount = $5,
-		    transaction_type = $6, transaction_date = $8,
-		    merchant_name = $9, location = $10, notes = $11, receipt_url = $12,
-		    is_recurring = $13, recurrence_interval = $14, recurrence_end_date = $15,
-		    parent_transaction_id = $16, ai_confidence_score = $17, ai_categorized = $18,
-		    credit_status = $19, updated_at = CURRENT_TIMESTAMP
+		    transaction_type = $6, transaction_date = $7,
+		    merchant_name = $8, location = $9, notes = $10, receipt_url = $11,
+		    is_recurring = $12, recurrence_interval = $13, recurrence_end_date = $14,
+		    parent_transaction_id = $15, ai_confidence_score = $16, ai_categorized = $17,
+		    credit_status = $18, transfer_account_id = $19, merchant_id = $20, payment_method = NULLIF($21, 0),
+		    approval_status = $22, updated_at = CURRENT_TIMESTAMP
 		WHERE transaction_id = $1
 		RETURNING transaction_id, workspace_id, account_id, category_id, description, amount,
 		          transaction_type, transaction_date, merchant_name, location,
 		          notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		          parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		          created_by, created_at, updated_at
+		          transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 	`
 
 	var updatedTransaction entities.Transaction
@@ -94,13 +113,15 @@ func (r *transactionRepository) Update(ctx context.Context, transaction *entitie
 		transaction.MerchantName, transaction.Location, transaction.Notes, transaction.ReceiptURL,
 		transaction.IsRecurring, transaction.RecurrenceInterval, transaction.RecurrenceEndDate,
 		transaction.ParentTransactionID, transaction.AIConfidenceScore, transaction.AICategorized, transaction.CreditStatus,
+		transaction.TransferAccountID, transaction.MerchantID, transaction.PaymentMethod, transaction.ApprovalStatus,
 	).Scan(
 		&updatedTransaction.TransactionID, &updatedTransaction.WorkspaceID, &updatedTransaction.AccountID, &updatedTransaction.CategoryID,
 		&updatedTransaction.Description, &updatedTransaction.Amount, &updatedTransaction.TransactionType,
 		&updatedTransaction.TransactionDate, &updatedTransaction.MerchantName, &updatedTransaction.Location, &updatedTransaction.Notes,
 		&updatedTransaction.ReceiptURL, &updatedTransaction.IsRecurring, &updatedTransaction.RecurrenceInterval, &updatedTransaction.RecurrenceEndDate,
 		&updatedTransaction.ParentTransactionID, &updatedTransaction.AIConfidenceScore, &updatedTransaction.AICategorized, &updatedTransaction.CreditStatus,
-		&updatedTransaction.CreatedBy, &updatedTransaction.CreatedAt, &updatedTransaction.UpdatedAt,
+		&updatedTransaction.TransferAccountID, &updatedTransaction.MerchantID, &updatedTransaction.CreatedBy, &updatedTransaction.PaymentMethod,
+		&updatedTransaction.ApprovalStatus, &updatedTransaction.ApprovalNote, &updatedTransaction.ApprovalDecidedBy, &updatedTransaction.ApprovalDecidedAt, &updatedTransaction.CreatedAt, &updatedTransaction.UpdatedAt,
 	)
 
 	if err != nil {
@@ -144,7 +165,8 @@ func (r *transactionRepository) FindByID(ctx context.Context, transactionID uuid
 		       transaction_type, transaction_date, merchant_name, location,
 		       notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		       parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		       created_by, created_at, updated_at
+		       transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 		FROM "vasst_expense".transactions 
 		WHERE transaction_id = $1
 	`
@@ -156,7 +178,8 @@ func (r *transactionRepository) FindByID(ctx context.Context, transactionID uuid
 		&transaction.TransactionDate, &transaction.MerchantName, &transaction.Location, &transaction.Notes,
 		&transaction.ReceiptURL, &transaction.IsRecurring, &transaction.RecurrenceInterval, &transaction.RecurrenceEndDate,
 		&transaction.ParentTransactionID, &transaction.AIConfidenceScore, &transaction.AICategorized, &transaction.CreditStatus,
-		&transaction.CreatedBy, &transaction.CreatedAt, &transaction.UpdatedAt,
+		&transaction.TransferAccountID, &transaction.MerchantID, &transaction.CreatedBy, &transaction.PaymentMethod,
+		&transaction.ApprovalStatus, &transaction.ApprovalNote, &transaction.ApprovalDecidedBy, &transaction.ApprovalDecidedAt, &transaction.CreatedAt, &transaction.UpdatedAt,
 	)
 
 	if err != nil {
@@ -176,7 +199,8 @@ func (r *transactionRepository) FindByWorkspace(ctx context.Context, workspaceID
 		       transaction_type, transaction_date, merchant_name, location,
 		       notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		       parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		       created_by, created_at, updated_at
+		       transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 		FROM "vasst_expense".transactions 
 		WHERE workspace_id = $1
 	`
@@ -231,6 +255,11 @@ func (r *transactionRepository) FindByWorkspace(ctx context.Context, workspaceID
 			args = append(args, *params.CreditStatus)
 			argIndex++
 		}
+		if params.ApprovalStatus != nil {
+			query += fmt.Sprintf(" AND approval_status = $%d", argIndex)
+			args = append(args, *params.ApprovalStatus)
+			argIndex++
+		}
 	}
 
 	query += " ORDER BY transaction_date DESC, created_at DESC"
@@ -252,7 +281,8 @@ func (r *transactionRepository) FindByWorkspace(ctx context.Context, workspaceID
 			&transaction.TransactionDate, &transaction.MerchantName, &transaction.Location, &transaction.Notes,
 			&transaction.ReceiptURL, &transaction.IsRecurring, &transaction.RecurrenceInterval, &transaction.RecurrenceEndDate,
 			&transaction.ParentTransactionID, &transaction.AIConfidenceScore, &transaction.AICategorized, &transaction.CreditStatus,
-			&transaction.CreatedBy, &transaction.CreatedAt, &transaction.UpdatedAt,
+			&transaction.TransferAccountID, &transaction.MerchantID, &transaction.CreatedBy, &transaction.PaymentMethod,
+			&transaction.ApprovalStatus, &transaction.ApprovalNote, &transaction.ApprovalDecidedBy, &transaction.ApprovalDecidedAt, &transaction.CreatedAt, &transaction.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -274,7 +304,8 @@ func (r *transactionRepository) FindByAccountID(ctx context.Context, accountID u
 		       transaction_type, transaction_date, merchant_name, location,
 		       notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		       parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		       created_by, created_at, updated_at
+		       transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 		FROM "vasst_expense".transactions 
 		WHERE account_id = $1
 		ORDER BY transaction_date DESC, created_at DESC
@@ -296,7 +327,8 @@ func (r *transactionRepository) FindByAccountID(ctx context.Context, accountID u
 			&transaction.TransactionDate, &transaction.MerchantName, &transaction.Location, &transaction.Notes,
 			&transaction.ReceiptURL, &transaction.IsRecurring, &transaction.RecurrenceInterval, &transaction.RecurrenceEndDate,
 			&transaction.ParentTransactionID, &transaction.AIConfidenceScore, &transaction.AICategorized, &transaction.CreditStatus,
-			&transaction.CreatedBy, &transaction.CreatedAt, &transaction.UpdatedAt,
+			&transaction.TransferAccountID, &transaction.MerchantID, &transaction.CreatedBy, &transaction.PaymentMethod,
+			&transaction.ApprovalStatus, &transaction.ApprovalNote, &transaction.ApprovalDecidedBy, &transaction.ApprovalDecidedAt, &transaction.CreatedAt, &transaction.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -314,7 +346,8 @@ func (r *transactionRepository) FindByCategoryID(ctx context.Context, categoryID
 		       transaction_type, transaction_date, merchant_name, location,
 		       notes, receipt_url, is_recurring, recurrence_interval, recurrence_end_date,
 		       parent_transaction_id, ai_confidence_score, ai_categorized, credit_status,
-		       created_by, created_at, updated_at
+		       transfer_account_id, merchant_id, created_by, COALESCE(payment_method, 0),
+		          approval_status, approval_note, approval_decided_by, approval_decided_at, created_at, updated_at
 		FROM "vasst_expense".transactions 
 		WHERE category_id = $1
 		ORDER BY transaction_date DESC, created_at DESC
@@ -336,7 +369,8 @@ func (r *transactionRepository) FindByCategoryID(ctx context.Context, categoryID
 			&transaction.TransactionDate, &transaction.MerchantName, &transaction.Location, &transaction.Notes,
 			&transaction.ReceiptURL, &transaction.IsRecurring, &transaction.RecurrenceInterval, &transaction.RecurrenceEndDate,
 			&transaction.ParentTransactionID, &transaction.AIConfidenceScore, &transaction.AICategorized, &transaction.CreditStatus,
-			&transaction.CreatedBy, &transaction.CreatedAt, &transaction.UpdatedAt,
+			&transaction.TransferAccountID, &transaction.MerchantID, &transaction.CreatedBy, &transaction.PaymentMethod,
+			&transaction.ApprovalStatus, &transaction.ApprovalNote, &transaction.ApprovalDecidedBy, &transaction.ApprovalDecidedAt, &transaction.CreatedAt, &transaction.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -405,9 +439,42 @@ func (r *transactionRepository) CountByWorkspace(ctx context.Context, workspaceI
 			args = append(args, *params.CreditStatus)
 			argIndex++
 		}
+		if params.ApprovalStatus != nil {
+			query += fmt.Sprintf(" AND approval_status = $%d", argIndex)
+			args = append(args, *params.ApprovalStatus)
+			argIndex++
+		}
 	}
 
 	var count int64
 	err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count, err
 }
+
+// UpdateApproval moves a transaction from one approval status to another, recording who decided and when.
+// It returns sql.ErrNoRows when the transaction is no longer in fromStatus, so concurrent decisions cannot both win.
+func (r *transactionRepository) UpdateApproval(ctx context.Context, transactionID uuid.UUID, fromStatus, toStatus int, note *string, decidedBy *uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".transactions
+		SET approval_status = $3, approval_note = $4, approval_decided_by = $5,
+		    approval_decided_at = CASE WHEN $5::uuid IS NULL THEN NULL ELSE CURRENT_TIMESTAMP END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = $1 AND approval_status = $2
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, transactionID, fromStatus, toStatus, note, decidedBy)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
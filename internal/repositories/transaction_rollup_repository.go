@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	transactionRollupRepository struct {
+		*postgres.Postgres
+	}
+
+	TransactionRollupRepository interface {
+		RefreshDays(ctx context.Context, workspaceID uuid.UUID, dates []time.Time) error
+		RebuildWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+		FindWorkspacesWithoutRollups(ctx context.Context) ([]uuid.UUID, error)
+		FindDriftedDays(ctx context.Context, since *time.Time) ([]*entities.RollupDay, error)
+	}
+)
+
+// NewTransactionRollupRepository creates a new TransactionRollupRepository
+func NewTransactionRollupRepository(pg *postgres.Postgres) TransactionRollupRepository {
+	return &transactionRollupRepository{pg}
+}
+
+// rollupAggregateSelect aggregates transactions (alias t) into rollup rows. Transfers between own accounts and expenses
+// still waiting for approval are left out.
+const rollupAggregateSelect = `
+	SELECT t.workspace_id, t.transaction_date, t.category_id, t.account_id, t.transaction_type,
+	       COALESCE(a.currency_id, w.currency_id) as currency_id, SUM(t.amount) as amount, COUNT(*) as transaction_count
+	FROM "vasst_expense".transactions t
+	INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+	LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+	WHERE t.transfer_account_id IS NULL AND t.approval_status IN ` + countedApprovalStatuses + `
+`
+
+// rollupAggregateGroupBy groups rollupAggregateSelect by the rollup key
+const rollupAggregateGroupBy = `
+	GROUP BY t.workspace_id, t.transaction_date, t.category_id, t.account_id, t.transaction_type, COALESCE(a.currency_id, w.currency_id)
+`
+
+// rollupDates formats dates for a date[] parameter
+func rollupDates(dates []time.Time) interface{} {
+	formatted := make([]string, 0, len(dates))
+	for _, date := range dates {
+		formatted = append(formatted, date.Format("2006-01-02"))
+	}
+	return pq.Array(formatted)
+}
+
+// RefreshDays recomputes the rollups of a workspace on the given days from its transactions
+func (r *transactionRollupRepository) RefreshDays(ctx context.Context, workspaceID uuid.UUID, dates []time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	return r.replace(ctx, "transaction_date = ANY($2::date[])", "t.transaction_date = ANY($2::date[])", workspaceID, rollupDates(dates))
+}
+
+// RebuildWorkspace recomputes every rollup of a workspace from its transactions
+func (r *transactionRollupRepository) RebuildWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+	return r.replace(ctx, "true", "true", workspaceID)
+}
+
+// replace deletes the rollups of a workspace ($1) matching rollupCondition and inserts them again from the transactions
+// matching transactionCondition, in a single database transaction so readers never see a day half rebuilt
+func (r *transactionRollupRepository) replace(ctx context.Context, rollupCondition, transactionCondition string, args ...interface{}) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize rebuilds of the same workspace, concurrent writes would otherwise insert the same day twice
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1::text))`, args[0]); err != nil {
+		return err
+	}
+
+	deleteQuery := `
+		DELETE FROM "vasst_expense".transaction_daily_rollups
+		WHERE workspace_id = $1 AND ` + rollupCondition
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, args...); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO "vasst_expense".transaction_daily_rollups (
+			workspace_id, transaction_date, category_id, account_id, transaction_type, currency_id, amount, transaction_count
+		)` + rollupAggregateSelect + ` AND t.workspace_id = $1 AND ` + transactionCondition + rollupAggregateGroupBy
+
+	if _, err := tx.ExecContext(ctx, insertQuery, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindWorkspacesWithoutRollups returns the workspaces with transactions but no rollups yet
+func (r *transactionRollupRepository) FindWorkspacesWithoutRollups(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+		SELECT w.workspace_id
+		FROM "vasst_expense".workspaces w
+		WHERE EXISTS (
+			SELECT 1 FROM "vasst_expense".transactions t
+			WHERE t.workspace_id = w.workspace_id AND t.transfer_account_id IS NULL
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM "vasst_expense".transaction_daily_rollups r WHERE r.workspace_id = w.workspace_id
+		)
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaceIDs []uuid.UUID
+	for rows.Next() {
+		var workspaceID uuid.UUID
+		if err := rows.Scan(&workspaceID); err != nil {
+			return nil, err
+		}
+
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return workspaceIDs, nil
+}
+
+// FindDriftedDays compares the rollups with the transactions they are built from and returns the days of every
+// workspace where they differ, since a date or over the whole history when since is nil
+func (r *transactionRollupRepository) FindDriftedDays(ctx context.Context, since *time.Time) ([]*entities.RollupDay, error) {
+	query := `
+		WITH expected AS (` + rollupAggregateSelect + ` AND ($1::date IS NULL OR t.transaction_date >= $1::date)` + rollupAggregateGroupBy + `),
+		actual AS (
+			SELECT workspace_id, transaction_date, category_id, account_id, transaction_type, currency_id, amount, transaction_count
+			FROM "vasst_expense".transaction_daily_rollups
+			WHERE $1::date IS NULL OR transaction_date >= $1::date
+		)
+		SELECT DISTINCT COALESCE(e.workspace_id, r.workspace_id), COALESCE(e.transaction_date, r.transaction_date)
+		FROM expected e
+		FULL OUTER JOIN actual r ON e.workspace_id = r.workspace_id
+			AND e.transaction_date = r.transaction_date
+			AND e.transaction_type = r.transaction_type
+			AND e.category_id IS NOT DISTINCT FROM r.category_id
+			AND e.account_id IS NOT DISTINCT FROM r.account_id
+		WHERE e.workspace_id IS NULL OR r.workspace_id IS NULL
+		OR e.amount <> r.amount OR e.transaction_count <> r.transaction_count OR e.currency_id <> r.currency_id
+	`
+
+	var sinceDate interface{}
+	if since != nil {
+		sinceDate = since.Format("2006-01-02")
+	}
+
+	rows, err := r.DB.QueryContext(ctx, query, sinceDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*entities.RollupDay
+	for rows.Next() {
+		var day entities.RollupDay
+		if err := rows.Scan(&day.WorkspaceID, &day.TransactionDate); err != nil {
+			return nil, err
+		}
+
+		days = append(days, &day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
@@ -23,6 +24,17 @@ type (
 		FindByID(ctx context.Context, userID uuid.UUID) (*entities.User, error)
 		FindByEmail(ctx context.Context, email string) (*entities.User, error)
 		FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entities.User, error)
+		SetActiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, makeDefault bool) error
+		ClearWorkspacePreferences(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) error
+		UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+		FindPasswordLockedUntil(ctx context.Context, userID uuid.UUID) (*time.Time, error)
+		RecordFailedPasswordAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockout time.Duration) (*time.Time, error)
+		ClearFailedPasswordAttempts(ctx context.Context, userID uuid.UUID) error
+		MarkPhoneVerified(ctx context.Context, userID uuid.UUID, phoneNumber string) error
+		MarkEmailVerified(ctx context.Context, userID uuid.UUID, email string) error
+		UpdateVerifiedPhoneNumber(ctx context.Context, userID uuid.UUID, phoneNumber string) error
+		ListAdmins(ctx context.Context) ([]*entities.User, error)
+		UpdateSystemRole(ctx context.Context, userID uuid.UUID, systemRole int) error
 	}
 )
 
@@ -42,7 +54,8 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) (entit
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING user_id, email, phone_number, password_hash, first_name, last_name, 
 		          timezone, currency_id, subscription_plan_id, email_verified_at, 
-		          phone_verified_at, status, created_at, updated_at
+		          phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+		          system_role
 	`
 
 	// Handle nullable timestamp fields
@@ -95,6 +108,9 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) (entit
 		&createdUser.Status,
 		&createdUser.CreatedAt,
 		&createdUser.UpdatedAt,
+		&createdUser.DefaultWorkspaceID,
+		&createdUser.ActiveWorkspaceID,
+		&createdUser.SystemRole,
 	)
 
 	if err != nil {
@@ -119,31 +135,48 @@ func (r *userRepository) Create(ctx context.Context, user *entities.User) (entit
 		WorkspaceType: entities.WorkspaceTypePersonal,
 		CurrencyID:    createdUser.CurrencyID,
 		Timezone:      createdUser.Timezone,
+		Settings:      entities.DefaultWorkspaceSettings(entities.WorkspaceTypePersonal),
 		CreatedBy:     createdUser.UserID,
 		IsActive:      true,
 	}
 
+	// The user owns the personal workspace, which is also the default and active workspace
 	queryWorkspace := `
-		INSERT INTO "vasst_expense".workspaces (
-			name, description, workspace_type, currency_id, timezone, is_active, created_by
+		WITH new_workspace AS (
+			INSERT INTO "vasst_expense".workspaces (
+				name, description, workspace_type, currency_id, timezone, settings, is_active, created_by
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING workspace_id, created_by
+		), owner_member AS (
+			INSERT INTO "vasst_expense".workspace_members (workspace_id, user_id, role, joined_at, is_active)
+			SELECT workspace_id, created_by, $9, CURRENT_TIMESTAMP, true FROM new_workspace
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		UPDATE "vasst_expense".users u
+		SET default_workspace_id = nw.workspace_id, active_workspace_id = nw.workspace_id
+		FROM new_workspace nw
+		WHERE u.user_id = nw.created_by
+		RETURNING nw.workspace_id
 	`
 
 	fmt.Println("workspace", workspace)
 
-	_, err = r.DB.ExecContext(ctx, queryWorkspace,
+	err = r.DB.QueryRowContext(ctx, queryWorkspace,
 		workspace.Name,
 		workspace.Description,
 		workspace.WorkspaceType,
 		workspace.CurrencyID,
 		workspace.Timezone,
+		workspace.Settings,
 		workspace.IsActive,
 		workspace.CreatedBy,
-	)
+		entities.WorkspaceRoleOwner,
+	).Scan(&workspace.WorkspaceID)
 	if err != nil {
 		return entities.User{}, err
 	}
+	createdUser.DefaultWorkspaceID = &workspace.WorkspaceID
+	createdUser.ActiveWorkspaceID = &workspace.WorkspaceID
 
 	// // Create user categories
 	// // Get all system categories
@@ -229,7 +262,8 @@ func (r *userRepository) Update(ctx context.Context, user *entities.User) (entit
 		WHERE user_id = $1
 		RETURNING user_id, email, phone_number, password_hash, first_name, last_name, 
 		          timezone, currency_id, subscription_plan_id, email_verified_at, 
-		          phone_verified_at, status, created_at, updated_at
+		          phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+		          system_role
 	`
 
 	// Handle nullable timestamp fields
@@ -281,6 +315,9 @@ func (r *userRepository) Update(ctx context.Context, user *entities.User) (entit
 		&updatedUser.Status,
 		&updatedUser.CreatedAt,
 		&updatedUser.UpdatedAt,
+		&updatedUser.DefaultWorkspaceID,
+		&updatedUser.ActiveWorkspaceID,
+		&updatedUser.SystemRole,
 	)
 
 	if err != nil {
@@ -330,7 +367,8 @@ func (r *userRepository) ListAll(ctx context.Context, limit, offset int) ([]*ent
 	query := `
 		SELECT user_id, email, phone_number, first_name, last_name, 
 			   timezone, currency_id, subscription_plan_id, email_verified_at, 
-			   phone_verified_at, status, created_at, updated_at
+			   phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+			   system_role
 		FROM "vasst_expense".users
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -362,6 +400,9 @@ func (r *userRepository) ListAll(ctx context.Context, limit, offset int) ([]*ent
 			&user.Status,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.DefaultWorkspaceID,
+			&user.ActiveWorkspaceID,
+			&user.SystemRole,
 		)
 		if err != nil {
 			return nil, err
@@ -393,7 +434,8 @@ func (r *userRepository) FindByID(ctx context.Context, userID uuid.UUID) (*entit
 	query := `
 		SELECT user_id, email, phone_number, password_hash, first_name, last_name, 
 		       timezone, currency_id, subscription_plan_id, email_verified_at, 
-		       phone_verified_at, status, created_at, updated_at
+		       phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+		       system_role
 		FROM "vasst_expense".users
 		WHERE user_id = $1
 	`
@@ -417,6 +459,9 @@ func (r *userRepository) FindByID(ctx context.Context, userID uuid.UUID) (*entit
 		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DefaultWorkspaceID,
+		&user.ActiveWorkspaceID,
+		&user.SystemRole,
 	)
 
 	if err != nil {
@@ -445,7 +490,8 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entiti
 	query := `
 		SELECT user_id, email, phone_number, password_hash, first_name, last_name, 
 		       timezone, currency_id, subscription_plan_id, email_verified_at, 
-		       phone_verified_at, status, created_at, updated_at
+		       phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+		       system_role
 		FROM "vasst_expense".users
 		WHERE email = $1
 	`
@@ -469,6 +515,9 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entiti
 		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DefaultWorkspaceID,
+		&user.ActiveWorkspaceID,
+		&user.SystemRole,
 	)
 
 	if err != nil {
@@ -497,7 +546,8 @@ func (r *userRepository) FindByPhoneNumber(ctx context.Context, phoneNumber stri
 	query := `
 		SELECT user_id, email, phone_number, password_hash, first_name, last_name, 
 		       timezone, currency_id, subscription_plan_id, email_verified_at, 
-		       phone_verified_at, status, created_at, updated_at
+		       phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+		       system_role
 		FROM "vasst_expense".users
 		WHERE phone_number = $1
 	`
@@ -521,6 +571,9 @@ func (r *userRepository) FindByPhoneNumber(ctx context.Context, phoneNumber stri
 		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.DefaultWorkspaceID,
+		&user.ActiveWorkspaceID,
+		&user.SystemRole,
 	)
 
 	if err != nil {
@@ -543,3 +596,263 @@ func (r *userRepository) FindByPhoneNumber(ctx context.Context, phoneNumber stri
 
 	return &user, nil
 }
+
+// SetActiveWorkspace switches the active workspace of a user, and the default workspace too when makeDefault is set
+func (r *userRepository) SetActiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, makeDefault bool) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET active_workspace_id = $2,
+			default_workspace_id = CASE WHEN $3 THEN $2 ELSE default_workspace_id END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, userID, workspaceID, makeDefault)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ClearWorkspacePreferences unsets the default and active workspace of a user that point to a workspace
+// the user left. The active workspace falls back to the default one.
+func (r *userRepository) ClearWorkspacePreferences(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET default_workspace_id = NULLIF(default_workspace_id, $2),
+			active_workspace_id = CASE WHEN active_workspace_id = $2 THEN NULLIF(default_workspace_id, $2) ELSE active_workspace_id END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND (default_workspace_id = $2 OR active_workspace_id = $2)
+	`
+
+	_, err := r.DB.ExecContext(ctx, query, userID, workspaceID)
+	return err
+}
+
+// UpdatePassword sets a new password hash and lifts any lock on password checks
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET password_hash = $2,
+			failed_password_attempts = 0,
+			password_locked_until = NULL,
+			password_changed_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindPasswordLockedUntil returns until when password checks of a user are locked, nil when they are not
+func (r *userRepository) FindPasswordLockedUntil(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	query := `
+		SELECT password_locked_until
+		FROM "vasst_expense".users
+		WHERE user_id = $1 AND password_locked_until > CURRENT_TIMESTAMP
+	`
+
+	var lockedUntil time.Time
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(&lockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lockedUntil, nil
+}
+
+// RecordFailedPasswordAttempt counts a wrong password. The attempt reaching maxAttempts locks password checks
+// for the lockout and starts the count over. It returns the lock it set, if any.
+func (r *userRepository) RecordFailedPasswordAttempt(ctx context.Context, userID uuid.UUID, maxAttempts int, lockout time.Duration) (*time.Time, error) {
+	query := `
+		UPDATE "vasst_expense".users
+		SET failed_password_attempts = CASE WHEN failed_password_attempts + 1 >= $2 THEN 0 ELSE failed_password_attempts + 1 END,
+			password_locked_until = CASE WHEN failed_password_attempts + 1 >= $2
+				THEN CURRENT_TIMESTAMP + make_interval(secs => $3) ELSE password_locked_until END
+		WHERE user_id = $1
+		RETURNING CASE WHEN failed_password_attempts = 0 THEN password_locked_until END
+	`
+
+	var lockedUntil *time.Time
+	err := r.DB.QueryRowContext(ctx, query, userID, maxAttempts, lockout.Seconds()).Scan(&lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockedUntil, nil
+}
+
+// ClearFailedPasswordAttempts starts the count of wrong passwords over after a correct one
+func (r *userRepository) ClearFailedPasswordAttempts(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET failed_password_attempts = 0
+		WHERE user_id = $1 AND failed_password_attempts > 0
+	`
+
+	_, err := r.DB.ExecContext(ctx, query, userID)
+	return err
+}
+
+// MarkPhoneVerified records that a user verified their phone number. It returns sql.ErrNoRows
+// when the user's number changed since the code was sent.
+func (r *userRepository) MarkPhoneVerified(ctx context.Context, userID uuid.UUID, phoneNumber string) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET phone_verified_at = COALESCE(phone_verified_at, CURRENT_TIMESTAMP),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND phone_number = $2
+	`
+
+	return r.execOne(ctx, query, userID, phoneNumber)
+}
+
+// MarkEmailVerified records that a user verified their email. It returns sql.ErrNoRows
+// when the user's email changed since the link was sent.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID, email string) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND email = $2
+	`
+
+	return r.execOne(ctx, query, userID, email)
+}
+
+// UpdateVerifiedPhoneNumber moves a user to a new phone number that was just verified
+func (r *userRepository) UpdateVerifiedPhoneNumber(ctx context.Context, userID uuid.UUID, phoneNumber string) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET phone_number = $2,
+			phone_verified_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+	`
+
+	return r.execOne(ctx, query, userID, phoneNumber)
+}
+
+// ListAdmins returns the users holding a system role, most powerful role first
+func (r *userRepository) ListAdmins(ctx context.Context) ([]*entities.User, error) {
+	query := `
+		SELECT user_id, email, phone_number, first_name, last_name,
+			   timezone, currency_id, subscription_plan_id, email_verified_at,
+			   phone_verified_at, status, created_at, updated_at, default_workspace_id, active_workspace_id,
+			   system_role
+		FROM "vasst_expense".users
+		WHERE system_role <> $1
+		ORDER BY system_role, first_name, last_name
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, entities.SystemRoleNone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		var user entities.User
+		var emailVerifiedAt, phoneVerifiedAt sql.NullTime
+		var email sql.NullString
+
+		err := rows.Scan(
+			&user.UserID,
+			&email,
+			&user.PhoneNumber,
+			&user.FirstName,
+			&user.LastName,
+			&user.Timezone,
+			&user.CurrencyID,
+			&user.SubscriptionPlanID,
+			&emailVerifiedAt,
+			&phoneVerifiedAt,
+			&user.Status,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.DefaultWorkspaceID,
+			&user.ActiveWorkspaceID,
+			&user.SystemRole,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if email.Valid {
+			user.Email = email.String
+		}
+		if emailVerifiedAt.Valid {
+			user.EmailVerifiedAt = &emailVerifiedAt.Time
+		}
+		if phoneVerifiedAt.Valid {
+			user.PhoneVerifiedAt = &phoneVerifiedAt.Time
+		}
+
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UpdateSystemRole grants a system role to a user, or takes it away with SystemRoleNone
+func (r *userRepository) UpdateSystemRole(ctx context.Context, userID uuid.UUID, systemRole int) error {
+	query := `
+		UPDATE "vasst_expense".users
+		SET system_role = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+	`
+
+	return r.execOne(ctx, query, userID, systemRole)
+}
+
+// execOne runs an update of one user, it returns sql.ErrNoRows when no row matched
+func (r *userRepository) execOne(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
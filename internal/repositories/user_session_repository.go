@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	userSessionRepository struct {
+		*postgres.Postgres
+	}
+
+	// UserSessionRepository defines methods for interacting with sessions and their refresh tokens in the database
+	UserSessionRepository interface {
+		Create(ctx context.Context, session *entities.UserSession, token *entities.RefreshToken) error
+		FindByID(ctx context.Context, sessionID uuid.UUID) (*entities.UserSession, error)
+		FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.UserSession, error)
+		FindTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+		RotateToken(ctx context.Context, tokenID uuid.UUID, next *entities.RefreshToken, client entities.SessionClient) error
+		Revoke(ctx context.Context, sessionID uuid.UUID, reason string) error
+		RevokeByUser(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+	}
+)
+
+// NewUserSessionRepository creates a new UserSessionRepository
+func NewUserSessionRepository(pg *postgres.Postgres) UserSessionRepository {
+	return &userSessionRepository{pg}
+}
+
+const userSessionSelect = `
+	SELECT session_id, user_id, device_name, user_agent, ip_address, last_used_at, expires_at,
+	       revoked_at, revoked_reason, created_at
+	FROM "vasst_expense".user_sessions
+`
+
+// scanUserSession scans a session row
+func scanUserSession(scan func(dest ...interface{}) error) (*entities.UserSession, error) {
+	var session entities.UserSession
+	err := scan(
+		&session.SessionID,
+		&session.UserID,
+		&session.DeviceName,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.RevokedReason,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// insertRefreshToken records a refresh token of a session
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, token *entities.RefreshToken) error {
+	query := `
+		INSERT INTO "vasst_expense".refresh_tokens (token_id, session_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		token.TokenID,
+		token.SessionID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+	)
+	return err
+}
+
+// Create opens a session with its first refresh token
+func (r *userSessionRepository) Create(ctx context.Context, session *entities.UserSession, token *entities.RefreshToken) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO "vasst_expense".user_sessions (
+			session_id, user_id, device_name, user_agent, ip_address, last_used_at, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, $6, CURRENT_TIMESTAMP)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		session.SessionID,
+		session.UserID,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertRefreshToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindByID returns a session, revoked or not
+func (r *userSessionRepository) FindByID(ctx context.Context, sessionID uuid.UUID) (*entities.UserSession, error) {
+	query := userSessionSelect + `
+		WHERE session_id = $1
+	`
+
+	session, err := scanUserSession(r.DB.QueryRowContext(ctx, query, sessionID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FindActiveByUser returns the sessions of a user that are neither revoked nor expired, most recently used first
+func (r *userSessionRepository) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.UserSession, error) {
+	query := userSessionSelect + `
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entities.UserSession
+	for rows.Next() {
+		session, err := scanUserSession(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// FindTokenByHash returns the refresh token with a hash, rotated or not
+func (r *userSessionRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	query := `
+		SELECT token_id, session_id, user_id, token_hash, expires_at, rotated_at, created_at
+		FROM "vasst_expense".refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.RefreshToken
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.TokenID,
+		&token.SessionID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateToken exchanges a refresh token for the next one of its session and extends the session.
+// It returns sql.ErrNoRows when the token was already rotated or the session was revoked meanwhile.
+func (r *userSessionRepository) RotateToken(ctx context.Context, tokenID uuid.UUID, next *entities.RefreshToken, client entities.SessionClient) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE "vasst_expense".refresh_tokens
+		SET rotated_at = CURRENT_TIMESTAMP
+		WHERE token_id = $1 AND rotated_at IS NULL
+	`, tokenID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// The device may have moved networks or updated its app since the last refresh
+	result, err = tx.ExecContext(ctx, `
+		UPDATE "vasst_expense".user_sessions
+		SET last_used_at = CURRENT_TIMESTAMP, expires_at = $2,
+		    user_agent = COALESCE(NULLIF($3, ''), user_agent), ip_address = COALESCE(NULLIF($4, ''), ip_address)
+		WHERE session_id = $1 AND revoked_at IS NULL
+	`, next.SessionID, next.ExpiresAt, client.UserAgent, client.IPAddress)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Revoke ends a session, it returns sql.ErrNoRows when the session was already revoked
+func (r *userSessionRepository) Revoke(ctx context.Context, sessionID uuid.UUID, reason string) error {
+	query := `
+		UPDATE "vasst_expense".user_sessions
+		SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = $2
+		WHERE session_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, sessionID, reason)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RevokeByUser ends every session of a user and returns how many were still open
+func (r *userSessionRepository) RevokeByUser(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	query := `
+		UPDATE "vasst_expense".user_sessions
+		SET revoked_at = CURRENT_TIMESTAMP, revoked_reason = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, userID, reason)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
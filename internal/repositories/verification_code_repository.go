@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
@@ -24,6 +25,7 @@ type (
 		CleanupExpiredCodes(ctx context.Context) error
 		IncrementAttempts(ctx context.Context, verificationCodeID uuid.UUID) error
 		MarkAsUsed(ctx context.Context, verificationCodeID uuid.UUID) error
+		CountRecent(ctx context.Context, phoneNumber, codeType string, window time.Duration) (int, error)
 	}
 )
 
@@ -263,7 +265,7 @@ func (r *verificationCodeRepository) IncrementAttempts(ctx context.Context, veri
 	query := `
 		UPDATE "vasst_expense".verification_codes
 		SET attempts_count = attempts_count + 1, updated_at = CURRENT_TIMESTAMP
-		WHERE verification_code_id = $1
+		WHERE verification_code_id = $1 AND attempts_count < max_attempts
 	`
 
 	result, err := r.DB.ExecContext(ctx, query, verificationCodeID)
@@ -288,7 +290,7 @@ func (r *verificationCodeRepository) MarkAsUsed(ctx context.Context, verificatio
 	query := `
 		UPDATE "vasst_expense".verification_codes
 		SET is_used = true, updated_at = CURRENT_TIMESTAMP
-		WHERE verification_code_id = $1
+		WHERE verification_code_id = $1 AND is_used = false
 	`
 
 	result, err := r.DB.ExecContext(ctx, query, verificationCodeID)
@@ -307,3 +309,17 @@ func (r *verificationCodeRepository) MarkAsUsed(ctx context.Context, verificatio
 
 	return nil
 }
+
+// CountRecent returns how many codes of a type were issued to a phone number within a window.
+// The window is applied in the database since created_at has no time zone.
+func (r *verificationCodeRepository) CountRecent(ctx context.Context, phoneNumber, codeType string, window time.Duration) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM "vasst_expense".verification_codes
+		WHERE phone_number = $1 AND code_type = $2 AND created_at >= CURRENT_TIMESTAMP - make_interval(secs => $3)
+	`
+
+	var count int
+	err := r.DB.QueryRowContext(ctx, query, phoneNumber, codeType, window.Seconds()).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,309 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	workspaceInvitationRepository struct {
+		*postgres.Postgres
+	}
+
+	// WorkspaceInvitationRepository defines methods for interacting with workspace invitations in the database
+	WorkspaceInvitationRepository interface {
+		Create(ctx context.Context, invitation *entities.WorkspaceInvitation) (entities.WorkspaceInvitation, error)
+		FindByID(ctx context.Context, invitationID uuid.UUID) (*entities.WorkspaceInvitation, error)
+		FindPendingByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*entities.WorkspaceInvitation, error)
+		FindPendingByContact(ctx context.Context, workspaceID uuid.UUID, email, phoneNumber string) (*entities.WorkspaceInvitation, error)
+		FindPendingForUser(ctx context.Context, userID uuid.UUID, email, phoneNumber string) ([]*entities.WorkspaceInvitation, error)
+		SetInvitee(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error
+		Respond(ctx context.Context, invitationID uuid.UUID, status int) error
+		Accept(ctx context.Context, invitationID uuid.UUID, member *entities.WorkspaceMember) error
+	}
+)
+
+// ErrAlreadyMember is returned when an invitation is accepted by a user who is already an active member of its workspace
+var ErrAlreadyMember = errors.New("user is already a member of this workspace")
+
+// NewWorkspaceInvitationRepository creates a new WorkspaceInvitationRepository
+func NewWorkspaceInvitationRepository(pg *postgres.Postgres) WorkspaceInvitationRepository {
+	return &workspaceInvitationRepository{pg}
+}
+
+const workspaceInvitationSelect = `
+	SELECT i.invitation_id, i.workspace_id, i.invited_by, i.email, i.phone_number, i.invitee_user_id,
+		   i.role, i.status, i.expires_at, i.responded_at, i.created_at, i.updated_at,
+		   w.name, COALESCE(TRIM(u.first_name || ' ' || u.last_name), '')
+	FROM "vasst_expense".workspace_invitations i
+	INNER JOIN "vasst_expense".workspaces w ON i.workspace_id = w.workspace_id
+	LEFT JOIN "vasst_expense".users u ON i.invited_by = u.user_id
+`
+
+// scanWorkspaceInvitation scans an invitation row joined with its workspace and inviter
+func scanWorkspaceInvitation(scan func(dest ...interface{}) error) (*entities.WorkspaceInvitation, error) {
+	var invitation entities.WorkspaceInvitation
+	err := scan(
+		&invitation.InvitationID,
+		&invitation.WorkspaceID,
+		&invitation.InvitedBy,
+		&invitation.Email,
+		&invitation.PhoneNumber,
+		&invitation.InviteeUserID,
+		&invitation.Role,
+		&invitation.Status,
+		&invitation.ExpiresAt,
+		&invitation.RespondedAt,
+		&invitation.CreatedAt,
+		&invitation.UpdatedAt,
+		&invitation.WorkspaceName,
+		&invitation.InviterName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (r *workspaceInvitationRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*entities.WorkspaceInvitation, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*entities.WorkspaceInvitation
+	for rows.Next() {
+		invitation, err := scanWorkspaceInvitation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// Create creates a new invitation
+func (r *workspaceInvitationRepository) Create(ctx context.Context, invitation *entities.WorkspaceInvitation) (entities.WorkspaceInvitation, error) {
+	query := `
+		INSERT INTO "vasst_expense".workspace_invitations (
+			invitation_id, workspace_id, invited_by, email, phone_number, invitee_user_id,
+			role, status, expires_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+
+	_, err := r.DB.ExecContext(ctx, query,
+		invitation.InvitationID,
+		invitation.WorkspaceID,
+		invitation.InvitedBy,
+		invitation.Email,
+		invitation.PhoneNumber,
+		invitation.InviteeUserID,
+		invitation.Role,
+		invitation.Status,
+		invitation.ExpiresAt,
+	)
+	if err != nil {
+		return entities.WorkspaceInvitation{}, err
+	}
+
+	createdInvitation, err := r.FindByID(ctx, invitation.InvitationID)
+	if err != nil {
+		return entities.WorkspaceInvitation{}, err
+	}
+	if createdInvitation == nil {
+		return entities.WorkspaceInvitation{}, sql.ErrNoRows
+	}
+
+	return *createdInvitation, nil
+}
+
+// FindByID returns an invitation by ID
+func (r *workspaceInvitationRepository) FindByID(ctx context.Context, invitationID uuid.UUID) (*entities.WorkspaceInvitation, error) {
+	query := workspaceInvitationSelect + `
+		WHERE i.invitation_id = $1
+	`
+
+	invitation, err := scanWorkspaceInvitation(r.DB.QueryRowContext(ctx, query, invitationID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// FindPendingByWorkspace returns the unexpired pending invitations of a workspace, newest first
+func (r *workspaceInvitationRepository) FindPendingByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*entities.WorkspaceInvitation, error) {
+	query := workspaceInvitationSelect + `
+		WHERE i.workspace_id = $1 AND i.status = $2 AND i.expires_at > CURRENT_TIMESTAMP
+		ORDER BY i.created_at DESC
+	`
+
+	return r.findAll(ctx, query, workspaceID, entities.WorkspaceInvitationStatusPending)
+}
+
+// FindPendingByContact returns the unexpired pending invitation of a workspace sent to an email or phone number
+func (r *workspaceInvitationRepository) FindPendingByContact(ctx context.Context, workspaceID uuid.UUID, email, phoneNumber string) (*entities.WorkspaceInvitation, error) {
+	query := workspaceInvitationSelect + `
+		WHERE i.workspace_id = $1 AND i.status = $2 AND i.expires_at > CURRENT_TIMESTAMP
+		AND (($3 <> '' AND LOWER(i.email) = LOWER($3)) OR ($4 <> '' AND i.phone_number = $4))
+		ORDER BY i.created_at DESC
+		LIMIT 1
+	`
+
+	invitation, err := scanWorkspaceInvitation(r.DB.QueryRowContext(ctx, query, workspaceID, entities.WorkspaceInvitationStatusPending, email, phoneNumber).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// FindPendingForUser returns the unexpired pending invitations addressed to a user, by user, email or phone number.
+// Invitations are only matched by email when it is the user's verified email.
+func (r *workspaceInvitationRepository) FindPendingForUser(ctx context.Context, userID uuid.UUID, email, phoneNumber string) ([]*entities.WorkspaceInvitation, error) {
+	query := workspaceInvitationSelect + `
+		WHERE i.status = $2 AND i.expires_at > CURRENT_TIMESTAMP
+		AND (i.invitee_user_id = $1
+			OR ($3 <> '' AND LOWER(i.email) = LOWER($3) AND EXISTS (
+				SELECT 1 FROM "vasst_expense".users v
+				WHERE v.user_id = $1 AND v.email_verified_at IS NOT NULL AND LOWER(v.email) = LOWER($3)
+			))
+			OR ($4 <> '' AND i.phone_number = $4))
+		ORDER BY i.created_at DESC
+	`
+
+	return r.findAll(ctx, query, userID, entities.WorkspaceInvitationStatusPending, email, phoneNumber)
+}
+
+// SetInvitee records the user an invitation belongs to, used when an invitee registers with the token
+func (r *workspaceInvitationRepository) SetInvitee(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".workspace_invitations
+		SET invitee_user_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE invitation_id = $1 AND status = $3
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, invitationID, userID, entities.WorkspaceInvitationStatusPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Respond moves a pending invitation to its final status. It only succeeds once, which makes the token single-use.
+func (r *workspaceInvitationRepository) Respond(ctx context.Context, invitationID uuid.UUID, status int) error {
+	query := `
+		UPDATE "vasst_expense".workspace_invitations
+		SET status = $2, responded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE invitation_id = $1 AND status = $3
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, invitationID, status, entities.WorkspaceInvitationStatusPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Accept marks a pending invitation accepted and adds its member to the workspace in one transaction, so an invitation
+// is never used up without a member. It returns sql.ErrNoRows when the invitation is no longer pending and
+// ErrAlreadyMember when the user is already an active member.
+func (r *workspaceInvitationRepository) Accept(ctx context.Context, invitationID uuid.UUID, member *entities.WorkspaceMember) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE "vasst_expense".workspace_invitations
+		SET status = $2, responded_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE invitation_id = $1 AND status = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, invitationID, entities.WorkspaceInvitationStatusAccepted, entities.WorkspaceInvitationStatusPending)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// A membership left earlier is reactivated, an active one is kept as is
+	memberQuery := `
+		INSERT INTO "vasst_expense".workspace_members (
+			workspace_member_id, workspace_id, user_id, role, invited_by, joined_at, is_active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role,
+			invited_by = EXCLUDED.invited_by,
+			joined_at = CURRENT_TIMESTAMP,
+			is_active = true,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE "vasst_expense".workspace_members.is_active = false
+		RETURNING workspace_member_id
+	`
+
+	var memberID uuid.UUID
+	err = tx.QueryRowContext(ctx, memberQuery,
+		member.WorkspaceMemberID,
+		member.WorkspaceID,
+		member.UserID,
+		member.Role,
+		member.InvitedBy,
+	).Scan(&memberID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrAlreadyMember
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	workspaceMemberRepository struct {
+		*postgres.Postgres
+	}
+
+	// WorkspaceMemberRepository defines methods for interacting with workspace members in the database
+	WorkspaceMemberRepository interface {
+		Create(ctx context.Context, member *entities.WorkspaceMember) (entities.WorkspaceMember, error)
+		FindByWorkspaceAndUser(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error)
+		FindByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*entities.WorkspaceMember, error)
+		UpdateRole(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, role int) error
+		Remove(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) error
+	}
+)
+
+// NewWorkspaceMemberRepository creates a new WorkspaceMemberRepository
+func NewWorkspaceMemberRepository(pg *postgres.Postgres) WorkspaceMemberRepository {
+	return &workspaceMemberRepository{pg}
+}
+
+// scanWorkspaceMember scans a workspace member row joined with its user
+func scanWorkspaceMember(scan func(dest ...interface{}) error) (*entities.WorkspaceMember, error) {
+	var member entities.WorkspaceMember
+	err := scan(
+		&member.WorkspaceMemberID,
+		&member.WorkspaceID,
+		&member.UserID,
+		&member.Role,
+		&member.InvitedBy,
+		&member.JoinedAt,
+		&member.IsActive,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+		&member.Name,
+		&member.Email,
+		&member.PhoneNumber,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+const workspaceMemberSelect = `
+	SELECT wm.workspace_member_id, wm.workspace_id, wm.user_id, wm.role, wm.invited_by,
+		   wm.joined_at, wm.is_active, wm.created_at, wm.updated_at,
+		   TRIM(u.first_name || ' ' || u.last_name), COALESCE(u.email, ''), u.phone_number
+	FROM "vasst_expense".workspace_members wm
+	INNER JOIN "vasst_expense".users u ON wm.user_id = u.user_id
+`
+
+// Create adds a user to a workspace, reactivating a previously removed membership
+func (r *workspaceMemberRepository) Create(ctx context.Context, member *entities.WorkspaceMember) (entities.WorkspaceMember, error) {
+	query := `
+		INSERT INTO "vasst_expense".workspace_members (
+			workspace_member_id, workspace_id, user_id, role, invited_by, joined_at, is_active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP, true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (workspace_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role,
+			invited_by = EXCLUDED.invited_by,
+			joined_at = CURRENT_TIMESTAMP,
+			is_active = true,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE "vasst_expense".workspace_members.is_active = false
+		RETURNING workspace_member_id
+	`
+
+	var memberID uuid.UUID
+	err := r.DB.QueryRowContext(ctx, query,
+		member.WorkspaceMemberID,
+		member.WorkspaceID,
+		member.UserID,
+		member.Role,
+		member.InvitedBy,
+	).Scan(&memberID)
+	if err != nil {
+		return entities.WorkspaceMember{}, err
+	}
+
+	createdMember, err := r.FindByWorkspaceAndUser(ctx, member.WorkspaceID, member.UserID)
+	if err != nil {
+		return entities.WorkspaceMember{}, err
+	}
+	if createdMember == nil {
+		return entities.WorkspaceMember{}, sql.ErrNoRows
+	}
+
+	return *createdMember, nil
+}
+
+// FindByWorkspaceAndUser returns the active membership of a user in a workspace
+func (r *workspaceMemberRepository) FindByWorkspaceAndUser(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error) {
+	query := workspaceMemberSelect + `
+		WHERE wm.workspace_id = $1 AND wm.user_id = $2 AND wm.is_active = true
+	`
+
+	member, err := scanWorkspaceMember(r.DB.QueryRowContext(ctx, query, workspaceID, userID).Scan)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// FindByWorkspace returns the active members of a workspace, owner first
+func (r *workspaceMemberRepository) FindByWorkspace(ctx context.Context, workspaceID uuid.UUID) ([]*entities.WorkspaceMember, error) {
+	query := workspaceMemberSelect + `
+		WHERE wm.workspace_id = $1 AND wm.is_active = true
+		ORDER BY wm.role, wm.joined_at
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*entities.WorkspaceMember
+	for rows.Next() {
+		member, err := scanWorkspaceMember(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// UpdateRole changes the role of an active member
+func (r *workspaceMemberRepository) UpdateRole(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, role int) error {
+	query := `
+		UPDATE "vasst_expense".workspace_members
+		SET role = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1 AND user_id = $2 AND is_active = true
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, workspaceID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Remove deactivates a membership, the row is kept so past activity still resolves to the user
+func (r *workspaceMemberRepository) Remove(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		UPDATE "vasst_expense".workspace_members
+		SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1 AND user_id = $2 AND is_active = true
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
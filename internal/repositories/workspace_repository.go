@@ -20,6 +20,8 @@ type (
 		Create(ctx context.Context, workspace *entities.Workspace) (entities.Workspace, error)
 		Update(ctx context.Context, workspace *entities.Workspace) (entities.Workspace, error)
 		Delete(ctx context.Context, workspaceID uuid.UUID) error
+		UpdateSettings(ctx context.Context, workspaceID uuid.UUID, settings entities.WorkspaceSettings) error
+		SetArchived(ctx context.Context, workspaceID uuid.UUID, archived bool) error
 		ListAll(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error)
 		FindByID(ctx context.Context, workspaceID uuid.UUID) (*entities.Workspace, error)
 		FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error)
@@ -34,17 +36,27 @@ func NewWorkspaceRepository(pg *postgres.Postgres) WorkspaceRepository {
 
 // Create creates a new workspace
 func (r *workspaceRepository) Create(ctx context.Context, workspace *entities.Workspace) (entities.Workspace, error) {
+	// The creator joins the workspace as its owner in the same statement
 	query := `
-		INSERT INTO "vasst_expense".workspaces (
-			workspace_id, name, description, workspace_type, icon, color_code,
-			currency_id, timezone, settings, is_active, created_by, created_at, updated_at
+		WITH created AS (
+			INSERT INTO "vasst_expense".workspaces (
+				workspace_id, name, description, workspace_type, icon, color_code,
+				currency_id, timezone, settings, budgeting_mode, is_active, created_by, created_at, updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			RETURNING workspace_id, name, description, workspace_type, icon, color_code,
+			          currency_id, timezone, settings, budgeting_mode, is_active, archived_at, created_by, created_at, updated_at
+		), owner AS (
+			INSERT INTO "vasst_expense".workspace_members (workspace_id, user_id, role)
+			SELECT workspace_id, created_by, $13 FROM created WHERE created_by IS NOT NULL
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING workspace_id, name, description, workspace_type, icon, color_code,
-		          currency_id, timezone, settings, is_active, created_by, created_at, updated_at
+		SELECT workspace_id, name, description, workspace_type, icon, color_code,
+		       currency_id, timezone, settings, budgeting_mode, is_active, archived_at, created_by, created_at, updated_at
+		FROM created
 	`
 
 	var createdWorkspace entities.Workspace
+	var settings []byte
 	err := r.DB.QueryRowContext(ctx, query,
 		workspace.WorkspaceID,
 		workspace.Name,
@@ -55,8 +67,10 @@ func (r *workspaceRepository) Create(ctx context.Context, workspace *entities.Wo
 		workspace.CurrencyID,
 		workspace.Timezone,
 		workspace.Settings,
+		workspace.BudgetingMode,
 		workspace.IsActive,
 		workspace.CreatedBy,
+		entities.WorkspaceRoleOwner,
 	).Scan(
 		&createdWorkspace.WorkspaceID,
 		&createdWorkspace.Name,
@@ -66,13 +80,19 @@ func (r *workspaceRepository) Create(ctx context.Context, workspace *entities.Wo
 		&createdWorkspace.ColorCode,
 		&createdWorkspace.CurrencyID,
 		&createdWorkspace.Timezone,
-		&createdWorkspace.Settings,
+		&settings,
+		&createdWorkspace.BudgetingMode,
 		&createdWorkspace.IsActive,
+		&createdWorkspace.ArchivedAt,
 		&createdWorkspace.CreatedBy,
 		&createdWorkspace.CreatedAt,
 		&createdWorkspace.UpdatedAt,
 	)
+	if err != nil {
+		return createdWorkspace, err
+	}
 
+	err = migrateWorkspaceSettings(&createdWorkspace, settings)
 	return createdWorkspace, err
 }
 
@@ -88,14 +108,16 @@ func (r *workspaceRepository) Update(ctx context.Context, workspace *entities.Wo
 			currency_id = $7,
 			timezone = $8,
 			settings = $9,
-			is_active = $10,
+			budgeting_mode = $10,
+			is_active = $11,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE workspace_id = $1
 		RETURNING workspace_id, name, description, workspace_type, icon, color_code,
-		          currency_id, timezone, settings, is_active, created_by, created_at, updated_at
+		          currency_id, timezone, settings, budgeting_mode, is_active, archived_at, created_by, created_at, updated_at
 	`
 
 	var updatedWorkspace entities.Workspace
+	var settings []byte
 	err := r.DB.QueryRowContext(ctx, query,
 		workspace.WorkspaceID,
 		workspace.Name,
@@ -106,6 +128,7 @@ func (r *workspaceRepository) Update(ctx context.Context, workspace *entities.Wo
 		workspace.CurrencyID,
 		workspace.Timezone,
 		workspace.Settings,
+		workspace.BudgetingMode,
 		workspace.IsActive,
 	).Scan(
 		&updatedWorkspace.WorkspaceID,
@@ -116,8 +139,10 @@ func (r *workspaceRepository) Update(ctx context.Context, workspace *entities.Wo
 		&updatedWorkspace.ColorCode,
 		&updatedWorkspace.CurrencyID,
 		&updatedWorkspace.Timezone,
-		&updatedWorkspace.Settings,
+		&settings,
+		&updatedWorkspace.BudgetingMode,
 		&updatedWorkspace.IsActive,
+		&updatedWorkspace.ArchivedAt,
 		&updatedWorkspace.CreatedBy,
 		&updatedWorkspace.CreatedAt,
 		&updatedWorkspace.UpdatedAt,
@@ -130,6 +155,10 @@ func (r *workspaceRepository) Update(ctx context.Context, workspace *entities.Wo
 		return entities.Workspace{}, err
 	}
 
+	if err := migrateWorkspaceSettings(&updatedWorkspace, settings); err != nil {
+		return entities.Workspace{}, err
+	}
+
 	return updatedWorkspace, nil
 }
 
@@ -157,14 +186,65 @@ func (r *workspaceRepository) Delete(ctx context.Context, workspaceID uuid.UUID)
 	return nil
 }
 
-// ListAll returns all workspaces (global, superadmin)
+// UpdateSettings replaces the settings of a workspace
+func (r *workspaceRepository) UpdateSettings(ctx context.Context, workspaceID uuid.UUID, settings entities.WorkspaceSettings) error {
+	query := `
+		UPDATE "vasst_expense".workspaces
+		SET settings = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, workspaceID, settings)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetArchived archives a workspace, or unarchives it when archived is false
+func (r *workspaceRepository) SetArchived(ctx context.Context, workspaceID uuid.UUID, archived bool) error {
+	query := `
+		UPDATE "vasst_expense".workspaces
+		SET archived_at = CASE WHEN $2 THEN CURRENT_TIMESTAMP END, updated_at = CURRENT_TIMESTAMP
+		WHERE workspace_id = $1
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, workspaceID, archived)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListAll returns the workspaces a user is a member of, owned or shared, with the user's role
 func (r *workspaceRepository) ListAll(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error) {
 	query := `
-		SELECT workspace_id, name, description, workspace_type, icon, color_code,
-			   currency_id, timezone, settings, is_active, created_at, updated_at
-		FROM "vasst_expense".workspaces
-		WHERE created_by = $3
-		ORDER BY created_at DESC
+		SELECT w.workspace_id, w.name, w.description, w.workspace_type, w.icon, w.color_code,
+			   w.currency_id, w.timezone, w.settings, w.budgeting_mode, w.is_active, w.archived_at, w.created_by, wm.role, w.created_at, w.updated_at
+		FROM "vasst_expense".workspaces w
+		INNER JOIN "vasst_expense".workspace_members wm ON w.workspace_id = wm.workspace_id
+		WHERE wm.user_id = $3 AND wm.is_active = true
+		ORDER BY w.created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
@@ -177,6 +257,7 @@ func (r *workspaceRepository) ListAll(ctx context.Context, userID uuid.UUID, lim
 	var workspaces []*entities.Workspace
 	for rows.Next() {
 		var workspace entities.Workspace
+		var settings []byte
 
 		err := rows.Scan(
 			&workspace.WorkspaceID,
@@ -187,14 +268,21 @@ func (r *workspaceRepository) ListAll(ctx context.Context, userID uuid.UUID, lim
 			&workspace.ColorCode,
 			&workspace.CurrencyID,
 			&workspace.Timezone,
-			&workspace.Settings,
+			&settings,
+			&workspace.BudgetingMode,
 			&workspace.IsActive,
+			&workspace.ArchivedAt,
+			&workspace.CreatedBy,
+			&workspace.Role,
 			&workspace.CreatedAt,
 			&workspace.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := migrateWorkspaceSettings(&workspace, settings); err != nil {
+			return nil, err
+		}
 
 		workspaces = append(workspaces, &workspace)
 	}
@@ -210,12 +298,13 @@ func (r *workspaceRepository) ListAll(ctx context.Context, userID uuid.UUID, lim
 func (r *workspaceRepository) FindByID(ctx context.Context, workspaceID uuid.UUID) (*entities.Workspace, error) {
 	query := `
 		SELECT workspace_id, name, description, workspace_type, icon, color_code,
-			   currency_id, timezone, settings, is_active, created_by, created_at, updated_at
+			   currency_id, timezone, settings, budgeting_mode, is_active, archived_at, created_by, created_at, updated_at
 		FROM "vasst_expense".workspaces
 		WHERE workspace_id = $1
 	`
 
 	var workspace entities.Workspace
+	var settings []byte
 
 	err := r.DB.QueryRowContext(ctx, query, workspaceID).Scan(
 		&workspace.WorkspaceID,
@@ -226,8 +315,10 @@ func (r *workspaceRepository) FindByID(ctx context.Context, workspaceID uuid.UUI
 		&workspace.ColorCode,
 		&workspace.CurrencyID,
 		&workspace.Timezone,
-		&workspace.Settings,
+		&settings,
+		&workspace.BudgetingMode,
 		&workspace.IsActive,
+		&workspace.ArchivedAt,
 		&workspace.CreatedBy,
 		&workspace.CreatedAt,
 		&workspace.UpdatedAt,
@@ -239,18 +330,22 @@ func (r *workspaceRepository) FindByID(ctx context.Context, workspaceID uuid.UUI
 		}
 		return nil, err
 	}
+	if err := migrateWorkspaceSettings(&workspace, settings); err != nil {
+		return nil, err
+	}
 
 	return &workspace, nil
 }
 
-// FindByUserID returns workspaces created by a specific user
+// FindByUserID returns the workspaces a user is a member of, owned or shared, with the user's role
 func (r *workspaceRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error) {
 	query := `
-		SELECT workspace_id, name, description, workspace_type, icon, color_code,
-			   currency_id, timezone, settings, is_active, created_by, created_at, updated_at
-		FROM "vasst_expense".workspaces
-		WHERE created_by = $1
-		ORDER BY created_at DESC
+		SELECT w.workspace_id, w.name, w.description, w.workspace_type, w.icon, w.color_code,
+			   w.currency_id, w.timezone, w.settings, w.budgeting_mode, w.is_active, w.archived_at, w.created_by, wm.role, w.created_at, w.updated_at
+		FROM "vasst_expense".workspaces w
+		INNER JOIN "vasst_expense".workspace_members wm ON w.workspace_id = wm.workspace_id
+		WHERE wm.user_id = $1 AND wm.is_active = true
+		ORDER BY w.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -263,6 +358,7 @@ func (r *workspaceRepository) FindByUserID(ctx context.Context, userID uuid.UUID
 	var workspaces []*entities.Workspace
 	for rows.Next() {
 		var workspace entities.Workspace
+		var settings []byte
 
 		err := rows.Scan(
 			&workspace.WorkspaceID,
@@ -273,15 +369,21 @@ func (r *workspaceRepository) FindByUserID(ctx context.Context, userID uuid.UUID
 			&workspace.ColorCode,
 			&workspace.CurrencyID,
 			&workspace.Timezone,
-			&workspace.Settings,
+			&settings,
+			&workspace.BudgetingMode,
 			&workspace.IsActive,
+			&workspace.ArchivedAt,
 			&workspace.CreatedBy,
+			&workspace.Role,
 			&workspace.CreatedAt,
 			&workspace.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := migrateWorkspaceSettings(&workspace, settings); err != nil {
+			return nil, err
+		}
 
 		workspaces = append(workspaces, &workspace)
 	}
@@ -297,12 +399,13 @@ func (r *workspaceRepository) FindByUserID(ctx context.Context, userID uuid.UUID
 func (r *workspaceRepository) FindByName(ctx context.Context, name string) (*entities.Workspace, error) {
 	query := `
 		SELECT workspace_id, name, description, workspace_type, icon, color_code,
-			   currency_id, timezone, settings, is_active, created_by, created_at, updated_at
+			   currency_id, timezone, settings, budgeting_mode, is_active, archived_at, created_by, created_at, updated_at
 		FROM "vasst_expense".workspaces
 		WHERE name = $1
 	`
 
 	var workspace entities.Workspace
+	var settings []byte
 
 	err := r.DB.QueryRowContext(ctx, query, name).Scan(
 		&workspace.WorkspaceID,
@@ -313,8 +416,10 @@ func (r *workspaceRepository) FindByName(ctx context.Context, name string) (*ent
 		&workspace.ColorCode,
 		&workspace.CurrencyID,
 		&workspace.Timezone,
-		&workspace.Settings,
+		&settings,
+		&workspace.BudgetingMode,
 		&workspace.IsActive,
+		&workspace.ArchivedAt,
 		&workspace.CreatedBy,
 		&workspace.CreatedAt,
 		&workspace.UpdatedAt,
@@ -326,6 +431,20 @@ func (r *workspaceRepository) FindByName(ctx context.Context, name string) (*ent
 		}
 		return nil, err
 	}
+	if err := migrateWorkspaceSettings(&workspace, settings); err != nil {
+		return nil, err
+	}
 
 	return &workspace, nil
 }
+
+// migrateWorkspaceSettings upgrades the settings scanned with a workspace, fields missing from older versions
+// take the defaults of its type
+func migrateWorkspaceSettings(workspace *entities.Workspace, settings []byte) error {
+	migrated, err := entities.MigrateWorkspaceSettings(settings, workspace.WorkspaceType)
+	if err != nil {
+		return err
+	}
+	workspace.Settings = migrated
+	return nil
+}
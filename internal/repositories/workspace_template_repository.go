@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
+)
+
+type (
+	workspaceTemplateRepository struct {
+		*postgres.Postgres
+	}
+
+	// WorkspaceTemplateRepository defines methods for reading the setup of a workspace and creating a workspace from a template
+	WorkspaceTemplateRepository interface {
+		FindCategories(ctx context.Context, workspaceID uuid.UUID) ([]*entities.UserCategory, error)
+		FindTags(ctx context.Context, workspaceID uuid.UUID) ([]*entities.UserTag, error)
+		FindBudgets(ctx context.Context, workspaceID uuid.UUID) ([]*entities.Budget, error)
+		CreateFromTemplate(ctx context.Context, workspace *entities.Workspace, categories []*entities.UserCategory, tags []*entities.UserTag, budgets []*entities.Budget) error
+	}
+)
+
+// NewWorkspaceTemplateRepository creates a new WorkspaceTemplateRepository
+func NewWorkspaceTemplateRepository(pg *postgres.Postgres) WorkspaceTemplateRepository {
+	return &workspaceTemplateRepository{pg}
+}
+
+// FindCategories returns the user categories used by the transactions and active budgets of a workspace
+func (r *workspaceTemplateRepository) FindCategories(ctx context.Context, workspaceID uuid.UUID) ([]*entities.UserCategory, error) {
+	query := `
+		WITH used AS (
+			SELECT category_id AS user_category_id
+			FROM "vasst_expense".transactions
+			WHERE workspace_id = $1 AND category_id IS NOT NULL
+			UNION
+			SELECT user_category_id
+			FROM "vasst_expense".budgets
+			WHERE workspace_id = $1 AND is_active = true AND user_category_id IS NOT NULL
+			UNION
+			SELECT c.id::uuid
+			FROM "vasst_expense".budgets b
+			CROSS JOIN jsonb_array_elements_text(COALESCE(b.scope->'category_ids', '[]'::jsonb)) c(id)
+			WHERE b.workspace_id = $1 AND b.is_active = true
+		)
+		SELECT uc.user_category_id, uc.user_id, uc.category_id, uc.name, uc.description, uc.icon,
+		       uc.is_custom, uc.is_active, uc.created_at, uc.updated_at
+		FROM "vasst_expense".user_categories uc
+		INNER JOIN used ON uc.user_category_id = used.user_category_id
+		ORDER BY uc.name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*entities.UserCategory
+	for rows.Next() {
+		var category entities.UserCategory
+		err := rows.Scan(
+			&category.UserCategoryID, &category.UserID, &category.CategoryID,
+			&category.Name, &category.Description, &category.Icon,
+			&category.IsCustom, &category.IsActive, &category.CreatedAt, &category.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// FindTags returns the user tags applied to the transactions of a workspace or scoping its active budgets
+func (r *workspaceTemplateRepository) FindTags(ctx context.Context, workspaceID uuid.UUID) ([]*entities.UserTag, error) {
+	query := `
+		WITH used AS (
+			SELECT tt.user_tag_id
+			FROM "vasst_expense".transaction_tags tt
+			INNER JOIN "vasst_expense".transactions t ON tt.transaction_id = t.transaction_id
+			WHERE t.workspace_id = $1 AND tt.user_tag_id IS NOT NULL
+			UNION
+			SELECT g.id::uuid
+			FROM "vasst_expense".budgets b
+			CROSS JOIN jsonb_array_elements_text(COALESCE(b.scope->'tag_ids', '[]'::jsonb)) g(id)
+			WHERE b.workspace_id = $1 AND b.is_active = true
+		)
+		SELECT ut.user_tag_id, ut.user_id, ut.name, ut.is_active, ut.created_at, ut.updated_at
+		FROM "vasst_expense".user_tags ut
+		INNER JOIN used ON ut.user_tag_id = used.user_tag_id
+		ORDER BY ut.name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*entities.UserTag
+	for rows.Next() {
+		var tag entities.UserTag
+		err := rows.Scan(
+			&tag.UserTagID, &tag.UserID, &tag.Name,
+			&tag.IsActive, &tag.CreatedAt, &tag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// FindBudgets returns the active budgets of a workspace, earliest period first
+func (r *workspaceTemplateRepository) FindBudgets(ctx context.Context, workspaceID uuid.UUID) ([]*entities.Budget, error) {
+	query := `
+		SELECT budget_id, workspace_id, user_category_id, name,
+		       budgeted_amount, period_type, period_start, period_end, spent_amount, scope, is_active,
+		       created_by, created_at, updated_at
+		FROM "vasst_expense".budgets
+		WHERE workspace_id = $1 AND is_active = true
+		ORDER BY period_start ASC, name ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []*entities.Budget
+	for rows.Next() {
+		var budget entities.Budget
+		err := rows.Scan(
+			&budget.BudgetID,
+			&budget.WorkspaceID,
+			&budget.UserCategoryID,
+			&budget.Name,
+			&budget.BudgetedAmount,
+			&budget.PeriodType,
+			&budget.PeriodStart,
+			&budget.PeriodEnd,
+			&budget.SpentAmount,
+			&budget.Scope,
+			&budget.IsActive,
+			&budget.CreatedBy,
+			&budget.CreatedAt,
+			&budget.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, &budget)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// CreateFromTemplate creates a workspace owned by its creator together with the missing categories and tags
+// of the creator and the budgets of the workspace, in a single transaction
+func (r *workspaceTemplateRepository) CreateFromTemplate(ctx context.Context, workspace *entities.Workspace, categories []*entities.UserCategory, tags []*entities.UserTag, budgets []*entities.Budget) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "vasst_expense".workspaces (
+			workspace_id, name, description, workspace_type, icon, color_code,
+			currency_id, timezone, settings, budgeting_mode, is_active, created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`,
+		workspace.WorkspaceID,
+		workspace.Name,
+		workspace.Description,
+		workspace.WorkspaceType,
+		workspace.Icon,
+		workspace.ColorCode,
+		workspace.CurrencyID,
+		workspace.Timezone,
+		workspace.Settings,
+		workspace.BudgetingMode,
+		workspace.IsActive,
+		workspace.CreatedBy,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO "vasst_expense".workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, workspace.WorkspaceID, workspace.CreatedBy, entities.WorkspaceRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO "vasst_expense".user_categories
+			(user_category_id, user_id, category_id, name, description, icon, is_custom, is_active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`,
+			category.UserCategoryID, category.UserID, category.CategoryID,
+			category.Name, category.Description, category.Icon,
+			category.IsCustom, category.IsActive,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range tags {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO "vasst_expense".user_tags (user_tag_id, user_id, name, is_active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`, tag.UserTagID, tag.UserID, tag.Name, tag.IsActive)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, budget := range budgets {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO "vasst_expense".budgets (
+				budget_id, workspace_id, user_category_id, name, budgeted_amount,
+				period_type, period_start, period_end, spent_amount, scope, is_active,
+				created_by, created_at, updated_at
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		`,
+			budget.BudgetID,
+			budget.WorkspaceID,
+			nullableUUID(budget.UserCategoryID),
+			budget.Name,
+			budget.BudgetedAmount,
+			budget.PeriodType,
+			budget.PeriodStart,
+			budget.PeriodEnd,
+			budget.SpentAmount,
+			budget.Scope,
+			budget.IsActive,
+			budget.CreatedBy,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+//go:generate mockgen -source=activity_service.go -package=mock -destination=mock/activity_service_mock.go
+type (
+	// DomainEventRecorder records the changes made by the services in the audit log and the activity feed
+	DomainEventRecorder interface {
+		// Record is best effort, a failure never fails the change itself
+		Record(ctx context.Context, event *entities.DomainEvent)
+	}
+
+	ActivityService interface {
+		DomainEventRecorder
+		GetActivities(ctx context.Context, userID uuid.UUID, params *entities.ActivityListParams, limit, offset int) ([]*entities.WorkspaceActivity, int64, error)
+	}
+
+	activityService struct {
+		activityRepo  repositories.ActivityRepository
+		userRepo      repositories.UserRepository
+		workspaceRepo repositories.WorkspaceRepository
+		authorizer    WorkspaceAuthorizer
+	}
+)
+
+// NewActivityService creates a new activity service
+func NewActivityService(activityRepo repositories.ActivityRepository, userRepo repositories.UserRepository, workspaceRepo repositories.WorkspaceRepository, authorizer WorkspaceAuthorizer) ActivityService {
+	return &activityService{
+		activityRepo:  activityRepo,
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		authorizer:    authorizer,
+	}
+}
+
+// activityFanOutLimit caps the workspaces an event on the actor's own data is shown in
+const activityFanOutLimit = 100
+
+// Record writes the event to the audit log and the activity feed of its workspace, or of all the actor's workspaces
+func (s *activityService) Record(ctx context.Context, event *entities.DomainEvent) {
+	channel := middleware.SourceChannelFromContext(ctx)
+
+	auditLog := &entities.AuditLog{
+		AuditLogID:   uuid.New(),
+		UserID:       event.ActorID,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		OldValues:    event.OldValues,
+		NewValues:    event.NewValues,
+		SourceType:   channel,
+	}
+
+	workspaceIDs := []uuid.UUID{event.WorkspaceID}
+	if event.WorkspaceID != uuid.Nil {
+		workspaceID := event.WorkspaceID
+		auditLog.WorkspaceID = &workspaceID
+	} else {
+		workspaceIDs = nil
+		if event.ActorID != nil {
+			workspaces, err := s.workspaceRepo.FindByUserID(ctx, *event.ActorID, activityFanOutLimit, 0)
+			if err == nil {
+				for _, workspace := range workspaces {
+					if workspace.ArchivedAt == nil {
+						workspaceIDs = append(workspaceIDs, workspace.WorkspaceID)
+					}
+				}
+			}
+		}
+	}
+
+	actorName := ""
+	if event.ActorID != nil {
+		if user, err := s.userRepo.FindByID(ctx, *event.ActorID); err == nil && user != nil {
+			actorName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+	}
+	summaryID, summaryEN := activitySummaries(event, actorName, channel)
+
+	activities := make([]*entities.WorkspaceActivity, 0, len(workspaceIDs))
+	for _, workspaceID := range workspaceIDs {
+		activities = append(activities, &entities.WorkspaceActivity{
+			ActivityID:   uuid.New(),
+			WorkspaceID:  workspaceID,
+			ActorID:      event.ActorID,
+			Action:       event.Action,
+			ResourceType: event.ResourceType,
+			ResourceID:   event.ResourceID,
+			Channel:      channel,
+			AIGenerated:  event.AIGenerated,
+			SummaryID:    summaryID,
+			SummaryEN:    summaryEN,
+			AuditLogID:   &auditLog.AuditLogID,
+		})
+	}
+
+	_ = s.activityRepo.Record(ctx, auditLog, activities)
+}
+
+// GetActivities returns the activity feed of a workspace, newest first, with the total matching the filters
+func (s *activityService) GetActivities(ctx context.Context, userID uuid.UUID, params *entities.ActivityListParams, limit, offset int) ([]*entities.WorkspaceActivity, int64, error) {
+	if _, err := s.authorizer.Authorize(ctx, params.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, 0, err
+	}
+
+	activities, err := s.activityRepo.FindByWorkspace(ctx, params, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.activityRepo.CountByWorkspace(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
+
+// memberJoinedEvent is the event of a user joining a workspace, added by the actor or on their own
+func memberJoinedEvent(member *entities.WorkspaceMember, actorID uuid.UUID, memberName string) *entities.DomainEvent {
+	return &entities.DomainEvent{
+		WorkspaceID:  member.WorkspaceID,
+		ActorID:      &actorID,
+		Action:       entities.ActivityActionJoined,
+		ResourceType: entities.ActivityResourceMember,
+		ResourceID:   &member.UserID,
+		ResourceName: memberName,
+		NewValues:    member,
+	}
+}
+
+// activitySummaries builds the human readable summary of an event in Indonesian and English
+func activitySummaries(event *entities.DomainEvent, actorName, channel string) (string, string) {
+	actorNameEN := actorName
+	if actorName == "" {
+		actorName, actorNameEN = "Seseorang", "Someone"
+	}
+
+	if event.ResourceType == entities.ActivityResourceMember {
+		if event.ActorID != nil && event.ResourceID != nil && *event.ActorID != *event.ResourceID {
+			return fmt.Sprintf("%s menambahkan %s ke workspace", actorName, event.ResourceName),
+				fmt.Sprintf("%s added %s to the workspace", actorNameEN, event.ResourceName)
+		}
+		return fmt.Sprintf("%s bergabung ke workspace", actorName),
+			fmt.Sprintf("%s joined the workspace", actorNameEN)
+	}
+
+	verbID, verbEN := "mengubah", "updated"
+	switch event.Action {
+	case entities.ActivityActionCreated:
+		verbID, verbEN = "menambahkan", "added"
+	case entities.ActivityActionDeleted:
+		verbID, verbEN = "menghapus", "deleted"
+	case entities.ActivityActionSubmitted:
+		verbID, verbEN = "mengajukan", "submitted"
+	case entities.ActivityActionApproved:
+		verbID, verbEN = "menyetujui", "approved"
+	case entities.ActivityActionRejected:
+		verbID, verbEN = "menolak", "rejected"
+	case entities.ActivityActionReimbursed:
+		verbID, verbEN = "membayar kembali", "reimbursed"
+	case entities.ActivityActionPaid:
+		verbID, verbEN = "membayar", "paid"
+	}
+
+	nounID, nounEN := event.ResourceType, event.ResourceType
+	switch event.ResourceType {
+	case entities.ActivityResourceTransaction:
+		nounID, nounEN = "transaksi", "transaction"
+		switch event.Detail {
+		case entities.TransactionTypeExpense:
+			nounID, nounEN = "pengeluaran", "expense"
+		case entities.TransactionTypeIncome:
+			nounID, nounEN = "pemasukan", "income"
+		}
+	case entities.ActivityResourceBudget:
+		nounID, nounEN = "anggaran", "budget"
+	case entities.ActivityResourceCategory:
+		nounID, nounEN = "kategori", "category"
+	case entities.ActivityResourceReimbursement:
+		nounID, nounEN = "klaim reimbursement", "reimbursement claim"
+	case entities.ActivityResourceSplit:
+		nounID, nounEN = "pembagian pengeluaran", "expense split"
+	}
+
+	summaryID := fmt.Sprintf("%s %s %s", actorName, verbID, nounID)
+	summaryEN := fmt.Sprintf("%s %s %s", actorNameEN, verbEN, nounEN)
+	if event.ResourceName != "" {
+		summaryID += fmt.Sprintf(" \"%s\"", event.ResourceName)
+		summaryEN += fmt.Sprintf(" \"%s\"", event.ResourceName)
+	}
+	if event.Amount != nil {
+		summaryID += " sebesar " + formatAmount("", *event.Amount)
+		summaryEN += " of " + formatAmount("", *event.Amount)
+	}
+	if channel == entities.ChannelWhatsApp {
+		summaryID += " lewat WhatsApp"
+		summaryEN += " via WhatsApp"
+	}
+	if event.AIGenerated {
+		summaryID += " (AI)"
+		summaryEN += " (AI)"
+	}
+
+	return summaryID, summaryEN
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestActivitySummaries(t *testing.T) {
+	actorID := uuid.New()
+	amount := 150000.0
+
+	t.Run("given an AI expense from WhatsApp, when summarizing, then both languages mention the amount, channel and AI", func(t *testing.T) {
+		summaryID, summaryEN := activitySummaries(&entities.DomainEvent{
+			ActorID:      &actorID,
+			Action:       entities.ActivityActionCreated,
+			ResourceType: entities.ActivityResourceTransaction,
+			ResourceName: "Makan siang",
+			Amount:       &amount,
+			Detail:       entities.TransactionTypeExpense,
+			AIGenerated:  true,
+		}, "Budi Santoso", entities.ChannelWhatsApp)
+		assert.Equal(t, `Budi Santoso menambahkan pengeluaran "Makan siang" sebesar 150.000 lewat WhatsApp (AI)`, summaryID)
+		assert.Equal(t, `Budi Santoso added expense "Makan siang" of 150.000 via WhatsApp (AI)`, summaryEN)
+	})
+
+	t.Run("given a deleted category without a known actor, when summarizing, then someone is named", func(t *testing.T) {
+		summaryID, summaryEN := activitySummaries(&entities.DomainEvent{
+			Action:       entities.ActivityActionDeleted,
+			ResourceType: entities.ActivityResourceCategory,
+			ResourceName: "Hiburan",
+		}, "", entities.ChannelWeb)
+		assert.Equal(t, `Seseorang menghapus kategori "Hiburan"`, summaryID)
+		assert.Equal(t, `Someone deleted category "Hiburan"`, summaryEN)
+	})
+
+	t.Run("given changed expense splits, when summarizing, then the split of the expense is named", func(t *testing.T) {
+		summaryID, summaryEN := activitySummaries(&entities.DomainEvent{
+			ActorID:      &actorID,
+			Action:       entities.ActivityActionUpdated,
+			ResourceType: entities.ActivityResourceSplit,
+			ResourceName: "Makan malam",
+			Amount:       &amount,
+		}, "Budi Santoso", entities.ChannelWeb)
+		assert.Equal(t, `Budi Santoso mengubah pembagian pengeluaran "Makan malam" sebesar 150.000`, summaryID)
+		assert.Equal(t, `Budi Santoso updated expense split "Makan malam" of 150.000`, summaryEN)
+	})
+
+	t.Run("given a member joining on their own, when summarizing, then the member joined", func(t *testing.T) {
+		summaryID, summaryEN := activitySummaries(memberJoinedEvent(&entities.WorkspaceMember{
+			WorkspaceID: uuid.New(),
+			UserID:      actorID,
+		}, actorID, ""), "Budi Santoso", entities.ChannelWeb)
+		assert.Equal(t, "Budi Santoso bergabung ke workspace", summaryID)
+		assert.Equal(t, "Budi Santoso joined the workspace", summaryEN)
+	})
+
+	t.Run("given a member added by an admin, when summarizing, then the admin added the member", func(t *testing.T) {
+		summaryID, summaryEN := activitySummaries(memberJoinedEvent(&entities.WorkspaceMember{
+			WorkspaceID: uuid.New(),
+			UserID:      uuid.New(),
+		}, actorID, "Siti Aminah"), "Budi Santoso", entities.ChannelWeb)
+		assert.Equal(t, "Budi Santoso menambahkan Siti Aminah ke workspace", summaryID)
+		assert.Equal(t, "Budi Santoso added Siti Aminah to the workspace", summaryEN)
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=admin_service.go -package=mock -destination=mock/admin_service_mock.go
+type (
+	AdminService interface {
+		ListAdmins(ctx context.Context) ([]*entities.User, error)
+		SetSystemRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, systemRole int) (*entities.User, error)
+		RecordAction(ctx context.Context, auditLog *entities.AdminAuditLog) error
+		GetAuditLogs(ctx context.Context, params *entities.AdminAuditListParams, limit, offset int) ([]*entities.AdminAuditLog, int64, error)
+	}
+
+	adminService struct {
+		userRepo       repositories.UserRepository
+		auditRepo      repositories.AdminAuditRepository
+		sessionService SessionService
+	}
+)
+
+// NewAdminService creates a new admin service
+func NewAdminService(userRepo repositories.UserRepository, auditRepo repositories.AdminAuditRepository, sessionService SessionService) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		sessionService: sessionService,
+	}
+}
+
+// ListAdmins returns the users holding a system role
+func (s *adminService) ListAdmins(ctx context.Context) ([]*entities.User, error) {
+	return s.userRepo.ListAdmins(ctx)
+}
+
+// SetSystemRole grants a system role to a user, or takes it away with SystemRoleNone.
+// Admins cannot change their own role, so there is always a superadmin left.
+func (s *adminService) SetSystemRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, systemRole int) (*entities.User, error) {
+	if !validSystemRole(systemRole) {
+		return nil, errorsutil.New(400, "invalid system role")
+	}
+	if actorID == userID {
+		return nil, errorsutil.New(400, "you cannot change your own system role")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+	if user.SystemRole == systemRole {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateSystemRole(ctx, userID, systemRole); err != nil {
+		return nil, err
+	}
+
+	// A promoted user gets the role on the next refresh, the tokens of a demoted user must stop working
+	if user.SystemRole != entities.SystemRoleNone {
+		if _, err := s.sessionService.RevokeAll(ctx, userID, entities.SessionRevokedRole); err != nil {
+			return nil, err
+		}
+	}
+
+	user.SystemRole = systemRole
+	return user, nil
+}
+
+// RecordAction writes a request of an admin to the audit log
+func (s *adminService) RecordAction(ctx context.Context, auditLog *entities.AdminAuditLog) error {
+	if auditLog.AuditLogID == uuid.Nil {
+		auditLog.AuditLogID = uuid.New()
+	}
+	return s.auditRepo.Create(ctx, auditLog)
+}
+
+// GetAuditLogs returns the audit log of the administration API, newest first, with the total matching the filters
+func (s *adminService) GetAuditLogs(ctx context.Context, params *entities.AdminAuditListParams, limit, offset int) ([]*entities.AdminAuditLog, int64, error) {
+	auditLogs, err := s.auditRepo.FindAll(ctx, params, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.auditRepo.Count(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return auditLogs, total, nil
+}
+
+// validSystemRole reports whether a role is one of the system roles, or none
+func validSystemRole(systemRole int) bool {
+	switch systemRole {
+	case entities.SystemRoleNone, entities.SystemRoleSuperAdmin, entities.SystemRoleSupport, entities.SystemRoleReadOnly:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestValidSystemRole(t *testing.T) {
+	t.Run("given the system roles or none, when validating, then they are valid", func(t *testing.T) {
+		for _, role := range []int{entities.SystemRoleNone, entities.SystemRoleSuperAdmin, entities.SystemRoleSupport, entities.SystemRoleReadOnly} {
+			assert.True(t, validSystemRole(role))
+		}
+	})
+
+	t.Run("given an unknown role, when validating, then it is invalid", func(t *testing.T) {
+		assert.False(t, validSystemRole(4))
+		assert.False(t, validSystemRole(-1))
+	})
+}
+
+func TestSetSystemRole(t *testing.T) {
+	s := &adminService{}
+	actorID := uuid.New()
+
+	t.Run("given an admin changing their own role, when setting it, then it is refused", func(t *testing.T) {
+		_, err := s.SetSystemRole(context.Background(), actorID, actorID, entities.SystemRoleNone)
+		assert.EqualError(t, err, "you cannot change your own system role")
+	})
+
+	t.Run("given an unknown role, when setting it, then it is refused", func(t *testing.T) {
+		_, err := s.SetSystemRole(context.Background(), actorID, uuid.New(), 9)
+		assert.EqualError(t, err, "invalid system role")
+	})
+}
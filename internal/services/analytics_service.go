@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+//go:generate mockgen -source=analytics_service.go -package=mock -destination=mock/analytics_service_mock.go
+type (
+	AnalyticsService interface {
+		GetBreakdown(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, groupBy string, transactionType int, params *entities.TransactionListParams) (*entities.AnalyticsBreakdown, error)
+		GetTimeSeries(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, interval string, transactionType int, params *entities.TransactionListParams) (*entities.AnalyticsTimeSeries, error)
+	}
+
+	analyticsService struct {
+		analyticsRepo repositories.AnalyticsRepository
+		authorizer    WorkspaceAuthorizer
+	}
+)
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(analyticsRepo repositories.AnalyticsRepository, authorizer WorkspaceAuthorizer) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		authorizer:    authorizer,
+	}
+}
+
+// paymentMethodLabels maps payment methods to their breakdown labels
+var paymentMethodLabels = map[int]string{
+	entities.PaymentMethodDebitQRIS: "Debit/QRIS",
+	entities.PaymentMethodCredit:    "Credit",
+	entities.PaymentMethodCash:      "Cash",
+	entities.PaymentMethodTransfer:  "Transfer",
+}
+
+// GetBreakdown returns the totals of a workspace grouped by category, merchant, account, tag or payment method,
+// converted into the workspace currency
+func (s *analyticsService) GetBreakdown(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, groupBy string, transactionType int, params *entities.TransactionListParams) (*entities.AnalyticsBreakdown, error) {
+	switch groupBy {
+	case entities.AnalyticsGroupByCategory, entities.AnalyticsGroupByMerchant, entities.AnalyticsGroupByAccount,
+		entities.AnalyticsGroupByTag, entities.AnalyticsGroupByPaymentMethod:
+	default:
+		return nil, errors.New("invalid group by")
+	}
+	if params == nil {
+		params = &entities.TransactionListParams{}
+	}
+
+	query, _, err := s.buildQuery(ctx, userID, workspaceID, transactionType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.analyticsRepo.FindTotals(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*entities.AnalyticsBreakdownItem
+	if groupBy == entities.AnalyticsGroupByCategory {
+		rows, err := s.analyticsRepo.FindCategoryBreakdown(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		items = buildCategoryBreakdown(rows, totals.Amount)
+	} else {
+		rows, err := s.analyticsRepo.FindBreakdown(ctx, query, groupBy)
+		if err != nil {
+			return nil, err
+		}
+		items = buildBreakdownItems(groupBy, rows, totals.Amount)
+	}
+
+	return &entities.AnalyticsBreakdown{
+		WorkspaceID:      workspaceID,
+		GroupBy:          groupBy,
+		TransactionType:  query.TransactionType,
+		CurrencyID:       query.CurrencyID,
+		StartDate:        params.StartDate,
+		EndDate:          params.EndDate,
+		TotalAmount:      roundAmount(totals.Amount),
+		TransactionCount: totals.TransactionCount,
+		UnconvertedCount: totals.UnconvertedCount,
+		Items:            items,
+	}, nil
+}
+
+// GetTimeSeries returns the totals of a workspace per day, week or month compared with the previous period.
+// The previous period is the requested period shifted back by its number of intervals.
+func (s *analyticsService) GetTimeSeries(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, interval string, transactionType int, params *entities.TransactionListParams) (*entities.AnalyticsTimeSeries, error) {
+	switch interval {
+	case entities.AnalyticsIntervalDay, entities.AnalyticsIntervalWeek, entities.AnalyticsIntervalMonth:
+	default:
+		return nil, errors.New("invalid interval")
+	}
+	if params == nil {
+		params = &entities.TransactionListParams{}
+	}
+
+	query, workspace, err := s.buildQuery(ctx, userID, workspaceID, transactionType, params)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate := today(workspace.Timezone)
+	if params.EndDate != nil {
+		endDate = *params.EndDate
+	}
+	calendar := workspaceCalendar(workspace.Settings)
+	startDate := defaultSeriesStart(interval, endDate, calendar)
+	if params.StartDate != nil {
+		startDate = *params.StartDate
+	}
+
+	periods := len(analyticsPeriods(interval, startDate, endDate, calendar))
+	previousStart := addAnalyticsPeriods(interval, startDate, -periods)
+	previousEnd := addAnalyticsPeriods(interval, endDate, -periods)
+
+	currentFilters := *params
+	currentFilters.StartDate = &startDate
+	currentFilters.EndDate = &endDate
+	query.Filters = &currentFilters
+	current, err := s.analyticsRepo.FindTimeSeries(ctx, query, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	previousFilters := *params
+	previousFilters.StartDate = &previousStart
+	previousFilters.EndDate = &previousEnd
+	previousQuery := *query
+	previousQuery.Filters = &previousFilters
+	previous, err := s.analyticsRepo.FindTimeSeries(ctx, &previousQuery, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	series := buildAnalyticsTimeSeries(interval, calendar, startDate, endDate, current, previous)
+	series.WorkspaceID = workspaceID
+	series.TransactionType = query.TransactionType
+	series.CurrencyID = query.CurrencyID
+
+	return series, nil
+}
+
+// buildQuery checks access to the workspace and validates the filters of an analytics request
+func (s *analyticsService) buildQuery(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, transactionType int, params *entities.TransactionListParams) (*entities.AnalyticsQuery, *entities.Workspace, error) {
+	if transactionType == 0 {
+		transactionType = entities.TransactionTypeExpense
+	}
+	if transactionType != entities.TransactionTypeIncome && transactionType != entities.TransactionTypeExpense {
+		return nil, nil, errors.New("invalid transaction type")
+	}
+	if params.StartDate != nil && params.EndDate != nil && params.StartDate.After(*params.EndDate) {
+		return nil, nil, errors.New("start date must be before end date")
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &entities.AnalyticsQuery{
+		WorkspaceID:     workspaceID,
+		TransactionType: transactionType,
+		CurrencyID:      workspace.CurrencyID,
+		WeekStartDay:    workspace.Settings.WeekStartDay,
+		MonthStartDay:   workspace.Settings.FiscalMonthStartDay,
+		Filters:         params,
+	}, workspace, nil
+}
+
+// buildBreakdownItems turns flat breakdown rows into items with labels and percentages of the total
+func buildBreakdownItems(groupBy string, rows []*entities.AnalyticsBreakdownRow, total float64) []*entities.AnalyticsBreakdownItem {
+	items := make([]*entities.AnalyticsBreakdownItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, &entities.AnalyticsBreakdownItem{
+			Key:              row.Key,
+			Label:            breakdownLabel(groupBy, row),
+			Amount:           roundAmount(row.Amount),
+			TransactionCount: row.TransactionCount,
+			UnconvertedCount: row.UnconvertedCount,
+			Percentage:       percentageOf(row.Amount, total),
+		})
+	}
+	return items
+}
+
+// buildCategoryBreakdown rolls the totals per user category up the system category hierarchy.
+// Top level categories are returned with their sub categories and user categories as children,
+// user categories without a system category are returned at the top level.
+func buildCategoryBreakdown(rows []*entities.AnalyticsBreakdownRow, total float64) []*entities.AnalyticsBreakdownItem {
+	var roots []*entities.AnalyticsBreakdownItem
+	nodes := make(map[string]*entities.AnalyticsBreakdownItem)
+
+	for _, row := range rows {
+		siblings := &roots
+		for i, categoryID := range row.CategoryPathIDs {
+			key := "category:" + categoryID
+			node, ok := nodes[key]
+			if !ok {
+				node = &entities.AnalyticsBreakdownItem{Key: key, Label: row.CategoryPathNames[i]}
+				nodes[key] = node
+				*siblings = append(*siblings, node)
+			}
+			node.Amount += row.Amount
+			node.TransactionCount += row.TransactionCount
+			node.UnconvertedCount += row.UnconvertedCount
+			siblings = &node.Children
+		}
+
+		*siblings = append(*siblings, &entities.AnalyticsBreakdownItem{
+			Key:              row.Key,
+			Label:            breakdownLabel(entities.AnalyticsGroupByCategory, row),
+			Amount:           row.Amount,
+			TransactionCount: row.TransactionCount,
+			UnconvertedCount: row.UnconvertedCount,
+		})
+	}
+
+	finalizeBreakdownItems(roots, total)
+	return roots
+}
+
+// finalizeBreakdownItems rounds amounts, sets percentages and sorts every level by amount, largest first
+func finalizeBreakdownItems(items []*entities.AnalyticsBreakdownItem, total float64) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Amount > items[j].Amount
+	})
+	for _, item := range items {
+		item.Percentage = percentageOf(item.Amount, total)
+		item.Amount = roundAmount(item.Amount)
+		finalizeBreakdownItems(item.Children, total)
+	}
+}
+
+// breakdownLabel returns the display label of a breakdown row, naming the group of transactions without a value
+func breakdownLabel(groupBy string, row *entities.AnalyticsBreakdownRow) string {
+	if groupBy == entities.AnalyticsGroupByPaymentMethod {
+		if paymentMethod, err := strconv.Atoi(row.Key); err == nil {
+			if label, ok := paymentMethodLabels[paymentMethod]; ok {
+				return label
+			}
+		}
+		return "Unknown"
+	}
+
+	if row.Key != "" && row.Label != "" {
+		return row.Label
+	}
+
+	switch groupBy {
+	case entities.AnalyticsGroupByCategory:
+		return "Uncategorized"
+	case entities.AnalyticsGroupByAccount:
+		return "No account"
+	case entities.AnalyticsGroupByTag:
+		return "Untagged"
+	default:
+		return "Unknown"
+	}
+}
+
+// buildAnalyticsTimeSeries fills every period between startDate and endDate, including periods without
+// transactions, and pairs each period with the period at the same position in the previous series
+func buildAnalyticsTimeSeries(interval string, calendar analyticsCalendar, startDate, endDate time.Time, current, previous []*entities.AnalyticsTimeSeriesPoint) *entities.AnalyticsTimeSeries {
+	periods := analyticsPeriods(interval, startDate, endDate, calendar)
+	shift := -len(periods)
+
+	currentByPeriod := make(map[time.Time]*entities.AnalyticsTimeSeriesPoint, len(current))
+	for _, point := range current {
+		currentByPeriod[point.PeriodStart] = point
+	}
+	previousByPeriod := make(map[time.Time]*entities.AnalyticsTimeSeriesPoint, len(previous))
+	for _, point := range previous {
+		previousByPeriod[point.PeriodStart] = point
+	}
+
+	series := &entities.AnalyticsTimeSeries{
+		Interval:          interval,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		PreviousStartDate: addAnalyticsPeriods(interval, startDate, shift),
+		PreviousEndDate:   addAnalyticsPeriods(interval, endDate, shift),
+		Points:            make([]*entities.AnalyticsTimeSeriesPoint, 0, len(periods)),
+	}
+
+	for _, period := range periods {
+		point := &entities.AnalyticsTimeSeriesPoint{
+			PeriodStart:         period,
+			PreviousPeriodStart: addAnalyticsPeriods(interval, period, shift),
+		}
+		if found, ok := currentByPeriod[period]; ok {
+			point.Amount = roundAmount(found.Amount)
+			point.TransactionCount = found.TransactionCount
+			point.UnconvertedCount = found.UnconvertedCount
+		}
+		if found, ok := previousByPeriod[point.PreviousPeriodStart]; ok {
+			point.PreviousAmount = roundAmount(found.Amount)
+			point.PreviousTransactionCount = found.TransactionCount
+			point.PreviousUnconvertedCount = found.UnconvertedCount
+		}
+		point.Change = roundAmount(point.Amount - point.PreviousAmount)
+		point.ChangePercentage = changePercentage(point.Amount, point.PreviousAmount)
+
+		series.TotalAmount += point.Amount
+		series.PreviousTotalAmount += point.PreviousAmount
+		series.UnconvertedCount += point.UnconvertedCount
+		series.PreviousUnconvertedCount += point.PreviousUnconvertedCount
+		series.Points = append(series.Points, point)
+	}
+
+	series.TotalAmount = roundAmount(series.TotalAmount)
+	series.PreviousTotalAmount = roundAmount(series.PreviousTotalAmount)
+	series.ChangePercentage = changePercentage(series.TotalAmount, series.PreviousTotalAmount)
+
+	return series
+}
+
+// analyticsCalendar tells on which day weeks and months start
+type analyticsCalendar struct {
+	weekStartDay  int // 0 (Sunday) to 6 (Saturday)
+	monthStartDay int // 1 to 28
+}
+
+// defaultAnalyticsCalendar has weeks starting on Monday and calendar months
+var defaultAnalyticsCalendar = analyticsCalendar{
+	weekStartDay:  entities.DefaultWeekStartDay,
+	monthStartDay: entities.DefaultFiscalMonthStartDay,
+}
+
+// workspaceCalendar returns the calendar set in the workspace settings
+func workspaceCalendar(settings entities.WorkspaceSettings) analyticsCalendar {
+	return analyticsCalendar{
+		weekStartDay:  settings.WeekStartDay,
+		monthStartDay: settings.FiscalMonthStartDay,
+	}
+}
+
+// analyticsPeriods returns the start of every period between startDate and endDate
+func analyticsPeriods(interval string, startDate, endDate time.Time, calendar analyticsCalendar) []time.Time {
+	var periods []time.Time
+	last := truncateAnalyticsPeriod(interval, endDate, calendar)
+	for period := truncateAnalyticsPeriod(interval, startDate, calendar); !period.After(last); period = addAnalyticsPeriods(interval, period, 1) {
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// truncateAnalyticsPeriod returns the start of the period containing date, weeks and months start as set in the calendar
+func truncateAnalyticsPeriod(interval string, date time.Time, calendar analyticsCalendar) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case entities.AnalyticsIntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) - calendar.weekStartDay + 7) % 7))
+	case entities.AnalyticsIntervalMonth:
+		month := day.Month()
+		if day.Day() < calendar.monthStartDay {
+			month--
+		}
+		return time.Date(day.Year(), month, calendar.monthStartDay, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// addAnalyticsPeriods moves date by n days, weeks or months
+func addAnalyticsPeriods(interval string, date time.Time, n int) time.Time {
+	switch interval {
+	case entities.AnalyticsIntervalWeek:
+		return date.AddDate(0, 0, 7*n)
+	case entities.AnalyticsIntervalMonth:
+		return date.AddDate(0, n, 0)
+	default:
+		return date.AddDate(0, 0, n)
+	}
+}
+
+// defaultSeriesStart returns the start of the default range ending at endDate:
+// the last 30 days, the last 12 weeks or the last 12 months
+func defaultSeriesStart(interval string, endDate time.Time, calendar analyticsCalendar) time.Time {
+	switch interval {
+	case entities.AnalyticsIntervalWeek:
+		return truncateAnalyticsPeriod(interval, endDate, calendar).AddDate(0, 0, -7*11)
+	case entities.AnalyticsIntervalMonth:
+		return truncateAnalyticsPeriod(interval, endDate, calendar).AddDate(0, -11, 0)
+	default:
+		return truncateAnalyticsPeriod(interval, endDate, calendar).AddDate(0, 0, -29)
+	}
+}
+
+// today returns the current date in a timezone, falling back to the server timezone
+func today(timezone string) time.Time {
+	now := time.Now()
+	if location, err := time.LoadLocation(timezone); err == nil {
+		now = now.In(location)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// percentageOf returns amount as a percentage of total, rounded to 2 decimal places
+func percentageOf(amount, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundAmount(amount / total * 100)
+}
+
+// changePercentage returns the change from previous to current in percent, or nil when there is nothing to compare with
+func changePercentage(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := roundAmount((current - previous) / previous * 100)
+	return &change
+}
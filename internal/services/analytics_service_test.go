@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestBuildCategoryBreakdown(t *testing.T) {
+	t.Run("given user categories under nested system categories, when building the breakdown, then totals roll up to the parents", func(t *testing.T) {
+		rows := []*entities.AnalyticsBreakdownRow{
+			{Key: "uc-coffee", Label: "Kopi", CategoryPathIDs: []string{"food", "drinks"}, CategoryPathNames: []string{"Food", "Drinks"}, Amount: 300000, TransactionCount: 6},
+			{Key: "uc-lunch", Label: "Makan Siang", CategoryPathIDs: []string{"food"}, CategoryPathNames: []string{"Food"}, Amount: 500000, TransactionCount: 10},
+			{Key: "uc-fuel", Label: "Bensin", CategoryPathIDs: []string{"transport"}, CategoryPathNames: []string{"Transport"}, Amount: 200000, TransactionCount: 4},
+		}
+
+		items := buildCategoryBreakdown(rows, 1000000)
+
+		assert.Len(t, items, 2)
+		assert.Equal(t, "category:food", items[0].Key)
+		assert.Equal(t, 800000.0, items[0].Amount)
+		assert.Equal(t, 16, items[0].TransactionCount)
+		assert.Equal(t, 80.0, items[0].Percentage)
+		assert.Equal(t, "uc-lunch", items[0].Children[0].Key)
+		assert.Equal(t, "category:drinks", items[0].Children[1].Key)
+		assert.Equal(t, "Kopi", items[0].Children[1].Children[0].Label)
+		assert.Equal(t, 30.0, items[0].Children[1].Children[0].Percentage)
+	})
+
+	t.Run("given uncategorized transactions, when building the breakdown, then they are a top level item", func(t *testing.T) {
+		rows := []*entities.AnalyticsBreakdownRow{
+			{Key: "", Amount: 50000, TransactionCount: 1},
+		}
+
+		items := buildCategoryBreakdown(rows, 50000)
+
+		assert.Len(t, items, 1)
+		assert.Equal(t, "Uncategorized", items[0].Label)
+		assert.Equal(t, 100.0, items[0].Percentage)
+		assert.Empty(t, items[0].Children)
+	})
+}
+
+func TestBuildBreakdownItems(t *testing.T) {
+	t.Run("given payment method rows, when building the items, then they are labelled", func(t *testing.T) {
+		rows := []*entities.AnalyticsBreakdownRow{
+			{Key: "3", Amount: 75000, TransactionCount: 3},
+			{Key: "", Amount: 25000, TransactionCount: 1},
+		}
+
+		items := buildBreakdownItems(entities.AnalyticsGroupByPaymentMethod, rows, 100000)
+
+		assert.Equal(t, "Cash", items[0].Label)
+		assert.Equal(t, 75.0, items[0].Percentage)
+		assert.Equal(t, "Unknown", items[1].Label)
+	})
+}
+
+func TestBuildAnalyticsTimeSeries(t *testing.T) {
+	t.Run("given sparse monthly totals, when building the series, then every month is filled and compared with the previous period", func(t *testing.T) {
+		start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+		current := []*entities.AnalyticsTimeSeriesPoint{
+			{PeriodStart: start, Amount: 1200000, TransactionCount: 12},
+			{PeriodStart: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), Amount: 900000, TransactionCount: 9, UnconvertedCount: 1},
+		}
+		previous := []*entities.AnalyticsTimeSeriesPoint{
+			{PeriodStart: time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), Amount: 1000000, TransactionCount: 10, UnconvertedCount: 2},
+		}
+
+		series := buildAnalyticsTimeSeries(entities.AnalyticsIntervalMonth, defaultAnalyticsCalendar, start, end, current, previous)
+
+		assert.Len(t, series.Points, 3)
+		assert.Equal(t, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), series.PreviousStartDate)
+		assert.Equal(t, 0.0, series.Points[1].Amount)
+		assert.Equal(t, 1000000.0, series.Points[0].PreviousAmount)
+		assert.Equal(t, 200000.0, series.Points[0].Change)
+		assert.Equal(t, 20.0, *series.Points[0].ChangePercentage)
+		assert.Nil(t, series.Points[2].ChangePercentage)
+		assert.Equal(t, 2100000.0, series.TotalAmount)
+		assert.Equal(t, 110.0, *series.ChangePercentage)
+		assert.Equal(t, 1, series.Points[2].UnconvertedCount)
+		assert.Equal(t, 2, series.Points[0].PreviousUnconvertedCount)
+		assert.Equal(t, 1, series.UnconvertedCount)
+		assert.Equal(t, 2, series.PreviousUnconvertedCount)
+	})
+
+	t.Run("given a range starting mid week, when building a weekly series, then periods start on Monday", func(t *testing.T) {
+		start := time.Date(2025, time.June, 4, 0, 0, 0, 0, time.UTC) // Wednesday
+		end := time.Date(2025, time.June, 15, 0, 0, 0, 0, time.UTC)  // Sunday
+
+		series := buildAnalyticsTimeSeries(entities.AnalyticsIntervalWeek, defaultAnalyticsCalendar, start, end, nil, nil)
+
+		assert.Len(t, series.Points, 2)
+		assert.Equal(t, time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC), series.Points[0].PeriodStart)
+		assert.Equal(t, time.Date(2025, time.May, 19, 0, 0, 0, 0, time.UTC), series.Points[0].PreviousPeriodStart)
+	})
+}
+
+func TestTruncateAnalyticsPeriod(t *testing.T) {
+	calendar := analyticsCalendar{weekStartDay: 0, monthStartDay: 25}
+
+	t.Run("given weeks starting on Sunday, when a Saturday is truncated, then the previous Sunday is returned", func(t *testing.T) {
+		saturday := time.Date(2025, time.June, 14, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2025, time.June, 8, 0, 0, 0, 0, time.UTC), truncateAnalyticsPeriod(entities.AnalyticsIntervalWeek, saturday, calendar))
+	})
+
+	t.Run("given fiscal months starting on the 25th, when a date before the 25th is truncated, then the 25th of the previous month is returned", func(t *testing.T) {
+		date := time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC), truncateAnalyticsPeriod(entities.AnalyticsIntervalMonth, date, calendar))
+	})
+
+	t.Run("given fiscal months starting on the 25th, when the 25th is truncated, then it starts its own period", func(t *testing.T) {
+		date := time.Date(2025, time.January, 25, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, date, truncateAnalyticsPeriod(entities.AnalyticsIntervalMonth, date, calendar))
+	})
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+// Detection thresholds. They are plain statistics on the user's own history so results are reproducible.
+const (
+	anomalyLookbackDays = 90
+
+	// Category outliers use the modified z-score of Iglewicz and Hoaglin on the median and median absolute deviation,
+	// which a few earlier outliers barely move
+	outlierMinSamples  = 8
+	outlierZScore      = 3.5
+	outlierMinMADRatio = 0.1 // keeps the deviation from collapsing to zero when most amounts are identical
+
+	newMerchantMinSamples = 10
+	newMerchantPercentile = 90
+
+	unusualHourMinSamples = 30
+	unusualHourMaxShare   = 0.02 // share of past expenses recorded within an hour of the candidate's hour
+
+	spikeWeeks         = 8
+	spikeMinActiveWeek = 4
+	spikeStdDevs       = 3.0
+	spikeMinRatio      = 2.0
+)
+
+// detectSpendingAnomalies compares an expense against the baseline of its user and returns what stands out.
+// Transfers and income are never anomalies.
+func detectSpendingAnomalies(candidate *entities.AnomalyCandidate, baseline *entities.AnomalyBaseline) []*entities.SpendingAnomaly {
+	if candidate.TransactionType != entities.TransactionTypeExpense || candidate.TransferAccountID != nil || candidate.Amount <= 0 {
+		return nil
+	}
+
+	anomalies := make([]*entities.SpendingAnomaly, 0)
+	add := func(anomalyType int, score, expected, actual float64) {
+		anomalies = append(anomalies, &entities.SpendingAnomaly{
+			TransactionID: candidate.TransactionID,
+			WorkspaceID:   candidate.WorkspaceID,
+			UserID:        candidate.UserID,
+			AnomalyType:   anomalyType,
+			Score:         roundAmount(score),
+			ExpectedValue: roundAmount(expected),
+			ActualValue:   roundAmount(actual),
+			Status:        entities.AnomalyStatusOpen,
+		})
+	}
+
+	if candidate.CategoryID != nil {
+		if score, median, ok := categoryOutlierScore(candidate.Amount, baseline.CategoryAmounts); ok {
+			add(entities.AnomalyTypeCategoryOutlier, score, median, candidate.Amount)
+		}
+
+		if ratio, mean, total, ok := categorySpikeRatio(candidate.Amount, baseline.CategoryWeekSpentSoFar, baseline.CategoryWeeklyTotals); ok {
+			add(entities.AnomalyTypeCategorySpike, ratio, mean, total)
+		}
+	}
+
+	if (candidate.MerchantID != nil || candidate.MerchantName != nil) && baseline.MerchantExpenseCount == 0 &&
+		len(baseline.ExpenseAmounts) >= newMerchantMinSamples {
+		typical := percentile(baseline.ExpenseAmounts, newMerchantPercentile)
+		if typical > 0 && candidate.Amount >= typical {
+			add(entities.AnomalyTypeNewMerchant, candidate.Amount/typical, typical, candidate.Amount)
+		}
+	}
+
+	if share, usualHour, ok := unusualHourShare(candidate.Hour, baseline.HourCounts); ok {
+		add(entities.AnomalyTypeUnusualHour, share*100, float64(usualHour), float64(candidate.Hour))
+	}
+
+	return anomalies
+}
+
+// categoryOutlierScore returns the modified z-score of amount against the past amounts of its category
+func categoryOutlierScore(amount float64, history []float64) (float64, float64, bool) {
+	if len(history) < outlierMinSamples {
+		return 0, 0, false
+	}
+
+	median := percentile(history, 50)
+	if median <= 0 || amount <= median {
+		return 0, median, false
+	}
+
+	deviations := make([]float64, len(history))
+	for i, value := range history {
+		deviations[i] = math.Abs(value - median)
+	}
+	mad := math.Max(percentile(deviations, 50), median*outlierMinMADRatio)
+
+	score := 0.6745 * (amount - median) / mad
+	return score, median, score > outlierZScore
+}
+
+// categorySpikeRatio checks whether the candidate pushes the spending of its category in the current week
+// far above the weekly spending of the previous weeks. Only the expense crossing the threshold is flagged.
+func categorySpikeRatio(amount, spentSoFar float64, weeklyTotals []float64) (float64, float64, float64, bool) {
+	weeks := make([]float64, spikeWeeks)
+	copy(weeks, weeklyTotals)
+
+	activeWeeks := 0
+	for _, total := range weeks {
+		if total > 0 {
+			activeWeeks++
+		}
+	}
+	if activeWeeks < spikeMinActiveWeek {
+		return 0, 0, 0, false
+	}
+
+	mean, stdDev := meanAndStdDev(weeks)
+	threshold := math.Max(mean+spikeStdDevs*stdDev, mean*spikeMinRatio)
+	total := spentSoFar + amount
+	if spentSoFar > threshold || total <= threshold {
+		return 0, mean, total, false
+	}
+
+	return total / mean, mean, total, true
+}
+
+// unusualHourShare returns the share of past expenses recorded within an hour of hour, and the most common hour
+func unusualHourShare(hour int, hourCounts [24]int) (float64, int, bool) {
+	total := 0
+	usualHour := 0
+	for h, count := range hourCounts {
+		total += count
+		if count > hourCounts[usualHour] {
+			usualHour = h
+		}
+	}
+	if total < unusualHourMinSamples || hour < 0 || hour > 23 {
+		return 0, usualHour, false
+	}
+
+	window := hourCounts[(hour+23)%24] + hourCounts[hour] + hourCounts[(hour+1)%24]
+	share := float64(window) / float64(total)
+	return share, usualHour, share < unusualHourMaxShare
+}
+
+// percentile returns the p-th percentile of values by linear interpolation between the closest ranks
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// meanAndStdDev returns the mean and population standard deviation of values
+func meanAndStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func newAnomalyCandidate(amount float64, hour int) *entities.AnomalyCandidate {
+	categoryID := uuid.New()
+	merchantName := "Kopi Kenangan"
+	return &entities.AnomalyCandidate{
+		TransactionID:   uuid.New(),
+		WorkspaceID:     uuid.New(),
+		UserID:          uuid.New(),
+		TransactionType: entities.TransactionTypeExpense,
+		CategoryID:      &categoryID,
+		MerchantName:    &merchantName,
+		Amount:          amount,
+		Hour:            hour,
+	}
+}
+
+func daytimeHourCounts() [24]int {
+	var counts [24]int
+	for hour := 8; hour <= 21; hour++ {
+		counts[hour] = 5
+	}
+	return counts
+}
+
+func anomalyTypes(anomalies []*entities.SpendingAnomaly) []int {
+	types := make([]int, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		types = append(types, anomaly.AnomalyType)
+	}
+	return types
+}
+
+func TestDetectSpendingAnomalies(t *testing.T) {
+	baseline := &entities.AnomalyBaseline{
+		CategoryAmounts:        []float64{45000, 50000, 52000, 48000, 55000, 47000, 51000, 49000, 53000, 50000},
+		ExpenseAmounts:         []float64{20000, 35000, 50000, 75000, 100000, 120000, 150000, 200000, 250000, 300000, 500000},
+		MerchantExpenseCount:   12,
+		HourCounts:             daytimeHourCounts(),
+		CategoryWeeklyTotals:   []float64{200000, 180000, 220000, 210000, 190000, 205000, 195000, 200000},
+		CategoryWeekSpentSoFar: 100000,
+	}
+
+	t.Run("given a usual expense, when detecting anomalies, then nothing is flagged", func(t *testing.T) {
+		anomalies := detectSpendingAnomalies(newAnomalyCandidate(52000, 12), baseline)
+
+		assert.Empty(t, anomalies)
+	})
+
+	t.Run("given an amount far above the category median, when detecting anomalies, then it is a category outlier", func(t *testing.T) {
+		anomalies := detectSpendingAnomalies(newAnomalyCandidate(90000, 12), baseline)
+
+		assert.Equal(t, []int{entities.AnomalyTypeCategoryOutlier}, anomalyTypes(anomalies))
+		assert.Equal(t, 50000.0, anomalies[0].ExpectedValue)
+		assert.Equal(t, 90000.0, anomalies[0].ActualValue)
+		assert.Greater(t, anomalies[0].Score, outlierZScore)
+		assert.Equal(t, entities.AnomalyStatusOpen, anomalies[0].Status)
+	})
+
+	t.Run("given an expense recorded at night, when detecting anomalies, then it is an unusual hour", func(t *testing.T) {
+		anomalies := detectSpendingAnomalies(newAnomalyCandidate(50000, 3), baseline)
+
+		assert.Equal(t, []int{entities.AnomalyTypeUnusualHour}, anomalyTypes(anomalies))
+		assert.Equal(t, 0.0, anomalies[0].Score)
+		assert.Equal(t, 8.0, anomalies[0].ExpectedValue)
+		assert.Equal(t, 3.0, anomalies[0].ActualValue)
+	})
+
+	t.Run("given a first expense at a merchant above the usual amounts, when detecting anomalies, then it is a new merchant anomaly", func(t *testing.T) {
+		candidate := newAnomalyCandidate(600000, 12)
+		candidate.CategoryID = nil
+
+		anomalies := detectSpendingAnomalies(candidate, &entities.AnomalyBaseline{
+			ExpenseAmounts: baseline.ExpenseAmounts,
+			HourCounts:     baseline.HourCounts,
+		})
+
+		assert.Equal(t, []int{entities.AnomalyTypeNewMerchant}, anomalyTypes(anomalies))
+		assert.Equal(t, 300000.0, anomalies[0].ExpectedValue)
+		assert.Equal(t, 2.0, anomalies[0].Score)
+	})
+
+	t.Run("given an expense doubling the weekly category spending, when detecting anomalies, then it is a category spike once", func(t *testing.T) {
+		spikeBaseline := *baseline
+		spikeBaseline.CategoryAmounts = nil
+		spikeBaseline.CategoryWeekSpentSoFar = 380000
+
+		anomalies := detectSpendingAnomalies(newAnomalyCandidate(50000, 12), &spikeBaseline)
+
+		assert.Equal(t, []int{entities.AnomalyTypeCategorySpike}, anomalyTypes(anomalies))
+		assert.Equal(t, 200000.0, anomalies[0].ExpectedValue)
+		assert.Equal(t, 430000.0, anomalies[0].ActualValue)
+
+		spikeBaseline.CategoryWeekSpentSoFar = 430000
+		assert.Empty(t, detectSpendingAnomalies(newAnomalyCandidate(50000, 12), &spikeBaseline))
+	})
+
+	t.Run("given too little history, when detecting anomalies, then nothing is flagged", func(t *testing.T) {
+		anomalies := detectSpendingAnomalies(newAnomalyCandidate(5000000, 3), &entities.AnomalyBaseline{
+			CategoryAmounts: []float64{50000, 52000},
+			ExpenseAmounts:  []float64{50000, 52000},
+		})
+
+		assert.Empty(t, anomalies)
+	})
+
+	t.Run("given a transfer, when detecting anomalies, then it is skipped", func(t *testing.T) {
+		candidate := newAnomalyCandidate(5000000, 3)
+		accountID := uuid.New()
+		candidate.TransferAccountID = &accountID
+
+		assert.Empty(t, detectSpendingAnomalies(candidate, baseline))
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	t.Run("given unsorted values, when taking percentiles, then ranks are interpolated", func(t *testing.T) {
+		values := []float64{40, 10, 30, 20}
+
+		assert.Equal(t, 25.0, percentile(values, 50))
+		assert.Equal(t, 37.0, percentile(values, 90))
+		assert.Equal(t, []float64{40, 10, 30, 20}, values)
+	})
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
@@ -14,21 +15,39 @@ import (
 type (
 	BudgetService interface {
 		CreateBudget(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, input *entities.CreateBudgetRequest) (*entities.Budget, error)
-		UpdateBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, input *entities.UpdateBudgetRequest) (*entities.Budget, error)
-		DeleteBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) error
-		GetAllBudgets(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error)
-		GetBudgetByID(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) (*entities.BudgetSimple, error)
+		UpdateBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID, input *entities.UpdateBudgetRequest) (*entities.Budget, error)
+		DeleteBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID) error
+		GetAllBudgets(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error)
+		GetBudgetByID(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID) (*entities.BudgetSimple, error)
+		GetBudgetTransactions(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID, limit, offset int) ([]*entities.BudgetTransaction, error)
 	}
 
 	budgetService struct {
-		budgetRepo repositories.BudgetRepository
+		budgetRepo   repositories.BudgetRepository
+		categoryRepo repositories.CategoryRepository
+		tagRepo      repositories.UserTagsRepository
+		accountRepo  repositories.AccountRepository
+		authorizer   WorkspaceAuthorizer
+		events       DomainEventRecorder
 	}
 )
 
 // NewBudgetService creates a new budget service
-func NewBudgetService(budgetRepo repositories.BudgetRepository) BudgetService {
+func NewBudgetService(
+	budgetRepo repositories.BudgetRepository,
+	categoryRepo repositories.CategoryRepository,
+	tagRepo repositories.UserTagsRepository,
+	accountRepo repositories.AccountRepository,
+	authorizer WorkspaceAuthorizer,
+	events DomainEventRecorder,
+) BudgetService {
 	return &budgetService{
-		budgetRepo: budgetRepo,
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		accountRepo:  accountRepo,
+		authorizer:   authorizer,
+		events:       events,
 	}
 }
 
@@ -53,14 +72,26 @@ func (s *budgetService) CreateBudget(ctx context.Context, workspaceID uuid.UUID,
 	if input.PeriodEnd.Before(input.PeriodStart) {
 		return nil, errors.New("period end must be after period start")
 	}
-	if input.UserCategoryID == uuid.Nil {
-		return nil, errors.New("user category ID is required")
+	scope, err := normalizeBudgetScope(input.UserCategoryID, input.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if input.UserCategoryID == uuid.Nil && len(scope.CategoryIDs) == 1 {
+		input.UserCategoryID = scope.CategoryIDs[0]
+	}
+
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageBudget); err != nil {
+		return nil, err
+	}
+	if err := s.validateBudgetScopeOwners(ctx, workspaceID, scope); err != nil {
+		return nil, err
 	}
 
 	budget := &entities.Budget{
 		BudgetID:       uuid.New(),
 		WorkspaceID:    workspaceID,
 		UserCategoryID: input.UserCategoryID,
+		Scope:          scope,
 		Name:           input.Name,
 		BudgetedAmount: input.BudgetedAmount,
 		PeriodType:     input.PeriodType,
@@ -77,12 +108,20 @@ func (s *budgetService) CreateBudget(ctx context.Context, workspaceID uuid.UUID,
 		return nil, err
 	}
 
+	// Count the expenses already recorded within the scope and period
+	createdBudget.SpentAmount, err = s.budgetRepo.RefreshSpentAmount(ctx, createdBudget.BudgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordBudgetEvent(ctx, userID, entities.ActivityActionCreated, &createdBudget, nil, &createdBudget)
+
 	// Return the budget with data populated from the database
 	return &createdBudget, nil
 }
 
 // UpdateBudget updates an existing budget
-func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, input *entities.UpdateBudgetRequest) (*entities.Budget, error) {
+func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID, input *entities.UpdateBudgetRequest) (*entities.Budget, error) {
 	// Validate required fields
 	if input.Name == "" {
 		return nil, errors.New("budget name is required")
@@ -102,8 +141,17 @@ func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, wo
 	if input.PeriodEnd.Before(input.PeriodStart) {
 		return nil, errors.New("period end must be after period start")
 	}
-	if input.UserCategoryID == uuid.Nil {
-		return nil, errors.New("user category ID is required")
+	scope, err := normalizeBudgetScope(input.UserCategoryID, input.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if input.UserCategoryID == uuid.Nil && len(scope.CategoryIDs) == 1 {
+		input.UserCategoryID = scope.CategoryIDs[0]
+	}
+
+	// Members may edit existing budgets
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionEditBudget); err != nil {
+		return nil, err
 	}
 
 	// Check if budget exists and belongs to workspace
@@ -117,6 +165,11 @@ func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, wo
 	if existingBudget.WorkspaceID != workspaceID {
 		return nil, errorsutil.New(404, "budget not found")
 	}
+	if err := s.validateBudgetScopeOwners(ctx, workspaceID, scope); err != nil {
+		return nil, err
+	}
+
+	previousBudget := *existingBudget
 
 	// Update fields
 	existingBudget.UserCategoryID = input.UserCategoryID
@@ -125,7 +178,7 @@ func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, wo
 	existingBudget.PeriodType = input.PeriodType
 	existingBudget.PeriodStart = input.PeriodStart
 	existingBudget.PeriodEnd = input.PeriodEnd
-	existingBudget.SpentAmount = input.SpentAmount
+	existingBudget.Scope = scope
 	existingBudget.IsActive = input.IsActive
 
 	// Update the budget - the repository will populate the struct with the actual data from DB
@@ -134,12 +187,24 @@ func (s *budgetService) UpdateBudget(ctx context.Context, budgetID uuid.UUID, wo
 		return nil, err
 	}
 
+	// The scope or period may have changed, so recalculate what has been spent
+	updatedBudget.SpentAmount, err = s.budgetRepo.RefreshSpentAmount(ctx, updatedBudget.BudgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordBudgetEvent(ctx, userID, entities.ActivityActionUpdated, &updatedBudget, &previousBudget, &updatedBudget)
+
 	// Return the budget with data populated from the database
 	return &updatedBudget, nil
 }
 
 // DeleteBudget deletes a budget
-func (s *budgetService) DeleteBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) error {
+func (s *budgetService) DeleteBudget(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageBudget); err != nil {
+		return err
+	}
+
 	// Check if budget exists and belongs to workspace
 	existingBudget, err := s.budgetRepo.FindByID(ctx, budgetID)
 	if err != nil {
@@ -152,16 +217,27 @@ func (s *budgetService) DeleteBudget(ctx context.Context, budgetID uuid.UUID, wo
 		return errorsutil.New(404, "budget not found")
 	}
 
-	return s.budgetRepo.Delete(ctx, budgetID)
+	if err := s.budgetRepo.Delete(ctx, budgetID); err != nil {
+		return err
+	}
+
+	s.recordBudgetEvent(ctx, userID, entities.ActivityActionDeleted, existingBudget, existingBudget, nil)
+	return nil
 }
 
 // GetAllBudgets returns all budgets for a workspace with pagination
-func (s *budgetService) GetAllBudgets(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error) {
+func (s *budgetService) GetAllBudgets(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, limit, offset int) ([]*entities.BudgetSimple, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
 	return s.budgetRepo.FindByWorkspace(ctx, workspaceID, limit, offset)
 }
 
 // GetBudgetByID returns a budget by ID within a workspace
-func (s *budgetService) GetBudgetByID(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID) (*entities.BudgetSimple, error) {
+func (s *budgetService) GetBudgetByID(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID) (*entities.BudgetSimple, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
 	budget, err := s.budgetRepo.FindByIDWithWorkspace(ctx, budgetID, workspaceID)
 	if err != nil {
 		return nil, err
@@ -171,3 +247,137 @@ func (s *budgetService) GetBudgetByID(ctx context.Context, budgetID uuid.UUID, w
 	}
 	return budget, nil
 }
+
+// GetBudgetTransactions returns the transactions that contributed to a budget's spent amount
+func (s *budgetService) GetBudgetTransactions(ctx context.Context, budgetID uuid.UUID, workspaceID uuid.UUID, userID uuid.UUID, limit, offset int) ([]*entities.BudgetTransaction, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+	existingBudget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if existingBudget == nil || existingBudget.WorkspaceID != workspaceID {
+		return nil, errorsutil.New(404, "budget not found")
+	}
+
+	return s.budgetRepo.FindContributingTransactions(ctx, budgetID, limit, offset)
+}
+
+// normalizeBudgetScope folds the legacy single user category into the scope and validates it
+func normalizeBudgetScope(userCategoryID uuid.UUID, scope entities.BudgetScope) (entities.BudgetScope, error) {
+	if userCategoryID != uuid.Nil {
+		found := false
+		for _, categoryID := range scope.CategoryIDs {
+			if categoryID == userCategoryID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			scope.CategoryIDs = append(scope.CategoryIDs, userCategoryID)
+		}
+	}
+
+	merchants := make([]string, 0, len(scope.Merchants))
+	for _, merchant := range scope.Merchants {
+		if merchant = strings.TrimSpace(merchant); merchant != "" {
+			merchants = append(merchants, merchant)
+		}
+	}
+	scope.Merchants = merchants
+
+	if scope.TotalSpend && scope.HasFilters() {
+		return entities.BudgetScope{}, errors.New("total spend budget cannot have scope filters")
+	}
+	if !scope.TotalSpend && !scope.HasFilters() {
+		return entities.BudgetScope{}, errors.New("budget scope is required")
+	}
+
+	return scope, nil
+}
+
+// validateBudgetScopeOwners checks that the categories, tags and accounts of a scope belong to members of the
+// budget's workspace. Records of other users are reported as not found, like records that do not exist.
+func (s *budgetService) validateBudgetScopeOwners(ctx context.Context, workspaceID uuid.UUID, scope entities.BudgetScope) error {
+	members := make(map[uuid.UUID]bool)
+	isMember := func(userID uuid.UUID) (bool, error) {
+		if member, ok := members[userID]; ok {
+			return member, nil
+		}
+		membership, err := s.authorizer.GetMembership(ctx, workspaceID, userID)
+		if err != nil {
+			return false, err
+		}
+		members[userID] = membership != nil
+		return members[userID], nil
+	}
+
+	for _, categoryID := range scope.CategoryIDs {
+		category, err := s.categoryRepo.FindUserCategoryByID(ctx, categoryID)
+		if err != nil && !errors.Is(err, entities.ErrNotFound) {
+			return err
+		}
+		if category == nil {
+			return errorsutil.New(400, "budget scope category not found")
+		}
+		if member, err := isMember(category.UserID); err != nil {
+			return err
+		} else if !member {
+			return errorsutil.New(400, "budget scope category not found")
+		}
+	}
+
+	for _, tagID := range scope.TagIDs {
+		tag, err := s.tagRepo.FindByID(ctx, tagID)
+		if err != nil {
+			return err
+		}
+		if tag == nil {
+			return errorsutil.New(400, "budget scope tag not found")
+		}
+		if member, err := isMember(tag.UserID); err != nil {
+			return err
+		} else if !member {
+			return errorsutil.New(400, "budget scope tag not found")
+		}
+	}
+
+	for _, accountID := range scope.AccountIDs {
+		account, err := s.accountRepo.FindByID(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			return errorsutil.New(400, "budget scope account not found")
+		}
+		if member, err := isMember(account.UserID); err != nil {
+			return err
+		} else if !member {
+			return errorsutil.New(400, "budget scope account not found")
+		}
+	}
+
+	return nil
+}
+
+// recordBudgetEvent records a budget write in the audit log and the activity feed of its workspace
+func (s *budgetService) recordBudgetEvent(ctx context.Context, userID uuid.UUID, action string, budget *entities.Budget, oldValues, newValues *entities.Budget) {
+	event := &entities.DomainEvent{
+		WorkspaceID:  budget.WorkspaceID,
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: entities.ActivityResourceBudget,
+		ResourceID:   &budget.BudgetID,
+		ResourceName: budget.Name,
+		Amount:       &budget.BudgetedAmount,
+	}
+	// A nil *Budget in an interface is not nil, only set the values that exist
+	if oldValues != nil {
+		event.OldValues = oldValues
+	}
+	if newValues != nil {
+		event.NewValues = newValues
+	}
+	s.events.Record(ctx, event)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+func TestNormalizeBudgetScope(t *testing.T) {
+	categoryID, otherCategoryID := uuid.New(), uuid.New()
+
+	t.Run("given a legacy user category, when normalizing, then it is added to the scope once", func(t *testing.T) {
+		scope, err := normalizeBudgetScope(categoryID, entities.BudgetScope{})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{categoryID}, scope.CategoryIDs)
+
+		scope, err = normalizeBudgetScope(categoryID, entities.BudgetScope{CategoryIDs: []uuid.UUID{otherCategoryID, categoryID}})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{otherCategoryID, categoryID}, scope.CategoryIDs)
+	})
+
+	t.Run("given merchants with blanks, when normalizing, then they are trimmed and the empty ones dropped", func(t *testing.T) {
+		scope, err := normalizeBudgetScope(uuid.Nil, entities.BudgetScope{Merchants: []string{"  Grab ", "", "   "}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Grab"}, scope.Merchants)
+	})
+
+	t.Run("given only blank merchants, when normalizing, then the scope is required", func(t *testing.T) {
+		_, err := normalizeBudgetScope(uuid.Nil, entities.BudgetScope{Merchants: []string{" "}})
+		assert.EqualError(t, err, "budget scope is required")
+	})
+
+	t.Run("given total spend with filters, when normalizing, then it fails", func(t *testing.T) {
+		_, err := normalizeBudgetScope(categoryID, entities.BudgetScope{TotalSpend: true})
+		assert.EqualError(t, err, "total spend budget cannot have scope filters")
+	})
+
+	t.Run("given total spend alone, when normalizing, then it passes", func(t *testing.T) {
+		scope, err := normalizeBudgetScope(uuid.Nil, entities.BudgetScope{TotalSpend: true})
+		assert.NoError(t, err)
+		assert.True(t, scope.TotalSpend)
+	})
+}
+
+// budgetScopeCategories, budgetScopeTags and budgetScopeAccounts find the records of a test, the other methods
+// are not used by budgets
+type budgetScopeCategories struct {
+	repositories.CategoryRepository
+	categories map[uuid.UUID]*entities.UserCategory
+}
+
+func (r *budgetScopeCategories) FindUserCategoryByID(ctx context.Context, userCategoryID uuid.UUID) (*entities.UserCategory, error) {
+	if category, ok := r.categories[userCategoryID]; ok {
+		return category, nil
+	}
+	return nil, entities.ErrNotFound
+}
+
+type budgetScopeTags struct {
+	repositories.UserTagsRepository
+	tags map[uuid.UUID]*entities.UserTag
+}
+
+func (r *budgetScopeTags) FindByID(ctx context.Context, userTagID uuid.UUID) (*entities.UserTag, error) {
+	return r.tags[userTagID], nil
+}
+
+type budgetScopeAccounts struct {
+	repositories.AccountRepository
+	accounts map[uuid.UUID]*entities.Account
+}
+
+func (r *budgetScopeAccounts) FindByID(ctx context.Context, accountID uuid.UUID) (*entities.Account, error) {
+	return r.accounts[accountID], nil
+}
+
+// createdBudgets keeps the budgets created in a test
+type createdBudgets struct {
+	repositories.BudgetRepository
+	budgets []*entities.Budget
+}
+
+func (r *createdBudgets) Create(ctx context.Context, budget *entities.Budget) (entities.Budget, error) {
+	r.budgets = append(r.budgets, budget)
+	return *budget, nil
+}
+
+func (r *createdBudgets) RefreshSpentAmount(ctx context.Context, budgetID uuid.UUID) (float64, error) {
+	return 0, nil
+}
+
+func TestCreateBudgetScopeOwners(t *testing.T) {
+	ctx := context.Background()
+	member, outsider := uuid.New(), uuid.New()
+	workspace := &entities.Workspace{WorkspaceID: uuid.New()}
+
+	memberCategory := &entities.UserCategory{UserCategoryID: uuid.New(), UserID: member}
+	outsiderCategory := &entities.UserCategory{UserCategoryID: uuid.New(), UserID: outsider}
+	memberTag := &entities.UserTag{UserTagID: uuid.New(), UserID: member}
+	outsiderTag := &entities.UserTag{UserTagID: uuid.New(), UserID: outsider}
+	memberAccount := &entities.Account{AccountID: uuid.New(), UserID: member}
+	outsiderAccount := &entities.Account{AccountID: uuid.New(), UserID: outsider}
+
+	newService := func() (*createdBudgets, BudgetService) {
+		budgetRepo := &createdBudgets{}
+		return budgetRepo, NewBudgetService(budgetRepo,
+			&budgetScopeCategories{categories: map[uuid.UUID]*entities.UserCategory{
+				memberCategory.UserCategoryID:   memberCategory,
+				outsiderCategory.UserCategoryID: outsiderCategory,
+			}},
+			&budgetScopeTags{tags: map[uuid.UUID]*entities.UserTag{
+				memberTag.UserTagID:   memberTag,
+				outsiderTag.UserTagID: outsiderTag,
+			}},
+			&budgetScopeAccounts{accounts: map[uuid.UUID]*entities.Account{
+				memberAccount.AccountID:   memberAccount,
+				outsiderAccount.AccountID: outsiderAccount,
+			}},
+			&memberAuthorizer{workspace: workspace, members: map[uuid.UUID]bool{member: true}},
+			&recordedEvents{})
+	}
+	request := func(scope entities.BudgetScope) *entities.CreateBudgetRequest {
+		return &entities.CreateBudgetRequest{
+			WorkspaceID:    workspace.WorkspaceID,
+			Name:           "Makan",
+			BudgetedAmount: 1000000,
+			PeriodType:     2,
+			PeriodStart:    time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:      time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC),
+			Scope:          scope,
+		}
+	}
+
+	t.Run("given a scope of the member's records, when creating, then the budget is saved", func(t *testing.T) {
+		budgetRepo, s := newService()
+
+		_, err := s.CreateBudget(ctx, workspace.WorkspaceID, member, request(entities.BudgetScope{
+			CategoryIDs: []uuid.UUID{memberCategory.UserCategoryID},
+			TagIDs:      []uuid.UUID{memberTag.UserTagID},
+			AccountIDs:  []uuid.UUID{memberAccount.AccountID},
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, budgetRepo.budgets, 1)
+	})
+
+	t.Run("given records of a user outside the workspace, when creating, then nothing is saved", func(t *testing.T) {
+		budgetRepo, s := newService()
+
+		_, err := s.CreateBudget(ctx, workspace.WorkspaceID, member, request(entities.BudgetScope{CategoryIDs: []uuid.UUID{outsiderCategory.UserCategoryID}}))
+		assert.EqualError(t, err, "budget scope category not found")
+
+		_, err = s.CreateBudget(ctx, workspace.WorkspaceID, member, request(entities.BudgetScope{TagIDs: []uuid.UUID{outsiderTag.UserTagID}}))
+		assert.EqualError(t, err, "budget scope tag not found")
+
+		_, err = s.CreateBudget(ctx, workspace.WorkspaceID, member, request(entities.BudgetScope{AccountIDs: []uuid.UUID{outsiderAccount.AccountID}}))
+		assert.EqualError(t, err, "budget scope account not found")
+
+		assert.Empty(t, budgetRepo.budgets)
+	})
+
+	t.Run("given a category that does not exist, when creating, then it is reported like a foreign one", func(t *testing.T) {
+		_, s := newService()
+
+		_, err := s.CreateBudget(ctx, workspace.WorkspaceID, member, request(entities.BudgetScope{CategoryIDs: []uuid.UUID{uuid.New()}}))
+		assert.EqualError(t, err, "budget scope category not found")
+	})
+}
@@ -33,13 +33,15 @@ type (
 
 	categoryService struct {
 		categoryRepo repositories.CategoryRepository
+		events       DomainEventRecorder
 	}
 )
 
 // NewCategoryService creates a new category service
-func NewCategoryService(categoryRepo repositories.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepo repositories.CategoryRepository, events DomainEventRecorder) CategoryService {
 	return &categoryService{
 		categoryRepo: categoryRepo,
+		events:       events,
 	}
 }
 
@@ -121,6 +123,8 @@ func (s *categoryService) CreateUserCategory(ctx context.Context, userID uuid.UU
 		return nil, err
 	}
 
+	s.recordUserCategoryEvent(ctx, userID, entities.ActivityActionCreated, &createdUserCategory, nil, &createdUserCategory)
+
 	// Return the user category with data populated from the database
 	return &createdUserCategory, nil
 }
@@ -155,6 +159,8 @@ func (s *categoryService) UpdateUserCategory(ctx context.Context, userID uuid.UU
 		}
 	}
 
+	previousUserCategory := *existingUserCategory
+
 	// Update fields
 	existingUserCategory.Name = input.Name
 	existingUserCategory.Description = input.Description
@@ -167,6 +173,8 @@ func (s *categoryService) UpdateUserCategory(ctx context.Context, userID uuid.UU
 		return nil, err
 	}
 
+	s.recordUserCategoryEvent(ctx, userID, entities.ActivityActionUpdated, &updatedUserCategory, &previousUserCategory, &updatedUserCategory)
+
 	// Return the user category with data populated from the database
 	return &updatedUserCategory, nil
 }
@@ -194,7 +202,12 @@ func (s *categoryService) DeleteUserCategory(ctx context.Context, userID uuid.UU
 		return errorsutil.New(409, "cannot delete category with existing transactions")
 	}
 
-	return s.categoryRepo.DeleteUserCategory(ctx, userCategoryID)
+	if err := s.categoryRepo.DeleteUserCategory(ctx, userCategoryID); err != nil {
+		return err
+	}
+
+	s.recordUserCategoryEvent(ctx, userID, entities.ActivityActionDeleted, existingUserCategory, existingUserCategory, nil)
+	return nil
 }
 
 // GetUserCategoryByID returns a user category by ID (with user ownership verification)
@@ -231,3 +244,23 @@ func (s *categoryService) AddSystemCategoryToUser(ctx context.Context, userID, c
 func (s *categoryService) GetCategoriesWithTransactionCount(ctx context.Context, userID uuid.UUID) ([]map[string]interface{}, error) {
 	return s.categoryRepo.GetCategoriesWithTransactionCount(ctx, userID)
 }
+
+// recordUserCategoryEvent records a user category write in the audit log and, as categories are shared by
+// all the workspaces of their owner, in the activity feed of each of them
+func (s *categoryService) recordUserCategoryEvent(ctx context.Context, userID uuid.UUID, action string, userCategory *entities.UserCategory, oldValues, newValues *entities.UserCategory) {
+	event := &entities.DomainEvent{
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: entities.ActivityResourceCategory,
+		ResourceID:   &userCategory.UserCategoryID,
+		ResourceName: userCategory.Name,
+	}
+	// A nil *UserCategory in an interface is not nil, only set the values that exist
+	if oldValues != nil {
+		event.OldValues = oldValues
+	}
+	if newValues != nil {
+		event.NewValues = newValues
+	}
+	s.events.Record(ctx, event)
+}
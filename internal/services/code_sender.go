@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vasst-id/vasst-expense-api/config"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/httpclient"
+)
+
+const whatsAppMessagesURL = "https://graph.facebook.com/v20.0/%s/messages"
+
+//go:generate mockgen -source=code_sender.go -package=mock -destination=mock/code_sender_mock.go
+type (
+	// CodeSender delivers one-time codes to a phone number
+	CodeSender interface {
+		SendCode(ctx context.Context, channel, phoneNumber, code, codeType string) error
+	}
+
+	codeSender struct {
+		httpClient            httpclient.Client
+		env                   string
+		whatsAppPhoneNumberID string
+		whatsAppAccessToken   string
+		whatsAppOTPTemplate   string
+		smsGatewayURL         string
+		smsGatewayAPIKey      string
+	}
+)
+
+// NewCodeSender creates a code sender delivering over the WhatsApp Cloud API and an SMS gateway.
+// Outside production, codes for a channel that is not configured are logged instead.
+func NewCodeSender(httpClient httpclient.Client, cfg *config.Config) CodeSender {
+	return &codeSender{
+		httpClient:            httpClient,
+		env:                   cfg.Env,
+		whatsAppPhoneNumberID: cfg.WhatsAppPhoneNumberID,
+		whatsAppAccessToken:   cfg.WhatsAppAccessToken,
+		whatsAppOTPTemplate:   cfg.WhatsAppOTPTemplate,
+		smsGatewayURL:         cfg.SMSGatewayURL,
+		smsGatewayAPIKey:      cfg.SMSGatewayAPIKey,
+	}
+}
+
+// SendCode delivers a code over WhatsApp or SMS
+func (s *codeSender) SendCode(ctx context.Context, channel, phoneNumber, code, codeType string) error {
+	switch channel {
+	case entities.CodeDeliverySMS:
+		if s.smsGatewayURL == "" {
+			return s.logUndelivered(channel, phoneNumber, code)
+		}
+		return s.post(ctx, s.smsGatewayURL, s.smsGatewayAPIKey, map[string]interface{}{
+			"to":      phoneNumber,
+			"message": codeMessage(code, codeType),
+		})
+	default:
+		if s.whatsAppPhoneNumberID == "" || s.whatsAppAccessToken == "" {
+			return s.logUndelivered(channel, phoneNumber, code)
+		}
+		return s.post(ctx, fmt.Sprintf(whatsAppMessagesURL, s.whatsAppPhoneNumberID), s.whatsAppAccessToken, s.whatsAppPayload(phoneNumber, code, codeType))
+	}
+}
+
+// whatsAppPayload uses the approved authentication template when configured, WhatsApp only delivers
+// free text to users who messaged the business in the last 24 hours
+func (s *codeSender) whatsAppPayload(phoneNumber, code, codeType string) map[string]interface{} {
+	to := strings.TrimPrefix(phoneNumber, "+")
+	if s.whatsAppOTPTemplate == "" {
+		return map[string]interface{}{
+			"messaging_product": "whatsapp",
+			"to":                to,
+			"type":              "text",
+			"text":              map[string]string{"body": codeMessage(code, codeType)},
+		}
+	}
+
+	codeParameter := []map[string]string{{"type": "text", "text": code}}
+	return map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                to,
+		"type":              "template",
+		"template": map[string]interface{}{
+			"name":     s.whatsAppOTPTemplate,
+			"language": map[string]string{"code": "id"},
+			"components": []map[string]interface{}{
+				{"type": "body", "parameters": codeParameter},
+				{"type": "button", "sub_type": "url", "index": "0", "parameters": codeParameter},
+			},
+		},
+	}
+}
+
+// post sends a JSON payload with a bearer token
+func (s *codeSender) post(ctx context.Context, url, token string, payload map[string]interface{}) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to send code: %s, response: %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// logUndelivered logs a code for development, production needs the channel configured
+func (s *codeSender) logUndelivered(channel, phoneNumber, code string) error {
+	if s.env == "production" {
+		return errors.New("code delivery is not available")
+	}
+	utils.Log().Warn().Str("channel", channel).Str("phone_number", phoneNumber).Str("code", code).Msg("code delivery is not configured")
+	return nil
+}
+
+// codeMessage is the text sent with a code
+func codeMessage(code, codeType string) string {
+	switch codeType {
+	case entities.VerificationCodeTypePasswordReset:
+		return fmt.Sprintf("Kode reset PIN VASST kamu: %s. Berlaku 10 menit. Jangan berikan kode ini kepada siapa pun.", code)
+	default:
+		return fmt.Sprintf("Kode verifikasi VASST kamu: %s. Berlaku 10 menit. Jangan berikan kode ini kepada siapa pun.", code)
+	}
+}
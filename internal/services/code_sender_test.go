@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestCodeMessage(t *testing.T) {
+	t.Run("given a password reset code, when writing the message, then it names the PIN reset", func(t *testing.T) {
+		message := codeMessage("123456", entities.VerificationCodeTypePasswordReset)
+		assert.Contains(t, message, "123456")
+		assert.Contains(t, message, "reset PIN")
+	})
+
+	t.Run("given a phone verification code, when writing the message, then it is a verification message", func(t *testing.T) {
+		message := codeMessage("654321", entities.VerificationCodeTypePhone)
+		assert.Contains(t, message, "654321")
+		assert.Contains(t, message, "verifikasi")
+	})
+}
+
+func TestWhatsAppPayload(t *testing.T) {
+	t.Run("given no OTP template, when building the payload, then the code is sent as text without the plus sign", func(t *testing.T) {
+		sender := &codeSender{}
+		payload := sender.whatsAppPayload("+6281234567890", "123456", entities.VerificationCodeTypePhone)
+		assert.Equal(t, "6281234567890", payload["to"])
+		assert.Equal(t, "text", payload["type"])
+	})
+
+	t.Run("given an OTP template, when building the payload, then the template carries the code", func(t *testing.T) {
+		sender := &codeSender{whatsAppOTPTemplate: "vasst_otp"}
+		payload := sender.whatsAppPayload("+6281234567890", "123456", entities.VerificationCodeTypePasswordReset)
+		assert.Equal(t, "template", payload["type"])
+
+		template := payload["template"].(map[string]interface{})
+		assert.Equal(t, "vasst_otp", template["name"])
+		components := template["components"].([]map[string]interface{})
+		assert.Equal(t, []map[string]string{{"type": "text", "text": "123456"}}, components[0]["parameters"])
+	})
+}
+
+func TestLogUndelivered(t *testing.T) {
+	t.Run("given production without a configured channel, when sending, then delivery fails", func(t *testing.T) {
+		sender := &codeSender{env: "production"}
+		assert.EqualError(t, sender.logUndelivered(entities.CodeDeliverySMS, "+6281234567890", "123456"), "code delivery is not available")
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/google/uuid"
@@ -21,22 +22,32 @@ type (
 		GetConversationByID(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID) (*entities.Conversation, error)
 		GetSimpleConversationsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.ConversationSimple, int64, error)
 		GetOrCreateConversationByChannel(ctx context.Context, userID uuid.UUID, channel string) (*entities.Conversation, error)
+
+		// Active workspace of a conversation, e.g. a WhatsApp chat
+		SetActiveWorkspace(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID, workspaceID uuid.UUID) (*entities.Conversation, error)
+		GetActiveWorkspaceID(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID) (*uuid.UUID, error)
 	}
 
 	conversationService struct {
 		conversationRepo repositories.ConversationRepository
 		userRepo         repositories.UserRepository
+		authorizer       WorkspaceAuthorizer
 	}
 )
 
+// conversationActiveWorkspaceKey is the conversation metadata key holding its active workspace
+const conversationActiveWorkspaceKey = "active_workspace_id"
+
 // NewConversationService creates a new conversation service
 func NewConversationService(
 	conversationRepo repositories.ConversationRepository,
 	userRepo repositories.UserRepository,
+	authorizer WorkspaceAuthorizer,
 ) ConversationService {
 	return &conversationService{
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
+		authorizer:       authorizer,
 	}
 }
 
@@ -222,11 +233,121 @@ func (s *conversationService) GetOrCreateConversationByChannel(ctx context.Conte
 		return existingConversation, nil
 	}
 
-	// Create new conversation if none exists
+	// Create new conversation if none exists, starting in the user's active workspace
 	input := &entities.CreateConversationRequest{
 		UserID:  userID,
 		Channel: channel,
 	}
+	if workspaceID := userActiveWorkspaceID(user); workspaceID != nil {
+		metadata, err := setConversationActiveWorkspace(nil, *workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		input.Metadata = &metadata
+	}
 
 	return s.CreateConversation(ctx, userID, input)
 }
+
+// SetActiveWorkspace switches the workspace a conversation works in, the user must be a member of it
+func (s *conversationService) SetActiveWorkspace(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID, workspaceID uuid.UUID) (*entities.Conversation, error) {
+	conversation, err := s.GetConversationByID(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	metadata, err := setConversationActiveWorkspace(conversation.Metadata, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	conversation.Metadata = &metadata
+
+	updatedConversation, err := s.conversationRepo.Update(ctx, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedConversation, nil
+}
+
+// GetActiveWorkspaceID returns the workspace a conversation works in, falling back to the user's active
+// and default workspace. It returns nil when the user has none.
+func (s *conversationService) GetActiveWorkspaceID(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID) (*uuid.UUID, error) {
+	conversation, err := s.GetConversationByID(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspaceID := conversationActiveWorkspace(conversation.Metadata); workspaceID != nil {
+		// The user may have left the workspace since it was selected
+		member, err := s.authorizer.GetMembership(ctx, *workspaceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			return workspaceID, nil
+		}
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	return userActiveWorkspaceID(user), nil
+}
+
+// userActiveWorkspaceID returns the active workspace of a user, else the default one
+func userActiveWorkspaceID(user *entities.User) *uuid.UUID {
+	if user.ActiveWorkspaceID != nil {
+		return user.ActiveWorkspaceID
+	}
+	return user.DefaultWorkspaceID
+}
+
+// conversationActiveWorkspace reads the active workspace from conversation metadata, nil when unset or unreadable
+func conversationActiveWorkspace(metadata *string) *uuid.UUID {
+	if metadata == nil || *metadata == "" {
+		return nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(*metadata), &values); err != nil {
+		return nil
+	}
+
+	value, ok := values[conversationActiveWorkspaceKey].(string)
+	if !ok {
+		return nil
+	}
+	workspaceID, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &workspaceID
+}
+
+// setConversationActiveWorkspace writes the active workspace into conversation metadata, keeping its other keys
+func setConversationActiveWorkspace(metadata *string, workspaceID uuid.UUID) (string, error) {
+	values := map[string]interface{}{}
+	if metadata != nil && *metadata != "" {
+		if err := json.Unmarshal([]byte(*metadata), &values); err != nil || values == nil {
+			return "", errors.New("invalid conversation metadata")
+		}
+	}
+
+	values[conversationActiveWorkspaceKey] = workspaceID.String()
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
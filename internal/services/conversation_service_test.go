@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestConversationActiveWorkspace(t *testing.T) {
+	workspaceID := uuid.New()
+
+	t.Run("given no metadata, when the active workspace is read, then it is unset", func(t *testing.T) {
+		assert.Nil(t, conversationActiveWorkspace(nil))
+	})
+
+	t.Run("given metadata with a workspace, when the active workspace is read, then it is returned", func(t *testing.T) {
+		metadata := `{"active_workspace_id":"` + workspaceID.String() + `","locale":"id"}`
+		assert.Equal(t, &workspaceID, conversationActiveWorkspace(&metadata))
+	})
+
+	t.Run("given invalid metadata, when the active workspace is read, then it is unset", func(t *testing.T) {
+		metadata := `not json`
+		assert.Nil(t, conversationActiveWorkspace(&metadata))
+	})
+}
+
+func TestSetConversationActiveWorkspace(t *testing.T) {
+	workspaceID := uuid.New()
+
+	t.Run("given no metadata, when the active workspace is set, then the metadata holds only it", func(t *testing.T) {
+		metadata, err := setConversationActiveWorkspace(nil, workspaceID)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"active_workspace_id":"`+workspaceID.String()+`"}`, metadata)
+	})
+
+	t.Run("given metadata with other keys, when the active workspace is set, then the other keys are kept", func(t *testing.T) {
+		existing := `{"active_workspace_id":"` + uuid.New().String() + `","locale":"id"}`
+		metadata, err := setConversationActiveWorkspace(&existing, workspaceID)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"active_workspace_id":"`+workspaceID.String()+`","locale":"id"}`, metadata)
+	})
+
+	t.Run("given metadata that is not a JSON object, when the active workspace is set, then it fails", func(t *testing.T) {
+		existing := `[1, 2]`
+		_, err := setConversationActiveWorkspace(&existing, workspaceID)
+		assert.Error(t, err)
+	})
+}
+
+func TestUserActiveWorkspaceID(t *testing.T) {
+	defaultWorkspaceID := uuid.New()
+	activeWorkspaceID := uuid.New()
+
+	t.Run("given a user with an active workspace, when it is resolved, then the active workspace is returned", func(t *testing.T) {
+		user := &entities.User{DefaultWorkspaceID: &defaultWorkspaceID, ActiveWorkspaceID: &activeWorkspaceID}
+		assert.Equal(t, &activeWorkspaceID, userActiveWorkspaceID(user))
+	})
+
+	t.Run("given a user with only a default workspace, when it is resolved, then the default workspace is returned", func(t *testing.T) {
+		user := &entities.User{DefaultWorkspaceID: &defaultWorkspaceID}
+		assert.Equal(t, &defaultWorkspaceID, userActiveWorkspaceID(user))
+	})
+}
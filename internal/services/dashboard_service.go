@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=dashboard_service.go -package=mock -destination=mock/dashboard_service_mock.go
+type (
+	DashboardService interface {
+		GetConsolidatedDashboard(ctx context.Context, userID uuid.UUID, excludedWorkspaceIDs []uuid.UUID, startDate, endDate *time.Time) (*entities.ConsolidatedDashboard, error)
+	}
+
+	dashboardService struct {
+		dashboardRepo repositories.DashboardRepository
+		userRepo      repositories.UserRepository
+		currencyRepo  repositories.CurrencyRepository
+	}
+)
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(
+	dashboardRepo repositories.DashboardRepository,
+	userRepo repositories.UserRepository,
+	currencyRepo repositories.CurrencyRepository,
+) DashboardService {
+	return &dashboardService{
+		dashboardRepo: dashboardRepo,
+		userRepo:      userRepo,
+		currencyRepo:  currencyRepo,
+	}
+}
+
+// GetConsolidatedDashboard returns the income, expenses, budget status and account balances of a user across all their workspaces,
+// converted into the user currency, with a breakdown per workspace. The period defaults to the current month up to today.
+func (s *dashboardService) GetConsolidatedDashboard(ctx context.Context, userID uuid.UUID, excludedWorkspaceIDs []uuid.UUID, startDate, endDate *time.Time) (*entities.ConsolidatedDashboard, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	periodEnd := today(user.Timezone)
+	if endDate != nil {
+		periodEnd = *endDate
+	}
+	periodStart := time.Date(periodEnd.Year(), periodEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if startDate != nil {
+		periodStart = *startDate
+	}
+	if periodStart.After(periodEnd) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	if excludedWorkspaceIDs == nil {
+		excludedWorkspaceIDs = []uuid.UUID{}
+	}
+
+	workspaces, err := s.dashboardRepo.FindWorkspaceSummaries(ctx, userID, user.CurrencyID, excludedWorkspaceIDs, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.dashboardRepo.FindAccountBalances(ctx, userID, user.CurrencyID, excludedWorkspaceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := buildConsolidatedDashboard(workspaces, accounts)
+	dashboard.CurrencyID = user.CurrencyID
+	dashboard.StartDate = periodStart
+	dashboard.EndDate = periodEnd
+	dashboard.ExcludedWorkspaceIDs = excludedWorkspaceIDs
+
+	currency, err := s.currencyRepo.FindByID(ctx, user.CurrencyID)
+	if err != nil {
+		return nil, err
+	}
+	if currency != nil {
+		dashboard.CurrencyCode = currency.CurrencyCode
+		dashboard.CurrencySymbol = currency.CurrencySymbol
+	}
+
+	return dashboard, nil
+}
+
+// buildConsolidatedDashboard adds up the workspace summaries and splits the account balances into assets and liabilities
+func buildConsolidatedDashboard(workspaces []*entities.ConsolidatedWorkspaceSummary, accounts []*entities.NetWorthAccount) *entities.ConsolidatedDashboard {
+	dashboard := &entities.ConsolidatedDashboard{
+		Accounts:   buildNetWorthBreakdown(accounts),
+		Workspaces: make([]*entities.ConsolidatedWorkspaceSummary, 0, len(workspaces)),
+	}
+
+	for _, workspace := range workspaces {
+		workspace.TotalIncome = roundAmount(workspace.TotalIncome)
+		workspace.TotalExpense = roundAmount(workspace.TotalExpense)
+		workspace.NetCashFlow = roundAmount(workspace.TotalIncome - workspace.TotalExpense)
+		finalizeBudgetStatus(&workspace.Budget)
+
+		dashboard.TotalIncome += workspace.TotalIncome
+		dashboard.TotalExpense += workspace.TotalExpense
+		dashboard.UnconvertedCount += workspace.UnconvertedCount
+		dashboard.Budget.BudgetCount += workspace.Budget.BudgetCount
+		dashboard.Budget.OverspentCount += workspace.Budget.OverspentCount
+		dashboard.Budget.BudgetedAmount += workspace.Budget.BudgetedAmount
+		dashboard.Budget.SpentAmount += workspace.Budget.SpentAmount
+		dashboard.Budget.UnconvertedCount += workspace.Budget.UnconvertedCount
+		dashboard.Workspaces = append(dashboard.Workspaces, workspace)
+	}
+
+	dashboard.TotalIncome = roundAmount(dashboard.TotalIncome)
+	dashboard.TotalExpense = roundAmount(dashboard.TotalExpense)
+	dashboard.NetCashFlow = roundAmount(dashboard.TotalIncome - dashboard.TotalExpense)
+	if dashboard.TotalIncome > 0 {
+		savingsRate := percentageOf(dashboard.NetCashFlow, dashboard.TotalIncome)
+		dashboard.SavingsRate = &savingsRate
+	}
+	finalizeBudgetStatus(&dashboard.Budget)
+
+	return dashboard
+}
+
+// finalizeBudgetStatus rounds the budget amounts and derives the remaining amount and percentage used
+func finalizeBudgetStatus(status *entities.ConsolidatedBudgetStatus) {
+	status.BudgetedAmount = roundAmount(status.BudgetedAmount)
+	status.SpentAmount = roundAmount(status.SpentAmount)
+	status.RemainingAmount = roundAmount(status.BudgetedAmount - status.SpentAmount)
+	status.PercentageUsed = percentageOf(status.SpentAmount, status.BudgetedAmount)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestBuildConsolidatedDashboard(t *testing.T) {
+	t.Run("given several workspaces, when building the dashboard, then totals and budget status are added up", func(t *testing.T) {
+		workspaces := []*entities.ConsolidatedWorkspaceSummary{
+			{
+				WorkspaceName: "Pribadi",
+				TotalIncome:   10000000,
+				TotalExpense:  6000000,
+				Budget:        entities.ConsolidatedBudgetStatus{BudgetCount: 2, BudgetedAmount: 5000000, SpentAmount: 4000000},
+			},
+			{
+				WorkspaceName: "Liburan Bali",
+				TotalExpense:  2000000,
+				Budget:        entities.ConsolidatedBudgetStatus{BudgetCount: 1, OverspentCount: 1, BudgetedAmount: 1500000, SpentAmount: 2000000},
+			},
+		}
+		accounts := []*entities.NetWorthAccount{
+			{AccountName: "BCA", AccountType: entities.AccountTypeDebit, ConvertedBalance: convertedBalance(7000000)},
+			{AccountName: "Kartu Kredit", AccountType: entities.AccountTypeCredit, ConvertedBalance: convertedBalance(1000000)},
+		}
+
+		dashboard := buildConsolidatedDashboard(workspaces, accounts)
+
+		assert.Equal(t, 10000000.0, dashboard.TotalIncome)
+		assert.Equal(t, 8000000.0, dashboard.TotalExpense)
+		assert.Equal(t, 2000000.0, dashboard.NetCashFlow)
+		assert.Equal(t, 20.0, *dashboard.SavingsRate)
+		assert.Equal(t, 3, dashboard.Budget.BudgetCount)
+		assert.Equal(t, 1, dashboard.Budget.OverspentCount)
+		assert.Equal(t, 500000.0, dashboard.Budget.RemainingAmount)
+		assert.Equal(t, 92.31, dashboard.Budget.PercentageUsed)
+		assert.Equal(t, -2000000.0, dashboard.Workspaces[1].NetCashFlow)
+		assert.Equal(t, -500000.0, dashboard.Workspaces[1].Budget.RemainingAmount)
+		assert.Equal(t, 6000000.0, dashboard.Accounts.NetWorth)
+	})
+
+	t.Run("given no income, when building the dashboard, then there is no savings rate", func(t *testing.T) {
+		dashboard := buildConsolidatedDashboard(nil, nil)
+
+		assert.Nil(t, dashboard.SavingsRate)
+		assert.Empty(t, dashboard.Workspaces)
+		assert.Equal(t, 0.0, dashboard.Budget.PercentageUsed)
+	})
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/vasst-id/vasst-expense-api/config"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
+)
+
+//go:generate mockgen -source=email_sender.go -package=mock -destination=mock/email_sender_mock.go
+type (
+	// EmailSender delivers plain text email
+	EmailSender interface {
+		SendEmail(ctx context.Context, to, subject, body string) error
+	}
+
+	emailSender struct {
+		env      string
+		host     string
+		port     int
+		username string
+		password string
+		from     string
+	}
+)
+
+// NewEmailSender creates an email sender over SMTP.
+// Outside production, email is logged instead when SMTP is not configured.
+func NewEmailSender(cfg *config.Config) EmailSender {
+	return &emailSender{
+		env:      cfg.Env,
+		host:     cfg.SMTPHost,
+		port:     cfg.SMTPPort,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+		from:     cfg.EmailFrom,
+	}
+}
+
+// SendEmail sends a plain text email, STARTTLS is used when the server offers it
+func (s *emailSender) SendEmail(ctx context.Context, to, subject, body string) error {
+	if s.host == "" || s.from == "" {
+		if s.env == "production" {
+			return errors.New("email delivery is not available")
+		}
+		utils.Log().Warn().Str("to", to).Str("subject", subject).Str("body", body).Msg("email delivery is not configured")
+		return nil
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	// EMAIL_FROM may carry a display name, e.g. "VASST <no-reply@vasst.id>", the envelope takes the bare address
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	addr := s.host + ":" + strconv.Itoa(s.port)
+	if err := smtp.SendMail(addr, auth, from.Address, []string{to}, emailMessage(from.String(), to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// emailMessage builds an RFC 5322 message, the subject is encoded since it may not be ASCII
+func emailMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailMessage(t *testing.T) {
+	t.Run("given a subject and body, when building the message, then headers precede a CRLF body", func(t *testing.T) {
+		message := string(emailMessage("no-reply@vasst.id", "budi@example.com", "Verifikasi email", "Halo\nBudi"))
+		assert.True(t, strings.HasPrefix(message, "From: no-reply@vasst.id\r\nTo: budi@example.com\r\n"))
+		assert.Contains(t, message, "Content-Type: text/plain; charset=\"utf-8\"\r\n\r\nHalo\r\nBudi")
+	})
+
+	t.Run("given a non-ASCII subject, when building the message, then the subject is encoded", func(t *testing.T) {
+		message := string(emailMessage("no-reply@vasst.id", "budi@example.com", "Verifikasi ✓", ""))
+		assert.Contains(t, message, "Subject: =?utf-8?q?")
+	})
+}
+
+func TestSendEmailWithoutSMTP(t *testing.T) {
+	t.Run("given production without SMTP, when sending, then delivery fails", func(t *testing.T) {
+		sender := &emailSender{env: "production"}
+		assert.EqualError(t, sender.SendEmail(context.Background(), "budi@example.com", "Subject", "Body"), "email delivery is not available")
+	})
+}
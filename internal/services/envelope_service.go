@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+//go:generate mockgen -source=envelope_service.go -package=mock -destination=mock/envelope_service_mock.go
+type (
+	EnvelopeService interface {
+		GetMonthSummary(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, month string) (*entities.EnvelopeMonthSummary, error)
+		AllocateEnvelope(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.AllocateEnvelopeRequest) (*entities.EnvelopeAllocation, error)
+		TransferBetweenEnvelopes(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.EnvelopeTransferRequest) (*entities.EnvelopeTransfer, error)
+		CoverOverspend(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.EnvelopeTransferRequest) (*entities.EnvelopeTransfer, error)
+		GetTransferHistory(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, limit, offset int) ([]*entities.EnvelopeTransferSimple, error)
+	}
+
+	envelopeService struct {
+		envelopeRepo repositories.EnvelopeRepository
+		authorizer   WorkspaceAuthorizer
+	}
+)
+
+// NewEnvelopeService creates a new envelope service
+func NewEnvelopeService(envelopeRepo repositories.EnvelopeRepository, authorizer WorkspaceAuthorizer) EnvelopeService {
+	return &envelopeService{
+		envelopeRepo: envelopeRepo,
+		authorizer:   authorizer,
+	}
+}
+
+// GetMonthSummary returns the available to assign pool and every envelope of a month
+func (s *envelopeService) GetMonthSummary(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, month string) (*entities.EnvelopeMonthSummary, error) {
+	workspace, err := s.getEnvelopeWorkspace(ctx, userID, workspaceID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	var monthStart time.Time
+	if month == "" {
+		monthStart = currentMonth(workspace.Timezone)
+	} else {
+		monthStart, err = parseEnvelopeMonth(month)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.buildSummary(ctx, workspaceID, monthStart)
+}
+
+// AllocateEnvelope sets the amount assigned to an envelope for a month, taken from the available to assign pool
+func (s *envelopeService) AllocateEnvelope(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.AllocateEnvelopeRequest) (*entities.EnvelopeAllocation, error) {
+	if input.UserCategoryID == uuid.Nil {
+		return nil, errors.New("user category ID is required")
+	}
+	if input.Amount < 0 {
+		return nil, errors.New("amount cannot be negative")
+	}
+	monthStart, err := parseEnvelopeMonth(input.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getEnvelopeWorkspace(ctx, userID, workspaceID, entities.WorkspacePermissionEditBudget); err != nil {
+		return nil, err
+	}
+
+	summary, err := s.buildSummary(ctx, workspaceID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the increase over the current allocation has to come from the pool
+	currentlyAssigned := 0.0
+	if envelope := findEnvelope(summary, input.UserCategoryID); envelope != nil {
+		currentlyAssigned = envelope.Assigned
+	}
+	if input.Amount-currentlyAssigned > roundAmount(summary.AvailableToAssign) {
+		return nil, errors.New("amount exceeds available to assign")
+	}
+
+	allocation := &entities.EnvelopeAllocation{
+		AllocationID:    uuid.New(),
+		WorkspaceID:     workspaceID,
+		UserCategoryID:  input.UserCategoryID,
+		AllocationMonth: monthStart,
+		Amount:          input.Amount,
+		CreatedBy:       userID,
+	}
+
+	upsertedAllocation, err := s.envelopeRepo.UpsertAllocation(ctx, allocation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upsertedAllocation, nil
+}
+
+// TransferBetweenEnvelopes moves available money from one envelope to another
+func (s *envelopeService) TransferBetweenEnvelopes(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.EnvelopeTransferRequest) (*entities.EnvelopeTransfer, error) {
+	return s.transfer(ctx, userID, workspaceID, input, entities.EnvelopeTransferTypeMove)
+}
+
+// CoverOverspend moves money from another envelope into an overspent envelope.
+// When no amount is given the whole overspent amount is covered.
+func (s *envelopeService) CoverOverspend(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.EnvelopeTransferRequest) (*entities.EnvelopeTransfer, error) {
+	return s.transfer(ctx, userID, workspaceID, input, entities.EnvelopeTransferTypeCover)
+}
+
+// GetTransferHistory returns the envelope transfers of a workspace
+func (s *envelopeService) GetTransferHistory(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, limit, offset int) ([]*entities.EnvelopeTransferSimple, error) {
+	if _, err := s.getEnvelopeWorkspace(ctx, userID, workspaceID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	return s.envelopeRepo.FindTransfersByWorkspace(ctx, workspaceID, limit, offset)
+}
+
+func (s *envelopeService) transfer(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.EnvelopeTransferRequest, transferType int) (*entities.EnvelopeTransfer, error) {
+	if input.FromUserCategoryID == uuid.Nil || input.ToUserCategoryID == uuid.Nil {
+		return nil, errors.New("source and destination envelopes are required")
+	}
+	if input.FromUserCategoryID == input.ToUserCategoryID {
+		return nil, errors.New("source and destination envelopes must be different")
+	}
+	if input.Amount < 0 || (transferType == entities.EnvelopeTransferTypeMove && input.Amount == 0) {
+		return nil, errors.New("amount must be greater than 0")
+	}
+	monthStart, err := parseEnvelopeMonth(input.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getEnvelopeWorkspace(ctx, userID, workspaceID, entities.WorkspacePermissionEditBudget); err != nil {
+		return nil, err
+	}
+
+	summary, err := s.buildSummary(ctx, workspaceID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := input.Amount
+	if transferType == entities.EnvelopeTransferTypeCover {
+		target := findEnvelope(summary, input.ToUserCategoryID)
+		if target == nil || !target.IsOverspent {
+			return nil, errors.New("envelope is not overspent")
+		}
+		overspent := roundAmount(-target.Available)
+		if amount == 0 {
+			amount = overspent
+		}
+		if amount > overspent {
+			return nil, errors.New("amount exceeds overspent amount")
+		}
+	}
+
+	source := findEnvelope(summary, input.FromUserCategoryID)
+	if source == nil || roundAmount(source.Available) < amount {
+		return nil, errors.New("insufficient envelope balance")
+	}
+
+	transfer := &entities.EnvelopeTransfer{
+		TransferID:         uuid.New(),
+		WorkspaceID:        workspaceID,
+		FromUserCategoryID: input.FromUserCategoryID,
+		ToUserCategoryID:   input.ToUserCategoryID,
+		TransferMonth:      monthStart,
+		Amount:             amount,
+		TransferType:       transferType,
+		Note:               input.Note,
+		CreatedBy:          userID,
+	}
+
+	createdTransfer, err := s.envelopeRepo.CreateTransfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdTransfer, nil
+}
+
+// getEnvelopeWorkspace verifies the user's permission on the workspace and that envelope budgeting is enabled
+func (s *envelopeService) getEnvelopeWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, permission entities.WorkspacePermission) (*entities.Workspace, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.BudgetingMode != entities.BudgetingModeEnvelope {
+		return nil, errors.New("envelope budgeting is not enabled for this workspace")
+	}
+	return workspace, nil
+}
+
+func (s *envelopeService) buildSummary(ctx context.Context, workspaceID uuid.UUID, monthStart time.Time) (*entities.EnvelopeMonthSummary, error) {
+	incomes, err := s.envelopeRepo.FindMonthlyIncome(ctx, workspaceID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.envelopeRepo.FindMonthlyActivity(ctx, workspaceID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildEnvelopeMonthSummary(workspaceID, monthStart, incomes, activities), nil
+}
+
+// buildEnvelopeMonthSummary rolls envelope balances forward month by month up to the requested month.
+// A positive balance carries over to the next month. Overspending that was not covered by the end
+// of a month does not carry over into the envelope; it is taken from the available to assign pool instead.
+func buildEnvelopeMonthSummary(workspaceID uuid.UUID, month time.Time, incomes []*entities.EnvelopeMonthlyIncome, activities []*entities.EnvelopeMonthlyActivity) *entities.EnvelopeMonthSummary {
+	summary := &entities.EnvelopeMonthSummary{
+		WorkspaceID: workspaceID,
+		Month:       month,
+		Envelopes:   []*entities.EnvelopeBalance{},
+	}
+
+	totalIncome := 0.0
+	for _, income := range incomes {
+		totalIncome += income.Amount
+		if sameMonth(income.Month, month) {
+			summary.Income = income.Amount
+		}
+	}
+
+	type envelopeState struct {
+		balance   float64
+		lastMonth time.Time
+		current   *entities.EnvelopeBalance
+	}
+
+	totalAssigned := 0.0
+	uncoveredPrevious := 0.0
+	states := make(map[uuid.UUID]*envelopeState)
+	var order []uuid.UUID
+
+	// Activities are ordered by month, so each envelope is closed before its next month starts
+	for _, activity := range activities {
+		totalAssigned += activity.Assigned
+
+		state, ok := states[activity.UserCategoryID]
+		if !ok {
+			state = &envelopeState{}
+			states[activity.UserCategoryID] = state
+			order = append(order, activity.UserCategoryID)
+		} else if state.balance < 0 {
+			uncoveredPrevious += -state.balance
+			state.balance = 0
+		}
+
+		if sameMonth(activity.Month, month) {
+			state.current = &entities.EnvelopeBalance{
+				UserCategoryID: activity.UserCategoryID,
+				EnvelopeName:   activity.EnvelopeName,
+				CarriedOver:    state.balance,
+				Assigned:       activity.Assigned,
+				TransferredIn:  activity.TransferredIn,
+				TransferredOut: activity.TransferredOut,
+				Spent:          activity.Spent,
+			}
+		} else {
+			// Keep the name for envelopes without activity in the requested month
+			state.current = &entities.EnvelopeBalance{
+				UserCategoryID: activity.UserCategoryID,
+				EnvelopeName:   activity.EnvelopeName,
+			}
+		}
+
+		state.balance += activity.Assigned + activity.TransferredIn - activity.TransferredOut - activity.Spent
+		state.lastMonth = activity.Month
+	}
+
+	for _, userCategoryID := range order {
+		state := states[userCategoryID]
+		envelope := state.current
+
+		if !sameMonth(state.lastMonth, month) {
+			// No activity this month: the envelope only holds what was carried over
+			if state.balance < 0 {
+				uncoveredPrevious += -state.balance
+				state.balance = 0
+			}
+			envelope.CarriedOver = state.balance
+		}
+
+		envelope.Available = roundAmount(state.balance)
+		envelope.IsOverspent = envelope.Available < 0
+		if envelope.IsOverspent {
+			summary.OverspentAmount += -envelope.Available
+		}
+
+		summary.Assigned += envelope.Assigned
+		summary.Spent += envelope.Spent
+		summary.Envelopes = append(summary.Envelopes, envelope)
+	}
+
+	summary.UncoveredPrevious = roundAmount(uncoveredPrevious)
+	summary.AvailableToAssign = roundAmount(totalIncome - totalAssigned - uncoveredPrevious)
+	summary.OverspentAmount = roundAmount(summary.OverspentAmount)
+
+	return summary
+}
+
+func findEnvelope(summary *entities.EnvelopeMonthSummary, userCategoryID uuid.UUID) *entities.EnvelopeBalance {
+	for _, envelope := range summary.Envelopes {
+		if envelope.UserCategoryID == userCategoryID {
+			return envelope
+		}
+	}
+	return nil
+}
+
+// parseEnvelopeMonth parses a YYYY-MM month into the first day of that month
+func parseEnvelopeMonth(month string) (time.Time, error) {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, errors.New("invalid month format, expected YYYY-MM")
+	}
+	return t, nil
+}
+
+// currentMonth returns the first day of the current month in the given timezone
+func currentMonth(timezone string) time.Time {
+	now := time.Now()
+	if location, err := time.LoadLocation(timezone); err == nil {
+		now = now.In(location)
+	}
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
+
+// roundAmount rounds an amount to 2 decimal places to avoid floating point drift
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestBuildEnvelopeMonthSummary(t *testing.T) {
+	workspaceID := uuid.New()
+	food := uuid.New()
+	transport := uuid.New()
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("given income and allocations, when building the month, then the pool is income minus assigned", func(t *testing.T) {
+		incomes := []*entities.EnvelopeMonthlyIncome{{Month: jan, Amount: 10000000}}
+		activities := []*entities.EnvelopeMonthlyActivity{
+			{UserCategoryID: food, EnvelopeName: "Makan", Month: jan, Assigned: 3000000, Spent: 1000000},
+			{UserCategoryID: transport, EnvelopeName: "Transport", Month: jan, Assigned: 1000000},
+		}
+
+		summary := buildEnvelopeMonthSummary(workspaceID, jan, incomes, activities)
+
+		assert.Equal(t, 10000000.0, summary.Income)
+		assert.Equal(t, 6000000.0, summary.AvailableToAssign)
+		assert.Equal(t, 4000000.0, summary.Assigned)
+		assert.Len(t, summary.Envelopes, 2)
+		assert.Equal(t, 2000000.0, findEnvelope(summary, food).Available)
+	})
+
+	t.Run("given a positive balance last month, when building the next month, then it carries over", func(t *testing.T) {
+		incomes := []*entities.EnvelopeMonthlyIncome{{Month: jan, Amount: 5000000}}
+		activities := []*entities.EnvelopeMonthlyActivity{
+			{UserCategoryID: food, EnvelopeName: "Makan", Month: jan, Assigned: 3000000, Spent: 1000000},
+			{UserCategoryID: food, EnvelopeName: "Makan", Month: feb, Spent: 500000},
+		}
+
+		summary := buildEnvelopeMonthSummary(workspaceID, feb, incomes, activities)
+		envelope := findEnvelope(summary, food)
+
+		assert.Equal(t, 2000000.0, envelope.CarriedOver)
+		assert.Equal(t, 1500000.0, envelope.Available)
+		assert.Equal(t, 2000000.0, summary.AvailableToAssign)
+		assert.Equal(t, 0.0, summary.Income)
+	})
+
+	t.Run("given an overspent envelope this month, when building the month, then it is flagged", func(t *testing.T) {
+		activities := []*entities.EnvelopeMonthlyActivity{
+			{UserCategoryID: food, EnvelopeName: "Makan", Month: jan, Assigned: 1000000, Spent: 1250000},
+			{UserCategoryID: transport, EnvelopeName: "Transport", Month: jan, Assigned: 500000, TransferredIn: 100000, TransferredOut: 0},
+		}
+
+		summary := buildEnvelopeMonthSummary(workspaceID, jan, nil, activities)
+		envelope := findEnvelope(summary, food)
+
+		assert.True(t, envelope.IsOverspent)
+		assert.Equal(t, -250000.0, envelope.Available)
+		assert.Equal(t, 250000.0, summary.OverspentAmount)
+		assert.False(t, findEnvelope(summary, transport).IsOverspent)
+	})
+
+	t.Run("given uncovered overspending last month, when building the next month, then it is taken from the pool", func(t *testing.T) {
+		incomes := []*entities.EnvelopeMonthlyIncome{{Month: jan, Amount: 2000000}}
+		activities := []*entities.EnvelopeMonthlyActivity{
+			{UserCategoryID: food, EnvelopeName: "Makan", Month: jan, Assigned: 1000000, Spent: 1250000},
+		}
+
+		summary := buildEnvelopeMonthSummary(workspaceID, feb, incomes, activities)
+		envelope := findEnvelope(summary, food)
+
+		assert.Equal(t, 0.0, envelope.CarriedOver)
+		assert.Equal(t, 0.0, envelope.Available)
+		assert.False(t, envelope.IsOverspent)
+		assert.Equal(t, 250000.0, summary.UncoveredPrevious)
+		assert.Equal(t, 750000.0, summary.AvailableToAssign)
+	})
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=exchange_rate_service.go -package=mock -destination=mock/exchange_rate_service_mock.go
+type (
+	ExchangeRateService interface {
+		UpsertExchangeRate(ctx context.Context, input *entities.UpsertExchangeRateInput) (*entities.ExchangeRate, error)
+		DeleteExchangeRate(ctx context.Context, exchangeRateID uuid.UUID) error
+		GetAllExchangeRates(ctx context.Context, limit, offset int) ([]*entities.ExchangeRate, error)
+	}
+
+	exchangeRateService struct {
+		exchangeRateRepo repositories.ExchangeRateRepository
+		currencyRepo     repositories.CurrencyRepository
+	}
+)
+
+// NewExchangeRateService creates a new exchange rate service
+func NewExchangeRateService(exchangeRateRepo repositories.ExchangeRateRepository, currencyRepo repositories.CurrencyRepository) ExchangeRateService {
+	return &exchangeRateService{
+		exchangeRateRepo: exchangeRateRepo,
+		currencyRepo:     currencyRepo,
+	}
+}
+
+// UpsertExchangeRate sets the rate of a currency pair from an effective date
+func (s *exchangeRateService) UpsertExchangeRate(ctx context.Context, input *entities.UpsertExchangeRateInput) (*entities.ExchangeRate, error) {
+	if input.FromCurrencyID == input.ToCurrencyID {
+		return nil, errors.New("from and to currency must be different")
+	}
+	if input.Rate <= 0 {
+		return nil, errors.New("rate must be greater than 0")
+	}
+
+	effectiveDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if input.EffectiveDate != "" {
+		parsedDate, err := time.Parse("2006-01-02", input.EffectiveDate)
+		if err != nil {
+			return nil, errors.New("invalid effective date format, expected YYYY-MM-DD")
+		}
+		effectiveDate = parsedDate
+	}
+
+	for _, currencyID := range []int{input.FromCurrencyID, input.ToCurrencyID} {
+		currency, err := s.currencyRepo.FindByID(ctx, currencyID)
+		if err != nil {
+			return nil, err
+		}
+		if currency == nil {
+			return nil, errorsutil.New(404, "currency not found")
+		}
+	}
+
+	rate := &entities.ExchangeRate{
+		ExchangeRateID: uuid.New(),
+		FromCurrencyID: input.FromCurrencyID,
+		ToCurrencyID:   input.ToCurrencyID,
+		Rate:           input.Rate,
+		EffectiveDate:  effectiveDate,
+	}
+
+	upsertedRate, err := s.exchangeRateRepo.Upsert(ctx, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upsertedRate, nil
+}
+
+// DeleteExchangeRate deletes an exchange rate
+func (s *exchangeRateService) DeleteExchangeRate(ctx context.Context, exchangeRateID uuid.UUID) error {
+	err := s.exchangeRateRepo.Delete(ctx, exchangeRateID)
+	if err == sql.ErrNoRows {
+		return errorsutil.New(404, "exchange rate not found")
+	}
+	return err
+}
+
+// GetAllExchangeRates returns exchange rates, newest effective date first
+func (s *exchangeRateService) GetAllExchangeRates(ctx context.Context, limit, offset int) ([]*entities.ExchangeRate, error) {
+	return s.exchangeRateRepo.FindAll(ctx, limit, offset)
+}
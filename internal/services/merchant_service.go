@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=merchant_service.go -package=mock -destination=mock/merchant_service_mock.go
+type (
+	MerchantService interface {
+		// Admin methods, global merchants and aliases
+		CreateMerchant(ctx context.Context, input *entities.CreateMerchantRequest) (*entities.Merchant, error)
+		UpdateMerchant(ctx context.Context, merchantID uuid.UUID, input *entities.UpdateMerchantRequest) (*entities.Merchant, error)
+		DeleteMerchant(ctx context.Context, merchantID uuid.UUID) error
+		GetAllMerchants(ctx context.Context, search string, limit, offset int) ([]*entities.Merchant, error)
+		CreateGlobalAlias(ctx context.Context, merchantID uuid.UUID, input *entities.CreateMerchantAliasRequest) (*entities.MerchantAlias, error)
+		GetGlobalAliases(ctx context.Context, merchantID uuid.UUID) ([]*entities.MerchantAlias, error)
+		DeleteGlobalAlias(ctx context.Context, merchantAliasID uuid.UUID) error
+
+		// User methods
+		GetMerchantDirectory(ctx context.Context, search string, limit, offset int) ([]*entities.Merchant, error)
+		CreatePersonalAlias(ctx context.Context, userID uuid.UUID, input *entities.CreateMerchantAliasRequest) (*entities.MerchantAlias, error)
+		GetPersonalAliases(ctx context.Context, userID uuid.UUID) ([]*entities.MerchantAlias, error)
+		DeletePersonalAlias(ctx context.Context, userID uuid.UUID, merchantAliasID uuid.UUID) error
+		NormalizeMerchant(ctx context.Context, userID uuid.UUID, merchantName string) (*entities.MerchantMatch, error)
+	}
+
+	merchantService struct {
+		merchantRepo repositories.MerchantRepository
+	}
+)
+
+// NewMerchantService creates a new merchant service
+func NewMerchantService(merchantRepo repositories.MerchantRepository) MerchantService {
+	return &merchantService{
+		merchantRepo: merchantRepo,
+	}
+}
+
+// CreateMerchant creates a global merchant. Its normalized name becomes a global prefix alias.
+func (s *merchantService) CreateMerchant(ctx context.Context, input *entities.CreateMerchantRequest) (*entities.Merchant, error) {
+	name := strings.TrimSpace(input.Name)
+	pattern := NormalizeMerchantName(name)
+	if pattern == "" {
+		return nil, errors.New("merchant name is required")
+	}
+
+	existingMerchant, err := s.merchantRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existingMerchant != nil {
+		return nil, errorsutil.New(409, "merchant with this name already exists")
+	}
+
+	merchant := &entities.Merchant{
+		MerchantID:        uuid.New(),
+		Name:              name,
+		DefaultCategoryID: input.DefaultCategoryID,
+		LogoURL:           input.LogoURL,
+		IsActive:          true,
+	}
+
+	createdMerchant, err := s.merchantRepo.Create(ctx, merchant)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.CreateGlobalAlias(ctx, createdMerchant.MerchantID, &entities.CreateMerchantAliasRequest{
+		Pattern:   pattern,
+		MatchType: entities.MerchantMatchPrefix,
+	})
+	if err != nil && err.Error() != "merchant alias already exists" {
+		return nil, err
+	}
+
+	return &createdMerchant, nil
+}
+
+// UpdateMerchant updates a global merchant
+func (s *merchantService) UpdateMerchant(ctx context.Context, merchantID uuid.UUID, input *entities.UpdateMerchantRequest) (*entities.Merchant, error) {
+	name := strings.TrimSpace(input.Name)
+	if NormalizeMerchantName(name) == "" {
+		return nil, errors.New("merchant name is required")
+	}
+
+	existingMerchant, err := s.merchantRepo.FindByID(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if existingMerchant == nil {
+		return nil, errorsutil.New(404, "merchant not found")
+	}
+
+	if !strings.EqualFold(existingMerchant.Name, name) {
+		sameName, err := s.merchantRepo.FindByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if sameName != nil {
+			return nil, errorsutil.New(409, "merchant with this name already exists")
+		}
+	}
+
+	existingMerchant.Name = name
+	existingMerchant.DefaultCategoryID = input.DefaultCategoryID
+	existingMerchant.LogoURL = input.LogoURL
+	existingMerchant.IsActive = input.IsActive
+
+	updatedMerchant, err := s.merchantRepo.Update(ctx, existingMerchant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedMerchant, nil
+}
+
+// DeleteMerchant deletes a global merchant and its aliases
+func (s *merchantService) DeleteMerchant(ctx context.Context, merchantID uuid.UUID) error {
+	err := s.merchantRepo.Delete(ctx, merchantID)
+	if err == sql.ErrNoRows {
+		return errorsutil.New(404, "merchant not found")
+	}
+	return err
+}
+
+// GetAllMerchants returns all merchants including inactive ones
+func (s *merchantService) GetAllMerchants(ctx context.Context, search string, limit, offset int) ([]*entities.Merchant, error) {
+	return s.merchantRepo.FindAll(ctx, search, false, limit, offset)
+}
+
+// CreateGlobalAlias adds a global matching rule to a merchant
+func (s *merchantService) CreateGlobalAlias(ctx context.Context, merchantID uuid.UUID, input *entities.CreateMerchantAliasRequest) (*entities.MerchantAlias, error) {
+	input.MerchantID = merchantID
+	return s.createAlias(ctx, nil, input)
+}
+
+// GetGlobalAliases returns the global aliases of a merchant
+func (s *merchantService) GetGlobalAliases(ctx context.Context, merchantID uuid.UUID) ([]*entities.MerchantAlias, error) {
+	merchant, err := s.merchantRepo.FindByID(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merchant == nil {
+		return nil, errorsutil.New(404, "merchant not found")
+	}
+
+	return s.merchantRepo.FindGlobalAliasesByMerchant(ctx, merchantID)
+}
+
+// DeleteGlobalAlias deletes a global alias
+func (s *merchantService) DeleteGlobalAlias(ctx context.Context, merchantAliasID uuid.UUID) error {
+	alias, err := s.merchantRepo.FindAliasByID(ctx, merchantAliasID)
+	if err != nil {
+		return err
+	}
+	if alias == nil || alias.UserID != nil {
+		return errorsutil.New(404, "merchant alias not found")
+	}
+
+	return s.merchantRepo.DeleteAlias(ctx, merchantAliasID)
+}
+
+// GetMerchantDirectory returns the active merchants
+func (s *merchantService) GetMerchantDirectory(ctx context.Context, search string, limit, offset int) ([]*entities.Merchant, error) {
+	return s.merchantRepo.FindAll(ctx, search, true, limit, offset)
+}
+
+// CreatePersonalAlias adds a matching rule that only applies to the user's own transactions
+func (s *merchantService) CreatePersonalAlias(ctx context.Context, userID uuid.UUID, input *entities.CreateMerchantAliasRequest) (*entities.MerchantAlias, error) {
+	if input.MerchantID == uuid.Nil {
+		return nil, errors.New("merchant ID is required")
+	}
+	return s.createAlias(ctx, &userID, input)
+}
+
+// GetPersonalAliases returns the personal aliases of a user
+func (s *merchantService) GetPersonalAliases(ctx context.Context, userID uuid.UUID) ([]*entities.MerchantAlias, error) {
+	return s.merchantRepo.FindAliasesByUser(ctx, userID)
+}
+
+// DeletePersonalAlias deletes a personal alias of a user
+func (s *merchantService) DeletePersonalAlias(ctx context.Context, userID uuid.UUID, merchantAliasID uuid.UUID) error {
+	alias, err := s.merchantRepo.FindAliasByID(ctx, merchantAliasID)
+	if err != nil {
+		return err
+	}
+	if alias == nil {
+		return errorsutil.New(404, "merchant alias not found")
+	}
+	if alias.UserID == nil || *alias.UserID != userID {
+		return errorsutil.New(403, "access denied to merchant alias")
+	}
+
+	return s.merchantRepo.DeleteAlias(ctx, merchantAliasID)
+}
+
+// NormalizeMerchant matches free text merchant names against the personal and global aliases.
+// It returns nil when no merchant matches. The user category is the user's category for the merchant's default category.
+func (s *merchantService) NormalizeMerchant(ctx context.Context, userID uuid.UUID, merchantName string) (*entities.MerchantMatch, error) {
+	text := NormalizeMerchantName(merchantName)
+	if text == "" {
+		return nil, nil
+	}
+
+	candidates, err := s.merchantRepo.FindAliasCandidates(ctx, userID, text)
+	if err != nil {
+		return nil, err
+	}
+
+	alias := matchMerchantAlias(text, candidates)
+	if alias == nil {
+		return nil, nil
+	}
+
+	merchant, err := s.merchantRepo.FindByID(ctx, alias.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merchant == nil {
+		return nil, nil
+	}
+
+	match := &entities.MerchantMatch{
+		Merchant: merchant,
+		Alias:    alias,
+	}
+	if merchant.DefaultCategoryID != nil {
+		match.UserCategoryID, err = s.merchantRepo.FindUserCategoryID(ctx, userID, *merchant.DefaultCategoryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return match, nil
+}
+
+func (s *merchantService) createAlias(ctx context.Context, userID *uuid.UUID, input *entities.CreateMerchantAliasRequest) (*entities.MerchantAlias, error) {
+	if input.MatchType == 0 {
+		input.MatchType = entities.MerchantMatchPrefix
+	}
+	if input.MatchType < entities.MerchantMatchExact || input.MatchType > entities.MerchantMatchContains {
+		return nil, errors.New("invalid match type")
+	}
+
+	pattern := NormalizeMerchantName(input.Pattern)
+	if pattern == "" {
+		return nil, errors.New("pattern is required")
+	}
+
+	merchant, err := s.merchantRepo.FindByID(ctx, input.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if merchant == nil {
+		return nil, errorsutil.New(404, "merchant not found")
+	}
+
+	// Candidates for the pattern itself include any alias with the same pattern
+	ownerID := uuid.Nil
+	if userID != nil {
+		ownerID = *userID
+	}
+	existingAliases, err := s.merchantRepo.FindAliasCandidates(ctx, ownerID, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range existingAliases {
+		if existing.Pattern == pattern && existing.MatchType == input.MatchType && (existing.UserID == nil) == (userID == nil) {
+			return nil, errorsutil.New(409, "merchant alias already exists")
+		}
+	}
+
+	alias := &entities.MerchantAlias{
+		MerchantAliasID: uuid.New(),
+		MerchantID:      input.MerchantID,
+		UserID:          userID,
+		Pattern:         pattern,
+		MatchType:       input.MatchType,
+	}
+
+	createdAlias, err := s.merchantRepo.CreateAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdAlias, nil
+}
+
+// NormalizeMerchantName reduces free text merchant names to comparable text: lower case, letters only,
+// and without words containing digits such as store numbers. "INDOMARET 123 JKT" becomes "indomaret jkt".
+func NormalizeMerchantName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		normalized = append(normalized, word)
+	}
+
+	return strings.Join(normalized, " ")
+}
+
+// matchMerchantAlias picks the alias that best matches normalized text. Patterns match whole words:
+// exact matches the full text, prefix its first words and contains any run of words.
+// Personal aliases win over global ones, then exact over prefix over contains, then the longest pattern.
+func matchMerchantAlias(text string, aliases []*entities.MerchantAlias) *entities.MerchantAlias {
+	var best *entities.MerchantAlias
+	for _, alias := range aliases {
+		if !merchantAliasMatches(text, alias) {
+			continue
+		}
+		if best == nil || merchantAliasRanksHigher(alias, best) {
+			best = alias
+		}
+	}
+	return best
+}
+
+func merchantAliasMatches(text string, alias *entities.MerchantAlias) bool {
+	switch alias.MatchType {
+	case entities.MerchantMatchExact:
+		return text == alias.Pattern
+	case entities.MerchantMatchPrefix:
+		return text == alias.Pattern || strings.HasPrefix(text, alias.Pattern+" ")
+	case entities.MerchantMatchContains:
+		return strings.Contains(" "+text+" ", " "+alias.Pattern+" ")
+	default:
+		return false
+	}
+}
+
+func merchantAliasRanksHigher(alias, other *entities.MerchantAlias) bool {
+	if (alias.UserID != nil) != (other.UserID != nil) {
+		return alias.UserID != nil
+	}
+	if alias.MatchType != other.MatchType {
+		return alias.MatchType < other.MatchType
+	}
+	return len(alias.Pattern) > len(other.Pattern)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestNormalizeMerchantName(t *testing.T) {
+	t.Run("given merchant names with store numbers and punctuation, when normalizing, then only lower case words remain", func(t *testing.T) {
+		assert.Equal(t, "indomaret jkt", NormalizeMerchantName("INDOMARET 123 JKT"))
+		assert.Equal(t, "indomaret", NormalizeMerchantName("  indomaret "))
+		assert.Equal(t, "indomaret point", NormalizeMerchantName("Indomaret Point"))
+		assert.Equal(t, "alfamart", NormalizeMerchantName("ALFAMART-T123*"))
+		assert.Equal(t, "h&m", NormalizeMerchantName("H&M"))
+		assert.Equal(t, "", NormalizeMerchantName("12345"))
+	})
+}
+
+func TestMatchMerchantAlias(t *testing.T) {
+	userID := uuid.New()
+	indomaret := &entities.MerchantAlias{MerchantID: uuid.New(), Pattern: "indomaret", MatchType: entities.MerchantMatchPrefix}
+	indomaretPoint := &entities.MerchantAlias{MerchantID: uuid.New(), Pattern: "indomaret point", MatchType: entities.MerchantMatchPrefix}
+	kopi := &entities.MerchantAlias{MerchantID: uuid.New(), Pattern: "kopi", MatchType: entities.MerchantMatchContains}
+	personalKopi := &entities.MerchantAlias{MerchantID: uuid.New(), UserID: &userID, Pattern: "kopi", MatchType: entities.MerchantMatchContains}
+	exactGrab := &entities.MerchantAlias{MerchantID: uuid.New(), Pattern: "grab", MatchType: entities.MerchantMatchExact}
+
+	t.Run("given variants of one merchant, when matching, then they resolve to the same alias", func(t *testing.T) {
+		aliases := []*entities.MerchantAlias{indomaret}
+
+		assert.Equal(t, indomaret, matchMerchantAlias("indomaret jkt", aliases))
+		assert.Equal(t, indomaret, matchMerchantAlias("indomaret", aliases))
+		assert.Nil(t, matchMerchantAlias("indomaretpoint", aliases))
+	})
+
+	t.Run("given overlapping prefixes, when matching, then the longest pattern wins", func(t *testing.T) {
+		aliases := []*entities.MerchantAlias{indomaret, indomaretPoint}
+
+		assert.Equal(t, indomaretPoint, matchMerchantAlias("indomaret point bintaro", aliases))
+	})
+
+	t.Run("given a personal and a global alias, when matching, then the personal alias wins", func(t *testing.T) {
+		aliases := []*entities.MerchantAlias{kopi, personalKopi}
+
+		assert.Equal(t, personalKopi, matchMerchantAlias("toko kopi tuku", aliases))
+	})
+
+	t.Run("given an exact alias, when the text has extra words, then it does not match", func(t *testing.T) {
+		aliases := []*entities.MerchantAlias{exactGrab}
+
+		assert.Equal(t, exactGrab, matchMerchantAlias("grab", aliases))
+		assert.Nil(t, matchMerchantAlias("grab food", aliases))
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=net_worth_service.go -package=mock -destination=mock/net_worth_service_mock.go
+type (
+	NetWorthService interface {
+		GetNetWorth(ctx context.Context, userID uuid.UUID, interval string, startDate, endDate *time.Time) (*entities.NetWorth, error)
+		TakeSnapshots(ctx context.Context) (int64, error)
+	}
+
+	netWorthService struct {
+		snapshotRepo repositories.AccountBalanceSnapshotRepository
+		userRepo     repositories.UserRepository
+		currencyRepo repositories.CurrencyRepository
+	}
+)
+
+// NewNetWorthService creates a new net worth service
+func NewNetWorthService(
+	snapshotRepo repositories.AccountBalanceSnapshotRepository,
+	userRepo repositories.UserRepository,
+	currencyRepo repositories.CurrencyRepository,
+) NetWorthService {
+	return &netWorthService{
+		snapshotRepo: snapshotRepo,
+		userRepo:     userRepo,
+		currencyRepo: currencyRepo,
+	}
+}
+
+// GetNetWorth returns the net worth of a user over a period and the current breakdown per account,
+// in the user currency. The series defaults to the last 30 days.
+func (s *netWorthService) GetNetWorth(ctx context.Context, userID uuid.UUID, interval string, startDate, endDate *time.Time) (*entities.NetWorth, error) {
+	if interval == "" {
+		interval = entities.AnalyticsIntervalDay
+	}
+	if interval != entities.AnalyticsIntervalDay && interval != entities.AnalyticsIntervalWeek && interval != entities.AnalyticsIntervalMonth {
+		return nil, errors.New("invalid interval")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	periodEnd := today(user.Timezone)
+	if endDate != nil {
+		periodEnd = *endDate
+	}
+	periodStart := defaultSeriesStart(interval, periodEnd, defaultAnalyticsCalendar)
+	if startDate != nil {
+		periodStart = *startDate
+	}
+	if periodStart.After(periodEnd) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	points, err := s.snapshotRepo.FindNetWorthSeries(ctx, userID, user.CurrencyID, interval, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.snapshotRepo.FindCurrentBalances(ctx, userID, user.CurrencyID)
+	if err != nil {
+		return nil, err
+	}
+
+	netWorth := buildNetWorth(points, accounts)
+	netWorth.CurrencyID = user.CurrencyID
+	netWorth.Interval = interval
+	netWorth.StartDate = periodStart
+	netWorth.EndDate = periodEnd
+
+	currency, err := s.currencyRepo.FindByID(ctx, user.CurrencyID)
+	if err != nil {
+		return nil, err
+	}
+	if currency != nil {
+		netWorth.CurrencyCode = currency.CurrencyCode
+		netWorth.CurrencySymbol = currency.CurrencySymbol
+	}
+
+	return netWorth, nil
+}
+
+// TakeSnapshots backfills the days before the first snapshot of accounts with older transactions and records
+// today's balance of every active account.
+// It returns the number of snapshots written.
+func (s *netWorthService) TakeSnapshots(ctx context.Context) (int64, error) {
+	backfilled, err := s.snapshotRepo.BackfillMissingHistory(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshotted, err := s.snapshotRepo.SnapshotActiveAccounts(ctx)
+	if err != nil {
+		return backfilled, err
+	}
+
+	return backfilled + snapshotted, nil
+}
+
+// buildNetWorth derives the net worth of every point and the current breakdown
+func buildNetWorth(points []*entities.NetWorthPoint, accounts []*entities.NetWorthAccount) *entities.NetWorth {
+	netWorth := &entities.NetWorth{
+		Series:  make([]*entities.NetWorthPoint, 0, len(points)),
+		Current: buildNetWorthBreakdown(accounts),
+	}
+
+	for _, point := range points {
+		point.Assets = roundAmount(point.Assets)
+		point.Liabilities = roundAmount(point.Liabilities)
+		point.NetWorth = roundAmount(point.Assets - point.Liabilities)
+		netWorth.Series = append(netWorth.Series, point)
+	}
+	if len(netWorth.Series) > 1 {
+		first := netWorth.Series[0].NetWorth
+		last := netWorth.Series[len(netWorth.Series)-1].NetWorth
+		netWorth.Change = roundAmount(last - first)
+		netWorth.ChangePercentage = changePercentage(last, first)
+	}
+
+	return netWorth
+}
+
+// buildNetWorthBreakdown splits account balances into assets and liabilities.
+// Liability balances are amounts owed and are subtracted from the assets, balances that could not be converted are only counted.
+func buildNetWorthBreakdown(accounts []*entities.NetWorthAccount) *entities.NetWorthBreakdown {
+	breakdown := &entities.NetWorthBreakdown{
+		Assets:      make([]*entities.NetWorthAccount, 0),
+		Liabilities: make([]*entities.NetWorthAccount, 0),
+	}
+
+	for _, account := range accounts {
+		var convertedBalance float64
+		if account.ConvertedBalance != nil {
+			convertedBalance = roundAmount(*account.ConvertedBalance)
+			account.ConvertedBalance = &convertedBalance
+		} else {
+			breakdown.UnconvertedCount++
+		}
+
+		if slices.Contains(entities.LiabilityAccountTypes, int64(account.AccountType)) {
+			breakdown.Liabilities = append(breakdown.Liabilities, account)
+			breakdown.TotalLiabilities += convertedBalance
+		} else {
+			breakdown.Assets = append(breakdown.Assets, account)
+			breakdown.TotalAssets += convertedBalance
+		}
+	}
+	breakdown.TotalAssets = roundAmount(breakdown.TotalAssets)
+	breakdown.TotalLiabilities = roundAmount(breakdown.TotalLiabilities)
+	breakdown.NetWorth = roundAmount(breakdown.TotalAssets - breakdown.TotalLiabilities)
+
+	return breakdown
+}
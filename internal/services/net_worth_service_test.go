@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestBuildNetWorth(t *testing.T) {
+	t.Run("given assets and liabilities, when building the net worth, then liabilities are subtracted", func(t *testing.T) {
+		accounts := []*entities.NetWorthAccount{
+			{AccountName: "BCA", AccountType: entities.AccountTypeDebit, ConvertedBalance: convertedBalance(15000000)},
+			{AccountName: "Dompet", AccountType: entities.AccountTypeCash, ConvertedBalance: convertedBalance(500000)},
+			{AccountName: "Kartu Kredit", AccountType: entities.AccountTypeCredit, ConvertedBalance: convertedBalance(2500000)},
+			{AccountName: "KPR", AccountType: entities.AccountTypeLoan, ConvertedBalance: convertedBalance(10000000)},
+		}
+
+		netWorth := buildNetWorth(nil, accounts)
+
+		assert.Len(t, netWorth.Current.Assets, 2)
+		assert.Len(t, netWorth.Current.Liabilities, 2)
+		assert.Equal(t, 15500000.0, netWorth.Current.TotalAssets)
+		assert.Equal(t, 12500000.0, netWorth.Current.TotalLiabilities)
+		assert.Equal(t, 3000000.0, netWorth.Current.NetWorth)
+		assert.Empty(t, netWorth.Series)
+		assert.Nil(t, netWorth.ChangePercentage)
+	})
+
+	t.Run("given an account without an exchange rate, when building the net worth, then it is listed but left out of the totals", func(t *testing.T) {
+		accounts := []*entities.NetWorthAccount{
+			{AccountName: "BCA", AccountType: entities.AccountTypeDebit, ConvertedBalance: convertedBalance(15000000)},
+			{AccountName: "Wise USD", AccountType: entities.AccountTypeDebit, Balance: 1000},
+		}
+
+		netWorth := buildNetWorth(nil, accounts)
+
+		assert.Len(t, netWorth.Current.Assets, 2)
+		assert.Equal(t, 15000000.0, netWorth.Current.TotalAssets)
+		assert.Equal(t, 1, netWorth.Current.UnconvertedCount)
+		assert.Nil(t, netWorth.Current.Assets[1].ConvertedBalance)
+	})
+
+	t.Run("given a series of snapshots, when building the net worth, then the change is measured from the first point", func(t *testing.T) {
+		points := []*entities.NetWorthPoint{
+			{Date: time.Date(2025, time.May, 31, 0, 0, 0, 0, time.UTC), Assets: 12000000, Liabilities: 2000000},
+			{Date: time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC), Assets: 13500000, Liabilities: 1500000},
+		}
+
+		netWorth := buildNetWorth(points, nil)
+
+		assert.Equal(t, 10000000.0, netWorth.Series[0].NetWorth)
+		assert.Equal(t, 12000000.0, netWorth.Series[1].NetWorth)
+		assert.Equal(t, 2000000.0, netWorth.Change)
+		assert.Equal(t, 20.0, *netWorth.ChangePercentage)
+	})
+}
+
+// convertedBalance returns a converted balance as the repository scans it
+func convertedBalance(amount float64) *float64 {
+	return &amount
+}
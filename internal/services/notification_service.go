@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=notification_service.go -package=mock -destination=mock/notification_service_mock.go
+type (
+	NotificationService interface {
+		Notify(ctx context.Context, userID uuid.UUID, notificationType, title, body string, data map[string]interface{}) (*entities.Notification, error)
+		GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*entities.Notification, error)
+		MarkAsRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error
+		MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	}
+
+	notificationService struct {
+		notificationRepo repositories.NotificationRepository
+	}
+)
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repositories.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify creates an in-app notification for a user
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType, title, body string, data map[string]interface{}) (*entities.Notification, error) {
+	notification := &entities.Notification{
+		NotificationID:   uuid.New(),
+		UserID:           userID,
+		NotificationType: notificationType,
+		Title:            title,
+		Body:             body,
+		Data:             data,
+	}
+
+	createdNotification, err := s.notificationRepo.Create(ctx, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdNotification, nil
+}
+
+// GetNotifications returns the notifications of a user, newest first
+func (s *notificationService) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*entities.Notification, error) {
+	notifications, err := s.notificationRepo.FindByUser(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []*entities.Notification{}
+	}
+
+	return notifications, nil
+}
+
+// MarkAsRead marks a notification of a user as read
+func (s *notificationService) MarkAsRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	notification, err := s.notificationRepo.FindByID(ctx, notificationID)
+	if err != nil {
+		return err
+	}
+	if notification == nil {
+		return errorsutil.New(404, "notification not found")
+	}
+
+	if notification.UserID != userID {
+		return errorsutil.New(403, "access denied to notification")
+	}
+
+	err = s.notificationRepo.MarkAsRead(ctx, notificationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorsutil.New(404, "notification not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// MarkAllAsRead marks every unread notification of a user as read
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllAsRead(ctx, userID)
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=recurring_charge_service.go -package=mock -destination=mock/recurring_charge_service_mock.go
+type (
+	RecurringChargeService interface {
+		GetRecurringCharges(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.RecurringChargeSummary, error)
+		TrackRecurringCharge(ctx context.Context, userID uuid.UUID, input *entities.TrackRecurringChargeRequest) (*entities.RecurringCharge, error)
+	}
+
+	recurringChargeService struct {
+		recurringChargeRepo repositories.RecurringChargeRepository
+		authorizer          WorkspaceAuthorizer
+	}
+)
+
+// NewRecurringChargeService creates a new recurring charge service
+func NewRecurringChargeService(recurringChargeRepo repositories.RecurringChargeRepository, authorizer WorkspaceAuthorizer) RecurringChargeService {
+	return &recurringChargeService{
+		recurringChargeRepo: recurringChargeRepo,
+		authorizer:          authorizer,
+	}
+}
+
+// recurringChargeHistoryMonths is how far back charges are looked for, enough for two yearly charges
+const recurringChargeHistoryMonths = 25
+
+// recurringChargeMaxAmountChange is the largest change between two consecutive charges still considered the same charge
+const recurringChargeMaxAmountChange = 0.3
+
+// recurringCadence describes the gaps in days accepted for a recurrence interval
+type recurringCadence struct {
+	interval       int
+	minGap         int
+	maxGap         int
+	graceDays      int
+	minOccurrences int
+	monthlyFactor  float64
+}
+
+var recurringCadences = []recurringCadence{
+	{interval: entities.RecurrenceIntervalWeekly, minGap: 5, maxGap: 9, graceDays: 2, minOccurrences: 3, monthlyFactor: 52.0 / 12},
+	{interval: entities.RecurrenceIntervalMonthly, minGap: 26, maxGap: 35, graceDays: 5, minOccurrences: 3, monthlyFactor: 1},
+	{interval: entities.RecurrenceIntervalYearly, minGap: 350, maxGap: 380, graceDays: 14, minOccurrences: 2, monthlyFactor: 1.0 / 12},
+}
+
+// GetRecurringCharges detects the subscriptions and bills of a workspace from its expense history
+func (s *recurringChargeService) GetRecurringCharges(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.RecurringChargeSummary, error) {
+	workspace, charges, err := s.detect(ctx, userID, workspaceID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := buildRecurringChargeSummary(charges)
+	summary.WorkspaceID = workspaceID
+	summary.CurrencyID = workspace.CurrencyID
+
+	return summary, nil
+}
+
+// TrackRecurringCharge records a detected charge as a recurring transaction by flagging its latest charge
+// with the detected recurrence interval
+func (s *recurringChargeService) TrackRecurringCharge(ctx context.Context, userID uuid.UUID, input *entities.TrackRecurringChargeRequest) (*entities.RecurringCharge, error) {
+	_, charges, err := s.detect(ctx, userID, input.WorkspaceID, entities.WorkspacePermissionCreateTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, charge := range charges {
+		if charge.MerchantKey != input.MerchantKey {
+			continue
+		}
+
+		err := s.recurringChargeRepo.MarkRecurring(ctx, charge.LatestTransactionID, charge.RecurrenceInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		charge.IsTracked = true
+		return charge, nil
+	}
+
+	return nil, errorsutil.New(404, "recurring charge not found")
+}
+
+// detect checks the user's permission on the workspace and detects its recurring charges as of today in the workspace timezone
+func (s *recurringChargeService) detect(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, permission entities.WorkspacePermission) (*entities.Workspace, []*entities.RecurringCharge, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, permission)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := today(workspace.Timezone)
+	history, err := s.recurringChargeRepo.FindExpenseHistory(ctx, workspaceID, now.AddDate(0, -recurringChargeHistoryMonths, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return workspace, detectRecurringCharges(history, now), nil
+}
+
+// recurringChargeKey groups the charges of a merchant: by merchant when it is in the directory,
+// otherwise by the normalized merchant name or description
+func recurringChargeKey(transaction *entities.RecurringChargeTransaction) string {
+	if transaction.MerchantID != nil {
+		return "merchant:" + transaction.MerchantID.String()
+	}
+
+	name := ""
+	if transaction.MerchantName != nil {
+		name = NormalizeMerchantName(*transaction.MerchantName)
+	}
+	if name == "" {
+		name = NormalizeMerchantName(transaction.Description)
+	}
+	if name == "" {
+		return ""
+	}
+	return "name:" + name
+}
+
+// detectRecurringCharges finds the merchants charging a similar amount at a regular weekly, monthly or yearly interval
+func detectRecurringCharges(history []*entities.RecurringChargeTransaction, now time.Time) []*entities.RecurringCharge {
+	groups := make(map[string][]*entities.RecurringChargeTransaction)
+	keys := make([]string, 0)
+	for _, transaction := range history {
+		key := recurringChargeKey(transaction)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], transaction)
+	}
+
+	charges := make([]*entities.RecurringCharge, 0)
+	for _, key := range keys {
+		if charge := detectRecurringCharge(key, groups[key], now); charge != nil {
+			charges = append(charges, charge)
+		}
+	}
+
+	sort.SliceStable(charges, func(i, j int) bool {
+		if charges[i].MonthlyCost != charges[j].MonthlyCost {
+			return charges[i].MonthlyCost > charges[j].MonthlyCost
+		}
+		return charges[i].MerchantName < charges[j].MerchantName
+	})
+
+	return charges
+}
+
+// detectRecurringCharge checks whether the charges of one merchant recur. Most gaps between charges must fit
+// the same interval and every charge must be within 30% of the one before, so price changes are followed.
+func detectRecurringCharge(key string, transactions []*entities.RecurringChargeTransaction, now time.Time) *entities.RecurringCharge {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].TransactionDate.Before(transactions[j].TransactionDate)
+	})
+	if len(transactions) < 2 {
+		return nil
+	}
+
+	gaps := make([]float64, 0, len(transactions)-1)
+	for i := 1; i < len(transactions); i++ {
+		previous, current := transactions[i-1], transactions[i]
+		if previous.Amount <= 0 || math.Abs(current.Amount-previous.Amount) > previous.Amount*recurringChargeMaxAmountChange {
+			return nil
+		}
+		gaps = append(gaps, current.TransactionDate.Sub(previous.TransactionDate).Hours()/24)
+	}
+
+	medianGap := percentile(gaps, 50)
+	var cadence *recurringCadence
+	for i := range recurringCadences {
+		if medianGap >= float64(recurringCadences[i].minGap) && medianGap <= float64(recurringCadences[i].maxGap) {
+			cadence = &recurringCadences[i]
+			break
+		}
+	}
+	if cadence == nil || len(transactions) < cadence.minOccurrences {
+		return nil
+	}
+
+	regularGaps := 0
+	for _, gap := range gaps {
+		if gap >= float64(cadence.minGap) && gap <= float64(cadence.maxGap) {
+			regularGaps++
+		}
+	}
+	if float64(regularGaps) < 0.75*float64(len(gaps)) {
+		return nil
+	}
+
+	first := transactions[0]
+	latest := transactions[len(transactions)-1]
+	previous := transactions[len(transactions)-2]
+
+	var total float64
+	isTracked := false
+	for _, transaction := range transactions {
+		total += transaction.Amount
+		if transaction.IsRecurring {
+			isTracked = true
+		}
+	}
+
+	name := latest.Description
+	if latest.MerchantName != nil && *latest.MerchantName != "" {
+		name = *latest.MerchantName
+	}
+
+	charge := &entities.RecurringCharge{
+		MerchantKey:         key,
+		MerchantID:          latest.MerchantID,
+		MerchantName:        name,
+		CategoryID:          latest.CategoryID,
+		AccountID:           latest.AccountID,
+		RecurrenceInterval:  cadence.interval,
+		Occurrences:         len(transactions),
+		AverageAmount:       roundAmount(total / float64(len(transactions))),
+		LatestAmount:        roundAmount(latest.Amount),
+		PreviousAmount:      roundAmount(previous.Amount),
+		MonthlyCost:         roundAmount(latest.Amount * cadence.monthlyFactor),
+		FirstChargeDate:     first.TransactionDate,
+		LastChargeDate:      latest.TransactionDate,
+		NextExpectedDate:    nextRecurringChargeDate(cadence.interval, latest.TransactionDate),
+		Status:              entities.RecurringChargeStatusActive,
+		IsTracked:           isTracked,
+		LatestTransactionID: latest.TransactionID,
+	}
+
+	if roundAmount(latest.Amount) > roundAmount(previous.Amount) {
+		charge.PriceChange = changePercentage(latest.Amount, previous.Amount)
+	}
+
+	if overdue := int(now.Sub(charge.NextExpectedDate).Hours() / 24); overdue > cadence.graceDays {
+		charge.DaysOverdue = overdue
+		charge.Status = entities.RecurringChargeStatusLate
+		if !now.Before(nextRecurringChargeDate(cadence.interval, charge.NextExpectedDate)) {
+			charge.Status = entities.RecurringChargeStatusMissed
+		}
+	}
+
+	return charge
+}
+
+// nextRecurringChargeDate returns the date a charge is expected after date
+func nextRecurringChargeDate(interval int, date time.Time) time.Time {
+	switch interval {
+	case entities.RecurrenceIntervalWeekly:
+		return date.AddDate(0, 0, 7)
+	case entities.RecurrenceIntervalYearly:
+		return date.AddDate(1, 0, 0)
+	default:
+		return date.AddDate(0, 1, 0)
+	}
+}
+
+// buildRecurringChargeSummary counts the charges by status. Missed charges are likely cancelled and left out of the monthly cost.
+func buildRecurringChargeSummary(charges []*entities.RecurringCharge) *entities.RecurringChargeSummary {
+	summary := &entities.RecurringChargeSummary{
+		Charges: charges,
+	}
+
+	for _, charge := range charges {
+		switch charge.Status {
+		case entities.RecurringChargeStatusActive:
+			summary.ActiveCount++
+		case entities.RecurringChargeStatusLate:
+			summary.LateCount++
+		case entities.RecurringChargeStatusMissed:
+			summary.MissedCount++
+		}
+		if charge.Status != entities.RecurringChargeStatusMissed {
+			summary.TotalMonthlyCost += charge.MonthlyCost
+		}
+		if charge.PriceChange != nil {
+			summary.PriceIncreaseCount++
+		}
+		if !charge.IsTracked {
+			summary.UntrackedCount++
+		}
+	}
+	summary.TotalMonthlyCost = roundAmount(summary.TotalMonthlyCost)
+
+	return summary
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func newRecurringChargeTransaction(merchantName string, amount float64, date time.Time) *entities.RecurringChargeTransaction {
+	return &entities.RecurringChargeTransaction{
+		TransactionID:   uuid.New(),
+		MerchantName:    &merchantName,
+		Description:     "Langganan",
+		Amount:          amount,
+		TransactionDate: date,
+	}
+}
+
+func monthlyCharges(merchantName string, amounts ...float64) []*entities.RecurringChargeTransaction {
+	transactions := make([]*entities.RecurringChargeTransaction, 0, len(amounts))
+	for i, amount := range amounts {
+		transactions = append(transactions, newRecurringChargeTransaction(merchantName, amount, time.Date(2025, time.January+time.Month(i), 5, 0, 0, 0, 0, time.UTC)))
+	}
+	return transactions
+}
+
+func TestDetectRecurringCharges(t *testing.T) {
+	t.Run("given monthly charges of the same amount, when detecting, then a monthly charge is found", func(t *testing.T) {
+		history := monthlyCharges("NETFLIX.COM", 54000, 54000, 54000, 54000)
+
+		charges := detectRecurringCharges(history, time.Date(2025, time.April, 20, 0, 0, 0, 0, time.UTC))
+
+		assert.Len(t, charges, 1)
+		assert.Equal(t, "name:netflix com", charges[0].MerchantKey)
+		assert.Equal(t, entities.RecurrenceIntervalMonthly, charges[0].RecurrenceInterval)
+		assert.Equal(t, 4, charges[0].Occurrences)
+		assert.Equal(t, 54000.0, charges[0].MonthlyCost)
+		assert.Equal(t, time.Date(2025, time.May, 5, 0, 0, 0, 0, time.UTC), charges[0].NextExpectedDate)
+		assert.Equal(t, entities.RecurringChargeStatusActive, charges[0].Status)
+		assert.Nil(t, charges[0].PriceChange)
+		assert.Equal(t, history[3].TransactionID, charges[0].LatestTransactionID)
+	})
+
+	t.Run("given a higher latest charge, when detecting, then the price increase is reported", func(t *testing.T) {
+		charges := detectRecurringCharges(monthlyCharges("Spotify", 54990, 54990, 64990), time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC))
+
+		assert.Len(t, charges, 1)
+		assert.Equal(t, 18.19, *charges[0].PriceChange)
+		assert.Equal(t, 64990.0, charges[0].LatestAmount)
+		assert.Equal(t, 54990.0, charges[0].PreviousAmount)
+	})
+
+	t.Run("given an overdue charge, when detecting, then it is late and later missed", func(t *testing.T) {
+		history := monthlyCharges("Gym", 350000, 350000, 350000)
+
+		late := detectRecurringCharges(history, time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC))
+		missed := detectRecurringCharges(history, time.Date(2025, time.May, 10, 0, 0, 0, 0, time.UTC))
+
+		assert.Equal(t, entities.RecurringChargeStatusLate, late[0].Status)
+		assert.Equal(t, 10, late[0].DaysOverdue)
+		assert.Equal(t, entities.RecurringChargeStatusMissed, missed[0].Status)
+		assert.Equal(t, 0.0, buildRecurringChargeSummary(missed).TotalMonthlyCost)
+	})
+
+	t.Run("given weekly and yearly charges, when detecting, then their monthly cost is normalized", func(t *testing.T) {
+		history := []*entities.RecurringChargeTransaction{
+			newRecurringChargeTransaction("iCloud", 1200000, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)),
+			newRecurringChargeTransaction("iCloud", 1200000, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+		}
+		for day := 3; day <= 24; day += 7 {
+			history = append(history, newRecurringChargeTransaction("Laundry", 60000, time.Date(2025, time.March, day, 0, 0, 0, 0, time.UTC)))
+		}
+
+		charges := detectRecurringCharges(history, time.Date(2025, time.March, 26, 0, 0, 0, 0, time.UTC))
+		summary := buildRecurringChargeSummary(charges)
+
+		assert.Len(t, charges, 2)
+		assert.Equal(t, entities.RecurrenceIntervalWeekly, charges[0].RecurrenceInterval)
+		assert.Equal(t, 260000.0, charges[0].MonthlyCost)
+		assert.Equal(t, entities.RecurrenceIntervalYearly, charges[1].RecurrenceInterval)
+		assert.Equal(t, 100000.0, charges[1].MonthlyCost)
+		assert.Equal(t, 360000.0, summary.TotalMonthlyCost)
+		assert.Equal(t, 2, summary.UntrackedCount)
+	})
+
+	t.Run("given irregular dates or amounts, when detecting, then nothing is found", func(t *testing.T) {
+		history := []*entities.RecurringChargeTransaction{
+			newRecurringChargeTransaction("Indomaret", 50000, time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)),
+			newRecurringChargeTransaction("Indomaret", 52000, time.Date(2025, time.January, 4, 0, 0, 0, 0, time.UTC)),
+			newRecurringChargeTransaction("Indomaret", 48000, time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC)),
+		}
+		history = append(history, monthlyCharges("PLN", 300000, 650000, 310000)...)
+
+		assert.Empty(t, detectRecurringCharges(history, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)))
+	})
+}
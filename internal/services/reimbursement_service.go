@@ -0,0 +1,667 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=reimbursement_service.go -package=mock -destination=mock/reimbursement_service_mock.go
+type (
+	ReimbursementService interface {
+		GetClaims(ctx context.Context, userID uuid.UUID, params *entities.ReimbursementClaimListParams, limit, offset int) ([]*entities.ReimbursementClaim, int64, error)
+		GetClaimByID(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error)
+		CreateClaim(ctx context.Context, userID uuid.UUID, input *entities.CreateReimbursementClaimRequest) (*entities.ReimbursementClaim, error)
+		UpdateClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.UpdateReimbursementClaimRequest) (*entities.ReimbursementClaim, error)
+		DeleteClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) error
+		SubmitClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error)
+		ApproveClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error)
+		RejectClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.RejectReimbursementClaimRequest) (*entities.ReimbursementClaim, error)
+		PayClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.PayReimbursementClaimRequest) (*entities.ReimbursementClaim, error)
+		GetBalances(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.ReimbursementBalance, error)
+		ExportClaimPDF(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) ([]byte, error)
+	}
+
+	reimbursementService struct {
+		reimbursementRepo   repositories.ReimbursementRepository
+		transactionRepo     repositories.TransactionRepository
+		rollupRepo          repositories.TransactionRollupRepository
+		accountRepo         repositories.AccountRepository
+		memberRepo          repositories.WorkspaceMemberRepository
+		currencyRepo        repositories.CurrencyRepository
+		authorizer          WorkspaceAuthorizer
+		notificationService NotificationService
+		events              DomainEventRecorder
+	}
+)
+
+// NewReimbursementService creates a new reimbursement service
+func NewReimbursementService(
+	reimbursementRepo repositories.ReimbursementRepository,
+	transactionRepo repositories.TransactionRepository,
+	rollupRepo repositories.TransactionRollupRepository,
+	accountRepo repositories.AccountRepository,
+	memberRepo repositories.WorkspaceMemberRepository,
+	currencyRepo repositories.CurrencyRepository,
+	authorizer WorkspaceAuthorizer,
+	notificationService NotificationService,
+	events DomainEventRecorder,
+) ReimbursementService {
+	return &reimbursementService{
+		reimbursementRepo:   reimbursementRepo,
+		transactionRepo:     transactionRepo,
+		rollupRepo:          rollupRepo,
+		accountRepo:         accountRepo,
+		memberRepo:          memberRepo,
+		currencyRepo:        currencyRepo,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		events:              events,
+	}
+}
+
+// GetClaims returns the claims of a workspace. Approvers see every claim, other members only their own.
+func (s *reimbursementService) GetClaims(ctx context.Context, userID uuid.UUID, params *entities.ReimbursementClaimListParams, limit, offset int) ([]*entities.ReimbursementClaim, int64, error) {
+	workspace, err := s.authorizer.Authorize(ctx, params.WorkspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !roleHasPermission(workspace.Role, entities.WorkspacePermissionApproveExpense) {
+		params.ClaimantID = &userID
+	}
+
+	claims, err := s.reimbursementRepo.FindByWorkspace(ctx, params, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.reimbursementRepo.CountByWorkspace(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return claims, total, nil
+}
+
+// GetClaimByID returns a claim with its expenses to its claimant or an approver
+func (s *reimbursementService) GetClaimByID(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, err := s.findVisibleClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	claim.Items, err = s.reimbursementRepo.FindItems(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+// CreateClaim drafts a claim of the user's own expenses, or submits it right away
+func (s *reimbursementService) CreateClaim(ctx context.Context, userID uuid.UUID, input *entities.CreateReimbursementClaimRequest) (*entities.ReimbursementClaim, error) {
+	workspace, err := s.authorizer.Authorize(ctx, input.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.WorkspaceType != entities.WorkspaceTypeBusiness {
+		return nil, errors.New("reimbursement claims are only available in business workspaces")
+	}
+
+	claim := &entities.ReimbursementClaim{
+		ClaimID:     uuid.New(),
+		WorkspaceID: input.WorkspaceID,
+		ClaimantID:  userID,
+		Status:      entities.ReimbursementStatusDraft,
+	}
+	items, err := s.prepareClaim(ctx, claim, input.Title, input.Notes, input.PayoutAccountID, input.Items)
+	if err != nil {
+		return nil, err
+	}
+	if input.Submit {
+		if len(items) == 0 {
+			return nil, errors.New("claim has no expenses")
+		}
+		now := time.Now()
+		claim.Status = entities.ReimbursementStatusSubmitted
+		claim.SubmittedAt = &now
+	}
+
+	createdClaim, err := s.reimbursementRepo.Create(ctx, claim, items)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNoExchangeRate) {
+			return nil, errorsutil.New(400, err.Error())
+		}
+		return nil, err
+	}
+
+	s.recordClaimEvent(ctx, userID, entities.ActivityActionCreated, &createdClaim)
+	if createdClaim.Status == entities.ReimbursementStatusSubmitted {
+		s.notifyApprovers(ctx, workspace, &createdClaim)
+	}
+
+	return s.withItems(ctx, &createdClaim)
+}
+
+// UpdateClaim replaces the details and expenses of a draft or rejected claim, a rejected claim goes back to draft
+func (s *reimbursementService) UpdateClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.UpdateReimbursementClaimRequest) (*entities.ReimbursementClaim, error) {
+	claim, _, err := s.findOwnEditableClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.prepareClaim(ctx, claim, input.Title, input.Notes, input.PayoutAccountID, input.Items)
+	if err != nil {
+		return nil, err
+	}
+	claim.Status = entities.ReimbursementStatusDraft
+	claim.SubmittedAt = nil
+
+	updatedClaim, err := s.reimbursementRepo.Update(ctx, claim, items)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "claim can no longer be changed")
+		}
+		if errors.Is(err, repositories.ErrNoExchangeRate) {
+			return nil, errorsutil.New(400, err.Error())
+		}
+		return nil, err
+	}
+
+	s.recordClaimEvent(ctx, userID, entities.ActivityActionUpdated, &updatedClaim)
+	return s.withItems(ctx, &updatedClaim)
+}
+
+// DeleteClaim deletes a draft or rejected claim, its expenses can then be claimed again
+func (s *reimbursementService) DeleteClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) error {
+	claim, _, err := s.findOwnEditableClaim(ctx, userID, claimID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reimbursementRepo.Delete(ctx, claimID); err != nil {
+		if err == sql.ErrNoRows {
+			return errorsutil.New(409, "claim can no longer be changed")
+		}
+		return err
+	}
+
+	s.recordClaimEvent(ctx, userID, entities.ActivityActionDeleted, claim)
+	return nil
+}
+
+// SubmitClaim submits a draft or rejected claim for review, the approvers of the workspace are notified
+func (s *reimbursementService) SubmitClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, workspace, err := s.findOwnEditableClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.reimbursementRepo.FindItems(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("claim has no expenses")
+	}
+
+	fromStatus := claim.Status
+	now := time.Now()
+	claim.Status = entities.ReimbursementStatusSubmitted
+	claim.SubmittedAt = &now
+
+	updatedClaim, err := s.moveClaim(ctx, userID, claim, fromStatus, entities.ActivityActionSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyApprovers(ctx, workspace, updatedClaim)
+	return updatedClaim, nil
+}
+
+// ApproveClaim approves a submitted claim, it then waits for payment
+func (s *reimbursementService) ApproveClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, err := s.findReviewableClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claim.Status = entities.ReimbursementStatusApproved
+	claim.ReviewedBy = &userID
+	claim.ReviewedAt = &now
+	claim.ReviewNote = nil
+
+	updatedClaim, err := s.moveClaim(ctx, userID, claim, entities.ReimbursementStatusSubmitted, entities.ActivityActionApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyClaimant(ctx, updatedClaim, "Klaim reimbursement disetujui",
+		fmt.Sprintf("Klaim \"%s\" sebesar %s telah disetujui", updatedClaim.Title, formatAmount("", updatedClaim.TotalAmount)))
+	return updatedClaim, nil
+}
+
+// RejectClaim rejects a submitted claim with the reason, its claimant may edit and submit it again
+func (s *reimbursementService) RejectClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.RejectReimbursementClaimRequest) (*entities.ReimbursementClaim, error) {
+	note := strings.TrimSpace(input.Note)
+	if note == "" {
+		return nil, errors.New("rejection note is required")
+	}
+
+	claim, err := s.findReviewableClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claim.Status = entities.ReimbursementStatusRejected
+	claim.ReviewedBy = &userID
+	claim.ReviewedAt = &now
+	claim.ReviewNote = &note
+
+	updatedClaim, err := s.moveClaim(ctx, userID, claim, entities.ReimbursementStatusSubmitted, entities.ActivityActionRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyClaimant(ctx, updatedClaim, "Klaim reimbursement ditolak",
+		fmt.Sprintf("Klaim \"%s\" ditolak: %s", updatedClaim.Title, note))
+	return updatedClaim, nil
+}
+
+// PayClaim pays an approved claim by a transfer from a company account to the claimant's payout account.
+// The expenses of the claim are marked as reimbursed together with the claim.
+func (s *reimbursementService) PayClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID, input *entities.PayReimbursementClaimRequest) (*entities.ReimbursementClaim, error) {
+	claim, err := s.findClaim(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizer.Authorize(ctx, claim.WorkspaceID, userID, entities.WorkspacePermissionApproveExpense); err != nil {
+		return nil, err
+	}
+	if claim.Status != entities.ReimbursementStatusApproved {
+		return nil, errorsutil.New(409, "claim is not approved")
+	}
+
+	// The company account belongs to the paying approver
+	account, err := s.accountRepo.FindByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errorsutil.New(404, "account not found")
+	}
+	if account.UserID != userID {
+		return nil, errorsutil.New(403, "access denied to account")
+	}
+	if account.AccountID == claim.PayoutAccountID {
+		return nil, errors.New("payment account must be different from the payout account")
+	}
+
+	paymentDate := time.Now()
+	if input.PaymentDate != nil && !input.PaymentDate.IsZero() {
+		paymentDate = *input.PaymentDate
+	}
+
+	now := time.Now()
+	claim.Status = entities.ReimbursementStatusPaid
+	claim.PaidBy = &userID
+	claim.PaidAt = &now
+
+	// The transfer, the claim and its expenses are written together. A transfer between accounts never counts as
+	// an expense, the claimed expenses already do.
+	transfer, err := s.reimbursementRepo.MarkPaid(ctx, claim, &entities.Transaction{
+		TransactionID:     uuid.New(),
+		WorkspaceID:       &claim.WorkspaceID,
+		AccountID:         &account.AccountID,
+		Description:       "Reimbursement: " + claim.Title,
+		Amount:            claim.TotalAmount,
+		TransactionType:   entities.TransactionTypeExpense,
+		PaymentMethod:     entities.PaymentMethodTransfer,
+		TransactionDate:   paymentDate,
+		TransferAccountID: &claim.PayoutAccountID,
+		CreatedBy:         &userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "claim status has changed")
+		}
+		return nil, err
+	}
+
+	updatedClaim, err := s.findClaim(ctx, claim.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+	if updatedClaim, err = s.withItems(ctx, updatedClaim); err != nil {
+		return nil, err
+	}
+
+	// A failed refresh is repaired later by the rollup consistency check
+	dates := []time.Time{transfer.TransactionDate}
+	for _, item := range updatedClaim.Items {
+		dates = append(dates, item.TransactionDate)
+	}
+	_ = s.rollupRepo.RefreshDays(ctx, claim.WorkspaceID, dates)
+
+	s.events.Record(ctx, &entities.DomainEvent{
+		WorkspaceID:  claim.WorkspaceID,
+		ActorID:      &userID,
+		Action:       entities.ActivityActionCreated,
+		ResourceType: entities.ActivityResourceTransaction,
+		ResourceID:   &transfer.TransactionID,
+		ResourceName: transfer.Description,
+		Amount:       &transfer.Amount,
+		Detail:       transfer.TransactionType,
+		NewValues:    &transfer,
+	})
+	s.recordClaimEvent(ctx, userID, entities.ActivityActionPaid, updatedClaim)
+
+	s.notifyClaimant(ctx, updatedClaim, "Klaim reimbursement dibayar",
+		fmt.Sprintf("Klaim \"%s\" sebesar %s telah ditransfer ke %s", updatedClaim.Title, formatAmount("", updatedClaim.TotalAmount), updatedClaim.PayoutAccountName))
+	return updatedClaim, nil
+}
+
+// GetBalances returns what the workspace owes every claimant. Members who are not approvers only see their own balance.
+func (s *reimbursementService) GetBalances(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.ReimbursementBalance, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.reimbursementRepo.FindBalances(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if roleHasPermission(workspace.Role, entities.WorkspacePermissionApproveExpense) {
+		return balances, nil
+	}
+
+	own := make([]*entities.ReimbursementBalance, 0, 1)
+	for _, balance := range balances {
+		if balance.ClaimantID == userID {
+			own = append(own, balance)
+		}
+	}
+	return own, nil
+}
+
+// ExportClaimPDF renders the summary of a claim for finance
+func (s *reimbursementService) ExportClaimPDF(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) ([]byte, error) {
+	claim, err := s.findVisibleClaim(ctx, userID, claimID)
+	if err != nil {
+		return nil, err
+	}
+	claim.Items, err = s.reimbursementRepo.FindItems(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, claim.WorkspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	currencySymbol := ""
+	currency, err := s.currencyRepo.FindByID(ctx, workspace.CurrencyID)
+	if err != nil {
+		return nil, err
+	}
+	if currency != nil {
+		currencySymbol = currency.CurrencySymbol
+	}
+
+	return RenderReimbursementClaimPDF(claim, workspace.Name, currencySymbol), nil
+}
+
+// prepareClaim sets the details of a claim and builds its expenses from the input after validating them
+func (s *reimbursementService) prepareClaim(ctx context.Context, claim *entities.ReimbursementClaim, title, notes string, payoutAccountID uuid.UUID, inputs []entities.ReimbursementClaimItemInput) ([]*entities.ReimbursementClaimItem, error) {
+	claim.Title = strings.TrimSpace(title)
+	if claim.Title == "" {
+		return nil, errors.New("title is required")
+	}
+	claim.Notes = nil
+	if trimmed := strings.TrimSpace(notes); trimmed != "" {
+		claim.Notes = &trimmed
+	}
+
+	// The payment is sent to an account of the claimant
+	payoutAccount, err := s.accountRepo.FindByID(ctx, payoutAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if payoutAccount == nil {
+		return nil, errorsutil.New(404, "account not found")
+	}
+	if payoutAccount.UserID != claim.ClaimantID {
+		return nil, errors.New("payout account must be your own account")
+	}
+	claim.PayoutAccountID = payoutAccountID
+
+	if len(inputs) > entities.ReimbursementClaimMaxItems {
+		return nil, errors.New("too many expenses in claim")
+	}
+
+	transactions := make([]*entities.Transaction, len(inputs))
+	transactionIDs := make([]uuid.UUID, 0, len(inputs))
+	for i, input := range inputs {
+		transaction, err := s.transactionRepo.FindByID(ctx, input.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		transactions[i] = transaction
+		transactionIDs = append(transactionIDs, input.TransactionID)
+	}
+	if err := validateReimbursementClaimItems(claim.WorkspaceID, claim.ClaimantID, inputs, transactions); err != nil {
+		return nil, err
+	}
+
+	if len(transactionIDs) > 0 {
+		claimed, err := s.reimbursementRepo.FindClaimedTransactionIDs(ctx, transactionIDs, claim.ClaimID)
+		if err != nil {
+			return nil, err
+		}
+		if len(claimed) > 0 {
+			return nil, errorsutil.New(409, "expense is already claimed")
+		}
+	}
+
+	items := make([]*entities.ReimbursementClaimItem, 0, len(inputs))
+	for i, input := range inputs {
+		item := &entities.ReimbursementClaimItem{
+			ClaimItemID:   uuid.New(),
+			ClaimID:       claim.ClaimID,
+			TransactionID: input.TransactionID,
+			ReceiptURL:    transactions[i].ReceiptURL,
+		}
+		if receiptURL := strings.TrimSpace(input.ReceiptURL); receiptURL != "" {
+			item.ReceiptURL = &receiptURL
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// findClaim returns a claim or a not found error
+func (s *reimbursementService) findClaim(ctx context.Context, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, err := s.reimbursementRepo.FindByID(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if claim == nil {
+		return nil, errorsutil.New(404, "claim not found")
+	}
+	return claim, nil
+}
+
+// findVisibleClaim returns a claim the user may see: their own, or any claim for an approver
+func (s *reimbursementService) findVisibleClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, err := s.findClaim(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, claim.WorkspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+	if claim.ClaimantID != userID && !roleHasPermission(workspace.Role, entities.WorkspacePermissionApproveExpense) {
+		return nil, errorsutil.New(403, "access denied to claim")
+	}
+	return claim, nil
+}
+
+// findOwnEditableClaim returns a draft or rejected claim of the user
+func (s *reimbursementService) findOwnEditableClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, *entities.Workspace, error) {
+	claim, err := s.findClaim(ctx, claimID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, claim.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claim.ClaimantID != userID {
+		return nil, nil, errorsutil.New(403, "only the claimant can change a claim")
+	}
+	if claim.Status != entities.ReimbursementStatusDraft && claim.Status != entities.ReimbursementStatusRejected {
+		return nil, nil, errorsutil.New(409, "claim can no longer be changed")
+	}
+	return claim, workspace, nil
+}
+
+// findReviewableClaim returns a submitted claim the user may approve or reject
+func (s *reimbursementService) findReviewableClaim(ctx context.Context, userID uuid.UUID, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	claim, err := s.findClaim(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, claim.WorkspaceID, userID, entities.WorkspacePermissionApproveExpense)
+	if err != nil {
+		return nil, err
+	}
+	if !canDecideApproval(workspace.Role, userID, &claim.ClaimantID) {
+		return nil, errorsutil.New(403, "cannot review your own claim")
+	}
+	if claim.Status != entities.ReimbursementStatusSubmitted {
+		return nil, errorsutil.New(409, "claim is not waiting for review")
+	}
+	return claim, nil
+}
+
+// moveClaim writes the new status of a claim and returns it with its expenses
+func (s *reimbursementService) moveClaim(ctx context.Context, userID uuid.UUID, claim *entities.ReimbursementClaim, fromStatus int, action string) (*entities.ReimbursementClaim, error) {
+	if err := s.reimbursementRepo.UpdateStatus(ctx, claim, fromStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "claim status has changed")
+		}
+		return nil, err
+	}
+
+	updatedClaim, err := s.findClaim(ctx, claim.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordClaimEvent(ctx, userID, action, updatedClaim)
+	return s.withItems(ctx, updatedClaim)
+}
+
+// withItems loads the expenses of a claim
+func (s *reimbursementService) withItems(ctx context.Context, claim *entities.ReimbursementClaim) (*entities.ReimbursementClaim, error) {
+	items, err := s.reimbursementRepo.FindItems(ctx, claim.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+	claim.Items = items
+	return claim, nil
+}
+
+// notifyApprovers tells the approvers of a workspace that a claim waits for their review
+func (s *reimbursementService) notifyApprovers(ctx context.Context, workspace *entities.Workspace, claim *entities.ReimbursementClaim) {
+	if !workspace.Settings.Notifications.ApprovalRequests {
+		return
+	}
+
+	members, err := s.memberRepo.FindByWorkspace(ctx, workspace.WorkspaceID)
+	if err != nil {
+		return
+	}
+
+	for _, member := range members {
+		if !roleHasPermission(member.Role, entities.WorkspacePermissionApproveExpense) || !canDecideApproval(member.Role, member.UserID, &claim.ClaimantID) {
+			continue
+		}
+		_, _ = s.notificationService.Notify(ctx, member.UserID, entities.NotificationTypeReimbursementClaim,
+			"Klaim reimbursement baru",
+			fmt.Sprintf("%s mengajukan klaim \"%s\" sebesar %s", claim.ClaimantName, claim.Title, formatAmount("", claim.TotalAmount)),
+			map[string]interface{}{
+				"claim_id":     claim.ClaimID.String(),
+				"workspace_id": workspace.WorkspaceID.String(),
+			},
+		)
+	}
+}
+
+// notifyClaimant tells the claimant about a review or payment of their claim
+func (s *reimbursementService) notifyClaimant(ctx context.Context, claim *entities.ReimbursementClaim, title, body string) {
+	_, _ = s.notificationService.Notify(ctx, claim.ClaimantID, entities.NotificationTypeReimbursementClaim, title, body,
+		map[string]interface{}{
+			"claim_id":     claim.ClaimID.String(),
+			"workspace_id": claim.WorkspaceID.String(),
+			"status":       claim.Status,
+		},
+	)
+}
+
+// recordClaimEvent records a claim change in the audit log and the activity feed of its workspace
+func (s *reimbursementService) recordClaimEvent(ctx context.Context, userID uuid.UUID, action string, claim *entities.ReimbursementClaim) {
+	s.events.Record(ctx, &entities.DomainEvent{
+		WorkspaceID:  claim.WorkspaceID,
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: entities.ActivityResourceReimbursement,
+		ResourceID:   &claim.ClaimID,
+		ResourceName: claim.Title,
+		Amount:       &claim.TotalAmount,
+		NewValues:    claim,
+	})
+}
+
+// validateReimbursementClaimItems checks the expenses of a claim, transactions holds the transaction of every input
+// in the same order, nil when it does not exist. Only the claimant's own expenses that count can be claimed, once.
+func validateReimbursementClaimItems(workspaceID uuid.UUID, claimantID uuid.UUID, inputs []entities.ReimbursementClaimItemInput, transactions []*entities.Transaction) error {
+	seen := make(map[uuid.UUID]bool, len(inputs))
+	for i, input := range inputs {
+		if seen[input.TransactionID] {
+			return errors.New("duplicate expense in claim")
+		}
+		seen[input.TransactionID] = true
+
+		transaction := transactions[i]
+		if transaction == nil || transaction.WorkspaceID == nil || *transaction.WorkspaceID != workspaceID {
+			return errorsutil.New(404, "transaction not found")
+		}
+		if transaction.TransactionType != entities.TransactionTypeExpense || transaction.TransferAccountID != nil {
+			return errors.New("only expenses can be claimed")
+		}
+		if transaction.CreatedBy == nil || *transaction.CreatedBy != claimantID {
+			return errors.New("only your own expenses can be claimed")
+		}
+		if transaction.ApprovalStatus != entities.ApprovalStatusNotRequired && transaction.ApprovalStatus != entities.ApprovalStatusApproved {
+			return errors.New("only approved expenses can be claimed")
+		}
+	}
+	return nil
+}
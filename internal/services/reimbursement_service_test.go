@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+func TestValidateReimbursementClaimItems(t *testing.T) {
+	workspaceID := uuid.New()
+	claimantID := uuid.New()
+	expense := func(approvalStatus int) *entities.Transaction {
+		return &entities.Transaction{
+			TransactionID:   uuid.New(),
+			WorkspaceID:     &workspaceID,
+			TransactionType: entities.TransactionTypeExpense,
+			CreatedBy:       &claimantID,
+			ApprovalStatus:  approvalStatus,
+		}
+	}
+	inputsFor := func(transactions ...*entities.Transaction) []entities.ReimbursementClaimItemInput {
+		inputs := make([]entities.ReimbursementClaimItemInput, 0, len(transactions))
+		for _, transaction := range transactions {
+			inputs = append(inputs, entities.ReimbursementClaimItemInput{TransactionID: transaction.TransactionID})
+		}
+		return inputs
+	}
+
+	t.Run("given the claimant's own approved or unreviewed expenses, when validating, then they can be claimed", func(t *testing.T) {
+		transactions := []*entities.Transaction{expense(entities.ApprovalStatusNotRequired), expense(entities.ApprovalStatusApproved)}
+		assert.NoError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions))
+	})
+
+	t.Run("given the same expense twice, when validating, then the claim is refused", func(t *testing.T) {
+		transaction := expense(entities.ApprovalStatusNotRequired)
+		transactions := []*entities.Transaction{transaction, transaction}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions), "duplicate expense in claim")
+	})
+
+	t.Run("given an expense of another workspace or a missing one, when validating, then it is not found", func(t *testing.T) {
+		transaction := expense(entities.ApprovalStatusNotRequired)
+		otherWorkspaceID := uuid.New()
+		transaction.WorkspaceID = &otherWorkspaceID
+		transactions := []*entities.Transaction{transaction}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions), "transaction not found")
+
+		inputs := []entities.ReimbursementClaimItemInput{{TransactionID: uuid.New()}}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputs, []*entities.Transaction{nil}), "transaction not found")
+	})
+
+	t.Run("given income or a transfer, when validating, then only expenses can be claimed", func(t *testing.T) {
+		income := expense(entities.ApprovalStatusNotRequired)
+		income.TransactionType = entities.TransactionTypeIncome
+		transactions := []*entities.Transaction{income}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions), "only expenses can be claimed")
+
+		transfer := expense(entities.ApprovalStatusNotRequired)
+		accountID := uuid.New()
+		transfer.TransferAccountID = &accountID
+		transactions = []*entities.Transaction{transfer}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions), "only expenses can be claimed")
+	})
+
+	t.Run("given an expense recorded by someone else, when validating, then it cannot be claimed", func(t *testing.T) {
+		transactions := []*entities.Transaction{expense(entities.ApprovalStatusNotRequired)}
+		assert.EqualError(t, validateReimbursementClaimItems(workspaceID, uuid.New(), inputsFor(transactions...), transactions), "only your own expenses can be claimed")
+	})
+
+	t.Run("given a pending, rejected or reimbursed expense, when validating, then it cannot be claimed", func(t *testing.T) {
+		for _, status := range []int{entities.ApprovalStatusDraft, entities.ApprovalStatusSubmitted, entities.ApprovalStatusRejected, entities.ApprovalStatusReimbursed} {
+			transactions := []*entities.Transaction{expense(status)}
+			assert.EqualError(t, validateReimbursementClaimItems(workspaceID, claimantID, inputsFor(transactions...), transactions), "only approved expenses can be claimed")
+		}
+	})
+}
+
+func TestRenderReimbursementClaimPDF(t *testing.T) {
+	t.Run("given a paid claim with expenses, when rendering, then a PDF document is returned", func(t *testing.T) {
+		paidAt := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+		merchant := "Grab"
+		claim := &entities.ReimbursementClaim{
+			Title:             "Client visit Surabaya",
+			ClaimantName:      "Budi",
+			PayoutAccountName: "BCA Budi",
+			TotalAmount:       350000,
+			Status:            entities.ReimbursementStatusPaid,
+			PaidAt:            &paidAt,
+			Items: []*entities.ReimbursementClaimItem{
+				{Description: "Taxi to airport", Amount: 150000, TransactionDate: paidAt, MerchantName: &merchant},
+				{Description: "Lunch with client", Amount: 200000, TransactionDate: paidAt},
+			},
+		}
+
+		document := RenderReimbursementClaimPDF(claim, "PT Maju", "Rp")
+		assert.True(t, bytes.HasPrefix(document, []byte("%PDF")))
+	})
+
+	t.Run("given each claim status, when labelling, then a readable label is returned", func(t *testing.T) {
+		assert.Equal(t, "Submitted", reimbursementStatusLabel(entities.ReimbursementStatusSubmitted))
+		assert.Equal(t, "Paid", reimbursementStatusLabel(entities.ReimbursementStatusPaid))
+	})
+}
+
+// paidClaims keeps the claims of a test, MarkPaid pays an approved claim with its transfer like the repository.
+// With staleReads claims are read as still approved, as by a payment racing another one.
+type paidClaims struct {
+	repositories.ReimbursementRepository
+	claims     map[uuid.UUID]*entities.ReimbursementClaim
+	transfers  []*entities.Transaction
+	staleReads bool
+}
+
+func (r *paidClaims) FindByID(ctx context.Context, claimID uuid.UUID) (*entities.ReimbursementClaim, error) {
+	if claim, ok := r.claims[claimID]; ok {
+		copied := *claim
+		if r.staleReads {
+			copied.Status = entities.ReimbursementStatusApproved
+		}
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *paidClaims) FindItems(ctx context.Context, claimID uuid.UUID) ([]*entities.ReimbursementClaimItem, error) {
+	return nil, nil
+}
+
+func (r *paidClaims) MarkPaid(ctx context.Context, claim *entities.ReimbursementClaim, transfer *entities.Transaction) (entities.Transaction, error) {
+	stored := r.claims[claim.ClaimID]
+	if stored.Status != entities.ReimbursementStatusApproved {
+		return entities.Transaction{}, sql.ErrNoRows
+	}
+	r.transfers = append(r.transfers, transfer)
+	stored.Status = entities.ReimbursementStatusPaid
+	stored.PaymentTransactionID = &transfer.TransactionID
+	return *transfer, nil
+}
+
+// ignoredRollups accepts the rollup refreshes of a test
+type ignoredRollups struct {
+	repositories.TransactionRollupRepository
+}
+
+func (r *ignoredRollups) RefreshDays(ctx context.Context, workspaceID uuid.UUID, dates []time.Time) error {
+	return nil
+}
+
+func TestPayClaim(t *testing.T) {
+	ctx := context.Background()
+	approver := uuid.New()
+	workspace := &entities.Workspace{WorkspaceID: uuid.New()}
+	companyAccount := &entities.Account{AccountID: uuid.New(), UserID: approver}
+
+	newService := func() (*paidClaims, *entities.ReimbursementClaim, ReimbursementService) {
+		claim := &entities.ReimbursementClaim{
+			ClaimID:         uuid.New(),
+			WorkspaceID:     workspace.WorkspaceID,
+			ClaimantID:      uuid.New(),
+			Title:           "Perjalanan dinas",
+			TotalAmount:     750000,
+			PayoutAccountID: uuid.New(),
+			Status:          entities.ReimbursementStatusApproved,
+		}
+		claims := &paidClaims{claims: map[uuid.UUID]*entities.ReimbursementClaim{claim.ClaimID: claim}}
+		// The transfer is only written by MarkPaid, the transaction repository is not used to pay
+		return claims, claim, NewReimbursementService(claims, nil, &ignoredRollups{},
+			&budgetScopeAccounts{accounts: map[uuid.UUID]*entities.Account{companyAccount.AccountID: companyAccount}},
+			nil, nil,
+			&memberAuthorizer{workspace: workspace, members: map[uuid.UUID]bool{approver: true}},
+			&ignoredNotifications{}, &recordedEvents{})
+	}
+
+	t.Run("given an approved claim, when paying it, then the claim is paid with one transfer", func(t *testing.T) {
+		claims, claim, s := newService()
+
+		paidClaim, err := s.PayClaim(ctx, approver, claim.ClaimID, &entities.PayReimbursementClaimRequest{AccountID: companyAccount.AccountID})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ReimbursementStatusPaid, paidClaim.Status)
+		assert.Len(t, claims.transfers, 1)
+		assert.Equal(t, claim.TotalAmount, claims.transfers[0].Amount)
+		assert.Equal(t, &claim.PayoutAccountID, claims.transfers[0].TransferAccountID)
+		assert.Equal(t, &claims.transfers[0].TransactionID, paidClaim.PaymentTransactionID)
+	})
+
+	t.Run("given a claim paid meanwhile, when paying it again, then it conflicts without a second transfer", func(t *testing.T) {
+		claims, claim, s := newService()
+		request := &entities.PayReimbursementClaimRequest{AccountID: companyAccount.AccountID}
+
+		_, err := s.PayClaim(ctx, approver, claim.ClaimID, request)
+		assert.NoError(t, err)
+
+		claims.staleReads = true
+		_, err = s.PayClaim(ctx, approver, claim.ClaimID, request)
+		assert.EqualError(t, err, "claim status has changed")
+		assert.Len(t, claims.transfers, 1)
+	})
+}
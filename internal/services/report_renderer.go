@@ -0,0 +1,238 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/utils/pdf"
+)
+
+// RenderCashFlowPDF renders a cash flow statement as a PDF document
+func RenderCashFlowPDF(statement *entities.CashFlowStatement) []byte {
+	doc := pdf.New()
+	doc.Title("Cash Flow Statement")
+	doc.Text(statement.WorkspaceName)
+	doc.Text(fmt.Sprintf("Period %s - %s", statement.StartDate.Format("2 Jan 2006"), statement.EndDate.Format("2 Jan 2006")))
+
+	summaryColumns := []pdf.Column{{Width: 0.6}, {Width: 0.4, Align: pdf.AlignRight}}
+	doc.Heading("Summary")
+	doc.Row(summaryColumns, []string{"Total income", formatAmount(statement.CurrencySymbol, statement.TotalIncome)}, false)
+	doc.Row(summaryColumns, []string{"Total expenses", formatAmount(statement.CurrencySymbol, statement.TotalExpense)}, false)
+	doc.Row(summaryColumns, []string{"Net cash flow", formatAmount(statement.CurrencySymbol, statement.NetCashFlow)}, true)
+	savingsRate := "-"
+	if statement.SavingsRate != nil {
+		savingsRate = formatPercentage(*statement.SavingsRate)
+	}
+	doc.Row(summaryColumns, []string{"Savings rate", savingsRate}, false)
+	if statement.UnconvertedCount > 0 {
+		doc.Space(4)
+		doc.Text(fmt.Sprintf("%d transactions have no exchange rate recorded for their currency and are left out of the totals.", statement.UnconvertedCount))
+	}
+
+	categoryColumns := []pdf.Column{{Width: 0.5}, {Width: 0.15, Align: pdf.AlignRight}, {Width: 0.35, Align: pdf.AlignRight}}
+	for _, section := range []struct {
+		title  string
+		totals []*entities.CashFlowCategoryTotal
+		total  float64
+	}{
+		{"Income by category", statement.Income, statement.TotalIncome},
+		{"Expenses by category", statement.Expenses, statement.TotalExpense},
+	} {
+		doc.Heading(section.title)
+		doc.Row(categoryColumns, []string{"Category", "Share", "Amount"}, true)
+		for _, total := range section.totals {
+			doc.Row(categoryColumns, []string{total.CategoryName, formatPercentage(total.Percentage), formatAmount(statement.CurrencySymbol, total.Amount)}, false)
+		}
+		doc.Row(categoryColumns, []string{"Total", "", formatAmount(statement.CurrencySymbol, section.total)}, true)
+	}
+
+	if len(statement.Accounts) > 0 {
+		accountColumns := []pdf.Column{
+			{Width: 0.28},
+			{Width: 0.18, Align: pdf.AlignRight},
+			{Width: 0.18, Align: pdf.AlignRight},
+			{Width: 0.18, Align: pdf.AlignRight},
+			{Width: 0.18, Align: pdf.AlignRight},
+		}
+		doc.Heading("Accounts")
+		doc.Row(accountColumns, []string{"Account", "Opening", "In", "Out", "Closing"}, true)
+		for _, account := range statement.Accounts {
+			doc.Row(accountColumns, []string{
+				account.AccountName,
+				formatAmount("", account.OpeningBalance),
+				formatAmount("", account.Inflow),
+				formatAmount("", account.Outflow),
+				formatAmount("", account.ClosingBalance),
+			}, false)
+		}
+		doc.Space(4)
+		doc.Text("Account balances are in the account currency and include transfers between own accounts.")
+	}
+
+	return doc.Bytes()
+}
+
+// RenderCashFlowText renders a cash flow statement as a WhatsApp message for the /summary command
+func RenderCashFlowText(statement *entities.CashFlowStatement) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*Ringkasan Arus Kas - %s*\n", statement.WorkspaceName)
+	fmt.Fprintf(&b, "%s - %s\n\n", statement.StartDate.Format("02/01/2006"), statement.EndDate.Format("02/01/2006"))
+
+	fmt.Fprintf(&b, "Pemasukan: %s\n", formatAmount(statement.CurrencySymbol, statement.TotalIncome))
+	fmt.Fprintf(&b, "Pengeluaran: %s\n", formatAmount(statement.CurrencySymbol, statement.TotalExpense))
+	fmt.Fprintf(&b, "*Arus kas bersih: %s*\n", formatAmount(statement.CurrencySymbol, statement.NetCashFlow))
+	if statement.SavingsRate != nil {
+		fmt.Fprintf(&b, "Tingkat tabungan: %s\n", formatPercentage(*statement.SavingsRate))
+	}
+	if statement.UnconvertedCount > 0 {
+		fmt.Fprintf(&b, "_%d transaksi belum termasuk karena kurs mata uangnya belum tercatat._\n", statement.UnconvertedCount)
+	}
+
+	if len(statement.Expenses) > 0 {
+		b.WriteString("\n*Pengeluaran terbesar*\n")
+		for i, total := range statement.Expenses {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(&b, "%d. %s: %s (%s)\n", i+1, total.CategoryName, formatAmount(statement.CurrencySymbol, total.Amount), formatPercentage(total.Percentage))
+		}
+	}
+
+	if len(statement.Accounts) > 0 {
+		b.WriteString("\n*Saldo akun*\n")
+		for _, account := range statement.Accounts {
+			fmt.Fprintf(&b, "- %s: %s → %s\n", account.AccountName, formatAmount("", account.OpeningBalance), formatAmount("", account.ClosingBalance))
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// RenderReimbursementClaimPDF renders the summary of a reimbursement claim for finance, amounts in the workspace currency
+func RenderReimbursementClaimPDF(claim *entities.ReimbursementClaim, workspaceName, currencySymbol string) []byte {
+	doc := pdf.New()
+	doc.Title("Reimbursement Claim")
+	doc.Text(workspaceName)
+	doc.Text(claim.Title)
+
+	summaryColumns := []pdf.Column{{Width: 0.35}, {Width: 0.65}}
+	doc.Heading("Summary")
+	doc.Row(summaryColumns, []string{"Claimant", claim.ClaimantName}, false)
+	doc.Row(summaryColumns, []string{"Status", reimbursementStatusLabel(claim.Status)}, false)
+	doc.Row(summaryColumns, []string{"Payout account", claim.PayoutAccountName}, false)
+	for _, date := range []struct {
+		label string
+		at    *time.Time
+	}{
+		{"Submitted", claim.SubmittedAt},
+		{"Reviewed", claim.ReviewedAt},
+		{"Paid", claim.PaidAt},
+	} {
+		if date.at != nil {
+			doc.Row(summaryColumns, []string{date.label, date.at.Format("2 Jan 2006")}, false)
+		}
+	}
+	if claim.ReviewNote != nil {
+		doc.Row(summaryColumns, []string{"Review note", *claim.ReviewNote}, false)
+	}
+	if claim.Notes != nil {
+		doc.Row(summaryColumns, []string{"Notes", *claim.Notes}, false)
+	}
+	doc.Row(summaryColumns, []string{"Total", formatAmount(currencySymbol, claim.TotalAmount)}, true)
+
+	itemColumns := []pdf.Column{
+		{Width: 0.14},
+		{Width: 0.36},
+		{Width: 0.2},
+		{Width: 0.1},
+		{Width: 0.2, Align: pdf.AlignRight},
+	}
+	doc.Heading("Expenses")
+	doc.Row(itemColumns, []string{"Date", "Description", "Category", "Receipt", "Amount"}, true)
+	var receipts []string
+	for _, item := range claim.Items {
+		category := "-"
+		if item.CategoryName != nil {
+			category = *item.CategoryName
+		}
+		receipt := "-"
+		if item.ReceiptURL != nil {
+			receipts = append(receipts, *item.ReceiptURL)
+			receipt = fmt.Sprintf("#%d", len(receipts))
+		}
+		doc.Row(itemColumns, []string{
+			item.TransactionDate.Format("02/01/2006"),
+			item.Description,
+			category,
+			receipt,
+			formatAmount("", item.Amount),
+		}, false)
+	}
+	doc.Row(itemColumns, []string{"Total", "", "", "", formatAmount(currencySymbol, claim.TotalAmount)}, true)
+
+	if len(receipts) > 0 {
+		doc.Heading("Receipts")
+		for i, receipt := range receipts {
+			doc.Text(fmt.Sprintf("#%d %s", i+1, receipt))
+		}
+	}
+	doc.Space(4)
+	doc.Text("Expense amounts are in the account currency, the total in the workspace currency.")
+
+	return doc.Bytes()
+}
+
+// reimbursementStatusLabel names a reimbursement claim status
+func reimbursementStatusLabel(status int) string {
+	switch status {
+	case entities.ReimbursementStatusDraft:
+		return "Draft"
+	case entities.ReimbursementStatusSubmitted:
+		return "Submitted"
+	case entities.ReimbursementStatusApproved:
+		return "Approved"
+	case entities.ReimbursementStatusRejected:
+		return "Rejected"
+	case entities.ReimbursementStatusPaid:
+		return "Paid"
+	default:
+		return "Unknown"
+	}
+}
+
+// formatAmount formats an amount with Indonesian separators, e.g. "Rp 1.250.000" or "-Rp 12.500,50"
+func formatAmount(symbol string, amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction := cents % 100; fraction != 0 {
+		fmt.Fprintf(&grouped, ",%02d", fraction)
+	}
+
+	if symbol == "" {
+		return sign + grouped.String()
+	}
+	return sign + symbol + " " + grouped.String()
+}
+
+// formatPercentage formats a percentage with up to one decimal, e.g. "12,5%"
+func formatPercentage(percentage float64) string {
+	formatted := strings.TrimSuffix(fmt.Sprintf("%.1f", percentage), ".0")
+	return strings.Replace(formatted, ".", ",", 1) + "%"
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+//go:generate mockgen -source=report_service.go -package=mock -destination=mock/report_service_mock.go
+type (
+	ReportService interface {
+		GetCashFlowStatement(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, startDate, endDate *time.Time) (*entities.CashFlowStatement, error)
+	}
+
+	reportService struct {
+		reportRepo   repositories.ReportRepository
+		authorizer   WorkspaceAuthorizer
+		currencyRepo repositories.CurrencyRepository
+	}
+)
+
+// NewReportService creates a new report service
+func NewReportService(
+	reportRepo repositories.ReportRepository,
+	authorizer WorkspaceAuthorizer,
+	currencyRepo repositories.CurrencyRepository,
+) ReportService {
+	return &reportService{
+		reportRepo:   reportRepo,
+		authorizer:   authorizer,
+		currencyRepo: currencyRepo,
+	}
+}
+
+// GetCashFlowStatement returns the income by category, expenses by category, net cash flow, savings rate
+// and account balances of a workspace over a period. The period defaults to the current fiscal month up to today.
+func (s *reportService) GetCashFlowStatement(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, startDate, endDate *time.Time) (*entities.CashFlowStatement, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	periodEnd := today(workspace.Timezone)
+	if endDate != nil {
+		periodEnd = *endDate
+	}
+	periodStart := truncateAnalyticsPeriod(entities.AnalyticsIntervalMonth, periodEnd, workspaceCalendar(workspace.Settings))
+	if startDate != nil {
+		periodStart = *startDate
+	}
+	if periodStart.After(periodEnd) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	income, err := s.reportRepo.FindCashFlowCategoryTotals(ctx, workspaceID, entities.TransactionTypeIncome, workspace.CurrencyID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.reportRepo.FindCashFlowCategoryTotals(ctx, workspaceID, entities.TransactionTypeExpense, workspace.CurrencyID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := s.reportRepo.FindCashFlowAccountMovements(ctx, workspaceID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingExpense, pendingUnconverted, err := s.reportRepo.FindPendingExpenseTotal(ctx, workspaceID, workspace.CurrencyID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := buildCashFlowStatement(income, expenses, movements)
+	statement.PendingExpense = roundAmount(pendingExpense)
+	statement.UnconvertedCount += pendingUnconverted
+	statement.WorkspaceID = workspace.WorkspaceID
+	statement.WorkspaceName = workspace.Name
+	statement.CurrencyID = workspace.CurrencyID
+	statement.StartDate = periodStart
+	statement.EndDate = periodEnd
+
+	currency, err := s.currencyRepo.FindByID(ctx, workspace.CurrencyID)
+	if err != nil {
+		return nil, err
+	}
+	if currency != nil {
+		statement.CurrencyCode = currency.CurrencyCode
+		statement.CurrencySymbol = currency.CurrencySymbol
+	}
+
+	return statement, nil
+}
+
+// buildCashFlowStatement totals the income and expenses and derives the account balances.
+// The closing balance is the current balance minus what moved after the period,
+// the opening balance is the closing balance minus what moved during the period.
+func buildCashFlowStatement(income, expenses []*entities.CashFlowCategoryTotal, movements []*entities.CashFlowAccountMovement) *entities.CashFlowStatement {
+	statement := &entities.CashFlowStatement{
+		Income:   make([]*entities.CashFlowCategoryTotal, 0, len(income)),
+		Expenses: make([]*entities.CashFlowCategoryTotal, 0, len(expenses)),
+		Accounts: make([]*entities.CashFlowAccountBalance, 0, len(movements)),
+	}
+
+	for _, total := range income {
+		statement.TotalIncome += total.Amount
+		statement.UnconvertedCount += total.UnconvertedCount
+	}
+	for _, total := range expenses {
+		statement.TotalExpense += total.Amount
+		statement.UnconvertedCount += total.UnconvertedCount
+	}
+
+	for _, total := range income {
+		total.Percentage = percentageOf(total.Amount, statement.TotalIncome)
+		total.Amount = roundAmount(total.Amount)
+		statement.Income = append(statement.Income, total)
+	}
+	for _, total := range expenses {
+		total.Percentage = percentageOf(total.Amount, statement.TotalExpense)
+		total.Amount = roundAmount(total.Amount)
+		statement.Expenses = append(statement.Expenses, total)
+	}
+
+	statement.TotalIncome = roundAmount(statement.TotalIncome)
+	statement.TotalExpense = roundAmount(statement.TotalExpense)
+	statement.NetCashFlow = roundAmount(statement.TotalIncome - statement.TotalExpense)
+	if statement.TotalIncome > 0 {
+		savingsRate := percentageOf(statement.NetCashFlow, statement.TotalIncome)
+		statement.SavingsRate = &savingsRate
+	}
+
+	for _, movement := range movements {
+		closing := movement.CurrentBalance - movement.MovementAfterPeriod
+		statement.Accounts = append(statement.Accounts, &entities.CashFlowAccountBalance{
+			AccountID:      movement.AccountID,
+			AccountName:    movement.AccountName,
+			CurrencyID:     movement.CurrencyID,
+			OpeningBalance: roundAmount(closing - movement.Inflow + movement.Outflow),
+			Inflow:         roundAmount(movement.Inflow),
+			Outflow:        roundAmount(movement.Outflow),
+			ClosingBalance: roundAmount(closing),
+		})
+	}
+
+	return statement
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestBuildCashFlowStatement(t *testing.T) {
+	t.Run("given income and expenses, when building the statement, then net cash flow and savings rate are computed", func(t *testing.T) {
+		income := []*entities.CashFlowCategoryTotal{
+			{CategoryName: "Gaji", Amount: 8000000, TransactionCount: 1},
+			{CategoryName: "Freelance", Amount: 2000000, TransactionCount: 2},
+		}
+		expenses := []*entities.CashFlowCategoryTotal{
+			{CategoryName: "Makan", Amount: 3000000, TransactionCount: 40},
+			{CategoryName: "Transport", Amount: 1000000, TransactionCount: 20},
+		}
+
+		statement := buildCashFlowStatement(income, expenses, nil)
+
+		assert.Equal(t, 10000000.0, statement.TotalIncome)
+		assert.Equal(t, 4000000.0, statement.TotalExpense)
+		assert.Equal(t, 6000000.0, statement.NetCashFlow)
+		assert.Equal(t, 60.0, *statement.SavingsRate)
+		assert.Equal(t, 20.0, statement.Income[1].Percentage)
+		assert.Equal(t, 75.0, statement.Expenses[0].Percentage)
+	})
+
+	t.Run("given no income, when building the statement, then the savings rate is empty", func(t *testing.T) {
+		expenses := []*entities.CashFlowCategoryTotal{{CategoryName: "Makan", Amount: 50000}}
+
+		statement := buildCashFlowStatement(nil, expenses, nil)
+
+		assert.Nil(t, statement.SavingsRate)
+		assert.Equal(t, -50000.0, statement.NetCashFlow)
+		assert.Empty(t, statement.Income)
+	})
+
+	t.Run("given categories with transactions without an exchange rate, when building the statement, then they are counted as unconverted", func(t *testing.T) {
+		income := []*entities.CashFlowCategoryTotal{{CategoryName: "Gaji", Amount: 8000000, TransactionCount: 2, UnconvertedCount: 1}}
+		expenses := []*entities.CashFlowCategoryTotal{{CategoryName: "Makan", Amount: 3000000, TransactionCount: 40, UnconvertedCount: 2}}
+
+		statement := buildCashFlowStatement(income, expenses, nil)
+
+		assert.Equal(t, 8000000.0, statement.TotalIncome)
+		assert.Equal(t, 3, statement.UnconvertedCount)
+	})
+
+	t.Run("given account movements, when building the statement, then balances are derived from the current balance", func(t *testing.T) {
+		movements := []*entities.CashFlowAccountMovement{
+			{AccountID: uuid.New(), AccountName: "BCA", CurrentBalance: 5000000, Inflow: 8000000, Outflow: 3500000, MovementAfterPeriod: -500000},
+		}
+
+		statement := buildCashFlowStatement(nil, nil, movements)
+
+		assert.Equal(t, 5500000.0, statement.Accounts[0].ClosingBalance)
+		assert.Equal(t, 1000000.0, statement.Accounts[0].OpeningBalance)
+	})
+}
+
+func TestRenderCashFlowText(t *testing.T) {
+	t.Run("given a statement, when rendering the summary, then amounts use Indonesian formatting", func(t *testing.T) {
+		savingsRate := 12.5
+		statement := &entities.CashFlowStatement{
+			WorkspaceName:  "Pribadi",
+			CurrencySymbol: "Rp",
+			StartDate:      time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:        time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC),
+			TotalIncome:    8000000,
+			TotalExpense:   7000000,
+			NetCashFlow:    1000000,
+			SavingsRate:    &savingsRate,
+			Expenses:       []*entities.CashFlowCategoryTotal{{CategoryName: "Makan", Amount: 3250000.5, Percentage: 46.4}},
+		}
+
+		text := RenderCashFlowText(statement)
+
+		assert.Contains(t, text, "*Ringkasan Arus Kas - Pribadi*")
+		assert.Contains(t, text, "01/01/2025 - 31/01/2025")
+		assert.Contains(t, text, "*Arus kas bersih: Rp 1.000.000*")
+		assert.Contains(t, text, "Tingkat tabungan: 12,5%")
+		assert.Contains(t, text, "1. Makan: Rp 3.250.000,50 (46,4%)")
+		assert.NotContains(t, text, "kurs")
+	})
+
+	t.Run("given transactions without an exchange rate, when rendering the summary, then the totals are marked incomplete", func(t *testing.T) {
+		statement := &entities.CashFlowStatement{WorkspaceName: "Pribadi", CurrencySymbol: "Rp", UnconvertedCount: 2}
+
+		text := RenderCashFlowText(statement)
+
+		assert.Contains(t, text, "2 transaksi belum termasuk karena kurs mata uangnya belum tercatat")
+	})
+}
+
+func TestFormatAmount(t *testing.T) {
+	t.Run("given amounts, when formatting, then thousands are grouped", func(t *testing.T) {
+		assert.Equal(t, "Rp 0", formatAmount("Rp", 0))
+		assert.Equal(t, "Rp 999", formatAmount("Rp", 999))
+		assert.Equal(t, "-Rp 12.500,50", formatAmount("Rp", -12500.5))
+		assert.Equal(t, "1.000.000", formatAmount("", 1000000))
+	})
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=session_service.go -package=mock -destination=mock/session_service_mock.go
+type (
+	SessionService interface {
+		StartSession(ctx context.Context, user *entities.User) (*entities.RefreshTokenResponse, error)
+		Refresh(ctx context.Context, input *entities.RefreshTokenRequest) (*entities.RefreshTokenResponse, error)
+		GetSessions(ctx context.Context, userID uuid.UUID, currentSessionID uuid.UUID) ([]*entities.UserSession, error)
+		Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+		LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error)
+		RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+		RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	}
+
+	sessionService struct {
+		sessionRepo    repositories.UserSessionRepository
+		userRepo       repositories.UserRepository
+		authMiddleware *middleware.AuthMiddleware
+	}
+)
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepo repositories.UserSessionRepository, userRepo repositories.UserRepository, authMiddleware *middleware.AuthMiddleware) SessionService {
+	return &sessionService{
+		sessionRepo:    sessionRepo,
+		userRepo:       userRepo,
+		authMiddleware: authMiddleware,
+	}
+}
+
+// StartSession opens a session for a user who just logged in or registered, on the device of the context
+func (s *sessionService) StartSession(ctx context.Context, user *entities.User) (*entities.RefreshTokenResponse, error) {
+	client := middleware.SessionClientFromContext(ctx)
+	deviceName := strings.TrimSpace(client.DeviceName)
+	if deviceName == "" {
+		deviceName = describeUserAgent(client.UserAgent)
+	}
+
+	session := &entities.UserSession{
+		SessionID:  uuid.New(),
+		UserID:     user.UserID,
+		DeviceName: deviceName,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		ExpiresAt:  time.Now().Add(middleware.RefreshTokenTTL),
+	}
+
+	refreshToken, token, err := newRefreshToken(session.SessionID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Create(ctx, session, token); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.SessionID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token and the next refresh token of the session.
+// Presenting a token that was already exchanged means it leaked, so the whole session is revoked.
+func (s *sessionService) Refresh(ctx context.Context, input *entities.RefreshTokenRequest) (*entities.RefreshTokenResponse, error) {
+	token, err := s.sessionRepo.FindTokenByHash(ctx, hashRefreshToken(input.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errorsutil.New(401, "invalid refresh token")
+	}
+	if token.RotatedAt != nil {
+		_ = s.sessionRepo.Revoke(ctx, token.SessionID, entities.SessionRevokedReuse)
+		return nil, errorsutil.New(401, "refresh token reuse detected")
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, errorsutil.New(401, "refresh token has expired")
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, token.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, errorsutil.New(401, "session has been revoked")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(401, "invalid refresh token")
+	}
+	if user.Status != entities.UserStatusActive {
+		return nil, errorsutil.New(403, "user account is inactive")
+	}
+
+	refreshToken, next, err := newRefreshToken(session.SessionID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.RotateToken(ctx, token.TokenID, next, middleware.SessionClientFromContext(ctx)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Another request exchanged the same token first
+			_ = s.sessionRepo.Revoke(ctx, session.SessionID, entities.SessionRevokedReuse)
+			return nil, errorsutil.New(401, "refresh token reuse detected")
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.SessionID, refreshToken)
+}
+
+// GetSessions returns the open sessions of a user, marking the one of the current access token
+func (s *sessionService) GetSessions(ctx context.Context, userID uuid.UUID, currentSessionID uuid.UUID) ([]*entities.UserSession, error) {
+	sessions, err := s.sessionRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.Current = session.SessionID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// Logout ends the session of the current access token, logging out twice is not an error
+func (s *sessionService) Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	err := s.revoke(ctx, userID, sessionID, entities.SessionRevokedLogout)
+	if err != nil && err.Error() == "session not found" {
+		return nil
+	}
+	return err
+}
+
+// LogoutAll ends every session of a user and returns how many were open
+func (s *sessionService) LogoutAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.RevokeAll(ctx, userID, entities.SessionRevokedLogoutAll)
+}
+
+// RevokeAll ends every session of a user for a reason, e.g. after a password change
+func (s *sessionService) RevokeAll(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	return s.sessionRepo.RevokeByUser(ctx, userID, reason)
+}
+
+// RevokeSession ends one of the user's sessions, e.g. a lost phone
+func (s *sessionService) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	return s.revoke(ctx, userID, sessionID, entities.SessionRevokedByUser)
+}
+
+// revoke ends an open session of a user
+func (s *sessionService) revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, reason string) error {
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return errorsutil.New(404, "session not found")
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, reason); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorsutil.New(404, "session not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// issueTokens pairs a new access token of a session with its refresh token
+func (s *sessionService) issueTokens(user *entities.User, sessionID uuid.UUID, refreshToken string) (*entities.RefreshTokenResponse, error) {
+	accessToken, err := s.authMiddleware.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(middleware.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken generates a random refresh token of a session, only its hash is stored
+func newRefreshToken(sessionID uuid.UUID, userID uuid.UUID) (string, *entities.RefreshToken, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(bytes)
+
+	return refreshToken, &entities.RefreshToken{
+		TokenID:   uuid.New(),
+		SessionID: sessionID,
+		UserID:    userID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().Add(middleware.RefreshTokenTTL),
+	}, nil
+}
+
+// hashRefreshToken returns the SHA-256 hash of a refresh token in hex
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// describeUserAgent names the device of a user agent for the session list, e.g. "Chrome on Windows"
+func describeUserAgent(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(userAgent, "iPhone"):
+		platform = "iPhone"
+	case strings.Contains(userAgent, "iPad"):
+		platform = "iPad"
+	case strings.Contains(userAgent, "Android"):
+		platform = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		platform = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		platform = "Linux"
+	}
+
+	// Order matters, Edge and Chrome also claim to be Safari
+	browser := ""
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Firefox/"), strings.Contains(userAgent, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+
+	// Apps and scripts, e.g. "okhttp/4.9.0", are named by their product
+	name := strings.SplitN(userAgent, "/", 2)[0]
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return name
+}
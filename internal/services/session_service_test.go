@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	sessionID := uuid.New()
+	userID := uuid.New()
+
+	t.Run("given a session, when generating a refresh token, then only its hash is kept on the record", func(t *testing.T) {
+		refreshToken, token, err := newRefreshToken(sessionID, userID)
+		assert.NoError(t, err)
+		assert.Len(t, refreshToken, 43)
+		assert.Equal(t, hashRefreshToken(refreshToken), token.TokenHash)
+		assert.NotEqual(t, refreshToken, token.TokenHash)
+		assert.Equal(t, sessionID, token.SessionID)
+		assert.Equal(t, userID, token.UserID)
+	})
+
+	t.Run("given two refresh tokens of the same session, when generating, then they differ", func(t *testing.T) {
+		first, _, _ := newRefreshToken(sessionID, userID)
+		second, _, _ := newRefreshToken(sessionID, userID)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	t.Run("given a refresh token, when hashing, then the SHA-256 hex digest is returned", func(t *testing.T) {
+		assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hashRefreshToken(""))
+		assert.Len(t, hashRefreshToken("token"), 64)
+	})
+}
+
+func TestDescribeUserAgent(t *testing.T) {
+	t.Run("given a desktop browser, when describing, then the browser and platform are named", func(t *testing.T) {
+		chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+		assert.Equal(t, "Chrome on Windows", describeUserAgent(chrome))
+
+		edge := chrome + " Edg/120.0.0.0"
+		assert.Equal(t, "Edge on Windows", describeUserAgent(edge))
+	})
+
+	t.Run("given a phone browser, when describing, then the phone is named", func(t *testing.T) {
+		safari := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+		assert.Equal(t, "Safari on iPhone", describeUserAgent(safari))
+	})
+
+	t.Run("given an app client or no user agent, when describing, then the product or a placeholder is returned", func(t *testing.T) {
+		assert.Equal(t, "okhttp", describeUserAgent("okhttp/4.9.0"))
+		assert.Equal(t, "Unknown device", describeUserAgent(""))
+	})
+}
+
+// memorySessionRepo keeps sessions and refresh tokens in memory, following the conditional updates of the
+// Postgres repository: a token rotates once and a session is revoked once
+type memorySessionRepo struct {
+	sessions map[uuid.UUID]*entities.UserSession
+	tokens   map[string]*entities.RefreshToken
+
+	// beforeRotate runs once before the next rotation, e.g. to exchange the same token concurrently
+	beforeRotate func()
+}
+
+func newMemorySessionRepo() *memorySessionRepo {
+	return &memorySessionRepo{
+		sessions: make(map[uuid.UUID]*entities.UserSession),
+		tokens:   make(map[string]*entities.RefreshToken),
+	}
+}
+
+func (r *memorySessionRepo) Create(ctx context.Context, session *entities.UserSession, token *entities.RefreshToken) error {
+	stored := *session
+	r.sessions[session.SessionID] = &stored
+	storedToken := *token
+	r.tokens[token.TokenHash] = &storedToken
+	return nil
+}
+
+func (r *memorySessionRepo) FindByID(ctx context.Context, sessionID uuid.UUID) (*entities.UserSession, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	found := *session
+	return &found, nil
+}
+
+func (r *memorySessionRepo) FindActiveByUser(ctx context.Context, userID uuid.UUID) ([]*entities.UserSession, error) {
+	var sessions []*entities.UserSession
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			found := *session
+			sessions = append(sessions, &found)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *memorySessionRepo) FindTokenByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	found := *token
+	return &found, nil
+}
+
+func (r *memorySessionRepo) RotateToken(ctx context.Context, tokenID uuid.UUID, next *entities.RefreshToken, client entities.SessionClient) error {
+	if beforeRotate := r.beforeRotate; beforeRotate != nil {
+		r.beforeRotate = nil
+		beforeRotate()
+	}
+
+	for _, token := range r.tokens {
+		if token.TokenID != tokenID {
+			continue
+		}
+		session := r.sessions[token.SessionID]
+		if token.RotatedAt != nil || session == nil || session.RevokedAt != nil {
+			return sql.ErrNoRows
+		}
+		now := time.Now()
+		token.RotatedAt = &now
+		storedNext := *next
+		r.tokens[next.TokenHash] = &storedNext
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (r *memorySessionRepo) Revoke(ctx context.Context, sessionID uuid.UUID, reason string) error {
+	session, ok := r.sessions[sessionID]
+	if !ok || session.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = &reason
+	return nil
+}
+
+func (r *memorySessionRepo) RevokeByUser(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	var count int64
+	for _, session := range r.sessions {
+		if session.UserID == userID && r.Revoke(ctx, session.SessionID, reason) == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// sessionUserRepo finds users by ID, the other methods are not used by sessions
+type sessionUserRepo struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*entities.User
+}
+
+func (r *sessionUserRepo) FindByID(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	return r.users[userID], nil
+}
+
+func TestSessionServiceRefresh(t *testing.T) {
+	ctx := context.Background()
+	user := &entities.User{UserID: uuid.New(), Status: entities.UserStatusActive}
+
+	newService := func() (*memorySessionRepo, SessionService) {
+		sessionRepo := newMemorySessionRepo()
+		userRepo := &sessionUserRepo{users: map[uuid.UUID]*entities.User{user.UserID: user}}
+		return sessionRepo, NewSessionService(sessionRepo, userRepo, middleware.NewAuthMiddleware("secret"))
+	}
+	refresh := func(s SessionService, refreshToken string) (*entities.RefreshTokenResponse, error) {
+		return s.Refresh(ctx, &entities.RefreshTokenRequest{RefreshToken: refreshToken})
+	}
+	onlySession := func(sessionRepo *memorySessionRepo) *entities.UserSession {
+		for _, session := range sessionRepo.sessions {
+			return session
+		}
+		return nil
+	}
+
+	t.Run("given a fresh refresh token, when refreshing, then the next refresh token is issued and the old one is rotated", func(t *testing.T) {
+		sessionRepo, s := newService()
+		started, err := s.StartSession(ctx, user)
+		assert.NoError(t, err)
+
+		refreshed, err := refresh(s, started.RefreshToken)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, refreshed.AccessToken)
+		assert.NotEqual(t, started.RefreshToken, refreshed.RefreshToken)
+		assert.NotNil(t, sessionRepo.tokens[hashRefreshToken(started.RefreshToken)].RotatedAt)
+
+		_, err = refresh(s, refreshed.RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("given a refresh token that was already exchanged, when refreshing, then the whole session is revoked", func(t *testing.T) {
+		sessionRepo, s := newService()
+		started, _ := s.StartSession(ctx, user)
+		refreshed, _ := refresh(s, started.RefreshToken)
+
+		_, err := refresh(s, started.RefreshToken)
+		assert.EqualError(t, err, "refresh token reuse detected")
+		session := onlySession(sessionRepo)
+		assert.NotNil(t, session.RevokedAt)
+		assert.Equal(t, entities.SessionRevokedReuse, *session.RevokedReason)
+
+		_, err = refresh(s, refreshed.RefreshToken)
+		assert.Error(t, err, "the token issued to the legitimate holder stops working too")
+	})
+
+	t.Run("given two requests exchanging the same token, when the second loses the race, then the session is revoked", func(t *testing.T) {
+		sessionRepo, s := newService()
+		started, _ := s.StartSession(ctx, user)
+
+		var first *entities.RefreshTokenResponse
+		sessionRepo.beforeRotate = func() {
+			first, _ = refresh(s, started.RefreshToken)
+		}
+
+		_, err := refresh(s, started.RefreshToken)
+		assert.EqualError(t, err, "refresh token reuse detected")
+		assert.NotNil(t, first)
+		session := onlySession(sessionRepo)
+		assert.NotNil(t, session.RevokedAt)
+		assert.Equal(t, entities.SessionRevokedReuse, *session.RevokedReason)
+	})
+
+	t.Run("given an expired refresh token, when refreshing, then it is refused and the session stays open", func(t *testing.T) {
+		sessionRepo, s := newService()
+		started, _ := s.StartSession(ctx, user)
+		sessionRepo.tokens[hashRefreshToken(started.RefreshToken)].ExpiresAt = time.Now().Add(-time.Minute)
+
+		_, err := refresh(s, started.RefreshToken)
+		assert.EqualError(t, err, "refresh token has expired")
+		assert.Nil(t, onlySession(sessionRepo).RevokedAt)
+	})
+
+	t.Run("given an unknown refresh token, when refreshing, then it is refused", func(t *testing.T) {
+		_, s := newService()
+
+		_, err := refresh(s, "unknown")
+		assert.EqualError(t, err, "invalid refresh token")
+	})
+
+	t.Run("given a logged out session, when refreshing, then it is refused", func(t *testing.T) {
+		sessionRepo, s := newService()
+		started, _ := s.StartSession(ctx, user)
+		assert.NoError(t, s.Logout(ctx, user.UserID, onlySession(sessionRepo).SessionID))
+
+		_, err := refresh(s, started.RefreshToken)
+		assert.EqualError(t, err, "session has been revoked")
+	})
+}
+
+func TestSessionServiceRevoke(t *testing.T) {
+	ctx := context.Background()
+	user := &entities.User{UserID: uuid.New(), Status: entities.UserStatusActive}
+	other := &entities.User{UserID: uuid.New(), Status: entities.UserStatusActive}
+
+	newService := func() (*memorySessionRepo, SessionService) {
+		sessionRepo := newMemorySessionRepo()
+		userRepo := &sessionUserRepo{users: map[uuid.UUID]*entities.User{user.UserID: user, other.UserID: other}}
+		return sessionRepo, NewSessionService(sessionRepo, userRepo, middleware.NewAuthMiddleware("secret"))
+	}
+	sessionOf := func(sessionRepo *memorySessionRepo, userID uuid.UUID) *entities.UserSession {
+		for _, session := range sessionRepo.sessions {
+			if session.UserID == userID {
+				return session
+			}
+		}
+		return nil
+	}
+
+	t.Run("given a session, when logging out twice, then both succeed and the session is revoked once", func(t *testing.T) {
+		sessionRepo, s := newService()
+		_, _ = s.StartSession(ctx, user)
+		session := sessionOf(sessionRepo, user.UserID)
+
+		assert.NoError(t, s.Logout(ctx, user.UserID, session.SessionID))
+		revokedAt := session.RevokedAt
+		assert.NotNil(t, revokedAt)
+		assert.Equal(t, entities.SessionRevokedLogout, *session.RevokedReason)
+
+		assert.NoError(t, s.Logout(ctx, user.UserID, session.SessionID))
+		assert.Equal(t, revokedAt, session.RevokedAt)
+	})
+
+	t.Run("given another user's session, when revoking it, then it is not found and stays open", func(t *testing.T) {
+		sessionRepo, s := newService()
+		_, _ = s.StartSession(ctx, other)
+		session := sessionOf(sessionRepo, other.UserID)
+
+		err := s.RevokeSession(ctx, user.UserID, session.SessionID)
+		assert.EqualError(t, err, "session not found")
+		assert.Nil(t, session.RevokedAt)
+	})
+
+	t.Run("given an already revoked session, when revoking it, then it is not found", func(t *testing.T) {
+		sessionRepo, s := newService()
+		_, _ = s.StartSession(ctx, user)
+		session := sessionOf(sessionRepo, user.UserID)
+		assert.NoError(t, s.RevokeSession(ctx, user.UserID, session.SessionID))
+
+		err := s.RevokeSession(ctx, user.UserID, session.SessionID)
+		assert.EqualError(t, err, "session not found")
+	})
+
+	t.Run("given several sessions, when logging out everywhere, then only the open sessions of the user are counted", func(t *testing.T) {
+		sessionRepo, s := newService()
+		_, _ = s.StartSession(ctx, user)
+		_, _ = s.StartSession(ctx, user)
+		_, _ = s.StartSession(ctx, other)
+
+		count, err := s.LogoutAll(ctx, user.UserID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		assert.Nil(t, sessionOf(sessionRepo, other.UserID).RevokedAt)
+	})
+}
@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=settlement_service.go -package=mock -destination=mock/settlement_service_mock.go
+type (
+	SettlementService interface {
+		// Transaction split methods
+		GetTransactionSplits(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) ([]*entities.TransactionSplit, error)
+		SetTransactionSplits(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.SetTransactionSplitsRequest) ([]*entities.TransactionSplit, error)
+
+		// Settlement methods
+		GetSettlementSummary(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, graceAmount float64) (*entities.SettlementSummary, error)
+		CreateSettlement(ctx context.Context, userID uuid.UUID, input *entities.CreateSettlementRequest) (*entities.Settlement, error)
+		GetSettlementByID(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error)
+		ListSettlements(ctx context.Context, userID uuid.UUID, params *entities.SettlementListParams, limit, offset int) ([]*entities.Settlement, error)
+		ConfirmSettlement(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error)
+		CancelSettlement(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error)
+	}
+
+	settlementService struct {
+		settlementRepo  repositories.SettlementRepository
+		transactionRepo repositories.TransactionRepository
+		authorizer      WorkspaceAuthorizer
+		events          DomainEventRecorder
+	}
+)
+
+// NewSettlementService creates a new settlement service
+func NewSettlementService(settlementRepo repositories.SettlementRepository, transactionRepo repositories.TransactionRepository, authorizer WorkspaceAuthorizer, events DomainEventRecorder) SettlementService {
+	return &settlementService{
+		settlementRepo:  settlementRepo,
+		transactionRepo: transactionRepo,
+		authorizer:      authorizer,
+		events:          events,
+	}
+}
+
+// settlementAmountTolerance absorbs rounding when comparing amounts with two decimals
+const settlementAmountTolerance = 0.01
+
+// GetTransactionSplits returns the splits of a transaction
+func (s *settlementService) GetTransactionSplits(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) ([]*entities.TransactionSplit, error) {
+	transaction, err := s.findWorkspaceTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	return s.settlementRepo.FindSplitsByTransaction(ctx, transactionID)
+}
+
+// SetTransactionSplits replaces the splits of an expense. The member who recorded it paid it, so every other
+// member of the split owes their share to them. The splits it replaces are kept in the audit log.
+func (s *settlementService) SetTransactionSplits(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.SetTransactionSplitsRequest) ([]*entities.TransactionSplit, error) {
+	transaction, err := s.findWorkspaceTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.TransactionType != entities.TransactionTypeExpense || transaction.TransferAccountID != nil {
+		return nil, errors.New("only expenses can be split")
+	}
+	if transaction.CreatedBy == nil {
+		return nil, errors.New("the payer of the transaction is unknown")
+	}
+
+	// Members may only split the expenses they recorded
+	if _, err := s.authorizer.AuthorizeOwnData(ctx, *transaction.WorkspaceID, userID, transaction.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := validateTransactionSplits(transaction.Amount, input.Splits); err != nil {
+		return nil, err
+	}
+
+	splits := make([]*entities.TransactionSplit, 0, len(input.Splits))
+	for _, split := range input.Splits {
+		member, err := s.authorizer.GetMembership(ctx, *transaction.WorkspaceID, split.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, errorsutil.New(400, "split members must belong to the workspace")
+		}
+
+		splits = append(splits, &entities.TransactionSplit{
+			TransactionSplitID: uuid.New(),
+			TransactionID:      transactionID,
+			UserID:             split.UserID,
+			Amount:             split.Amount,
+		})
+	}
+
+	previous, err := s.settlementRepo.FindSplitsByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced, err := s.settlementRepo.ReplaceSplits(ctx, transactionID, splits)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Record(ctx, &entities.DomainEvent{
+		WorkspaceID:  *transaction.WorkspaceID,
+		ActorID:      &userID,
+		Action:       entities.ActivityActionUpdated,
+		ResourceType: entities.ActivityResourceSplit,
+		ResourceID:   &transactionID,
+		ResourceName: transaction.Description,
+		Amount:       &transaction.Amount,
+		OldValues:    previous,
+		NewValues:    replaced,
+	})
+
+	return replaced, nil
+}
+
+// GetSettlementSummary returns the split balances of a workspace and the fewest transfers that settle them.
+// Balances under graceAmount are considered settled.
+func (s *settlementService) GetSettlementSummary(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, graceAmount float64) (*entities.SettlementSummary, error) {
+	if graceAmount < 0 {
+		return nil, errors.New("grace amount cannot be negative")
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.settlementRepo.FindBalances(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if balances == nil {
+		balances = []*entities.MemberBalance{}
+	}
+
+	transfers := minimizeSettlementTransfers(balances, graceAmount)
+
+	return &entities.SettlementSummary{
+		WorkspaceID: workspaceID,
+		CurrencyID:  workspace.CurrencyID,
+		GraceAmount: graceAmount,
+		IsSettled:   len(transfers) == 0,
+		Balances:    balances,
+		Transfers:   transfers,
+	}, nil
+}
+
+// CreateSettlement records a payment between two members. The recording member must be the payer or the payee,
+// their side is confirmed right away and the other side confirms later.
+func (s *settlementService) CreateSettlement(ctx context.Context, userID uuid.UUID, input *entities.CreateSettlementRequest) (*entities.Settlement, error) {
+	if input.Amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if input.FromUserID == input.ToUserID {
+		return nil, errors.New("payer and payee must be different members")
+	}
+	if userID != input.FromUserID && userID != input.ToUserID {
+		return nil, errorsutil.New(403, "only the payer or the payee can record a settlement")
+	}
+
+	if _, err := s.authorizer.Authorize(ctx, input.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction); err != nil {
+		return nil, err
+	}
+
+	counterpartID := input.ToUserID
+	if userID == input.ToUserID {
+		counterpartID = input.FromUserID
+	}
+	counterpart, err := s.authorizer.GetMembership(ctx, input.WorkspaceID, counterpartID)
+	if err != nil {
+		return nil, err
+	}
+	if counterpart == nil {
+		return nil, errorsutil.New(400, "payer and payee must belong to the workspace")
+	}
+
+	now := time.Now()
+	settlement := &entities.Settlement{
+		SettlementID: uuid.New(),
+		WorkspaceID:  input.WorkspaceID,
+		FromUserID:   input.FromUserID,
+		ToUserID:     input.ToUserID,
+		Amount:       math.Round(input.Amount*100) / 100,
+		Status:       entities.SettlementStatusPending,
+		CreatedBy:    userID,
+	}
+	if notes := strings.TrimSpace(input.Notes); notes != "" {
+		settlement.Notes = &notes
+	}
+	if evidenceURL := strings.TrimSpace(input.EvidenceURL); evidenceURL != "" {
+		settlement.EvidenceURL = &evidenceURL
+	}
+	if userID == input.FromUserID {
+		settlement.PayerConfirmedAt = &now
+	} else {
+		settlement.PayeeConfirmedAt = &now
+	}
+
+	createdSettlement, err := s.settlementRepo.Create(ctx, settlement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdSettlement, nil
+}
+
+// GetSettlementByID returns a settlement of a workspace the user can view
+func (s *settlementService) GetSettlementByID(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error) {
+	settlement, err := s.settlementRepo.FindByID(ctx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+	if settlement == nil {
+		return nil, errorsutil.New(404, "settlement not found")
+	}
+
+	if _, err := s.authorizer.Authorize(ctx, settlement.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// ListSettlements returns the settlement history of a workspace, cancelled settlements included
+func (s *settlementService) ListSettlements(ctx context.Context, userID uuid.UUID, params *entities.SettlementListParams, limit, offset int) ([]*entities.Settlement, error) {
+	if _, err := s.authorizer.Authorize(ctx, params.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	return s.settlementRepo.List(ctx, params, limit, offset)
+}
+
+// ConfirmSettlement confirms a pending settlement from the side of the user, the payer or the payee.
+// It completes and counts towards the balances once both sides confirmed.
+func (s *settlementService) ConfirmSettlement(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error) {
+	settlement, err := s.findPartySettlement(ctx, userID, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.settlementRepo.Confirm(ctx, settlementID, userID == settlement.FromUserID, userID == settlement.ToUserID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "settlement is no longer pending")
+		}
+		return nil, err
+	}
+
+	return s.settlementRepo.FindByID(ctx, settlementID)
+}
+
+// CancelSettlement cancels a pending settlement, completed settlements are reversed by recording the opposite payment
+func (s *settlementService) CancelSettlement(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error) {
+	if _, err := s.findPartySettlement(ctx, userID, settlementID); err != nil {
+		return nil, err
+	}
+
+	if err := s.settlementRepo.Cancel(ctx, settlementID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "settlement is no longer pending")
+		}
+		return nil, err
+	}
+
+	return s.settlementRepo.FindByID(ctx, settlementID)
+}
+
+// findPartySettlement returns a pending settlement the user is the payer or the payee of
+func (s *settlementService) findPartySettlement(ctx context.Context, userID uuid.UUID, settlementID uuid.UUID) (*entities.Settlement, error) {
+	settlement, err := s.GetSettlementByID(ctx, userID, settlementID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != settlement.FromUserID && userID != settlement.ToUserID {
+		return nil, errorsutil.New(403, "only the payer or the payee can change a settlement")
+	}
+	if settlement.Status != entities.SettlementStatusPending {
+		return nil, errorsutil.New(409, "settlement is no longer pending")
+	}
+
+	return settlement, nil
+}
+
+// findWorkspaceTransaction returns a transaction that belongs to a workspace
+func (s *settlementService) findWorkspaceTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, errorsutil.New(404, "transaction not found")
+	}
+	if transaction.WorkspaceID == nil {
+		return nil, errors.New("transaction does not belong to a workspace")
+	}
+
+	return transaction, nil
+}
+
+// validateTransactionSplits checks that every member appears once with a positive share
+// and that the shares add up to the transaction amount
+func validateTransactionSplits(amount float64, splits []entities.TransactionSplitInput) error {
+	if len(splits) == 0 {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(splits))
+	total := 0.0
+	for _, split := range splits {
+		if split.Amount <= 0 {
+			return errors.New("split amounts must be greater than zero")
+		}
+		if seen[split.UserID] {
+			return errors.New("each member can only appear once in a split")
+		}
+		seen[split.UserID] = true
+		total += split.Amount
+	}
+
+	if math.Abs(total-amount) > settlementAmountTolerance {
+		return errors.New("split amounts must add up to the transaction amount")
+	}
+
+	return nil
+}
+
+// minimizeSettlementTransfers returns transfers that settle the net balances, greedily paying the largest creditor
+// from the largest debtor. Every transfer settles at least one member, so there is at most one transfer fewer
+// than the members with a balance. Balances and transfers under graceAmount are ignored.
+func minimizeSettlementTransfers(balances []*entities.MemberBalance, graceAmount float64) []*entities.SettlementTransfer {
+	type position struct {
+		userID uuid.UUID
+		name   string
+		amount float64
+	}
+
+	var creditors, debtors []*position
+	for _, balance := range balances {
+		amount := math.Round(balance.NetBalance*100) / 100
+		if math.Abs(amount) < graceAmount || amount == 0 {
+			continue
+		}
+		if amount > 0 {
+			creditors = append(creditors, &position{userID: balance.UserID, name: balance.Name, amount: amount})
+		} else {
+			debtors = append(debtors, &position{userID: balance.UserID, name: balance.Name, amount: -amount})
+		}
+	}
+
+	byAmount := func(positions []*position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if positions[i].amount != positions[j].amount {
+				return positions[i].amount > positions[j].amount
+			}
+			return positions[i].userID.String() < positions[j].userID.String()
+		}
+	}
+	sort.Slice(creditors, byAmount(creditors))
+	sort.Slice(debtors, byAmount(debtors))
+
+	transfers := []*entities.SettlementTransfer{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		debtor, creditor := debtors[i], creditors[j]
+		amount := math.Min(debtor.amount, creditor.amount)
+		if amount >= graceAmount && amount > 0 {
+			transfers = append(transfers, &entities.SettlementTransfer{
+				FromUserID:   debtor.userID,
+				FromUserName: debtor.name,
+				ToUserID:     creditor.userID,
+				ToUserName:   creditor.name,
+				Amount:       math.Round(amount*100) / 100,
+			})
+		}
+
+		debtor.amount -= amount
+		creditor.amount -= amount
+		if debtor.amount < settlementAmountTolerance {
+			i++
+		}
+		if creditor.amount < settlementAmountTolerance {
+			j++
+		}
+	}
+
+	return transfers
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+func TestValidateTransactionSplits(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	t.Run("given shares adding up to the amount, when validating, then it passes", func(t *testing.T) {
+		err := validateTransactionSplits(100000, []entities.TransactionSplitInput{
+			{UserID: first, Amount: 33333.33},
+			{UserID: second, Amount: 66666.67},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("given no shares, when validating, then the split is removed", func(t *testing.T) {
+		assert.NoError(t, validateTransactionSplits(100000, nil))
+	})
+
+	t.Run("given shares short of the amount, when validating, then it fails", func(t *testing.T) {
+		err := validateTransactionSplits(100000, []entities.TransactionSplitInput{
+			{UserID: first, Amount: 40000},
+			{UserID: second, Amount: 50000},
+		})
+		assert.EqualError(t, err, "split amounts must add up to the transaction amount")
+	})
+
+	t.Run("given a member twice, when validating, then it fails", func(t *testing.T) {
+		err := validateTransactionSplits(100000, []entities.TransactionSplitInput{
+			{UserID: first, Amount: 50000},
+			{UserID: first, Amount: 50000},
+		})
+		assert.EqualError(t, err, "each member can only appear once in a split")
+	})
+}
+
+func TestMinimizeSettlementTransfers(t *testing.T) {
+	alice, budi, citra, dewi := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("given one creditor and two debtors, when settling, then each debtor pays the creditor once", func(t *testing.T) {
+		transfers := minimizeSettlementTransfers([]*entities.MemberBalance{
+			{UserID: alice, Name: "Alice", NetBalance: 300000},
+			{UserID: budi, Name: "Budi", NetBalance: -200000},
+			{UserID: citra, Name: "Citra", NetBalance: -100000},
+		}, entities.SettlementGraceAmount)
+
+		assert.Len(t, transfers, 2)
+		assert.Equal(t, budi, transfers[0].FromUserID)
+		assert.Equal(t, alice, transfers[0].ToUserID)
+		assert.Equal(t, 200000.0, transfers[0].Amount)
+		assert.Equal(t, citra, transfers[1].FromUserID)
+		assert.Equal(t, 100000.0, transfers[1].Amount)
+	})
+
+	t.Run("given two creditors and two debtors, when settling, then at most three transfers settle everyone", func(t *testing.T) {
+		transfers := minimizeSettlementTransfers([]*entities.MemberBalance{
+			{UserID: alice, NetBalance: 250000},
+			{UserID: budi, NetBalance: 50000},
+			{UserID: citra, NetBalance: -150000},
+			{UserID: dewi, NetBalance: -150000},
+		}, entities.SettlementGraceAmount)
+
+		assert.LessOrEqual(t, len(transfers), 3)
+		received := map[uuid.UUID]float64{}
+		for _, transfer := range transfers {
+			received[transfer.ToUserID] += transfer.Amount
+		}
+		assert.Equal(t, 250000.0, received[alice])
+		assert.Equal(t, 50000.0, received[budi])
+	})
+
+	t.Run("given balances under the grace amount, when settling, then no transfer is needed", func(t *testing.T) {
+		transfers := minimizeSettlementTransfers([]*entities.MemberBalance{
+			{UserID: alice, NetBalance: 7500},
+			{UserID: budi, NetBalance: -7500},
+		}, entities.SettlementGraceAmount)
+
+		assert.Empty(t, transfers)
+	})
+
+	t.Run("given a remainder under the grace amount, when settling, then it is not transferred", func(t *testing.T) {
+		transfers := minimizeSettlementTransfers([]*entities.MemberBalance{
+			{UserID: alice, NetBalance: 100000},
+			{UserID: budi, NetBalance: 5000},
+			{UserID: citra, NetBalance: -105000},
+		}, entities.SettlementGraceAmount)
+
+		assert.Len(t, transfers, 1)
+		assert.Equal(t, alice, transfers[0].ToUserID)
+		assert.Equal(t, 100000.0, transfers[0].Amount)
+	})
+}
+
+// memberAuthorizer lets the members of one workspace do anything in it
+type memberAuthorizer struct {
+	workspace *entities.Workspace
+	members   map[uuid.UUID]bool
+}
+
+func (a *memberAuthorizer) Authorize(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, permission entities.WorkspacePermission) (*entities.Workspace, error) {
+	if workspaceID != a.workspace.WorkspaceID || !a.members[userID] {
+		return nil, errorsutil.New(403, "access denied to workspace")
+	}
+	return a.workspace, nil
+}
+
+func (a *memberAuthorizer) AuthorizeOwnData(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, createdBy *uuid.UUID) (*entities.Workspace, error) {
+	return a.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+}
+
+func (a *memberAuthorizer) GetMembership(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error) {
+	if workspaceID != a.workspace.WorkspaceID || !a.members[userID] {
+		return nil, nil
+	}
+	return &entities.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID}, nil
+}
+
+// recordedEvents keeps the domain events of a test
+type recordedEvents []*entities.DomainEvent
+
+func (e *recordedEvents) Record(ctx context.Context, event *entities.DomainEvent) {
+	*e = append(*e, event)
+}
+
+// splitTransactionRepo finds the transactions of a test, the other methods are not used by splits
+type splitTransactionRepo struct {
+	repositories.TransactionRepository
+	transactions map[uuid.UUID]*entities.Transaction
+}
+
+func (r *splitTransactionRepo) FindByID(ctx context.Context, transactionID uuid.UUID) (*entities.Transaction, error) {
+	return r.transactions[transactionID], nil
+}
+
+// memorySplitRepo keeps the splits of transactions in memory, the other methods are not used by splits
+type memorySplitRepo struct {
+	repositories.SettlementRepository
+	splits map[uuid.UUID][]*entities.TransactionSplit
+}
+
+func (r *memorySplitRepo) FindSplitsByTransaction(ctx context.Context, transactionID uuid.UUID) ([]*entities.TransactionSplit, error) {
+	return r.splits[transactionID], nil
+}
+
+func (r *memorySplitRepo) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []*entities.TransactionSplit) ([]*entities.TransactionSplit, error) {
+	r.splits[transactionID] = splits
+	return splits, nil
+}
+
+func TestSetTransactionSplits(t *testing.T) {
+	ctx := context.Background()
+	payer, first, second := uuid.New(), uuid.New(), uuid.New()
+	workspace := &entities.Workspace{WorkspaceID: uuid.New()}
+	transaction := &entities.Transaction{
+		TransactionID:   uuid.New(),
+		WorkspaceID:     &workspace.WorkspaceID,
+		TransactionType: entities.TransactionTypeExpense,
+		Description:     "Makan malam",
+		Amount:          300000,
+		CreatedBy:       &payer,
+	}
+
+	t.Run("given an expense split before, when splitting it again, then the replaced splits are recorded", func(t *testing.T) {
+		splitRepo := &memorySplitRepo{splits: map[uuid.UUID][]*entities.TransactionSplit{}}
+		events := &recordedEvents{}
+		s := NewSettlementService(splitRepo,
+			&splitTransactionRepo{transactions: map[uuid.UUID]*entities.Transaction{transaction.TransactionID: transaction}},
+			&memberAuthorizer{workspace: workspace, members: map[uuid.UUID]bool{payer: true, first: true, second: true}},
+			events)
+
+		_, err := s.SetTransactionSplits(ctx, payer, transaction.TransactionID, &entities.SetTransactionSplitsRequest{Splits: []entities.TransactionSplitInput{
+			{UserID: payer, Amount: 150000},
+			{UserID: first, Amount: 150000},
+		}})
+		assert.NoError(t, err)
+		before := splitRepo.splits[transaction.TransactionID]
+
+		after, err := s.SetTransactionSplits(ctx, payer, transaction.TransactionID, &entities.SetTransactionSplitsRequest{Splits: []entities.TransactionSplitInput{
+			{UserID: payer, Amount: 100000},
+			{UserID: first, Amount: 100000},
+			{UserID: second, Amount: 100000},
+		}})
+		assert.NoError(t, err)
+
+		assert.Len(t, *events, 2)
+		event := (*events)[1]
+		assert.Equal(t, entities.ActivityResourceSplit, event.ResourceType)
+		assert.Equal(t, entities.ActivityActionUpdated, event.Action)
+		assert.Equal(t, transaction.TransactionID, *event.ResourceID)
+		assert.Equal(t, before, event.OldValues)
+		assert.Equal(t, after, event.NewValues)
+	})
+}
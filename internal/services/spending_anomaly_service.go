@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=spending_anomaly_service.go -package=mock -destination=mock/spending_anomaly_service_mock.go
+type (
+	SpendingAnomalyService interface {
+		DetectAnomalies(ctx context.Context, batchSize int) (int64, error)
+		GetAnomalies(ctx context.Context, userID uuid.UUID, status int, limit, offset int) ([]*entities.SpendingAnomaly, error)
+		DismissAnomaly(ctx context.Context, userID uuid.UUID, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error)
+	}
+
+	spendingAnomalyService struct {
+		anomalyRepo         repositories.SpendingAnomalyRepository
+		workspaceRepo       repositories.WorkspaceRepository
+		notificationService NotificationService
+	}
+)
+
+// NewSpendingAnomalyService creates a new spending anomaly service
+func NewSpendingAnomalyService(anomalyRepo repositories.SpendingAnomalyRepository, workspaceRepo repositories.WorkspaceRepository, notificationService NotificationService) SpendingAnomalyService {
+	return &spendingAnomalyService{
+		anomalyRepo:         anomalyRepo,
+		workspaceRepo:       workspaceRepo,
+		notificationService: notificationService,
+	}
+}
+
+// DetectAnomalies checks up to batchSize transactions not checked yet against the baselines of their users,
+// stores what stands out and notifies the users when their workspace settings ask for it. It returns the number of anomalies found.
+func (s *spendingAnomalyService) DetectAnomalies(ctx context.Context, batchSize int) (int64, error) {
+	candidates, err := s.anomalyRepo.FindUncheckedTransactions(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var found int64
+	checkedIDs := make([]uuid.UUID, 0, len(candidates))
+	notifyByWorkspace := make(map[uuid.UUID]bool)
+	for _, candidate := range candidates {
+		// Expenses without an exchange rate are marked checked without a comparison
+		if candidate.TransactionType == entities.TransactionTypeExpense && candidate.TransferAccountID == nil && !candidate.Unconverted {
+			baselineStart := candidate.TransactionDate.AddDate(0, 0, -anomalyLookbackDays)
+			baseline, err := s.anomalyRepo.FindBaseline(ctx, candidate, baselineStart)
+			if err != nil {
+				return found, err
+			}
+
+			for _, anomaly := range detectSpendingAnomalies(candidate, baseline) {
+				anomaly.SpendingAnomalyID = uuid.New()
+				createdAnomaly, err := s.anomalyRepo.Create(ctx, anomaly)
+				if err != nil {
+					return found, err
+				}
+				if createdAnomaly == nil {
+					continue
+				}
+				found++
+
+				notify, err := s.notifiesAnomalies(ctx, candidate.WorkspaceID, notifyByWorkspace)
+				if err != nil {
+					return found, err
+				}
+				if !notify {
+					continue
+				}
+
+				title, body := spendingAnomalyMessage(candidate, createdAnomaly)
+				_, err = s.notificationService.Notify(ctx, candidate.UserID, entities.NotificationTypeSpendingAnomaly, title, body, map[string]interface{}{
+					"spending_anomaly_id": createdAnomaly.SpendingAnomalyID,
+					"transaction_id":      createdAnomaly.TransactionID,
+					"workspace_id":        createdAnomaly.WorkspaceID,
+					"anomaly_type":        createdAnomaly.AnomalyType,
+				})
+				if err != nil {
+					return found, err
+				}
+			}
+		}
+
+		checkedIDs = append(checkedIDs, candidate.TransactionID)
+	}
+
+	if len(checkedIDs) > 0 {
+		if err := s.anomalyRepo.MarkChecked(ctx, checkedIDs); err != nil {
+			return found, err
+		}
+	}
+
+	return found, nil
+}
+
+// notifiesAnomalies reports whether the settings of a workspace ask for spending anomaly notifications, cached per batch
+func (s *spendingAnomalyService) notifiesAnomalies(ctx context.Context, workspaceID uuid.UUID, cache map[uuid.UUID]bool) (bool, error) {
+	if notify, ok := cache[workspaceID]; ok {
+		return notify, nil
+	}
+
+	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+	notify := workspace != nil && workspace.Settings.Notifications.SpendingAnomalies
+	cache[workspaceID] = notify
+
+	return notify, nil
+}
+
+// GetAnomalies returns the anomalies of a user, optionally filtered by status
+func (s *spendingAnomalyService) GetAnomalies(ctx context.Context, userID uuid.UUID, status int, limit, offset int) ([]*entities.SpendingAnomaly, error) {
+	if status != 0 && status != entities.AnomalyStatusOpen && status != entities.AnomalyStatusDismissed {
+		return nil, errorsutil.New(400, "invalid status")
+	}
+
+	anomalies, err := s.anomalyRepo.FindByUser(ctx, userID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if anomalies == nil {
+		anomalies = []*entities.SpendingAnomaly{}
+	}
+
+	return anomalies, nil
+}
+
+// DismissAnomaly marks an anomaly as expected. Its transaction counts as normal spending in later baselines.
+func (s *spendingAnomalyService) DismissAnomaly(ctx context.Context, userID uuid.UUID, anomalyID uuid.UUID) (*entities.SpendingAnomaly, error) {
+	anomaly, err := s.anomalyRepo.FindByID(ctx, anomalyID)
+	if err != nil {
+		return nil, err
+	}
+	if anomaly == nil {
+		return nil, errorsutil.New(404, "spending anomaly not found")
+	}
+
+	if anomaly.UserID != userID {
+		return nil, errorsutil.New(403, "access denied to spending anomaly")
+	}
+
+	dismissedAnomaly, err := s.anomalyRepo.Dismiss(ctx, anomalyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(404, "spending anomaly not found")
+		}
+		return nil, err
+	}
+
+	return dismissedAnomaly, nil
+}
+
+// spendingAnomalyMessage returns the notification title and body of an anomaly
+func spendingAnomalyMessage(candidate *entities.AnomalyCandidate, anomaly *entities.SpendingAnomaly) (string, string) {
+	subject := candidate.Description
+	if candidate.MerchantName != nil && *candidate.MerchantName != "" {
+		subject = *candidate.MerchantName
+	}
+	amount := formatAmount("", candidate.Amount)
+
+	switch anomaly.AnomalyType {
+	case entities.AnomalyTypeCategoryOutlier:
+		return "Unusually large expense",
+			fmt.Sprintf("%s of %s is well above your usual %s in this category.", subject, amount, formatAmount("", anomaly.ExpectedValue))
+	case entities.AnomalyTypeNewMerchant:
+		return "Large expense at a new merchant",
+			fmt.Sprintf("This is your first expense at %s and at %s it is larger than most of your expenses.", subject, amount)
+	case entities.AnomalyTypeUnusualHour:
+		return "Expense at an unusual hour",
+			fmt.Sprintf("%s of %s was recorded around %02d:00, when you rarely spend.", subject, amount, int(anomaly.ActualValue))
+	case entities.AnomalyTypeCategorySpike:
+		return "Spending spike in a category",
+			fmt.Sprintf("You spent %s in this category over the last 7 days, against %s in a usual week.", formatAmount("", anomaly.ActualValue), formatAmount("", anomaly.ExpectedValue))
+	default:
+		return "Unusual expense", fmt.Sprintf("%s of %s looks unusual.", subject, amount)
+	}
+}
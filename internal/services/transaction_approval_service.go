@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=transaction_approval_service.go -package=mock -destination=mock/transaction_approval_service_mock.go
+type (
+	TransactionApprovalService interface {
+		GetPendingApprovals(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, limit, offset int) ([]*entities.Transaction, int64, error)
+		SubmitTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error)
+		ApproveTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error)
+		RejectTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.RejectTransactionRequest) (*entities.Transaction, error)
+		ReimburseTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error)
+		HandleQuickReply(ctx context.Context, userID uuid.UUID, input *entities.ApprovalQuickReplyRequest) (*entities.Transaction, error)
+		// NotifyApprovers tells the approvers of a workspace that an expense waits for their decision
+		NotifyApprovers(ctx context.Context, workspace *entities.Workspace, transaction *entities.Transaction)
+	}
+
+	transactionApprovalService struct {
+		transactionRepo     repositories.TransactionRepository
+		memberRepo          repositories.WorkspaceMemberRepository
+		rollupRepo          repositories.TransactionRollupRepository
+		authorizer          WorkspaceAuthorizer
+		notificationService NotificationService
+		events              DomainEventRecorder
+	}
+)
+
+// NewTransactionApprovalService creates a new transaction approval service
+func NewTransactionApprovalService(
+	transactionRepo repositories.TransactionRepository,
+	memberRepo repositories.WorkspaceMemberRepository,
+	rollupRepo repositories.TransactionRollupRepository,
+	authorizer WorkspaceAuthorizer,
+	notificationService NotificationService,
+	events DomainEventRecorder,
+) TransactionApprovalService {
+	return &transactionApprovalService{
+		transactionRepo:     transactionRepo,
+		memberRepo:          memberRepo,
+		rollupRepo:          rollupRepo,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		events:              events,
+	}
+}
+
+// GetPendingApprovals returns the submitted expenses of a workspace waiting for a decision
+func (s *transactionApprovalService) GetPendingApprovals(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, limit, offset int) ([]*entities.Transaction, int64, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionApproveExpense); err != nil {
+		return nil, 0, err
+	}
+
+	status := entities.ApprovalStatusSubmitted
+	params := &entities.TransactionListParams{ApprovalStatus: &status}
+
+	transactions, err := s.transactionRepo.FindByWorkspace(ctx, workspaceID, params, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.transactionRepo.CountByWorkspace(ctx, workspaceID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+// SubmitTransaction submits a draft or rejected expense for approval
+func (s *transactionApprovalService) SubmitTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.findTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members may only submit the expenses they recorded
+	workspace, err := s.authorizer.AuthorizeOwnData(ctx, *transaction.WorkspaceID, userID, transaction.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.ApprovalStatus != entities.ApprovalStatusDraft && transaction.ApprovalStatus != entities.ApprovalStatusRejected {
+		return nil, errorsutil.New(409, "transaction cannot be submitted")
+	}
+
+	updated, err := s.moveApproval(ctx, userID, transaction, entities.ApprovalStatusSubmitted, nil, false, entities.ActivityActionSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	s.NotifyApprovers(ctx, workspace, updated)
+	return updated, nil
+}
+
+// ApproveTransaction approves a submitted expense, it then counts toward budgets and reports
+func (s *transactionApprovalService) ApproveTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.findDecidableTransaction(ctx, userID, transactionID, entities.ApprovalStatusSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.moveApproval(ctx, userID, transaction, entities.ApprovalStatusApproved, nil, true, entities.ActivityActionApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(ctx, updated, "Pengeluaran disetujui",
+		fmt.Sprintf("Pengeluaran \"%s\" sebesar %s telah disetujui", updated.Description, formatAmount("", updated.Amount)))
+	return updated, nil
+}
+
+// RejectTransaction rejects a submitted expense with the reason, its author may edit and submit it again
+func (s *transactionApprovalService) RejectTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.RejectTransactionRequest) (*entities.Transaction, error) {
+	note := strings.TrimSpace(input.Note)
+	if note == "" {
+		return nil, errors.New("rejection note is required")
+	}
+
+	transaction, err := s.findDecidableTransaction(ctx, userID, transactionID, entities.ApprovalStatusSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.moveApproval(ctx, userID, transaction, entities.ApprovalStatusRejected, &note, true, entities.ActivityActionRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(ctx, updated, "Pengeluaran ditolak",
+		fmt.Sprintf("Pengeluaran \"%s\" ditolak: %s", updated.Description, note))
+	return updated, nil
+}
+
+// ReimburseTransaction marks an approved expense as paid back to its author
+func (s *transactionApprovalService) ReimburseTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.findTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionApproveExpense); err != nil {
+		return nil, err
+	}
+	if transaction.ApprovalStatus != entities.ApprovalStatusApproved {
+		return nil, errorsutil.New(409, "transaction is not approved")
+	}
+
+	updated, err := s.moveApproval(ctx, userID, transaction, entities.ApprovalStatusReimbursed, transaction.ApprovalNote, true, entities.ActivityActionReimbursed)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(ctx, updated, "Pengeluaran dibayar kembali",
+		fmt.Sprintf("Pengeluaran \"%s\" sebesar %s telah dibayar kembali", updated.Description, formatAmount("", updated.Amount)))
+	return updated, nil
+}
+
+// HandleQuickReply approves or rejects an expense from a WhatsApp quick reply
+func (s *transactionApprovalService) HandleQuickReply(ctx context.Context, userID uuid.UUID, input *entities.ApprovalQuickReplyRequest) (*entities.Transaction, error) {
+	action, transactionID, err := parseApprovalQuickReply(input.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = middleware.WithSourceChannel(ctx, entities.ChannelWhatsApp)
+	if action == entities.ActivityActionRejected {
+		note := input.Note
+		if strings.TrimSpace(note) == "" {
+			note = "Ditolak lewat WhatsApp"
+		}
+		return s.RejectTransaction(ctx, userID, transactionID, &entities.RejectTransactionRequest{Note: note})
+	}
+	return s.ApproveTransaction(ctx, userID, transactionID)
+}
+
+// NotifyApprovers tells the approvers of a workspace that an expense waits for their decision
+func (s *transactionApprovalService) NotifyApprovers(ctx context.Context, workspace *entities.Workspace, transaction *entities.Transaction) {
+	if !workspace.Settings.Notifications.ApprovalRequests {
+		return
+	}
+
+	members, err := s.memberRepo.FindByWorkspace(ctx, workspace.WorkspaceID)
+	if err != nil {
+		return
+	}
+
+	for _, member := range members {
+		if !roleHasPermission(member.Role, entities.WorkspacePermissionApproveExpense) {
+			continue
+		}
+		if transaction.CreatedBy != nil && *transaction.CreatedBy == member.UserID && member.Role != entities.WorkspaceRoleOwner {
+			continue
+		}
+		_, _ = s.notificationService.Notify(ctx, member.UserID, entities.NotificationTypeApprovalRequest,
+			"Persetujuan pengeluaran",
+			fmt.Sprintf("Pengeluaran \"%s\" sebesar %s menunggu persetujuan Anda", transaction.Description, formatAmount("", transaction.Amount)),
+			map[string]interface{}{
+				"transaction_id": transaction.TransactionID.String(),
+				"workspace_id":   workspace.WorkspaceID.String(),
+				"quick_replies": []string{
+					"approve:" + transaction.TransactionID.String(),
+					"reject:" + transaction.TransactionID.String(),
+				},
+			},
+		)
+	}
+}
+
+// findTransaction returns a workspace transaction, transactions outside a workspace never need approval
+func (s *transactionApprovalService) findTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil || transaction.WorkspaceID == nil {
+		return nil, errorsutil.New(404, "transaction not found")
+	}
+	return transaction, nil
+}
+
+// findDecidableTransaction returns a transaction in the given status the user may decide on
+func (s *transactionApprovalService) findDecidableTransaction(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, status int) (*entities.Transaction, error) {
+	transaction, err := s.findTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionApproveExpense)
+	if err != nil {
+		return nil, err
+	}
+	if !canDecideApproval(workspace.Role, userID, transaction.CreatedBy) {
+		return nil, errorsutil.New(403, "cannot decide on your own expense")
+	}
+	if transaction.ApprovalStatus != status {
+		return nil, errorsutil.New(409, "transaction is not waiting for approval")
+	}
+	return transaction, nil
+}
+
+// moveApproval changes the approval status of a transaction and refreshes what depends on it
+func (s *transactionApprovalService) moveApproval(ctx context.Context, userID uuid.UUID, transaction *entities.Transaction, status int, note *string, decided bool, action string) (*entities.Transaction, error) {
+	var decidedBy *uuid.UUID
+	if decided {
+		decidedBy = &userID
+	}
+
+	if err := s.transactionRepo.UpdateApproval(ctx, transaction.TransactionID, transaction.ApprovalStatus, status, note, decidedBy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(409, "transaction approval status has changed")
+		}
+		return nil, err
+	}
+
+	updated, err := s.transactionRepo.FindByID(ctx, transaction.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, errorsutil.New(404, "transaction not found")
+	}
+
+	// A failed refresh is repaired later by the rollup consistency check
+	_ = s.rollupRepo.RefreshDays(ctx, *updated.WorkspaceID, []time.Time{updated.TransactionDate})
+
+	s.events.Record(ctx, &entities.DomainEvent{
+		WorkspaceID:  *updated.WorkspaceID,
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: entities.ActivityResourceTransaction,
+		ResourceID:   &updated.TransactionID,
+		ResourceName: updated.Description,
+		Amount:       &updated.Amount,
+		Detail:       updated.TransactionType,
+		OldValues:    transaction,
+		NewValues:    updated,
+	})
+
+	return updated, nil
+}
+
+// notifySubmitter tells the author of an expense about a decision on it
+func (s *transactionApprovalService) notifySubmitter(ctx context.Context, transaction *entities.Transaction, title, body string) {
+	if transaction.CreatedBy == nil {
+		return
+	}
+	_, _ = s.notificationService.Notify(ctx, *transaction.CreatedBy, entities.NotificationTypeApprovalDecision, title, body,
+		map[string]interface{}{
+			"transaction_id":  transaction.TransactionID.String(),
+			"workspace_id":    transaction.WorkspaceID.String(),
+			"approval_status": transaction.ApprovalStatus,
+		},
+	)
+}
+
+// requiresApproval tells whether an expense recorded by userID in a workspace must be approved before it counts.
+// Only business workspaces have approvals, for the members listed in the settings or above the amount threshold.
+func requiresApproval(workspace *entities.Workspace, userID uuid.UUID, transactionType int, amount float64) bool {
+	if workspace.WorkspaceType != entities.WorkspaceTypeBusiness || transactionType != entities.TransactionTypeExpense {
+		return false
+	}
+
+	approvals := workspace.Settings.Approvals
+	for _, requiredUserID := range approvals.RequiredForUserIDs {
+		if requiredUserID == userID {
+			return true
+		}
+	}
+	return approvals.Enabled && amount > approvals.AmountThreshold
+}
+
+// approvalStatusAfterUpdate is the approval status of an edited transaction. An approved expense whose amount
+// changed is submitted again, an expense that no longer needs approval leaves the workflow.
+func approvalStatusAfterUpdate(current int, required bool, amountChanged bool) int {
+	switch {
+	case current == entities.ApprovalStatusReimbursed:
+		return current
+	case !required:
+		if current == entities.ApprovalStatusApproved {
+			return current
+		}
+		return entities.ApprovalStatusNotRequired
+	case current == entities.ApprovalStatusNotRequired:
+		return entities.ApprovalStatusSubmitted
+	case current == entities.ApprovalStatusApproved && amountChanged:
+		return entities.ApprovalStatusSubmitted
+	default:
+		return current
+	}
+}
+
+// canDecideApproval tells whether a member with role may approve or reject an expense created by createdBy.
+// Approvers cannot decide on their own expenses, except the owner who has no one above them.
+func canDecideApproval(role int, userID uuid.UUID, createdBy *uuid.UUID) bool {
+	if createdBy == nil || *createdBy != userID {
+		return true
+	}
+	return role == entities.WorkspaceRoleOwner
+}
+
+// parseApprovalQuickReply reads a quick reply payload such as "approve:<transaction_id>" or "reject:<transaction_id>"
+// and returns the activity action it stands for
+func parseApprovalQuickReply(payload string) (string, uuid.UUID, error) {
+	action, id, found := strings.Cut(strings.TrimSpace(payload), ":")
+	if !found {
+		return "", uuid.Nil, errors.New("invalid quick reply payload")
+	}
+
+	transactionID, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return "", uuid.Nil, errors.New("invalid quick reply payload")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(action)) {
+	case "approve":
+		return entities.ActivityActionApproved, transactionID, nil
+	case "reject":
+		return entities.ActivityActionRejected, transactionID, nil
+	default:
+		return "", uuid.Nil, errors.New("invalid quick reply payload")
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	userID := uuid.New()
+	businessWorkspace := func(approvals entities.WorkspaceApprovalSettings) *entities.Workspace {
+		settings := entities.DefaultWorkspaceSettings(entities.WorkspaceTypeBusiness)
+		settings.Approvals = approvals
+		return &entities.Workspace{WorkspaceType: entities.WorkspaceTypeBusiness, Settings: settings}
+	}
+
+	t.Run("given approvals above a threshold, when the expense exceeds it, then it needs approval", func(t *testing.T) {
+		workspace := businessWorkspace(entities.WorkspaceApprovalSettings{Enabled: true, AmountThreshold: 500000})
+		assert.True(t, requiresApproval(workspace, userID, entities.TransactionTypeExpense, 750000))
+		assert.False(t, requiresApproval(workspace, userID, entities.TransactionTypeExpense, 500000))
+	})
+
+	t.Run("given a member whose expenses always need approval, when approvals are off, then it still needs approval", func(t *testing.T) {
+		workspace := businessWorkspace(entities.WorkspaceApprovalSettings{RequiredForUserIDs: []uuid.UUID{userID}})
+		assert.True(t, requiresApproval(workspace, userID, entities.TransactionTypeExpense, 1000))
+		assert.False(t, requiresApproval(workspace, uuid.New(), entities.TransactionTypeExpense, 1000))
+	})
+
+	t.Run("given income or a personal workspace, when recording, then no approval is needed", func(t *testing.T) {
+		workspace := businessWorkspace(entities.WorkspaceApprovalSettings{Enabled: true})
+		assert.False(t, requiresApproval(workspace, userID, entities.TransactionTypeIncome, 1000))
+
+		workspace.WorkspaceType = entities.WorkspaceTypePersonal
+		assert.False(t, requiresApproval(workspace, userID, entities.TransactionTypeExpense, 1000))
+	})
+}
+
+func TestApprovalStatusAfterUpdate(t *testing.T) {
+	t.Run("given an approved expense, when its amount changes, then it is submitted again", func(t *testing.T) {
+		assert.Equal(t, entities.ApprovalStatusSubmitted, approvalStatusAfterUpdate(entities.ApprovalStatusApproved, true, true))
+		assert.Equal(t, entities.ApprovalStatusApproved, approvalStatusAfterUpdate(entities.ApprovalStatusApproved, true, false))
+	})
+
+	t.Run("given an expense raised above the threshold, when updating, then it is submitted", func(t *testing.T) {
+		assert.Equal(t, entities.ApprovalStatusSubmitted, approvalStatusAfterUpdate(entities.ApprovalStatusNotRequired, true, true))
+	})
+
+	t.Run("given a pending expense lowered below the threshold, when updating, then it leaves the workflow", func(t *testing.T) {
+		assert.Equal(t, entities.ApprovalStatusNotRequired, approvalStatusAfterUpdate(entities.ApprovalStatusSubmitted, false, true))
+		assert.Equal(t, entities.ApprovalStatusNotRequired, approvalStatusAfterUpdate(entities.ApprovalStatusRejected, false, true))
+	})
+
+	t.Run("given a reimbursed expense, when updating, then it stays reimbursed", func(t *testing.T) {
+		assert.Equal(t, entities.ApprovalStatusReimbursed, approvalStatusAfterUpdate(entities.ApprovalStatusReimbursed, true, true))
+	})
+}
+
+func TestCanDecideApproval(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	t.Run("given an admin, when deciding on their own expense, then it is refused", func(t *testing.T) {
+		assert.False(t, canDecideApproval(entities.WorkspaceRoleAdmin, userID, &userID))
+		assert.True(t, canDecideApproval(entities.WorkspaceRoleAdmin, userID, &otherID))
+	})
+
+	t.Run("given the owner, when deciding on their own expense, then it is allowed", func(t *testing.T) {
+		assert.True(t, canDecideApproval(entities.WorkspaceRoleOwner, userID, &userID))
+	})
+}
+
+func TestParseApprovalQuickReply(t *testing.T) {
+	transactionID := uuid.New()
+
+	t.Run("given an approve payload, when parsing, then the approve action and transaction are returned", func(t *testing.T) {
+		action, id, err := parseApprovalQuickReply("approve:" + transactionID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ActivityActionApproved, action)
+		assert.Equal(t, transactionID, id)
+	})
+
+	t.Run("given a reject payload in capitals, when parsing, then the reject action is returned", func(t *testing.T) {
+		action, _, err := parseApprovalQuickReply(" REJECT:" + transactionID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ActivityActionRejected, action)
+	})
+
+	t.Run("given an unknown action or a bad ID, when parsing, then the payload is invalid", func(t *testing.T) {
+		_, _, err := parseApprovalQuickReply("pay:" + transactionID.String())
+		assert.EqualError(t, err, "invalid quick reply payload")
+
+		_, _, err = parseApprovalQuickReply("approve:not-an-id")
+		assert.EqualError(t, err, "invalid quick reply payload")
+	})
+}
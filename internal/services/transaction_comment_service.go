@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=transaction_comment_service.go -package=mock -destination=mock/transaction_comment_service_mock.go
+type (
+	TransactionCommentService interface {
+		GetComments(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) ([]*entities.TransactionComment, error)
+		CreateComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.CreateTransactionCommentRequest) (*entities.TransactionComment, error)
+		UpdateComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, commentID uuid.UUID, input *entities.UpdateTransactionCommentRequest) (*entities.TransactionComment, error)
+		DeleteComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, commentID uuid.UUID) error
+
+		// ReplyToMessage posts a comment on the transaction a bot message is linked to, for replies on WhatsApp
+		ReplyToMessage(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, input *entities.ReplyTransactionCommentRequest) (*entities.TransactionComment, error)
+	}
+
+	transactionCommentService struct {
+		commentRepo         repositories.TransactionCommentRepository
+		transactionRepo     repositories.TransactionRepository
+		memberRepo          repositories.WorkspaceMemberRepository
+		messageRepo         repositories.MessageRepository
+		conversationRepo    repositories.ConversationRepository
+		authorizer          WorkspaceAuthorizer
+		notificationService NotificationService
+	}
+)
+
+// NewTransactionCommentService creates a new transaction comment service
+func NewTransactionCommentService(
+	commentRepo repositories.TransactionCommentRepository,
+	transactionRepo repositories.TransactionRepository,
+	memberRepo repositories.WorkspaceMemberRepository,
+	messageRepo repositories.MessageRepository,
+	conversationRepo repositories.ConversationRepository,
+	authorizer WorkspaceAuthorizer,
+	notificationService NotificationService,
+) TransactionCommentService {
+	return &transactionCommentService{
+		commentRepo:         commentRepo,
+		transactionRepo:     transactionRepo,
+		memberRepo:          memberRepo,
+		messageRepo:         messageRepo,
+		conversationRepo:    conversationRepo,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+	}
+}
+
+// commentMentionPattern matches @name mentions, names are a single word of letters, digits, dots, dashes or underscores
+var commentMentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.-]+)`)
+
+// GetComments returns the thread of a transaction, oldest first
+func (s *transactionCommentService) GetComments(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID) ([]*entities.TransactionComment, error) {
+	transaction, err := s.findWorkspaceTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.FindByTransaction(ctx, transactionID)
+}
+
+// CreateComment posts a comment on a transaction and notifies the mentioned members
+func (s *transactionCommentService) CreateComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.CreateTransactionCommentRequest) (*entities.TransactionComment, error) {
+	return s.createComment(ctx, userID, transactionID, input, nil)
+}
+
+// UpdateComment changes a comment, only its author may edit it. Members mentioned for the first time are notified.
+func (s *transactionCommentService) UpdateComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, commentID uuid.UUID, input *entities.UpdateTransactionCommentRequest) (*entities.TransactionComment, error) {
+	content := strings.TrimSpace(input.Content)
+	if err := validateTransactionComment(content, input.Attachments); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.findComment(ctx, transactionID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizer.Authorize(ctx, comment.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction); err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, errorsutil.New(403, "only the author can edit a comment")
+	}
+
+	members, err := s.memberRepo.FindByWorkspace(ctx, comment.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	mentions, err := resolveCommentMentions(content, input.Mentions, members)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMentions := comment.Mentions
+	comment.Content = content
+	comment.Mentions = mentions
+	comment.Attachments = input.Attachments
+
+	updatedComment, err := s.commentRepo.Update(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, updatedComment, newCommentMentions(previousMentions, mentions))
+	return updatedComment, nil
+}
+
+// DeleteComment removes a comment, members may delete their own comments and admins any comment
+func (s *transactionCommentService) DeleteComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, commentID uuid.UUID) error {
+	comment, err := s.findComment(ctx, transactionID, commentID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.authorizer.AuthorizeOwnData(ctx, comment.WorkspaceID, userID, &comment.UserID); err != nil {
+		return err
+	}
+
+	return s.commentRepo.Delete(ctx, commentID)
+}
+
+// ReplyToMessage posts a comment on the transaction a message of the user's conversation is linked to
+func (s *transactionCommentService) ReplyToMessage(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, input *entities.ReplyTransactionCommentRequest) (*entities.TransactionComment, error) {
+	message, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, errorsutil.New(404, "message not found")
+	}
+
+	conversation, err := s.conversationRepo.FindByID(ctx, message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil || conversation.UserID != userID {
+		return nil, errorsutil.New(403, "access denied to message")
+	}
+	if message.RelatedTransactionID == nil {
+		return nil, errors.New("message is not linked to a transaction")
+	}
+
+	ctx = middleware.WithSourceChannel(ctx, entities.ChannelWhatsApp)
+	return s.createComment(ctx, userID, *message.RelatedTransactionID, &entities.CreateTransactionCommentRequest{
+		Content:     input.Content,
+		Attachments: input.Attachments,
+	}, &message.MessageID)
+}
+
+func (s *transactionCommentService) createComment(ctx context.Context, userID uuid.UUID, transactionID uuid.UUID, input *entities.CreateTransactionCommentRequest, sourceMessageID *uuid.UUID) (*entities.TransactionComment, error) {
+	content := strings.TrimSpace(input.Content)
+	if err := validateTransactionComment(content, input.Attachments); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.findWorkspaceTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	// Viewers can read the thread but not post to it
+	if _, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction); err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.FindByWorkspace(ctx, *transaction.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	mentions, err := resolveCommentMentions(content, input.Mentions, members)
+	if err != nil {
+		return nil, err
+	}
+
+	createdComment, err := s.commentRepo.Create(ctx, &entities.TransactionComment{
+		CommentID:       uuid.New(),
+		TransactionID:   transactionID,
+		WorkspaceID:     *transaction.WorkspaceID,
+		UserID:          userID,
+		Content:         content,
+		Mentions:        mentions,
+		Attachments:     input.Attachments,
+		Channel:         middleware.SourceChannelFromContext(ctx),
+		SourceMessageID: sourceMessageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyMentions(ctx, createdComment, mentions)
+	return createdComment, nil
+}
+
+// notifyMentions tells mentioned members about a comment, the author is never notified.
+// A failed notification does not undo the comment.
+func (s *transactionCommentService) notifyMentions(ctx context.Context, comment *entities.TransactionComment, mentions []uuid.UUID) {
+	for _, mentionedUserID := range mentions {
+		if mentionedUserID == comment.UserID {
+			continue
+		}
+		_, _ = s.notificationService.Notify(ctx, mentionedUserID, entities.NotificationTypeTransactionMention,
+			"Anda disebut dalam komentar",
+			fmt.Sprintf("%s menyebut Anda dalam komentar transaksi", comment.AuthorName),
+			map[string]interface{}{
+				"comment_id":     comment.CommentID.String(),
+				"transaction_id": comment.TransactionID.String(),
+				"workspace_id":   comment.WorkspaceID.String(),
+			},
+		)
+	}
+}
+
+// findWorkspaceTransaction returns a transaction that belongs to a workspace, personal transactions have no thread
+func (s *transactionCommentService) findWorkspaceTransaction(ctx context.Context, transactionID uuid.UUID) (*entities.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, errorsutil.New(404, "transaction not found")
+	}
+	if transaction.WorkspaceID == nil {
+		return nil, errors.New("transaction does not belong to a workspace")
+	}
+	return transaction, nil
+}
+
+// findComment returns a comment of a transaction
+func (s *transactionCommentService) findComment(ctx context.Context, transactionID uuid.UUID, commentID uuid.UUID) (*entities.TransactionComment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.TransactionID != transactionID {
+		return nil, errorsutil.New(404, "comment not found")
+	}
+	return comment, nil
+}
+
+// validateTransactionComment checks a comment has content or attachments within the limits
+func validateTransactionComment(content string, attachments []entities.CommentAttachment) error {
+	if content == "" && len(attachments) == 0 {
+		return errors.New("comment content or attachment is required")
+	}
+	if len([]rune(content)) > entities.TransactionCommentMaxLength {
+		return errors.New("comment is too long")
+	}
+	if len(attachments) > entities.TransactionCommentMaxAttachments {
+		return errors.New("too many attachments")
+	}
+	for _, attachment := range attachments {
+		if strings.TrimSpace(attachment.URL) == "" {
+			return errors.New("attachment url is required")
+		}
+	}
+	return nil
+}
+
+// resolveCommentMentions returns the members mentioned by user ID or with @name in the content, in order and without duplicates.
+// An @name matches a member's full name without spaces or, when no other member shares it, their first name.
+// Names that match no member or several members are left as plain text.
+func resolveCommentMentions(content string, mentionIDs []uuid.UUID, members []*entities.WorkspaceMember) (entities.CommentMentions, error) {
+	isMember := make(map[uuid.UUID]bool, len(members))
+	byFullName := make(map[string][]uuid.UUID)
+	byFirstName := make(map[string][]uuid.UUID)
+	for _, member := range members {
+		isMember[member.UserID] = true
+		words := strings.Fields(strings.ToLower(member.Name))
+		if len(words) == 0 {
+			continue
+		}
+		fullName := strings.Join(words, "")
+		byFullName[fullName] = append(byFullName[fullName], member.UserID)
+		byFirstName[words[0]] = append(byFirstName[words[0]], member.UserID)
+	}
+
+	mentions := entities.CommentMentions{}
+	seen := make(map[uuid.UUID]bool)
+	add := func(userID uuid.UUID) {
+		if !seen[userID] {
+			seen[userID] = true
+			mentions = append(mentions, userID)
+		}
+	}
+
+	for _, userID := range mentionIDs {
+		if !isMember[userID] {
+			return nil, errors.New("mentioned user is not a member of the workspace")
+		}
+		add(userID)
+	}
+
+	for _, match := range commentMentionPattern.FindAllStringSubmatch(content, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if candidates := byFullName[name]; len(candidates) == 1 {
+			add(candidates[0])
+		} else if candidates := byFirstName[name]; len(candidates) == 1 {
+			add(candidates[0])
+		}
+	}
+
+	return mentions, nil
+}
+
+// newCommentMentions returns the mentions that were not in the previous mentions
+func newCommentMentions(previous, current []uuid.UUID) []uuid.UUID {
+	known := make(map[uuid.UUID]bool, len(previous))
+	for _, userID := range previous {
+		known[userID] = true
+	}
+
+	var added []uuid.UUID
+	for _, userID := range current {
+		if !known[userID] {
+			added = append(added, userID)
+		}
+	}
+	return added
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestResolveCommentMentions(t *testing.T) {
+	budi := &entities.WorkspaceMember{UserID: uuid.New(), Name: "Budi Santoso"}
+	siti := &entities.WorkspaceMember{UserID: uuid.New(), Name: "Siti Aminah"}
+	sitiRahma := &entities.WorkspaceMember{UserID: uuid.New(), Name: "Siti Rahma"}
+	members := []*entities.WorkspaceMember{budi, siti, sitiRahma}
+
+	t.Run("given a unique first name, when resolving, then the member is mentioned", func(t *testing.T) {
+		mentions, err := resolveCommentMentions("@budi ini struk makan siang kemarin", nil, members)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.CommentMentions{budi.UserID}, mentions)
+	})
+
+	t.Run("given a shared first name, when resolving, then only the full name mentions the member", func(t *testing.T) {
+		mentions, err := resolveCommentMentions("@Siti tolong cek, @SitiRahma juga.", nil, members)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.CommentMentions{sitiRahma.UserID}, mentions)
+	})
+
+	t.Run("given user IDs and names of the same member, when resolving, then the member is mentioned once", func(t *testing.T) {
+		mentions, err := resolveCommentMentions("@budi @andi", []uuid.UUID{siti.UserID, budi.UserID}, members)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.CommentMentions{siti.UserID, budi.UserID}, mentions)
+	})
+
+	t.Run("given a user ID outside the workspace, when resolving, then it fails", func(t *testing.T) {
+		_, err := resolveCommentMentions("", []uuid.UUID{uuid.New()}, members)
+		assert.EqualError(t, err, "mentioned user is not a member of the workspace")
+	})
+}
+
+func TestValidateTransactionComment(t *testing.T) {
+	attachment := entities.CommentAttachment{URL: "https://example.com/receipt.jpg", FileName: "receipt.jpg", MimeType: "image/jpeg"}
+
+	t.Run("given only an attachment, when validating, then it passes", func(t *testing.T) {
+		assert.NoError(t, validateTransactionComment("", []entities.CommentAttachment{attachment}))
+	})
+
+	t.Run("given neither content nor attachments, when validating, then it fails", func(t *testing.T) {
+		assert.EqualError(t, validateTransactionComment("", nil), "comment content or attachment is required")
+	})
+
+	t.Run("given content over the limit, when validating, then it fails", func(t *testing.T) {
+		content := strings.Repeat("a", entities.TransactionCommentMaxLength+1)
+		assert.EqualError(t, validateTransactionComment(content, nil), "comment is too long")
+	})
+
+	t.Run("given an attachment without a url, when validating, then it fails", func(t *testing.T) {
+		err := validateTransactionComment("lihat lampiran", []entities.CommentAttachment{{FileName: "receipt.jpg"}})
+		assert.EqualError(t, err, "attachment url is required")
+	})
+}
+
+func TestNewCommentMentions(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	t.Run("given an edit adding a mention, when comparing, then only the added member is returned", func(t *testing.T) {
+		assert.Equal(t, []uuid.UUID{second}, newCommentMentions([]uuid.UUID{first}, []uuid.UUID{first, second}))
+	})
+
+	t.Run("given an edit removing a mention, when comparing, then nobody is returned", func(t *testing.T) {
+		assert.Empty(t, newCommentMentions([]uuid.UUID{first, second}, []uuid.UUID{first}))
+	})
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+//go:generate mockgen -source=transaction_rollup_service.go -package=mock -destination=mock/transaction_rollup_service_mock.go
+type (
+	TransactionRollupService interface {
+		RebuildMissing(ctx context.Context) (int, error)
+		CheckConsistency(ctx context.Context, since *time.Time) (int, error)
+	}
+
+	transactionRollupService struct {
+		rollupRepo repositories.TransactionRollupRepository
+	}
+)
+
+// NewTransactionRollupService creates a new transaction rollup service
+func NewTransactionRollupService(rollupRepo repositories.TransactionRollupRepository) TransactionRollupService {
+	return &transactionRollupService{
+		rollupRepo: rollupRepo,
+	}
+}
+
+// RebuildMissing builds the rollups of every workspace that has transactions but no rollups yet.
+// It returns the number of workspaces rebuilt.
+func (s *transactionRollupService) RebuildMissing(ctx context.Context) (int, error) {
+	workspaceIDs, err := s.rollupRepo.FindWorkspacesWithoutRollups(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, workspaceID := range workspaceIDs {
+		if err := s.rollupRepo.RebuildWorkspace(ctx, workspaceID); err != nil {
+			return i, err
+		}
+	}
+
+	return len(workspaceIDs), nil
+}
+
+// CheckConsistency compares the rollups with the transactions since a date, or over the whole history when since is nil,
+// and rebuilds the days that drifted, e.g. after a failed refresh or an account currency change. It returns the number of days repaired.
+func (s *transactionRollupService) CheckConsistency(ctx context.Context, since *time.Time) (int, error) {
+	days, err := s.rollupRepo.FindDriftedDays(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	repaired := 0
+	for workspaceID, dates := range groupRollupDays(days) {
+		if err := s.rollupRepo.RefreshDays(ctx, workspaceID, dates); err != nil {
+			return repaired, err
+		}
+		repaired += len(dates)
+	}
+
+	return repaired, nil
+}
+
+// groupRollupDays groups drifted days by workspace so each workspace is refreshed at once
+func groupRollupDays(days []*entities.RollupDay) map[uuid.UUID][]time.Time {
+	grouped := make(map[uuid.UUID][]time.Time)
+	for _, day := range days {
+		grouped[day.WorkspaceID] = append(grouped[day.WorkspaceID], day.TransactionDate)
+	}
+	return grouped
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestGroupRollupDays(t *testing.T) {
+	t.Run("given drifted days of several workspaces, when grouping, then each workspace gets its days", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		june1 := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+		june2 := time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)
+
+		grouped := groupRollupDays([]*entities.RollupDay{
+			{WorkspaceID: first, TransactionDate: june1},
+			{WorkspaceID: second, TransactionDate: june1},
+			{WorkspaceID: first, TransactionDate: june2},
+		})
+
+		assert.Len(t, grouped, 2)
+		assert.Equal(t, []time.Time{june1, june2}, grouped[first])
+		assert.Equal(t, []time.Time{june1}, grouped[second])
+	})
+}
+
+// memoryRollupRepo compares the expense totals of the transactions with their rollups per workspace and day.
+// Refreshing or rebuilding a workspace in failFor fails.
+type memoryRollupRepo struct {
+	totals  map[uuid.UUID]map[time.Time]float64
+	rollups map[uuid.UUID]map[time.Time]float64
+	order   []uuid.UUID // workspaces in the order they are listed
+	failFor map[uuid.UUID]bool
+
+	refreshedDays int
+	rebuilt       []uuid.UUID
+}
+
+func newMemoryRollupRepo() *memoryRollupRepo {
+	return &memoryRollupRepo{
+		totals:  make(map[uuid.UUID]map[time.Time]float64),
+		rollups: make(map[uuid.UUID]map[time.Time]float64),
+		failFor: make(map[uuid.UUID]bool),
+	}
+}
+
+// record adds the expense total of a day and, when rollup is not nil, its rollup
+func (r *memoryRollupRepo) record(workspaceID uuid.UUID, date time.Time, amount float64, rollup *float64) {
+	if _, ok := r.totals[workspaceID]; !ok {
+		r.totals[workspaceID] = make(map[time.Time]float64)
+		r.order = append(r.order, workspaceID)
+	}
+	r.totals[workspaceID][date] = amount
+	if rollup != nil {
+		if _, ok := r.rollups[workspaceID]; !ok {
+			r.rollups[workspaceID] = make(map[time.Time]float64)
+		}
+		r.rollups[workspaceID][date] = *rollup
+	}
+}
+
+func (r *memoryRollupRepo) RefreshDays(ctx context.Context, workspaceID uuid.UUID, dates []time.Time) error {
+	if r.failFor[workspaceID] {
+		return errors.New("refresh failed")
+	}
+	if _, ok := r.rollups[workspaceID]; !ok {
+		r.rollups[workspaceID] = make(map[time.Time]float64)
+	}
+	for _, date := range dates {
+		r.rollups[workspaceID][date] = r.totals[workspaceID][date]
+	}
+	r.refreshedDays += len(dates)
+	return nil
+}
+
+func (r *memoryRollupRepo) RebuildWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
+	if r.failFor[workspaceID] {
+		return errors.New("rebuild failed")
+	}
+	r.rollups[workspaceID] = make(map[time.Time]float64)
+	for date, amount := range r.totals[workspaceID] {
+		r.rollups[workspaceID][date] = amount
+	}
+	r.rebuilt = append(r.rebuilt, workspaceID)
+	return nil
+}
+
+func (r *memoryRollupRepo) FindWorkspacesWithoutRollups(ctx context.Context) ([]uuid.UUID, error) {
+	var workspaceIDs []uuid.UUID
+	for _, workspaceID := range r.order {
+		if len(r.rollups[workspaceID]) == 0 {
+			workspaceIDs = append(workspaceIDs, workspaceID)
+		}
+	}
+	return workspaceIDs, nil
+}
+
+func (r *memoryRollupRepo) FindDriftedDays(ctx context.Context, since *time.Time) ([]*entities.RollupDay, error) {
+	var days []*entities.RollupDay
+	for _, workspaceID := range r.order {
+		for date, amount := range r.totals[workspaceID] {
+			if since != nil && date.Before(*since) {
+				continue
+			}
+			if rollup, ok := r.rollups[workspaceID][date]; !ok || rollup != amount {
+				days = append(days, &entities.RollupDay{WorkspaceID: workspaceID, TransactionDate: date})
+			}
+		}
+	}
+	return days, nil
+}
+
+func TestCheckRollupConsistency(t *testing.T) {
+	ctx := context.Background()
+	first, second := uuid.New(), uuid.New()
+	june1 := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	june2 := time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC)
+	june3 := time.Date(2025, time.June, 3, 0, 0, 0, 0, time.UTC)
+	rollup := func(amount float64) *float64 { return &amount }
+
+	newRepo := func() *memoryRollupRepo {
+		repo := newMemoryRollupRepo()
+		repo.record(first, june1, 100000, rollup(100000))
+		repo.record(first, june2, 250000, rollup(200000)) // a refresh failed after an edit
+		repo.record(first, june3, 50000, nil)             // the refresh of a new day never ran
+		repo.record(second, june2, 75000, rollup(0))
+		return repo
+	}
+
+	t.Run("given drifted days, when checking the whole history, then every drifted day is rebuilt", func(t *testing.T) {
+		repo := newRepo()
+
+		repaired, err := NewTransactionRollupService(repo).CheckConsistency(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, repaired)
+
+		drifted, _ := repo.FindDriftedDays(ctx, nil)
+		assert.Empty(t, drifted)
+		assert.Equal(t, 250000.0, repo.rollups[first][june2])
+		assert.Equal(t, 50000.0, repo.rollups[first][june3])
+		assert.Equal(t, 75000.0, repo.rollups[second][june2])
+	})
+
+	t.Run("given drifted days, when checking since a date, then only the days from that date are rebuilt", func(t *testing.T) {
+		repo := newRepo()
+
+		repaired, err := NewTransactionRollupService(repo).CheckConsistency(ctx, &june3)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, repaired)
+		assert.Equal(t, 200000.0, repo.rollups[first][june2])
+		assert.Equal(t, 50000.0, repo.rollups[first][june3])
+	})
+
+	t.Run("given consistent rollups, when checking, then nothing is repaired", func(t *testing.T) {
+		repo := newMemoryRollupRepo()
+		repo.record(first, june1, 100000, rollup(100000))
+
+		repaired, err := NewTransactionRollupService(repo).CheckConsistency(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, repaired)
+	})
+
+	t.Run("given a workspace failing to refresh, when checking, then the error and the days repaired before it are returned", func(t *testing.T) {
+		repo := newRepo()
+		repo.failFor[second] = true
+
+		repaired, err := NewTransactionRollupService(repo).CheckConsistency(ctx, nil)
+		assert.EqualError(t, err, "refresh failed")
+		assert.Equal(t, repo.refreshedDays, repaired)
+		assert.Equal(t, 0.0, repo.rollups[second][june2])
+	})
+}
+
+func TestRebuildMissingRollups(t *testing.T) {
+	ctx := context.Background()
+	june1 := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	rolledUp := 100000.0
+
+	t.Run("given workspaces with and without rollups, when rebuilding, then only the ones without are rebuilt", func(t *testing.T) {
+		repo := newMemoryRollupRepo()
+		withRollups, first, second := uuid.New(), uuid.New(), uuid.New()
+		repo.record(withRollups, june1, 100000, &rolledUp)
+		repo.record(first, june1, 50000, nil)
+		repo.record(second, june1, 75000, nil)
+
+		rebuilt, err := NewTransactionRollupService(repo).RebuildMissing(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, rebuilt)
+		assert.Equal(t, []uuid.UUID{first, second}, repo.rebuilt)
+		assert.Equal(t, 75000.0, repo.rollups[second][june1])
+	})
+
+	t.Run("given a workspace failing to rebuild, when rebuilding, then the workspaces rebuilt before it are reported with the error", func(t *testing.T) {
+		repo := newMemoryRollupRepo()
+		first, failing, last := uuid.New(), uuid.New(), uuid.New()
+		repo.record(first, june1, 50000, nil)
+		repo.record(failing, june1, 75000, nil)
+		repo.record(last, june1, 25000, nil)
+		repo.failFor[failing] = true
+
+		rebuilt, err := NewTransactionRollupService(repo).RebuildMissing(ctx)
+		assert.EqualError(t, err, "rebuild failed")
+		assert.Equal(t, 1, rebuilt)
+		assert.Equal(t, []uuid.UUID{first}, repo.rebuilt)
+		assert.Empty(t, repo.rollups[last])
+	})
+}
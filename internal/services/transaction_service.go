@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
@@ -24,21 +25,33 @@ type (
 
 	transactionService struct {
 		transactionRepo repositories.TransactionRepository
-		workspaceRepo   repositories.WorkspaceRepository
+		authorizer      WorkspaceAuthorizer
 		accountRepo     repositories.AccountRepository
+		rollupRepo      repositories.TransactionRollupRepository
+		merchantService MerchantService
+		events          DomainEventRecorder
+		approvals       TransactionApprovalService
 	}
 )
 
 // NewTransactionService creates a new transaction service
 func NewTransactionService(
 	transactionRepo repositories.TransactionRepository,
-	workspaceRepo repositories.WorkspaceRepository,
+	authorizer WorkspaceAuthorizer,
 	accountRepo repositories.AccountRepository,
+	rollupRepo repositories.TransactionRollupRepository,
+	merchantService MerchantService,
+	events DomainEventRecorder,
+	approvals TransactionApprovalService,
 ) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
-		workspaceRepo:   workspaceRepo,
+		authorizer:      authorizer,
 		accountRepo:     accountRepo,
+		rollupRepo:      rollupRepo,
+		merchantService: merchantService,
+		events:          events,
+		approvals:       approvals,
 	}
 }
 
@@ -54,23 +67,35 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.
 	if input.TransactionType == 0 {
 		return nil, errors.New("transaction type is required")
 	}
-	// if input.PaymentMethod == 0 {
-	// 	return nil, errors.New("payment method is required")
-	// }
+	if !validPaymentMethod(input.PaymentMethod) {
+		return nil, errors.New("invalid payment method")
+	}
 	if input.TransactionDate.IsZero() {
 		return nil, errors.New("transaction date is required")
 	}
 
-	// Verify workspace ownership
-	workspace, err := s.workspaceRepo.FindByID(ctx, input.WorkspaceID)
+	// Viewers cannot record transactions
+	workspace, err := s.authorizer.Authorize(ctx, input.WorkspaceID, userID, entities.WorkspacePermissionCreateTransaction)
 	if err != nil {
 		return nil, err
 	}
-	if workspace == nil {
-		return nil, errorsutil.New(404, "workspace not found")
+	settings := workspace.Settings
+
+	// The workspace default account applies to its owner only, other members pick their own account
+	if input.AccountID == uuid.Nil && settings.DefaultAccountID != nil {
+		account, err := s.accountRepo.FindByID(ctx, *settings.DefaultAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if account != nil && account.UserID == userID {
+			input.AccountID = account.AccountID
+		}
 	}
-	if workspace.CreatedBy != userID {
-		return nil, errorsutil.New(403, "access denied to workspace")
+	if input.AccountID == uuid.Nil {
+		return nil, errors.New("account is required")
+	}
+	if input.PaymentMethod == 0 {
+		input.PaymentMethod = settings.DefaultPaymentMethod
 	}
 
 	// Verify account ownership if account is specified
@@ -87,16 +112,20 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.
 		}
 	}
 
+	if err := s.validateTransferAccount(ctx, userID, &input.AccountID, input.TransferAccountID); err != nil {
+		return nil, err
+	}
+
 	// Create new transaction
 	transaction := &entities.Transaction{
-		TransactionID:   uuid.New(),
-		WorkspaceID:     &input.WorkspaceID,
-		AccountID:       &input.AccountID,
-		CategoryID:      input.CategoryID,
-		Description:     input.Description,
-		Amount:          input.Amount,
-		TransactionType: input.TransactionType,
-		// PaymentMethod:      input.PaymentMethod,
+		TransactionID:      uuid.New(),
+		WorkspaceID:        &input.WorkspaceID,
+		AccountID:          &input.AccountID,
+		CategoryID:         input.CategoryID,
+		Description:        input.Description,
+		Amount:             input.Amount,
+		TransactionType:    input.TransactionType,
+		PaymentMethod:      input.PaymentMethod,
 		TransactionDate:    input.TransactionDate,
 		MerchantName:       input.MerchantName,
 		Location:           input.Location,
@@ -104,8 +133,22 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.
 		IsRecurring:        input.IsRecurring != nil && *input.IsRecurring,
 		RecurrenceInterval: input.RecurrenceInterval,
 		RecurrenceEndDate:  input.RecurrenceEndDate,
+		TransferAccountID:  input.TransferAccountID,
 		CreatedBy:          &userID,
 	}
+	applyAICategory(transaction, input.AIConfidenceScore, settings.AutoCategorizeConfidence)
+
+	// Expenses that need approval do not count until approved, drafts wait for their author to submit them
+	if requiresApproval(workspace, userID, transaction.TransactionType, transaction.Amount) {
+		transaction.ApprovalStatus = entities.ApprovalStatusSubmitted
+		if input.SaveAsDraft {
+			transaction.ApprovalStatus = entities.ApprovalStatusDraft
+		}
+	}
+
+	if err := s.applyMerchant(ctx, userID, transaction); err != nil {
+		return nil, err
+	}
 
 	// Create the transaction - the repository will populate the struct with the actual data from DB
 	createdTransaction, err := s.transactionRepo.Create(ctx, transaction)
@@ -113,6 +156,12 @@ func (s *transactionService) CreateTransaction(ctx context.Context, userID uuid.
 		return nil, err
 	}
 
+	s.refreshRollups(ctx, createdTransaction.WorkspaceID, createdTransaction.TransactionDate)
+	s.recordTransactionEvent(ctx, userID, entities.ActivityActionCreated, &createdTransaction, nil, &createdTransaction)
+	if createdTransaction.ApprovalStatus == entities.ApprovalStatusSubmitted {
+		s.approvals.NotifyApprovers(ctx, workspace, &createdTransaction)
+	}
+
 	// Return the transaction with data populated from the database
 	return &createdTransaction, nil
 }
@@ -128,15 +177,13 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID uuid.
 		return nil, errorsutil.New(404, "transaction not found")
 	}
 
-	// Verify workspace ownership
+	// Members may only change the transactions they recorded
+	var workspace *entities.Workspace
 	if existingTransaction.WorkspaceID != nil {
-		workspace, err := s.workspaceRepo.FindByID(ctx, *existingTransaction.WorkspaceID)
+		workspace, err = s.authorizer.AuthorizeOwnData(ctx, *existingTransaction.WorkspaceID, userID, existingTransaction.CreatedBy)
 		if err != nil {
 			return nil, err
 		}
-		if workspace == nil || workspace.CreatedBy != userID {
-			return nil, errorsutil.New(403, "access denied")
-		}
 	}
 
 	// Validate required fields
@@ -149,15 +196,16 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID uuid.
 	if input.TransactionType == 0 {
 		return nil, errors.New("transaction type is required")
 	}
-	// if input.PaymentMethod == 0 {
-	// 	return nil, errors.New("payment method is required")
-	// }
+	if !validPaymentMethod(input.PaymentMethod) {
+		return nil, errors.New("invalid payment method")
+	}
 	if input.TransactionDate.IsZero() {
 		return nil, errors.New("transaction date is required")
 	}
 
-	// Verify account ownership if account is being changed
-	if input.AccountID != nil && *input.AccountID != uuid.Nil {
+	// Verify account ownership if account is being changed, an admin editing a member's transaction keeps the member's account
+	accountChanged := existingTransaction.AccountID == nil || input.AccountID == nil || *existingTransaction.AccountID != *input.AccountID
+	if accountChanged && input.AccountID != nil && *input.AccountID != uuid.Nil {
 		account, err := s.accountRepo.FindByID(ctx, *input.AccountID)
 		if err != nil {
 			return nil, err
@@ -170,13 +218,22 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID uuid.
 		}
 	}
 
+	if err := s.validateTransferAccount(ctx, userID, input.AccountID, input.TransferAccountID); err != nil {
+		return nil, err
+	}
+
+	previousTransaction := *existingTransaction
+	previousDate := existingTransaction.TransactionDate
+
 	// Update fields
 	existingTransaction.AccountID = input.AccountID
 	existingTransaction.CategoryID = input.CategoryID
 	existingTransaction.Description = input.Description
 	existingTransaction.Amount = input.Amount
 	existingTransaction.TransactionType = input.TransactionType
-	// existingTransaction.PaymentMethod = input.PaymentMethod
+	if input.PaymentMethod != 0 {
+		existingTransaction.PaymentMethod = input.PaymentMethod
+	}
 	existingTransaction.TransactionDate = input.TransactionDate
 	existingTransaction.MerchantName = input.MerchantName
 	existingTransaction.Location = input.Location
@@ -188,6 +245,21 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID uuid.
 		existingTransaction.RecurrenceInterval = *input.RecurrenceInterval
 	}
 	existingTransaction.RecurrenceEndDate = input.RecurrenceEndDate
+	existingTransaction.TransferAccountID = input.TransferAccountID
+
+	// The approval rules follow the author of the expense, not the member editing it
+	if workspace != nil {
+		author := userID
+		if existingTransaction.CreatedBy != nil {
+			author = *existingTransaction.CreatedBy
+		}
+		required := requiresApproval(workspace, author, existingTransaction.TransactionType, existingTransaction.Amount)
+		existingTransaction.ApprovalStatus = approvalStatusAfterUpdate(previousTransaction.ApprovalStatus, required, previousTransaction.Amount != existingTransaction.Amount)
+	}
+
+	if err := s.applyMerchant(ctx, userID, existingTransaction); err != nil {
+		return nil, err
+	}
 
 	// Update the transaction - the repository will populate the struct with the actual data from DB
 	updatedTransaction, err := s.transactionRepo.Update(ctx, existingTransaction)
@@ -195,6 +267,12 @@ func (s *transactionService) UpdateTransaction(ctx context.Context, userID uuid.
 		return nil, err
 	}
 
+	s.refreshRollups(ctx, updatedTransaction.WorkspaceID, previousDate, updatedTransaction.TransactionDate)
+	s.recordTransactionEvent(ctx, userID, entities.ActivityActionUpdated, &updatedTransaction, &previousTransaction, &updatedTransaction)
+	if updatedTransaction.ApprovalStatus == entities.ApprovalStatusSubmitted && previousTransaction.ApprovalStatus != entities.ApprovalStatusSubmitted {
+		s.approvals.NotifyApprovers(ctx, workspace, &updatedTransaction)
+	}
+
 	// Return the transaction with data populated from the database
 	return &updatedTransaction, nil
 }
@@ -210,33 +288,27 @@ func (s *transactionService) DeleteTransaction(ctx context.Context, userID uuid.
 		return errorsutil.New(404, "transaction not found")
 	}
 
-	// Verify workspace ownership
+	// Members may only change the transactions they recorded
 	if existingTransaction.WorkspaceID != nil {
-		workspace, err := s.workspaceRepo.FindByID(ctx, *existingTransaction.WorkspaceID)
-		if err != nil {
+		if _, err := s.authorizer.AuthorizeOwnData(ctx, *existingTransaction.WorkspaceID, userID, existingTransaction.CreatedBy); err != nil {
 			return err
 		}
-		if workspace == nil || workspace.CreatedBy != userID {
-			return errorsutil.New(403, "access denied")
-		}
 	}
 
-	return s.transactionRepo.Delete(ctx, transactionID)
+	if err := s.transactionRepo.Delete(ctx, transactionID); err != nil {
+		return err
+	}
+
+	s.refreshRollups(ctx, existingTransaction.WorkspaceID, existingTransaction.TransactionDate)
+	s.recordTransactionEvent(ctx, userID, entities.ActivityActionDeleted, existingTransaction, existingTransaction, nil)
+	return nil
 }
 
 // GetTransactionsByWorkspace returns transactions for a workspace with pagination and filtering
 func (s *transactionService) GetTransactionsByWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, params *entities.TransactionListParams, limit, offset int) ([]*entities.Transaction, int64, error) {
-	// Verify workspace ownership
-	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
-	if err != nil {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
 		return nil, 0, err
 	}
-	if workspace == nil {
-		return nil, 0, errorsutil.New(404, "workspace not found")
-	}
-	if workspace.CreatedBy != userID {
-		return nil, 0, errorsutil.New(403, "access denied")
-	}
 
 	// Get transactions
 	transactions, err := s.transactionRepo.FindByWorkspace(ctx, workspaceID, params, limit, offset)
@@ -287,16 +359,127 @@ func (s *transactionService) GetTransactionByID(ctx context.Context, userID uuid
 		return nil, errorsutil.New(404, "transaction not found")
 	}
 
-	// Verify workspace ownership
 	if transaction.WorkspaceID != nil {
-		workspace, err := s.workspaceRepo.FindByID(ctx, *transaction.WorkspaceID)
-		if err != nil {
+		if _, err := s.authorizer.Authorize(ctx, *transaction.WorkspaceID, userID, entities.WorkspacePermissionView); err != nil {
 			return nil, err
 		}
-		if workspace == nil || workspace.CreatedBy != userID {
-			return nil, errorsutil.New(403, "access denied")
-		}
 	}
 
 	return transaction, nil
 }
+
+// validateTransferAccount checks the counterpart account of a transfer between own accounts.
+// An expense moves money from the account to the transfer account, an income the other way around.
+func (s *transactionService) validateTransferAccount(ctx context.Context, userID uuid.UUID, accountID *uuid.UUID, transferAccountID *uuid.UUID) error {
+	if transferAccountID == nil {
+		return nil
+	}
+	if accountID == nil || *accountID == uuid.Nil {
+		return errors.New("account is required for a transfer")
+	}
+	if *accountID == *transferAccountID {
+		return errors.New("transfer account must be different from the account")
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, *transferAccountID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errorsutil.New(404, "transfer account not found")
+	}
+	if account.UserID != userID {
+		return errorsutil.New(403, "access denied to account")
+	}
+	return nil
+}
+
+// applyMerchant links a transaction to the merchant its merchant name matches and replaces the name with the
+// canonical merchant name. Transactions without a category get the user's category for the merchant's default category.
+// Every way of creating a transaction, including imports and AI extraction, goes through here.
+func (s *transactionService) applyMerchant(ctx context.Context, userID uuid.UUID, transaction *entities.Transaction) error {
+	transaction.MerchantID = nil
+	if transaction.MerchantName == nil {
+		return nil
+	}
+
+	match, err := s.merchantService.NormalizeMerchant(ctx, userID, *transaction.MerchantName)
+	if err != nil {
+		return err
+	}
+	if match == nil {
+		return nil
+	}
+
+	transaction.MerchantID = &match.Merchant.MerchantID
+	transaction.MerchantName = &match.Merchant.Name
+	if transaction.CategoryID == nil {
+		transaction.CategoryID = match.UserCategoryID
+	}
+
+	return nil
+}
+
+// validPaymentMethod reports whether a payment method is known, 0 leaves it unset
+func validPaymentMethod(paymentMethod int) bool {
+	switch paymentMethod {
+	case 0, entities.PaymentMethodDebitQRIS, entities.PaymentMethodCredit, entities.PaymentMethodCash, entities.PaymentMethodTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+// applyAICategory keeps a category suggested by the AI only when its confidence reaches the workspace threshold,
+// otherwise the transaction is left uncategorized for the user to review
+func applyAICategory(transaction *entities.Transaction, confidenceScore *float64, threshold float64) {
+	if confidenceScore == nil {
+		return
+	}
+
+	transaction.AIConfidenceScore = confidenceScore
+	if transaction.CategoryID == nil {
+		return
+	}
+	if *confidenceScore < threshold {
+		transaction.CategoryID = nil
+		return
+	}
+	transaction.AICategorized = true
+}
+
+// refreshRollups recomputes the daily rollups of the days a transaction write touched. A failed refresh
+// does not fail the write, the rollup consistency check repairs the day later.
+func (s *transactionService) refreshRollups(ctx context.Context, workspaceID *uuid.UUID, dates ...time.Time) {
+	if workspaceID == nil {
+		return
+	}
+	_ = s.rollupRepo.RefreshDays(ctx, *workspaceID, dates)
+}
+
+// recordTransactionEvent records a transaction write in the audit log and the activity feed of its workspace
+func (s *transactionService) recordTransactionEvent(ctx context.Context, userID uuid.UUID, action string, transaction *entities.Transaction, oldValues, newValues *entities.Transaction) {
+	if transaction.WorkspaceID == nil {
+		return
+	}
+
+	event := &entities.DomainEvent{
+		WorkspaceID:  *transaction.WorkspaceID,
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: entities.ActivityResourceTransaction,
+		ResourceID:   &transaction.TransactionID,
+		ResourceName: transaction.Description,
+		Amount:       &transaction.Amount,
+		Detail:       transaction.TransactionType,
+		AIGenerated:  transaction.AICategorized || transaction.AIConfidenceScore != nil,
+	}
+	// A nil *Transaction in an interface is not nil, only set the values that exist
+	if oldValues != nil {
+		event.OldValues = oldValues
+	}
+	if newValues != nil {
+		event.NewValues = newValues
+	}
+	s.events.Record(ctx, event)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestApplyAICategory(t *testing.T) {
+	categoryID := uuid.New()
+
+	t.Run("given a suggestion at the threshold, when it is applied, then the category is kept as AI categorized", func(t *testing.T) {
+		score := 0.8
+		transaction := &entities.Transaction{CategoryID: &categoryID}
+
+		applyAICategory(transaction, &score, 0.8)
+
+		assert.Equal(t, &categoryID, transaction.CategoryID)
+		assert.True(t, transaction.AICategorized)
+		assert.Equal(t, &score, transaction.AIConfidenceScore)
+	})
+
+	t.Run("given a suggestion below the threshold, when it is applied, then the transaction is left uncategorized", func(t *testing.T) {
+		score := 0.6
+		transaction := &entities.Transaction{CategoryID: &categoryID}
+
+		applyAICategory(transaction, &score, 0.9)
+
+		assert.Nil(t, transaction.CategoryID)
+		assert.False(t, transaction.AICategorized)
+	})
+
+	t.Run("given a category picked by the user, when no score is given, then the category is kept", func(t *testing.T) {
+		transaction := &entities.Transaction{CategoryID: &categoryID}
+
+		applyAICategory(transaction, nil, 0.9)
+
+		assert.Equal(t, &categoryID, transaction.CategoryID)
+		assert.False(t, transaction.AICategorized)
+	})
+}
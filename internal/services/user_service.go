@@ -2,7 +2,13 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -11,6 +17,7 @@ import (
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
 	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
 	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
 )
 
@@ -29,26 +36,58 @@ type (
 
 		// Authentication methods
 		Login(ctx context.Context, input *entities.LoginRequest) (*entities.LoginResponse, error)
+		AdminLogin(ctx context.Context, input *entities.LoginRequest) (*entities.LoginResponse, error)
 		ForgotPassword(ctx context.Context, input *entities.ForgotPasswordRequest) error
+		VerifyPasswordResetCode(ctx context.Context, input *entities.VerifyPasswordResetCodeRequest) (*entities.PasswordResetTokenResponse, error)
 		ResetPassword(ctx context.Context, input *entities.ResetPasswordRequest) error
-		ChangePassword(ctx context.Context, input *entities.ChangePasswordRequest) error
+		ChangePassword(ctx context.Context, userID uuid.UUID, input *entities.ChangePasswordRequest) (*entities.LoginResponse, error)
 		VerifyPhone(ctx context.Context, input *entities.VerifyPhoneRequest) error
 		ResendVerificationCode(ctx context.Context, input *entities.ResendVerificationCodeRequest) error
 		VerifyEmail(ctx context.Context, input *entities.VerifyEmailRequest) error
 		ResendVerificationEmail(ctx context.Context, input *entities.ResendVerificationEmailRequest) error
+		RequestPhoneChange(ctx context.Context, userID uuid.UUID, input *entities.ChangePhoneRequest) error
+		ConfirmPhoneChange(ctx context.Context, userID uuid.UUID, input *entities.ConfirmPhoneChangeRequest) (*entities.User, error)
 	}
 
 	userService struct {
-		userRepo       repositories.UserRepository
-		authMiddleware *middleware.AuthMiddleware
+		userRepo                repositories.UserRepository
+		authMiddleware          *middleware.AuthMiddleware
+		invitationService       WorkspaceInvitationService
+		sessionService          SessionService
+		verificationCodeService VerificationCodeService
+		emailSender             EmailSender
+		appURL                  string
 	}
 )
 
-// NewUserService creates a new user service
-func NewUserService(userRepo repositories.UserRepository, authMiddleware *middleware.AuthMiddleware) UserService {
+const (
+	// maxPasswordAttempts wrong PINs in a row lock PIN checks for passwordLockout
+	maxPasswordAttempts = 5
+	passwordLockout     = 15 * time.Minute
+	passwordResetTTL    = 10 * time.Minute
+	// emailVerificationTTL is how long an email verification link works
+	emailVerificationTTL = 24 * time.Hour
+)
+
+// NewUserService creates a new user service.
+// Email verification links point to appURL, the bare token is sent when it is empty.
+func NewUserService(
+	userRepo repositories.UserRepository,
+	authMiddleware *middleware.AuthMiddleware,
+	invitationService WorkspaceInvitationService,
+	sessionService SessionService,
+	verificationCodeService VerificationCodeService,
+	emailSender EmailSender,
+	appURL string,
+) UserService {
 	return &userService{
-		userRepo:       userRepo,
-		authMiddleware: authMiddleware,
+		userRepo:                userRepo,
+		authMiddleware:          authMiddleware,
+		invitationService:       invitationService,
+		sessionService:          sessionService,
+		verificationCodeService: verificationCodeService,
+		emailSender:             emailSender,
+		appURL:                  strings.TrimRight(appURL, "/"),
 	}
 }
 
@@ -97,6 +136,15 @@ func (s *userService) CreateUser(ctx context.Context, input *entities.CreateUser
 		return nil, errorsutil.New(409, "user with this phone number already exists")
 	}
 
+	// Validate the workspace invitation the user registers with, it is joined after phone verification
+	var invitation *entities.WorkspaceInvitation
+	if input.InvitationToken != "" {
+		invitation, err = s.invitationService.ValidateRegistrationInvitation(ctx, input.InvitationToken, input.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Hash the password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -128,17 +176,30 @@ func (s *userService) CreateUser(ctx context.Context, input *entities.CreateUser
 		return nil, err
 	}
 
-	// Generate JWT token
-	token, err := s.authMiddleware.GenerateToken(&createdUser)
+	if invitation != nil {
+		if err := s.invitationService.ClaimInvitation(ctx, invitation.InvitationID, createdUser.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The phone number is verified with a code, the user can ask for another one if this one is not delivered
+	_, _ = s.verificationCodeService.CreateVerificationCode(ctx, &entities.CreateVerificationCodeRequest{
+		PhoneNumber: createdUser.PhoneNumber,
+		CodeType:    entities.VerificationCodeTypePhone,
+	})
+
+	// Registering logs the user in on the device
+	tokens, err := s.sessionService.StartSession(ctx, &createdUser)
 	if err != nil {
 		return nil, err
 	}
 
 	loginResponse := &entities.LoginResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   3600, // 1 hour
-		User:        &createdUser,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         &createdUser,
 	}
 
 	return loginResponse, nil
@@ -165,23 +226,16 @@ func (s *userService) UpdateUser(ctx context.Context, userID uuid.UUID, input *e
 		}
 	}
 
-	// Check for phone number uniqueness
+	// The phone number signs the user in, a new one is only taken once a code sent to it is verified
 	if input.PhoneNumber != "" && input.PhoneNumber != existingUser.PhoneNumber {
-		userWithPhone, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
-		if err != nil {
-			return nil, err
-		}
-		if userWithPhone != nil && userWithPhone.UserID != userID {
-			return nil, errorsutil.New(409, "phone number already in use")
-		}
+		return nil, errorsutil.New(400, "phone number changes must be verified")
 	}
 
-	// Update fields
-	if input.Email != "" {
+	// A new email is unverified until its link is opened
+	emailChanged := input.Email != "" && input.Email != existingUser.Email
+	if emailChanged {
 		existingUser.Email = input.Email
-	}
-	if input.PhoneNumber != "" {
-		existingUser.PhoneNumber = input.PhoneNumber
+		existingUser.EmailVerifiedAt = nil
 	}
 	if input.FirstName != "" {
 		existingUser.FirstName = input.FirstName
@@ -208,6 +262,10 @@ func (s *userService) UpdateUser(ctx context.Context, userID uuid.UUID, input *e
 		return nil, err
 	}
 
+	if emailChanged {
+		_ = s.sendEmailVerification(ctx, &updatedUser)
+	}
+
 	// Return the user with data populated from the database
 	return &updatedUser, nil
 }
@@ -291,31 +349,30 @@ func (s *userService) ResetUserPassword(ctx context.Context, input *entities.Res
 	}
 
 	// Verify old password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.OldPassword))
-	if err != nil {
-		return errorsutil.New(400, "old password is incorrect")
-	}
-
-	// Hash the new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
-	if err != nil {
+	if err := s.checkPassword(ctx, user, input.OldPassword, "old password is incorrect"); err != nil {
 		return err
 	}
 
-	// Update the user's password
-	user.PasswordHash = string(hashedPassword)
-
-	// Save the updated user
-	_, err = s.userRepo.Update(ctx, user)
-	if err != nil {
+	if err := s.setPassword(ctx, user.UserID, input.NewPassword); err != nil {
 		return err
 	}
 
-	return nil
+	_, err = s.sessionService.RevokeAll(ctx, user.UserID, entities.SessionRevokedPassword)
+	return err
 }
 
 // Login authenticates a user and returns a login response
 func (s *userService) Login(ctx context.Context, input *entities.LoginRequest) (*entities.LoginResponse, error) {
+	return s.login(ctx, input, false)
+}
+
+// AdminLogin authenticates a user holding a system role for the administration API
+func (s *userService) AdminLogin(ctx context.Context, input *entities.LoginRequest) (*entities.LoginResponse, error) {
+	return s.login(ctx, input, true)
+}
+
+// login checks the password before the role, so a failed admin login does not tell who is an admin
+func (s *userService) login(ctx context.Context, input *entities.LoginRequest, adminOnly bool) (*entities.LoginResponse, error) {
 	var user *entities.User
 	var err error
 
@@ -334,9 +391,8 @@ func (s *userService) Login(ctx context.Context, input *entities.LoginRequest) (
 	}
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password))
-	if err != nil {
-		return nil, errorsutil.New(400, "password is incorrect")
+	if err := s.checkPassword(ctx, user, input.Password, "password is incorrect"); err != nil {
+		return nil, err
 	}
 
 	// Check if user is active
@@ -344,52 +400,200 @@ func (s *userService) Login(ctx context.Context, input *entities.LoginRequest) (
 		return nil, errorsutil.New(403, "user account is inactive")
 	}
 
-	// Generate JWT token
-	token, err := s.authMiddleware.GenerateToken(user)
+	if adminOnly && user.SystemRole == entities.SystemRoleNone {
+		return nil, errorsutil.New(403, "admin access required")
+	}
+
+	// Every login opens a session of its own
+	tokens, err := s.sessionService.StartSession(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
 	loginResponse := &entities.LoginResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   3600, // 1 hour
-		User:        user,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
 	}
 
 	return loginResponse, nil
 }
 
-// ForgotPassword initiates password reset process
+// ForgotPassword sends a password reset code to the user's phone over WhatsApp or SMS. It succeeds for every
+// phone number, so the response does not tell whether the number has an account.
 func (s *userService) ForgotPassword(ctx context.Context, input *entities.ForgotPasswordRequest) error {
-	user, err := s.userRepo.FindByEmail(ctx, input.Email)
+	user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
 	if err != nil {
 		return err
 	}
-	if user == nil {
-		return errorsutil.New(404, "user not found")
+	if user == nil || user.Status != entities.UserStatusActive {
+		return nil
 	}
 
-	// TODO: Implement password reset token generation and email sending
-	// For now, just return success
+	_, err = s.verificationCodeService.CreateVerificationCode(ctx, &entities.CreateVerificationCodeRequest{
+		PhoneNumber: user.PhoneNumber,
+		CodeType:    entities.VerificationCodeTypePasswordReset,
+		Channel:     input.Channel,
+	})
+	if err != nil {
+		// Resend limits and delivery failures only happen for existing accounts, reporting them would reveal the account
+		utils.Log().Warn().Err(err).Str("user_id", user.UserID.String()).Msg("failed to send password reset code")
+	}
 	return nil
 }
 
-// ResetPassword resets password using a token
+// VerifyPasswordResetCode uses up a password reset code and returns a short-lived token to set a new password.
+// Unknown phone numbers and every rejected code get the same error.
+func (s *userService) VerifyPasswordResetCode(ctx context.Context, input *entities.VerifyPasswordResetCodeRequest) (*entities.PasswordResetTokenResponse, error) {
+	user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(400, "invalid verification code")
+	}
+
+	if err := s.verificationCodeService.ConsumeCode(ctx, user.PhoneNumber, entities.VerificationCodeTypePasswordReset, input.Code); err != nil {
+		var codeErr *errorsutil.Error
+		if errors.As(err, &codeErr) && codeErr.Status() < 500 {
+			return nil, errorsutil.New(400, "invalid verification code")
+		}
+		return nil, err
+	}
+
+	resetToken, err := s.authMiddleware.GeneratePasswordResetToken(user.UserID, passwordFingerprint(user.PasswordHash), time.Now().Add(passwordResetTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PasswordResetTokenResponse{
+		ResetToken: resetToken,
+		ExpiresIn:  int(passwordResetTTL.Seconds()),
+	}, nil
+}
+
+// ResetPassword sets a new password with a reset token and logs the user out everywhere
 func (s *userService) ResetPassword(ctx context.Context, input *entities.ResetPasswordRequest) error {
-	// TODO: Implement token validation and password reset
-	// For now, just return success
+	claims, err := s.authMiddleware.ValidatePasswordResetToken(input.Token)
+	if err != nil {
+		return errorsutil.New(400, "invalid or expired reset token")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errorsutil.New(404, "user not found")
+	}
+
+	// The token only works against the password it was issued for
+	if claims.PasswordFingerprint != passwordFingerprint(user.PasswordHash) {
+		return errorsutil.New(400, "reset token has already been used")
+	}
+
+	if err := s.setPassword(ctx, user.UserID, input.NewPassword); err != nil {
+		return err
+	}
+
+	_, err = s.sessionService.RevokeAll(ctx, user.UserID, entities.SessionRevokedPassword)
+	return err
+}
+
+// ChangePassword sets a new password after checking the current one. Every session is revoked,
+// the device making the change gets a new one.
+func (s *userService) ChangePassword(ctx context.Context, userID uuid.UUID, input *entities.ChangePasswordRequest) (*entities.LoginResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	if err := s.checkPassword(ctx, user, input.CurrentPassword, "current password is incorrect"); err != nil {
+		return nil, err
+	}
+	if input.NewPassword == input.CurrentPassword {
+		return nil, errors.New("new password must be different from the current one")
+	}
+
+	if err := s.setPassword(ctx, user.UserID, input.NewPassword); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sessionService.RevokeAll(ctx, user.UserID, entities.SessionRevokedPassword); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.StartSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.LoginResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+	}, nil
+}
+
+// checkPassword compares a password with the user's. Repeated failures lock password checks for a while,
+// since a 6-digit PIN is otherwise quick to guess.
+func (s *userService) checkPassword(ctx context.Context, user *entities.User, password string, incorrectMessage string) error {
+	lockedUntil, err := s.userRepo.FindPasswordLockedUntil(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	if lockedUntil != nil {
+		return errorsutil.New(429, "too many incorrect attempts, try again later")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		lockedUntil, err := s.userRepo.RecordFailedPasswordAttempt(ctx, user.UserID, maxPasswordAttempts, passwordLockout)
+		if err != nil {
+			return err
+		}
+		if lockedUntil != nil {
+			return errorsutil.New(429, "too many incorrect attempts, try again later")
+		}
+		return errorsutil.New(400, incorrectMessage)
+	}
+
+	return s.userRepo.ClearFailedPasswordAttempts(ctx, user.UserID)
+}
+
+// setPassword hashes and stores a new password
+func (s *userService) setPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	if len(password) != 6 {
+		return errors.New("new password must be exactly 6 digits")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorsutil.New(404, "user not found")
+		}
+		return err
+	}
 	return nil
 }
 
-// ChangePassword changes user's password
-func (s *userService) ChangePassword(ctx context.Context, input *entities.ChangePasswordRequest) error {
-	// TODO: Get current user from context
-	// For now, return not implemented error
-	return errorsutil.New(501, "change password not implemented")
+// passwordFingerprint identifies a password hash without revealing it
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
 }
 
-// VerifyPhone verifies a phone number with a code
+// VerifyPhone verifies a phone number with the code sent to it
 func (s *userService) VerifyPhone(ctx context.Context, input *entities.VerifyPhoneRequest) error {
 	user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
 	if err != nil {
@@ -399,16 +603,13 @@ func (s *userService) VerifyPhone(ctx context.Context, input *entities.VerifyPho
 		return errorsutil.New(404, "user not found")
 	}
 
-	// TODO: Implement phone verification logic
-	// For now, just mark as verified
-	now := time.Now()
-	user.PhoneVerifiedAt = &now
-
-	_, err = s.userRepo.Update(ctx, user)
-	return err
+	return s.verificationCodeService.VerifyCode(ctx, &entities.VerifyVerificationCodeRequest{
+		PhoneNumber: user.PhoneNumber,
+		Code:        input.Code,
+	})
 }
 
-// ResendVerificationCode resends verification code for phone
+// ResendVerificationCode sends a new verification code to an unverified phone number
 func (s *userService) ResendVerificationCode(ctx context.Context, input *entities.ResendVerificationCodeRequest) error {
 	user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
 	if err != nil {
@@ -417,14 +618,37 @@ func (s *userService) ResendVerificationCode(ctx context.Context, input *entitie
 	if user == nil {
 		return errorsutil.New(404, "user not found")
 	}
+	if user.PhoneVerifiedAt != nil {
+		return errorsutil.New(409, "phone number is already verified")
+	}
 
-	// TODO: Implement SMS sending logic
-	// For now, just return success
-	return nil
+	_, err = s.verificationCodeService.CreateVerificationCode(ctx, &entities.CreateVerificationCodeRequest{
+		PhoneNumber: user.PhoneNumber,
+		CodeType:    entities.VerificationCodeTypePhone,
+		Channel:     input.Channel,
+	})
+	return err
 }
 
-// VerifyEmail verifies an email with a token
+// VerifyEmail verifies an email with the token of its verification link
 func (s *userService) VerifyEmail(ctx context.Context, input *entities.VerifyEmailRequest) error {
+	claims, err := s.authMiddleware.ValidateEmailVerificationToken(input.Token)
+	if err != nil || !strings.EqualFold(claims.Email, input.Email) {
+		return errorsutil.New(400, "invalid or expired verification link")
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, claims.UserID, claims.Email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// The user changed their email since the link was sent
+			return errorsutil.New(400, "invalid or expired verification link")
+		}
+		return err
+	}
+	return nil
+}
+
+// ResendVerificationEmail sends a new verification link to an unverified email
+func (s *userService) ResendVerificationEmail(ctx context.Context, input *entities.ResendVerificationEmailRequest) error {
 	user, err := s.userRepo.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return err
@@ -432,27 +656,106 @@ func (s *userService) VerifyEmail(ctx context.Context, input *entities.VerifyEma
 	if user == nil {
 		return errorsutil.New(404, "user not found")
 	}
+	if user.EmailVerifiedAt != nil {
+		return errorsutil.New(409, "email is already verified")
+	}
 
-	// TODO: Implement email verification logic
-	// For now, just mark as verified
-	now := time.Now()
-	user.EmailVerifiedAt = &now
-
-	_, err = s.userRepo.Update(ctx, user)
-	return err
+	return s.sendEmailVerification(ctx, user)
 }
 
-// ResendVerificationEmail resends verification email
-func (s *userService) ResendVerificationEmail(ctx context.Context, input *entities.ResendVerificationEmailRequest) error {
-	user, err := s.userRepo.FindByEmail(ctx, input.Email)
+// RequestPhoneChange sends a code to the phone number a user wants to move to
+func (s *userService) RequestPhoneChange(ctx context.Context, userID uuid.UUID, input *entities.ChangePhoneRequest) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 	if user == nil {
 		return errorsutil.New(404, "user not found")
 	}
+	if input.PhoneNumber == user.PhoneNumber {
+		return errors.New("new phone number is the current one")
+	}
+
+	if err := s.checkPhoneNumberAvailable(ctx, userID, input.PhoneNumber); err != nil {
+		return err
+	}
+
+	_, err = s.verificationCodeService.CreateVerificationCode(ctx, &entities.CreateVerificationCodeRequest{
+		PhoneNumber: input.PhoneNumber,
+		CodeType:    entities.VerificationCodeTypePhoneChange,
+		Channel:     input.Channel,
+	})
+	return err
+}
+
+// ConfirmPhoneChange moves a user to a new phone number with the code sent to it, the number is verified as a result
+func (s *userService) ConfirmPhoneChange(ctx context.Context, userID uuid.UUID, input *entities.ConfirmPhoneChangeRequest) (*entities.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	// Checked before the code is used up, and again below for a number taken meanwhile
+	if err := s.checkPhoneNumberAvailable(ctx, userID, input.PhoneNumber); err != nil {
+		return nil, err
+	}
+
+	if err := s.verificationCodeService.ConsumeCode(ctx, input.PhoneNumber, entities.VerificationCodeTypePhoneChange, input.Code); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPhoneNumberAvailable(ctx, userID, input.PhoneNumber); err != nil {
+		return nil, err
+	}
 
-	// TODO: Implement email sending logic
-	// For now, just return success
+	if err := s.userRepo.UpdateVerifiedPhoneNumber(ctx, userID, input.PhoneNumber); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorsutil.New(404, "user not found")
+		}
+		return nil, err
+	}
+
+	return s.GetUserByID(ctx, userID)
+}
+
+// checkPhoneNumberAvailable refuses a phone number registered to another user
+func (s *userService) checkPhoneNumberAvailable(ctx context.Context, userID uuid.UUID, phoneNumber string) error {
+	userWithPhone, err := s.userRepo.FindByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		return err
+	}
+	if userWithPhone != nil && userWithPhone.UserID != userID {
+		return errorsutil.New(409, "phone number already in use")
+	}
 	return nil
 }
+
+// sendEmailVerification emails a verification link for the user's current email
+func (s *userService) sendEmailVerification(ctx context.Context, user *entities.User) error {
+	if user.Email == "" {
+		return errors.New("email is required")
+	}
+
+	token, err := s.authMiddleware.GenerateEmailVerificationToken(user.UserID, user.Email, time.Now().Add(emailVerificationTTL))
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmail(ctx, user.Email, "Verifikasi email VASST kamu", emailVerificationMessage(user.FirstName, s.emailVerificationLink(user.Email, token)))
+}
+
+// emailVerificationLink is the link that opens the app's email verification page, or the bare token without an app URL
+func (s *userService) emailVerificationLink(email, token string) string {
+	if s.appURL == "" {
+		return token
+	}
+	return s.appURL + "/verify-email?email=" + url.QueryEscape(email) + "&token=" + url.QueryEscape(token)
+}
+
+// emailVerificationMessage is the body of the email verification email
+func emailVerificationMessage(firstName, link string) string {
+	return fmt.Sprintf("Halo %s,\n\nBuka tautan berikut untuk memverifikasi email kamu:\n%s\n\nTautan ini berlaku 24 jam. Abaikan email ini jika kamu tidak mendaftarkan email ini di VASST.", firstName, link)
+}
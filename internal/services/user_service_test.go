@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	"github.com/vasst-id/vasst-expense-api/internal/utils"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
+)
+
+func TestPasswordFingerprint(t *testing.T) {
+	t.Run("given the same password hash, when fingerprinting, then the fingerprint is stable", func(t *testing.T) {
+		assert.Equal(t, passwordFingerprint("$2a$10$hash"), passwordFingerprint("$2a$10$hash"))
+		assert.Len(t, passwordFingerprint("$2a$10$hash"), 16)
+	})
+
+	t.Run("given a changed password hash, when fingerprinting, then the fingerprint differs", func(t *testing.T) {
+		assert.NotEqual(t, passwordFingerprint("$2a$10$before"), passwordFingerprint("$2a$10$after"))
+	})
+
+	t.Run("given a password hash, when fingerprinting, then the hash itself is not revealed", func(t *testing.T) {
+		assert.NotContains(t, passwordFingerprint("$2a$10$hash"), "hash")
+	})
+}
+
+func TestPasswordResetToken(t *testing.T) {
+	auth := middleware.NewAuthMiddleware("secret")
+	userID := uuid.New()
+
+	t.Run("given a reset token, when validating, then the user and password fingerprint are returned", func(t *testing.T) {
+		token, err := auth.GeneratePasswordResetToken(userID, passwordFingerprint("hash"), time.Now().Add(passwordResetTTL))
+		assert.NoError(t, err)
+
+		claims, err := auth.ValidatePasswordResetToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, passwordFingerprint("hash"), claims.PasswordFingerprint)
+	})
+
+	t.Run("given an expired reset token, when validating, then it is refused", func(t *testing.T) {
+		token, _ := auth.GeneratePasswordResetToken(userID, passwordFingerprint("hash"), time.Now().Add(-time.Minute))
+
+		_, err := auth.ValidatePasswordResetToken(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("given an access token, when validating it as a reset token, then it is refused", func(t *testing.T) {
+		accessToken, err := auth.GenerateToken(&entities.User{UserID: userID}, uuid.New())
+		assert.NoError(t, err)
+
+		_, err = auth.ValidatePasswordResetToken(accessToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestEmailVerificationLink(t *testing.T) {
+	t.Run("given an app URL, when building the link, then it opens the verify email page with the email and token", func(t *testing.T) {
+		s := &userService{appURL: "https://app.vasst.id"}
+		assert.Equal(t, "https://app.vasst.id/verify-email?email=budi%2Bkerja%40example.com&token=abc.def", s.emailVerificationLink("budi+kerja@example.com", "abc.def"))
+	})
+
+	t.Run("given no app URL, when building the link, then the bare token is sent", func(t *testing.T) {
+		s := &userService{}
+		assert.Equal(t, "abc.def", s.emailVerificationLink("budi@example.com", "abc.def"))
+	})
+}
+
+func TestEmailVerificationToken(t *testing.T) {
+	auth := middleware.NewAuthMiddleware("secret")
+	userID := uuid.New()
+
+	t.Run("given an email verification token, when validating, then the user and email are returned", func(t *testing.T) {
+		token, err := auth.GenerateEmailVerificationToken(userID, "budi@example.com", time.Now().Add(emailVerificationTTL))
+		assert.NoError(t, err)
+
+		claims, err := auth.ValidateEmailVerificationToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+		assert.Equal(t, "budi@example.com", claims.Email)
+	})
+
+	t.Run("given a password reset token, when validating it as an email verification token, then it is refused", func(t *testing.T) {
+		token, _ := auth.GeneratePasswordResetToken(userID, passwordFingerprint("hash"), time.Now().Add(passwordResetTTL))
+
+		_, err := auth.ValidateEmailVerificationToken(token)
+		assert.Error(t, err)
+	})
+}
+
+func TestAccessTokenPhoneVerified(t *testing.T) {
+	auth := middleware.NewAuthMiddleware("secret")
+
+	t.Run("given users with and without a verified phone, when issuing access tokens, then the claim follows the user", func(t *testing.T) {
+		verifiedAt := time.Now()
+		token, _ := auth.GenerateToken(&entities.User{UserID: uuid.New(), PhoneVerifiedAt: &verifiedAt}, uuid.New())
+		claims, err := auth.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.True(t, claims.PhoneVerified)
+
+		token, _ = auth.GenerateToken(&entities.User{UserID: uuid.New()}, uuid.New())
+		claims, err = auth.ValidateToken(token)
+		assert.NoError(t, err)
+		assert.False(t, claims.PhoneVerified)
+	})
+}
+
+// passwordResetUserRepo finds users by phone number, the other methods are not used by the password reset
+type passwordResetUserRepo struct {
+	repositories.UserRepository
+	users map[string]*entities.User
+}
+
+func (r *passwordResetUserRepo) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entities.User, error) {
+	return r.users[phoneNumber], nil
+}
+
+// passwordResetCodes records the codes sent and fails with the configured errors
+type passwordResetCodes struct {
+	VerificationCodeService
+	sent       []string
+	createErr  error
+	consumeErr error
+}
+
+func (c *passwordResetCodes) CreateVerificationCode(ctx context.Context, input *entities.CreateVerificationCodeRequest) (*entities.VerificationCode, error) {
+	if c.createErr != nil {
+		return nil, c.createErr
+	}
+	c.sent = append(c.sent, input.PhoneNumber)
+	return &entities.VerificationCode{}, nil
+}
+
+func (c *passwordResetCodes) ConsumeCode(ctx context.Context, phoneNumber, codeType, code string) error {
+	return c.consumeErr
+}
+
+func TestPasswordResetEnumeration(t *testing.T) {
+	utils.SetLogger(logs.New(logs.Options{}))
+	ctx := context.Background()
+	newService := func(codes *passwordResetCodes) UserService {
+		userRepo := &passwordResetUserRepo{users: map[string]*entities.User{
+			"+6281111111111": {UserID: uuid.New(), PhoneNumber: "+6281111111111", Status: entities.UserStatusActive},
+			"+6282222222222": {UserID: uuid.New(), PhoneNumber: "+6282222222222", Status: entities.UserStatusInactive},
+		}}
+		return NewUserService(userRepo, middleware.NewAuthMiddleware("secret"), nil, nil, codes, nil, "")
+	}
+
+	t.Run("given unknown, inactive and active numbers, when requesting a reset, then every request succeeds and only the active one gets a code", func(t *testing.T) {
+		codes := &passwordResetCodes{}
+		s := newService(codes)
+
+		for _, phoneNumber := range []string{"+6281111111111", "+6282222222222", "+6289999999999"} {
+			assert.NoError(t, s.ForgotPassword(ctx, &entities.ForgotPasswordRequest{PhoneNumber: phoneNumber}))
+		}
+		assert.Equal(t, []string{"+6281111111111"}, codes.sent)
+	})
+
+	t.Run("given the code cannot be sent to an account, when requesting a reset, then the request still succeeds", func(t *testing.T) {
+		s := newService(&passwordResetCodes{createErr: errorsutil.New(429, "please wait before requesting another code")})
+
+		assert.NoError(t, s.ForgotPassword(ctx, &entities.ForgotPasswordRequest{PhoneNumber: "+6281111111111"}))
+	})
+
+	t.Run("given an unknown number and a missing code, when verifying, then both get the same invalid code error", func(t *testing.T) {
+		s := newService(&passwordResetCodes{consumeErr: errorsutil.New(404, "no active verification code found")})
+
+		_, unknownErr := s.VerifyPasswordResetCode(ctx, &entities.VerifyPasswordResetCodeRequest{PhoneNumber: "+6289999999999", Code: "123456"})
+		_, missingErr := s.VerifyPasswordResetCode(ctx, &entities.VerifyPasswordResetCodeRequest{PhoneNumber: "+6281111111111", Code: "123456"})
+		assert.EqualError(t, unknownErr, "invalid verification code")
+		assert.EqualError(t, missingErr, "invalid verification code")
+	})
+
+	t.Run("given a valid code, when verifying, then a reset token is returned", func(t *testing.T) {
+		s := newService(&passwordResetCodes{})
+
+		resetToken, err := s.VerifyPasswordResetCode(ctx, &entities.VerifyPasswordResetCodeRequest{PhoneNumber: "+6281111111111", Code: "123456"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resetToken.ResetToken)
+	})
+}
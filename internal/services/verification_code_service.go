@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
 	"errors"
 	"fmt"
 	"math/big"
@@ -19,6 +21,7 @@ type (
 	VerificationCodeService interface {
 		CreateVerificationCode(ctx context.Context, input *entities.CreateVerificationCodeRequest) (*entities.VerificationCode, error)
 		VerifyCode(ctx context.Context, input *entities.VerifyVerificationCodeRequest) error
+		ConsumeCode(ctx context.Context, phoneNumber, codeType, code string) error
 		ResendVerificationCode(ctx context.Context, phoneNumber, codeType string) error
 		CleanupExpiredCodes(ctx context.Context) error
 	}
@@ -26,14 +29,26 @@ type (
 	verificationCodeService struct {
 		verificationCodeRepo repositories.VerificationCodeRepository
 		userRepo             repositories.UserRepository
+		invitationService    WorkspaceInvitationService
+		codeSender           CodeSender
 	}
 )
 
+const (
+	verificationCodeTTL      = 10 * time.Minute
+	verificationCodeCooldown = 1 * time.Minute
+	// maxVerificationCodesPerHour caps the codes a phone number gets per type, with 3 attempts per code
+	// it also caps the guesses per hour
+	maxVerificationCodesPerHour = 5
+)
+
 // NewVerificationCodeService creates a new verification code service
-func NewVerificationCodeService(verificationCodeRepo repositories.VerificationCodeRepository, userRepo repositories.UserRepository) VerificationCodeService {
+func NewVerificationCodeService(verificationCodeRepo repositories.VerificationCodeRepository, userRepo repositories.UserRepository, invitationService WorkspaceInvitationService, codeSender CodeSender) VerificationCodeService {
 	return &verificationCodeService{
 		verificationCodeRepo: verificationCodeRepo,
 		userRepo:             userRepo,
+		invitationService:    invitationService,
+		codeSender:           codeSender,
 	}
 }
 
@@ -69,19 +84,26 @@ func (s *verificationCodeService) CreateVerificationCode(ctx context.Context, in
 	// If there's an existing active code, check if it's too recent (rate limiting)
 	if existingCode != nil {
 		timeSinceCreation := time.Since(existingCode.CreatedAt)
-		if timeSinceCreation < 1*time.Minute { // 1 minute cooldown
+		if timeSinceCreation < verificationCodeCooldown {
 			return nil, errorsutil.New(429, "please wait before requesting another code")
 		}
 	}
 
+	recentCodes, err := s.verificationCodeRepo.CountRecent(ctx, input.PhoneNumber, input.CodeType, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if recentCodes >= maxVerificationCodesPerHour {
+		return nil, errorsutil.New(429, "too many codes requested, try again later")
+	}
+
 	// Generate a random 6-digit code
 	code, err := generateRandomCode()
 	if err != nil {
 		return nil, err
 	}
 
-	// Set expiration time (10 minutes from now)
-	expiresAt := time.Now().Add(10 * time.Minute)
+	expiresAt := time.Now().Add(verificationCodeTTL)
 
 	verificationCode := &entities.VerificationCode{
 		VerificationCodeID: uuid.New(),
@@ -100,9 +122,15 @@ func (s *verificationCodeService) CreateVerificationCode(ctx context.Context, in
 		return nil, err
 	}
 
-	// TODO: Send SMS with the code
-	// For now, just return the code (in production, this should be sent via SMS)
-	fmt.Printf("Verification code for %s: %s\n", input.PhoneNumber, code)
+	channel := input.Channel
+	if channel == "" {
+		channel = entities.CodeDeliveryWhatsApp
+	}
+	if err := s.codeSender.SendCode(ctx, channel, input.PhoneNumber, code, input.CodeType); err != nil {
+		// An undelivered code must not hold back the next request
+		_ = s.verificationCodeRepo.Delete(ctx, createdCode.VerificationCodeID)
+		return nil, errorsutil.New(502, "failed to deliver verification code")
+	}
 
 	return &createdCode, nil
 }
@@ -117,8 +145,34 @@ func (s *verificationCodeService) VerifyCode(ctx context.Context, input *entitie
 		return errors.New("code is required")
 	}
 
+	if err := s.ConsumeCode(ctx, input.PhoneNumber, entities.VerificationCodeTypePhone, input.Code); err != nil {
+		return err
+	}
+
+	// Mark the user's phone number as verified, a code may also be verified before registering
+	user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	if err := s.userRepo.MarkPhoneVerified(ctx, user.UserID, user.PhoneNumber); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// Join the workspaces the verified phone number was invited to.
+	// The code is already used at this point, a failed join is picked up on the next verification.
+	_, _ = s.invitationService.JoinInvitedWorkspaces(ctx, user)
+
+	return nil
+}
+
+// ConsumeCode checks a code of a type issued to a phone number and uses it up
+func (s *verificationCodeService) ConsumeCode(ctx context.Context, phoneNumber, codeType, code string) error {
 	// Find the active verification code
-	verificationCode, err := s.verificationCodeRepo.FindActiveByPhoneNumberAndType(ctx, input.PhoneNumber, "phone_verification")
+	verificationCode, err := s.verificationCodeRepo.FindActiveByPhoneNumberAndType(ctx, phoneNumber, codeType)
 	if err != nil {
 		return err
 	}
@@ -136,40 +190,29 @@ func (s *verificationCodeService) VerifyCode(ctx context.Context, input *entitie
 		return errorsutil.New(400, "maximum verification attempts exceeded")
 	}
 
-	// Increment attempts count
+	// Increment attempts count, concurrent guesses past the maximum are refused here
 	err = s.verificationCodeRepo.IncrementAttempts(ctx, verificationCode.VerificationCodeID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorsutil.New(400, "maximum verification attempts exceeded")
+		}
 		return err
 	}
 
 	// Verify the code
-	if verificationCode.Code != input.Code {
+	if subtle.ConstantTimeCompare([]byte(verificationCode.Code), []byte(code)) != 1 {
 		return errorsutil.New(400, "invalid verification code")
 	}
 
-	// Mark the code as used
+	// Mark the code as used, a code is only good once
 	err = s.verificationCodeRepo.MarkAsUsed(ctx, verificationCode.VerificationCodeID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorsutil.New(404, "no active verification code found")
+		}
 		return err
 	}
 
-	// Update user's phone verification status
-	// user, err := s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
-	// if err != nil {
-	// 	return err
-	// }
-	// if user == nil {
-	// 	return errorsutil.New(404, "user not found")
-	// }
-
-	// now := time.Now()
-	// user.PhoneVerifiedAt = &now
-
-	// _, err = s.userRepo.Update(ctx, user)
-	// if err != nil {
-	// 	return err
-	// }
-
 	return nil
 }
 
@@ -184,7 +227,7 @@ func (s *verificationCodeService) ResendVerificationCode(ctx context.Context, ph
 	// If there's an existing active code, check if it's too recent
 	if existingCode != nil {
 		timeSinceCreation := time.Since(existingCode.CreatedAt)
-		if timeSinceCreation < 1*time.Minute { // 1 minute cooldown
+		if timeSinceCreation < verificationCodeCooldown {
 			return errorsutil.New(429, "please wait before requesting another code")
 		}
 	}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+// workspaceRolePermissions is the permission matrix of the workspace member roles
+var workspaceRolePermissions = map[int][]entities.WorkspacePermission{
+	entities.WorkspaceRoleOwner: {
+		entities.WorkspacePermissionView,
+		entities.WorkspacePermissionCreateTransaction,
+		entities.WorkspacePermissionManageOwnData,
+		entities.WorkspacePermissionManageAllData,
+		entities.WorkspacePermissionEditBudget,
+		entities.WorkspacePermissionManageBudget,
+		entities.WorkspacePermissionManageWorkspace,
+		entities.WorkspacePermissionManageMembers,
+		entities.WorkspacePermissionArchiveWorkspace,
+		entities.WorkspacePermissionDeleteWorkspace,
+		entities.WorkspacePermissionApproveExpense,
+	},
+	entities.WorkspaceRoleAdmin: {
+		entities.WorkspacePermissionView,
+		entities.WorkspacePermissionCreateTransaction,
+		entities.WorkspacePermissionManageOwnData,
+		entities.WorkspacePermissionManageAllData,
+		entities.WorkspacePermissionEditBudget,
+		entities.WorkspacePermissionManageBudget,
+		entities.WorkspacePermissionManageWorkspace,
+		entities.WorkspacePermissionManageMembers,
+		entities.WorkspacePermissionArchiveWorkspace,
+		entities.WorkspacePermissionApproveExpense,
+	},
+	entities.WorkspaceRoleMember: {
+		entities.WorkspacePermissionView,
+		entities.WorkspacePermissionCreateTransaction,
+		entities.WorkspacePermissionManageOwnData,
+		entities.WorkspacePermissionEditBudget,
+	},
+	entities.WorkspaceRoleViewer: {
+		entities.WorkspacePermissionView,
+	},
+}
+
+// roleHasPermission reports whether the permission matrix grants a permission to a role
+func roleHasPermission(role int, permission entities.WorkspacePermission) bool {
+	for _, granted := range workspaceRolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// archivedWorkspacePermissions are the permissions still granted on an archived, read-only workspace
+var archivedWorkspacePermissions = map[entities.WorkspacePermission]bool{
+	entities.WorkspacePermissionView:             true,
+	entities.WorkspacePermissionArchiveWorkspace: true,
+	entities.WorkspacePermissionDeleteWorkspace:  true,
+}
+
+// workspaceAllowsPermission reports whether the state of a workspace allows a permission, whatever the role
+func workspaceAllowsPermission(workspace *entities.Workspace, permission entities.WorkspacePermission) bool {
+	return workspace.ArchivedAt == nil || archivedWorkspacePermissions[permission]
+}
+
+// isValidWorkspaceRole reports whether a role exists in the permission matrix
+func isValidWorkspaceRole(role int) bool {
+	_, ok := workspaceRolePermissions[role]
+	return ok
+}
+
+//go:generate mockgen -source=workspace_authorizer.go -package=mock -destination=mock/workspace_authorizer_mock.go
+type (
+	// WorkspaceAuthorizer centralizes the access checks on workspaces for every service
+	WorkspaceAuthorizer interface {
+		// Authorize returns the workspace when the user is a member whose role grants the permission
+		Authorize(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, permission entities.WorkspacePermission) (*entities.Workspace, error)
+		// AuthorizeOwnData authorizes changing a record created by createdBy, members may only change their own records
+		AuthorizeOwnData(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, createdBy *uuid.UUID) (*entities.Workspace, error)
+		// GetMembership returns the active membership of a user, nil when the user is not a member
+		GetMembership(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error)
+	}
+
+	workspaceAuthorizer struct {
+		workspaceRepo repositories.WorkspaceRepository
+		memberRepo    repositories.WorkspaceMemberRepository
+	}
+)
+
+// NewWorkspaceAuthorizer creates a new workspace authorizer
+func NewWorkspaceAuthorizer(workspaceRepo repositories.WorkspaceRepository, memberRepo repositories.WorkspaceMemberRepository) WorkspaceAuthorizer {
+	return &workspaceAuthorizer{
+		workspaceRepo: workspaceRepo,
+		memberRepo:    memberRepo,
+	}
+}
+
+// Authorize returns the workspace when the user is a member whose role grants the permission
+func (a *workspaceAuthorizer) Authorize(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, permission entities.WorkspacePermission) (*entities.Workspace, error) {
+	workspace, member, err := a.findMembership(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !roleHasPermission(member.Role, permission) {
+		return nil, errorsutil.New(403, "access denied to workspace")
+	}
+	if !workspaceAllowsPermission(workspace, permission) {
+		return nil, errorsutil.New(409, "workspace is archived")
+	}
+	return workspace, nil
+}
+
+// AuthorizeOwnData authorizes changing a record created by createdBy, members may only change their own records
+func (a *workspaceAuthorizer) AuthorizeOwnData(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, createdBy *uuid.UUID) (*entities.Workspace, error) {
+	workspace, member, err := a.findMembership(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !workspaceAllowsPermission(workspace, entities.WorkspacePermissionManageOwnData) {
+		return nil, errorsutil.New(409, "workspace is archived")
+	}
+	if roleHasPermission(member.Role, entities.WorkspacePermissionManageAllData) {
+		return workspace, nil
+	}
+	if roleHasPermission(member.Role, entities.WorkspacePermissionManageOwnData) && createdBy != nil && *createdBy == userID {
+		return workspace, nil
+	}
+	return nil, errorsutil.New(403, "access denied to workspace")
+}
+
+// GetMembership returns the active membership of a user, nil when the user is not a member
+func (a *workspaceAuthorizer) GetMembership(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error) {
+	return a.memberRepo.FindByWorkspaceAndUser(ctx, workspaceID, userID)
+}
+
+func (a *workspaceAuthorizer) findMembership(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.Workspace, *entities.WorkspaceMember, error) {
+	workspace, err := a.workspaceRepo.FindByID(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if workspace == nil {
+		return nil, nil, errorsutil.New(404, "workspace not found")
+	}
+
+	member, err := a.memberRepo.FindByWorkspaceAndUser(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, errorsutil.New(403, "access denied to workspace")
+	}
+	workspace.Role = member.Role
+
+	return workspace, member, nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestRoleHasPermission(t *testing.T) {
+	t.Run("given a member, when recording and managing budgets, then only transactions and budget edits are allowed", func(t *testing.T) {
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleMember, entities.WorkspacePermissionView))
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleMember, entities.WorkspacePermissionCreateTransaction))
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleMember, entities.WorkspacePermissionEditBudget))
+		assert.False(t, roleHasPermission(entities.WorkspaceRoleMember, entities.WorkspacePermissionManageBudget))
+		assert.False(t, roleHasPermission(entities.WorkspaceRoleMember, entities.WorkspacePermissionManageAllData))
+	})
+
+	t.Run("given a viewer, when checking any write permission, then it is denied", func(t *testing.T) {
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleViewer, entities.WorkspacePermissionView))
+		assert.False(t, roleHasPermission(entities.WorkspaceRoleViewer, entities.WorkspacePermissionCreateTransaction))
+		assert.False(t, roleHasPermission(entities.WorkspaceRoleViewer, entities.WorkspacePermissionEditBudget))
+	})
+
+	t.Run("given an admin, when deleting the workspace, then only the owner may", func(t *testing.T) {
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleAdmin, entities.WorkspacePermissionManageMembers))
+		assert.False(t, roleHasPermission(entities.WorkspaceRoleAdmin, entities.WorkspacePermissionDeleteWorkspace))
+		assert.True(t, roleHasPermission(entities.WorkspaceRoleOwner, entities.WorkspacePermissionDeleteWorkspace))
+	})
+
+	t.Run("given an unknown role, when checking viewing, then it is denied", func(t *testing.T) {
+		assert.False(t, roleHasPermission(0, entities.WorkspacePermissionView))
+	})
+}
+
+func TestWorkspaceAllowsPermission(t *testing.T) {
+	archivedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("given an active workspace, when recording a transaction, then it is allowed", func(t *testing.T) {
+		assert.True(t, workspaceAllowsPermission(&entities.Workspace{}, entities.WorkspacePermissionCreateTransaction))
+	})
+
+	t.Run("given an archived workspace, when writing to it, then it is denied", func(t *testing.T) {
+		workspace := &entities.Workspace{ArchivedAt: &archivedAt}
+		assert.False(t, workspaceAllowsPermission(workspace, entities.WorkspacePermissionCreateTransaction))
+		assert.False(t, workspaceAllowsPermission(workspace, entities.WorkspacePermissionManageBudget))
+		assert.False(t, workspaceAllowsPermission(workspace, entities.WorkspacePermissionManageWorkspace))
+	})
+
+	t.Run("given an archived workspace, when viewing or unarchiving it, then it is allowed", func(t *testing.T) {
+		workspace := &entities.Workspace{ArchivedAt: &archivedAt}
+		assert.True(t, workspaceAllowsPermission(workspace, entities.WorkspacePermissionView))
+		assert.True(t, workspaceAllowsPermission(workspace, entities.WorkspacePermissionArchiveWorkspace))
+	})
+}
+
+func TestValidateAssignableRole(t *testing.T) {
+	t.Run("given the owner, when assigning admin, then it is allowed", func(t *testing.T) {
+		assert.NoError(t, validateAssignableRole(entities.WorkspaceRoleOwner, entities.WorkspaceRoleAdmin))
+	})
+
+	t.Run("given an admin, when assigning admin, then it is denied", func(t *testing.T) {
+		assert.EqualError(t, validateAssignableRole(entities.WorkspaceRoleAdmin, entities.WorkspaceRoleAdmin), "access denied to workspace member")
+		assert.NoError(t, validateAssignableRole(entities.WorkspaceRoleAdmin, entities.WorkspaceRoleViewer))
+	})
+
+	t.Run("given the owner role or an unknown role, when assigning, then the role is invalid", func(t *testing.T) {
+		assert.EqualError(t, validateAssignableRole(entities.WorkspaceRoleOwner, entities.WorkspaceRoleOwner), "invalid role")
+		assert.EqualError(t, validateAssignableRole(entities.WorkspaceRoleOwner, 9), "invalid role")
+	})
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=workspace_invitation_service.go -package=mock -destination=mock/workspace_invitation_service_mock.go
+type (
+	WorkspaceInvitationService interface {
+		// Inviter methods
+		CreateInvitation(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.CreateWorkspaceInvitationRequest) (*entities.WorkspaceInvitation, error)
+		GetWorkspaceInvitations(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.WorkspaceInvitation, error)
+		RevokeInvitation(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, invitationID uuid.UUID) error
+
+		// Invitee methods
+		GetMyInvitations(ctx context.Context, userID uuid.UUID) ([]*entities.WorkspaceInvitation, error)
+		AcceptInvitation(ctx context.Context, userID uuid.UUID, input *entities.RespondWorkspaceInvitationRequest) (*entities.WorkspaceMember, error)
+		DeclineInvitation(ctx context.Context, userID uuid.UUID, input *entities.RespondWorkspaceInvitationRequest) error
+
+		// Registration flow: the invitation is claimed at registration and accepted after phone verification
+		ValidateRegistrationInvitation(ctx context.Context, token string, phoneNumber string) (*entities.WorkspaceInvitation, error)
+		ClaimInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error
+		JoinInvitedWorkspaces(ctx context.Context, user *entities.User) (int, error)
+	}
+
+	workspaceInvitationService struct {
+		invitationRepo      repositories.WorkspaceInvitationRepository
+		memberRepo          repositories.WorkspaceMemberRepository
+		userRepo            repositories.UserRepository
+		authorizer          WorkspaceAuthorizer
+		notificationService NotificationService
+		authMiddleware      *middleware.AuthMiddleware
+		appURL              string
+		events              DomainEventRecorder
+	}
+)
+
+// NewWorkspaceInvitationService creates a new workspace invitation service.
+// Invitation links point to appURL, they are left out when it is empty.
+func NewWorkspaceInvitationService(
+	invitationRepo repositories.WorkspaceInvitationRepository,
+	memberRepo repositories.WorkspaceMemberRepository,
+	userRepo repositories.UserRepository,
+	authorizer WorkspaceAuthorizer,
+	notificationService NotificationService,
+	authMiddleware *middleware.AuthMiddleware,
+	appURL string,
+	events DomainEventRecorder,
+) WorkspaceInvitationService {
+	return &workspaceInvitationService{
+		invitationRepo:      invitationRepo,
+		memberRepo:          memberRepo,
+		userRepo:            userRepo,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		authMiddleware:      authMiddleware,
+		appURL:              strings.TrimRight(appURL, "/"),
+		events:              events,
+	}
+}
+
+// CreateInvitation invites a user by email or WhatsApp phone number, or creates a shareable link when both are empty
+func (s *workspaceInvitationService) CreateInvitation(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.CreateWorkspaceInvitationRequest) (*entities.WorkspaceInvitation, error) {
+	email := strings.TrimSpace(input.Email)
+	phoneNumber := strings.TrimSpace(input.PhoneNumber)
+
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAssignableRole(workspace.Role, input.Role); err != nil {
+		return nil, err
+	}
+
+	invitation := &entities.WorkspaceInvitation{
+		InvitationID: uuid.New(),
+		WorkspaceID:  workspaceID,
+		InvitedBy:    &userID,
+		Role:         input.Role,
+		Status:       entities.WorkspaceInvitationStatusPending,
+		ExpiresAt:    time.Now().Add(entities.WorkspaceInvitationTTL),
+	}
+
+	if email != "" || phoneNumber != "" {
+		existingInvitation, err := s.invitationRepo.FindPendingByContact(ctx, workspaceID, email, phoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		if existingInvitation != nil {
+			return nil, errorsutil.New(409, "an invitation is already pending for this contact")
+		}
+
+		invitee, err := s.findUserByContact(ctx, email, phoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		if invitee != nil {
+			member, err := s.memberRepo.FindByWorkspaceAndUser(ctx, workspaceID, invitee.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if member != nil {
+				return nil, errorsutil.New(409, "user is already a member of this workspace")
+			}
+			invitation.InviteeUserID = &invitee.UserID
+		}
+	}
+	if email != "" {
+		invitation.Email = &email
+	}
+	if phoneNumber != "" {
+		invitation.PhoneNumber = &phoneNumber
+	}
+
+	createdInvitation, err := s.invitationRepo.Create(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachToken(&createdInvitation); err != nil {
+		return nil, err
+	}
+
+	// Registered invitees are told in the app, the inviter shares the link through WhatsApp or email otherwise.
+	// A failed notification does not undo the invitation, it is still listed for the invitee.
+	if createdInvitation.InviteeUserID != nil {
+		_, _ = s.notificationService.Notify(ctx, *createdInvitation.InviteeUserID, entities.NotificationTypeWorkspaceInvitation,
+			"Undangan workspace",
+			fmt.Sprintf("%s mengundang Anda ke workspace %s", createdInvitation.InviterName, createdInvitation.WorkspaceName),
+			map[string]interface{}{
+				"invitation_id": createdInvitation.InvitationID.String(),
+				"workspace_id":  createdInvitation.WorkspaceID.String(),
+			},
+		)
+	}
+
+	return &createdInvitation, nil
+}
+
+// GetWorkspaceInvitations returns the pending invitations of a workspace with their tokens
+func (s *workspaceInvitationService) GetWorkspaceInvitations(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.WorkspaceInvitation, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.FindPendingByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if err := s.attachToken(invitation); err != nil {
+			return nil, err
+		}
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation cancels a pending invitation so its token can no longer be used
+func (s *workspaceInvitationService) RevokeInvitation(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, invitationID uuid.UUID) error {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation == nil || invitation.WorkspaceID != workspaceID {
+		return errorsutil.New(404, "invitation not found")
+	}
+
+	return s.respond(ctx, invitation.InvitationID, entities.WorkspaceInvitationStatusRevoked)
+}
+
+// GetMyInvitations returns the pending invitations addressed to a user, with tokens to accept or decline them
+func (s *workspaceInvitationService) GetMyInvitations(ctx context.Context, userID uuid.UUID) ([]*entities.WorkspaceInvitation, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	// Anyone can set an unverified email or phone number, so invitations are only matched on verified ones
+	email, phoneNumber := "", ""
+	if user.EmailVerifiedAt != nil {
+		email = user.Email
+	}
+	if user.PhoneVerifiedAt != nil {
+		phoneNumber = user.PhoneNumber
+	}
+	invitations, err := s.invitationRepo.FindPendingForUser(ctx, userID, email, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if err := s.attachToken(invitation); err != nil {
+			return nil, err
+		}
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation joins the workspace of an invitation with the invited role
+func (s *workspaceInvitationService) AcceptInvitation(ctx context.Context, userID uuid.UUID, input *entities.RespondWorkspaceInvitationRequest) (*entities.WorkspaceMember, error) {
+	invitation, err := s.resolveInviteeInvitation(ctx, userID, input.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	existingMember, err := s.memberRepo.FindByWorkspaceAndUser(ctx, invitation.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existingMember != nil {
+		return nil, errorsutil.New(409, "user is already a member of this workspace")
+	}
+
+	return s.join(ctx, invitation, userID)
+}
+
+// DeclineInvitation declines an invitation, its token can no longer be used
+func (s *workspaceInvitationService) DeclineInvitation(ctx context.Context, userID uuid.UUID, input *entities.RespondWorkspaceInvitationRequest) error {
+	invitation, err := s.resolveInviteeInvitation(ctx, userID, input.Token)
+	if err != nil {
+		return err
+	}
+
+	return s.respond(ctx, invitation.InvitationID, entities.WorkspaceInvitationStatusDeclined)
+}
+
+// ValidateRegistrationInvitation checks the invitation token a new user registers with.
+// An invitation sent to a phone number can only be claimed by that phone number.
+func (s *workspaceInvitationService) ValidateRegistrationInvitation(ctx context.Context, token string, phoneNumber string) (*entities.WorkspaceInvitation, error) {
+	invitation, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeUserID != nil ||
+		(invitation.PhoneNumber != nil && *invitation.PhoneNumber != strings.TrimSpace(phoneNumber)) {
+		return nil, errorsutil.New(403, "invitation was sent to another user")
+	}
+
+	return invitation, nil
+}
+
+// ClaimInvitation records the registered user of an invitation, it is accepted once the phone number is verified
+func (s *workspaceInvitationService) ClaimInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	if err := s.invitationRepo.SetInvitee(ctx, invitationID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return errorsutil.New(409, "invitation is no longer pending")
+		}
+		return err
+	}
+	return nil
+}
+
+// JoinInvitedWorkspaces accepts the pending invitations claimed by a user or sent to the user's verified phone number.
+// It returns the number of workspaces joined.
+func (s *workspaceInvitationService) JoinInvitedWorkspaces(ctx context.Context, user *entities.User) (int, error) {
+	// The email is not verified at this point, so email invitations are only joined once claimed
+	invitations, err := s.invitationRepo.FindPendingForUser(ctx, user.UserID, "", user.PhoneNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	joined := 0
+	for _, invitation := range invitations {
+		member, err := s.memberRepo.FindByWorkspaceAndUser(ctx, invitation.WorkspaceID, user.UserID)
+		if err != nil {
+			return joined, err
+		}
+		if member != nil {
+			continue
+		}
+
+		if _, err := s.join(ctx, invitation, user.UserID); err != nil {
+			// Accepted meanwhile, or joined through another invitation
+			if errors.Is(err, errInvitationNotPending) || errors.Is(err, errAlreadyMember) {
+				continue
+			}
+			return joined, err
+		}
+		joined++
+	}
+
+	return joined, nil
+}
+
+var (
+	errInvitationNotPending = errorsutil.New(409, "invitation is no longer pending")
+	errAlreadyMember        = errorsutil.New(409, "user is already a member of this workspace")
+)
+
+// join consumes a pending invitation and adds the user to its workspace, both or neither are written
+func (s *workspaceInvitationService) join(ctx context.Context, invitation *entities.WorkspaceInvitation, userID uuid.UUID) (*entities.WorkspaceMember, error) {
+	err := s.invitationRepo.Accept(ctx, invitation.InvitationID, &entities.WorkspaceMember{
+		WorkspaceMemberID: uuid.New(),
+		WorkspaceID:       invitation.WorkspaceID,
+		UserID:            userID,
+		Role:              invitation.Role,
+		InvitedBy:         invitation.InvitedBy,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errInvitationNotPending
+		}
+		if errors.Is(err, repositories.ErrAlreadyMember) {
+			return nil, errAlreadyMember
+		}
+		return nil, err
+	}
+
+	member, err := s.memberRepo.FindByWorkspaceAndUser(ctx, invitation.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errorsutil.New(404, "member not found")
+	}
+
+	s.events.Record(ctx, memberJoinedEvent(member, userID, ""))
+
+	return member, nil
+}
+
+// respond moves a pending invitation to its final status, only the first response wins
+func (s *workspaceInvitationService) respond(ctx context.Context, invitationID uuid.UUID, status int) error {
+	if err := s.invitationRepo.Respond(ctx, invitationID, status); err != nil {
+		if err == sql.ErrNoRows {
+			return errInvitationNotPending
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveInviteeInvitation resolves a token for a registered user, who must be the addressee of the invitation
+func (s *workspaceInvitationService) resolveInviteeInvitation(ctx context.Context, userID uuid.UUID, token string) (*entities.WorkspaceInvitation, error) {
+	invitation, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+	if !invitationAddressedTo(invitation, user) {
+		return nil, errorsutil.New(403, "invitation was sent to another user")
+	}
+
+	return invitation, nil
+}
+
+// resolveToken returns the pending, unexpired invitation of a token
+func (s *workspaceInvitationService) resolveToken(ctx context.Context, token string) (*entities.WorkspaceInvitation, error) {
+	invitationID, err := s.authMiddleware.ValidateInvitationToken(token)
+	if err != nil {
+		return nil, errors.New("invalid invitation token")
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, errorsutil.New(404, "invitation not found")
+	}
+	if invitation.Status != entities.WorkspaceInvitationStatusPending {
+		return nil, errInvitationNotPending
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, errors.New("invitation has expired")
+	}
+
+	return invitation, nil
+}
+
+// attachToken signs the token and link of an invitation
+func (s *workspaceInvitationService) attachToken(invitation *entities.WorkspaceInvitation) error {
+	token, err := s.authMiddleware.GenerateInvitationToken(invitation.InvitationID, invitation.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	invitation.Token = token
+	if s.appURL != "" {
+		invitation.InvitationURL = s.appURL + "/invitations?token=" + url.QueryEscape(token)
+	}
+	return nil
+}
+
+// findUserByContact finds the registered user of an email or phone number, only when that contact is verified.
+// Anyone can put an unverified email or phone number on their profile, binding the invitation to them would hand it over.
+func (s *workspaceInvitationService) findUserByContact(ctx context.Context, email, phoneNumber string) (*entities.User, error) {
+	if email != "" {
+		user, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.EmailVerifiedAt != nil {
+			return user, nil
+		}
+	}
+	if phoneNumber != "" {
+		user, err := s.userRepo.FindByPhoneNumber(ctx, phoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.PhoneVerifiedAt != nil {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// invitationAddressedTo reports whether a registered user may respond to an invitation.
+// Links are open to anyone holding the token, other invitations only to the verified email or phone number invited.
+// An invitation bound to a user is also limited to that user.
+func invitationAddressedTo(invitation *entities.WorkspaceInvitation, user *entities.User) bool {
+	if invitation.InviteeUserID != nil && *invitation.InviteeUserID != user.UserID {
+		return false
+	}
+	if invitation.Email == nil && invitation.PhoneNumber == nil {
+		return true
+	}
+	if invitation.PhoneNumber != nil && user.PhoneVerifiedAt != nil && *invitation.PhoneNumber == user.PhoneNumber {
+		return true
+	}
+	return invitation.Email != nil && user.Email != "" && user.EmailVerifiedAt != nil && strings.EqualFold(*invitation.Email, user.Email)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+)
+
+func TestInvitationAddressedTo(t *testing.T) {
+	verifiedAt := time.Now()
+	user := &entities.User{
+		UserID:          uuid.New(),
+		Email:           "budi@example.com",
+		EmailVerifiedAt: &verifiedAt,
+		PhoneNumber:     "+6281234567890",
+		PhoneVerifiedAt: &verifiedAt,
+	}
+
+	t.Run("given a link invitation, when any user responds, then it is allowed", func(t *testing.T) {
+		assert.True(t, invitationAddressedTo(&entities.WorkspaceInvitation{}, user))
+	})
+
+	t.Run("given an invitation to the user's phone number, when the user responds, then it is allowed", func(t *testing.T) {
+		phoneNumber := "+6281234567890"
+		assert.True(t, invitationAddressedTo(&entities.WorkspaceInvitation{PhoneNumber: &phoneNumber}, user))
+	})
+
+	t.Run("given an invitation to the user's email in another case, when the user responds, then it is allowed", func(t *testing.T) {
+		email := "Budi@Example.com"
+		assert.True(t, invitationAddressedTo(&entities.WorkspaceInvitation{Email: &email}, user))
+	})
+
+	t.Run("given an invitation to another phone number, when the user responds, then it is denied", func(t *testing.T) {
+		phoneNumber := "+6289999999999"
+		assert.False(t, invitationAddressedTo(&entities.WorkspaceInvitation{PhoneNumber: &phoneNumber}, user))
+	})
+
+	t.Run("given an invitation claimed by another user, when the user responds with a matching phone number, then it is denied", func(t *testing.T) {
+		otherUserID := uuid.New()
+		phoneNumber := "+6281234567890"
+		invitation := &entities.WorkspaceInvitation{InviteeUserID: &otherUserID, PhoneNumber: &phoneNumber}
+		assert.False(t, invitationAddressedTo(invitation, user))
+	})
+
+	t.Run("given an invitation to the user's unverified email, when the user responds, then it is denied", func(t *testing.T) {
+		email := "budi@example.com"
+		unverified := &entities.User{UserID: uuid.New(), Email: email, PhoneNumber: "+6281111111111"}
+		assert.False(t, invitationAddressedTo(&entities.WorkspaceInvitation{Email: &email}, unverified))
+	})
+
+	t.Run("given an email invitation, when a user without email responds, then it is denied", func(t *testing.T) {
+		email := "budi@example.com"
+		assert.False(t, invitationAddressedTo(&entities.WorkspaceInvitation{Email: &email}, &entities.User{UserID: uuid.New()}))
+	})
+
+	t.Run("given an invitation to the user's unverified phone number, when the user responds, then it is denied", func(t *testing.T) {
+		phoneNumber := "+6282222222222"
+		unverified := &entities.User{UserID: uuid.New(), PhoneNumber: phoneNumber}
+		assert.False(t, invitationAddressedTo(&entities.WorkspaceInvitation{PhoneNumber: &phoneNumber}, unverified))
+	})
+
+	t.Run("given an email invitation bound to the user, when the email is not verified, then it is denied", func(t *testing.T) {
+		email := "budi@example.com"
+		unverified := &entities.User{UserID: uuid.New(), Email: email, PhoneNumber: "+6281111111111"}
+		invitation := &entities.WorkspaceInvitation{InviteeUserID: &unverified.UserID, Email: &email}
+		assert.False(t, invitationAddressedTo(invitation, unverified))
+	})
+
+	t.Run("given a link claimed by the user at registration, when the user responds, then it is allowed", func(t *testing.T) {
+		assert.True(t, invitationAddressedTo(&entities.WorkspaceInvitation{InviteeUserID: &user.UserID}, user))
+	})
+}
+
+// invitationStore keeps the invitations of a test, Accept fails with acceptErr when it is set
+type invitationStore struct {
+	repositories.WorkspaceInvitationRepository
+	invitations map[uuid.UUID]*entities.WorkspaceInvitation
+	members     *invitedMembers
+	acceptErr   error
+}
+
+func (r *invitationStore) FindPendingByContact(ctx context.Context, workspaceID uuid.UUID, email, phoneNumber string) (*entities.WorkspaceInvitation, error) {
+	return nil, nil
+}
+
+func (r *invitationStore) Create(ctx context.Context, invitation *entities.WorkspaceInvitation) (entities.WorkspaceInvitation, error) {
+	r.invitations[invitation.InvitationID] = invitation
+	return *invitation, nil
+}
+
+func (r *invitationStore) FindByID(ctx context.Context, invitationID uuid.UUID) (*entities.WorkspaceInvitation, error) {
+	if invitation, ok := r.invitations[invitationID]; ok {
+		copied := *invitation
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *invitationStore) Accept(ctx context.Context, invitationID uuid.UUID, member *entities.WorkspaceMember) error {
+	if r.acceptErr != nil {
+		return r.acceptErr
+	}
+	invitation := r.invitations[invitationID]
+	if invitation.Status != entities.WorkspaceInvitationStatusPending {
+		return sql.ErrNoRows
+	}
+	invitation.Status = entities.WorkspaceInvitationStatusAccepted
+	r.members.members[member.UserID] = member
+	return nil
+}
+
+// invitedMembers keeps the members of the invited workspace
+type invitedMembers struct {
+	repositories.WorkspaceMemberRepository
+	members map[uuid.UUID]*entities.WorkspaceMember
+}
+
+func (r *invitedMembers) FindByWorkspaceAndUser(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID) (*entities.WorkspaceMember, error) {
+	return r.members[userID], nil
+}
+
+// invitationUsers finds the registered users of a test by ID, email or phone number
+type invitationUsers struct {
+	repositories.UserRepository
+	users []*entities.User
+}
+
+func (r *invitationUsers) FindByID(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	for _, user := range r.users {
+		if user.UserID == userID {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *invitationUsers) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *invitationUsers) FindByPhoneNumber(ctx context.Context, phoneNumber string) (*entities.User, error) {
+	for _, user := range r.users {
+		if user.PhoneNumber == phoneNumber {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// ignoredNotifications accepts the notifications of a test
+type ignoredNotifications struct {
+	NotificationService
+}
+
+func (n *ignoredNotifications) Notify(ctx context.Context, userID uuid.UUID, notificationType, title, body string, data map[string]interface{}) (*entities.Notification, error) {
+	return &entities.Notification{}, nil
+}
+
+func TestWorkspaceInvitationInvitee(t *testing.T) {
+	ctx := context.Background()
+	verifiedAt := time.Now()
+	owner := uuid.New()
+	workspace := &entities.Workspace{WorkspaceID: uuid.New(), Role: entities.WorkspaceRoleOwner}
+
+	verifiedUser := &entities.User{UserID: uuid.New(), Email: "budi@example.com", EmailVerifiedAt: &verifiedAt,
+		PhoneNumber: "+6281234567890", PhoneVerifiedAt: &verifiedAt}
+	// squatter put the contacts of someone else on an unverified profile
+	squatter := &entities.User{UserID: uuid.New(), Email: "siti@example.com", PhoneNumber: "+6289876543210"}
+
+	newService := func() (*invitationStore, *recordedEvents, WorkspaceInvitationService) {
+		members := &invitedMembers{members: map[uuid.UUID]*entities.WorkspaceMember{}}
+		store := &invitationStore{invitations: map[uuid.UUID]*entities.WorkspaceInvitation{}, members: members}
+		events := &recordedEvents{}
+		return store, events, NewWorkspaceInvitationService(store, members,
+			&invitationUsers{users: []*entities.User{verifiedUser, squatter}},
+			&memberAuthorizer{workspace: workspace, members: map[uuid.UUID]bool{owner: true}},
+			&ignoredNotifications{}, middleware.NewAuthMiddleware("secret"), "", events)
+	}
+
+	t.Run("given the verified contacts of a user, when inviting them, then the invitation is bound to the user", func(t *testing.T) {
+		_, _, s := newService()
+
+		invitation, err := s.CreateInvitation(ctx, owner, workspace.WorkspaceID, &entities.CreateWorkspaceInvitationRequest{Email: verifiedUser.Email, Role: entities.WorkspaceRoleMember})
+		assert.NoError(t, err)
+		assert.Equal(t, &verifiedUser.UserID, invitation.InviteeUserID)
+
+		invitation, err = s.CreateInvitation(ctx, owner, workspace.WorkspaceID, &entities.CreateWorkspaceInvitationRequest{PhoneNumber: verifiedUser.PhoneNumber, Role: entities.WorkspaceRoleMember})
+		assert.NoError(t, err)
+		assert.Equal(t, &verifiedUser.UserID, invitation.InviteeUserID)
+	})
+
+	t.Run("given unverified contacts on a profile, when inviting them, then the invitation is not bound and cannot be accepted", func(t *testing.T) {
+		_, _, s := newService()
+
+		for _, request := range []*entities.CreateWorkspaceInvitationRequest{
+			{Email: squatter.Email, Role: entities.WorkspaceRoleMember},
+			{PhoneNumber: squatter.PhoneNumber, Role: entities.WorkspaceRoleMember},
+		} {
+			invitation, err := s.CreateInvitation(ctx, owner, workspace.WorkspaceID, request)
+			assert.NoError(t, err)
+			assert.Nil(t, invitation.InviteeUserID)
+
+			_, err = s.AcceptInvitation(ctx, squatter.UserID, &entities.RespondWorkspaceInvitationRequest{Token: invitation.Token})
+			assert.EqualError(t, err, "invitation was sent to another user")
+		}
+	})
+
+	t.Run("given an invitation, when it is accepted, then the invitation is used up and the member added", func(t *testing.T) {
+		store, events, s := newService()
+		invitation, err := s.CreateInvitation(ctx, owner, workspace.WorkspaceID, &entities.CreateWorkspaceInvitationRequest{Email: verifiedUser.Email, Role: entities.WorkspaceRoleMember})
+		assert.NoError(t, err)
+
+		member, err := s.AcceptInvitation(ctx, verifiedUser.UserID, &entities.RespondWorkspaceInvitationRequest{Token: invitation.Token})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.WorkspaceRoleMember, member.Role)
+		assert.Equal(t, entities.WorkspaceInvitationStatusAccepted, store.invitations[invitation.InvitationID].Status)
+		assert.Len(t, *events, 1)
+	})
+
+	t.Run("given the member cannot be added, when accepting, then the error is returned and nothing is recorded", func(t *testing.T) {
+		store, events, s := newService()
+		invitation, err := s.CreateInvitation(ctx, owner, workspace.WorkspaceID, &entities.CreateWorkspaceInvitationRequest{Email: verifiedUser.Email, Role: entities.WorkspaceRoleMember})
+		assert.NoError(t, err)
+		store.acceptErr = errors.New("insert failed")
+
+		_, err = s.AcceptInvitation(ctx, verifiedUser.UserID, &entities.RespondWorkspaceInvitationRequest{Token: invitation.Token})
+		assert.EqualError(t, err, "insert failed")
+		assert.Equal(t, entities.WorkspaceInvitationStatusPending, store.invitations[invitation.InvitationID].Status)
+		assert.Empty(t, *events)
+	})
+
+	t.Run("given the user joined meanwhile, when accepting, then it is reported as a conflict", func(t *testing.T) {
+		store, _, s := newService()
+		invitation, err := s.CreateInvitation(ctx, owner, workspace.WorkspaceID, &entities.CreateWorkspaceInvitationRequest{Email: verifiedUser.Email, Role: entities.WorkspaceRoleMember})
+		assert.NoError(t, err)
+		store.acceptErr = repositories.ErrAlreadyMember
+
+		_, err = s.AcceptInvitation(ctx, verifiedUser.UserID, &entities.RespondWorkspaceInvitationRequest{Token: invitation.Token})
+		assert.EqualError(t, err, "user is already a member of this workspace")
+	})
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/middleware"
 	"github.com/vasst-id/vasst-expense-api/internal/repositories"
 	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
 )
@@ -13,23 +16,53 @@ import (
 //go:generate mockgen -source=workspace_service.go -package=mock -destination=mock/workspace_service_mock.go
 type (
 	WorkspaceService interface {
-		// Global (superadmin) methods
 		CreateWorkspace(ctx context.Context, userID uuid.UUID, input *entities.CreateWorkspaceInput) (*entities.Workspace, error)
-		UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, input *entities.UpdateWorkspaceInput) (*entities.Workspace, error)
-		DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+		UpdateWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.UpdateWorkspaceInput) (*entities.Workspace, error)
+		DeleteWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) error
+		ArchiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error)
+		UnarchiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error)
 		ListAllWorkspaces(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error)
-		GetWorkspaceByID(ctx context.Context, workspaceID uuid.UUID) (*entities.Workspace, error)
+		GetWorkspaceByID(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error)
+		GetSettings(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.WorkspaceSettings, error)
+		UpdateSettings(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, patch []byte) (*entities.WorkspaceSettings, error)
+		SwitchWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.SwitchWorkspaceRequest) (*entities.SwitchWorkspaceResponse, error)
+
+		// Member management
+		GetMembers(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.WorkspaceMember, error)
+		AddMember(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.AddWorkspaceMemberRequest) (*entities.WorkspaceMember, error)
+		UpdateMemberRole(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, memberUserID uuid.UUID, input *entities.UpdateWorkspaceMemberRequest) (*entities.WorkspaceMember, error)
+		RemoveMember(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, memberUserID uuid.UUID) error
 	}
 
 	workspaceService struct {
-		workspaceRepo repositories.WorkspaceRepository
+		workspaceRepo  repositories.WorkspaceRepository
+		memberRepo     repositories.WorkspaceMemberRepository
+		userRepo       repositories.UserRepository
+		accountRepo    repositories.AccountRepository
+		authorizer     WorkspaceAuthorizer
+		authMiddleware *middleware.AuthMiddleware
+		events         DomainEventRecorder
 	}
 )
 
 // NewWorkspaceService creates a new workspace service
-func NewWorkspaceService(workspaceRepo repositories.WorkspaceRepository) WorkspaceService {
+func NewWorkspaceService(
+	workspaceRepo repositories.WorkspaceRepository,
+	memberRepo repositories.WorkspaceMemberRepository,
+	userRepo repositories.UserRepository,
+	accountRepo repositories.AccountRepository,
+	authorizer WorkspaceAuthorizer,
+	authMiddleware *middleware.AuthMiddleware,
+	events DomainEventRecorder,
+) WorkspaceService {
 	return &workspaceService{
-		workspaceRepo: workspaceRepo,
+		workspaceRepo:  workspaceRepo,
+		memberRepo:     memberRepo,
+		userRepo:       userRepo,
+		accountRepo:    accountRepo,
+		authorizer:     authorizer,
+		authMiddleware: authMiddleware,
+		events:         events,
 	}
 }
 
@@ -52,6 +85,15 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, userID uuid.UUID
 		timezone = "Asia/Jakarta"
 	}
 
+	// Workspaces use standard budgets unless envelope budgeting is requested
+	budgetingMode := input.BudgetingMode
+	if budgetingMode == 0 {
+		budgetingMode = entities.BudgetingModeStandard
+	}
+	if budgetingMode != entities.BudgetingModeStandard && budgetingMode != entities.BudgetingModeEnvelope {
+		return nil, errors.New("invalid budgeting mode")
+	}
+
 	// Check if workspace with same name already exists
 	existingWorkspace, err := s.workspaceRepo.FindByName(ctx, input.Name)
 	if err != nil {
@@ -70,7 +112,8 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, userID uuid.UUID
 		ColorCode:     input.ColorCode,
 		CurrencyID:    input.CurrencyID,
 		Timezone:      timezone,
-		Settings:      "{}",   // Default empty JSON
+		Settings:      entities.DefaultWorkspaceSettings(input.WorkspaceType),
+		BudgetingMode: budgetingMode,
 		IsActive:      true,   // Default to active
 		CreatedBy:     userID, // Use the authenticated user ID
 	}
@@ -81,19 +124,18 @@ func (s *workspaceService) CreateWorkspace(ctx context.Context, userID uuid.UUID
 		return nil, err
 	}
 
+	createdWorkspace.Role = entities.WorkspaceRoleOwner
+
 	// Return the workspace with data populated from the database
 	return &createdWorkspace, nil
 }
 
 // UpdateWorkspace updates an existing workspace
-func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID uuid.UUID, input *entities.UpdateWorkspaceInput) (*entities.Workspace, error) {
-	existingWorkspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+func (s *workspaceService) UpdateWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.UpdateWorkspaceInput) (*entities.Workspace, error) {
+	existingWorkspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageWorkspace)
 	if err != nil {
 		return nil, err
 	}
-	if existingWorkspace == nil {
-		return nil, errorsutil.New(404, "workspace not found")
-	}
 
 	// Check for name uniqueness if name is being changed
 	if input.Name != "" && input.Name != existingWorkspace.Name {
@@ -115,6 +157,10 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID uuid
 	}
 	if input.WorkspaceType != 0 {
 		existingWorkspace.WorkspaceType = input.WorkspaceType
+		// Approvals only exist in business workspaces
+		if input.WorkspaceType != entities.WorkspaceTypeBusiness {
+			existingWorkspace.Settings.Approvals = entities.WorkspaceApprovalSettings{RequiredForUserIDs: []uuid.UUID{}}
+		}
 	}
 	if input.Icon != "" {
 		existingWorkspace.Icon = input.Icon
@@ -128,6 +174,12 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID uuid
 	if input.Timezone != "" {
 		existingWorkspace.Timezone = input.Timezone
 	}
+	if input.BudgetingMode != 0 {
+		if input.BudgetingMode != entities.BudgetingModeStandard && input.BudgetingMode != entities.BudgetingModeEnvelope {
+			return nil, errors.New("invalid budgeting mode")
+		}
+		existingWorkspace.BudgetingMode = input.BudgetingMode
+	}
 	// Update IsActive field
 	existingWorkspace.IsActive = input.IsActive
 
@@ -137,35 +189,306 @@ func (s *workspaceService) UpdateWorkspace(ctx context.Context, workspaceID uuid
 		return nil, err
 	}
 
+	updatedWorkspace.Role = existingWorkspace.Role
+
 	// Return the workspace with data populated from the database
 	return &updatedWorkspace, nil
 }
 
 // DeleteWorkspace deletes a workspace
-func (s *workspaceService) DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error {
-	existingWorkspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
-	if err != nil {
+func (s *workspaceService) DeleteWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) error {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionDeleteWorkspace); err != nil {
 		return err
 	}
-	if existingWorkspace == nil {
-		return errorsutil.New(404, "workspace not found")
-	}
 	return s.workspaceRepo.Delete(ctx, workspaceID)
 }
 
-// ListAllWorkspaces returns all workspaces with pagination
+// ArchiveWorkspace makes a workspace read-only, members keep viewing it
+func (s *workspaceService) ArchiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error) {
+	return s.setArchived(ctx, userID, workspaceID, true)
+}
+
+// UnarchiveWorkspace makes an archived workspace writable again
+func (s *workspaceService) UnarchiveWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error) {
+	return s.setArchived(ctx, userID, workspaceID, false)
+}
+
+func (s *workspaceService) setArchived(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, archived bool) (*entities.Workspace, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionArchiveWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	if archived == (workspace.ArchivedAt != nil) {
+		if archived {
+			return nil, errorsutil.New(409, "workspace is already archived")
+		}
+		return nil, errorsutil.New(409, "workspace is not archived")
+	}
+
+	if err := s.workspaceRepo.SetArchived(ctx, workspaceID, archived); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsutil.New(404, "workspace not found")
+		}
+		return nil, err
+	}
+
+	return s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+}
+
+// ListAllWorkspaces returns the owned and shared workspaces of a user with pagination
 func (s *workspaceService) ListAllWorkspaces(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.Workspace, error) {
 	return s.workspaceRepo.ListAll(ctx, userID, limit, offset)
 }
 
-// GetWorkspaceByID returns a workspace by ID
-func (s *workspaceService) GetWorkspaceByID(ctx context.Context, workspaceID uuid.UUID) (*entities.Workspace, error) {
-	workspace, err := s.workspaceRepo.FindByID(ctx, workspaceID)
+// GetWorkspaceByID returns a workspace by ID when the user is a member
+func (s *workspaceService) GetWorkspaceByID(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.Workspace, error) {
+	return s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+}
+
+// GetSettings returns the settings of a workspace
+func (s *workspaceService) GetSettings(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.WorkspaceSettings, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace.Settings, nil
+}
+
+// UpdateSettings merges a partial settings document into the settings of a workspace and validates the result
+func (s *workspaceService) UpdateSettings(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, patch []byte) (*entities.WorkspaceSettings, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := applyWorkspaceSettingsPatch(workspace.Settings, patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWorkspaceSettings(settings, workspace.WorkspaceType); err != nil {
+		return nil, err
+	}
+
+	// The default account is used by its owner, who must be a member
+	if settings.DefaultAccountID != nil {
+		account, err := s.accountRepo.FindByID(ctx, *settings.DefaultAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			return nil, errors.New("default account not found")
+		}
+		if err := s.requireMember(ctx, workspaceID, account.UserID, "default account must belong to a workspace member"); err != nil {
+			return nil, err
+		}
+	}
+	for _, approvalUserID := range settings.Approvals.RequiredForUserIDs {
+		if err := s.requireMember(ctx, workspaceID, approvalUserID, "approval users must be workspace members"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.workspaceRepo.UpdateSettings(ctx, workspaceID, settings); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorsutil.New(404, "workspace not found")
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// requireMember fails with message when the user is not a member of the workspace
+func (s *workspaceService) requireMember(ctx context.Context, workspaceID uuid.UUID, userID uuid.UUID, message string) error {
+	member, err := s.authorizer.GetMembership(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return errors.New(message)
+	}
+	return nil
+}
+
+// SwitchWorkspace makes a workspace of the user active and returns a new access token holding it
+func (s *workspaceService) SwitchWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.SwitchWorkspaceRequest) (*entities.SwitchWorkspaceResponse, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetActiveWorkspace(ctx, userID, workspaceID, input.MakeDefault); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorsutil.New(404, "user not found")
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	// The new access token stays in the session of the one it replaces
+	token, err := s.authMiddleware.GenerateToken(user, middleware.SessionIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.SwitchWorkspaceResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(middleware.AccessTokenTTL.Seconds()),
+		User:        user,
+		Workspace:   workspace,
+	}, nil
+}
+
+// GetMembers returns the members of a workspace
+func (s *workspaceService) GetMembers(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) ([]*entities.WorkspaceMember, error) {
+	if _, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView); err != nil {
+		return nil, err
+	}
+	return s.memberRepo.FindByWorkspace(ctx, workspaceID)
+}
+
+// AddMember adds a registered user to a workspace with a role
+func (s *workspaceService) AddMember(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.AddWorkspaceMemberRequest) (*entities.WorkspaceMember, error) {
+	if input.Email == "" && input.PhoneNumber == "" {
+		return nil, errors.New("email or phone number is required")
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAssignableRole(workspace.Role, input.Role); err != nil {
+		return nil, err
+	}
+
+	var user *entities.User
+	if input.Email != "" {
+		user, err = s.userRepo.FindByEmail(ctx, input.Email)
+	} else {
+		user, err = s.userRepo.FindByPhoneNumber(ctx, input.PhoneNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errorsutil.New(404, "user not found")
+	}
+
+	existingMember, err := s.memberRepo.FindByWorkspaceAndUser(ctx, workspaceID, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existingMember != nil {
+		return nil, errorsutil.New(409, "user is already a member of this workspace")
+	}
+
+	member, err := s.memberRepo.Create(ctx, &entities.WorkspaceMember{
+		WorkspaceMemberID: uuid.New(),
+		WorkspaceID:       workspaceID,
+		UserID:            user.UserID,
+		Role:              input.Role,
+		InvitedBy:         &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Record(ctx, memberJoinedEvent(&member, userID, strings.TrimSpace(user.FirstName+" "+user.LastName)))
+
+	return &member, nil
+}
+
+// UpdateMemberRole changes the role of a member, the owner keeps its role
+func (s *workspaceService) UpdateMemberRole(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, memberUserID uuid.UUID, input *entities.UpdateWorkspaceMemberRequest) (*entities.WorkspaceMember, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers)
 	if err != nil {
 		return nil, err
 	}
-	if workspace == nil {
-		return nil, errorsutil.New(404, "workspace not found")
+	if err := validateAssignableRole(workspace.Role, input.Role); err != nil {
+		return nil, err
+	}
+
+	member, err := s.findManageableMember(ctx, workspace, userID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.memberRepo.UpdateRole(ctx, workspaceID, memberUserID, input.Role); err != nil {
+		return nil, err
+	}
+	member.Role = input.Role
+
+	return member, nil
+}
+
+// RemoveMember removes a member from a workspace. Any member except the owner may leave on their own.
+func (s *workspaceService) RemoveMember(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, memberUserID uuid.UUID) error {
+	if memberUserID == userID {
+		member, err := s.authorizer.GetMembership(ctx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if member == nil {
+			return errorsutil.New(404, "workspace member not found")
+		}
+		if member.Role == entities.WorkspaceRoleOwner {
+			return errorsutil.New(400, "the workspace owner cannot leave the workspace")
+		}
+		return s.removeMember(ctx, workspaceID, userID)
+	}
+
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionManageMembers)
+	if err != nil {
+		return err
+	}
+	if _, err := s.findManageableMember(ctx, workspace, userID, memberUserID); err != nil {
+		return err
+	}
+
+	return s.removeMember(ctx, workspaceID, memberUserID)
+}
+
+// removeMember removes a member and moves the member's default and active workspace away from the workspace
+func (s *workspaceService) removeMember(ctx context.Context, workspaceID uuid.UUID, memberUserID uuid.UUID) error {
+	if err := s.memberRepo.Remove(ctx, workspaceID, memberUserID); err != nil {
+		return err
+	}
+	return s.userRepo.ClearWorkspacePreferences(ctx, memberUserID, workspaceID)
+}
+
+// findManageableMember returns a member the user may change: never the owner, and admins only manage lower roles
+func (s *workspaceService) findManageableMember(ctx context.Context, workspace *entities.Workspace, userID uuid.UUID, memberUserID uuid.UUID) (*entities.WorkspaceMember, error) {
+	member, err := s.memberRepo.FindByWorkspaceAndUser(ctx, workspace.WorkspaceID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errorsutil.New(404, "workspace member not found")
+	}
+	if member.Role == entities.WorkspaceRoleOwner {
+		return nil, errorsutil.New(403, "the workspace owner cannot be changed")
+	}
+	if memberUserID == userID || member.Role <= workspace.Role {
+		return nil, errorsutil.New(403, "access denied to workspace member")
+	}
+	return member, nil
+}
+
+// validateAssignableRole checks that a role exists, is not owner, and is below the role of the member assigning it
+func validateAssignableRole(assignerRole int, role int) error {
+	if !isValidWorkspaceRole(role) || role == entities.WorkspaceRoleOwner {
+		return errors.New("invalid role")
+	}
+	if role <= assignerRole {
+		return errorsutil.New(403, "access denied to workspace member")
 	}
-	return workspace, nil
+	return nil
 }
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+// applyWorkspaceSettingsPatch merges a partial settings document into the settings.
+// Fields left out keep their value, unknown fields are rejected.
+func applyWorkspaceSettingsPatch(settings entities.WorkspaceSettings, patch []byte) (entities.WorkspaceSettings, error) {
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return settings, errors.New("invalid settings")
+	}
+
+	settings.Version = entities.WorkspaceSettingsVersion
+	if settings.Approvals.RequiredForUserIDs == nil {
+		settings.Approvals.RequiredForUserIDs = []uuid.UUID{}
+	}
+
+	return settings, nil
+}
+
+// validateWorkspaceSettings checks the settings against the schema of the workspace type
+func validateWorkspaceSettings(settings entities.WorkspaceSettings, workspaceType int) error {
+	if settings.WeekStartDay < 0 || settings.WeekStartDay > 6 {
+		return errors.New("week start day must be between 0 and 6")
+	}
+	if settings.FiscalMonthStartDay < 1 || settings.FiscalMonthStartDay > 28 {
+		return errors.New("fiscal month start day must be between 1 and 28")
+	}
+	if !validPaymentMethod(settings.DefaultPaymentMethod) {
+		return errors.New("invalid default payment method")
+	}
+	if settings.AutoCategorizeConfidence < 0 || settings.AutoCategorizeConfidence > 1 {
+		return errors.New("auto categorize confidence must be between 0 and 1")
+	}
+	if settings.Approvals.AmountThreshold < 0 {
+		return errors.New("approval amount threshold cannot be negative")
+	}
+	if workspaceType != entities.WorkspaceTypeBusiness && (settings.Approvals.Enabled || len(settings.Approvals.RequiredForUserIDs) > 0) {
+		return errors.New("approvals are only available for business workspaces")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func TestMigrateWorkspaceSettings(t *testing.T) {
+	t.Run("given unversioned free-form settings, when they are migrated, then they take the defaults of the workspace type", func(t *testing.T) {
+		settings, err := entities.MigrateWorkspaceSettings([]byte(`{"theme":"dark"}`), entities.WorkspaceTypeTravel)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.WorkspaceSettingsVersion, settings.Version)
+		assert.Equal(t, entities.DefaultWeekStartDay, settings.WeekStartDay)
+		assert.False(t, settings.Notifications.SpendingAnomalies)
+	})
+
+	t.Run("given unversioned settings with a schema field, when they are migrated, then the field is kept", func(t *testing.T) {
+		settings, err := entities.MigrateWorkspaceSettings([]byte(`{"fiscal_month_start_day":25}`), entities.WorkspaceTypePersonal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25, settings.FiscalMonthStartDay)
+		assert.Equal(t, 0.8, settings.AutoCategorizeConfidence)
+	})
+
+	t.Run("given settings from a newer version, when they are migrated, then it fails", func(t *testing.T) {
+		_, err := entities.MigrateWorkspaceSettings([]byte(`{"version":99}`), entities.WorkspaceTypePersonal)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestApplyWorkspaceSettingsPatch(t *testing.T) {
+	current := entities.DefaultWorkspaceSettings(entities.WorkspaceTypeBusiness)
+
+	t.Run("given a partial document, when it is applied, then only the given fields change", func(t *testing.T) {
+		settings, err := applyWorkspaceSettingsPatch(current, []byte(`{"week_start_day":0,"approvals":{"enabled":true}}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, settings.WeekStartDay)
+		assert.True(t, settings.Approvals.Enabled)
+		assert.Equal(t, current.FiscalMonthStartDay, settings.FiscalMonthStartDay)
+		assert.Equal(t, current.AutoCategorizeConfidence, settings.AutoCategorizeConfidence)
+		assert.True(t, settings.Notifications.SpendingAnomalies)
+	})
+
+	t.Run("given a null default account, when it is applied, then the default account is cleared", func(t *testing.T) {
+		accountID := uuid.New()
+		withAccount := current
+		withAccount.DefaultAccountID = &accountID
+
+		settings, err := applyWorkspaceSettingsPatch(withAccount, []byte(`{"default_account_id":null}`))
+
+		assert.NoError(t, err)
+		assert.Nil(t, settings.DefaultAccountID)
+	})
+
+	t.Run("given an unknown field, when it is applied, then it is rejected", func(t *testing.T) {
+		_, err := applyWorkspaceSettingsPatch(current, []byte(`{"week_starts_on":"sunday"}`))
+
+		assert.EqualError(t, err, "invalid settings")
+	})
+
+	t.Run("given another version, when it is applied, then the version stays current", func(t *testing.T) {
+		settings, err := applyWorkspaceSettingsPatch(current, []byte(`{"version":0}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.WorkspaceSettingsVersion, settings.Version)
+	})
+}
+
+func TestValidateWorkspaceSettings(t *testing.T) {
+	t.Run("given the defaults of every workspace type, when they are validated, then they pass", func(t *testing.T) {
+		for workspaceType := entities.WorkspaceTypePersonal; workspaceType <= entities.WorkspaceTypeShared; workspaceType++ {
+			assert.NoError(t, validateWorkspaceSettings(entities.DefaultWorkspaceSettings(workspaceType), workspaceType))
+		}
+	})
+
+	t.Run("given a fiscal month starting on the 31st, when it is validated, then it fails", func(t *testing.T) {
+		settings := entities.DefaultWorkspaceSettings(entities.WorkspaceTypePersonal)
+		settings.FiscalMonthStartDay = 31
+
+		assert.EqualError(t, validateWorkspaceSettings(settings, entities.WorkspaceTypePersonal), "fiscal month start day must be between 1 and 28")
+	})
+
+	t.Run("given a confidence above 1, when it is validated, then it fails", func(t *testing.T) {
+		settings := entities.DefaultWorkspaceSettings(entities.WorkspaceTypePersonal)
+		settings.AutoCategorizeConfidence = 80
+
+		assert.EqualError(t, validateWorkspaceSettings(settings, entities.WorkspaceTypePersonal), "auto categorize confidence must be between 0 and 1")
+	})
+
+	t.Run("given approvals in a personal workspace, when they are validated, then they fail", func(t *testing.T) {
+		settings := entities.DefaultWorkspaceSettings(entities.WorkspaceTypePersonal)
+		settings.Approvals.Enabled = true
+
+		assert.EqualError(t, validateWorkspaceSettings(settings, entities.WorkspaceTypePersonal), "approvals are only available for business workspaces")
+		assert.NoError(t, validateWorkspaceSettings(settings, entities.WorkspaceTypeBusiness))
+	})
+}
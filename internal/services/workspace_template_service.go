@@ -0,0 +1,445 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	errorsutil "github.com/vasst-id/vasst-expense-api/internal/utils/errors"
+)
+
+//go:generate mockgen -source=workspace_template_service.go -package=mock -destination=mock/workspace_template_service_mock.go
+type (
+	WorkspaceTemplateService interface {
+		ExportTemplate(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.WorkspaceTemplate, error)
+		ImportTemplate(ctx context.Context, userID uuid.UUID, input *entities.ImportWorkspaceTemplateRequest) (*entities.Workspace, error)
+		CloneWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.CloneWorkspaceRequest) (*entities.Workspace, error)
+	}
+
+	workspaceTemplateService struct {
+		workspaceRepo repositories.WorkspaceRepository
+		templateRepo  repositories.WorkspaceTemplateRepository
+		categoryRepo  repositories.CategoryRepository
+		userTagsRepo  repositories.UserTagsRepository
+		authorizer    WorkspaceAuthorizer
+	}
+)
+
+// NewWorkspaceTemplateService creates a new workspace template service
+func NewWorkspaceTemplateService(
+	workspaceRepo repositories.WorkspaceRepository,
+	templateRepo repositories.WorkspaceTemplateRepository,
+	categoryRepo repositories.CategoryRepository,
+	userTagsRepo repositories.UserTagsRepository,
+	authorizer WorkspaceAuthorizer,
+) WorkspaceTemplateService {
+	return &workspaceTemplateService{
+		workspaceRepo: workspaceRepo,
+		templateRepo:  templateRepo,
+		categoryRepo:  categoryRepo,
+		userTagsRepo:  userTagsRepo,
+		authorizer:    authorizer,
+	}
+}
+
+// ExportTemplate exports the setup of a workspace as a template, transactions are left out
+func (s *workspaceTemplateService) ExportTemplate(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID) (*entities.WorkspaceTemplate, error) {
+	workspace, err := s.authorizer.Authorize(ctx, workspaceID, userID, entities.WorkspacePermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.templateRepo.FindCategories(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.templateRepo.FindTags(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := s.templateRepo.FindBudgets(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := buildWorkspaceTemplate(workspace, categories, tags, budgets, time.Now())
+	return &template, nil
+}
+
+// ImportTemplate creates a workspace owned by the user from a template. Categories and tags are matched
+// by name against those of the user, the missing ones are created.
+func (s *workspaceTemplateService) ImportTemplate(ctx context.Context, userID uuid.UUID, input *entities.ImportWorkspaceTemplateRequest) (*entities.Workspace, error) {
+	template := input.Template
+	if name := strings.TrimSpace(input.Name); name != "" {
+		template.Name = name
+	}
+	return s.createFromTemplate(ctx, userID, template, input.StartDate)
+}
+
+// CloneWorkspace creates a workspace owned by the user with the categories, budgets, tags and settings
+// of another workspace, without its transactions. Budgets are re-dated from the start date.
+func (s *workspaceTemplateService) CloneWorkspace(ctx context.Context, userID uuid.UUID, workspaceID uuid.UUID, input *entities.CloneWorkspaceRequest) (*entities.Workspace, error) {
+	template, err := s.ExportTemplate(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = strings.TrimSpace(input.Name)
+	if input.Description != nil {
+		template.Description = *input.Description
+	}
+	return s.createFromTemplate(ctx, userID, *template, input.StartDate)
+}
+
+func (s *workspaceTemplateService) createFromTemplate(ctx context.Context, userID uuid.UUID, template entities.WorkspaceTemplate, startDate *time.Time) (*entities.Workspace, error) {
+	// Templates written by hand may leave the settings out
+	if template.Settings.Version == 0 {
+		template.Settings = entities.DefaultWorkspaceSettings(template.WorkspaceType)
+	}
+	if err := validateWorkspaceTemplate(template); err != nil {
+		return nil, err
+	}
+
+	existingWorkspace, err := s.workspaceRepo.FindByName(ctx, template.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existingWorkspace != nil {
+		return nil, errorsutil.New(409, "workspace with this name already exists")
+	}
+
+	categories, err := s.categoryRepo.FindActiveUserCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.userTagsRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := templateStartDate(startDate, time.Now())
+	plan, err := planWorkspaceTemplateImport(template, userID, categories, tags, start)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.CreateFromTemplate(ctx, plan.workspace, plan.categories, plan.tags, plan.budgets); err != nil {
+		return nil, err
+	}
+
+	return s.authorizer.Authorize(ctx, plan.workspace.WorkspaceID, userID, entities.WorkspacePermissionView)
+}
+
+// workspaceTemplateImport is what importing a template creates
+type workspaceTemplateImport struct {
+	workspace  *entities.Workspace
+	categories []*entities.UserCategory // missing categories of the user
+	tags       []*entities.UserTag      // missing tags of the user
+	budgets    []*entities.Budget
+}
+
+// templateNameKey normalizes a category or tag name, names are matched case-insensitively
+func templateNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// budgetPeriodByMonth reports whether the period of a budget follows the calendar months
+func budgetPeriodByMonth(periodType int) bool {
+	return periodType == entities.PeriodTypeMonthly || periodType == entities.PeriodTypeYearly
+}
+
+// calendarOffset returns how far to is from from, in months and days when byMonth is set so
+// month-long periods keep their length, in days only otherwise
+func calendarOffset(from, to time.Time, byMonth bool) (months, days int) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if byMonth {
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()), to.Day() - from.Day()
+	}
+	return 0, int(to.Sub(from).Hours() / 24)
+}
+
+// templateStartDate returns the date a template starts on, today when no start date is given
+func templateStartDate(startDate *time.Time, now time.Time) time.Time {
+	if startDate != nil && !startDate.IsZero() {
+		now = *startDate
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// buildWorkspaceTemplate turns the setup of a workspace into a template. Budget periods become offsets
+// from the earliest budget start. Budgets scoped by accounts only are left out, accounts are not part of a template.
+func buildWorkspaceTemplate(workspace *entities.Workspace, categories []*entities.UserCategory, tags []*entities.UserTag, budgets []*entities.Budget, now time.Time) entities.WorkspaceTemplate {
+	settings := workspace.Settings
+	settings.DefaultAccountID = nil
+	settings.Approvals.RequiredForUserIDs = []uuid.UUID{}
+
+	template := entities.WorkspaceTemplate{
+		Version:       entities.WorkspaceTemplateVersion,
+		Name:          workspace.Name,
+		Description:   workspace.Description,
+		WorkspaceType: workspace.WorkspaceType,
+		Icon:          workspace.Icon,
+		ColorCode:     workspace.ColorCode,
+		CurrencyID:    workspace.CurrencyID,
+		Timezone:      workspace.Timezone,
+		BudgetingMode: workspace.BudgetingMode,
+		Settings:      settings,
+		Categories:    []entities.WorkspaceTemplateCategory{},
+		Tags:          []string{},
+		Budgets:       []entities.WorkspaceTemplateBudget{},
+		ExportedAt:    now,
+	}
+
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.UserCategoryID] = category.Name
+		template.Categories = append(template.Categories, entities.WorkspaceTemplateCategory{
+			Name:        category.Name,
+			CategoryID:  category.CategoryID,
+			Description: category.Description,
+			Icon:        category.Icon,
+		})
+	}
+	tagNames := make(map[uuid.UUID]string, len(tags))
+	for _, tag := range tags {
+		tagNames[tag.UserTagID] = tag.Name
+		template.Tags = append(template.Tags, tag.Name)
+	}
+
+	var reference time.Time
+	for _, budget := range budgets {
+		if reference.IsZero() || budget.PeriodStart.Before(reference) {
+			reference = budget.PeriodStart
+		}
+	}
+
+	for _, budget := range budgets {
+		scope := entities.WorkspaceTemplateBudgetScope{
+			Merchants:  budget.Scope.Merchants,
+			TotalSpend: budget.Scope.TotalSpend,
+		}
+		for _, categoryID := range budget.Scope.CategoryIDs {
+			if name, ok := categoryNames[categoryID]; ok {
+				scope.CategoryNames = append(scope.CategoryNames, name)
+			}
+		}
+		for _, tagID := range budget.Scope.TagIDs {
+			if name, ok := tagNames[tagID]; ok {
+				scope.TagNames = append(scope.TagNames, name)
+			}
+		}
+		if !scope.TotalSpend && len(scope.CategoryNames) == 0 && len(scope.TagNames) == 0 && len(scope.Merchants) == 0 {
+			continue
+		}
+
+		byMonth := budgetPeriodByMonth(budget.PeriodType)
+		startMonths, startDays := calendarOffset(reference, budget.PeriodStart, byMonth)
+		lengthMonths, lengthDays := calendarOffset(budget.PeriodStart, budget.PeriodEnd.AddDate(0, 0, 1), byMonth)
+
+		template.Budgets = append(template.Budgets, entities.WorkspaceTemplateBudget{
+			Name:              budget.Name,
+			CategoryName:      categoryNames[budget.UserCategoryID],
+			BudgetedAmount:    budget.BudgetedAmount,
+			PeriodType:        budget.PeriodType,
+			StartOffsetMonths: startMonths,
+			StartOffsetDays:   startDays,
+			LengthMonths:      lengthMonths,
+			LengthDays:        lengthDays,
+			Scope:             scope,
+		})
+	}
+
+	return template
+}
+
+// redateTemplateBudget returns the period of a template budget when the template starts on start
+func redateTemplateBudget(budget entities.WorkspaceTemplateBudget, start time.Time) (time.Time, time.Time) {
+	periodStart := start.AddDate(0, budget.StartOffsetMonths, budget.StartOffsetDays)
+	periodEnd := periodStart.AddDate(0, budget.LengthMonths, budget.LengthDays-1)
+	return periodStart, periodEnd
+}
+
+// validateWorkspaceTemplate checks a template before anything is created from it
+func validateWorkspaceTemplate(template entities.WorkspaceTemplate) error {
+	if template.Version < 1 || template.Version > entities.WorkspaceTemplateVersion {
+		return errors.New("unsupported template version")
+	}
+	if strings.TrimSpace(template.Name) == "" {
+		return errors.New("workspace name is required")
+	}
+	if template.WorkspaceType == 0 {
+		return errors.New("workspace type is required")
+	}
+	if template.CurrencyID == 0 {
+		return errors.New("currency ID is required")
+	}
+	if template.BudgetingMode != 0 && template.BudgetingMode != entities.BudgetingModeStandard && template.BudgetingMode != entities.BudgetingModeEnvelope {
+		return errors.New("invalid budgeting mode")
+	}
+	return validateWorkspaceSettings(template.Settings, template.WorkspaceType)
+}
+
+// planWorkspaceTemplateImport maps the categories and tags of a template by name onto those of the user,
+// adding the missing ones, and re-dates the budgets from the start date
+func planWorkspaceTemplateImport(template entities.WorkspaceTemplate, userID uuid.UUID, userCategories []*entities.UserCategory, userTags []*entities.UserTag, start time.Time) (*workspaceTemplateImport, error) {
+	settings := template.Settings
+	settings.Version = entities.WorkspaceSettingsVersion
+	settings.DefaultAccountID = nil
+	settings.Approvals.RequiredForUserIDs = []uuid.UUID{}
+
+	timezone := template.Timezone
+	if timezone == "" {
+		timezone = "Asia/Jakarta"
+	}
+	budgetingMode := template.BudgetingMode
+	if budgetingMode == 0 {
+		budgetingMode = entities.BudgetingModeStandard
+	}
+
+	plan := &workspaceTemplateImport{
+		workspace: &entities.Workspace{
+			WorkspaceID:   uuid.New(),
+			Name:          strings.TrimSpace(template.Name),
+			Description:   template.Description,
+			WorkspaceType: template.WorkspaceType,
+			Icon:          template.Icon,
+			ColorCode:     template.ColorCode,
+			CurrencyID:    template.CurrencyID,
+			Timezone:      timezone,
+			Settings:      settings,
+			BudgetingMode: budgetingMode,
+			IsActive:      true,
+			CreatedBy:     userID,
+		},
+	}
+
+	categoryIDs := make(map[string]uuid.UUID, len(userCategories))
+	for _, category := range userCategories {
+		categoryIDs[templateNameKey(category.Name)] = category.UserCategoryID
+	}
+	for _, category := range template.Categories {
+		key := templateNameKey(category.Name)
+		if key == "" {
+			return nil, errors.New("invalid template category")
+		}
+		if _, ok := categoryIDs[key]; ok {
+			continue
+		}
+		if category.CategoryID == uuid.Nil {
+			return nil, errors.New("invalid template category")
+		}
+		created := &entities.UserCategory{
+			UserCategoryID: uuid.New(),
+			UserID:         userID,
+			CategoryID:     category.CategoryID,
+			Name:           strings.TrimSpace(category.Name),
+			Description:    category.Description,
+			Icon:           category.Icon,
+			IsCustom:       true,
+			IsActive:       true,
+		}
+		categoryIDs[key] = created.UserCategoryID
+		plan.categories = append(plan.categories, created)
+	}
+
+	tagIDs := make(map[string]uuid.UUID, len(userTags))
+	for _, tag := range userTags {
+		tagIDs[templateNameKey(tag.Name)] = tag.UserTagID
+	}
+	addTag := func(name string) uuid.UUID {
+		key := templateNameKey(name)
+		if id, ok := tagIDs[key]; ok {
+			return id
+		}
+		created := &entities.UserTag{
+			UserTagID: uuid.New(),
+			UserID:    userID,
+			Name:      strings.TrimSpace(name),
+			IsActive:  true,
+		}
+		tagIDs[key] = created.UserTagID
+		plan.tags = append(plan.tags, created)
+		return created.UserTagID
+	}
+	for _, name := range template.Tags {
+		if templateNameKey(name) != "" {
+			addTag(name)
+		}
+	}
+
+	findCategory := func(name string) (uuid.UUID, error) {
+		id, ok := categoryIDs[templateNameKey(name)]
+		if !ok {
+			return uuid.Nil, errors.New("template category not found")
+		}
+		return id, nil
+	}
+
+	for _, templateBudget := range template.Budgets {
+		if templateBudget.Name == "" {
+			return nil, errors.New("budget name is required")
+		}
+		if templateBudget.BudgetedAmount <= 0 {
+			return nil, errors.New("budgeted amount must be greater than 0")
+		}
+		if templateBudget.PeriodType < entities.PeriodTypeWeekly || templateBudget.PeriodType > entities.PeriodTypeOneTime {
+			return nil, errors.New("invalid period type")
+		}
+
+		userCategoryID := uuid.Nil
+		if templateBudget.CategoryName != "" {
+			id, err := findCategory(templateBudget.CategoryName)
+			if err != nil {
+				return nil, err
+			}
+			userCategoryID = id
+		}
+
+		scope := entities.BudgetScope{
+			Merchants:  templateBudget.Scope.Merchants,
+			TotalSpend: templateBudget.Scope.TotalSpend,
+		}
+		for _, name := range templateBudget.Scope.CategoryNames {
+			id, err := findCategory(name)
+			if err != nil {
+				return nil, err
+			}
+			scope.CategoryIDs = append(scope.CategoryIDs, id)
+		}
+		for _, name := range templateBudget.Scope.TagNames {
+			if templateNameKey(name) != "" {
+				scope.TagIDs = append(scope.TagIDs, addTag(name))
+			}
+		}
+		scope, err := normalizeBudgetScope(userCategoryID, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		periodStart, periodEnd := redateTemplateBudget(templateBudget, start)
+		if periodEnd.Before(periodStart) {
+			return nil, errors.New("period end must be after period start")
+		}
+
+		plan.budgets = append(plan.budgets, &entities.Budget{
+			BudgetID:       uuid.New(),
+			WorkspaceID:    plan.workspace.WorkspaceID,
+			UserCategoryID: userCategoryID,
+			Name:           templateBudget.Name,
+			BudgetedAmount: templateBudget.BudgetedAmount,
+			PeriodType:     templateBudget.PeriodType,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+			Scope:          scope,
+			IsActive:       true,
+			CreatedBy:      userID,
+		})
+	}
+
+	return plan, nil
+}
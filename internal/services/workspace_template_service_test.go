@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vasst-id/vasst-expense-api/internal/entities"
+)
+
+func templateDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRedateTemplateBudget(t *testing.T) {
+	t.Run("given a monthly budget one month after the template start, when re-dated, then it covers the matching calendar month", func(t *testing.T) {
+		months, days := calendarOffset(templateDate(2025, 1, 15), templateDate(2025, 2, 1), true)
+		lengthMonths, lengthDays := calendarOffset(templateDate(2025, 2, 1), templateDate(2025, 3, 1), true)
+		budget := entities.WorkspaceTemplateBudget{StartOffsetMonths: months, StartOffsetDays: days, LengthMonths: lengthMonths, LengthDays: lengthDays}
+
+		start, end := redateTemplateBudget(budget, templateDate(2025, 3, 15))
+
+		assert.Equal(t, templateDate(2025, 4, 1), start)
+		assert.Equal(t, templateDate(2025, 4, 30), end)
+	})
+
+	t.Run("given a one time budget, when re-dated, then it keeps its offset and length in days", func(t *testing.T) {
+		months, days := calendarOffset(templateDate(2025, 6, 1), templateDate(2025, 6, 4), false)
+		lengthMonths, lengthDays := calendarOffset(templateDate(2025, 6, 4), templateDate(2025, 6, 11), false)
+		budget := entities.WorkspaceTemplateBudget{StartOffsetMonths: months, StartOffsetDays: days, LengthMonths: lengthMonths, LengthDays: lengthDays}
+
+		start, end := redateTemplateBudget(budget, templateDate(2026, 2, 27))
+
+		assert.Equal(t, templateDate(2026, 3, 2), start)
+		assert.Equal(t, templateDate(2026, 3, 8), end)
+	})
+}
+
+func TestBuildWorkspaceTemplate(t *testing.T) {
+	foodID, hotelID, tagID, accountID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	workspace := &entities.Workspace{
+		Name:          "Bali 2025",
+		WorkspaceType: entities.WorkspaceTypeTravel,
+		CurrencyID:    1,
+		BudgetingMode: entities.BudgetingModeStandard,
+		Settings:      entities.DefaultWorkspaceSettings(entities.WorkspaceTypeTravel),
+	}
+	workspace.Settings.DefaultAccountID = &accountID
+	categories := []*entities.UserCategory{
+		{UserCategoryID: foodID, CategoryID: uuid.New(), Name: "Makan"},
+		{UserCategoryID: hotelID, CategoryID: uuid.New(), Name: "Hotel"},
+	}
+	tags := []*entities.UserTag{{UserTagID: tagID, Name: "Honeymoon"}}
+	budgets := []*entities.Budget{
+		{
+			Name: "Food", UserCategoryID: foodID, BudgetedAmount: 3000000, PeriodType: entities.PeriodTypeOneTime,
+			PeriodStart: templateDate(2025, 7, 1), PeriodEnd: templateDate(2025, 7, 7),
+			Scope: entities.BudgetScope{CategoryIDs: []uuid.UUID{foodID}, TagIDs: []uuid.UUID{tagID}},
+		},
+		{
+			Name: "Card", BudgetedAmount: 1000000, PeriodType: entities.PeriodTypeOneTime,
+			PeriodStart: templateDate(2025, 7, 1), PeriodEnd: templateDate(2025, 7, 7),
+			Scope: entities.BudgetScope{AccountIDs: []uuid.UUID{accountID}},
+		},
+	}
+
+	template := buildWorkspaceTemplate(workspace, categories, tags, budgets, templateDate(2025, 8, 1))
+
+	t.Run("given a workspace, when exported, then categories and tags are referenced by name", func(t *testing.T) {
+		assert.Len(t, template.Categories, 2)
+		assert.Equal(t, []string{"Honeymoon"}, template.Tags)
+		assert.Equal(t, "Makan", template.Budgets[0].CategoryName)
+		assert.Equal(t, []string{"Makan"}, template.Budgets[0].Scope.CategoryNames)
+		assert.Equal(t, []string{"Honeymoon"}, template.Budgets[0].Scope.TagNames)
+	})
+
+	t.Run("given a budget scoped by accounts only, when exported, then it is left out with the default account", func(t *testing.T) {
+		assert.Len(t, template.Budgets, 1)
+		assert.Nil(t, template.Settings.DefaultAccountID)
+	})
+
+	t.Run("given an exported template, when imported by a user with a category of another case, then it maps by name", func(t *testing.T) {
+		userID, makanID := uuid.New(), uuid.New()
+		userCategories := []*entities.UserCategory{{UserCategoryID: makanID, Name: "makan"}}
+
+		plan, err := planWorkspaceTemplateImport(template, userID, userCategories, nil, templateDate(2026, 7, 10))
+
+		assert.NoError(t, err)
+		assert.Len(t, plan.categories, 1)
+		assert.Equal(t, "Hotel", plan.categories[0].Name)
+		assert.Len(t, plan.tags, 1)
+		assert.Equal(t, makanID, plan.budgets[0].UserCategoryID)
+		assert.Equal(t, []uuid.UUID{makanID}, plan.budgets[0].Scope.CategoryIDs)
+		assert.Equal(t, []uuid.UUID{plan.tags[0].UserTagID}, plan.budgets[0].Scope.TagIDs)
+		assert.Equal(t, templateDate(2026, 7, 10), plan.budgets[0].PeriodStart)
+		assert.Equal(t, templateDate(2026, 7, 16), plan.budgets[0].PeriodEnd)
+		assert.Equal(t, userID, plan.workspace.CreatedBy)
+	})
+
+	t.Run("given a budget on a category missing from the template, when imported, then it fails", func(t *testing.T) {
+		broken := template
+		broken.Categories = nil
+
+		_, err := planWorkspaceTemplateImport(broken, uuid.New(), nil, nil, templateDate(2026, 7, 10))
+
+		assert.EqualError(t, err, "template category not found")
+	})
+}
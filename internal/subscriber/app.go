@@ -10,7 +10,10 @@ import (
 
 	"github.com/getsentry/sentry-go"
 	"github.com/vasst-id/vasst-expense-api/config"
+	"github.com/vasst-id/vasst-expense-api/internal/jobs"
 	"github.com/vasst-id/vasst-expense-api/internal/pubsub"
+	"github.com/vasst-id/vasst-expense-api/internal/repositories"
+	"github.com/vasst-id/vasst-expense-api/internal/services"
 	"github.com/vasst-id/vasst-expense-api/internal/utils"
 	logs "github.com/vasst-id/vasst-expense-api/internal/utils/logger"
 	"github.com/vasst-id/vasst-expense-api/internal/utils/postgres"
@@ -114,6 +117,17 @@ func Run(config *config.Config) {
 	// messageEventHandler := handlers.NewMessageEventHandler(pubsubClient, messageService, userService, whatsAppMediaService, whatsAppService, storageService, organizationService, config, logger)
 	// aiEventHandler := handlers.NewAIEventHandler(pubsubClient, geminiService, messageService, whatsAppService)
 
+	// Initialize scheduled jobs
+	netWorthService := services.NewNetWorthService(repositories.NewAccountBalanceSnapshotRepository(pg), repositories.NewUserRepository(pg), repositories.NewCurrencyRepository(pg))
+	netWorthSnapshotJob := jobs.NewNetWorthSnapshotJob(netWorthService, logger)
+
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(pg))
+	spendingAnomalyService := services.NewSpendingAnomalyService(repositories.NewSpendingAnomalyRepository(pg), repositories.NewWorkspaceRepository(pg), notificationService)
+	spendingAnomalyJob := jobs.NewSpendingAnomalyJob(spendingAnomalyService, logger)
+
+	transactionRollupService := services.NewTransactionRollupService(repositories.NewTransactionRollupRepository(pg))
+	transactionRollupJob := jobs.NewTransactionRollupJob(transactionRollupService, logger)
+
 	// // Initialize workers
 	// messageWorker := workers.NewMessageWorker(pubsubClient, messageEventHandler)
 	// aiWorker := workers.NewAIWorker(pubsubClient, aiEventHandler)
@@ -121,6 +135,18 @@ func Run(config *config.Config) {
 	// Start workers
 	logger.Info().Msg("Starting event-driven workers...")
 
+	if err := netWorthSnapshotJob.Start(); err != nil {
+		log.Fatalf("error starting net worth snapshot job: %s", err.Error())
+	}
+
+	if err := spendingAnomalyJob.Start(); err != nil {
+		log.Fatalf("error starting spending anomaly job: %s", err.Error())
+	}
+
+	if err := transactionRollupJob.Start(); err != nil {
+		log.Fatalf("error starting transaction rollup job: %s", err.Error())
+	}
+
 	// if err := messageWorker.Start(); err != nil {
 	// 	log.Fatalf("error starting message worker: %s", err.Error())
 	// }
@@ -146,6 +172,21 @@ func Run(config *config.Config) {
 	var wg sync.WaitGroup
 	wg.Add(3)
 
+	go func() {
+		defer wg.Done()
+		netWorthSnapshotJob.Stop()
+	}()
+
+	go func() {
+		defer wg.Done()
+		spendingAnomalyJob.Stop()
+	}()
+
+	go func() {
+		defer wg.Done()
+		transactionRollupJob.Stop()
+	}()
+
 	// go func() {
 	// 	defer wg.Done()
 	// 	messageWorker.Stop()
@@ -0,0 +1,215 @@
+// Package pdf writes simple text and table documents as PDF without external dependencies.
+// Documents are A4 portrait, use the standard Helvetica fonts and break pages automatically.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	marginLeft   = 50.0
+	marginRight  = 50.0
+	marginTop    = 60.0
+	marginBottom = 60.0
+
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// Align is the horizontal alignment of a table cell
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+)
+
+// Column describes a table column. Width is a fraction of the printable width.
+type Column struct {
+	Width float64
+	Align Align
+}
+
+// Document is a PDF document under construction
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// New creates an empty document with one page
+func New() *Document {
+	d := &Document{}
+	d.addPage()
+	return d
+}
+
+// Title writes a large bold line
+func (d *Document) Title(text string) {
+	d.line(text, 18, fontBold, marginLeft, 26)
+}
+
+// Heading writes a bold section heading with some space above it
+func (d *Document) Heading(text string) {
+	d.Space(8)
+	d.line(text, 12, fontBold, marginLeft, 18)
+}
+
+// Text writes a line of regular text
+func (d *Document) Text(text string) {
+	d.line(text, 10, fontRegular, marginLeft, 14)
+}
+
+// Space adds vertical space
+func (d *Document) Space(points float64) {
+	d.y -= points
+}
+
+// Row writes one table row. Bold rows are used for headers and totals.
+func (d *Document) Row(columns []Column, values []string, bold bool) {
+	font := fontRegular
+	if bold {
+		font = fontBold
+	}
+	size := 10.0
+
+	d.ensureSpace(14)
+	printable := pageWidth - marginLeft - marginRight
+	x := marginLeft
+	for i, column := range columns {
+		if i >= len(values) {
+			break
+		}
+		width := column.Width * printable
+		text := truncate(values[i], width-4, size)
+		textX := x
+		if column.Align == AlignRight {
+			textX = x + width - textWidth(text, size)
+		}
+		d.write(text, size, font, textX)
+		x += width
+	}
+	d.y -= 14
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// 1: catalog, 2: pages, 3-4: fonts, then a page and a content stream per page
+	pageCount := len(d.pages)
+	kids := make([]string, pageCount)
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - marginTop
+}
+
+func (d *Document) ensureSpace(height float64) {
+	if d.y-height < marginBottom {
+		d.addPage()
+	}
+}
+
+func (d *Document) line(text string, size float64, font string, x, height float64) {
+	d.ensureSpace(height)
+	d.write(text, size, font, x)
+	d.y -= height
+}
+
+func (d *Document) write(text string, size float64, font string, x float64) {
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.y, escape(text))
+}
+
+// escape encodes text as a PDF literal string. Characters outside Latin-1 are replaced.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth approximates the width of text in Helvetica. Digits and common
+// punctuation use their exact widths so right aligned amounts line up.
+func textWidth(text string, size float64) float64 {
+	units := 0
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == '.' || r == ',' || r == ' ':
+			units += 278
+		case r == '-':
+			units += 333
+		case r == '%':
+			units += 889
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// truncate shortens text to fit in width, marking the cut with dots
+func truncate(text string, width, size float64) string {
+	if textWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument(t *testing.T) {
+
+	t.Run("given a short document, when Bytes(), then a single page PDF is rendered", func(t *testing.T) {
+		doc := New()
+		doc.Title("Cash Flow Statement")
+		doc.Text("Period 2025-01-01 - 2025-01-31")
+
+		out := doc.Bytes()
+
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+		assert.Contains(t, string(out), "/Count 1")
+		assert.Contains(t, string(out), "(Cash Flow Statement) Tj")
+	})
+
+	t.Run("given more rows than fit on a page, when Bytes(), then pages are added", func(t *testing.T) {
+		doc := New()
+		columns := []Column{{Width: 0.7}, {Width: 0.3, Align: AlignRight}}
+		for i := 0; i < 100; i++ {
+			doc.Row(columns, []string{"Makan", "1.250.000"}, false)
+		}
+
+		assert.Contains(t, string(doc.Bytes()), "/Count 2")
+	})
+
+	t.Run("given special characters, when escape(text), then they are encoded", func(t *testing.T) {
+		assert.Equal(t, `Kopi \(Kenangan\) \\ \351`, escape("Kopi (Kenangan) \\ é"))
+		assert.Equal(t, "?", escape("☕"))
+	})
+
+	t.Run("given long text, when truncate(text), then it fits the width", func(t *testing.T) {
+		text := truncate(strings.Repeat("Belanja bulanan ", 10), 100, 10)
+
+		assert.True(t, strings.HasSuffix(text, "..."))
+		assert.LessOrEqual(t, textWidth(text, 10), 100.0)
+	})
+}
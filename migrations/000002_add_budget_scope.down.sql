@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transaction_tags_transaction_id;
+DROP INDEX IF EXISTS "vasst_expense".idx_budgets_workspace_id;
+
+ALTER TABLE "vasst_expense".budgets
+    DROP COLUMN IF EXISTS scope;
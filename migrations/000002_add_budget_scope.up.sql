@@ -0,0 +1,16 @@
+-- Budget scope: a budget can now cover several categories, tags, accounts and merchants,
+-- or the total spend of the workspace. Stored as JSONB:
+-- {"category_ids": [...], "tag_ids": [...], "account_ids": [...], "merchants": [...], "total_spend": false}
+ALTER TABLE "vasst_expense".budgets
+    ADD COLUMN scope JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Existing budgets are scoped to their single user category
+UPDATE "vasst_expense".budgets
+SET scope = jsonb_build_object('category_ids', jsonb_build_array(user_category_id))
+WHERE user_category_id IS NOT NULL;
+
+-- Index for budget lookups per workspace
+CREATE INDEX IF NOT EXISTS idx_budgets_workspace_id ON "vasst_expense".budgets(workspace_id);
+
+-- Index used by the spent amount calculation
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_transaction_id ON "vasst_expense".transaction_tags(transaction_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_envelope_transfers_workspace_month;
+DROP INDEX IF EXISTS "vasst_expense".idx_envelope_allocations_workspace_month;
+
+DROP TABLE IF EXISTS "vasst_expense".envelope_transfers;
+DROP TABLE IF EXISTS "vasst_expense".envelope_allocations;
+
+ALTER TABLE "vasst_expense".workspaces
+    DROP COLUMN IF EXISTS budgeting_mode;
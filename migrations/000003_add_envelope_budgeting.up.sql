@@ -0,0 +1,33 @@
+-- Envelope ("amplop") budgeting mode per workspace
+ALTER TABLE "vasst_expense".workspaces
+    ADD COLUMN budgeting_mode INT NOT NULL DEFAULT 1; -- '1 - standard', '2 - envelope'
+
+-- Envelope Allocations: money assigned from the "available to assign" pool to an envelope for a month
+CREATE TABLE "vasst_expense".envelope_allocations (
+    allocation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    user_category_id UUID NOT NULL REFERENCES "vasst_expense".user_categories(user_category_id) ON DELETE CASCADE,
+    allocation_month DATE NOT NULL, -- first day of the month
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES "vasst_expense".users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(workspace_id, user_category_id, allocation_month)
+);
+
+-- Envelope Transfers: history of money moved between envelopes
+CREATE TABLE "vasst_expense".envelope_transfers (
+    transfer_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    from_user_category_id UUID NOT NULL REFERENCES "vasst_expense".user_categories(user_category_id) ON DELETE CASCADE,
+    to_user_category_id UUID NOT NULL REFERENCES "vasst_expense".user_categories(user_category_id) ON DELETE CASCADE,
+    transfer_month DATE NOT NULL, -- first day of the month
+    amount DECIMAL(15,2) NOT NULL,
+    transfer_type INT NOT NULL DEFAULT 1, -- '1 - move', '2 - overspend cover'
+    note TEXT,
+    created_by UUID REFERENCES "vasst_expense".users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_envelope_allocations_workspace_month ON "vasst_expense".envelope_allocations(workspace_id, allocation_month);
+CREATE INDEX idx_envelope_transfers_workspace_month ON "vasst_expense".envelope_transfers(workspace_id, transfer_month);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_categories_parent_category_id;
+DROP INDEX IF EXISTS "vasst_expense".idx_transaction_tags_user_tag_id;
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_workspace_merchant;
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_workspace_category_date;
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_workspace_date;
+
+DROP TABLE IF EXISTS "vasst_expense".exchange_rates;
@@ -0,0 +1,18 @@
+-- Exchange Rates: conversion rates between currencies, effective from a date
+CREATE TABLE "vasst_expense".exchange_rates (
+    exchange_rate_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_currency_id INT NOT NULL REFERENCES "vasst_expense".currency(currency_id) ON DELETE CASCADE,
+    to_currency_id INT NOT NULL REFERENCES "vasst_expense".currency(currency_id) ON DELETE CASCADE,
+    rate DECIMAL(20,8) NOT NULL, -- 1 unit of from_currency = rate units of to_currency
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(from_currency_id, to_currency_id, effective_date)
+);
+
+-- Indexes backing the analytics aggregates
+CREATE INDEX idx_transactions_workspace_date ON "vasst_expense".transactions(workspace_id, transaction_type, transaction_date);
+CREATE INDEX idx_transactions_workspace_category_date ON "vasst_expense".transactions(workspace_id, category_id, transaction_date);
+CREATE INDEX idx_transactions_workspace_merchant ON "vasst_expense".transactions(workspace_id, LOWER(TRIM(merchant_name)));
+CREATE INDEX idx_transaction_tags_user_tag_id ON "vasst_expense".transaction_tags(user_tag_id);
+CREATE INDEX idx_categories_parent_category_id ON "vasst_expense".categories(parent_category_id);
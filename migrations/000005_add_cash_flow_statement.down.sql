@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_transfer_account_date;
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_account_date;
+
+ALTER TABLE "vasst_expense".transactions
+    DROP COLUMN IF EXISTS transfer_account_id;
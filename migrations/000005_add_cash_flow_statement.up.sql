@@ -0,0 +1,8 @@
+-- Transfers between own accounts: the counterpart account of a transaction.
+-- An expense moves money from account_id to transfer_account_id, an income the other way around.
+ALTER TABLE "vasst_expense".transactions
+    ADD COLUMN transfer_account_id UUID REFERENCES "vasst_expense".accounts(account_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_account_date ON "vasst_expense".transactions(account_id, transaction_date);
+CREATE INDEX idx_transactions_transfer_account_date ON "vasst_expense".transactions(transfer_account_id, transaction_date)
+    WHERE transfer_account_id IS NOT NULL;
@@ -0,0 +1,5 @@
+COMMENT ON COLUMN "vasst_expense".accounts.account_type IS NULL;
+
+DROP INDEX IF EXISTS "vasst_expense".idx_account_balance_snapshots_user_date;
+
+DROP TABLE IF EXISTS "vasst_expense".account_balance_snapshots;
@@ -0,0 +1,20 @@
+-- Daily account balance snapshots for net worth tracking.
+-- balance is in the account currency, user_balance in the currency of the account owner.
+CREATE TABLE "vasst_expense".account_balance_snapshots (
+    account_balance_snapshot_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES "vasst_expense".accounts(account_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    account_type INT NOT NULL,
+    balance DECIMAL(15,2) NOT NULL,
+    currency_id INT NOT NULL REFERENCES "vasst_expense".currency(currency_id),
+    user_balance DECIMAL(15,2) NOT NULL,
+    user_currency_id INT NOT NULL REFERENCES "vasst_expense".currency(currency_id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, snapshot_date)
+);
+
+CREATE INDEX idx_account_balance_snapshots_user_date ON "vasst_expense".account_balance_snapshots(user_id, snapshot_date);
+
+COMMENT ON COLUMN "vasst_expense".accounts.account_type IS '1 - debit, 2 - credit, 3 - savings, 4 - cash, 5 - digital wallet, 6 - loan';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_merchant_id;
+
+ALTER TABLE "vasst_expense".transactions DROP COLUMN IF EXISTS merchant_id;
+
+DROP INDEX IF EXISTS "vasst_expense".idx_merchant_aliases_merchant_id;
+DROP INDEX IF EXISTS "vasst_expense".idx_merchant_aliases_user_pattern;
+DROP INDEX IF EXISTS "vasst_expense".idx_merchant_aliases_global_pattern;
+
+DROP TABLE IF EXISTS "vasst_expense".merchant_aliases;
+DROP TABLE IF EXISTS "vasst_expense".merchants;
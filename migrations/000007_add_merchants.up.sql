@@ -0,0 +1,32 @@
+-- Merchant directory: canonical merchants with matching aliases.
+-- Aliases without a user_id are global (managed by admins), the others are personal.
+CREATE TABLE "vasst_expense".merchants (
+    merchant_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    default_category_id UUID REFERENCES "vasst_expense".categories(category_id) ON DELETE SET NULL,
+    logo_url VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "vasst_expense".merchant_aliases (
+    merchant_alias_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    merchant_id UUID NOT NULL REFERENCES "vasst_expense".merchants(merchant_id) ON DELETE CASCADE,
+    user_id UUID REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    pattern VARCHAR(100) NOT NULL, -- normalized text
+    match_type INT NOT NULL DEFAULT 2, -- '1 - exact', '2 - prefix', '3 - contains'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_merchant_aliases_global_pattern ON "vasst_expense".merchant_aliases(pattern, match_type)
+    WHERE user_id IS NULL;
+CREATE UNIQUE INDEX idx_merchant_aliases_user_pattern ON "vasst_expense".merchant_aliases(user_id, pattern, match_type)
+    WHERE user_id IS NOT NULL;
+CREATE INDEX idx_merchant_aliases_merchant_id ON "vasst_expense".merchant_aliases(merchant_id);
+
+ALTER TABLE "vasst_expense".transactions
+    ADD COLUMN merchant_id UUID REFERENCES "vasst_expense".merchants(merchant_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_merchant_id ON "vasst_expense".transactions(merchant_id) WHERE merchant_id IS NOT NULL;
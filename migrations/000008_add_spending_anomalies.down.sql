@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_anomaly_unchecked;
+
+ALTER TABLE "vasst_expense".transactions DROP COLUMN IF EXISTS anomaly_checked_at;
+
+DROP INDEX IF EXISTS "vasst_expense".idx_spending_anomalies_user_status;
+DROP TABLE IF EXISTS "vasst_expense".spending_anomalies;
+
+DROP INDEX IF EXISTS "vasst_expense".idx_notifications_user_created;
+DROP TABLE IF EXISTS "vasst_expense".notifications;
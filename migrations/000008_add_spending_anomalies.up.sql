@@ -0,0 +1,41 @@
+-- In-app notifications
+CREATE TABLE "vasst_expense".notifications (
+    notification_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    notification_type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user_created ON "vasst_expense".notifications(user_id, created_at DESC);
+
+-- Spending anomalies found by the detector. Open anomalies are left out of the baselines,
+-- dismissed ones count as normal spending again.
+CREATE TABLE "vasst_expense".spending_anomalies (
+    spending_anomaly_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES "vasst_expense".transactions(transaction_id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    anomaly_type INT NOT NULL, -- '1 - category outlier', '2 - new merchant large amount', '3 - unusual hour', '4 - category spike'
+    score DECIMAL(10,2) NOT NULL,
+    expected_value DECIMAL(15,2) NOT NULL,
+    actual_value DECIMAL(15,2) NOT NULL,
+    status INT NOT NULL DEFAULT 1, -- '1 - open', '2 - dismissed'
+    dismissed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (transaction_id, anomaly_type)
+);
+
+CREATE INDEX idx_spending_anomalies_user_status ON "vasst_expense".spending_anomalies(user_id, status, created_at DESC);
+
+ALTER TABLE "vasst_expense".transactions ADD COLUMN anomaly_checked_at TIMESTAMPTZ;
+
+-- Existing history only serves as baseline, it is not checked retroactively
+UPDATE "vasst_expense".transactions SET anomaly_checked_at = CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_transactions_anomaly_unchecked ON "vasst_expense".transactions(created_at)
+    WHERE anomaly_checked_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transaction_daily_rollups_key;
+DROP TABLE IF EXISTS "vasst_expense".transaction_daily_rollups;
@@ -0,0 +1,32 @@
+-- Daily totals of transactions per workspace, category, account and type. Amounts are in currency_id,
+-- the account currency or the workspace currency for transactions without an account, and are converted when read.
+-- Transfers between own accounts are not rolled up.
+CREATE TABLE "vasst_expense".transaction_daily_rollups (
+    transaction_daily_rollup_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    transaction_date DATE NOT NULL,
+    category_id UUID,
+    account_id UUID,
+    transaction_type INT NOT NULL,
+    currency_id INT NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    transaction_count INT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_transaction_daily_rollups_key ON "vasst_expense".transaction_daily_rollups (
+    workspace_id, transaction_date, transaction_type,
+    COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(account_id, '00000000-0000-0000-0000-000000000000'::uuid)
+);
+
+INSERT INTO "vasst_expense".transaction_daily_rollups (
+    workspace_id, transaction_date, category_id, account_id, transaction_type, currency_id, amount, transaction_count
+)
+SELECT t.workspace_id, t.transaction_date, t.category_id, t.account_id, t.transaction_type,
+       COALESCE(a.currency_id, w.currency_id), SUM(t.amount), COUNT(*)
+FROM "vasst_expense".transactions t
+INNER JOIN "vasst_expense".workspaces w ON t.workspace_id = w.workspace_id
+LEFT JOIN "vasst_expense".accounts a ON t.account_id = a.account_id
+WHERE t.transfer_account_id IS NULL
+GROUP BY t.workspace_id, t.transaction_date, t.category_id, t.account_id, t.transaction_type, COALESCE(a.currency_id, w.currency_id);
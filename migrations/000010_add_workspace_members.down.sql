@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_workspace_members_user;
+DROP TABLE IF EXISTS "vasst_expense".workspace_members;
@@ -0,0 +1,21 @@
+-- Workspace members: users sharing a workspace with a role.
+-- The creator of a workspace is its owner.
+CREATE TABLE "vasst_expense".workspace_members (
+    workspace_member_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    role INT NOT NULL DEFAULT 3, -- '1 - owner', '2 - admin', '3 - member', '4 - viewer'
+    invited_by UUID REFERENCES "vasst_expense".users(user_id) ON DELETE SET NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user ON "vasst_expense".workspace_members(user_id, is_active);
+
+INSERT INTO "vasst_expense".workspace_members (workspace_id, user_id, role, joined_at)
+SELECT workspace_id, created_by, 1, created_at
+FROM "vasst_expense".workspaces
+WHERE created_by IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_workspace_invitations_pending_invitee;
+DROP INDEX IF EXISTS "vasst_expense".idx_workspace_invitations_pending_email;
+DROP INDEX IF EXISTS "vasst_expense".idx_workspace_invitations_pending_phone;
+DROP INDEX IF EXISTS "vasst_expense".idx_workspace_invitations_workspace;
+DROP TABLE IF EXISTS "vasst_expense".workspace_invitations;
@@ -0,0 +1,21 @@
+-- Workspace invitations by email, WhatsApp phone number or shareable link (neither email nor phone).
+-- The invitation token is a signed JWT carrying the invitation ID, so only its status is stored.
+CREATE TABLE "vasst_expense".workspace_invitations (
+    invitation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    invited_by UUID REFERENCES "vasst_expense".users(user_id) ON DELETE SET NULL,
+    email VARCHAR(100),
+    phone_number VARCHAR(20),
+    invitee_user_id UUID REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE, -- known user, or the user who registered with the token
+    role INT NOT NULL DEFAULT 3, -- '2 - admin', '3 - member', '4 - viewer'
+    status INT NOT NULL DEFAULT 1, -- '1 - pending', '2 - accepted', '3 - declined', '4 - revoked'
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_workspace_invitations_workspace ON "vasst_expense".workspace_invitations(workspace_id, status);
+CREATE INDEX idx_workspace_invitations_pending_phone ON "vasst_expense".workspace_invitations(phone_number) WHERE status = 1;
+CREATE INDEX idx_workspace_invitations_pending_email ON "vasst_expense".workspace_invitations(email) WHERE status = 1;
+CREATE INDEX idx_workspace_invitations_pending_invitee ON "vasst_expense".workspace_invitations(invitee_user_id) WHERE status = 1;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_settlements_workspace;
+DROP TABLE IF EXISTS "vasst_expense".settlements;
+DROP INDEX IF EXISTS "vasst_expense".idx_transaction_splits_user;
+DROP TABLE IF EXISTS "vasst_expense".transaction_splits;
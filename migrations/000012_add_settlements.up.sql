@@ -0,0 +1,37 @@
+-- Transaction splits: the share of an expense each member owes. The member who recorded the expense paid it,
+-- so every other member of a split owes their share to the transaction's created_by.
+-- Amounts are in the currency of the transaction's account.
+CREATE TABLE "vasst_expense".transaction_splits (
+    transaction_split_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES "vasst_expense".transactions(transaction_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(transaction_id, user_id)
+);
+
+CREATE INDEX idx_transaction_splits_user ON "vasst_expense".transaction_splits(user_id);
+
+-- Settlements: payments between members to settle split balances, in the workspace currency.
+-- Rows are never deleted or edited, a settlement only moves from pending to completed or cancelled.
+CREATE TABLE "vasst_expense".settlements (
+    settlement_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id), -- payer
+    to_user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id), -- payee
+    amount DECIMAL(15,2) NOT NULL,
+    notes TEXT,
+    evidence_url TEXT, -- photo of the transfer receipt
+    status INT NOT NULL DEFAULT 1, -- '1 - pending', '2 - completed', '3 - cancelled'
+    created_by UUID NOT NULL REFERENCES "vasst_expense".users(user_id),
+    payer_confirmed_at TIMESTAMPTZ,
+    payee_confirmed_at TIMESTAMPTZ,
+    settled_at TIMESTAMPTZ, -- when both sides confirmed
+    cancelled_by UUID REFERENCES "vasst_expense".users(user_id),
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_settlements_workspace ON "vasst_expense".settlements(workspace_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE "vasst_expense".users
+    DROP COLUMN IF EXISTS active_workspace_id,
+    DROP COLUMN IF EXISTS default_workspace_id;
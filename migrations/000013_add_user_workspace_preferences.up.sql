@@ -0,0 +1,23 @@
+-- Default workspace (selected after login) and active workspace (last switched to) of each user.
+-- Routes fall back to the active workspace when no workspace_id is given.
+ALTER TABLE "vasst_expense".users
+    ADD COLUMN default_workspace_id UUID REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE SET NULL,
+    ADD COLUMN active_workspace_id UUID REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE SET NULL;
+
+-- Owners of workspaces created at registration after 000010 had no membership row
+INSERT INTO "vasst_expense".workspace_members (workspace_id, user_id, role, joined_at, is_active)
+SELECT w.workspace_id, w.created_by, 1, w.created_at, true
+FROM "vasst_expense".workspaces w
+WHERE w.created_by IS NOT NULL
+ON CONFLICT (workspace_id, user_id) DO NOTHING;
+
+-- The oldest owned workspace is the personal workspace created at registration
+UPDATE "vasst_expense".users u
+SET default_workspace_id = w.workspace_id, active_workspace_id = w.workspace_id
+FROM (
+    SELECT DISTINCT ON (created_by) created_by, workspace_id
+    FROM "vasst_expense".workspaces
+    WHERE created_by IS NOT NULL
+    ORDER BY created_by, created_at
+) w
+WHERE u.user_id = w.created_by;
@@ -0,0 +1,3 @@
+UPDATE "vasst_expense".workspaces
+SET settings = '{}'::jsonb
+WHERE settings->>'version' = '1';
@@ -0,0 +1,22 @@
+-- Workspace settings follow a versioned schema (entities.WorkspaceSettings), starting at version 1.
+-- Existing free-form settings are never read, they are completed with the defaults of the workspace type.
+UPDATE "vasst_expense".workspaces
+SET settings = jsonb_build_object(
+        'version', 1,
+        'week_start_day', 1,
+        'fiscal_month_start_day', 1,
+        'default_account_id', NULL,
+        'default_payment_method', 0,
+        'auto_categorize_confidence', CASE WHEN workspace_type = 2 THEN 0.9 ELSE 0.8 END,
+        'approvals', jsonb_build_object(
+            'enabled', false,
+            'amount_threshold', 0,
+            'required_for_user_ids', '[]'::jsonb
+        ),
+        'notifications', jsonb_build_object(
+            'spending_anomalies', workspace_type NOT IN (3, 4),
+            'approval_requests', true
+        )
+    ),
+    updated_at = CURRENT_TIMESTAMP
+WHERE NOT (settings ? 'version');
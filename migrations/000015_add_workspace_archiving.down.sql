@@ -0,0 +1,2 @@
+ALTER TABLE "vasst_expense".workspaces
+    DROP COLUMN IF EXISTS archived_at;
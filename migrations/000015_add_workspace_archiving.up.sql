@@ -0,0 +1,3 @@
+-- Archived workspaces are read-only, ended trips and events are archived instead of deleted
+ALTER TABLE "vasst_expense".workspaces
+    ADD COLUMN archived_at TIMESTAMPTZ;
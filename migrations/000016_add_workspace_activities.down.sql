@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_audit_logs_workspace_created;
+DROP TABLE IF EXISTS "vasst_expense".workspace_activities;
@@ -0,0 +1,20 @@
+-- Activity feed of each workspace, written together with the audit log from the same domain events.
+-- Summaries are rendered once, in Indonesian and English, with the names at the time of the change.
+CREATE TABLE "vasst_expense".workspace_activities (
+    activity_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES "vasst_expense".users(user_id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL, -- 'create', 'update', 'delete', 'join'
+    resource_type VARCHAR(50) NOT NULL, -- 'transaction', 'budget', 'category', 'member'
+    resource_id UUID,
+    channel VARCHAR(20) NOT NULL DEFAULT 'web', -- 'web', 'whatsapp', 'api'
+    ai_generated BOOLEAN NOT NULL DEFAULT false,
+    summary_id TEXT NOT NULL,
+    summary_en TEXT NOT NULL,
+    audit_log_id UUID REFERENCES "vasst_expense".audit_logs(audit_log_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_workspace_activities_workspace_created ON "vasst_expense".workspace_activities(workspace_id, created_at DESC);
+CREATE INDEX idx_workspace_activities_actor ON "vasst_expense".workspace_activities(workspace_id, actor_id);
+CREATE INDEX idx_audit_logs_workspace_created ON "vasst_expense".audit_logs(workspace_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transaction_comments_transaction_created;
+DROP TABLE IF EXISTS "vasst_expense".transaction_comments;
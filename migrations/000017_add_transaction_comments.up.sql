@@ -0,0 +1,18 @@
+-- Discussion thread of a transaction in a shared workspace.
+-- Mentions and attachments are JSONB arrays, comments posted by replying on WhatsApp keep the message they came from.
+CREATE TABLE "vasst_expense".transaction_comments (
+    comment_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES "vasst_expense".transactions(transaction_id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id),
+    content TEXT NOT NULL DEFAULT '',
+    mentions JSONB NOT NULL DEFAULT '[]', -- user IDs of the mentioned members
+    attachments JSONB NOT NULL DEFAULT '[]', -- [{url, file_name, mime_type}]
+    channel VARCHAR(20) NOT NULL DEFAULT 'web', -- 'web', 'whatsapp', 'api'
+    source_message_id UUID REFERENCES "vasst_expense".messages(message_id) ON DELETE SET NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_comments_transaction_created ON "vasst_expense".transaction_comments(transaction_id, created_at);
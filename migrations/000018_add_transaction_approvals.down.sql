@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_transactions_workspace_pending_approval;
+
+ALTER TABLE "vasst_expense".transactions
+    DROP COLUMN IF EXISTS approval_decided_at,
+    DROP COLUMN IF EXISTS approval_decided_by,
+    DROP COLUMN IF EXISTS approval_note,
+    DROP COLUMN IF EXISTS approval_status;
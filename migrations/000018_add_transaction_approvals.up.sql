@@ -0,0 +1,11 @@
+-- Approval workflow of expenses in business workspaces.
+-- '0 - not required', '1 - draft', '2 - submitted', '3 - approved', '4 - rejected', '5 - reimbursed'.
+-- Only transactions without approval, approved and reimbursed ones count toward totals.
+ALTER TABLE "vasst_expense".transactions
+    ADD COLUMN approval_status INT NOT NULL DEFAULT 0,
+    ADD COLUMN approval_note TEXT, -- reason given by the approver
+    ADD COLUMN approval_decided_by UUID REFERENCES "vasst_expense".users(user_id),
+    ADD COLUMN approval_decided_at TIMESTAMPTZ;
+
+CREATE INDEX idx_transactions_workspace_pending_approval ON "vasst_expense".transactions(workspace_id, transaction_date)
+    WHERE approval_status = 2;
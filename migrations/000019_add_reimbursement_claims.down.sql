@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_reimbursement_claim_items_claim;
+DROP TABLE IF EXISTS "vasst_expense".reimbursement_claim_items;
+DROP INDEX IF EXISTS "vasst_expense".idx_reimbursement_claims_claimant;
+DROP INDEX IF EXISTS "vasst_expense".idx_reimbursement_claims_workspace;
+DROP TABLE IF EXISTS "vasst_expense".reimbursement_claims;
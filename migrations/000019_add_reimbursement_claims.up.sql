@@ -0,0 +1,37 @@
+-- Reimbursement claims: staff of a business workspace claim back the expenses they paid out of pocket.
+-- A claim moves from draft to submitted, then approved or rejected by an approver, and approved claims are paid
+-- by a transfer from a company account to the claimant's payout account.
+CREATE TABLE "vasst_expense".reimbursement_claims (
+    claim_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES "vasst_expense".workspaces(workspace_id) ON DELETE CASCADE,
+    claimant_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id),
+    title VARCHAR(255) NOT NULL,
+    notes TEXT,
+    payout_account_id UUID NOT NULL REFERENCES "vasst_expense".accounts(account_id), -- claimant's account receiving the payment
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    status INT NOT NULL DEFAULT 1, -- '1 - draft', '2 - submitted', '3 - approved', '4 - rejected', '5 - paid'
+    submitted_at TIMESTAMPTZ,
+    reviewed_by UUID REFERENCES "vasst_expense".users(user_id),
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT, -- reason of a rejection
+    paid_by UUID REFERENCES "vasst_expense".users(user_id),
+    paid_at TIMESTAMPTZ,
+    payment_transaction_id UUID REFERENCES "vasst_expense".transactions(transaction_id) ON DELETE SET NULL, -- the transfer paying the claim
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reimbursement_claims_workspace ON "vasst_expense".reimbursement_claims(workspace_id, created_at DESC);
+CREATE INDEX idx_reimbursement_claims_claimant ON "vasst_expense".reimbursement_claims(workspace_id, claimant_id, status);
+
+-- The expenses bundled in a claim with their receipt. An expense is claimed at most once.
+CREATE TABLE "vasst_expense".reimbursement_claim_items (
+    claim_item_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    claim_id UUID NOT NULL REFERENCES "vasst_expense".reimbursement_claims(claim_id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES "vasst_expense".transactions(transaction_id) ON DELETE CASCADE,
+    receipt_url TEXT, -- defaults to the receipt of the transaction
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(transaction_id)
+);
+
+CREATE INDEX idx_reimbursement_claim_items_claim ON "vasst_expense".reimbursement_claim_items(claim_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_refresh_tokens_session;
+DROP TABLE IF EXISTS "vasst_expense".refresh_tokens;
+DROP INDEX IF EXISTS "vasst_expense".idx_user_sessions_user_active;
+DROP TABLE IF EXISTS "vasst_expense".user_sessions;
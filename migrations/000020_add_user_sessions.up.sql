@@ -0,0 +1,29 @@
+-- A session is a login on one device. Its refresh tokens form one family: every refresh rotates the token,
+-- and presenting a rotated token again revokes the whole session.
+CREATE TABLE "vasst_expense".user_sessions (
+    session_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    device_name VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    revoked_reason VARCHAR(20), -- 'logout', 'logout_all', 'revoked', 'reuse'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_user_active ON "vasst_expense".user_sessions(user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+
+-- Only the SHA-256 hash of a refresh token is stored
+CREATE TABLE "vasst_expense".refresh_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES "vasst_expense".user_sessions(session_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES "vasst_expense".users(user_id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    rotated_at TIMESTAMPTZ, -- set once the token has been exchanged for the next one
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_session ON "vasst_expense".refresh_tokens(session_id);
@@ -0,0 +1,4 @@
+ALTER TABLE "vasst_expense".users
+    DROP COLUMN IF EXISTS password_changed_at,
+    DROP COLUMN IF EXISTS password_locked_until,
+    DROP COLUMN IF EXISTS failed_password_attempts;
@@ -0,0 +1,5 @@
+-- PINs are 6 digits, so checks of the current PIN are locked for a while after repeated failures
+ALTER TABLE "vasst_expense".users
+    ADD COLUMN failed_password_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN password_locked_until TIMESTAMPTZ,
+    ADD COLUMN password_changed_at TIMESTAMPTZ;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "vasst_expense".idx_audit_logs_admin_created;
+DROP INDEX IF EXISTS "vasst_expense".idx_users_system_role;
+
+ALTER TABLE "vasst_expense".users
+    DROP COLUMN IF EXISTS system_role;
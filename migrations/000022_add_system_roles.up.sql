@@ -0,0 +1,10 @@
+-- System roles grant access to the administration API (v0): 0 none, 1 superadmin, 2 support, 3 read-only.
+-- The first superadmin is granted here by hand, e.g.
+-- UPDATE "vasst_expense".users SET system_role = 1 WHERE phone_number = '+62...';
+ALTER TABLE "vasst_expense".users
+    ADD COLUMN system_role INTEGER NOT NULL DEFAULT 0 CHECK (system_role BETWEEN 0 AND 3);
+
+CREATE INDEX idx_users_system_role ON "vasst_expense".users(system_role) WHERE system_role <> 0;
+
+-- Requests to the administration API are audited in audit_logs with source_type 'admin'
+CREATE INDEX idx_audit_logs_admin_created ON "vasst_expense".audit_logs(created_at DESC) WHERE source_type = 'admin';
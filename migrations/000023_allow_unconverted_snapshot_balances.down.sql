@@ -0,0 +1,6 @@
+UPDATE "vasst_expense".account_balance_snapshots
+SET user_balance = balance
+WHERE user_balance IS NULL;
+
+ALTER TABLE "vasst_expense".account_balance_snapshots
+    ALTER COLUMN user_balance SET NOT NULL;
@@ -0,0 +1,14 @@
+-- user_balance is NULL when no exchange rate between the account and user currency was recorded on the snapshot date.
+-- Such balances used to be kept unconverted, which counted them at a rate of 1.
+ALTER TABLE "vasst_expense".account_balance_snapshots
+    ALTER COLUMN user_balance DROP NOT NULL;
+
+UPDATE "vasst_expense".account_balance_snapshots s
+SET user_balance = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE s.currency_id <> s.user_currency_id
+AND NOT EXISTS (
+    SELECT 1 FROM "vasst_expense".exchange_rates er
+    WHERE er.effective_date <= s.snapshot_date
+    AND ((er.from_currency_id = s.currency_id AND er.to_currency_id = s.user_currency_id)
+      OR (er.from_currency_id = s.user_currency_id AND er.to_currency_id = s.currency_id AND er.rate <> 0))
+);
@@ -0,0 +1,6 @@
+ALTER TABLE "vasst_expense".transactions
+    DROP CONSTRAINT transactions_transfer_account_id_fkey;
+
+ALTER TABLE "vasst_expense".transactions
+    ADD CONSTRAINT transactions_transfer_account_id_fkey
+    FOREIGN KEY (transfer_account_id) REFERENCES "vasst_expense".accounts(account_id) ON DELETE SET NULL;
@@ -0,0 +1,9 @@
+-- A transfer whose destination account is deleted would otherwise turn into a plain expense or income,
+-- and cash flow, rollups and budgets would start counting it. Accounts are deactivated instead of deleted,
+-- an account that is still the counterpart of a transfer cannot be deleted.
+ALTER TABLE "vasst_expense".transactions
+    DROP CONSTRAINT transactions_transfer_account_id_fkey;
+
+ALTER TABLE "vasst_expense".transactions
+    ADD CONSTRAINT transactions_transfer_account_id_fkey
+    FOREIGN KEY (transfer_account_id) REFERENCES "vasst_expense".accounts(account_id) ON DELETE RESTRICT;